@@ -25,12 +25,26 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/auth"
 	"github.com/osbuild/osbuild-composer/internal/cloudapi"
 	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/distrodefs"
 	"github.com/osbuild/osbuild-composer/internal/dnfjson"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
+	"github.com/osbuild/osbuild-composer/internal/store"
 	"github.com/osbuild/osbuild-composer/internal/weldr"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
+// repositoriesWatchInterval is how often the repository definitions are
+// checked for changes.
+const repositoriesWatchInterval = time.Second * 10
+
+// composeSchedulesInterval is how often the compose schedules are checked,
+// the finest cron schedules run every minute.
+const composeSchedulesInterval = time.Minute
+
+// composeQueueInterval is how often the deferred composes are started when
+// the running ones finished.
+const composeQueueInterval = time.Second * 10
+
 type Composer struct {
 	config   *ComposerConfigFile
 	stateDir string
@@ -44,7 +58,7 @@ type Composer struct {
 	weldr   *weldr.API
 	api     *cloudapi.Server
 
-	weldrListener, localWorkerListener, workerListener, apiListener, promListener net.Listener
+	weldrListener, weldrTLSListener, localWorkerListener, workerListener, apiListener, promListener net.Listener
 }
 
 func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Composer, error) {
@@ -66,9 +80,21 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 		if err != nil {
 			return nil, err
 		}
+		workerConfig.DeduplicateArtifacts = config.Worker.DeduplicateArtifacts
+	}
+
+	workerConfig.ImageCatalogDir, err = c.ensureStateDirectory("image-catalog", 0700)
+	if err != nil {
+		return nil, err
 	}
 
 	c.distros = distroregistry.NewDefault()
+	if config.DistroDefinitionsDir != "" {
+		c.distros, err = distrodefs.NewRegistry(c.distros, config.DistroDefinitionsDir)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load distro definitions: %v", err)
+		}
+	}
 	logrus.Infof("Loaded %d distros", len(c.distros.List()))
 
 	// Clean up the cache, removes unknown distros and files
@@ -112,17 +138,45 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 		return nil, fmt.Errorf("Unable to parse request job timeout: %v", err)
 	}
 
+	if config.Worker.BuildDeduplicationWindow != "" {
+		workerConfig.BuildDeduplicationWindow, err = time.ParseDuration(config.Worker.BuildDeduplicationWindow)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse build deduplication window: %v", err)
+		}
+	}
+
+	if config.Worker.CAKey != "" {
+		if config.Worker.CA == "" {
+			return nil, fmt.Errorf("renewing worker certificates requires the worker CA certificate")
+		}
+		validity, err := time.ParseDuration(config.Worker.CertificateValidity)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse certificate validity: %v", err)
+		}
+		workerConfig.CertificateAuthority, err = worker.LoadCertificateAuthority(config.Worker.CA, config.Worker.CAKey, validity)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load worker certificate authority: %v", err)
+		}
+	}
+
 	c.workers = worker.NewServer(c.logger, jobs, workerConfig)
 
 	return &c, nil
 }
 
 func (c *Composer) InitWeldr(repoPaths []string, weldrListener net.Listener,
-	distrosImageTypeDenylist map[string][]string) (err error) {
+	distrosImageTypeDenylist map[string][]string, blueprintsGit *store.BlueprintsGitConfig, maxParallelComposes int) (err error) {
 	c.weldr, err = weldr.New(repoPaths, c.stateDir, c.solver, c.distros, c.logger, c.workers, distrosImageTypeDenylist)
 	if err != nil {
 		return err
 	}
+	if blueprintsGit != nil {
+		err = c.weldr.EnableBlueprintsGit(*blueprintsGit)
+		if err != nil {
+			return fmt.Errorf("cannot set up the blueprints git repository: %v", err)
+		}
+	}
+	c.weldr.SetMaxParallelComposes(maxParallelComposes)
 	c.weldrListener = weldrListener
 
 	// Preload the Metadata for all the supported distros
@@ -130,6 +184,30 @@ func (c *Composer) InitWeldr(repoPaths []string, weldrListener net.Listener,
 	return nil
 }
 
+// InitWeldrTLS serves the weldr API on the listener too, for remote clients
+// authenticated by their certificates. It must be called after InitWeldr.
+func (c *Composer) InitWeldrTLS(cert, key string, l net.Listener) error {
+	if c.weldr == nil {
+		return fmt.Errorf("the weldr API over TLS needs the weldr API socket")
+	}
+	if len(c.config.WeldrAPI.AllowedDomains) == 0 {
+		return fmt.Errorf("the weldr API over TLS needs the allowed domains of its clients")
+	}
+
+	tlsConfig, err := createTLSConfig(&connectionConfig{
+		CACertFile:     c.config.WeldrAPI.CA,
+		ServerKeyFile:  key,
+		ServerCertFile: cert,
+		AllowedDomains: c.config.WeldrAPI.AllowedDomains,
+		ClientAuth:     tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating TLS configuration for the weldr API: %v", err)
+	}
+	c.weldrTLSListener = tls.NewListener(l, tlsConfig)
+	return nil
+}
+
 func (c *Composer) InitMetricsAPI(prometheus net.Listener) {
 	c.promListener = prometheus
 }
@@ -138,6 +216,36 @@ func (c *Composer) InitAPI(cert, key string, enableTLS bool, enableMTLS bool, en
 	config := v2.ServerConfig{
 		JWTEnabled:           c.config.Koji.EnableJWT,
 		TenantProviderFields: c.config.Koji.JWTTenantProviderFields,
+		EnableMockTarget:     c.config.CloudAPI.EnableMockTarget,
+		AdminTenants:         c.config.CloudAPI.AdminTenants,
+	}
+
+	for _, hub := range c.config.Koji.Hubs {
+		config.KojiHubs = append(config.KojiHubs, v2.KojiHub{
+			URL:         hub.URL,
+			Principal:   hub.Principal,
+			AllowedTags: hub.AllowedTags,
+		})
+	}
+
+	if c.config.CloudAPI.BlueprintFragmentsDir != "" {
+		var err error
+		config.BlueprintFragments, err = v2.LoadBlueprintFragments(c.config.CloudAPI.BlueprintFragmentsDir)
+		if err != nil {
+			return fmt.Errorf("cannot load blueprint fragments: %v", err)
+		}
+		logrus.Infof("Loaded %d blueprint fragments", len(config.BlueprintFragments))
+	}
+
+	if c.config.CloudAPI.ManifestSigningKey != "" {
+		keyPEM, err := os.ReadFile(c.config.CloudAPI.ManifestSigningKey)
+		if err != nil {
+			return fmt.Errorf("cannot read manifest signing key: %v", err)
+		}
+		config.ManifestSigningKey, err = v2.ParseManifestSigningKey(keyPEM)
+		if err != nil {
+			return fmt.Errorf("cannot parse manifest signing key: %v", err)
+		}
 	}
 
 	c.api = cloudapi.NewServer(c.workers, c.distros, config)
@@ -338,6 +446,20 @@ func (c *Composer) Start() error {
 				panic(err)
 			}
 		}()
+
+		// pick up rotated repository URLs and certificates without a restart
+		go c.weldr.WatchRepositories(repositoriesWatchInterval)
+		go c.weldr.RunComposeSchedules(composeSchedulesInterval)
+		go c.weldr.RunComposeQueue(composeQueueInterval)
+	}
+
+	if c.weldrTLSListener != nil {
+		go func() {
+			err := c.weldr.Serve(c.weldrTLSListener)
+			if err != nil && err != http.ErrServerClosed {
+				panic(err)
+			}
+		}()
 	}
 
 	sigint := make(chan os.Signal, 1)
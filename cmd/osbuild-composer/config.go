@@ -8,16 +8,22 @@ import (
 	"strconv"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/osbuild/osbuild-composer/internal/store"
 )
 
 type ComposerConfigFile struct {
 	Koji         KojiAPIConfig   `toml:"koji"`
+	CloudAPI     CloudAPIConfig  `toml:"cloud_api"`
 	Worker       WorkerAPIConfig `toml:"worker"`
 	WeldrAPI     WeldrAPIConfig  `toml:"weldr_api"`
 	SyslogServer string          `toml:"syslog_server" env:"SYSLOG_SERVER"`
 	LogLevel     string          `toml:"log_level"`
 	LogFormat    string          `toml:"log_format"`
 	DNFJson      string          `toml:"dnf-json"`
+	// Directory with definitions of additional distros in its "distros"
+	// subdirectory and their repositories in "repositories"
+	DistroDefinitionsDir string `toml:"distro_definitions_dir"`
 }
 
 type KojiAPIConfig struct {
@@ -30,6 +36,31 @@ type KojiAPIConfig struct {
 	JWTKeysCA               string   `toml:"jwt_ca_file"`
 	JWTACLFile              string   `toml:"jwt_acl_file"`
 	JWTTenantProviderFields []string `toml:"jwt_tenant_provider_fields"`
+	// Koji instances builds can be imported to, keyed by an arbitrary
+	// name. Any instance is allowed if none is configured.
+	Hubs map[string]KojiHubConfig `toml:"hubs"`
+}
+
+// CloudAPIConfig configures the features of the cloud API, the listener and
+// the authentication of the API are configured in the koji section.
+type CloudAPIConfig struct {
+	// Private key the manifests returned by the API are signed with, they
+	// aren't signed if not set
+	ManifestSigningKey string `toml:"manifest_signing_key"`
+	// Allow uploads to the mock target, for testing only
+	EnableMockTarget bool     `toml:"enable_mock_target"`
+	AdminTenants     []string `toml:"admin_tenants"`
+	// Directory with the blueprint fragments compose requests can
+	// reference, a JSON file with customizations per fragment
+	BlueprintFragmentsDir string `toml:"blueprint_fragments_dir"`
+}
+
+type KojiHubConfig struct {
+	URL string `toml:"url"`
+	// Kerberos principal the workers authenticate to the hub as, their
+	// default one for the hub if empty
+	Principal   string   `toml:"principal"`
+	AllowedTags []string `toml:"allowed_tags"`
 }
 
 type WorkerAPIConfig struct {
@@ -38,6 +69,7 @@ type WorkerAPIConfig struct {
 	RequestJobTimeout       string   `toml:"request_job_timeout"`
 	BasePath                string   `toml:"base_path"`
 	EnableArtifacts         bool     `toml:"enable_artifacts"`
+	DeduplicateArtifacts    bool     `toml:"deduplicate_artifacts"`
 	PGHost                  string   `toml:"pg_host" env:"PGHOST"`
 	PGPort                  string   `toml:"pg_port" env:"PGPORT"`
 	PGDatabase              string   `toml:"pg_database" env:"PGDATABASE"`
@@ -52,10 +84,33 @@ type WorkerAPIConfig struct {
 	JWTKeysCA               string   `toml:"jwt_ca_file"`
 	JWTACLFile              string   `toml:"jwt_acl_file"`
 	JWTTenantProviderFields []string `toml:"jwt_tenant_provider_fields"`
+	CAKey                   string   `toml:"ca_key"`
+	CertificateValidity     string   `toml:"certificate_validity"`
+	// reuse identical builds of reproducible cloud API requests of the
+	// same tenant finished within this duration, disabled if not set
+	BuildDeduplicationWindow string `toml:"build_deduplication_window"`
 }
 
 type WeldrAPIConfig struct {
 	DistroConfigs map[string]WeldrDistroConfig `toml:"distros"`
+	// Git repository every change to the blueprints is committed to, the
+	// blueprints aren't committed anywhere if not set
+	BlueprintsGit WeldrBlueprintsGitConfig `toml:"blueprints_git"`
+	// Maximum number of composes building at the same time, the others
+	// wait until one of them finishes. There's no limit if it's 0.
+	MaxParallelComposes int `toml:"max_parallel_composes"`
+	// Clients of the API over TLS must have certificates signed by the
+	// CA for one of the allowed domains. The CAs of the host are used if
+	// the CA isn't set.
+	AllowedDomains []string `toml:"allowed_domains"`
+	CA             string   `toml:"ca"`
+}
+
+type WeldrBlueprintsGitConfig struct {
+	Dir string `toml:"dir"`
+	// Remote repository the commits are pushed to, optional
+	Remote string `toml:"remote"`
+	Branch string `toml:"branch"`
 }
 
 type WeldrDistroConfig struct {
@@ -76,6 +131,19 @@ func (c *ComposerConfigFile) weldrDistrosImageTypeDenyList() map[string][]string
 	return distrosImageTypeDenyList
 }
 
+// weldrBlueprintsGit returns the git repository the blueprints are committed
+// to, nil if there's none.
+func (c *ComposerConfigFile) weldrBlueprintsGit() *store.BlueprintsGitConfig {
+	if c.WeldrAPI.BlueprintsGit.Dir == "" {
+		return nil
+	}
+	return &store.BlueprintsGitConfig{
+		Dir:    c.WeldrAPI.BlueprintsGit.Dir,
+		Remote: c.WeldrAPI.BlueprintsGit.Remote,
+		Branch: c.WeldrAPI.BlueprintsGit.Branch,
+	}
+}
+
 // GetDefaultConfig returns the default configuration of osbuild-composer
 // Defaults:
 //   - 'azure-rhui', 'azure-sap-rhui', 'ec2', 'ec2-ha', 'ec2-sap' image types on 'rhel-*'
@@ -88,15 +156,16 @@ func GetDefaultConfig() *ComposerConfigFile {
 			EnableJWT:  false,
 		},
 		Worker: WorkerAPIConfig{
-			RequestJobTimeout: "0",
-			BasePath:          "/api/worker/v1",
-			EnableArtifacts:   true,
-			EnableTLS:         true,
-			EnableMTLS:        true,
-			EnableJWT:         false,
+			RequestJobTimeout:   "0",
+			BasePath:            "/api/worker/v1",
+			EnableArtifacts:     true,
+			EnableTLS:           true,
+			EnableMTLS:          true,
+			EnableJWT:           false,
+			CertificateValidity: "720h",
 		},
 		WeldrAPI: WeldrAPIConfig{
-			map[string]WeldrDistroConfig{
+			DistroConfigs: map[string]WeldrDistroConfig{
 				"rhel-*": {
 					ImageTypeDenyList: []string{
 						"azure-eap7-rhui",
@@ -36,12 +36,13 @@ func TestDefaultConfig(t *testing.T) {
 	}, defaultConfig.Koji)
 
 	require.Equal(t, WorkerAPIConfig{
-		RequestJobTimeout: "0",
-		BasePath:          "/api/worker/v1",
-		EnableArtifacts:   true,
-		EnableTLS:         true,
-		EnableMTLS:        true,
-		EnableJWT:         false,
+		RequestJobTimeout:   "0",
+		BasePath:            "/api/worker/v1",
+		EnableArtifacts:     true,
+		EnableTLS:           true,
+		EnableMTLS:          true,
+		EnableJWT:           false,
+		CertificateValidity: "720h",
 	}, defaultConfig.Worker)
 
 	expectedWeldrAPIConfig := WeldrAPIConfig{
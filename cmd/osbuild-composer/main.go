@@ -94,12 +94,29 @@ func main() {
 			logrus.Fatal("The osbuild-composer.socket unit is misconfigured. It should contain only one socket.")
 		}
 
-		err = composer.InitWeldr(repositoryConfigs, l[0], config.weldrDistrosImageTypeDenyList())
+		repoPaths := repositoryConfigs
+		if config.DistroDefinitionsDir != "" {
+			// repositories of the defined distros take precedence
+			repoPaths = append([]string{config.DistroDefinitionsDir}, repositoryConfigs...)
+		}
+		err = composer.InitWeldr(repoPaths, l[0], config.weldrDistrosImageTypeDenyList(), config.weldrBlueprintsGit(), config.WeldrAPI.MaxParallelComposes)
 		if err != nil {
 			logrus.Fatalf("Error initializing weldr API: %v", err)
 		}
 	}
 
+	// the weldr API is only served over TCP if the socket is enabled
+	if l, exists := listeners["osbuild-composer-weldr-tls.socket"]; exists {
+		if len(l) != 1 {
+			logrus.Fatal("The osbuild-composer-weldr-tls.socket unit is misconfigured. It should contain only one socket.")
+		}
+
+		err = composer.InitWeldrTLS(ServerCertFile, ServerKeyFile, l[0])
+		if err != nil {
+			logrus.Fatalf("Error initializing weldr API over TLS: %v", err)
+		}
+	}
+
 	if l, exists := listeners["osbuild-local-worker.socket"]; exists {
 		if len(l) != 1 {
 			logrus.Fatal("The osbuild-local-worker.socket unit is misconfigured. It should contain only one socket.")
@@ -21,6 +21,8 @@ func main() {
 	var shareWith string
 	var arch string
 	var bootMode string
+	var kmsKeyID string
+	var imdsSupport string
 	flag.StringVar(&accessKeyID, "access-key-id", "", "access key ID")
 	flag.StringVar(&secretAccessKey, "secret-access-key", "", "secret access key")
 	flag.StringVar(&sessionToken, "session-token", "", "session token")
@@ -32,6 +34,8 @@ func main() {
 	flag.StringVar(&shareWith, "account-id", "", "account id to share image with")
 	flag.StringVar(&arch, "arch", "", "arch (x86_64 or aarch64)")
 	flag.StringVar(&bootMode, "boot-mode", "", "boot mode (legacy-bios, uefi, uefi-preferred)")
+	flag.StringVar(&kmsKeyID, "kms-key-id", "", "customer managed KMS key encrypting the AMI")
+	flag.StringVar(&imdsSupport, "imds-support", "", "set to v2.0 to require IMDSv2")
 	flag.Parse()
 
 	a, err := awscloud.New(region, accessKeyID, secretAccessKey, sessionToken)
@@ -53,12 +57,23 @@ func main() {
 		share = append(share, shareWith)
 	}
 
-	var bootModePtr *string
+	var options awscloud.RegisterOptions
 	if bootMode != "" {
-		bootModePtr = &bootMode
+		options.BootMode = &bootMode
+	}
+	if imdsSupport != "" {
+		options.IMDSSupport = &imdsSupport
+	}
+
+	if kmsKeyID != "" {
+		options.KMSKeyARN, err = a.KMSKeyARN(kmsKeyID)
+		if err != nil {
+			println(err.Error())
+			return
+		}
 	}
 
-	ami, err := a.Register(imageName, bucketName, keyName, share, arch, bootModePtr)
+	ami, err := a.Register(imageName, bucketName, keyName, share, arch, options)
 	if err != nil {
 		println(err.Error())
 		return
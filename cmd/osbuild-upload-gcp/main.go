@@ -88,7 +88,7 @@ func main() {
 	// Import Image to Compute Engine
 	if !skipImport {
 		logrus.Infof("[GCP] 📥 Importing image into Compute Engine as '%s'", imageName)
-		_, importErr := g.ComputeImageInsert(ctx, bucketName, objectName, imageName, regions, guestOSFeatures)
+		_, importErr := g.ComputeImageInsert(ctx, bucketName, objectName, imageName, regions, gcp.ComputeImageOptions{GuestOsFeatures: guestOSFeatures})
 
 		// Cleanup storage before checking for errors
 		logrus.Infof("[GCP] 🧹 Deleting uploaded image file: %s/%s", bucketName, objectName)
@@ -35,7 +35,7 @@ func main() {
 	flag.BoolVar(&public, "public", false, "if set, the S3 object is marked as public (default: false)")
 	flag.Parse()
 
-	a, err := awscloud.NewForEndpoint(endpoint, region, accessKeyID, secretAccessKey, sessionToken, caBundle, skipSSLVerification)
+	a, err := awscloud.NewForEndpoint(endpoint, region, accessKeyID, secretAccessKey, sessionToken, caBundle, skipSSLVerification, true)
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// certificateRenewer provides the client certificate of the worker to TLS
+// connections and renews it before it expires.
+type certificateRenewer struct {
+	certFile string
+	keyFile  string
+	// Renew the certificate when it expires in less than this, a third of
+	// the validity of the certificate if zero
+	renewBefore time.Duration
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// certificateRenewalClient issues the renewed certificates, it's
+// implemented by worker.Client.
+type certificateRenewalClient interface {
+	RenewCertificate(csr []byte) ([]byte, error)
+}
+
+func newCertificateRenewer(certFile, keyFile string, renewBefore time.Duration) (*certificateRenewer, error) {
+	cert, err := loadCertificate(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &certificateRenewer{
+		certFile:    certFile,
+		keyFile:     keyFile,
+		renewBefore: renewBefore,
+		cert:        cert,
+	}, nil
+}
+
+func loadCertificate(certFile, keyFile string) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// GetClientCertificate can be used as tls.Config.GetClientCertificate, so
+// new connections use the renewed certificate.
+func (r *certificateRenewer) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// needsRenewal returns true if the certificate expires soon.
+func (r *certificateRenewer) needsRenewal(now time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	leaf := r.cert.Leaf
+	renewBefore := r.renewBefore
+	if renewBefore == 0 {
+		renewBefore = leaf.NotAfter.Sub(leaf.NotBefore) / 3
+	}
+	return now.After(leaf.NotAfter.Add(-renewBefore))
+}
+
+// renew requests a certificate for a new private key and replaces the
+// current certificate and key, both in memory and on disk.
+func (r *certificateRenewer) renew(client certificateRenewalClient) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	subject := r.cert.Leaf.Subject
+	r.mu.RUnlock()
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject}, key)
+	if err != nil {
+		return fmt.Errorf("cannot create certificate signing request: %v", err)
+	}
+	certPEM, err := client.RenewCertificate(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}))
+	if err != nil {
+		return err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("invalid certificate issued by the server: %v", err)
+	}
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	// both files are replaced atomically, but not together: the worker
+	// cannot start if it's stopped in between
+	err = writeFileAtomically(r.keyFile, keyPEM, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot write the private key: %v", err)
+	}
+	err = writeFileAtomically(r.certFile, certPEM, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot write the certificate: %v", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+
+	logrus.Infof("Renewed the client certificate, valid until %v", cert.Leaf.NotAfter)
+	return nil
+}
+
+// Run checks every interval if the certificate needs to be renewed, until
+// the context is canceled. Failed renewals are retried at the next check.
+func (r *certificateRenewer) Run(ctx context.Context, client certificateRenewalClient, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if r.needsRenewal(time.Now()) {
+			if err := r.renew(client); err != nil {
+				logrus.Errorf("Error renewing the client certificate: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func writeFileAtomically(name string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
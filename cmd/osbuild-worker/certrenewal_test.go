@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// fakeRenewalClient issues certificates like the worker server does.
+type fakeRenewalClient struct {
+	ca      *worker.CertificateAuthority
+	current *x509.Certificate
+	err     error
+}
+
+func (c *fakeRenewalClient) RenewCertificate(csrPEM []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	block, _ := pem.Decode(csrPEM)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := c.ca.RenewCertificate(c.current, csr)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), nil
+}
+
+// writeTestCertificate writes a key and a certificate valid from notBefore
+// to notAfter, issued by a new CA, and returns the CA.
+func writeTestCertificate(t *testing.T, certFile, keyFile string, notBefore, notAfter time.Time) *worker.CertificateAuthority {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "osbuild CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "worker.example.com"},
+		DNSNames:     []string{"worker.example.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return &worker.CertificateAuthority{
+		Certificate: caCert,
+		Key:         caKey,
+		Validity:    30 * 24 * time.Hour,
+	}
+}
+
+func TestCertificateRenewerNeedsRenewal(t *testing.T) {
+	dir := t.TempDir()
+	certFile := path.Join(dir, "worker-crt.pem")
+	keyFile := path.Join(dir, "worker-key.pem")
+	now := time.Now()
+	writeTestCertificate(t, certFile, keyFile, now.Add(-20*time.Hour), now.Add(11*time.Hour))
+
+	// a third of the validity by default, 10h20m before it expires
+	renewer, err := newCertificateRenewer(certFile, keyFile, 0)
+	require.NoError(t, err)
+	require.False(t, renewer.needsRenewal(now))
+	require.True(t, renewer.needsRenewal(now.Add(time.Hour)))
+
+	renewer, err = newCertificateRenewer(certFile, keyFile, time.Hour)
+	require.NoError(t, err)
+	require.False(t, renewer.needsRenewal(now.Add(9*time.Hour)))
+	require.True(t, renewer.needsRenewal(now.Add(10*time.Hour+time.Minute)))
+}
+
+func TestCertificateRenewerRenew(t *testing.T) {
+	dir := t.TempDir()
+	certFile := path.Join(dir, "worker-crt.pem")
+	keyFile := path.Join(dir, "worker-key.pem")
+	ca := writeTestCertificate(t, certFile, keyFile, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	renewer, err := newCertificateRenewer(certFile, keyFile, 0)
+	require.NoError(t, err)
+	old, err := renewer.GetClientCertificate(nil)
+	require.NoError(t, err)
+
+	// the current certificate is kept if the renewal fails
+	err = renewer.renew(&fakeRenewalClient{err: errors.New("server unavailable")})
+	require.Error(t, err)
+	current, err := renewer.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, old, current)
+
+	err = renewer.renew(&fakeRenewalClient{ca: ca, current: old.Leaf})
+	require.NoError(t, err)
+	current, err = renewer.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, old.Leaf.SerialNumber, current.Leaf.SerialNumber)
+	require.Equal(t, "worker.example.com", current.Leaf.Subject.CommonName)
+	require.False(t, renewer.needsRenewal(time.Now()))
+
+	// the renewed certificate and key are used after a restart
+	loaded, err := loadCertificate(certFile, keyFile)
+	require.NoError(t, err)
+	require.Equal(t, current.Leaf.Raw, loaded.Leaf.Raw)
+	info, err := os.Stat(keyFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
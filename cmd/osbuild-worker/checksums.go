@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+type artifactChecksum struct {
+	Name string
+	// hex digest, without the "sha256:" prefix
+	SHA256 string
+}
+
+// checksummedArtifacts returns the artifacts of the checksums of an osbuild
+// job, named the way targets uploading them with the prefix name them, and
+// sorted by their name.
+func checksummedArtifacts(checksums map[string]string, prefix string) []artifactChecksum {
+	artifacts := make([]artifactChecksum, 0, len(checksums))
+	for artifactPath, checksum := range checksums {
+		artifacts = append(artifacts, artifactChecksum{
+			Name:   prefix + path.Base(artifactPath),
+			SHA256: strings.TrimPrefix(checksum, "sha256:"),
+		})
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].Name < artifacts[j].Name
+	})
+	return artifacts
+}
+
+// sha256sums returns the artifacts in the format of sha256sum.
+func sha256sums(artifacts []artifactChecksum) []byte {
+	var buf bytes.Buffer
+	for _, a := range artifacts {
+		fmt.Fprintf(&buf, "%s  %s\n", a.SHA256, a.Name)
+	}
+	return buf.Bytes()
+}
+
+// checksumFile returns the artifacts in the format of the CHECKSUM files of
+// Fedora releases, before they're clearsigned.
+func checksumFile(artifacts []artifactChecksum) []byte {
+	var buf bytes.Buffer
+	for _, a := range artifacts {
+		fmt.Fprintf(&buf, "SHA256 (%s) = %s\n", a.Name, a.SHA256)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksumManifests(t *testing.T) {
+	artifacts := checksummedArtifacts(map[string]string{
+		"qcow2/disk.qcow2": "sha256:2222",
+		"archive/disk.tar": "sha256:1111",
+	}, "composer-api-1234-")
+	require.Equal(t, []artifactChecksum{
+		{Name: "composer-api-1234-disk.qcow2", SHA256: "2222"},
+		{Name: "composer-api-1234-disk.tar", SHA256: "1111"},
+	}, artifacts)
+
+	require.Equal(t, "2222  composer-api-1234-disk.qcow2\n1111  composer-api-1234-disk.tar\n", string(sha256sums(artifacts)))
+	require.Equal(t, "SHA256 (composer-api-1234-disk.qcow2) = 2222\nSHA256 (composer-api-1234-disk.tar) = 1111\n", string(checksumFile(artifacts)))
+}
@@ -3,9 +3,12 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/upload/azure"
+	"github.com/osbuild/osbuild-composer/internal/upload/resumable"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,29 +22,49 @@ type kerberosConfig struct {
 }
 
 type kojiServerConfig struct {
-	Kerberos           *kerberosConfig `toml:"kerberos,omitempty"`
-	RelaxTimeoutFactor uint            `toml:"relax_timeout_factor"`
+	Kerberos *kerberosConfig `toml:"kerberos,omitempty"`
+	// Further Kerberos identities, composer selects which one a build
+	// authenticates as by its principal
+	Identities         []kerberosConfig `toml:"identities,omitempty"`
+	RelaxTimeoutFactor uint             `toml:"relax_timeout_factor"`
 }
 
 type gcpConfig struct {
 	Credentials string `toml:"credentials"`
 	Bucket      string `toml:"bucket"`
+	VaultPath   string `toml:"vault_path"`
 }
 
 type azureConfig struct {
 	Credentials   string `toml:"credentials"`
 	UploadThreads int    `toml:"upload_threads"`
+	VaultPath     string `toml:"vault_path"`
 }
 
 type awsConfig struct {
 	Credentials string `toml:"credentials"`
 	Bucket      string `toml:"bucket"`
+	VaultPath   string `toml:"vault_path"`
 }
 
 type ociConfig struct {
 	Credentials string `toml:"credentials"`
 }
 
+type hetznerConfig struct {
+	// path to a file containing the API token of the project the images
+	// are imported to
+	Credentials string `toml:"credentials"`
+}
+
+type cephConfig struct {
+	// ceph.conf of the cluster images are imported to with the rbd target
+	Config  string `toml:"config"`
+	Keyring string `toml:"keyring"`
+	// user without the "client." prefix
+	User string `toml:"user"`
+}
+
 type genericS3Config struct {
 	Credentials         string `toml:"credentials"`
 	Endpoint            string `toml:"endpoint"`
@@ -64,6 +87,11 @@ type containersConfig struct {
 	PathPrefix   string `toml:"path_prefix"`
 	CertPath     string `toml:"cert_path"`
 	TLSVerify    bool   `toml:"tls_verify"`
+	VaultPath    string `toml:"vault_path"`
+	// path to the cosign private key signing the images on request
+	CosignKey string `toml:"cosign_key"`
+	// path to a file containing the passphrase of the cosign key
+	CosignPassphrase string `toml:"cosign_passphrase"`
 }
 
 type pulpConfig struct {
@@ -71,6 +99,92 @@ type pulpConfig struct {
 	ServerURL   string `toml:"server_address"`
 }
 
+type vaultConfig struct {
+	Address      string `toml:"address"`
+	Namespace    string `toml:"namespace"`
+	CACert       string `toml:"ca_cert"`
+	TokenPath    string `toml:"token"`
+	RoleID       string `toml:"role_id"`
+	SecretIDPath string `toml:"secret_id"`
+	// how long secrets without a lease are cached, e.g. the ones of the
+	// KV engines
+	SecretTTL string `toml:"secret_ttl"`
+}
+
+type sandboxLimitsConfig struct {
+	Memory string `toml:"memory"`
+	// size of the output of a job, only supported by osbuild jobs
+	Disk string `toml:"disk"`
+	PIDs uint64 `toml:"pids"`
+}
+
+type sandboxConfig struct {
+	// "podman" or "bubblewrap"
+	Type string `toml:"type"`
+	// container image with osbuild and dnf-json, required by podman
+	Image string `toml:"image"`
+	// resource limits per job type
+	Limits map[string]sandboxLimitsConfig `toml:"limits"`
+}
+
+type certificateRenewalConfig struct {
+	// renew the client certificate when it expires in less than this,
+	// a third of its validity by default
+	RenewBefore string `toml:"renew_before"`
+}
+
+type depsolveCacheConfig struct {
+	// how long a result is reused while the repositories don't change
+	// default value: 1h
+	TTL string `toml:"ttl"`
+	// default value: 100
+	MaxEntries int `toml:"max_entries"`
+}
+
+type vulnerabilityScanConfig struct {
+	// default value: https://api.osv.dev
+	OSVURL string `toml:"osv_url"`
+	// OSV ecosystems of the packages of the distributions, by the prefix
+	// of their name, e.g. "rhel-9" = "Red Hat"
+	Ecosystems map[string]string `toml:"ecosystems"`
+}
+
+type signingConfig struct {
+	// path to the armored private key which signs the artifacts
+	Key string `toml:"key"`
+	// path to a file containing the passphrase of the key, if encrypted
+	Passphrase string `toml:"passphrase"`
+}
+
+type libvirtConfig struct {
+	// connection URIs of the hosts images can be imported to, the first one
+	// is the default
+	URIs []string `toml:"uris"`
+	// path to a Go template of the domain XML replacing the default one,
+	// executed with .Name, .MemoryMiB, .VCPUs, .Arch, .DiskPath and
+	// .DiskFormat
+	DomainTemplate string `toml:"domain_template"`
+}
+
+type resumableUploadsConfig struct {
+	// directory keeping the state of the uploads, it has to survive
+	// restarts of the worker
+	StateDirectory string `toml:"state_directory"`
+	// size of the uploaded parts, at least 5 MiB
+	// default value: 64
+	PartSizeMiB int64 `toml:"part_size_mib"`
+	// number of parts of an upload uploaded in parallel
+	// default value: 4
+	Concurrency int `toml:"concurrency"`
+}
+
+type mockTargetConfig struct {
+	// default duration of the simulated uploads
+	Latency string `toml:"latency"`
+	// default probability of a simulated upload failing
+	FailureRate float64 `toml:"failure_rate"`
+}
+
 type workerConfig struct {
 	Composer       *composerConfig             `toml:"composer"`
 	Koji           map[string]kojiServerConfig `toml:"koji"`
@@ -81,16 +195,42 @@ type workerConfig struct {
 	Authentication *authenticationConfig       `toml:"authentication"`
 	Containers     *containersConfig           `toml:"containers"`
 	OCI            *ociConfig                  `toml:"oci"`
+	Hetzner        *hetznerConfig              `toml:"hetzner"`
+	Ceph           *cephConfig                 `toml:"ceph"`
 	Pulp           *pulpConfig                 `toml:"pulp"`
+	Vault          *vaultConfig                `toml:"vault"`
+	Sandbox        *sandboxConfig              `toml:"sandbox"`
+	// renew the client certificate used with mTLS automatically
+	CertificateRenewal *certificateRenewalConfig `toml:"certificate_renewal"`
+	// simulate uploads to the mock target, which is only meant for testing
+	MockTarget *mockTargetConfig `toml:"mock_target"`
+	// import images to libvirt hosts, meant for development
+	Libvirt *libvirtConfig `toml:"libvirt"`
+	// upload images to S3, Azure and GCS in parts which aren't uploaded
+	// again when the upload is resumed after a restart
+	ResumableUploads *resumableUploadsConfig `toml:"resumable_uploads"`
+	// reuse the results of depsolve jobs while the repositories don't change
+	DepsolveCache *depsolveCacheConfig `toml:"depsolve_cache"`
+	// scan the packages of builds for known vulnerabilities
+	VulnerabilityScan *vulnerabilityScanConfig `toml:"vulnerability_scan"`
+	// sign the artifacts of builds with a GPG key
+	Signing *signingConfig `toml:"signing"`
+	// jobs pinned to this ID are only run by this worker
+	// default value: the hostname
+	WorkerID string `toml:"worker_id"`
 	// default value: /api/worker/v1
 	BasePath string `toml:"base_path"`
 	DNFJson  string `toml:"dnf-json"`
+	// Maximum number of targets of a job uploaded in parallel
+	// default value: 4
+	UploadConcurrency int `toml:"upload_concurrency"`
 }
 
 func parseConfig(file string) (*workerConfig, error) {
 	// set defaults
 	config := workerConfig{
-		BasePath: "/api/worker/v1",
+		BasePath:          "/api/worker/v1",
+		UploadConcurrency: 4,
 	}
 
 	_, err := toml.DecodeFile(file, &config)
@@ -113,5 +253,111 @@ func parseConfig(file string) (*workerConfig, error) {
 		}
 	}
 
+	if config.UploadConcurrency < 1 {
+		return nil, fmt.Errorf("invalid upload concurrency: %d", config.UploadConcurrency)
+	}
+
+	if config.Sandbox != nil {
+		switch config.Sandbox.Type {
+		case SandboxTypePodman:
+			if config.Sandbox.Image == "" {
+				return nil, fmt.Errorf("the podman sandbox requires an image")
+			}
+		case SandboxTypeBubblewrap:
+		default:
+			return nil, fmt.Errorf("invalid sandbox type: %q", config.Sandbox.Type)
+		}
+		for jobType, limits := range config.Sandbox.Limits {
+			for _, size := range []string{limits.Memory, limits.Disk} {
+				if size == "" {
+					continue
+				}
+				if _, err := common.DataSizeToUint64(size); err != nil {
+					return nil, fmt.Errorf("invalid sandbox limit of %s jobs: %v", jobType, err)
+				}
+			}
+		}
+	}
+
+	if config.Libvirt != nil && len(config.Libvirt.URIs) == 0 {
+		return nil, fmt.Errorf("no connection URIs of the libvirt target set")
+	}
+
+	// set defaults for resumable uploads only if the config section is present
+	if config.ResumableUploads != nil {
+		if config.ResumableUploads.StateDirectory == "" {
+			return nil, fmt.Errorf("no state directory of resumable uploads set")
+		}
+		// S3 rejects smaller parts
+		if config.ResumableUploads.PartSizeMiB == 0 {
+			config.ResumableUploads.PartSizeMiB = resumable.DefaultPartSize / resumable.MiB
+		} else if config.ResumableUploads.PartSizeMiB < 5 {
+			return nil, fmt.Errorf("invalid part size of resumable uploads: %d MiB", config.ResumableUploads.PartSizeMiB)
+		}
+		if config.ResumableUploads.Concurrency == 0 {
+			config.ResumableUploads.Concurrency = resumable.DefaultConcurrency
+		} else if config.ResumableUploads.Concurrency < 0 {
+			return nil, fmt.Errorf("invalid concurrency of resumable uploads: %d", config.ResumableUploads.Concurrency)
+		}
+	}
+
+	if config.Vault != nil && config.Vault.SecretTTL != "" {
+		if _, err := time.ParseDuration(config.Vault.SecretTTL); err != nil {
+			return nil, fmt.Errorf("invalid secret_ttl of the vault: %v", err)
+		}
+	}
+
+	if config.MockTarget != nil {
+		if config.MockTarget.Latency != "" {
+			if _, err := time.ParseDuration(config.MockTarget.Latency); err != nil {
+				return nil, fmt.Errorf("invalid latency of the mock target: %v", err)
+			}
+		}
+		if config.MockTarget.FailureRate < 0 || config.MockTarget.FailureRate > 1 {
+			return nil, fmt.Errorf("invalid failure rate of the mock target: %v", config.MockTarget.FailureRate)
+		}
+	}
+
+	if config.DepsolveCache != nil {
+		if config.DepsolveCache.TTL == "" {
+			config.DepsolveCache.TTL = "1h"
+		}
+		if _, err := time.ParseDuration(config.DepsolveCache.TTL); err != nil {
+			return nil, fmt.Errorf("invalid ttl of the depsolve cache: %v", err)
+		}
+		if config.DepsolveCache.MaxEntries == 0 {
+			config.DepsolveCache.MaxEntries = 100
+		}
+		if config.DepsolveCache.MaxEntries < 0 {
+			return nil, fmt.Errorf("invalid maximum number of entries of the depsolve cache: %d", config.DepsolveCache.MaxEntries)
+		}
+	}
+
+	if config.VulnerabilityScan != nil {
+		if config.VulnerabilityScan.OSVURL == "" {
+			config.VulnerabilityScan.OSVURL = "https://api.osv.dev"
+		}
+		if len(config.VulnerabilityScan.Ecosystems) == 0 {
+			return nil, fmt.Errorf("the vulnerability scan requires the OSV ecosystems of the distributions")
+		}
+	}
+
+	if config.Signing != nil && config.Signing.Key == "" {
+		return nil, fmt.Errorf("the signing section requires the path to a key")
+	}
+
+	if config.Containers != nil && config.Containers.CosignPassphrase != "" && config.Containers.CosignKey == "" {
+		return nil, fmt.Errorf("cosign_passphrase is set, but cosign_key is missing")
+	}
+
+	if config.Vault == nil {
+		if (config.AWS != nil && config.AWS.VaultPath != "") ||
+			(config.GCP != nil && config.GCP.VaultPath != "") ||
+			(config.Azure != nil && config.Azure.VaultPath != "") ||
+			(config.Containers != nil && config.Containers.VaultPath != "") {
+			return nil, fmt.Errorf("vault_path is set, but the vault section is missing")
+		}
+	}
+
 	return &config, nil
 }
@@ -20,6 +20,8 @@ func Test_parseConfig(t *testing.T) {
 # comment
 base_path = "/api/image-builder-worker/v1"
 dnf-json = "/usr/libexec/dnf-json"
+upload_concurrency = 2
+worker_id = "worker-1.example.com"
 
 [composer]
 proxy = "http://proxy.example.com"
@@ -38,6 +40,10 @@ relax_timeout_factor = 42
 principal = "toucan-automation-stage@EXAMPLE.COM"
 keytab = "/etc/osbuild-worker/client-stage.keytab"
 
+[[koji."kojihub.stage.example.com".identities]]
+principal = "toucan-cloud-stage@EXAMPLE.COM"
+keytab = "/etc/osbuild-worker/client-cloud-stage.keytab"
+
 [gcp]
 credentials = "/etc/osbuild-worker/gcp-creds"
 
@@ -48,10 +54,22 @@ upload_threads = 8
 [aws]
 credentials = "/etc/osbuild-worker/aws-creds"
 bucket = "buckethead"
+vault_path = "aws/creds/osbuild-worker"
 
 [oci]
 credentials = "/etc/osbuild-worker/oci-creds"
 
+[hetzner]
+credentials = "/etc/osbuild-worker/hetzner-token"
+
+[ceph]
+config = "/etc/ceph/ceph.conf"
+keyring = "/etc/ceph/ceph.client.osbuild.keyring"
+user = "osbuild"
+
+[resumable_uploads]
+state_directory = "/var/lib/osbuild-worker/uploads"
+
 [generic_s3]
 credentials = "/etc/osbuild-worker/s3-creds"
 endpoint = "http://s3.example.com"
@@ -69,10 +87,43 @@ offline_token = "/etc/osbuild-worker/offline_token"
 [pulp]
 credentials = "/etc/osbuild-worker/pulp-creds"
 server_address = "https://example.com/pulp"
+
+[vault]
+address = "https://vault.example.com:8200"
+namespace = "image-builder"
+ca_cert = "/etc/osbuild-worker/vault-ca.pem"
+role_id = "osbuild-worker"
+secret_id = "/etc/osbuild-worker/vault-secret-id"
+secret_ttl = "10m"
+
+[sandbox]
+type = "podman"
+image = "quay.io/osbuild/osbuild"
+
+[sandbox.limits.osbuild]
+memory = "8 GiB"
+disk = "100 GiB"
+pids = 4096
+
+[mock_target]
+latency = "30s"
+failure_rate = 0.1
+
+[depsolve_cache]
+ttl = "6h"
+
+[vulnerability_scan.ecosystems]
+"rhel-9" = "Red Hat"
+
+[signing]
+key = "/etc/osbuild-worker/signing.asc"
+passphrase = "/etc/osbuild-worker/signing-passphrase"
 `,
 			want: &workerConfig{
-				BasePath: "/api/image-builder-worker/v1",
-				DNFJson:  "/usr/libexec/dnf-json",
+				BasePath:          "/api/image-builder-worker/v1",
+				DNFJson:           "/usr/libexec/dnf-json",
+				UploadConcurrency: 2,
+				WorkerID:          "worker-1.example.com",
 				Composer: &composerConfig{
 					Proxy: "http://proxy.example.com",
 				},
@@ -89,6 +140,12 @@ server_address = "https://example.com/pulp"
 							Principal: "toucan-automation-stage@EXAMPLE.COM",
 							KeyTab:    "/etc/osbuild-worker/client-stage.keytab",
 						},
+						Identities: []kerberosConfig{
+							{
+								Principal: "toucan-cloud-stage@EXAMPLE.COM",
+								KeyTab:    "/etc/osbuild-worker/client-cloud-stage.keytab",
+							},
+						},
 						RelaxTimeoutFactor: 42,
 					},
 				},
@@ -102,10 +159,24 @@ server_address = "https://example.com/pulp"
 				AWS: &awsConfig{
 					Credentials: "/etc/osbuild-worker/aws-creds",
 					Bucket:      "buckethead",
+					VaultPath:   "aws/creds/osbuild-worker",
 				},
 				OCI: &ociConfig{
 					Credentials: "/etc/osbuild-worker/oci-creds",
 				},
+				Hetzner: &hetznerConfig{
+					Credentials: "/etc/osbuild-worker/hetzner-token",
+				},
+				Ceph: &cephConfig{
+					Config:  "/etc/ceph/ceph.conf",
+					Keyring: "/etc/ceph/ceph.client.osbuild.keyring",
+					User:    "osbuild",
+				},
+				ResumableUploads: &resumableUploadsConfig{
+					StateDirectory: "/var/lib/osbuild-worker/uploads",
+					PartSizeMiB:    64,
+					Concurrency:    4,
+				},
 				GenericS3: &genericS3Config{
 					Credentials:         "/etc/osbuild-worker/s3-creds",
 					Endpoint:            "http://s3.example.com",
@@ -124,13 +195,49 @@ server_address = "https://example.com/pulp"
 					Credentials: "/etc/osbuild-worker/pulp-creds",
 					ServerURL:   "https://example.com/pulp",
 				},
+				Vault: &vaultConfig{
+					Address:      "https://vault.example.com:8200",
+					Namespace:    "image-builder",
+					CACert:       "/etc/osbuild-worker/vault-ca.pem",
+					RoleID:       "osbuild-worker",
+					SecretIDPath: "/etc/osbuild-worker/vault-secret-id",
+					SecretTTL:    "10m",
+				},
+				Sandbox: &sandboxConfig{
+					Type:  "podman",
+					Image: "quay.io/osbuild/osbuild",
+					Limits: map[string]sandboxLimitsConfig{
+						"osbuild": {
+							Memory: "8 GiB",
+							Disk:   "100 GiB",
+							PIDs:   4096,
+						},
+					},
+				},
+				MockTarget: &mockTargetConfig{
+					Latency:     "30s",
+					FailureRate: 0.1,
+				},
+				DepsolveCache: &depsolveCacheConfig{
+					TTL:        "6h",
+					MaxEntries: 100,
+				},
+				VulnerabilityScan: &vulnerabilityScanConfig{
+					OSVURL:     "https://api.osv.dev",
+					Ecosystems: map[string]string{"rhel-9": "Red Hat"},
+				},
+				Signing: &signingConfig{
+					Key:        "/etc/osbuild-worker/signing.asc",
+					Passphrase: "/etc/osbuild-worker/signing-passphrase",
+				},
 			},
 		},
 		{
 			name:   "default",
 			config: ``,
 			want: &workerConfig{
-				BasePath: "/api/worker/v1",
+				BasePath:          "/api/worker/v1",
+				UploadConcurrency: 4,
 			},
 		},
 	}
@@ -163,6 +270,91 @@ server_address = "https://example.com/pulp"
 [azure]
 credentials = "/etc/osbuild-worker/azure-creds"
 upload_threads = -5
+`)
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong upload concurrency", func(t *testing.T) {
+		configFile := prepareConfig(t, `upload_concurrency = 0`)
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong sandbox config", func(t *testing.T) {
+		for _, config := range []string{
+			"[sandbox]\ntype = \"docker\"",
+			"[sandbox]\ntype = \"podman\"",
+			"[sandbox]\ntype = \"bubblewrap\"\n[sandbox.limits.osbuild]\nmemory = \"lots\"",
+		} {
+			configFile := prepareConfig(t, config)
+			_, err := parseConfig(configFile)
+			require.Error(t, err, config)
+		}
+	})
+
+	t.Run("wrong mock target config", func(t *testing.T) {
+		for _, config := range []string{
+			"[mock_target]\nlatency = \"soon\"",
+			"[mock_target]\nfailure_rate = 1.5",
+		} {
+			configFile := prepareConfig(t, config)
+			_, err := parseConfig(configFile)
+			require.Error(t, err, config)
+		}
+	})
+
+	t.Run("wrong resumable uploads config", func(t *testing.T) {
+		for _, config := range []string{
+			"[resumable_uploads]\npart_size_mib = 64",
+			"[resumable_uploads]\nstate_directory = \"/var/lib/osbuild-worker/uploads\"\npart_size_mib = 4",
+			"[resumable_uploads]\nstate_directory = \"/var/lib/osbuild-worker/uploads\"\nconcurrency = -1",
+		} {
+			configFile := prepareConfig(t, config)
+			_, err := parseConfig(configFile)
+			require.Error(t, err, config)
+		}
+	})
+
+	t.Run("libvirt without uris", func(t *testing.T) {
+		configFile := prepareConfig(t, "[libvirt]\nuris = []")
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong depsolve cache config", func(t *testing.T) {
+		for _, config := range []string{
+			"[depsolve_cache]\nttl = \"forever\"",
+			"[depsolve_cache]\nmax_entries = -1",
+		} {
+			configFile := prepareConfig(t, config)
+			_, err := parseConfig(configFile)
+			require.Error(t, err, config)
+		}
+	})
+
+	t.Run("vulnerability scan without ecosystems", func(t *testing.T) {
+		configFile := prepareConfig(t, "[vulnerability_scan]\nosv_url = \"https://osv.example.com\"")
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("signing without key", func(t *testing.T) {
+		configFile := prepareConfig(t, "[signing]\npassphrase = \"/etc/osbuild-worker/signing-passphrase\"")
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("cosign passphrase without key", func(t *testing.T) {
+		configFile := prepareConfig(t, "[containers]\ncosign_passphrase = \"/etc/osbuild-worker/cosign-passphrase\"")
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("vault path without vault config", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[gcp]
+vault_path = "gcp/key/osbuild-worker"
 `)
 		_, err := parseConfig(configFile)
 		require.Error(t, err)
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/v5/docker/reference"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// containerSpecAuths returns the credentials of the specs, by the registry of
+// their source.
+func containerSpecAuths(specs []worker.ContainerSpec) (map[string]worker.ContainerAuth, error) {
+	auths := map[string]worker.ContainerAuth{}
+	for _, spec := range specs {
+		if spec.Auth == nil {
+			continue
+		}
+		ref, err := reference.ParseNormalizedNamed(spec.Source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid container source %s: %v", spec.Source, err)
+		}
+		auths[reference.Domain(ref)] = *spec.Auth
+	}
+	return auths, nil
+}
+
+// writeContainersAuthFile writes a containers-auth.json(5) file to dir, with
+// the credentials of the registries added to the ones of the base auth file
+// of the worker, if any. It returns the path of the file.
+func writeContainersAuthFile(dir, base string, auths map[string]worker.ContainerAuth) (string, error) {
+	authFile := map[string]interface{}{}
+	if base != "" {
+		data, err := os.ReadFile(base)
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("cannot read the containers auth file: %v", err)
+		}
+		if len(data) > 0 {
+			err = json.Unmarshal(data, &authFile)
+			if err != nil {
+				return "", fmt.Errorf("cannot parse the containers auth file %s: %v", base, err)
+			}
+		}
+	}
+
+	registries, _ := authFile["auths"].(map[string]interface{})
+	if registries == nil {
+		registries = map[string]interface{}{}
+	}
+	for registry, auth := range auths {
+		registries[registry] = map[string]string{
+			"auth": base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password)),
+		}
+	}
+	authFile["auths"] = registries
+
+	data, err := json.Marshal(authFile)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "containers-auth.json")
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return "", fmt.Errorf("cannot write the containers auth file: %v", err)
+	}
+	return path, nil
+}
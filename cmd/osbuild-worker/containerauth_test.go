@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestContainerSpecAuths(t *testing.T) {
+	auths, err := containerSpecAuths([]worker.ContainerSpec{
+		{Source: "registry.example.com/private:1", Auth: &worker.ContainerAuth{Username: "user", Password: "secret"}},
+		{Source: "quay.io/public"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]worker.ContainerAuth{
+		"registry.example.com": {Username: "user", Password: "secret"},
+	}, auths)
+
+	_, err = containerSpecAuths([]worker.ContainerSpec{{Source: "Invalid", Auth: &worker.ContainerAuth{}}})
+	require.Error(t, err)
+}
+
+func TestWriteContainersAuthFile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	require.NoError(t, os.WriteFile(base, []byte(`{"auths": {"quay.io": {"auth": "dXNlcjpwYXNz"}}}`), 0600))
+
+	path, err := writeContainersAuthFile(t.TempDir(), base, map[string]worker.ContainerAuth{
+		"registry.example.com": {Username: "user", Password: "secret"},
+	})
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var authFile map[string]map[string]map[string]string
+	require.NoError(t, json.Unmarshal(data, &authFile))
+	require.Equal(t, map[string]map[string]string{
+		"quay.io":              {"auth": "dXNlcjpwYXNz"},
+		"registry.example.com": {"auth": "dXNlcjpzZWNyZXQ="},
+	}, authFile["auths"])
+
+	// the worker doesn't need an auth file of its own
+	path, err = writeContainersAuthFile(t.TempDir(), "", map[string]worker.ContainerAuth{
+		"registry.example.com": {Username: "user", Password: "secret"},
+	})
+	require.NoError(t, err)
+	require.FileExists(t, path)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	"github.com/google/uuid"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/osbuild/images/pkg/rpmmd"
+)
+
+// media type of the SBOM attachments, as used by `cosign attach sbom`
+const spdxJSONMediaType = "text/spdx+json"
+
+// ContainerSigner signs the container images pushed to registries with
+// sigstore signatures, which are stored as cosign compatible attachments,
+// and attaches an SBOM of the image.
+type ContainerSigner struct {
+	KeyPath    string
+	Passphrase []byte
+}
+
+// signatureSystemContext returns a copy of the system context which stores
+// the signatures as sigstore attachments in the registry.
+func signatureSystemContext(sys *types.SystemContext, dir string) (*types.SystemContext, error) {
+	err := os.WriteFile(filepath.Join(dir, "sigstore.yaml"), []byte("default-docker:\n  use-sigstore-attachments: true\n"), 0600)
+	if err != nil {
+		return nil, err
+	}
+	signSys := *sys
+	signSys.RegistriesDirPath = dir
+	return &signSys, nil
+}
+
+// Sign adds a signature to the pushed image, by copying it onto itself.
+// All blobs already exist in the registry, only the signature is uploaded.
+func (cs *ContainerSigner) Sign(ctx context.Context, sys *types.SystemContext, name reference.Named, manifestDigest digest.Digest) error {
+	dir, err := os.MkdirTemp("", "registries.d-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	signSys, err := signatureSystemContext(sys, dir)
+	if err != nil {
+		return err
+	}
+
+	digested, err := reference.WithDigest(reference.TrimNamed(name), manifestDigest)
+	if err != nil {
+		return err
+	}
+	ref, err := docker.NewReference(digested)
+	if err != nil {
+		return err
+	}
+
+	policyContext, err := signature.NewPolicyContext(&signature.Policy{
+		Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = policyContext.Destroy()
+	}()
+
+	passphrase := cs.Passphrase
+	if passphrase == nil {
+		passphrase = []byte{}
+	}
+	_, err = copy.Image(ctx, policyContext, ref, ref, &copy.Options{
+		SignBySigstorePrivateKeyFile:     cs.KeyPath,
+		SignSigstorePrivateKeyPassphrase: passphrase,
+		SourceCtx:                        signSys,
+		DestinationCtx:                   signSys,
+		ImageListSelection:               copy.CopyAllImages,
+		PreserveDigests:                  true,
+	})
+	return err
+}
+
+// AttachSBOM pushes the SBOM as an OCI artifact tagged the way cosign does,
+// "sha256-<digest>.sbom", and returns its reference.
+func (cs *ContainerSigner) AttachSBOM(ctx context.Context, sys *types.SystemContext, name reference.Named, manifestDigest digest.Digest, sbom []byte) (string, error) {
+	tag := fmt.Sprintf("%s-%s.sbom", manifestDigest.Algorithm(), manifestDigest.Encoded())
+	tagged, err := reference.WithTag(reference.TrimNamed(name), tag)
+	if err != nil {
+		return "", err
+	}
+	ref, err := docker.NewReference(tagged)
+	if err != nil {
+		return "", err
+	}
+
+	dest, err := ref.NewImageDestination(ctx, sys)
+	if err != nil {
+		return "", err
+	}
+	defer dest.Close()
+
+	putBlob := func(data []byte, mediaType string, isConfig bool) (imgspecv1.Descriptor, error) {
+		info, err := dest.PutBlob(ctx, bytes.NewReader(data), types.BlobInfo{
+			Digest:    digest.FromBytes(data),
+			Size:      int64(len(data)),
+			MediaType: mediaType,
+		}, none.NoCache, isConfig)
+		if err != nil {
+			return imgspecv1.Descriptor{}, err
+		}
+		return imgspecv1.Descriptor{
+			MediaType: mediaType,
+			Digest:    info.Digest,
+			Size:      info.Size,
+		}, nil
+	}
+
+	config, err := putBlob([]byte("{}"), imgspecv1.MediaTypeImageConfig, true)
+	if err != nil {
+		return "", fmt.Errorf("error uploading the SBOM config: %w", err)
+	}
+	layer, err := putBlob(sbom, spdxJSONMediaType, false)
+	if err != nil {
+		return "", fmt.Errorf("error uploading the SBOM: %w", err)
+	}
+
+	m, err := manifest.OCI1FromComponents(config, []imgspecv1.Descriptor{layer}).Serialize()
+	if err != nil {
+		return "", err
+	}
+	err = dest.PutManifest(ctx, m, nil)
+	if err != nil {
+		return "", fmt.Errorf("error uploading the SBOM manifest: %w", err)
+	}
+	err = dest.Commit(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	return tagged.String(), nil
+}
+
+type spdxDocument struct {
+	SPDXVersion       string           `json:"spdxVersion"`
+	DataLicense       string           `json:"dataLicense"`
+	SPDXID            string           `json:"SPDXID"`
+	Name              string           `json:"name"`
+	DocumentNamespace string           `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo `json:"creationInfo"`
+	Packages          []spdxPackage    `json:"packages"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo"`
+	DownloadLocation string            `json:"downloadLocation"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// rpmPURL returns the package URL of the rpm, see
+// https://github.com/package-url/purl-spec
+func rpmPURL(rpm rpmmd.RPM) string {
+	purl := fmt.Sprintf("pkg:rpm/%s@%s-%s?arch=%s", rpm.Name, rpm.Version, rpm.Release, rpm.Arch)
+	if rpm.Epoch != nil && *rpm.Epoch != "" && *rpm.Epoch != "0" {
+		purl += "&epoch=" + *rpm.Epoch
+	}
+	return purl
+}
+
+// spdxSBOM returns an SPDX document listing the packages of the image.
+func spdxSBOM(name string, rpms []rpmmd.RPM, created time.Time) ([]byte, error) {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://osbuild.org/spdx/%s-%s", strings.ReplaceAll(name, ":", "-"), uuid.New()),
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: osbuild-composer"},
+		},
+		Packages: []spdxPackage{},
+	}
+	for i, rpm := range rpms {
+		version := fmt.Sprintf("%s-%s", rpm.Version, rpm.Release)
+		if rpm.Epoch != nil && *rpm.Epoch != "" && *rpm.Epoch != "0" {
+			version = *rpm.Epoch + ":" + version
+		}
+		doc.Packages = append(doc.Packages, spdxPackage{
+			Name:             rpm.Name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i),
+			VersionInfo:      version,
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs: []spdxExternalRef{
+				{
+					ReferenceCategory: "PACKAGE-MANAGER",
+					ReferenceType:     "purl",
+					ReferenceLocator:  rpmPURL(rpm),
+				},
+			},
+		})
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containers/image/v5/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/images/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+func TestRPMPURL(t *testing.T) {
+	require.Equal(t, "pkg:rpm/bash@5.1.8-6.el9?arch=x86_64", rpmPURL(rpmmd.RPM{
+		Name:    "bash",
+		Version: "5.1.8",
+		Release: "6.el9",
+		Arch:    "x86_64",
+	}))
+	require.Equal(t, "pkg:rpm/openssl@3.0.7-24.el9?arch=x86_64&epoch=1", rpmPURL(rpmmd.RPM{
+		Name:    "openssl",
+		Epoch:   common.ToPtr("1"),
+		Version: "3.0.7",
+		Release: "24.el9",
+		Arch:    "x86_64",
+	}))
+}
+
+func TestSPDXSBOM(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sbom, err := spdxSBOM("registry.example.com/edge:latest", []rpmmd.RPM{
+		{Name: "bash", Version: "5.1.8", Release: "6.el9", Arch: "x86_64"},
+		{Name: "openssl", Epoch: common.ToPtr("1"), Version: "3.0.7", Release: "24.el9", Arch: "x86_64"},
+	}, created)
+	require.NoError(t, err)
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(sbom, &doc))
+	require.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	require.Equal(t, "registry.example.com/edge:latest", doc.Name)
+	require.Equal(t, "2024-01-02T03:04:05Z", doc.CreationInfo.Created)
+	require.Len(t, doc.Packages, 2)
+	require.Equal(t, "5.1.8-6.el9", doc.Packages[0].VersionInfo)
+	require.Equal(t, "1:3.0.7-24.el9", doc.Packages[1].VersionInfo)
+	require.Equal(t, "pkg:rpm/openssl@3.0.7-24.el9?arch=x86_64&epoch=1", doc.Packages[1].ExternalRefs[0].ReferenceLocator)
+	require.NotEqual(t, doc.Packages[0].SPDXID, doc.Packages[1].SPDXID)
+}
+
+func TestSignatureSystemContext(t *testing.T) {
+	sys := &types.SystemContext{AuthFilePath: "/etc/osbuild-worker/containers-auth.json"}
+	dir := t.TempDir()
+	signSys, err := signatureSystemContext(sys, dir)
+	require.NoError(t, err)
+
+	require.Equal(t, dir, signSys.RegistriesDirPath)
+	require.Equal(t, sys.AuthFilePath, signSys.AuthFilePath)
+	require.Empty(t, sys.RegistriesDirPath)
+
+	config, err := os.ReadFile(filepath.Join(dir, "sigstore.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(config), "use-sigstore-attachments: true")
+}
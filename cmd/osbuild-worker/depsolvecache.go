@@ -0,0 +1,168 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osbuild/images/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// The result of depsolving the same package sets doesn't change as long as
+// the metadata of the repositories stays the same. The cache keeps the
+// results of recent depsolve jobs together with the checksums of the
+// repomd.xml files of their repositories, a cached result is only used if
+// none of the repositories changed since.
+//
+// Only repositories with a base URL which don't need a client certificate
+// are supported, package sets using other repositories are always depsolved
+// by dnf-json.
+type depsolveCache struct {
+	mu         sync.Mutex
+	entries    map[string]depsolveCacheEntry
+	ttl        time.Duration
+	maxEntries int
+	client     *http.Client
+}
+
+type depsolveCacheEntry struct {
+	// checksums of repomd.xml by the hash of the repository config
+	revisions    map[string]string
+	packageSpecs map[string][]rpmmd.PackageSpec
+	added        time.Time
+}
+
+func newDepsolveCache(ttl time.Duration, maxEntries int) *depsolveCache {
+	return &depsolveCache{
+		entries:    make(map[string]depsolveCacheEntry),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// depsolveCacheKey identifies the package sets of a depsolve job, the
+// repositories are part of the key, but not their metadata.
+func depsolveCacheKey(packageSets map[string][]rpmmd.PackageSet, modulePlatformID, arch, releasever string, modules map[string]worker.DepsolveModules, noWeakDeps []string) (string, error) {
+	data, err := json.Marshal(struct {
+		PackageSets      map[string][]rpmmd.PackageSet
+		ModulePlatformID string
+		Arch             string
+		Releasever       string
+		Modules          map[string]worker.DepsolveModules
+		NoWeakDeps       []string
+	}{packageSets, modulePlatformID, arch, releasever, modules, noWeakDeps})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// repoRevision returns the checksum of the repomd.xml file of the repository.
+func (c *depsolveCache) repoRevision(repo rpmmd.RepoConfig) (string, error) {
+	if repo.RHSM || len(repo.BaseURLs) == 0 || (repo.IgnoreSSL != nil && *repo.IgnoreSSL) {
+		return "", fmt.Errorf("repository %s is not supported by the cache", repo.Hash())
+	}
+
+	url := strings.TrimSuffix(repo.BaseURLs[0], "/") + "/repodata/repomd.xml"
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching %s: %s", url, resp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, resp.Body); err != nil {
+		return "", fmt.Errorf("error fetching %s: %v", url, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// revisions returns the current revisions of all repositories of the
+// package sets.
+func (c *depsolveCache) revisions(packageSets map[string][]rpmmd.PackageSet) (map[string]string, error) {
+	revisions := make(map[string]string)
+	for _, chain := range packageSets {
+		for _, pkgSet := range chain {
+			for _, repo := range pkgSet.Repositories {
+				id := repo.Hash()
+				if _, ok := revisions[id]; ok {
+					continue
+				}
+				revision, err := c.repoRevision(repo)
+				if err != nil {
+					return nil, err
+				}
+				revisions[id] = revision
+			}
+		}
+	}
+	return revisions, nil
+}
+
+func sameRevisions(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, revision := range a {
+		if b[id] != revision {
+			return false
+		}
+	}
+	return true
+}
+
+// get returns the cached result for the key, if the metadata of its
+// repositories didn't change. Results with outdated metadata are dropped.
+func (c *depsolveCache) get(key string, revisions map[string]string) (map[string][]rpmmd.PackageSpec, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(entry.added) > c.ttl || !sameRevisions(entry.revisions, revisions) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.packageSpecs, true
+}
+
+// add caches the result for the key, evicting expired entries and, if the
+// cache is still full, the oldest one.
+func (c *depsolveCache) add(key string, revisions map[string]string, packageSpecs map[string][]rpmmd.PackageSpec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var oldest string
+	for k, entry := range c.entries {
+		if time.Since(entry.added) > c.ttl {
+			delete(c.entries, k)
+			continue
+		}
+		if oldest == "" || entry.added.Before(c.entries[oldest].added) {
+			oldest = k
+		}
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries && oldest != "" {
+		delete(c.entries, oldest)
+	}
+
+	c.entries[key] = depsolveCacheEntry{
+		revisions:    revisions,
+		packageSpecs: packageSpecs,
+		added:        time.Now(),
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/images/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestDepsolveCache(t *testing.T) {
+	repomd := "revision-1"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repo/repodata/repomd.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(repomd))
+	}))
+	defer srv.Close()
+
+	packageSets := map[string][]rpmmd.PackageSet{
+		"os": {
+			{
+				Include:      []string{"@core"},
+				Repositories: []rpmmd.RepoConfig{{Name: "repo", BaseURLs: []string{srv.URL + "/repo/"}}},
+			},
+		},
+	}
+	specs := map[string][]rpmmd.PackageSpec{
+		"os": {{Name: "bash", Version: "5.2"}},
+	}
+
+	cache := newDepsolveCache(time.Hour, 1)
+	key, err := depsolveCacheKey(packageSets, "platform:f39", "x86_64", "39", nil, nil)
+	require.NoError(t, err)
+	otherKey, err := depsolveCacheKey(packageSets, "platform:f39", "aarch64", "39", nil, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, key, otherKey)
+	modulesKey, err := depsolveCacheKey(packageSets, "platform:f39", "x86_64", "39", map[string]worker.DepsolveModules{
+		"os": {Enable: []string{"nodejs:18"}},
+	}, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, key, modulesKey)
+	minimalKey, err := depsolveCacheKey(packageSets, "platform:f39", "x86_64", "39", nil, []string{"os"})
+	require.NoError(t, err)
+	require.NotEqual(t, key, minimalKey)
+
+	revisions, err := cache.revisions(packageSets)
+	require.NoError(t, err)
+	_, ok := cache.get(key, revisions)
+	require.False(t, ok)
+
+	cache.add(key, revisions, specs)
+	cached, ok := cache.get(key, revisions)
+	require.True(t, ok)
+	require.Equal(t, specs, cached)
+
+	// the oldest entry is evicted when the cache is full
+	cache.add(otherKey, revisions, specs)
+	_, ok = cache.get(key, revisions)
+	require.False(t, ok)
+	cache.add(key, revisions, specs)
+
+	// changed metadata invalidates the cached result
+	repomd = "revision-2"
+	newRevisions, err := cache.revisions(packageSets)
+	require.NoError(t, err)
+	require.NotEqual(t, revisions, newRevisions)
+	_, ok = cache.get(key, newRevisions)
+	require.False(t, ok)
+	_, ok = cache.get(key, revisions)
+	require.False(t, ok)
+
+	// expired results aren't used
+	cache = newDepsolveCache(0, 1)
+	cache.add(key, newRevisions, specs)
+	_, ok = cache.get(key, newRevisions)
+	require.False(t, ok)
+}
+
+func TestDepsolveCacheUnsupportedRepos(t *testing.T) {
+	cache := newDepsolveCache(time.Hour, 1)
+	for _, repo := range []rpmmd.RepoConfig{
+		{Metalink: "https://mirrors.example.com/metalink?repo=fedora-39"},
+		{BaseURLs: []string{"https://cdn.example.com/repo"}, RHSM: true},
+	} {
+		_, err := cache.revisions(map[string][]rpmmd.PackageSet{
+			"os": {{Include: []string{"@core"}, Repositories: []rpmmd.RepoConfig{repo}}},
+		})
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// The AWS Marketplace Catalog API is only available in us-east-1, which is
+// also the region the AMIs of the products are ingested from.
+const awsMarketplaceRegion = "us-east-1"
+
+type AWSMarketplacePublishJobImpl struct {
+	AWSCreds string
+	// how often the status of the change set is checked, the AMI is
+	// scanned before it's added to the product, which takes a while
+	PollInterval time.Duration
+}
+
+// waitForChangeSet polls the change set until it's finished, the last state
+// of the change set is returned.
+func waitForChangeSet(a *awscloud.AWS, changeSet *awscloud.ChangeSet, interval time.Duration) (*awscloud.ChangeSet, error) {
+	for !changeSet.Finished() {
+		time.Sleep(interval)
+		cs, err := a.DescribeChangeSet(changeSet.ID)
+		if err != nil {
+			return changeSet, err
+		}
+		changeSet = cs
+	}
+	return changeSet, nil
+}
+
+func (impl *AWSMarketplacePublishJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id())
+	result := worker.AWSMarketplacePublishJobResult{}
+
+	defer func() {
+		err := job.Update(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	var args worker.AWSMarketplacePublishJob
+	err := job.Args(&args)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingJobArgs, fmt.Sprintf("Error parsing arguments: %v", err), nil)
+		return err
+	}
+
+	if args.Ami == "" {
+		if job.NDynamicArgs() != 1 {
+			logWithId.Error("No AMI given and dynamic args empty")
+			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorNoDynamicArgs, "An aws marketplace publish job should have an AMI or depend on an ec2 copy job", nil)
+			return nil
+		}
+		var cjResult worker.AWSEC2CopyJobResult
+		err = job.DynamicArgs(0, &cjResult)
+		if err != nil {
+			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingDynamicArgs, "Error parsing dynamic args as ec2 copy job", nil)
+			return err
+		}
+		if cjResult.JobError != nil {
+			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, "AWSEC2CopyJob dependency failed", nil)
+			return nil
+		}
+
+		args.Ami = cjResult.Ami
+		args.Region = cjResult.Region
+	}
+	if args.Region != awsMarketplaceRegion {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("AWS Marketplace only ingests AMIs from %s, not from '%s'", awsMarketplaceRegion, args.Region), nil)
+		return nil
+	}
+	result.Ami = args.Ami
+
+	aws, err := getAWS(impl.AWSCreds, awsMarketplaceRegion)
+	if err != nil {
+		logWithId.Errorf("Error creating aws client: %v", err)
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, "Invalid worker config", nil)
+		return err
+	}
+
+	securityGroups := make([]awscloud.AMIProductSecurityGroup, 0, len(args.SecurityGroups))
+	for _, sg := range args.SecurityGroups {
+		securityGroups = append(securityGroups, awscloud.AMIProductSecurityGroup{
+			IpProtocol: sg.IpProtocol,
+			FromPort:   sg.FromPort,
+			ToPort:     sg.ToPort,
+			IpRanges:   sg.IpRanges,
+		})
+	}
+	changeSet, err := aws.AddAMIProductVersion(awscloud.AMIProductVersion{
+		ProductID:               args.ProductID,
+		VersionTitle:            args.VersionTitle,
+		ReleaseNotes:            args.ReleaseNotes,
+		Ami:                     args.Ami,
+		AccessRoleARN:           args.AccessRoleARN,
+		UserName:                args.UserName,
+		OperatingSystemName:     args.OperatingSystemName,
+		OperatingSystemVersion:  args.OperatingSystemVersion,
+		UsageInstructions:       args.UsageInstructions,
+		RecommendedInstanceType: args.RecommendedInstanceType,
+		SecurityGroups:          securityGroups,
+	})
+	if err != nil {
+		logWithId.Errorf("Error starting the change set: %v", err)
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMarketplacePublish, fmt.Sprintf("Error adding a version to product '%s'", args.ProductID), nil)
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case "ResourceNotFoundException":
+				result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMarketplaceChangeSet, fmt.Sprintf("Product '%s' not found", args.ProductID), nil)
+			case "ResourceInUseException":
+				result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMarketplaceChangeSet, fmt.Sprintf("Product '%s' has another change set in progress", args.ProductID), nil)
+			case "ValidationException":
+				result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMarketplaceChangeSet, "Invalid product version", aerr.Message())
+			}
+		}
+		return err
+	}
+	result.ChangeSetID = changeSet.ID
+	result.ChangeSetARN = changeSet.ARN
+	result.ChangeSetStatus = changeSet.Status
+
+	changeSet, err = waitForChangeSet(aws, changeSet, impl.PollInterval)
+	result.ChangeSetStatus = changeSet.Status
+	if err != nil {
+		logWithId.Errorf("Error describing the change set %s: %v", changeSet.ID, err)
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMarketplacePublish, fmt.Sprintf("Error getting the status of change set '%s'", changeSet.ID), nil)
+		return err
+	}
+	if changeSet.Status != awscloud.ChangeSetStatusSucceeded {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMarketplaceChangeSet, fmt.Sprintf("Change set '%s' finished with status %s", changeSet.ID, changeSet.Status), changeSet.Failure)
+		return nil
+	}
+
+	logWithId.Infof("Added version %s of product %s", args.VersionTitle, args.ProductID)
+	return nil
+}
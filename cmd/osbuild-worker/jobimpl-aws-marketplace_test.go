@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
+)
+
+func TestWaitForChangeSet(t *testing.T) {
+	statuses := []string{awscloud.ChangeSetStatusApplying, awscloud.ChangeSetStatusFailed}
+	var request map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/StartChangeSet":
+			require.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			fmt.Fprint(w, `{"ChangeSetId": "cs-1", "ChangeSetArn": "arn:aws:aws-marketplace:us-east-1:123456789012:AWSMarketplace/ChangeSet/cs-1"}`)
+		case "/DescribeChangeSet":
+			require.Equal(t, "AWSMarketplace", r.URL.Query().Get("catalog"))
+			require.Equal(t, "cs-1", r.URL.Query().Get("changeSetId"))
+			status := statuses[0]
+			statuses = statuses[1:]
+			fmt.Fprintf(w, `{"ChangeSetId": "cs-1", "Status": %q, "FailureDescription": "scan failed", "ChangeSet": [{"ErrorDetailList": [{"ErrorCode": "INVALID_AMI", "ErrorMessage": "the AMI has no ENA support"}]}]}`, status)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	a, err := awscloud.NewForEndpoint(srv.URL, awsMarketplaceRegion, "key-id", "secret", "", "", false, true)
+	require.NoError(t, err)
+
+	changeSet, err := a.AddAMIProductVersion(awscloud.AMIProductVersion{
+		ProductID:     "prod-1",
+		VersionTitle:  "1.0",
+		Ami:           "ami-0123",
+		AccessRoleARN: "arn:aws:iam::123456789012:role/marketplace",
+		SecurityGroups: []awscloud.AMIProductSecurityGroup{
+			{IpProtocol: "tcp", FromPort: 22, ToPort: 22, IpRanges: []string{"0.0.0.0/0"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "cs-1", changeSet.ID)
+	require.False(t, changeSet.Finished())
+
+	require.Equal(t, "AWSMarketplace", request["Catalog"])
+	change := request["ChangeSet"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "AddDeliveryOptions", change["ChangeType"])
+	require.Equal(t, map[string]interface{}{"Identifier": "prod-1", "Type": "AmiProduct@1.0"}, change["Entity"])
+	var details struct {
+		Version struct {
+			VersionTitle string
+		}
+		DeliveryOptions []struct {
+			Details struct {
+				AmiDeliveryOptionDetails struct {
+					AmiSource struct {
+						AmiId         string
+						AccessRoleArn string
+					}
+					SecurityGroups []awscloud.AMIProductSecurityGroup
+				}
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(change["Details"].(string)), &details))
+	require.Equal(t, "1.0", details.Version.VersionTitle)
+	require.Len(t, details.DeliveryOptions, 1)
+	require.Equal(t, "ami-0123", details.DeliveryOptions[0].Details.AmiDeliveryOptionDetails.AmiSource.AmiId)
+	require.Equal(t, 22, details.DeliveryOptions[0].Details.AmiDeliveryOptionDetails.SecurityGroups[0].FromPort)
+
+	changeSet, err = waitForChangeSet(a, changeSet, 0)
+	require.NoError(t, err)
+	require.Empty(t, statuses)
+	require.True(t, changeSet.Finished())
+	require.Equal(t, awscloud.ChangeSetStatusFailed, changeSet.Status)
+	require.Equal(t, "scan failed; INVALID_AMI: the AMI has no ENA support", changeSet.Failure)
+}
@@ -47,7 +47,17 @@ func (impl *AWSEC2CopyJobImpl) Run(job worker.Job) error {
 		return err
 	}
 
-	ami, err := aws.CopyImage(args.TargetName, args.Ami, args.SourceRegion)
+	var kmsKeyARN string
+	if args.KMSKeyID != "" {
+		kmsKeyARN, err = aws.KMSKeyARN(args.KMSKeyID)
+		if err != nil {
+			logWithId.Errorf("Error looking up KMS key: %v", err)
+			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorSharingTarget, fmt.Sprintf("Invalid KMS key '%s': %v", args.KMSKeyID, err), nil)
+			return err
+		}
+	}
+
+	ami, err := aws.CopyImage(args.TargetName, args.Ami, args.SourceRegion, kmsKeyARN)
 	if err != nil {
 		logWithId.Errorf("Error copying ami: %v", err)
 		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorSharingTarget, fmt.Sprintf("Error copying ami %s", args.Ami), nil)
@@ -68,6 +78,7 @@ func (impl *AWSEC2CopyJobImpl) Run(job worker.Job) error {
 
 	result.Ami = ami
 	result.Region = args.TargetRegion
+	result.KMSKeyARN = kmsKeyARN
 	return nil
 }
 
@@ -112,6 +123,7 @@ func (impl *AWSEC2ShareJobImpl) Run(job worker.Job) error {
 
 		args.Ami = cjResult.Ami
 		args.Region = cjResult.Region
+		args.KMSKeyARN = cjResult.KMSKeyARN
 	}
 
 	aws, err := getAWS(impl.AWSCreds, args.Region)
@@ -121,7 +133,7 @@ func (impl *AWSEC2ShareJobImpl) Run(job worker.Job) error {
 		return err
 	}
 
-	err = aws.ShareImage(args.Ami, args.ShareWithAccounts)
+	err = aws.ShareImage(args.Ami, args.ShareWithAccounts, args.KMSKeyARN)
 	if err != nil {
 		logWithId.Errorf("Error sharing image: %v", err)
 		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorSharingTarget, fmt.Sprintf("Error sharing image with target %v", args.ShareWithAccounts), nil)
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// ComposeJobImpl finishes a compose of several images once all of its
+// builds finished, recording whether any of them failed.
+type ComposeJobImpl struct{}
+
+func (impl *ComposeJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id().String())
+
+	var result worker.ComposeJobResult
+	defer func() {
+		err := job.Update(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	for i := 0; i < job.NDynamicArgs(); i++ {
+		var buildResult worker.OSBuildJobResult
+		err := job.DynamicArgs(i, &buildResult)
+		if err != nil {
+			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingDynamicArgs, "Error parsing dynamic args", err.Error())
+			return err
+		}
+		if !buildResult.Success {
+			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, "at least one image build failed", nil)
+			break
+		}
+	}
+
+	return nil
+}
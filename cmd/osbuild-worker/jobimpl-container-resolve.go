@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/sirupsen/logrus"
 
@@ -31,11 +32,25 @@ func (impl *ContainerResolveJobImpl) Run(job worker.Job) error {
 	resolver := container.NewResolver(args.Arch)
 	resolver.AuthFilePath = impl.AuthFilePath
 
-	for _, s := range args.Specs {
-		resolver.Add(container.SourceSpec{s.Source, s.Name, s.TLSVerify})
+	// the registries of private containers get the credentials of the job
+	auths, err := containerSpecAuths(args.Specs)
+	if err == nil && len(auths) > 0 {
+		var authDir string
+		authDir, err = os.MkdirTemp("", "container-resolve-")
+		if err == nil {
+			defer os.RemoveAll(authDir)
+			resolver.AuthFilePath, err = writeContainersAuthFile(authDir, impl.AuthFilePath, auths)
+		}
 	}
 
-	specs, err := resolver.Finish()
+	var specs []container.Spec
+	if err == nil {
+		for _, s := range args.Specs {
+			resolver.Add(container.SourceSpec{s.Source, s.Name, s.TLSVerify})
+		}
+
+		specs, err = resolver.Finish()
+	}
 
 	if err != nil {
 		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorContainerResolution, err.Error(), nil)
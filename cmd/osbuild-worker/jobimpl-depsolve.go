@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
 
 	"github.com/osbuild/images/pkg/rpmmd"
 	"github.com/osbuild/osbuild-composer/internal/dnfjson"
@@ -13,17 +14,23 @@ import (
 
 type DepsolveJobImpl struct {
 	Solver *dnfjson.BaseSolver
+	// optional, results of identical jobs are reused if set
+	Cache *depsolveCache
 }
 
 // depsolve each package set in the pacakgeSets map.  The repositories defined
 // in repos are used for all package sets, whereas the repositories in
 // packageSetsRepos are only used for the package set with the same name
-// (matching map keys).
-func (impl *DepsolveJobImpl) depsolve(packageSets map[string][]rpmmd.PackageSet, modulePlatformID, arch, releasever string) (map[string][]rpmmd.PackageSpec, error) {
+// (matching map keys). The modules are enabled and disabled for the package
+// set with the same name, the package sets named in noWeakDeps are depsolved
+// without weak dependencies.
+func (impl *DepsolveJobImpl) depsolve(packageSets map[string][]rpmmd.PackageSet, modulePlatformID, arch, releasever string, modules map[string]worker.DepsolveModules, noWeakDeps []string) (map[string][]rpmmd.PackageSpec, error) {
 	solver := impl.Solver.NewWithConfig(modulePlatformID, releasever, arch, "")
 
 	depsolvedSets := make(map[string][]rpmmd.PackageSpec)
 	for name, pkgSet := range packageSets {
+		solver.SetModules(modules[name].Enable, modules[name].Disable)
+		solver.SetInstallWeakDeps(!slices.Contains(noWeakDeps, name))
 		res, err := solver.Depsolve(pkgSet)
 		if err != nil {
 			return nil, err
@@ -34,6 +41,36 @@ func (impl *DepsolveJobImpl) depsolve(packageSets map[string][]rpmmd.PackageSet,
 	return depsolvedSets, nil
 }
 
+// cachedDepsolve depsolves the package sets, reusing the result of an
+// identical job if the metadata of the repositories didn't change since.
+// It returns true if the result comes from the cache.
+func (impl *DepsolveJobImpl) cachedDepsolve(logWithId *logrus.Entry, packageSets map[string][]rpmmd.PackageSet, modulePlatformID, arch, releasever string, modules map[string]worker.DepsolveModules, noWeakDeps []string) (map[string][]rpmmd.PackageSpec, bool, error) {
+	key, err := depsolveCacheKey(packageSets, modulePlatformID, arch, releasever, modules, noWeakDeps)
+	if err != nil {
+		logWithId.Warnf("Not using the depsolve cache: %v", err)
+		specs, err := impl.depsolve(packageSets, modulePlatformID, arch, releasever, modules, noWeakDeps)
+		return specs, false, err
+	}
+	revisions, err := impl.Cache.revisions(packageSets)
+	if err != nil {
+		logWithId.Infof("Not using the depsolve cache: %v", err)
+		specs, err := impl.depsolve(packageSets, modulePlatformID, arch, releasever, modules, noWeakDeps)
+		return specs, false, err
+	}
+
+	if specs, ok := impl.Cache.get(key, revisions); ok {
+		logWithId.Info("Repository metadata didn't change, reusing the cached depsolve result")
+		return specs, true, nil
+	}
+
+	specs, err := impl.depsolve(packageSets, modulePlatformID, arch, releasever, modules, noWeakDeps)
+	if err != nil {
+		return nil, false, err
+	}
+	impl.Cache.add(key, revisions, specs)
+	return specs, false, nil
+}
+
 func (impl *DepsolveJobImpl) Run(job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 	var args worker.DepsolveJob
@@ -43,7 +80,13 @@ func (impl *DepsolveJobImpl) Run(job worker.Job) error {
 	}
 
 	var result worker.DepsolveJobResult
-	result.PackageSpecs, err = impl.depsolve(args.PackageSets, args.ModulePlatformID, args.Arch, args.Releasever)
+	if impl.Cache != nil {
+		var cacheHit bool
+		result.PackageSpecs, cacheHit, err = impl.cachedDepsolve(logWithId, args.PackageSets, args.ModulePlatformID, args.Arch, args.Releasever, args.Modules, args.NoWeakDeps)
+		result.CacheHit = &cacheHit
+	} else {
+		result.PackageSpecs, err = impl.depsolve(args.PackageSets, args.ModulePlatformID, args.Arch, args.Releasever, args.Modules, args.NoWeakDeps)
+	}
 	if err != nil {
 		switch e := err.(type) {
 		case dnfjson.Error:
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/cloud/gcp"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// GCPImageExportJobImpl exports the images imported to GCP by osbuild jobs
+// to Cloud Storage.
+type GCPImageExportJobImpl struct {
+	// returns the client for the credentials of the target, the same way
+	// the osbuild job does
+	GCP func(credentials []byte) (*gcp.GCP, error)
+	// time limit of the export itself, and how often its status is checked
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// importedImageName returns the name of the image the osbuild job imported
+// to GCP.
+func importedImageName(results []*target.TargetResult) (string, bool) {
+	for _, tr := range results {
+		if tr.Name != target.TargetNameGCP || tr.TargetError != nil {
+			continue
+		}
+		options, ok := tr.Options.(*target.GCPTargetResultOptions)
+		if !ok || options.ImageName == "" {
+			continue
+		}
+		return options.ImageName, true
+	}
+	return "", false
+}
+
+func (impl *GCPImageExportJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id())
+	result := worker.GCPImageExportJobResult{}
+
+	defer func() {
+		err := job.Update(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	var args worker.GCPImageExportJob
+	err := job.Args(&args)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingJobArgs, fmt.Sprintf("Error parsing arguments: %v", err), nil)
+		return err
+	}
+
+	if job.NDynamicArgs() != 1 {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorNoDynamicArgs, "A gcp image export job should depend on an osbuild job", nil)
+		return nil
+	}
+	var osbuildResult worker.OSBuildJobResult
+	err = job.DynamicArgs(0, &osbuildResult)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingDynamicArgs, "Error parsing dynamic args as osbuild job", nil)
+		return err
+	}
+	if osbuildResult.JobError != nil || !osbuildResult.Success {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, "The build failed, there is no image to export", nil)
+		return nil
+	}
+	imageName, ok := importedImageName(osbuildResult.TargetResults)
+	if !ok {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, "The build didn't import an image to GCP", nil)
+		return nil
+	}
+	result.ImageName = imageName
+
+	g, err := impl.GCP(args.Credentials)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, "Invalid worker config", err.Error())
+		return err
+	}
+
+	logWithId.Infof("[GCP] 📦 Exporting image %s to gs://%s/%s", imageName, args.Bucket, args.Object)
+	ctx, cancel := context.WithTimeout(context.Background(), impl.Timeout+10*time.Minute)
+	defer cancel()
+	result.URI, err = g.ComputeImageExport(ctx, imageName, args.Bucket, args.Object, impl.Timeout, impl.PollInterval)
+	if err != nil {
+		logWithId.Errorf("Error exporting image: %v", err)
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorExportingImage, fmt.Sprintf("Error exporting image %s", imageName), err.Error())
+		return nil
+	}
+	logWithId.Infof("[GCP] 🎉 Image exported to %s", result.URI)
+	return nil
+}
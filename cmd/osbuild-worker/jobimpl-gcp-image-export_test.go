@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+func TestImportedImageName(t *testing.T) {
+	failed := target.NewGCPTargetResult(&target.GCPTargetResultOptions{ImageName: "failed"}, nil)
+	failed.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorImportingImage, "import failed", nil)
+	results := []*target.TargetResult{
+		target.NewAWSTargetResult(&target.AWSTargetResultOptions{Ami: "ami-0123", Region: "eu-central-1"}, nil),
+		failed,
+	}
+
+	_, ok := importedImageName(results)
+	require.False(t, ok)
+
+	results = append(results, target.NewGCPTargetResult(&target.GCPTargetResultOptions{
+		ImageName: "composer-api-image",
+		ProjectID: "project",
+	}, nil))
+	name, ok := importedImageName(results)
+	require.True(t, ok)
+	require.Equal(t, "composer-api-image", name)
+}
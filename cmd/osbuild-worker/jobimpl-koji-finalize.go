@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/url"
+	"path"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -20,7 +21,7 @@ type KojiFinalizeJobImpl struct {
 }
 
 func (impl *KojiFinalizeJobImpl) kojiImport(
-	server string,
+	server, principal, tag string,
 	build koji.Build,
 	buildRoots []koji.BuildRoot,
 	outputs []koji.BuildOutput,
@@ -36,8 +37,13 @@ func (impl *KojiFinalizeJobImpl) kojiImport(
 		return fmt.Errorf("Koji server has not been configured: %s", serverURL.Hostname())
 	}
 
+	creds, err := kojiServer.credentials(principal)
+	if err != nil {
+		return err
+	}
+
 	transport := koji.CreateKojiTransport(kojiServer.relaxTimeoutFactor)
-	k, err := koji.NewFromGSSAPI(server, &kojiServer.creds, transport)
+	k, err := koji.NewFromGSSAPI(server, creds, transport)
 	if err != nil {
 		return err
 	}
@@ -48,15 +54,23 @@ func (impl *KojiFinalizeJobImpl) kojiImport(
 		}
 	}()
 
-	_, err = k.CGImport(build, buildRoots, outputs, directory, token)
+	result, err := k.CGImport(build, buildRoots, outputs, directory, token)
 	if err != nil {
 		return fmt.Errorf("Could not import build into koji: %v", err)
 	}
 
+	if tag != "" {
+		taskID, err := k.TagBuild(tag, result.BuildID)
+		if err != nil {
+			return fmt.Errorf("Could not tag build into %s: %v", tag, err)
+		}
+		logrus.Infof("Tagging build %d into %s, task %d", result.BuildID, tag, taskID)
+	}
+
 	return nil
 }
 
-func (impl *KojiFinalizeJobImpl) kojiFail(server string, buildID int, token string) error {
+func (impl *KojiFinalizeJobImpl) kojiFail(server, principal string, buildID int, token string) error {
 
 	serverURL, err := url.Parse(server)
 	if err != nil {
@@ -68,8 +82,13 @@ func (impl *KojiFinalizeJobImpl) kojiFail(server string, buildID int, token stri
 		return fmt.Errorf("Koji server has not been configured: %s", serverURL.Hostname())
 	}
 
+	creds, err := kojiServer.credentials(principal)
+	if err != nil {
+		return err
+	}
+
 	transport := koji.CreateKojiTransport(kojiServer.relaxTimeoutFactor)
-	k, err := koji.NewFromGSSAPI(server, &kojiServer.creds, transport)
+	k, err := koji.NewFromGSSAPI(server, creds, transport)
 	if err != nil {
 		return err
 	}
@@ -102,7 +121,7 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 		// Fail the Koji build if the job error is set and the necessary
 		// information to identify the job are available.
 		if kojiFinalizeJobResult.JobError != nil && initArgs != nil {
-			err = impl.kojiFail(args.Server, int(initArgs.BuildID), initArgs.Token)
+			err = impl.kojiFail(args.Server, args.Principal, int(initArgs.BuildID), initArgs.Token)
 			if err != nil {
 				logWithId.Errorf("Failing Koji job failed: %v", err)
 			}
@@ -135,6 +154,13 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 	}
 
 	for i, buildResult := range osbuildResults {
+		// the images built before the deadline of the compose passed are
+		// imported without the others
+		if deadlineExceeded(buildResult) && !hasKojiImage(buildResult) {
+			logWithId.Infof("Leaving out image %d, the compose deadline passed before it was uploaded", i)
+			continue
+		}
+
 		buildRPMs := make([]rpmmd.RPM, 0)
 		// collect packages from stages in build pipelines
 		for _, plName := range buildResult.PipelineNames.Build {
@@ -189,6 +215,11 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 			BootMode:        buildResult.ImageBootMode,
 			OSBuildArtifact: kojiTargetResult.OsbuildArtifact,
 			OSBuildVersion:  buildResult.OSBuildVersion,
+			PartitionTable:  kojiPartitionTable(kojiTargetOptions.PartitionTable),
+			Packages:        koji.PackagesFromRPMs(imageRPMs),
+		}
+		if artifact := kojiTargetResult.OsbuildArtifact; artifact != nil {
+			imgOutputExtraInfo.Digest = buildResult.ArtifactChecksums[path.Join(artifact.ExportName, artifact.ExportFilename)]
 		}
 
 		// The image filename is now set in the KojiTargetResultOptions.
@@ -281,6 +312,25 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 				Type:         koji.BuildOutputTypeLog,
 			})
 		}
+
+		// SBOM output
+		// Old workers don't upload the SBOM.
+		if kojiTargetOptions.SBOM != nil {
+			outputs = append(outputs, koji.BuildOutput{
+				BuildRootID:  uint64(i),
+				Filename:     kojiTargetOptions.SBOM.Filename,
+				FileSize:     kojiTargetOptions.SBOM.Size,
+				Arch:         buildResult.Arch,
+				ChecksumType: koji.ChecksumType(kojiTargetOptions.SBOM.ChecksumType),
+				Checksum:     kojiTargetOptions.SBOM.Checksum,
+				Type:         koji.BuildOutputTypeSBOM,
+			})
+		}
+	}
+
+	if len(outputs) == 0 {
+		kojiFinalizeJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorDeadlineExceeded, "compose deadline exceeded before any image was uploaded", nil)
+		return nil
 	}
 
 	build := koji.Build{
@@ -295,11 +345,12 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 			TypeInfo: koji.TypeInfoBuild{
 				Image: imgOutputsExtraInfo,
 			},
-			Manifest: manifestOutputsExtraInfo,
+			Manifest:       manifestOutputsExtraInfo,
+			ComposeRequest: kojiComposeRequestInfo(args.ComposeRequest),
 		},
 	}
 
-	err = impl.kojiImport(args.Server, build, buildRoots, outputs, args.KojiDirectory, initArgs.Token)
+	err = impl.kojiImport(args.Server, args.Principal, args.Tag, build, buildRoots, outputs, args.KojiDirectory, initArgs.Token)
 	if err != nil {
 		kojiFinalizeJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorKojiFinalize, err.Error(), nil)
 		return err
@@ -308,6 +359,37 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 	return nil
 }
 
+func kojiPartitionTable(pt *target.KojiPartitionTable) *koji.PartitionTable {
+	if pt == nil {
+		return nil
+	}
+	kojiPT := &koji.PartitionTable{
+		Type:       pt.Type,
+		UUID:       pt.UUID,
+		Partitions: make([]koji.Partition, 0, len(pt.Partitions)),
+	}
+	for _, p := range pt.Partitions {
+		kojiPT.Partitions = append(kojiPT.Partitions, koji.Partition(p))
+	}
+	return kojiPT
+}
+
+func kojiComposeRequestInfo(cr *worker.KojiComposeRequest) *koji.ComposeRequestInfo {
+	if cr == nil {
+		return nil
+	}
+	info := &koji.ComposeRequestInfo{
+		Distribution:     cr.Distribution,
+		BlueprintName:    cr.BlueprintName,
+		BlueprintVersion: cr.BlueprintVersion,
+		Images:           make([]koji.ComposeRequestImage, 0, len(cr.Images)),
+	}
+	for _, image := range cr.Images {
+		info.Images = append(info.Images, koji.ComposeRequestImage(image))
+	}
+	return info
+}
+
 // Extracts dynamic args of the koji-finalize job. Returns an error if they
 // cannot be unmarshalled.
 func extractDynamicArgs(job worker.Job) (*worker.KojiInitJobResult, []worker.OSBuildJobResult, error) {
@@ -336,6 +418,10 @@ func hasFailedDependency(kojiInitResult worker.KojiInitJobResult, osbuildResults
 	}
 
 	for _, r := range osbuildResults {
+		// builds which exceeded the deadline of the compose are left out
+		if deadlineExceeded(r) {
+			continue
+		}
 		// No `OSBuildOutput` implies failure: either osbuild crashed or
 		// rejected the input (manifest or command line arguments)
 		if r.OSBuildOutput == nil || !r.OSBuildOutput.Success || r.JobError != nil {
@@ -344,3 +430,18 @@ func hasFailedDependency(kojiInitResult worker.KojiInitJobResult, osbuildResults
 	}
 	return false
 }
+
+// Returns true if the build failed or skipped some of its targets, because
+// the deadline of the compose passed.
+func deadlineExceeded(osbuildResult worker.OSBuildJobResult) bool {
+	return osbuildResult.JobError != nil && osbuildResult.JobError.ID == clienterrors.ErrorDeadlineExceeded
+}
+
+// Returns true if the build uploaded its image to Koji.
+func hasKojiImage(osbuildResult worker.OSBuildJobResult) bool {
+	if osbuildResult.OSBuildOutput == nil || !osbuildResult.OSBuildOutput.Success {
+		return false
+	}
+	kojiTargetResults := osbuildResult.TargetResultsByName(target.TargetNameKoji)
+	return len(kojiTargetResults) == 1 && kojiTargetResults[0].TargetError == nil
+}
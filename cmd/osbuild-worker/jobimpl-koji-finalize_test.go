@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/osbuild/images/pkg/osbuild"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+func TestHasFailedDependencyDeadline(t *testing.T) {
+	uploaded := worker.OSBuildJobResult{
+		OSBuildOutput: &osbuild.Result{Success: true},
+		TargetResults: []*target.TargetResult{
+			target.NewKojiTargetResult(&target.KojiTargetResultOptions{}, nil),
+		},
+	}
+	// the koji target finished, another one was skipped
+	partial := uploaded
+	partial.JobResult = worker.JobResult{
+		JobError: clienterrors.WorkerClientError(clienterrors.ErrorDeadlineExceeded, "compose deadline exceeded, some targets were skipped", nil),
+	}
+	// the reaper failed the job while it was building
+	timedOut := worker.OSBuildJobResult{
+		JobResult: worker.JobResult{
+			JobError: clienterrors.WorkerClientError(clienterrors.ErrorDeadlineExceeded, "compose deadline exceeded while building the image", nil),
+		},
+	}
+	failed := worker.OSBuildJobResult{
+		JobResult: worker.JobResult{
+			JobError: clienterrors.WorkerClientError(clienterrors.ErrorBuildJob, "osbuild build failed", nil),
+		},
+	}
+
+	require.False(t, hasFailedDependency(worker.KojiInitJobResult{}, []worker.OSBuildJobResult{uploaded, partial, timedOut}))
+	require.True(t, hasFailedDependency(worker.KojiInitJobResult{}, []worker.OSBuildJobResult{uploaded, timedOut, failed}))
+
+	// only the images uploaded before the deadline are imported
+	require.True(t, hasKojiImage(uploaded))
+	require.True(t, deadlineExceeded(partial))
+	require.True(t, hasKojiImage(partial))
+	require.True(t, deadlineExceeded(timedOut))
+	require.False(t, hasKojiImage(timedOut))
+}
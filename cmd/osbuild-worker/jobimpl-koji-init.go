@@ -15,7 +15,7 @@ type KojiInitJobImpl struct {
 	KojiServers map[string]kojiServer
 }
 
-func (impl *KojiInitJobImpl) kojiInit(server, name, version, release string) (string, uint64, error) {
+func (impl *KojiInitJobImpl) kojiInit(server, principal, name, version, release string) (string, uint64, error) {
 
 	serverURL, err := url.Parse(server)
 	if err != nil {
@@ -27,8 +27,13 @@ func (impl *KojiInitJobImpl) kojiInit(server, name, version, release string) (st
 		return "", 0, fmt.Errorf("Koji server has not been configured: %s", serverURL.Hostname())
 	}
 
+	creds, err := kojiServer.credentials(principal)
+	if err != nil {
+		return "", 0, err
+	}
+
 	transport := koji.CreateKojiTransport(kojiServer.relaxTimeoutFactor)
-	k, err := koji.NewFromGSSAPI(server, &kojiServer.creds, transport)
+	k, err := koji.NewFromGSSAPI(server, creds, transport)
 	if err != nil {
 		return "", 0, err
 	}
@@ -55,7 +60,7 @@ func (impl *KojiInitJobImpl) Run(job worker.Job) error {
 	}
 
 	var result worker.KojiInitJobResult
-	result.Token, result.BuildID, err = impl.kojiInit(args.Server, args.Name, args.Version, args.Release)
+	result.Token, result.BuildID, err = impl.kojiInit(args.Server, args.Principal, args.Name, args.Version, args.Release)
 	if err != nil {
 		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorKojiInit, err.Error(), nil)
 	}
@@ -5,18 +5,26 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
 	"github.com/osbuild/images/pkg/container"
 	"github.com/osbuild/images/pkg/osbuild"
+	"github.com/osbuild/images/pkg/rpmmd"
 
 	"github.com/osbuild/osbuild-composer/internal/upload/oci"
 	"github.com/osbuild/osbuild-composer/internal/upload/pulp"
@@ -26,10 +34,16 @@ import (
 
 	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
 	"github.com/osbuild/osbuild-composer/internal/cloud/gcp"
+	"github.com/osbuild/osbuild-composer/internal/cloud/hcloud"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/upload/azure"
+	"github.com/osbuild/osbuild-composer/internal/upload/httpupload"
 	"github.com/osbuild/osbuild-composer/internal/upload/koji"
+	"github.com/osbuild/osbuild-composer/internal/upload/libvirt"
+	"github.com/osbuild/osbuild-composer/internal/upload/rbd"
+	"github.com/osbuild/osbuild-composer/internal/upload/resumable"
+	"github.com/osbuild/osbuild-composer/internal/upload/vagrant"
 	"github.com/osbuild/osbuild-composer/internal/upload/vmware"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
@@ -55,6 +69,8 @@ type ContainersConfiguration struct {
 	PathPrefix   string
 	CertPath     string
 	TLSVerify    *bool
+	// Signs the pushed images on request, nil if not configured
+	Signer *ContainerSigner
 }
 
 type AzureConfiguration struct {
@@ -74,6 +90,71 @@ type PulpConfiguration struct {
 	ServerAddress string
 }
 
+// MockTargetConfiguration are the defaults of the simulated uploads to the
+// mock target.
+type MockTargetConfiguration struct {
+	Latency     time.Duration
+	FailureRate float64
+}
+
+// CephConfiguration is the cluster images are imported to with the rbd
+// target.
+type CephConfiguration struct {
+	ConfigPath string
+	Keyring    string
+	User       string
+}
+
+// LibvirtConfiguration are the hosts images can be imported to with the
+// libvirt target.
+type LibvirtConfiguration struct {
+	// the first one is the default
+	URIs []string
+	// template of the domain XML, the default one if empty
+	DomainTemplate string
+}
+
+// ResumableUploadsConfiguration keeps the state of uploads to S3, Azure and
+// GCS, so that an upload interrupted by a restart of the worker is resumed
+// instead of started over.
+type ResumableUploadsConfiguration struct {
+	Store   *resumable.Store
+	Options resumable.Options
+}
+
+// uploadS3 uploads the file to S3 and returns the location of the object. The
+// upload is only resumable if configured, c may be nil.
+func (c *ResumableUploadsConfiguration) uploadS3(a *awscloud.AWS, imagePath, bucket, key string) (string, error) {
+	if c == nil {
+		result, err := a.Upload(imagePath, bucket, key)
+		if err != nil {
+			return "", err
+		}
+		return result.Location, nil
+	}
+	return a.UploadResumable(context.Background(), imagePath, bucket, key, c.Store, c.Options)
+}
+
+// uploadPageBlob uploads the image to an Azure page blob, with the given
+// number of threads unless the upload is resumable.
+func (c *ResumableUploadsConfiguration) uploadPageBlob(client *azure.StorageClient, metadata azure.BlobMetadata, imagePath string, threads int) error {
+	if c == nil {
+		return client.UploadPageBlob(metadata, imagePath, threads)
+	}
+	return client.UploadPageBlobResumable(context.Background(), metadata, imagePath, c.Store, c.Options)
+}
+
+// uploadGCS uploads the image to a Cloud Storage object.
+func (c *ResumableUploadsConfiguration) uploadGCS(ctx context.Context, g *gcp.GCP, imagePath, bucket, object string, metadata map[string]string) error {
+	var err error
+	if c == nil {
+		_, err = g.StorageObjectUpload(ctx, imagePath, bucket, object, metadata)
+	} else {
+		_, err = g.StorageObjectUploadResumable(ctx, imagePath, bucket, object, metadata, c.Store, c.Options)
+	}
+	return err
+}
+
 type OSBuildJobImpl struct {
 	Store            string
 	Output           string
@@ -83,9 +164,29 @@ type OSBuildJobImpl struct {
 	OCIConfig        OCIConfiguration
 	AWSCreds         string
 	AWSBucket        string
+	HetznerCreds     string
 	S3Config         S3Configuration
 	ContainersConfig ContainersConfiguration
 	PulpConfig       PulpConfiguration
+	// Uploads to the mock target fail if nil
+	MockTargetConfig *MockTargetConfiguration
+	// Maximum number of targets of a job which are handled in parallel
+	UploadConcurrency int
+	// Sandbox running osbuild, nil runs it directly on the host
+	Sandbox *Sandbox
+	// Uploads to the libvirt target fail if nil
+	LibvirtConfig *LibvirtConfiguration
+	// Uploads to the rbd target fail if nil
+	CephConfig *CephConfiguration
+	// Uploads to S3, Azure and GCS aren't resumable if nil
+	ResumableUploads *ResumableUploadsConfiguration
+}
+
+func (impl *OSBuildJobImpl) uploadConcurrency() int {
+	if impl.UploadConcurrency < 1 {
+		return 1
+	}
+	return impl.UploadConcurrency
 }
 
 // Returns an *awscloud.AWS object with the credentials of the request. If they
@@ -102,11 +203,35 @@ func (impl *OSBuildJobImpl) getAWS(region string, accessId string, secret string
 }
 
 func (impl *OSBuildJobImpl) getAWSForS3TargetFromOptions(options *target.AWSS3TargetOptions) (*awscloud.AWS, error) {
+	caBundle := options.CABundle
+	if options.CABundleData != "" {
+		// the bundle is only read when the session is created
+		f, err := os.CreateTemp(impl.Output, "s3-ca-bundle-*.pem")
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(options.CABundleData)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		caBundle = f.Name()
+	}
+
+	pathStyle := !options.VirtualHostedStyle
 	if options.AccessKeyID != "" && options.SecretAccessKey != "" {
-		return awscloud.NewForEndpoint(options.Endpoint, options.Region, options.AccessKeyID, options.SecretAccessKey, options.SessionToken, options.CABundle, options.SkipSSLVerification)
+		return awscloud.NewForEndpoint(options.Endpoint, options.Region, options.AccessKeyID, options.SecretAccessKey, options.SessionToken, caBundle, options.SkipSSLVerification, pathStyle)
 	}
 	if impl.S3Config.Creds != "" {
-		return awscloud.NewForEndpointFromFile(impl.S3Config.Creds, options.Endpoint, options.Region, options.CABundle, options.SkipSSLVerification)
+		// the credentials of the worker are only trusted with a verified
+		// endpoint
+		if options.SkipSSLVerification {
+			return nil, fmt.Errorf("skipping the SSL verification isn't allowed with the credentials of the worker")
+		}
+		return awscloud.NewForEndpointFromFile(impl.S3Config.Creds, options.Endpoint, options.Region, caBundle, false, pathStyle)
 	}
 	return nil, fmt.Errorf("no credentials found")
 }
@@ -116,7 +241,7 @@ func (impl *OSBuildJobImpl) getAWSForS3TargetFromConfig() (*awscloud.AWS, string
 	if err != nil {
 		return nil, "", err
 	}
-	aws, err := awscloud.NewForEndpointFromFile(impl.S3Config.Creds, impl.S3Config.Endpoint, impl.S3Config.Region, impl.S3Config.CABundle, impl.S3Config.SkipSSLVerification)
+	aws, err := awscloud.NewForEndpointFromFile(impl.S3Config.Creds, impl.S3Config.Endpoint, impl.S3Config.Region, impl.S3Config.CABundle, impl.S3Config.SkipSSLVerification, true)
 	return aws, impl.S3Config.Bucket, err
 }
 
@@ -155,8 +280,15 @@ func (impl *OSBuildJobImpl) getAWSForS3Target(options *target.AWSS3TargetOptions
 				err = fmt.Errorf("No AWS bucket provided")
 			}
 		}
-	} else if options.Endpoint != "" && options.Region != "" { // Endpoint != "" && Region != "" => Generic S3 Weldr API
+	} else if options.Endpoint != "" && options.Region != "" { // Endpoint != "" && Region != "" => Generic S3 Weldr and Composer API
 		aws, err = impl.getAWSForS3TargetFromOptions(options)
+		// Composer API requests without a bucket use the configured one
+		if err == nil && bucket == "" {
+			bucket = impl.S3Config.Bucket
+			if bucket == "" {
+				err = fmt.Errorf("No S3 bucket provided")
+			}
+		}
 	} else if options.Endpoint == "" && options.Region == "" { // Endpoint == "" && Region == "" => Generic S3 Composer API
 		aws, bucket, err = impl.getAWSForS3TargetFromConfig()
 	} else {
@@ -243,62 +375,105 @@ func validateResult(result *worker.OSBuildJobResult, jobID string) {
 	result.Success = true
 }
 
-func uploadToS3(a *awscloud.AWS, outputDirectory, exportPath, bucket, key, filename string, public bool) (string, *clienterrors.Error) {
-	imagePath := path.Join(outputDirectory, exportPath, filename)
-
+// s3ObjectKey returns the key of the object a file is uploaded to, the key
+// of the target is its prefix
+func s3ObjectKey(key, filename string) string {
 	if key == "" {
 		key = uuid.New().String()
 	}
-	key += "-" + filename
+	return key + "-" + filename
+}
+
+func uploadToS3(a *awscloud.AWS, uploads *ResumableUploadsConfiguration, outputDirectory, exportPath, bucket, key, filename string, options *target.AWSS3TargetOptions) (string, *clienterrors.Error) {
+	imagePath := path.Join(outputDirectory, exportPath, filename)
 
-	result, err := a.Upload(imagePath, bucket, key)
+	location, err := uploads.uploadS3(a, imagePath, bucket, key)
 	if err != nil {
-		return "", clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+		return "", targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
 
 	}
 
-	if public {
+	if options.Public {
 		err := a.MarkS3ObjectAsPublic(bucket, key)
 		if err != nil {
-			return "", clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+			return "", targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
 		}
 
-		return result.Location, nil
+		return location, nil
+	}
+
+	if options.ACL != "" {
+		err := a.SetS3ObjectACL(bucket, key, options.ACL)
+		if err != nil {
+			return "", targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+		}
 	}
 
-	url, err := a.S3ObjectPresignedURL(bucket, key)
+	url, err := a.S3ObjectPresignedURL(bucket, key, time.Duration(options.URLExpiryHours)*time.Hour)
 	if err != nil {
-		return "", clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+		return "", targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
 	}
 
 	return url, nil
 }
 
-func (impl *OSBuildJobImpl) getContainerClient(destination string, targetOptions *target.ContainerTargetOptions) (*container.Client, error) {
+// uploadChecksumManifest uploads a SHA256SUMS file listing all the artifacts
+// of the job with the key prefix of the S3 target, and returns its URL.
+func (impl *OSBuildJobImpl) uploadChecksumManifest(job worker.Job, a *awscloud.AWS, bucket string, targetOptions *target.AWSS3TargetOptions, checksums map[string]string) (string, *clienterrors.Error) {
+	// the targets are handled concurrently, each needs its own file
+	tempDirectory, err := os.MkdirTemp(impl.Output, job.Id().String()+"-checksums-*")
+	if err != nil {
+		return "", clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+	}
+	defer os.RemoveAll(tempDirectory)
+
+	sums := sha256sums(checksummedArtifacts(checksums, targetOptions.Key+"-"))
+	err = os.WriteFile(path.Join(tempDirectory, "SHA256SUMS"), sums, 0600)
+	if err != nil {
+		return "", clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, "Error writing the checksum manifest", err.Error())
+	}
+	return uploadToS3(a, impl.ResumableUploads, tempDirectory, "", bucket, s3ObjectKey(targetOptions.Key, "SHA256SUMS"), "SHA256SUMS", targetOptions)
+}
+
+// getContainerClient returns the client pushing the image, and the system
+// context with the same settings for signing it afterwards.
+func (impl *OSBuildJobImpl) getContainerClient(destination string, targetOptions *target.ContainerTargetOptions) (*container.Client, *types.SystemContext, error) {
 	destination, appliedDefaults := container.ApplyDefaultDomainPath(destination, impl.ContainersConfig.Domain, impl.ContainersConfig.PathPrefix)
 	client, err := container.NewClient(destination)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if impl.ContainersConfig.AuthFilePath != "" {
 		client.SetAuthFilePath(impl.ContainersConfig.AuthFilePath)
 	}
+	sys := &types.SystemContext{
+		AuthFilePath: client.GetAuthFilePath(),
+	}
 
+	tlsVerify := targetOptions.TlsVerify
 	if appliedDefaults {
 
 		if impl.ContainersConfig.CertPath != "" {
 			client.SetDockerCertPath(impl.ContainersConfig.CertPath)
+			sys.DockerCertPath = impl.ContainersConfig.CertPath
 		}
-		client.SetTLSVerify(impl.ContainersConfig.TLSVerify)
+		tlsVerify = impl.ContainersConfig.TLSVerify
 	} else {
 		if targetOptions.Username != "" || targetOptions.Password != "" {
 			client.SetCredentials(targetOptions.Username, targetOptions.Password)
+			sys.DockerAuthConfig = &types.DockerAuthConfig{
+				Username: targetOptions.Username,
+				Password: targetOptions.Password,
+			}
 		}
-		client.SetTLSVerify(targetOptions.TlsVerify)
+	}
+	client.SetTLSVerify(tlsVerify)
+	if tlsVerify != nil {
+		sys.DockerInsecureSkipTLSVerify = types.NewOptionalBool(!*tlsVerify)
 	}
 
-	return client, nil
+	return client, sys, nil
 }
 
 // Read server configuration and credentials from the target options and fall
@@ -306,18 +481,18 @@ func (impl *OSBuildJobImpl) getContainerClient(destination string, targetOptions
 // Mixing sources is allowed. For example, the server address can be configured
 // in the worker config while the targetOptions provide the credentials (or
 // vice versa).
-func (impl *OSBuildJobImpl) getPulpClient(targetOptions *target.PulpOSTreeTargetOptions) (*pulp.Client, error) {
+func (impl *OSBuildJobImpl) getPulpClient(serverAddress, username, password string) (*pulp.Client, error) {
 
 	var creds *pulp.Credentials
 	// Credentials are considered together. In other words, the username can't
 	// come from a different config source than the password.
-	if targetOptions.Username != "" && targetOptions.Password != "" {
+	if username != "" && password != "" {
 		creds = &pulp.Credentials{
-			Username: targetOptions.Username,
-			Password: targetOptions.Password,
+			Username: username,
+			Password: password,
 		}
 	}
-	address := targetOptions.ServerAddress
+	address := serverAddress
 	if address == "" {
 		// fall back to worker configuration for server address
 		address = impl.PulpConfig.ServerAddress
@@ -467,16 +642,44 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 		return nil
 	}
 
+	if jobArgs.DeadlineExceeded() {
+		osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorDeadlineExceeded, "compose deadline exceeded before the build started", nil)
+		return nil
+	}
+
+	authFilePath := impl.ContainersConfig.AuthFilePath
+	if len(jobArgs.ContainerAuths) > 0 {
+		// the containers are pulled with the credentials of the job
+		var authDir string
+		authDir, err = os.MkdirTemp("", "osbuild-containers-")
+		if err == nil {
+			defer os.RemoveAll(authDir)
+			authFilePath, err = writeContainersAuthFile(authDir, impl.ContainersConfig.AuthFilePath, jobArgs.ContainerAuths)
+		}
+		if err != nil {
+			osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorBuildJob, "Error writing the container registry credentials", err.Error())
+			return err
+		}
+	}
+
 	var extraEnv []string
-	if impl.ContainersConfig.AuthFilePath != "" {
+	if authFilePath != "" {
 		extraEnv = []string{
-			fmt.Sprintf("REGISTRY_AUTH_FILE=%s", impl.ContainersConfig.AuthFilePath),
+			fmt.Sprintf("REGISTRY_AUTH_FILE=%s", authFilePath),
 		}
 	}
 
 	// Run osbuild and handle two kinds of errors
-	osbuildJobResult.OSBuildOutput, err = osbuild.RunOSBuild(jobArgs.Manifest, impl.Store, outputDirectory, exports, nil, extraEnv, true, os.Stderr)
+	if impl.Sandbox != nil {
+		osbuildJobResult.OSBuildOutput, err = impl.Sandbox.RunOSBuild(jobArgs.Manifest, impl.Store, outputDirectory, exports, extraEnv, os.Stderr)
+	} else {
+		osbuildJobResult.OSBuildOutput, err = osbuild.RunOSBuild(jobArgs.Manifest, impl.Store, outputDirectory, exports, nil, extraEnv, true, os.Stderr)
+	}
 	// First handle the case when "running" osbuild failed
+	if errors.Is(err, errSandboxDiskLimitExceeded) {
+		osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorResourceLimitExceeded, "osbuild exceeded the disk limit of the sandbox", nil)
+		return nil
+	}
 	if err != nil {
 		osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorBuildJob, "osbuild build failed", nil)
 		return err
@@ -510,697 +713,1499 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 		return nil
 	}
 
+	// Package the images of vagrant image types into boxes, the targets
+	// upload the boxes instead
+	boxes := map[string]string{}
 	for _, jobTarget := range jobArgs.Targets {
-		var targetResult *target.TargetResult
-		artifact := jobTarget.OsbuildArtifact
-		switch targetOptions := jobTarget.Options.(type) {
-		case *target.WorkerServerTargetOptions:
-			targetResult = target.NewWorkerServerTargetResult(&artifact)
-			var f *os.File
-			imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
-			f, err = os.Open(imagePath)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
-				break
-			}
-			defer f.Close()
-			err = job.UploadArtifact(jobTarget.ImageName, f)
+		provider := jobTarget.OsbuildArtifact.VagrantProvider
+		if provider == "" {
+			continue
+		}
+		imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		if _, ok := boxes[imagePath]; !ok {
+			boxPath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, vagrant.BoxFilename(provider))
+			logWithId.Infof("[vagrant] 📦 Packaging the %s box", provider)
+			err = vagrant.PackageBox(provider, imagePath, boxPath)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
-				break
+				osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorPackagingArtifact, "Error packaging the vagrant box", err.Error())
+				return nil
 			}
+			boxes[imagePath] = boxPath
+		}
+		jobTarget.OsbuildArtifact.ExportFilename = path.Base(boxes[imagePath])
+	}
 
-		case *target.VMWareTargetOptions:
-			targetResult = target.NewVMWareTargetResult(&artifact)
-			credentials := vmware.Credentials{
-				Username:   targetOptions.Username,
-				Password:   targetOptions.Password,
-				Host:       targetOptions.Host,
-				Cluster:    targetOptions.Cluster,
-				Datacenter: targetOptions.Datacenter,
-				Datastore:  targetOptions.Datastore,
-				Folder:     targetOptions.Folder,
+	// Configure WSL in the archives of wsl images, all targets of the job
+	// share the options
+	wslArchives := map[string]string{}
+	for _, jobTarget := range jobArgs.Targets {
+		options := jobTarget.OsbuildArtifact.WSL
+		if options == nil {
+			continue
+		}
+		archivePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		if _, ok := wslArchives[archivePath]; !ok {
+			customizedPath := archivePath + ".wsl-tmp"
+			logWithId.Infof("[wsl] 🐧 Configuring WSL in the archive")
+			err = customizeWSLArchive(archivePath, customizedPath, options)
+			if err == nil {
+				if options.Bundle {
+					wslArchives[archivePath] = strings.TrimSuffix(archivePath, ".tar.gz") + ".wsl"
+				} else {
+					wslArchives[archivePath] = archivePath
+				}
+				err = os.Rename(customizedPath, wslArchives[archivePath])
 			}
-
-			tempDirectory, err := os.MkdirTemp(impl.Output, job.Id().String()+"-vmware-*")
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
-				break
+				osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorPackagingArtifact, "Error configuring WSL in the archive", err.Error())
+				return nil
 			}
+		}
+		jobTarget.OsbuildArtifact.ExportFilename = path.Base(wslArchives[archivePath])
+	}
+
+	// Checksum the exported artifacts, so that consumers can verify them
+	osbuildJobResult.ArtifactChecksums = make(map[string]string)
+	for _, jobTarget := range jobArgs.Targets {
+		artifactPath := path.Join(jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		if _, ok := osbuildJobResult.ArtifactChecksums[artifactPath]; ok {
+			continue
+		}
+		checksum, err := common.FileSHA256(path.Join(outputDirectory, artifactPath))
+		if err != nil {
+			logWithId.Warnf("Computing checksum of artifact %s failed: %v", artifactPath, err)
+			continue
+		}
+		osbuildJobResult.ArtifactChecksums[artifactPath] = checksum
+	}
+
+	handleTargets(jobArgs.Targets, impl.uploadConcurrency(), osbuildJobResult, func(jobTarget *target.Target) (*target.TargetResult, *clienterrors.Error, bool) {
+		// Don't start uploading to any other targets once the compose
+		// deadline passed, report the ones which already finished.
+		if jobArgs.DeadlineExceeded() {
+			logWithId.Warnf("Compose deadline exceeded, skipping target %s", jobTarget.Name)
+			return nil, nil, true
+		}
+		targetResult, jobError := impl.handleTarget(job, &jobArgs, jobTarget, outputDirectory, osbuildJobResult, manifestInfo)
+		return targetResult, jobError, false
+	})
+
+	return nil
+}
 
+// handleTargets calls handle for every target, at most concurrency of them
+// at once, and records the results in the job result in the order of the
+// targets. The handlers only read the job result. A target is reported as
+// skipped if handle returns true, a returned error fails the whole job.
+func handleTargets(targets []*target.Target, concurrency int, osbuildJobResult *worker.OSBuildJobResult, handle func(*target.Target) (*target.TargetResult, *clienterrors.Error, bool)) {
+	targetResults := make([]*target.TargetResult, len(targets))
+	targetJobErrors := make([]*clienterrors.Error, len(targets))
+	targetPanics := make([]interface{}, len(targets))
+	skipped := make([]bool, len(targets))
+	uploadSlots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, jobTarget := range targets {
+		uploadSlots <- struct{}{}
+		wg.Add(1)
+		go func(i int, jobTarget *target.Target) {
+			defer wg.Done()
+			defer func() { <-uploadSlots }()
+			// re-panic in the job's goroutine, so the panic is reported
 			defer func() {
-				err := os.RemoveAll(tempDirectory)
-				if err != nil {
-					logWithId.Errorf("Error removing temporary directory for vmware symlink(%s): %v", tempDirectory, err)
+				if r := recover(); r != nil {
+					targetPanics[i] = r
 				}
 			}()
 
-			exportedImagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+			targetResults[i], targetJobErrors[i], skipped[i] = handle(jobTarget)
+		}(i, jobTarget)
+	}
+	wg.Wait()
 
-			if strings.HasSuffix(exportedImagePath, ".vmdk") {
-				// create a symlink so that uploaded image has the name specified by user
-				imageName := jobTarget.ImageName + ".vmdk"
-				imagePath := path.Join(tempDirectory, imageName)
+	var skippedTargets []target.TargetName
+	for i, jobTarget := range targets {
+		if targetPanics[i] != nil {
+			panic(targetPanics[i])
+		}
+		if targetJobErrors[i] != nil {
+			// TODO: we may not want to return completely here with multiple targets, because then no TargetErrors will be added to the JobError details
+			// Nevertheless, all target errors will be still in the OSBuildJobResult.
+			osbuildJobResult.JobError = targetJobErrors[i]
+			return
+		}
+		if skipped[i] {
+			skippedTargets = append(skippedTargets, jobTarget.Name)
+			continue
+		}
+		osbuildJobResult.TargetResults = append(osbuildJobResult.TargetResults, targetResults[i])
+	}
 
-				err = os.Symlink(exportedImagePath, imagePath)
-				if err != nil {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
-					break
-				}
+	targetErrors := osbuildJobResult.TargetErrors()
+	if len(skippedTargets) != 0 {
+		osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorDeadlineExceeded, "compose deadline exceeded, some targets were skipped", skippedTargets)
+	} else if len(targetErrors) != 0 {
+		osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorTargetError, "at least one target failed", targetErrors)
+	} else {
+		osbuildJobResult.Success = true
+		osbuildJobResult.UploadStatus = "success"
+	}
+}
 
-				err = vmware.ImportVmdk(credentials, imagePath)
-				if err != nil {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
-					break
-				}
-			} else if strings.HasSuffix(exportedImagePath, ".ova") {
-				err = vmware.ImportOva(credentials, exportedImagePath, jobTarget.ImageName)
-				if err != nil {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
-					break
-				}
-			} else {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, "No vmdk or ova provided", nil)
+// handleTarget uploads the artifact of the job to the target and returns the
+// result of the target. The returned error is set if the whole job has to
+// fail, errors of the target are part of the target result. Targets of a job
+// are handled concurrently.
+func (impl *OSBuildJobImpl) handleTarget(job worker.Job, jobArgs *worker.OSBuildJob, jobTarget *target.Target, outputDirectory string, osbuildJobResult *worker.OSBuildJobResult, manifestInfo *worker.ManifestInfo) (*target.TargetResult, *clienterrors.Error) {
+	logWithId := logrus.WithField("jobId", job.Id().String())
+
+	var err error
+	var targetResult *target.TargetResult
+	artifact := jobTarget.OsbuildArtifact
+	checksum := osbuildJobResult.ArtifactChecksums[path.Join(artifact.ExportName, artifact.ExportFilename)]
+	switch targetOptions := jobTarget.Options.(type) {
+	case *target.WorkerServerTargetOptions:
+		targetResult = target.NewWorkerServerTargetResult(&artifact)
+		var f *os.File
+		imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		f, err = os.Open(imagePath)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+			break
+		}
+		defer f.Close()
+		err = job.UploadArtifact(jobTarget.ImageName, f, checksum)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+
+	case *target.VMWareTargetOptions:
+		targetResult = target.NewVMWareTargetResult(nil, &artifact)
+		credentials := vmware.Credentials{
+			Username:   targetOptions.Username,
+			Password:   targetOptions.Password,
+			Host:       targetOptions.Host,
+			Cluster:    targetOptions.Cluster,
+			Datacenter: targetOptions.Datacenter,
+			Datastore:  targetOptions.Datastore,
+			Folder:     targetOptions.Folder,
+		}
+
+		tempDirectory, err := os.MkdirTemp(impl.Output, job.Id().String()+"-vmware-*")
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+
+		defer func() {
+			err := os.RemoveAll(tempDirectory)
+			if err != nil {
+				logWithId.Errorf("Error removing temporary directory for vmware symlink(%s): %v", tempDirectory, err)
+			}
+		}()
+
+		exportedImagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+
+		if targetOptions.ContentLibrary != "" {
+			itemID, err := vmware.ImportToContentLibrary(context.Background(), credentials, targetOptions.ContentLibrary, jobTarget.ImageName, exportedImagePath)
+			if err != nil {
+				targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
 				break
 			}
+			targetResult.Options = &target.VMWareTargetResultOptions{
+				ContentLibraryItemID: itemID,
+			}
+		} else if strings.HasSuffix(exportedImagePath, ".vmdk") {
+			// create a symlink so that uploaded image has the name specified by user
+			imageName := jobTarget.ImageName + ".vmdk"
+			imagePath := path.Join(tempDirectory, imageName)
 
-		case *target.AWSTargetOptions:
-			targetResult = target.NewAWSTargetResult(nil, &artifact)
-			a, err := impl.getAWS(targetOptions.Region, targetOptions.AccessKeyID, targetOptions.SecretAccessKey, targetOptions.SessionToken)
+			err = os.Symlink(exportedImagePath, imagePath)
 			if err != nil {
 				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
 				break
 			}
 
-			if targetOptions.Key == "" {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No AWS object key provided", nil)
+			err = vmware.ImportVmdk(credentials, imagePath)
+			if err != nil {
+				targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
 				break
 			}
-
-			bucket := targetOptions.Bucket
-			if bucket == "" {
-				bucket = impl.AWSBucket
-				if bucket == "" {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No AWS bucket provided", nil)
-					break
-				}
+		} else if strings.HasSuffix(exportedImagePath, ".ova") {
+			err = vmware.ImportOva(credentials, exportedImagePath, jobTarget.ImageName)
+			if err != nil {
+				targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+				break
 			}
+		} else {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, "No vmdk or ova provided", nil)
+			break
+		}
 
-			// TODO: Remove this once multiple exports will be supported and used by image definitions
-			// RHUI images tend to be produced as archives in Brew to save disk space,
-			// however they can't be imported to the cloud provider as an archive.
-			// Workaround this situation for Koji composes by checking if the image file
-			// is an archive and if it is, extract it before uploading to the cloud.
-			imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
-			if strings.HasSuffix(imagePath, ".xz") {
-				imagePath, err = extractXzArchive(imagePath)
-				if err != nil {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorTargetError, "Failed to extract compressed image", err.Error())
-					break
-				}
+	case *target.AWSTargetOptions:
+		targetResult = target.NewAWSTargetResult(nil, &artifact)
+		a, err := impl.getAWS(targetOptions.Region, targetOptions.AccessKeyID, targetOptions.SecretAccessKey, targetOptions.SessionToken)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+
+		if targetOptions.Key == "" {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No AWS object key provided", nil)
+			break
+		}
+
+		bucket := targetOptions.Bucket
+		if bucket == "" {
+			bucket = impl.AWSBucket
+			if bucket == "" {
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No AWS bucket provided", nil)
+				break
 			}
+		}
 
-			_, err = a.Upload(imagePath, bucket, targetOptions.Key)
+		// fail before uploading the image if the key can't be used
+		var kmsKeyARN string
+		if targetOptions.KMSKeyID != "" {
+			kmsKeyARN, err = a.KMSKeyARN(targetOptions.KMSKeyID)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("Invalid KMS key %s: %v", targetOptions.KMSKeyID, err), nil)
 				break
 			}
+		}
 
-			ami, err := a.Register(jobTarget.ImageName, bucket, targetOptions.Key, targetOptions.ShareWithAccounts, common.CurrentArch(), targetOptions.BootMode)
+		// TODO: Remove this once multiple exports will be supported and used by image definitions
+		// RHUI images tend to be produced as archives in Brew to save disk space,
+		// however they can't be imported to the cloud provider as an archive.
+		// Workaround this situation for Koji composes by checking if the image file
+		// is an archive and if it is, extract it before uploading to the cloud.
+		imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		if strings.HasSuffix(imagePath, ".xz") {
+			imagePath, err = extractXzArchive(imagePath)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorImportingImage, err.Error(), nil)
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorTargetError, "Failed to extract compressed image", err.Error())
 				break
 			}
+		}
 
-			if ami == nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorImportingImage, "No ami returned", nil)
+		_, err = impl.ResumableUploads.uploadS3(a, imagePath, bucket, targetOptions.Key)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+
+		// the AMI is registered by the user, with their own parameters
+		if targetOptions.SnapshotOnly {
+			snapshotID, err := a.ImportSnapshot(jobTarget.ImageName, bucket, targetOptions.Key, targetOptions.ShareWithAccounts, kmsKeyARN)
+			if err != nil {
+				targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, err.Error(), err)
 				break
 			}
 			targetResult.Options = &target.AWSTargetResultOptions{
-				Ami:    *ami,
-				Region: targetOptions.Region,
+				Region:     targetOptions.Region,
+				SnapshotID: *snapshotID,
+				KMSKeyARN:  kmsKeyARN,
 			}
+			break
+		}
 
-		case *target.AWSS3TargetOptions:
-			targetResult = target.NewAWSS3TargetResult(nil, &artifact)
-			a, bucket, err := impl.getAWSForS3Target(targetOptions)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
-				break
-			}
+		registerOptions := awscloud.RegisterOptions{
+			BootMode:        targetOptions.BootMode,
+			KMSKeyARN:       kmsKeyARN,
+			Tags:            targetOptions.Tags,
+			IMDSSupport:     targetOptions.IMDSSupport,
+			ENASupport:      targetOptions.ENASupport,
+			SriovNetSupport: targetOptions.SriovNetSupport,
+		}
+		ami, err := a.Register(jobTarget.ImageName, bucket, targetOptions.Key, targetOptions.ShareWithAccounts, common.CurrentArch(), registerOptions)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, err.Error(), err)
+			break
+		}
 
-			if targetOptions.Key == "" {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No AWS object key provided", nil)
-				break
-			}
+		if ami == nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorImportingImage, "No ami returned", nil)
+			break
+		}
+		enaSupport := targetOptions.ENASupport
+		if enaSupport == nil {
+			enaSupport = common.ToPtr(true)
+		}
+		targetResult.Options = &target.AWSTargetResultOptions{
+			Ami:             *ami,
+			Region:          targetOptions.Region,
+			KMSKeyARN:       kmsKeyARN,
+			BootMode:        targetOptions.BootMode,
+			Tags:            targetOptions.Tags,
+			IMDSSupport:     targetOptions.IMDSSupport,
+			ENASupport:      enaSupport,
+			SriovNetSupport: targetOptions.SriovNetSupport,
+		}
+
+	case *target.AWSS3TargetOptions:
+		targetResult = target.NewAWSS3TargetResult(nil, &artifact)
+		a, bucket, err := impl.getAWSForS3Target(targetOptions)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
 
-			url, targetError := uploadToS3(a, outputDirectory, jobTarget.OsbuildArtifact.ExportName, bucket, targetOptions.Key, jobTarget.OsbuildArtifact.ExportFilename, targetOptions.Public)
+		if targetOptions.Key == "" {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No AWS object key provided", nil)
+			break
+		}
+
+		key := s3ObjectKey(targetOptions.Key, jobTarget.OsbuildArtifact.ExportFilename)
+		url, targetError := uploadToS3(a, impl.ResumableUploads, outputDirectory, jobTarget.OsbuildArtifact.ExportName, bucket, key, jobTarget.OsbuildArtifact.ExportFilename, targetOptions)
+		if targetError != nil {
+			targetResult.TargetError = targetError
+			break
+		}
+		resultOptions := &target.AWSS3TargetResultOptions{URL: url}
+		if targetOptions.ReturnObjectKey {
+			resultOptions.Bucket = bucket
+			resultOptions.Key = key
+		}
+		targetResult.Options = resultOptions
+
+		if jobArgs.ChecksumManifest {
+			resultOptions.ChecksumsURL, targetError = impl.uploadChecksumManifest(job, a, bucket, targetOptions, osbuildJobResult.ArtifactChecksums)
 			if targetError != nil {
 				targetResult.TargetError = targetError
 				break
 			}
-			targetResult.Options = &target.AWSS3TargetResultOptions{URL: url}
+		}
 
-		case *target.AzureTargetOptions:
-			targetResult = target.NewAzureTargetResult(&artifact)
-			azureStorageClient, err := azure.NewStorageClient(targetOptions.StorageAccount, targetOptions.StorageAccessKey)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
-				break
-			}
+	case *target.AzureTargetOptions:
+		targetResult = target.NewAzureTargetResult(&artifact)
+		azureStorageClient, err := azure.NewStorageClient(targetOptions.StorageAccount, targetOptions.StorageAccessKey)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
 
-			// Azure cannot create an image from a blob without .vhd extension
-			blobName := azure.EnsureVHDExtension(jobTarget.ImageName)
-			metadata := azure.BlobMetadata{
-				StorageAccount: targetOptions.StorageAccount,
-				ContainerName:  targetOptions.Container,
-				BlobName:       blobName,
-			}
+		// Azure cannot create an image from a blob without .vhd extension
+		blobName := azure.EnsureVHDExtension(jobTarget.ImageName)
+		metadata := azure.BlobMetadata{
+			StorageAccount: targetOptions.StorageAccount,
+			ContainerName:  targetOptions.Container,
+			BlobName:       blobName,
+		}
 
-			const azureMaxUploadGoroutines = 4
-			err = azureStorageClient.UploadPageBlob(
-				metadata,
-				path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename),
-				azureMaxUploadGoroutines,
-			)
+		const azureMaxUploadGoroutines = 4
+		err = impl.ResumableUploads.uploadPageBlob(
+			azureStorageClient,
+			metadata,
+			path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename),
+			azureMaxUploadGoroutines,
+		)
 
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
-				break
-			}
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
 
-		case *target.GCPTargetOptions:
-			targetResult = target.NewGCPTargetResult(nil, &artifact)
-			ctx := context.Background()
+	case *target.GCPTargetOptions:
+		targetResult = target.NewGCPTargetResult(nil, &artifact)
+		ctx := context.Background()
 
-			g, err := impl.getGCP(targetOptions.Credentials)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
-				break
-			}
+		g, err := impl.getGCP(targetOptions.Credentials)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
 
-			if targetOptions.Object == "" {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No GCP object key provided", nil)
-				break
-			}
+		if targetOptions.Object == "" {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No GCP object key provided", nil)
+			break
+		}
 
-			bucket := targetOptions.Bucket
+		bucket := targetOptions.Bucket
+		if bucket == "" {
+			bucket = impl.GCPConfig.Bucket
 			if bucket == "" {
-				bucket = impl.GCPConfig.Bucket
-				if bucket == "" {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No GCP bucket provided", nil)
-					break
-				}
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No GCP bucket provided", nil)
+				break
 			}
+		}
+
+		logWithId.Infof("[GCP] 🚀 Uploading image to: %s/%s", bucket, targetOptions.Object)
+		err = impl.ResumableUploads.uploadGCS(ctx, g, path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename),
+			bucket, targetOptions.Object, map[string]string{gcp.MetadataKeyImageName: jobTarget.ImageName})
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+
+		logWithId.Infof("[GCP] 📥 Importing image into Compute Engine as '%s'", jobTarget.ImageName)
+
+		imageOptions := gcp.ComputeImageOptions{
+			GuestOsFeatures: gcp.GuestOsFeaturesWith(gcp.GuestOsFeaturesByDistro(targetOptions.Os), targetOptions.GuestOsFeatures...),
+			Architecture:    targetOptions.Architecture,
+			Family:          targetOptions.Family,
+			Licenses:        targetOptions.Licenses,
+		}
+		_, importErr := g.ComputeImageInsert(ctx, bucket, targetOptions.Object, jobTarget.ImageName, []string{targetOptions.Region}, imageOptions)
+		if importErr == nil {
+			logWithId.Infof("[GCP] 🎉 Image import finished successfully")
+		}
+
+		// Cleanup storage before checking for errors
+		logWithId.Infof("[GCP] 🧹 Deleting uploaded image file: %s/%s", bucket, targetOptions.Object)
+		if err = g.StorageObjectDelete(ctx, bucket, targetOptions.Object); err != nil {
+			logWithId.Errorf("[GCP] Encountered error while deleting object: %v", err)
+		}
 
-			logWithId.Infof("[GCP] 🚀 Uploading image to: %s/%s", bucket, targetOptions.Object)
-			_, err = g.StorageObjectUpload(ctx, path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename),
-				bucket, targetOptions.Object, map[string]string{gcp.MetadataKeyImageName: jobTarget.ImageName})
+		// check error from ComputeImageInsert()
+		if importErr != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, importErr.Error(), importErr)
+			break
+		}
+		logWithId.Infof("[GCP] 💿 Image URL: %s", g.ComputeImageURL(jobTarget.ImageName))
+
+		if len(targetOptions.ShareWithAccounts) > 0 {
+			logWithId.Infof("[GCP] 🔗 Sharing the image with: %+v", targetOptions.ShareWithAccounts)
+			err = g.ComputeImageShare(ctx, jobTarget.ImageName, targetOptions.ShareWithAccounts)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+				targetResult.TargetError = targetError(clienterrors.ErrorSharingTarget, err.Error(), err)
 				break
 			}
+		}
+		targetResult.Options = &target.GCPTargetResultOptions{
+			ImageName: jobTarget.ImageName,
+			ProjectID: g.GetProjectID(),
+		}
 
-			logWithId.Infof("[GCP] 📥 Importing image into Compute Engine as '%s'", jobTarget.ImageName)
-
-			_, importErr := g.ComputeImageInsert(ctx, bucket, targetOptions.Object, jobTarget.ImageName, []string{targetOptions.Region}, gcp.GuestOsFeaturesByDistro(targetOptions.Os))
-			if importErr == nil {
-				logWithId.Infof("[GCP] 🎉 Image import finished successfully")
-			}
+	case *target.AzureImageTargetOptions:
+		targetResult = target.NewAzureImageTargetResult(nil, &artifact)
+		ctx := context.Background()
 
-			// Cleanup storage before checking for errors
-			logWithId.Infof("[GCP] 🧹 Deleting uploaded image file: %s/%s", bucket, targetOptions.Object)
-			if err = g.StorageObjectDelete(ctx, bucket, targetOptions.Object); err != nil {
-				logWithId.Errorf("[GCP] Encountered error while deleting object: %v", err)
-			}
+		if impl.AzureConfig.Creds == nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorSharingTarget, "osbuild job has org.osbuild.azure.image target but this worker doesn't have azure credentials", nil)
+			break
+		}
 
-			// check error from ComputeImageInsert()
-			if importErr != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorImportingImage, importErr.Error(), nil)
+		c, err := azure.NewClient(*impl.AzureConfig.Creds, targetOptions.TenantID)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+			break
+		}
+		logWithId.Info("[Azure] 🔑 Logged in Azure")
+
+		// the generation and the security type can't be changed once the
+		// image exists, fail before uploading it if the gallery image
+		// definition doesn't match them
+		hyperVGeneration := targetOptions.HyperVGeneration
+		if gallery := targetOptions.Gallery; gallery != nil && !targetOptions.BlobOnly {
+			definition, err := c.GetGalleryImageDefinition(ctx, targetOptions.SubscriptionID, targetOptions.ResourceGroup, gallery.Name, gallery.ImageDefinition)
+			if err != nil {
+				targetResult.TargetError = targetError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("retrieving the gallery image definition failed: %v", err), err)
 				break
 			}
-			logWithId.Infof("[GCP] 💿 Image URL: %s", g.ComputeImageURL(jobTarget.ImageName))
-
-			if len(targetOptions.ShareWithAccounts) > 0 {
-				logWithId.Infof("[GCP] 🔗 Sharing the image with: %+v", targetOptions.ShareWithAccounts)
-				err = g.ComputeImageShare(ctx, jobTarget.ImageName, targetOptions.ShareWithAccounts)
-				if err != nil {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorSharingTarget, err.Error(), nil)
-					break
-				}
+			definitionGeneration := definition.HyperVGeneration
+			if definitionGeneration == "" {
+				definitionGeneration = azure.HyperVGenerationV1
 			}
-			targetResult.Options = &target.GCPTargetResultOptions{
-				ImageName: jobTarget.ImageName,
-				ProjectID: g.GetProjectID(),
+			if hyperVGeneration == "" {
+				hyperVGeneration = definitionGeneration
 			}
-
-		case *target.AzureImageTargetOptions:
-			targetResult = target.NewAzureImageTargetResult(nil, &artifact)
-			ctx := context.Background()
-
-			if impl.AzureConfig.Creds == nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorSharingTarget, "osbuild job has org.osbuild.azure.image target but this worker doesn't have azure credentials", nil)
+			if hyperVGeneration != definitionGeneration {
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig,
+					fmt.Sprintf("gallery image definition %s/%s is %s, the image is %s", gallery.Name, gallery.ImageDefinition, definitionGeneration, hyperVGeneration), nil)
 				break
 			}
-
-			c, err := azure.NewClient(*impl.AzureConfig.Creds, targetOptions.TenantID)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+			if targetOptions.SecurityType != "" && !definition.SupportsSecurityType(targetOptions.SecurityType) {
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig,
+					fmt.Sprintf("gallery image definition %s/%s doesn't support security type %s", gallery.Name, gallery.ImageDefinition, targetOptions.SecurityType), nil)
 				break
 			}
-			logWithId.Info("[Azure] 🔑 Logged in Azure")
+		}
 
-			storageAccountTag := azure.Tag{
-				Name:  "imageBuilderStorageAccount",
-				Value: fmt.Sprintf("location=%s", targetOptions.Location),
-			}
+		storageAccountTag := azure.Tag{
+			Name:  "imageBuilderStorageAccount",
+			Value: fmt.Sprintf("location=%s", targetOptions.Location),
+		}
 
-			storageAccount, err := c.GetResourceNameByTag(
-				ctx,
-				targetOptions.SubscriptionID,
-				targetOptions.ResourceGroup,
-				storageAccountTag,
-			)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("searching for a storage account failed: %v", err), nil)
-				break
-			}
+		storageAccount, err := c.GetResourceNameByTag(
+			ctx,
+			targetOptions.SubscriptionID,
+			targetOptions.ResourceGroup,
+			storageAccountTag,
+		)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("searching for a storage account failed: %v", err), err)
+			break
+		}
 
-			if storageAccount == "" {
-				logWithId.Info("[Azure] 📦 Creating a new storage account")
-				const storageAccountPrefix = "ib"
-				storageAccount = azure.RandomStorageAccountName(storageAccountPrefix)
-
-				err := c.CreateStorageAccount(
-					ctx,
-					targetOptions.SubscriptionID,
-					targetOptions.ResourceGroup,
-					storageAccount,
-					targetOptions.Location,
-					storageAccountTag,
-				)
-				if err != nil {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("creating a new storage account failed: %v", err), nil)
-					break
-				}
-			}
+		if storageAccount == "" {
+			logWithId.Info("[Azure] 📦 Creating a new storage account")
+			const storageAccountPrefix = "ib"
+			storageAccount = azure.RandomStorageAccountName(storageAccountPrefix)
 
-			logWithId.Info("[Azure] 🔑📦 Retrieving a storage account key")
-			storageAccessKey, err := c.GetStorageAccountKey(
+			err := c.CreateStorageAccount(
 				ctx,
 				targetOptions.SubscriptionID,
 				targetOptions.ResourceGroup,
 				storageAccount,
+				targetOptions.Location,
+				storageAccountTag,
 			)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("retrieving the storage account key failed: %v", err), nil)
+				targetResult.TargetError = targetError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("creating a new storage account failed: %v", err), err)
 				break
 			}
+		}
 
-			azureStorageClient, err := azure.NewStorageClient(storageAccount, storageAccessKey)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("creating the storage client failed: %v", err), nil)
-				break
-			}
+		logWithId.Info("[Azure] 🔑📦 Retrieving a storage account key")
+		storageAccessKey, err := c.GetStorageAccountKey(
+			ctx,
+			targetOptions.SubscriptionID,
+			targetOptions.ResourceGroup,
+			storageAccount,
+		)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("retrieving the storage account key failed: %v", err), err)
+			break
+		}
 
-			storageContainer := "imagebuilder"
+		azureStorageClient, err := azure.NewStorageClient(storageAccount, storageAccessKey)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("creating the storage client failed: %v", err), err)
+			break
+		}
+
+		storageContainer := "imagebuilder"
 
-			logWithId.Info("[Azure] 📦 Ensuring that we have a storage container")
-			err = azureStorageClient.CreateStorageContainerIfNotExist(ctx, storageAccount, storageContainer)
+		logWithId.Info("[Azure] 📦 Ensuring that we have a storage container")
+		err = azureStorageClient.CreateStorageContainerIfNotExist(ctx, storageAccount, storageContainer)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("cannot create a storage container: %v", err), err)
+			break
+		}
+
+		// Azure cannot create an image from a blob without .vhd extension
+		blobName := azure.EnsureVHDExtension(jobTarget.ImageName)
+
+		// TODO: Remove this once multiple exports will be supported and used by image definitions
+		// RHUI images tend to be produced as archives in Brew to save disk space,
+		// however they can't be imported to the cloud provider as an archive.
+		// Workaround this situation for Koji composes by checking if the image file
+		// is an archive and if it is, extract it before uploading to the cloud.
+		imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		if strings.HasSuffix(imagePath, ".xz") {
+			imagePath, err = extractXzArchive(imagePath)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("cannot create a storage container: %v", err), nil)
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorTargetError, "Failed to extract compressed image", err.Error())
 				break
 			}
+		}
 
-			// Azure cannot create an image from a blob without .vhd extension
-			blobName := azure.EnsureVHDExtension(jobTarget.ImageName)
-
-			// TODO: Remove this once multiple exports will be supported and used by image definitions
-			// RHUI images tend to be produced as archives in Brew to save disk space,
-			// however they can't be imported to the cloud provider as an archive.
-			// Workaround this situation for Koji composes by checking if the image file
-			// is an archive and if it is, extract it before uploading to the cloud.
-			imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
-			if strings.HasSuffix(imagePath, ".xz") {
-				imagePath, err = extractXzArchive(imagePath)
+		logWithId.Info("[Azure] ⬆ Uploading the image")
+		err = impl.ResumableUploads.uploadPageBlob(
+			azureStorageClient,
+			azure.BlobMetadata{
+				StorageAccount: storageAccount,
+				ContainerName:  storageContainer,
+				BlobName:       blobName,
+			},
+			imagePath,
+			impl.AzureConfig.UploadThreads,
+		)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, fmt.Sprintf("uploading the image failed: %v", err), err)
+			break
+		}
+
+		if targetOptions.BlobOnly {
+			blob := azure.BlobMetadata{
+				StorageAccount: storageAccount,
+				ContainerName:  storageContainer,
+				BlobName:       blobName,
+			}
+			azureResult := &target.AzureImageTargetResultOptions{
+				BlobURL: azure.BlobURL(blob),
+			}
+			if targetOptions.SASExpiryHours > 0 {
+				expiry := time.Now().Add(time.Duration(targetOptions.SASExpiryHours) * time.Hour)
+				azureResult.SASToken, err = azureStorageClient.BlobSASToken(blob, expiry)
 				if err != nil {
-					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorTargetError, "Failed to extract compressed image", err.Error())
+					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorGeneratingSignedURL, err.Error(), nil)
 					break
 				}
 			}
+			logWithId.Infof("[Azure] 🎉 Image uploaded to %s", azureResult.BlobURL)
+			targetResult.Options = azureResult
+			break
+		}
 
-			logWithId.Info("[Azure] ⬆ Uploading the image")
-			err = azureStorageClient.UploadPageBlob(
-				azure.BlobMetadata{
-					StorageAccount: storageAccount,
-					ContainerName:  storageContainer,
-					BlobName:       blobName,
-				},
-				imagePath,
-				impl.AzureConfig.UploadThreads,
-			)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, fmt.Sprintf("uploading the image failed: %v", err), nil)
-				break
-			}
+		logWithId.Info("[Azure] 📝 Registering the image")
+		err = c.RegisterImage(
+			ctx,
+			targetOptions.SubscriptionID,
+			targetOptions.ResourceGroup,
+			storageAccount,
+			storageContainer,
+			blobName,
+			jobTarget.ImageName,
+			targetOptions.Location,
+			hyperVGeneration,
+		)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, fmt.Sprintf("registering the image failed: %v", err), err)
+			break
+		}
+		logWithId.Info("[Azure] 🎉 Image uploaded and registered!")
+		azureResult := &target.AzureImageTargetResultOptions{
+			ImageName: jobTarget.ImageName,
+		}
 
-			logWithId.Info("[Azure] 📝 Registering the image")
-			err = c.RegisterImage(
+		if gallery := targetOptions.Gallery; gallery != nil {
+			logWithId.Infof("[Azure] 🖼 Publishing the image as version %s of %s/%s", gallery.Version, gallery.Name, gallery.ImageDefinition)
+			var regions []azure.GalleryTargetRegion
+			for _, r := range gallery.TargetRegions {
+				regions = append(regions, azure.GalleryTargetRegion{
+					Name:               r.Name,
+					ReplicaCount:       r.ReplicaCount,
+					StorageAccountType: r.StorageAccountType,
+				})
+			}
+			azureResult.GalleryImageVersionID, err = c.CreateGalleryImageVersion(
 				ctx,
 				targetOptions.SubscriptionID,
 				targetOptions.ResourceGroup,
-				storageAccount,
-				storageContainer,
-				blobName,
 				jobTarget.ImageName,
 				targetOptions.Location,
+				azure.GalleryImageVersion{
+					Gallery:            gallery.Name,
+					ImageDefinition:    gallery.ImageDefinition,
+					Version:            gallery.Version,
+					ReplicaCount:       gallery.ReplicaCount,
+					StorageAccountType: gallery.StorageAccountType,
+					TargetRegions:      regions,
+				},
 			)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorImportingImage, fmt.Sprintf("registering the image failed: %v", err), nil)
+				targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, fmt.Sprintf("publishing the gallery image version failed: %v", err), err)
 				break
 			}
-			logWithId.Info("[Azure] 🎉 Image uploaded and registered!")
-			targetResult.Options = &target.AzureImageTargetResultOptions{
-				ImageName: jobTarget.ImageName,
-			}
+			logWithId.Info("[Azure] 🎉 Gallery image version published!")
+		}
+		targetResult.Options = azureResult
 
-		case *target.KojiTargetOptions:
-			targetResult = target.NewKojiTargetResult(nil, &artifact)
-			kojiServerURL, err := url.Parse(targetOptions.Server)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("failed to parse Koji server URL: %v", err), nil)
-				break
-			}
+	case *target.KojiTargetOptions:
+		targetResult = target.NewKojiTargetResult(nil, &artifact)
+		kojiServerURL, err := url.Parse(targetOptions.Server)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("failed to parse Koji server URL: %v", err), nil)
+			break
+		}
 
-			kojiServer, exists := impl.KojiServers[kojiServerURL.Hostname()]
-			if !exists {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("Koji server has not been configured: %s", kojiServerURL.Hostname()), nil)
-				break
-			}
+		kojiServer, exists := impl.KojiServers[kojiServerURL.Hostname()]
+		if !exists {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("Koji server has not been configured: %s", kojiServerURL.Hostname()), nil)
+			break
+		}
 
-			kojiTransport := koji.CreateKojiTransport(kojiServer.relaxTimeoutFactor)
+		kojiCreds, err := kojiServer.credentials(targetOptions.Principal)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+			break
+		}
 
-			kojiAPI, err := koji.NewFromGSSAPI(targetOptions.Server, &kojiServer.creds, kojiTransport)
-			if err != nil {
-				logWithId.Warnf("[Koji] 🔑 login failed: %v", err) // DON'T EDIT: Used for Splunk dashboard
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("failed to authenticate with Koji server %q: %v", kojiServerURL.Hostname(), err), nil)
-				break
-			}
-			logWithId.Infof("[Koji] 🔑 Authenticated with %q", kojiServerURL.Hostname())
-			defer func() {
-				err := kojiAPI.Logout()
-				if err != nil {
-					logWithId.Warnf("[Koji] logout failed: %v", err)
-				}
-			}()
+		kojiTransport := koji.CreateKojiTransport(kojiServer.relaxTimeoutFactor)
 
-			file, err := os.Open(path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename))
+		kojiAPI, err := koji.NewFromGSSAPI(targetOptions.Server, kojiCreds, kojiTransport)
+		if err != nil {
+			logWithId.Warnf("[Koji] 🔑 login failed: %v", err) // DON'T EDIT: Used for Splunk dashboard
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("failed to authenticate with Koji server %q: %v", kojiServerURL.Hostname(), err), nil)
+			break
+		}
+		logWithId.Infof("[Koji] 🔑 Authenticated with %q", kojiServerURL.Hostname())
+		defer func() {
+			err := kojiAPI.Logout()
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, fmt.Sprintf("failed to open the image for reading: %v", err), nil)
-				break
+				logWithId.Warnf("[Koji] logout failed: %v", err)
 			}
-			defer file.Close()
+		}()
 
-			logWithId.Info("[Koji] ⬆ Uploading the image")
-			imageHash, imageSize, err := kojiAPI.Upload(file, targetOptions.UploadDirectory, jobTarget.ImageName)
-			if err != nil {
-				logWithId.Warnf("[Koji] ⬆ upload failed: %v", err) // DON'T EDIT: used for Splunk dashboard
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
-				break
-			}
-			logWithId.Info("[Koji] 🎉 Image successfully uploaded")
+		file, err := os.Open(path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename))
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, fmt.Sprintf("failed to open the image for reading: %v", err), nil)
+			break
+		}
+		defer file.Close()
 
-			var manifest bytes.Buffer
-			err = json.Indent(&manifest, jobArgs.Manifest, "", "  ")
-			if err != nil {
-				logWithId.Warnf("[Koji] Indenting osbuild manifest failed: %v", err)
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, err.Error(), nil)
-				break
-			}
-			logWithId.Info("[Koji] ⬆ Uploading the osbuild manifest")
-			manifestFilename := jobTarget.ImageName + ".manifest.json"
-			manifestHash, manifestSize, err := kojiAPI.Upload(&manifest, targetOptions.UploadDirectory, manifestFilename)
-			if err != nil {
-				logWithId.Warnf("[Koji] ⬆ upload failed: %v", err)
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
-				break
-			}
-			logWithId.Info("[Koji] 🎉 Manifest successfully uploaded")
+		logWithId.Info("[Koji] ⬆ Uploading the image")
+		imageHash, imageSize, err := kojiAPI.Upload(file, targetOptions.UploadDirectory, jobTarget.ImageName)
+		if err != nil {
+			logWithId.Warnf("[Koji] ⬆ upload failed: %v", err) // DON'T EDIT: used for Splunk dashboard
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		logWithId.Info("[Koji] 🎉 Image successfully uploaded")
 
-			var osbuildLog bytes.Buffer
-			err = osbuildJobResult.OSBuildOutput.Write(&osbuildLog)
-			if err != nil {
-				logWithId.Warnf("[Koji] Converting osbuild log to text failed: %v", err)
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, err.Error(), nil)
-				break
-			}
-			logWithId.Info("[Koji] ⬆ Uploading the osbuild output log")
-			osbuildOutputFilename := jobTarget.ImageName + ".osbuild.log"
-			osbuildOutputHash, osbuildOutputSize, err := kojiAPI.Upload(&osbuildLog, targetOptions.UploadDirectory, osbuildOutputFilename)
-			if err != nil {
-				logWithId.Warnf("[Koji] ⬆ upload failed: %v", err)
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
-				break
-			}
-			logWithId.Info("[Koji] 🎉 osbuild output log successfully uploaded")
-
-			// Attach the manifest info to the koji target result, so that it
-			// it can be imported to the Koji build by the koji-finalize job.
-			var kojiManifestInfo *target.ManifestInfo
-			if manifestInfo != nil {
-				kojiManifestInfo = &target.ManifestInfo{
-					OSBuildComposerVersion: manifestInfo.OSBuildComposerVersion,
+		var manifest bytes.Buffer
+		err = json.Indent(&manifest, jobArgs.Manifest, "", "  ")
+		if err != nil {
+			logWithId.Warnf("[Koji] Indenting osbuild manifest failed: %v", err)
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, err.Error(), nil)
+			break
+		}
+		logWithId.Info("[Koji] ⬆ Uploading the osbuild manifest")
+		manifestFilename := jobTarget.ImageName + ".manifest.json"
+		manifestHash, manifestSize, err := kojiAPI.Upload(&manifest, targetOptions.UploadDirectory, manifestFilename)
+		if err != nil {
+			logWithId.Warnf("[Koji] ⬆ upload failed: %v", err)
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		logWithId.Info("[Koji] 🎉 Manifest successfully uploaded")
+
+		var osbuildLog bytes.Buffer
+		err = osbuildJobResult.OSBuildOutput.Write(&osbuildLog)
+		if err != nil {
+			logWithId.Warnf("[Koji] Converting osbuild log to text failed: %v", err)
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, err.Error(), nil)
+			break
+		}
+		logWithId.Info("[Koji] ⬆ Uploading the osbuild output log")
+		osbuildOutputFilename := jobTarget.ImageName + ".osbuild.log"
+		osbuildOutputHash, osbuildOutputSize, err := kojiAPI.Upload(&osbuildLog, targetOptions.UploadDirectory, osbuildOutputFilename)
+		if err != nil {
+			logWithId.Warnf("[Koji] ⬆ upload failed: %v", err)
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		logWithId.Info("[Koji] 🎉 osbuild output log successfully uploaded")
+
+		var imageRPMs []rpmmd.RPM
+		for _, plName := range osbuildJobResult.PipelineNames.Payload {
+			imageRPMs = append(imageRPMs, osbuild.OSBuildMetadataToRPMs(osbuildJobResult.OSBuildOutput.Metadata[plName])...)
+		}
+		sbom, err := spdxSBOM(jobTarget.ImageName, rpmmd.DeduplicateRPMs(imageRPMs), time.Now())
+		if err != nil {
+			logWithId.Warnf("[Koji] Creating the SBOM failed: %v", err)
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, err.Error(), nil)
+			break
+		}
+		logWithId.Info("[Koji] ⬆ Uploading the SBOM")
+		sbomFilename := jobTarget.ImageName + ".spdx.json"
+		sbomHash, sbomSize, err := kojiAPI.Upload(bytes.NewReader(sbom), targetOptions.UploadDirectory, sbomFilename)
+		if err != nil {
+			logWithId.Warnf("[Koji] ⬆ upload failed: %v", err)
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		logWithId.Info("[Koji] 🎉 SBOM successfully uploaded")
+
+		partitionTable, err := manifestPartitionTable(jobArgs.Manifest)
+		if err != nil {
+			// the partition table is informational only
+			logWithId.Warnf("[Koji] Reading the partition table from the manifest failed: %v", err)
+		}
+
+		// Attach the manifest info to the koji target result, so that it
+		// it can be imported to the Koji build by the koji-finalize job.
+		var kojiManifestInfo *target.ManifestInfo
+		if manifestInfo != nil {
+			kojiManifestInfo = &target.ManifestInfo{
+				OSBuildComposerVersion: manifestInfo.OSBuildComposerVersion,
+			}
+			for _, composerDep := range manifestInfo.OSBuildComposerDeps {
+				dep := &target.OSBuildComposerDepModule{
+					Path:    composerDep.Path,
+					Version: composerDep.Version,
 				}
-				for _, composerDep := range manifestInfo.OSBuildComposerDeps {
-					dep := &target.OSBuildComposerDepModule{
-						Path:    composerDep.Path,
-						Version: composerDep.Version,
-					}
-					if composerDep.Replace != nil {
-						dep.Replace = &target.OSBuildComposerDepModule{
-							Path:    composerDep.Replace.Path,
-							Version: composerDep.Replace.Version,
-						}
+				if composerDep.Replace != nil {
+					dep.Replace = &target.OSBuildComposerDepModule{
+						Path:    composerDep.Replace.Path,
+						Version: composerDep.Replace.Version,
 					}
-					kojiManifestInfo.OSBuildComposerDeps = append(kojiManifestInfo.OSBuildComposerDeps, dep)
 				}
+				kojiManifestInfo.OSBuildComposerDeps = append(kojiManifestInfo.OSBuildComposerDeps, dep)
 			}
+		}
 
-			targetResult.Options = &target.KojiTargetResultOptions{
-				Image: &target.KojiOutputInfo{
-					Filename:     jobTarget.ImageName,
-					ChecksumType: target.ChecksumTypeMD5,
-					Checksum:     imageHash,
-					Size:         imageSize,
-				},
-				OSBuildManifest: &target.KojiOutputInfo{
-					Filename:     manifestFilename,
-					ChecksumType: target.ChecksumTypeMD5,
-					Checksum:     manifestHash,
-					Size:         manifestSize,
-				},
-				Log: &target.KojiOutputInfo{
-					Filename:     osbuildOutputFilename,
-					ChecksumType: target.ChecksumTypeMD5,
-					Checksum:     osbuildOutputHash,
-					Size:         osbuildOutputSize,
-				},
-				OSBuildManifestInfo: kojiManifestInfo,
-			}
+		targetResult.Options = &target.KojiTargetResultOptions{
+			Image: &target.KojiOutputInfo{
+				Filename:     jobTarget.ImageName,
+				ChecksumType: target.ChecksumTypeMD5,
+				Checksum:     imageHash,
+				Size:         imageSize,
+			},
+			OSBuildManifest: &target.KojiOutputInfo{
+				Filename:     manifestFilename,
+				ChecksumType: target.ChecksumTypeMD5,
+				Checksum:     manifestHash,
+				Size:         manifestSize,
+			},
+			Log: &target.KojiOutputInfo{
+				Filename:     osbuildOutputFilename,
+				ChecksumType: target.ChecksumTypeMD5,
+				Checksum:     osbuildOutputHash,
+				Size:         osbuildOutputSize,
+			},
+			OSBuildManifestInfo: kojiManifestInfo,
+			SBOM: &target.KojiOutputInfo{
+				Filename:     sbomFilename,
+				ChecksumType: target.ChecksumTypeMD5,
+				Checksum:     sbomHash,
+				Size:         sbomSize,
+			},
+			PartitionTable: partitionTable,
+		}
 
-		case *target.OCITargetOptions:
-			targetResult = target.NewOCITargetResult(nil, &artifact)
-			// create an ociClient uploader with a valid storage client
-			var ociClient oci.Client
-			ociClient, err = impl.getOCI(oci.ClientParams{
-				User:        targetOptions.User,
-				Region:      targetOptions.Region,
-				Tenancy:     targetOptions.Tenancy,
-				Fingerprint: targetOptions.Fingerprint,
-				PrivateKey:  targetOptions.PrivateKey,
-			})
+	case *target.OCITargetOptions:
+		targetResult = target.NewOCITargetResult(nil, &artifact)
+		// create an ociClient uploader with a valid storage client
+		var ociClient oci.Client
+		ociClient, err = impl.getOCI(oci.ClientParams{
+			User:        targetOptions.User,
+			Region:      targetOptions.Region,
+			Tenancy:     targetOptions.Tenancy,
+			Fingerprint: targetOptions.Fingerprint,
+			PrivateKey:  targetOptions.PrivateKey,
+		})
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+		logWithId.Info("[OCI] 🔑 Logged in OCI")
+		logWithId.Info("[OCI] ⬆ Uploading the image")
+		file, err := os.Open(path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename))
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+		defer file.Close()
+		i, _ := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+		bucket := impl.OCIConfig.Bucket
+		if targetOptions.Bucket != "" {
+			bucket = targetOptions.Bucket
+		}
+		namespace := impl.OCIConfig.Namespace
+		if targetOptions.Namespace != "" {
+			namespace = targetOptions.Namespace
+		}
+		err = ociClient.Upload(
+			fmt.Sprintf("osbuild-upload-%d", i),
+			bucket,
+			namespace,
+			file,
+		)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+
+		compartment := impl.OCIConfig.Compartment
+		if targetOptions.Compartment != "" {
+			compartment = targetOptions.Compartment
+		}
+		imageID, err := ociClient.CreateImageWithOptions(
+			fmt.Sprintf("osbuild-upload-%d", i),
+			bucket,
+			namespace,
+			compartment,
+			jobTarget.ImageName,
+			oci.ImageOptions{
+				LaunchMode: targetOptions.LaunchMode,
+				Shapes:     targetOptions.Shapes,
+			},
+		)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+
+		region := targetOptions.Region
+		if region == "" && impl.OCIConfig.ClientParams != nil {
+			region = impl.OCIConfig.ClientParams.Region
+		}
+		logWithId.Info("[OCI] 🎉 Image uploaded and registered!")
+		targetResult.Options = &target.OCITargetResultOptions{
+			Region:  region,
+			ImageID: imageID,
+		}
+	case *target.OCIObjectStorageTargetOptions:
+		targetResult = target.NewOCIObjectStorageTargetResult(nil, &artifact)
+		// create an ociClient uploader with a valid storage client
+		ociClient, err := impl.getOCI(oci.ClientParams{
+			User:        targetOptions.User,
+			Region:      targetOptions.Region,
+			Tenancy:     targetOptions.Tenancy,
+			Fingerprint: targetOptions.Fingerprint,
+			PrivateKey:  targetOptions.PrivateKey,
+		})
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+		logWithId.Info("[OCI] 🔑 Logged in OCI")
+		logWithId.Info("[OCI] ⬆ Uploading the image")
+		file, err := os.Open(path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename))
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+		defer file.Close()
+		i, _ := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+		bucket := impl.OCIConfig.Bucket
+		if targetOptions.Bucket != "" {
+			bucket = targetOptions.Bucket
+		}
+		namespace := impl.OCIConfig.Namespace
+		if targetOptions.Namespace != "" {
+			namespace = targetOptions.Namespace
+		}
+		objectName := fmt.Sprintf("%sosbuild-upload-%d", targetOptions.ObjectNamePrefix, i)
+		err = ociClient.UploadWithOptions(
+			objectName,
+			bucket,
+			namespace,
+			file,
+			oci.UploadOptions{StorageTier: targetOptions.StorageTier},
+		)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+
+		parExpiry := oci.DefaultPARExpiry
+		if targetOptions.PARExpiryHours > 0 {
+			parExpiry = time.Duration(targetOptions.PARExpiryHours) * time.Hour
+		}
+		uri, err := ociClient.PreAuthenticatedRequestWithExpiry(objectName, bucket, namespace, parExpiry)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorGeneratingSignedURL, err.Error(), nil)
+			break
+		}
+		logWithId.Info("[OCI] 🎉 Image uploaded and pre-authenticated request generated!")
+		targetResult.Options = &target.OCIObjectStorageTargetResultOptions{
+			URL:        uri,
+			ObjectPath: oci.ObjectPath(objectName, bucket, namespace),
+		}
+	case *target.ContainerTargetOptions:
+		targetResult = target.NewContainerTargetResult(nil, &artifact)
+		destination := jobTarget.ImageName
+
+		logWithId.Printf("[container] 📦 Preparing upload to '%s'", destination)
+
+		if targetOptions.Sign && impl.ContainersConfig.Signer == nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "signing containers is not configured on this worker", nil)
+			break
+		}
+
+		client, sys, err := impl.getContainerClient(destination, targetOptions)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+		clients := []*container.Client{client}
+		systemContexts := []*types.SystemContext{sys}
+		for _, ref := range targetOptions.AdditionalReferences {
+			c, s, err := impl.getContainerClient(ref, targetOptions)
 			if err != nil {
 				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
 				break
 			}
-			logWithId.Info("[OCI] 🔑 Logged in OCI")
-			logWithId.Info("[OCI] ⬆ Uploading the image")
-			file, err := os.Open(path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename))
+			clients = append(clients, c)
+			systemContexts = append(systemContexts, s)
+		}
+		if targetResult.TargetError != nil {
+			break
+		}
+
+		sourcePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+
+		// TODO: get the container type from the metadata of the osbuild job
+		sourceRef := fmt.Sprintf("oci-archive:%s", sourcePath)
+
+		resultOptions := &target.ContainerTargetResultOptions{URL: client.Target.String()}
+		var manifestDigest digest.Digest
+		for _, c := range clients {
+			logWithId.Printf("[container] ⬆ Uploading the image to %s", c.Target.String())
+			manifestDigest, err = c.UploadImage(context.Background(), sourceRef, "")
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+				logWithId.Infof("[container] 🙁 Upload of '%s' to '%s' failed: %v", sourceRef, c.Target.String(), err)
+				targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
 				break
 			}
-			defer file.Close()
-			i, _ := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
-			bucket := impl.OCIConfig.Bucket
-			if targetOptions.Bucket != "" {
-				bucket = targetOptions.Bucket
-			}
-			namespace := impl.OCIConfig.Namespace
-			if targetOptions.Namespace != "" {
-				namespace = targetOptions.Namespace
+			logWithId.Printf("[container] 🎉 Image uploaded (%s)!", manifestDigest.String())
+			resultOptions.References = append(resultOptions.References, c.Target.String())
+		}
+		if targetResult.TargetError != nil {
+			break
+		}
+		resultOptions.Digest = manifestDigest.String()
+		targetResult.Options = resultOptions
+
+		if targetOptions.Sign {
+			signer := impl.ContainersConfig.Signer
+			// signatures are stored in the repository of the image, tags
+			// of the same repository share them
+			signed := map[string]bool{}
+			for i, c := range clients {
+				repository := reference.TrimNamed(c.Target).String()
+				if signed[repository] {
+					continue
+				}
+				err = signer.Sign(context.Background(), systemContexts[i], c.Target, manifestDigest)
+				if err != nil {
+					logWithId.Infof("[container] 🙁 Signing of '%s' failed: %v", c.Target.String(), err)
+					targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorSigningArtifact, "Error signing the container", err.Error())
+					break
+				}
+				signed[repository] = true
 			}
-			err = ociClient.Upload(
-				fmt.Sprintf("osbuild-upload-%d", i),
-				bucket,
-				namespace,
-				file,
-			)
-			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+			if targetResult.TargetError != nil {
 				break
 			}
+			resultOptions.Signed = true
 
-			compartment := impl.OCIConfig.Compartment
-			if targetOptions.Compartment != "" {
-				compartment = targetOptions.Compartment
+			var rpms []rpmmd.RPM
+			for _, plName := range osbuildJobResult.PipelineNames.Payload {
+				rpms = append(rpms, osbuild.OSBuildMetadataToRPMs(osbuildJobResult.OSBuildOutput.Metadata[plName])...)
 			}
-			imageID, err := ociClient.CreateImage(
-				fmt.Sprintf("osbuild-upload-%d", i),
-				bucket,
-				namespace,
-				compartment,
-				jobTarget.ImageName,
-			)
+			sbom, err := spdxSBOM(client.Target.String(), rpms, time.Now())
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorSigningArtifact, "Error creating the SBOM of the container", err.Error())
 				break
 			}
-
-			logWithId.Info("[OCI] 🎉 Image uploaded and registered!")
-			targetResult.Options = &target.OCITargetResultOptions{ImageID: imageID}
-		case *target.OCIObjectStorageTargetOptions:
-			targetResult = target.NewOCIObjectStorageTargetResult(nil, &artifact)
-			// create an ociClient uploader with a valid storage client
-			ociClient, err := impl.getOCI(oci.ClientParams{
-				User:        targetOptions.User,
-				Region:      targetOptions.Region,
-				Tenancy:     targetOptions.Tenancy,
-				Fingerprint: targetOptions.Fingerprint,
-				PrivateKey:  targetOptions.PrivateKey,
-			})
+			resultOptions.SBOM, err = signer.AttachSBOM(context.Background(), sys, client.Target, manifestDigest, sbom)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+				logWithId.Infof("[container] 🙁 Attaching the SBOM to '%s' failed: %v", client.Target.String(), err)
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorSigningArtifact, "Error attaching the SBOM to the container", err.Error())
 				break
 			}
-			logWithId.Info("[OCI] 🔑 Logged in OCI")
-			logWithId.Info("[OCI] ⬆ Uploading the image")
-			file, err := os.Open(path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename))
+			logWithId.Printf("[container] 🔏 Image signed, SBOM attached (%s)", resultOptions.SBOM)
+		}
+
+	case *target.ORASTargetOptions:
+		targetResult = target.NewORASTargetResult(nil, &artifact)
+		client, sys, err := impl.getContainerClient(jobTarget.ImageName, &target.ContainerTargetOptions{
+			Username:  targetOptions.Username,
+			Password:  targetOptions.Password,
+			TlsVerify: targetOptions.TlsVerify,
+		})
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+
+		imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		logWithId.Printf("[oras] ⬆ Pushing the image to %s", client.Target.String())
+		manifestDigest, err := pushORASArtifact(context.Background(), sys, client.Target, imagePath, targetOptions.ArtifactType, targetOptions.MediaType, targetOptions.Annotations)
+		if err != nil {
+			logWithId.Infof("[oras] 🙁 Push of '%s' failed: %v", imagePath, err)
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		logWithId.Printf("[oras] 🎉 Image pushed (%s)!", manifestDigest.String())
+		targetResult.Options = &target.ORASTargetResultOptions{
+			URL:    client.Target.String(),
+			Digest: manifestDigest.String(),
+		}
+
+	case *target.PulpOSTreeTargetOptions:
+		targetResult = target.NewPulpOSTreeTargetResult(nil, &artifact)
+		archivePath := filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+
+		client, err := impl.getPulpClient(targetOptions.ServerAddress, targetOptions.Username, targetOptions.Password)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+
+		url, err := client.UploadAndDistributeCommit(archivePath, targetOptions.Repository, targetOptions.BasePath)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		targetResult.Options = &target.PulpOSTreeTargetResultOptions{RepoURL: url}
+
+	case *target.PulpFileTargetOptions:
+		targetResult = target.NewPulpFileTargetResult(nil, &artifact)
+		imagePath := filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+
+		client, err := impl.getPulpClient(targetOptions.ServerAddress, targetOptions.Username, targetOptions.Password)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+
+		fileURL, err := client.UploadAndDistributeFile(imagePath, jobTarget.ImageName, targetOptions.Repository, targetOptions.BasePath)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		targetResult.Options = &target.PulpFileTargetResultOptions{FileURL: fileURL}
+
+	case *target.MockTargetOptions:
+		targetResult = target.NewMockTargetResult(nil, &artifact)
+		if impl.MockTargetConfig == nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "mock target is not enabled on this worker", nil)
+			break
+		}
+
+		// the image has to be built even though it isn't uploaded
+		imagePath := filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		if _, err := os.Stat(imagePath); err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+			break
+		}
+
+		latency := impl.MockTargetConfig.Latency
+		if targetOptions.Latency != nil {
+			latency = time.Duration(*targetOptions.Latency) * time.Second
+		}
+		failureRate := impl.MockTargetConfig.FailureRate
+		if targetOptions.FailureRate != nil {
+			failureRate = *targetOptions.FailureRate
+		}
+
+		logWithId.Infof("[Mock] 🎭 Simulating the upload of %s for %v", jobTarget.ImageName, latency)
+		time.Sleep(latency)
+		if mockUploadFails(failureRate) {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, "simulated upload failure", nil)
+			break
+		}
+		targetResult.Options = &target.MockTargetResultOptions{URL: "mock://" + jobTarget.ImageName}
+
+	case *target.HetznerTargetOptions:
+		targetResult = target.NewHetznerTargetResult(nil, &artifact)
+		if impl.HetznerCreds == "" {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, "No Hetzner Cloud credentials configured on the worker", nil)
+			break
+		}
+		h, err := hcloud.NewFromFile(impl.HetznerCreds)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+
+		tempDirectory, err := os.MkdirTemp(impl.Output, job.Id().String()+"-hetzner-*")
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+		defer func() {
+			err := os.RemoveAll(tempDirectory)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
-				break
-			}
-			defer file.Close()
-			i, _ := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
-			bucket := impl.OCIConfig.Bucket
-			if targetOptions.Bucket != "" {
-				bucket = targetOptions.Bucket
-			}
-			namespace := impl.OCIConfig.Namespace
-			if targetOptions.Namespace != "" {
-				namespace = targetOptions.Namespace
+				logWithId.Errorf("Error removing temporary directory for the raw image (%s): %v", tempDirectory, err)
 			}
-			err = ociClient.Upload(
-				fmt.Sprintf("osbuild-upload-%d", i),
-				bucket,
-				namespace,
-				file,
-			)
+		}()
+
+		// the image is written to the disk of a server as is
+		imagePath, err := rawImage(filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename), tempDirectory)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, fmt.Sprintf("Error converting the image to a raw disk image: %v", err), nil)
+			break
+		}
+
+		logWithId.Infof("[Hetzner] 🚀 Importing the image as snapshot '%s'", jobTarget.ImageName)
+		imageID, err := h.ImportImage(context.Background(), imagePath, hcloud.ImportOptions{
+			Location:    targetOptions.Location,
+			Datacenter:  targetOptions.Datacenter,
+			ServerType:  targetOptions.ServerType,
+			Description: jobTarget.ImageName,
+			Labels:      targetOptions.Labels,
+		})
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, err.Error(), err)
+			break
+		}
+		targetResult.Options = &target.HetznerTargetResultOptions{ImageID: imageID}
+
+	case *target.LibvirtTargetOptions:
+		targetResult = target.NewLibvirtTargetResult(nil, &artifact)
+		if impl.LibvirtConfig == nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "libvirt target is not enabled on this worker", nil)
+			break
+		}
+		uri := targetOptions.URI
+		if uri == "" {
+			uri = impl.LibvirtConfig.URIs[0]
+		}
+		allowed := false
+		for _, u := range impl.LibvirtConfig.URIs {
+			allowed = allowed || u == uri
+		}
+		if !allowed {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("libvirt host %s is not allowed on this worker", uri), nil)
+			break
+		}
+
+		imagePath := filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		if strings.HasSuffix(imagePath, ".xz") {
+			imagePath, err = extractXzArchive(imagePath)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, fmt.Sprintf("Error extracting the image: %v", err), nil)
 				break
 			}
+		}
+		format := "raw"
+		if strings.HasSuffix(imagePath, ".qcow2") {
+			format = "qcow2"
+		}
 
-			uri, err := ociClient.PreAuthenticatedRequest(fmt.Sprintf("osbuild-upload-%d", i), bucket, namespace)
+		client := libvirt.NewClient(uri)
+		volumePath, err := client.ImportVolume(context.Background(), targetOptions.Pool, jobTarget.ImageName, imagePath, format)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, err.Error(), err)
+			break
+		}
+		resultOptions := &target.LibvirtTargetResultOptions{VolumePath: volumePath}
+		targetResult.Options = resultOptions
+
+		if targetOptions.DomainName != "" {
+			err = client.CreateDomain(context.Background(), libvirt.DomainOptions{
+				Name:       targetOptions.DomainName,
+				MemoryMiB:  targetOptions.MemoryMiB,
+				VCPUs:      targetOptions.VCPUs,
+				Persistent: targetOptions.Persistent,
+				Template:   impl.LibvirtConfig.DomainTemplate,
+			}, targetOptions.Arch, volumePath, format)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorGeneratingSignedURL, err.Error(), nil)
+				targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, err.Error(), err)
 				break
 			}
-			logWithId.Info("[OCI] 🎉 Image uploaded and pre-authenticated request generated!")
-			targetResult.Options = &target.OCIObjectStorageTargetResultOptions{URL: uri}
-		case *target.ContainerTargetOptions:
-			targetResult = target.NewContainerTargetResult(nil, &artifact)
-			destination := jobTarget.ImageName
+			resultOptions.DomainName = targetOptions.DomainName
+		}
 
-			logWithId.Printf("[container] 📦 Preparing upload to '%s'", destination)
+	case *target.RBDTargetOptions:
+		targetResult = target.NewRBDTargetResult(nil, &artifact)
+		if impl.CephConfig == nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, "No Ceph cluster configured on the worker", nil)
+			break
+		}
 
-			client, err := impl.getContainerClient(destination, targetOptions)
+		tempDirectory, err := os.MkdirTemp(impl.Output, job.Id().String()+"-rbd-*")
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+		defer func() {
+			err := os.RemoveAll(tempDirectory)
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
-				break
+				logWithId.Errorf("Error removing temporary directory for the raw image (%s): %v", tempDirectory, err)
 			}
+		}()
 
-			logWithId.Printf("[container] ⬆ Uploading the image to %s", client.Target.String())
+		// RBD images are block devices, they're imported as is
+		imagePath, err := rawImage(filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename), tempDirectory)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, fmt.Sprintf("Error converting the image to a raw disk image: %v", err), nil)
+			break
+		}
 
-			sourcePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		client := rbd.NewClient(impl.CephConfig.ConfigPath, impl.CephConfig.Keyring, impl.CephConfig.User)
+		spec, err := client.ImportImage(context.Background(), imagePath, targetOptions.Pool, targetOptions.Namespace, jobTarget.ImageName)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorImportingImage, err.Error(), err)
+			break
+		}
+		targetResult.Options = &target.RBDTargetResultOptions{ImageSpec: spec}
 
-			// TODO: get the container type from the metadata of the osbuild job
-			sourceRef := fmt.Sprintf("oci-archive:%s", sourcePath)
+	case *target.HTTPTargetOptions:
+		targetResult = target.NewHTTPTargetResult(nil, &artifact)
+		imagePath := filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
 
-			digest, err := client.UploadImage(context.Background(), sourceRef, "")
+		logWithId.Infof("[HTTP] 🚀 Uploading image to: %s", targetOptions.URL)
+		uploadURL, err := httpupload.Upload(context.Background(), &http.Client{}, httpupload.Destination{
+			URL:      targetOptions.URL,
+			Headers:  targetOptions.Headers,
+			Username: targetOptions.Username,
+			Password: targetOptions.Password,
+			WebDAV:   targetOptions.WebDAV,
+		}, imagePath, jobTarget.ImageName)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		logWithId.Infof("[HTTP] 🎉 Image uploaded to: %s", uploadURL)
+		targetResult.Options = &target.HTTPTargetResultOptions{URL: uploadURL}
 
+	case *target.BareMetalTargetOptions:
+		targetResult = target.NewBareMetalTargetResult(nil, &artifact)
+		tempDirectory, err := os.MkdirTemp(impl.Output, job.Id().String()+"-baremetal-*")
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+			break
+		}
+		defer func() {
+			err := os.RemoveAll(tempDirectory)
 			if err != nil {
-				logWithId.Infof("[container] 🙁 Upload of '%s' failed: %v", sourceRef, err)
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
-				break
+				logWithId.Errorf("Error removing temporary directory for the raw image (%s): %v", tempDirectory, err)
 			}
-			logWithId.Printf("[container] 🎉 Image uploaded (%s)!", digest.String())
-			targetResult.Options = &target.ContainerTargetResultOptions{URL: client.Target.String(), Digest: digest.String()}
+		}()
+
+		// Ironic and Equinix Metal write the raw image to the disk as is
+		imagePath, err := rawImage(filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename), tempDirectory)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, fmt.Sprintf("Error converting the image to a raw disk image: %v", err), nil)
+			break
+		}
+		imageChecksum, err := common.FileSHA256(imagePath)
+		if err != nil {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, fmt.Sprintf("Error computing the checksum of the raw disk image: %v", err), nil)
+			break
+		}
 
-		case *target.PulpOSTreeTargetOptions:
-			targetResult = target.NewPulpOSTreeTargetResult(nil, &artifact)
-			archivePath := filepath.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		// the artifacts of each image are kept in a directory of their own
+		dest := httpupload.Destination{
+			URL:      strings.TrimSuffix(targetOptions.URL, "/") + "/" + jobTarget.ImageName,
+			Headers:  targetOptions.Headers,
+			Username: targetOptions.Username,
+			Password: targetOptions.Password,
+			WebDAV:   targetOptions.WebDAV,
+		}
+		logWithId.Infof("[BareMetal] 🚀 Uploading image to: %s", dest.URL)
+		imageURL, err := httpupload.Upload(context.Background(), &http.Client{}, dest, imagePath, "disk.raw")
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		resultOptions := &target.BareMetalTargetResultOptions{
+			ImageURL:      imageURL,
+			ImageChecksum: strings.TrimPrefix(imageChecksum, "sha256:"),
+		}
 
-			client, err := impl.getPulpClient(targetOptions)
+		if targetOptions.NetbootExport != "" {
+			kernelPath, initrdPath, err := netbootFiles(filepath.Join(outputDirectory, targetOptions.NetbootExport))
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, fmt.Sprintf("Error finding the kernel and initrd of the image: %v", err), nil)
 				break
 			}
-
-			url, err := client.UploadAndDistributeCommit(archivePath, targetOptions.Repository, targetOptions.BasePath)
+			resultOptions.KernelURL, err = httpupload.Upload(context.Background(), &http.Client{}, dest, kernelPath, "vmlinuz")
 			if err != nil {
-				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error(), nil)
+				targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+				break
+			}
+			resultOptions.InitrdURL, err = httpupload.Upload(context.Background(), &http.Client{}, dest, initrdPath, "initrd.img")
+			if err != nil {
+				targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+				break
+			}
+			scriptPath := filepath.Join(tempDirectory, "boot.ipxe")
+			err = os.WriteFile(scriptPath, ipxeScript(resultOptions.KernelURL, resultOptions.InitrdURL, targetOptions.KernelArgs), 0600)
+			if err != nil {
+				targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, fmt.Sprintf("Error writing the iPXE script: %v", err), nil)
+				break
+			}
+			resultOptions.IPXEScriptURL, err = httpupload.Upload(context.Background(), &http.Client{}, dest, scriptPath, "boot.ipxe")
+			if err != nil {
+				targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
 				break
 			}
-			targetResult.Options = &target.PulpOSTreeTargetResultOptions{RepoURL: url}
-
-		default:
-			// TODO: we may not want to return completely here with multiple targets, because then no TargetErrors will be added to the JobError details
-			// Nevertheless, all target errors will be still in the OSBuildJobResult.
-			osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTarget, fmt.Sprintf("invalid target type: %s", jobTarget.Name), nil)
-			return nil
 		}
-
-		// this is a programming error
-		if targetResult == nil {
-			panic("target results object not created by the target handling code")
+		logWithId.Infof("[BareMetal] 🎉 Image published to: %s", dest.URL)
+		targetResult.Options = resultOptions
+
+	case *target.VagrantCloudTargetOptions:
+		targetResult = target.NewVagrantCloudTargetResult(nil, &artifact)
+		provider := jobTarget.OsbuildArtifact.VagrantProvider
+		if provider == "" {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "the artifact isn't a vagrant box", nil)
+			break
+		}
+		boxPath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+		cloud := vagrant.Cloud{
+			URL:   targetOptions.URL,
+			Token: targetOptions.Token,
 		}
-		osbuildJobResult.TargetResults = append(osbuildJobResult.TargetResults, targetResult)
+		box := vagrant.Box{
+			Name:    targetOptions.Box,
+			Version: targetOptions.Version,
+			SHA256:  strings.TrimPrefix(checksum, "sha256:"),
+		}
+		logWithId.Infof("[vagrant] 🚀 Uploading the %s box to %s version %s", provider, box.Name, box.Version)
+		err = cloud.Upload(context.Background(), &http.Client{}, box, provider, boxPath, targetOptions.Release)
+		if err != nil {
+			targetResult.TargetError = targetError(clienterrors.ErrorUploadingImage, err.Error(), err)
+			break
+		}
+		logWithId.Infof("[vagrant] 🎉 Box uploaded to %s version %s", box.Name, box.Version)
+		targetResult.Options = &target.VagrantCloudTargetResultOptions{
+			Box:      box.Name,
+			Version:  box.Version,
+			Provider: provider,
+			Released: targetOptions.Release,
+		}
+
+	default:
+		return nil, clienterrors.WorkerClientError(clienterrors.ErrorInvalidTarget, fmt.Sprintf("invalid target type: %s", jobTarget.Name), nil)
 	}
 
-	targetErrors := osbuildJobResult.TargetErrors()
-	if len(targetErrors) != 0 {
-		osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorTargetError, "at least one target failed", targetErrors)
-	} else {
-		osbuildJobResult.Success = true
-		osbuildJobResult.UploadStatus = "success"
+	// this is a programming error
+	if targetResult == nil {
+		panic("target results object not created by the target handling code")
 	}
+	targetResult.ArtifactChecksum = checksum
+	return targetResult, nil
+}
 
-	return nil
+// mockUploadFails returns true with the given probability.
+func mockUploadFails(failureRate float64) bool {
+	if failureRate <= 0 {
+		return false
+	}
+	const precision = 1000000
+	i, err := rand.Int(rand.Reader, big.NewInt(precision))
+	if err != nil {
+		return true
+	}
+	return float64(i.Int64()) < failureRate*precision
+}
+
+// rawImage returns the path of the image as a raw disk image. Compressed
+// images are extracted next to the archive, qcow2 images are converted to
+// tmpdir.
+func rawImage(imagePath, tmpdir string) (string, error) {
+	var err error
+	if strings.HasSuffix(imagePath, ".xz") {
+		imagePath, err = extractXzArchive(imagePath)
+		if err != nil {
+			return "", err
+		}
+	}
+	if !strings.HasSuffix(imagePath, ".qcow2") {
+		return imagePath, nil
+	}
+
+	rawPath := filepath.Join(tmpdir, strings.TrimSuffix(filepath.Base(imagePath), ".qcow2")+".raw")
+	out, err := exec.Command("qemu-img", "convert", "-f", "qcow2", "-O", "raw", imagePath, rawPath).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("qemu-img convert failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return rawPath, nil
 }
 
+// extractXzArchiveMu serializes the extraction of archives, because multiple
+// targets of a job may extract the same archive concurrently.
+var extractXzArchiveMu sync.Mutex
+
 // extractXzArchive extracts the provided XZ archive in the same directory
-// and returns the path to decompressed file.
+// and returns the path to decompressed file. The archive is kept, because
+// other targets of the job may upload it, and it's extracted only once.
 func extractXzArchive(archivePath string) (string, error) {
+	extractXzArchiveMu.Lock()
+	defer extractXzArchiveMu.Unlock()
+
 	workingDir, archiveFilename := path.Split(archivePath)
 	decompressedFilename := strings.TrimSuffix(archiveFilename, ".xz")
+	decompressedPath := path.Join(workingDir, decompressedFilename)
+
+	if _, err := os.Stat(decompressedPath); err == nil {
+		return decompressedPath, nil
+	}
 
-	cmd := exec.Command("xz", "-d", archivePath)
+	cmd := exec.Command("xz", "-d", "-k", archivePath)
 	cmd.Dir = workingDir
 	err := cmd.Run()
 	if err != nil {
 		return "", err
 	}
 
-	return path.Join(workingDir, decompressedFilename), nil
+	return decompressedPath, nil
 }
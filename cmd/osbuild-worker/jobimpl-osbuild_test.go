@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+func TestGetAWSForS3TargetGenericS3(t *testing.T) {
+	impl := &OSBuildJobImpl{
+		Output:   t.TempDir(),
+		S3Config: S3Configuration{Bucket: "configured"},
+	}
+	options := &target.AWSS3TargetOptions{
+		Region:             "us-east-1",
+		Endpoint:           "https://minio.example.com:9000",
+		AccessKeyID:        "key-id",
+		SecretAccessKey:    "secret",
+		VirtualHostedStyle: true,
+	}
+
+	a, bucket, err := impl.getAWSForS3Target(options)
+	require.NoError(t, err)
+	require.NotNil(t, a)
+	require.Equal(t, "configured", bucket)
+
+	options.Bucket = "requested"
+	_, bucket, err = impl.getAWSForS3Target(options)
+	require.NoError(t, err)
+	require.Equal(t, "requested", bucket)
+
+	impl.S3Config.Bucket = ""
+	options.Bucket = ""
+	_, _, err = impl.getAWSForS3Target(options)
+	require.EqualError(t, err, "No S3 bucket provided")
+
+	// the bundle is only written for creating the session
+	options.Bucket = "requested"
+	options.CABundleData = "not a certificate"
+	_, _, err = impl.getAWSForS3Target(options)
+	require.Error(t, err)
+	entries, err := os.ReadDir(impl.Output)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestGetAWSForS3TargetWorkerCredentials(t *testing.T) {
+	creds := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(creds, []byte("[default]\naws_access_key_id = key-id\naws_secret_access_key = secret\n"), 0600))
+	impl := &OSBuildJobImpl{
+		Output:   t.TempDir(),
+		S3Config: S3Configuration{Creds: creds},
+	}
+	options := &target.AWSS3TargetOptions{
+		Region:   "us-east-1",
+		Endpoint: "https://minio.example.com:9000",
+		Bucket:   "images",
+	}
+
+	a, _, err := impl.getAWSForS3Target(options)
+	require.NoError(t, err)
+	require.NotNil(t, a)
+
+	// the credentials of the worker are only sent to verified endpoints
+	options.SkipSSLVerification = true
+	_, _, err = impl.getAWSForS3Target(options)
+	require.EqualError(t, err, "skipping the SSL verification isn't allowed with the credentials of the worker")
+}
+
+func newTestTargets(n int) []*target.Target {
+	var targets []*target.Target
+	for i := 0; i < n; i++ {
+		targets = append(targets, &target.Target{
+			Name:      target.TargetNameAWS,
+			ImageName: fmt.Sprintf("image-%d", i),
+		})
+	}
+	return targets
+}
+
+func TestHandleTargetsOrder(t *testing.T) {
+	targets := newTestTargets(6)
+	// the first targets finish last
+	delays := make(map[*target.Target]time.Duration)
+	for i, jobTarget := range targets {
+		delays[jobTarget] = time.Duration(len(targets)-i) * 10 * time.Millisecond
+	}
+	var running, maxRunning int32
+	var result worker.OSBuildJobResult
+	handleTargets(targets, 3, &result, func(jobTarget *target.Target) (*target.TargetResult, *clienterrors.Error, bool) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(delays[jobTarget])
+		return target.NewAWSTargetResult(&target.AWSTargetResultOptions{Ami: jobTarget.ImageName}, nil), nil, false
+	})
+
+	require.True(t, result.Success)
+	require.Nil(t, result.JobError)
+	require.LessOrEqual(t, maxRunning, int32(3))
+	require.Len(t, result.TargetResults, len(targets))
+	for i, targetResult := range result.TargetResults {
+		require.Equal(t, fmt.Sprintf("image-%d", i), targetResult.Options.(*target.AWSTargetResultOptions).Ami)
+	}
+}
+
+func TestHandleTargetsFailure(t *testing.T) {
+	targets := newTestTargets(3)
+	var result worker.OSBuildJobResult
+	handleTargets(targets, 3, &result, func(jobTarget *target.Target) (*target.TargetResult, *clienterrors.Error, bool) {
+		targetResult := target.NewAWSTargetResult(&target.AWSTargetResultOptions{Ami: jobTarget.ImageName}, nil)
+		if jobTarget.ImageName == "image-1" {
+			targetResult.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorImportingImage, "import failed", nil)
+		}
+		return targetResult, nil, false
+	})
+
+	require.False(t, result.Success)
+	require.Equal(t, clienterrors.ErrorTargetError, result.JobError.ID)
+	require.Equal(t, []*clienterrors.Error{
+		clienterrors.WorkerClientError(clienterrors.ErrorImportingImage, "import failed", target.TargetNameAWS),
+	}, result.TargetErrors())
+	// the other targets are reported as well
+	require.Len(t, result.TargetResults, 3)
+	require.Nil(t, result.TargetResults[0].TargetError)
+	require.NotNil(t, result.TargetResults[1].TargetError)
+	require.Nil(t, result.TargetResults[2].TargetError)
+}
+
+func TestHandleTargetsJobError(t *testing.T) {
+	targets := newTestTargets(3)
+	var result worker.OSBuildJobResult
+	handleTargets(targets, 1, &result, func(jobTarget *target.Target) (*target.TargetResult, *clienterrors.Error, bool) {
+		if jobTarget.ImageName == "image-1" {
+			return nil, clienterrors.WorkerClientError(clienterrors.ErrorInvalidTarget, "invalid target", nil), false
+		}
+		return target.NewAWSTargetResult(&target.AWSTargetResultOptions{Ami: jobTarget.ImageName}, nil), nil, false
+	})
+
+	require.False(t, result.Success)
+	require.Equal(t, clienterrors.ErrorInvalidTarget, result.JobError.ID)
+	require.Len(t, result.TargetResults, 1)
+}
+
+func TestHandleTargetsSkipped(t *testing.T) {
+	targets := newTestTargets(3)
+	var result worker.OSBuildJobResult
+	handleTargets(targets, 2, &result, func(jobTarget *target.Target) (*target.TargetResult, *clienterrors.Error, bool) {
+		if jobTarget.ImageName == "image-2" {
+			return nil, nil, true
+		}
+		return target.NewAWSTargetResult(&target.AWSTargetResultOptions{Ami: jobTarget.ImageName}, nil), nil, false
+	})
+
+	require.False(t, result.Success)
+	require.Equal(t, clienterrors.ErrorDeadlineExceeded, result.JobError.ID)
+	require.Equal(t, []target.TargetName{target.TargetNameAWS}, result.JobError.Details)
+	require.Len(t, result.TargetResults, 2)
+}
+
+func TestHandleTargetsPanic(t *testing.T) {
+	targets := newTestTargets(3)
+	var result worker.OSBuildJobResult
+	require.PanicsWithValue(t, "boom", func() {
+		handleTargets(targets, 3, &result, func(jobTarget *target.Target) (*target.TargetResult, *clienterrors.Error, bool) {
+			if jobTarget.ImageName == "image-1" {
+				panic("boom")
+			}
+			return target.NewAWSTargetResult(&target.AWSTargetResultOptions{}, nil), nil, false
+		})
+	})
+}
+
+// testJob records the artifacts uploaded to composer
+type testJob struct {
+	worker.Job
+	id uuid.UUID
+
+	mu        sync.Mutex
+	checksums map[string]string
+}
+
+func (j *testJob) Id() uuid.UUID {
+	return j.id
+}
+
+func (j *testJob) UploadArtifact(name string, reader io.Reader, checksum string) error {
+	_, err := io.Copy(io.Discard, reader)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.checksums[name] = checksum
+	return nil
+}
+
+func TestHandleTargetsConcurrently(t *testing.T) {
+	outputDirectory := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(outputDirectory, "image"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDirectory, "image", "disk.img"), []byte("image"), 0600))
+
+	failureRate := 1.0
+	var targets []*target.Target
+	for i := 0; i < 4; i++ {
+		targetOptions := &target.MockTargetOptions{}
+		if i == 2 {
+			targetOptions.FailureRate = &failureRate
+		}
+		targets = append(targets, target.NewMockTarget(targetOptions), target.NewWorkerServerTarget())
+	}
+	for i, jobTarget := range targets {
+		jobTarget.ImageName = fmt.Sprintf("image-%d", i)
+		jobTarget.OsbuildArtifact = target.OsbuildArtifact{
+			ExportName:     "image",
+			ExportFilename: "disk.img",
+		}
+	}
+
+	impl := &OSBuildJobImpl{
+		Output:           t.TempDir(),
+		MockTargetConfig: &MockTargetConfiguration{Latency: 10 * time.Millisecond},
+	}
+	job := &testJob{id: uuid.New(), checksums: make(map[string]string)}
+	jobArgs := worker.OSBuildJob{Targets: targets}
+	result := worker.OSBuildJobResult{
+		ArtifactChecksums: map[string]string{"image/disk.img": "sha256:6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d"},
+	}
+	handleTargets(targets, 4, &result, func(jobTarget *target.Target) (*target.TargetResult, *clienterrors.Error, bool) {
+		targetResult, jobError := impl.handleTarget(job, &jobArgs, jobTarget, outputDirectory, &result, &worker.ManifestInfo{})
+		return targetResult, jobError, false
+	})
+
+	require.Equal(t, clienterrors.ErrorTargetError, result.JobError.ID)
+	require.Len(t, result.TargetResults, len(targets))
+	for i, targetResult := range result.TargetResults {
+		require.Equal(t, targets[i].Name, targetResult.Name)
+		if i == 4 {
+			require.Equal(t, clienterrors.ErrorUploadingImage, targetResult.TargetError.ID)
+		} else {
+			require.Nil(t, targetResult.TargetError)
+		}
+	}
+	require.Len(t, job.checksums, 4)
+	for _, checksum := range job.checksums {
+		require.Equal(t, result.ArtifactChecksums["image/disk.img"], checksum)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/upload/ostree"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// OSTreeMirrorJobImpl pushes the commits built by osbuild jobs to remote
+// ostree repositories, pulling them from the S3 targets they were uploaded to.
+type OSTreeMirrorJobImpl struct {
+	Client *http.Client
+}
+
+// pullCommit downloads the commit archive and extracts it to dir.
+func (impl *OSTreeMirrorJobImpl) pullCommit(url, dir string) (*ostree.Commit, error) {
+	resp, err := impl.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading the commit archive failed: %s", resp.Status)
+	}
+	return ostree.ExtractCommit(resp.Body, dir)
+}
+
+func (impl *OSTreeMirrorJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id())
+	result := worker.OSTreeMirrorJobResult{}
+
+	defer func() {
+		err := job.Update(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	var args worker.OSTreeMirrorJob
+	err := job.Args(&args)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingJobArgs, fmt.Sprintf("Error parsing arguments: %v", err), nil)
+		return err
+	}
+
+	if job.NDynamicArgs() != 1 {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorNoDynamicArgs, "An ostree mirror job should depend on an osbuild job", nil)
+		return nil
+	}
+	var osbuildResult worker.OSBuildJobResult
+	err = job.DynamicArgs(0, &osbuildResult)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingDynamicArgs, "Error parsing dynamic args as osbuild job", nil)
+		return err
+	}
+	if osbuildResult.JobError != nil || !osbuildResult.Success {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, "The build failed, there is no commit to mirror", nil)
+		return nil
+	}
+	if args.Target == nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, "No target the commit was uploaded to", nil)
+		return nil
+	}
+	url, _, ok := uploadedArtifact(args.Target, osbuildResult.TargetResults)
+	if !ok {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, "The commit archive wasn't uploaded", nil)
+		return nil
+	}
+
+	tmpdir, err := os.MkdirTemp("", "ostree-mirror-")
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMirroringOSTree, "Error creating a temporary directory", err.Error())
+		return err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	commit, err := impl.pullCommit(url, tmpdir)
+	if err != nil {
+		logWithId.Errorf("Error pulling the commit: %v", err)
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMirroringOSTree, "Error pulling the commit", err.Error())
+		return nil
+	}
+	result.Ref = commit.Ref
+	result.Checksum = commit.Checksum
+
+	logWithId.Infof("[ostree] Pushing commit %s (%s) to %s", commit.Checksum, commit.Ref, args.URL)
+	err = commit.Push(context.Background(), impl.Client, ostree.Remote{
+		URL:      args.URL,
+		Username: args.Username,
+		Password: args.Password,
+	})
+	if err != nil {
+		logWithId.Errorf("Error pushing the commit: %v", err)
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMirroringOSTree, fmt.Sprintf("Error pushing the commit to %s", args.URL), err.Error())
+		return nil
+	}
+	result.URL = args.URL
+	logWithId.Infof("[ostree] Commit %s pushed", commit.Checksum)
+	return nil
+}
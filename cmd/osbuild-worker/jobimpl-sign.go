@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	//nolint:staticcheck // deprecated, but the only OpenPGP implementation vendored
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// SignJobImpl signs the artifacts uploaded to S3 by an osbuild job with the
+// key of the worker. The detached signature, a SHA256SUMS file and its
+// signature are uploaded next to each artifact.
+type SignJobImpl struct {
+	Entity *openpgp.Entity
+	// returns the client and the bucket of an S3 target, the same way the
+	// osbuild job does
+	S3     func(options *target.AWSS3TargetOptions) (*awscloud.AWS, string, error)
+	Client *http.Client
+}
+
+// loadSigningKey reads the first private key of an armored key ring,
+// decrypting it with the passphrase in passphrasePath, if set.
+func loadSigningKey(path, passphrasePath string) (*openpgp.Entity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entities, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the signing key: %v", err)
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, fmt.Errorf("no private key found in %s", path)
+	}
+	entity := entities[0]
+
+	if entity.PrivateKey.Encrypted {
+		passphrase, err := readSecretFile(passphrasePath)
+		if err != nil {
+			return nil, err
+		}
+		if passphrase == "" {
+			return nil, fmt.Errorf("the signing key is encrypted, but no passphrase was given")
+		}
+		err = entity.PrivateKey.Decrypt([]byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting the signing key: %v", err)
+		}
+	}
+	return entity, nil
+}
+
+// signArtifact downloads the artifact, returning its detached signature and
+// its SHA256 checksum.
+func (impl *SignJobImpl) signArtifact(url string) ([]byte, string, error) {
+	resp, err := impl.Client.Get(url)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("downloading the artifact failed: %s", resp.Status)
+	}
+
+	var signature bytes.Buffer
+	hash := sha256.New()
+	err = openpgp.ArmoredDetachSign(&signature, impl.Entity, io.TeeReader(resp.Body, hash), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return signature.Bytes(), "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// publish signs the artifact uploaded to the S3 target and uploads the
+// signature files with the same key prefix.
+func (impl *SignJobImpl) publish(t *target.Target, url, checksum, tmpdir string) (*worker.ArtifactSignature, error) {
+	options := t.Options.(*target.AWSS3TargetOptions)
+	filename := t.OsbuildArtifact.ExportFilename
+	artifact := options.Key + "-" + filename
+
+	signature, actualChecksum, err := impl.signArtifact(url)
+	if err != nil {
+		return nil, err
+	}
+	if checksum != "" && checksum != actualChecksum {
+		return nil, fmt.Errorf("checksum mismatch of %s: expected %s, got %s", artifact, checksum, actualChecksum)
+	}
+
+	var sums bytes.Buffer
+	fmt.Fprintf(&sums, "%s  %s\n", actualChecksum[len("sha256:"):], artifact)
+	var sumsSignature bytes.Buffer
+	err = openpgp.ArmoredDetachSign(&sumsSignature, impl.Entity, bytes.NewReader(sums.Bytes()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{
+		filename + ".asc": signature,
+		"SHA256SUMS":      sums.Bytes(),
+		"SHA256SUMS.asc":  sumsSignature.Bytes(),
+	}
+	for name, content := range files {
+		err = os.WriteFile(filepath.Join(tmpdir, name), content, 0600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a, bucket, err := impl.S3(options)
+	if err != nil {
+		return nil, err
+	}
+	urls := make(map[string]string)
+	for name := range files {
+		u, uploadErr := uploadToS3(a, nil, tmpdir, "", bucket, s3ObjectKey(options.Key, name), name, options)
+		if uploadErr != nil {
+			return nil, fmt.Errorf("error uploading %s: %s", name, uploadErr.Reason)
+		}
+		urls[name] = u
+	}
+
+	return &worker.ArtifactSignature{
+		Artifact:              artifact,
+		Checksum:              actualChecksum,
+		SignatureURL:          urls[filename+".asc"],
+		ChecksumsURL:          urls["SHA256SUMS"],
+		ChecksumsSignatureURL: urls["SHA256SUMS.asc"],
+	}, nil
+}
+
+// clearsign returns the text signed with a cleartext signature, see
+// https://www.rfc-editor.org/rfc/rfc4880#section-7. The clearsign package
+// isn't vendored, but the signature is an ordinary detached signature of the
+// text.
+func clearsign(entity *openpgp.Entity, text []byte) ([]byte, error) {
+	// the trailing whitespace of the lines and the last line break aren't
+	// signed
+	lines := strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	var signature bytes.Buffer
+	err := openpgp.ArmoredDetachSignText(&signature, entity, strings.NewReader(strings.Join(lines, "\n")), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA256\n\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "-") {
+			buf.WriteString("- ")
+		}
+		buf.WriteString(line + "\n")
+	}
+	buf.Write(signature.Bytes())
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// publishChecksumFile uploads a clearsigned CHECKSUM file listing all the
+// artifacts of the build with the key prefix of the S3 target, and returns
+// its URL.
+func (impl *SignJobImpl) publishChecksumFile(t *target.Target, checksums map[string]string, tmpdir string) (string, error) {
+	options := t.Options.(*target.AWSS3TargetOptions)
+	if len(checksums) == 0 {
+		return "", fmt.Errorf("the build has no checksums of its artifacts")
+	}
+
+	content, err := clearsign(impl.Entity, checksumFile(checksummedArtifacts(checksums, options.Key+"-")))
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(filepath.Join(tmpdir, "CHECKSUM"), content, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	a, bucket, err := impl.S3(options)
+	if err != nil {
+		return "", err
+	}
+	u, uploadErr := uploadToS3(a, nil, tmpdir, "", bucket, s3ObjectKey(options.Key, "CHECKSUM"), "CHECKSUM", options)
+	if uploadErr != nil {
+		return "", fmt.Errorf("error uploading CHECKSUM: %s", uploadErr.Reason)
+	}
+	return u, nil
+}
+
+// uploadedArtifact returns the URL and the checksum of the artifact the
+// osbuild job uploaded to the target.
+func uploadedArtifact(t *target.Target, results []*target.TargetResult) (string, string, bool) {
+	for _, tr := range results {
+		if tr.Name != t.Name || tr.TargetError != nil || tr.OsbuildArtifact == nil ||
+			tr.OsbuildArtifact.ExportFilename != t.OsbuildArtifact.ExportFilename {
+			continue
+		}
+		options, ok := tr.Options.(*target.AWSS3TargetResultOptions)
+		if !ok {
+			continue
+		}
+		return options.URL, tr.ArtifactChecksum, true
+	}
+	return "", "", false
+}
+
+func (impl *SignJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id())
+	result := worker.SignJobResult{}
+
+	defer func() {
+		err := job.Update(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	var args worker.SignJob
+	err := job.Args(&args)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingJobArgs, fmt.Sprintf("Error parsing arguments: %v", err), nil)
+		return err
+	}
+
+	if job.NDynamicArgs() != 1 {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorNoDynamicArgs, "A sign job should depend on an osbuild job", nil)
+		return nil
+	}
+	var osbuildResult worker.OSBuildJobResult
+	err = job.DynamicArgs(0, &osbuildResult)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingDynamicArgs, "Error parsing dynamic args as osbuild job", nil)
+		return err
+	}
+	if osbuildResult.JobError != nil || !osbuildResult.Success {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, "The build failed, its artifacts aren't signed", nil)
+		return nil
+	}
+
+	tmpdir, err := os.MkdirTemp("", "sign-")
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorSigningArtifact, "Error creating a temporary directory", err.Error())
+		return err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	result.Signatures = []worker.ArtifactSignature{}
+	for _, t := range args.Targets {
+		if _, ok := t.Options.(*target.AWSS3TargetOptions); !ok || t.OsbuildArtifact.ExportFilename == "" {
+			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("Artifacts of %s targets can't be signed", t.Name), nil)
+			return nil
+		}
+		url, checksum, ok := uploadedArtifact(t, osbuildResult.TargetResults)
+		if !ok {
+			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, fmt.Sprintf("The artifact %s wasn't uploaded", t.OsbuildArtifact.ExportFilename), nil)
+			return nil
+		}
+
+		if !args.SkipArtifacts {
+			signature, err := impl.publish(t, url, checksum, tmpdir)
+			if err != nil {
+				logWithId.Errorf("Error signing %s: %v", t.OsbuildArtifact.ExportFilename, err)
+				result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorSigningArtifact, "Error signing the artifact", err.Error())
+				return nil
+			}
+			result.Signatures = append(result.Signatures, *signature)
+		}
+
+		if args.ChecksumFile {
+			checksumFileURL, err := impl.publishChecksumFile(t, osbuildResult.ArtifactChecksums, tmpdir)
+			if err != nil {
+				logWithId.Errorf("Error signing the CHECKSUM file: %v", err)
+				result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorSigningArtifact, "Error signing the CHECKSUM file", err.Error())
+				return nil
+			}
+			result.ChecksumFileURLs = append(result.ChecksumFileURLs, checksumFileURL)
+		}
+	}
+	logWithId.Infof("Signed %d artifacts and %d CHECKSUM files", len(result.Signatures), len(result.ChecksumFileURLs))
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	//nolint:staticcheck // deprecated, but the only OpenPGP implementation vendored
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+func writeSigningKey(t *testing.T) string {
+	entity, err := openpgp.NewEntity("osbuild-worker", "", "worker@example.com", nil)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "signing.asc")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	w, err := armor.Encode(f, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+	return path
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	entity, err := loadSigningKey(writeSigningKey(t), "")
+	require.NoError(t, err)
+	require.NotNil(t, entity.PrivateKey)
+	require.False(t, entity.PrivateKey.Encrypted)
+
+	_, err = loadSigningKey(filepath.Join(t.TempDir(), "missing.asc"), "")
+	require.Error(t, err)
+}
+
+func TestSignArtifact(t *testing.T) {
+	artifact := []byte("disk image")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/image.qcow2" {
+			http.NotFound(w, r)
+			return
+		}
+		_, err := w.Write(artifact)
+		require.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	entity, err := loadSigningKey(writeSigningKey(t), "")
+	require.NoError(t, err)
+	impl := &SignJobImpl{
+		Entity: entity,
+		Client: srv.Client(),
+	}
+
+	signature, checksum, err := impl.signArtifact(srv.URL + "/image.qcow2")
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("sha256:%x", sha256.Sum256(artifact)), checksum)
+	signer, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity}, bytes.NewReader(artifact), bytes.NewReader(signature))
+	require.NoError(t, err)
+	require.Equal(t, entity.PrimaryKey.KeyId, signer.PrimaryKey.KeyId)
+
+	_, _, err = impl.signArtifact(srv.URL + "/missing.qcow2")
+	require.Error(t, err)
+}
+
+func TestClearsign(t *testing.T) {
+	entity, err := loadSigningKey(writeSigningKey(t), "")
+	require.NoError(t, err)
+
+	text := "SHA256 (image.qcow2) = 0123  \n-dashed line\n"
+	signed, err := clearsign(entity, []byte(text))
+	require.NoError(t, err)
+
+	header := "-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA256\n\nSHA256 (image.qcow2) = 0123\n- -dashed line\n"
+	require.True(t, strings.HasPrefix(string(signed), header), string(signed))
+	signature := strings.TrimPrefix(string(signed), header)
+	require.True(t, strings.HasPrefix(signature, "-----BEGIN PGP SIGNATURE-----"), signature)
+
+	// the signed text has CRLF line endings without the last one, and no
+	// trailing whitespace
+	signer, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity}, strings.NewReader("SHA256 (image.qcow2) = 0123\r\n-dashed line"), strings.NewReader(signature))
+	require.NoError(t, err)
+	require.Equal(t, entity.PrimaryKey.KeyId, signer.PrimaryKey.KeyId)
+}
+
+func TestUploadedArtifact(t *testing.T) {
+	s3Target := &target.Target{
+		Name:            target.TargetNameAWSS3,
+		OsbuildArtifact: target.OsbuildArtifact{ExportFilename: "image.qcow2"},
+	}
+	results := []*target.TargetResult{
+		{
+			Name:            target.TargetNameAWSS3,
+			OsbuildArtifact: &target.OsbuildArtifact{ExportFilename: "other.qcow2"},
+			Options:         &target.AWSS3TargetResultOptions{URL: "https://example.com/other.qcow2"},
+		},
+		{
+			Name:             target.TargetNameAWSS3,
+			OsbuildArtifact:  &target.OsbuildArtifact{ExportFilename: "image.qcow2"},
+			Options:          &target.AWSS3TargetResultOptions{URL: "https://example.com/image.qcow2"},
+			ArtifactChecksum: "sha256:0123",
+		},
+	}
+
+	url, checksum, ok := uploadedArtifact(s3Target, results)
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/image.qcow2", url)
+	require.Equal(t, "sha256:0123", checksum)
+
+	_, _, ok = uploadedArtifact(s3Target, results[:1])
+	require.False(t, ok)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/images/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// number of packages looked up in a single request, the maximum of the
+// OSV API
+const osvBatchSize = 1000
+
+// VulnerabilityScanJobImpl scans the depsolved packages of a build with
+// the querybatch endpoint of an OSV database.
+type VulnerabilityScanJobImpl struct {
+	OSVURL string
+	// OSV ecosystems of the packages by the prefix of the distribution name
+	Ecosystems map[string]string
+	Client     *http.Client
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// ecosystem returns the ecosystem of the longest prefix of the distribution
+// name.
+func (impl *VulnerabilityScanJobImpl) ecosystem(distribution string) (string, bool) {
+	var prefix string
+	for p := range impl.Ecosystems {
+		if strings.HasPrefix(distribution, p) && len(p) > len(prefix) {
+			prefix = p
+		}
+	}
+	ecosystem, ok := impl.Ecosystems[prefix]
+	return ecosystem, ok
+}
+
+func packageVersion(pkg rpmmd.PackageSpec) string {
+	if pkg.Epoch != 0 {
+		return fmt.Sprintf("%d:%s-%s", pkg.Epoch, pkg.Version, pkg.Release)
+	}
+	return fmt.Sprintf("%s-%s", pkg.Version, pkg.Release)
+}
+
+// uniquePackages returns the packages of the package sets of the payload
+// pipelines, sorted and without duplicates. The packages of the build root
+// aren't part of the image.
+func uniquePackages(packageSets map[string][]rpmmd.PackageSpec, payloadPipelines []string) []rpmmd.PackageSpec {
+	seen := make(map[string]bool)
+	var packages []rpmmd.PackageSpec
+	for _, name := range payloadPipelines {
+		for _, pkg := range packageSets[name] {
+			nevra := pkg.GetNEVRA()
+			if seen[nevra] {
+				continue
+			}
+			seen[nevra] = true
+			packages = append(packages, pkg)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].GetNEVRA() < packages[j].GetNEVRA()
+	})
+	return packages
+}
+
+func (impl *VulnerabilityScanJobImpl) queryBatch(queries []osvQuery) (*osvBatchResponse, error) {
+	body, err := json.Marshal(map[string][]osvQuery{"queries": queries})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := impl.Client.Post(strings.TrimSuffix(impl.OSVURL, "/")+"/v1/querybatch", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV query failed: %s", resp.Status)
+	}
+
+	var result osvBatchResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding OSV response: %v", err)
+	}
+	return &result, nil
+}
+
+// scan returns the known vulnerabilities of the packages.
+func (impl *VulnerabilityScanJobImpl) scan(ecosystem string, packages []rpmmd.PackageSpec) ([]worker.Vulnerability, error) {
+	vulnerabilities := []worker.Vulnerability{}
+	for start := 0; start < len(packages); start += osvBatchSize {
+		batch := packages[start:]
+		if len(batch) > osvBatchSize {
+			batch = batch[:osvBatchSize]
+		}
+
+		queries := make([]osvQuery, len(batch))
+		for i, pkg := range batch {
+			queries[i] = osvQuery{
+				Package: osvPackage{Name: pkg.Name, Ecosystem: ecosystem},
+				Version: packageVersion(pkg),
+			}
+		}
+		result, err := impl.queryBatch(queries)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Results) != len(batch) {
+			return nil, fmt.Errorf("OSV returned %d results for %d packages", len(result.Results), len(batch))
+		}
+
+		for i, r := range result.Results {
+			for _, vuln := range r.Vulns {
+				vulnerabilities = append(vulnerabilities, worker.Vulnerability{
+					ID:      vuln.ID,
+					Package: batch[i].Name,
+					Version: packageVersion(batch[i]),
+					Arch:    batch[i].Arch,
+				})
+			}
+		}
+	}
+	return vulnerabilities, nil
+}
+
+func (impl *VulnerabilityScanJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id())
+	result := worker.VulnerabilityScanJobResult{}
+
+	defer func() {
+		err := job.Update(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	var args worker.VulnerabilityScanJob
+	err := job.Args(&args)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingJobArgs, fmt.Sprintf("Error parsing arguments: %v", err), nil)
+		return err
+	}
+
+	if job.NDynamicArgs() != 2 {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorNoDynamicArgs, "A vulnerability scan job should depend on a depsolve and an osbuild job", nil)
+		return nil
+	}
+	var depsolveResult worker.DepsolveJobResult
+	err = job.DynamicArgs(0, &depsolveResult)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingDynamicArgs, "Error parsing dynamic args as depsolve job", nil)
+		return err
+	}
+	var osbuildResult worker.OSBuildJobResult
+	err = job.DynamicArgs(1, &osbuildResult)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingDynamicArgs, "Error parsing dynamic args as osbuild job", nil)
+		return err
+	}
+	if depsolveResult.JobError != nil || osbuildResult.JobError != nil || !osbuildResult.Success {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorJobDependency, "The build failed, its packages aren't scanned", nil)
+		return nil
+	}
+
+	if osbuildResult.PipelineNames == nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorParsingDynamicArgs, "The build result doesn't name its payload pipelines", nil)
+		return nil
+	}
+
+	ecosystem, ok := impl.ecosystem(args.Distribution)
+	if !ok {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidConfig, fmt.Sprintf("No OSV ecosystem configured for %s", args.Distribution), nil)
+		return nil
+	}
+
+	result.Vulnerabilities, err = impl.scan(ecosystem, uniquePackages(depsolveResult.PackageSpecs, osbuildResult.PipelineNames.Payload))
+	if err != nil {
+		logWithId.Errorf("Error scanning packages: %v", err)
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorVulnerabilityScan, "Error looking up the vulnerabilities of the packages", err.Error())
+		return nil
+	}
+	logWithId.Infof("Found %d known vulnerabilities", len(result.Vulnerabilities))
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/images/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestVulnerabilityScanEcosystem(t *testing.T) {
+	impl := &VulnerabilityScanJobImpl{
+		Ecosystems: map[string]string{
+			"rhel":   "Red Hat",
+			"rhel-9": "Red Hat:9",
+		},
+	}
+
+	ecosystem, ok := impl.ecosystem("rhel-9.4")
+	require.True(t, ok)
+	require.Equal(t, "Red Hat:9", ecosystem)
+	ecosystem, ok = impl.ecosystem("rhel-8.10")
+	require.True(t, ok)
+	require.Equal(t, "Red Hat", ecosystem)
+	_, ok = impl.ecosystem("fedora-39")
+	require.False(t, ok)
+}
+
+func TestVulnerabilityScan(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/querybatch", r.URL.Path)
+		var request struct {
+			Queries []osvQuery `json:"queries"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		var response osvBatchResponse
+		response.Results = make([]struct {
+			Vulns []struct {
+				ID string `json:"id"`
+			} `json:"vulns"`
+		}, len(request.Queries))
+		for i, query := range request.Queries {
+			require.Equal(t, "Red Hat", query.Package.Ecosystem)
+			if query.Package.Name == "openssl" && query.Version == "1:3.0.7-24.el9" {
+				response.Results[i].Vulns = append(response.Results[i].Vulns, struct {
+					ID string `json:"id"`
+				}{ID: "RHSA-2024:0001"})
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer srv.Close()
+
+	packages := uniquePackages(map[string][]rpmmd.PackageSpec{
+		"build": {
+			{Name: "bash", Version: "5.1.8", Release: "6.el9", Arch: "x86_64"},
+			{Name: "openssl", Epoch: 1, Version: "3.0.7", Release: "24.el9", Arch: "x86_64"},
+		},
+		"os": {
+			{Name: "openssl", Epoch: 1, Version: "3.0.7", Release: "24.el9", Arch: "x86_64"},
+			{Name: "kernel", Version: "5.14.0", Release: "427.el9", Arch: "x86_64"},
+		},
+		"installer": {
+			{Name: "openssl", Epoch: 1, Version: "3.0.7", Release: "24.el9", Arch: "x86_64"},
+		},
+	}, []string{"os", "installer"})
+	// the packages of the build root aren't scanned
+	require.Len(t, packages, 2)
+	require.Equal(t, "kernel", packages[0].Name)
+	require.Equal(t, "openssl", packages[1].Name)
+
+	impl := &VulnerabilityScanJobImpl{
+		OSVURL: srv.URL,
+		Client: srv.Client(),
+	}
+	vulnerabilities, err := impl.scan("Red Hat", packages)
+	require.NoError(t, err)
+	require.Equal(t, []worker.Vulnerability{
+		{
+			ID:      "RHSA-2024:0001",
+			Package: "openssl",
+			Version: "1:3.0.7-24.el9",
+			Arch:    "x86_64",
+		},
+	}, vulnerabilities)
+}
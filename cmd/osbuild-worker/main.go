@@ -7,6 +7,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"strings"
@@ -25,12 +26,17 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/upload/azure"
 	"github.com/osbuild/osbuild-composer/internal/upload/koji"
 	"github.com/osbuild/osbuild-composer/internal/upload/oci"
+	"github.com/osbuild/osbuild-composer/internal/upload/resumable"
+	"github.com/osbuild/osbuild-composer/internal/vault"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
 const configFile = "/etc/osbuild-worker/osbuild-worker.toml"
 const backoffDuration = time.Second * 10
 
+// How often the expiry of the client certificate is checked
+const certificateRenewalInterval = time.Hour
+
 type connectionConfig struct {
 	CACertFile     string
 	ClientKeyFile  string
@@ -38,10 +44,29 @@ type connectionConfig struct {
 }
 
 type kojiServer struct {
-	creds              koji.GSSAPICredentials
+	creds koji.GSSAPICredentials
+	// further identities, keyed by their principal
+	identities         map[string]koji.GSSAPICredentials
 	relaxTimeoutFactor uint
 }
 
+// credentials returns the Kerberos identity of the principal, or the default
+// identity of the server if the principal is empty.
+func (s kojiServer) credentials(principal string) (*koji.GSSAPICredentials, error) {
+	if principal == "" || principal == s.creds.Principal {
+		if s.creds.Principal == "" {
+			return nil, fmt.Errorf("no default Kerberos identity has been configured")
+		}
+		creds := s.creds
+		return &creds, nil
+	}
+	creds, exists := s.identities[principal]
+	if !exists {
+		return nil, fmt.Errorf("Kerberos principal has not been configured: %s", principal)
+	}
+	return &creds, nil
+}
+
 // Represents the implementation of a job type as defined by the worker API.
 type JobImplementation interface {
 	Run(job worker.Job) error
@@ -241,24 +266,45 @@ func main() {
 
 	kojiServers := make(map[string]kojiServer)
 	for server, kojiConfig := range config.Koji {
-		if kojiConfig.Kerberos == nil {
+		if kojiConfig.Kerberos == nil && len(kojiConfig.Identities) == 0 {
 			// For now we only support Kerberos authentication.
 			continue
 		}
-		kojiServers[server] = kojiServer{
-			creds: koji.GSSAPICredentials{
+		ks := kojiServer{
+			identities:         make(map[string]koji.GSSAPICredentials),
+			relaxTimeoutFactor: kojiConfig.RelaxTimeoutFactor,
+		}
+		if kojiConfig.Kerberos != nil {
+			ks.creds = koji.GSSAPICredentials{
 				Principal: kojiConfig.Kerberos.Principal,
 				KeyTab:    kojiConfig.Kerberos.KeyTab,
-			},
-			relaxTimeoutFactor: kojiConfig.RelaxTimeoutFactor,
+			}
+		}
+		for _, identity := range kojiConfig.Identities {
+			ks.identities[identity.Principal] = koji.GSSAPICredentials{
+				Principal: identity.Principal,
+				KeyTab:    identity.KeyTab,
+			}
 		}
+		kojiServers[server] = ks
 	}
 
+	workerID := config.WorkerID
+	if workerID == "" {
+		workerID, err = os.Hostname()
+		if err != nil {
+			logrus.Fatalf("Could not get the hostname to use as the worker ID: %v", err)
+		}
+	}
+	logrus.Infof("Worker ID: %s", workerID)
+
 	var client *worker.Client
+	var certRenewer *certificateRenewer
 	if unix {
 		client = worker.NewClientUnix(worker.ClientConfig{
 			BaseURL:  address,
 			BasePath: config.BasePath,
+			WorkerID: workerID,
 		})
 	} else if config.Authentication != nil {
 		var conf *tls.Config
@@ -304,6 +350,7 @@ func main() {
 			ClientSecret: clientSecret,
 			BasePath:     config.BasePath,
 			ProxyURL:     proxy,
+			WorkerID:     workerID,
 		})
 		if err != nil {
 			logrus.Fatalf("Error creating worker client: %v", err)
@@ -320,6 +367,22 @@ func main() {
 			if err != nil {
 				logrus.Fatalf("Error creating TLS config: %v", err)
 			}
+
+			if config.CertificateRenewal != nil {
+				var renewBefore time.Duration
+				if config.CertificateRenewal.RenewBefore != "" {
+					renewBefore, err = time.ParseDuration(config.CertificateRenewal.RenewBefore)
+					if err != nil {
+						logrus.Fatalf("Unable to parse renew_before: %v", err)
+					}
+				}
+				certRenewer, err = newCertificateRenewer(conConf.ClientCertFile, conConf.ClientKeyFile, renewBefore)
+				if err != nil {
+					logrus.Fatalf("Error loading the client certificate: %v", err)
+				}
+				conf.Certificates = nil
+				conf.GetClientCertificate = certRenewer.GetClientCertificate
+			}
 		}
 
 		proxy := ""
@@ -332,10 +395,15 @@ func main() {
 			TlsConfig: conf,
 			BasePath:  config.BasePath,
 			ProxyURL:  proxy,
+			WorkerID:  workerID,
 		})
 		if err != nil {
 			logrus.Fatalf("Error creating worker client: %v", err)
 		}
+
+		if certRenewer != nil {
+			go certRenewer.Run(context.Background(), client, certificateRenewalInterval)
+		}
 	}
 
 	// Load Azure credentials early. If the credentials file is malformed,
@@ -343,9 +411,12 @@ func main() {
 	// job with the org.osbuild.azure.image target.
 	var azureConfig AzureConfiguration
 	if config.Azure != nil {
-		azureConfig.Creds, err = azure.ParseAzureCredentialsFile(config.Azure.Credentials)
-		if err != nil {
-			logrus.Fatalf("cannot load azure credentials: %v", err)
+		// credentials stored in vault are loaded below
+		if config.Azure.VaultPath == "" {
+			azureConfig.Creds, err = azure.ParseAzureCredentialsFile(config.Azure.Credentials)
+			if err != nil {
+				logrus.Fatalf("cannot load azure credentials: %v", err)
+			}
 		}
 		azureConfig.UploadThreads = config.Azure.UploadThreads
 	}
@@ -388,12 +459,24 @@ func main() {
 	var containersPathPrefix = ""
 	var containersCertPath = ""
 	var containersTLSVerify = true
+	var containerSigner *ContainerSigner
 	if config.Containers != nil {
 		containersAuthFilePath = config.Containers.AuthFilePath
 		containersDomain = config.Containers.Domain
 		containersPathPrefix = config.Containers.PathPrefix
 		containersCertPath = config.Containers.CertPath
 		containersTLSVerify = config.Containers.TLSVerify
+
+		if config.Containers.CosignKey != "" {
+			passphrase, err := readSecretFile(config.Containers.CosignPassphrase)
+			if err != nil {
+				logrus.Fatalf("Error reading the passphrase of the cosign key: %v", err)
+			}
+			containerSigner = &ContainerSigner{
+				KeyPath:    config.Containers.CosignKey,
+				Passphrase: []byte(passphrase),
+			}
+		}
 	}
 
 	var ociConfig OCIConfiguration
@@ -426,6 +509,11 @@ func main() {
 		}
 	}
 
+	var hetznerCredentials = ""
+	if config.Hetzner != nil {
+		hetznerCredentials = config.Hetzner.Credentials
+	}
+
 	var pulpCredsFilePath = ""
 	var pulpAddress = ""
 	if config.Pulp != nil {
@@ -433,17 +521,159 @@ func main() {
 		pulpAddress = config.Pulp.ServerURL
 	}
 
+	var mockTargetConfig *MockTargetConfiguration
+	if config.MockTarget != nil {
+		logrus.Warn("The mock target is enabled, uploads to it are only simulated")
+		mockTargetConfig = &MockTargetConfiguration{
+			FailureRate: config.MockTarget.FailureRate,
+		}
+		if config.MockTarget.Latency != "" {
+			mockTargetConfig.Latency, err = time.ParseDuration(config.MockTarget.Latency)
+			if err != nil {
+				logrus.Fatalf("Unable to parse the latency of the mock target: %v", err)
+			}
+		}
+	}
+
+	var resumableUploads *ResumableUploadsConfiguration
+	if config.ResumableUploads != nil {
+		stateStore, err := resumable.NewStore(config.ResumableUploads.StateDirectory)
+		if err != nil {
+			logrus.Fatalf("Error configuring resumable uploads: %v", err)
+		}
+		resumableUploads = &ResumableUploadsConfiguration{
+			Store: stateStore,
+			Options: resumable.Options{
+				PartSize:    config.ResumableUploads.PartSizeMiB * resumable.MiB,
+				Concurrency: config.ResumableUploads.Concurrency,
+			},
+		}
+	}
+
+	var cephConfiguration *CephConfiguration
+	if config.Ceph != nil {
+		cephConfiguration = &CephConfiguration{
+			ConfigPath: config.Ceph.Config,
+			Keyring:    config.Ceph.Keyring,
+			User:       config.Ceph.User,
+		}
+	}
+
+	var libvirtConfiguration *LibvirtConfiguration
+	if config.Libvirt != nil {
+		logrus.Warnf("The libvirt target is enabled for %s", strings.Join(config.Libvirt.URIs, ", "))
+		libvirtConfiguration = &LibvirtConfiguration{
+			URIs: config.Libvirt.URIs,
+		}
+		if config.Libvirt.DomainTemplate != "" {
+			domainTemplate, err := os.ReadFile(config.Libvirt.DomainTemplate)
+			if err != nil {
+				logrus.Fatalf("Error reading the libvirt domain template: %v", err)
+			}
+			libvirtConfiguration.DomainTemplate = string(domainTemplate)
+		}
+	}
+
+	// Credentials stored in vault replace the ones from the files. They are
+	// loaded early, so a misconfiguration is reported before the first job.
+	var vaultCreds *vaultCredentials
+	if config.Vault != nil {
+		token, err := readSecretFile(config.Vault.TokenPath)
+		if err != nil {
+			logrus.Fatalf("Could not read vault token: %v", err)
+		}
+		secretID, err := readSecretFile(config.Vault.SecretIDPath)
+		if err != nil {
+			logrus.Fatalf("Could not read vault secret id: %v", err)
+		}
+		var secretTTL time.Duration
+		if config.Vault.SecretTTL != "" {
+			secretTTL, err = time.ParseDuration(config.Vault.SecretTTL)
+			if err != nil {
+				logrus.Fatalf("Unable to parse the secret TTL of the vault: %v", err)
+			}
+		}
+		vaultClient, err := vault.NewClient(vault.Config{
+			Address:    config.Vault.Address,
+			Namespace:  config.Vault.Namespace,
+			CACertFile: config.Vault.CACert,
+			Token:      token,
+			RoleID:     config.Vault.RoleID,
+			SecretID:   secretID,
+			SecretTTL:  secretTTL,
+		})
+		if err != nil {
+			logrus.Fatalf("Error creating vault client: %v", err)
+		}
+		runtimeDirectory, ok := os.LookupEnv("RUNTIME_DIRECTORY")
+		if !ok {
+			logrus.Fatal("RUNTIME_DIRECTORY is not set. Is the service file missing RuntimeDirectory=?")
+		}
+		vaultCreds, err = newVaultCredentials(vaultClient, runtimeDirectory)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		removeOnSignal(vaultCreds)
+
+		if config.AWS != nil && config.AWS.VaultPath != "" {
+			awsCredentials = vaultCreds.AWS(config.AWS.VaultPath)
+		}
+		if config.GCP != nil && config.GCP.VaultPath != "" {
+			gcpConfig.Creds = vaultCreds.GCP(config.GCP.VaultPath)
+		}
+		if config.Azure != nil && config.Azure.VaultPath != "" {
+			vaultCreds.Azure(config.Azure.VaultPath, &azureConfig)
+		}
+		if config.Containers != nil && config.Containers.VaultPath != "" {
+			containersAuthFilePath = vaultCreds.Containers(config.Containers.VaultPath, containersDomain)
+		}
+
+		// the credentials are only checked here, the jobs write them
+		// again while they run
+		err = vaultCreds.Refresh()
+		if err != nil {
+			logrus.Fatalf("Could not load credentials from vault: %v", err)
+		}
+		err = vaultCreds.Remove()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
+		renewerCtx, renewerCancel := context.WithCancel(context.Background())
+		defer renewerCancel()
+		go vaultClient.RunRenewer(renewerCtx, time.Minute)
+	}
+
 	// depsolve jobs can be done during other jobs
 	depsolveCtx, depsolveCtxCancel := context.WithCancel(context.Background())
 	solver := dnfjson.NewBaseSolver(rpmmd_cache)
+	// the default of the solver, needed to run dnf-json in the sandbox
+	dnfJsonPath := "/usr/libexec/osbuild-composer/dnf-json"
 	if config.DNFJson != "" {
-		solver.SetDNFJSONPath(config.DNFJson)
+		dnfJsonPath = config.DNFJson
+	}
+	depsolveSandbox, err := newSandbox(config.Sandbox, worker.JobTypeDepsolve)
+	if err != nil {
+		logrus.Fatalf("Error configuring the sandbox: %v", err)
+	}
+	if depsolveSandbox != nil || config.DNFJson != "" {
+		dnfJsonCmd := depsolveSandbox.Command([]string{rpmmd_cache}, nil, dnfJsonPath)
+		solver.SetDNFJSONPath(dnfJsonCmd[0], dnfJsonCmd[1:]...)
+	}
+	var cache *depsolveCache
+	if config.DepsolveCache != nil {
+		ttl, err := time.ParseDuration(config.DepsolveCache.TTL)
+		if err != nil {
+			logrus.Fatalf("Unable to parse the ttl of the depsolve cache: %v", err)
+		}
+		cache = newDepsolveCache(ttl, config.DepsolveCache.MaxEntries)
 	}
 	defer depsolveCtxCancel()
 	go func() {
 		jobImpls := map[string]JobImplementation{
 			worker.JobTypeDepsolve: &DepsolveJobImpl{
 				Solver: solver,
+				Cache:  cache,
 			},
 		}
 		acceptedJobTypes := []string{}
@@ -469,42 +699,56 @@ func main() {
 	}()
 
 	// non-depsolve job
-	jobImpls := map[string]JobImplementation{
-		worker.JobTypeOSBuild: &OSBuildJobImpl{
-			Store:       store,
-			Output:      output,
-			KojiServers: kojiServers,
-			GCPConfig:   gcpConfig,
-			AzureConfig: azureConfig,
-			OCIConfig:   ociConfig,
-			AWSCreds:    awsCredentials,
-			AWSBucket:   awsBucket,
-			S3Config: S3Configuration{
-				Creds:               genericS3Credentials,
-				Endpoint:            genericS3Endpoint,
-				Region:              genericS3Region,
-				Bucket:              genericS3Bucket,
-				CABundle:            genericS3CABundle,
-				SkipSSLVerification: genericS3SkipSSLVerification,
-			},
-			ContainersConfig: ContainersConfiguration{
-				AuthFilePath: containersAuthFilePath,
-				Domain:       containersDomain,
-				PathPrefix:   containersPathPrefix,
-				CertPath:     containersCertPath,
-				TLSVerify:    &containersTLSVerify,
-			},
-			PulpConfig: PulpConfiguration{
-				CredsFilePath: pulpCredsFilePath,
-				ServerAddress: pulpAddress,
-			},
+	osbuildSandbox, err := newSandbox(config.Sandbox, worker.JobTypeOSBuild)
+	if err != nil {
+		logrus.Fatalf("Error configuring the sandbox: %v", err)
+	}
+	osbuildJobImpl := &OSBuildJobImpl{
+		Store:        store,
+		Output:       output,
+		KojiServers:  kojiServers,
+		GCPConfig:    gcpConfig,
+		AzureConfig:  azureConfig,
+		OCIConfig:    ociConfig,
+		AWSCreds:     awsCredentials,
+		AWSBucket:    awsBucket,
+		HetznerCreds: hetznerCredentials,
+		S3Config: S3Configuration{
+			Creds:               genericS3Credentials,
+			Endpoint:            genericS3Endpoint,
+			Region:              genericS3Region,
+			Bucket:              genericS3Bucket,
+			CABundle:            genericS3CABundle,
+			SkipSSLVerification: genericS3SkipSSLVerification,
 		},
+		ContainersConfig: ContainersConfiguration{
+			AuthFilePath: containersAuthFilePath,
+			Domain:       containersDomain,
+			PathPrefix:   containersPathPrefix,
+			CertPath:     containersCertPath,
+			TLSVerify:    &containersTLSVerify,
+			Signer:       containerSigner,
+		},
+		PulpConfig: PulpConfiguration{
+			CredsFilePath: pulpCredsFilePath,
+			ServerAddress: pulpAddress,
+		},
+		MockTargetConfig:  mockTargetConfig,
+		UploadConcurrency: config.UploadConcurrency,
+		Sandbox:           osbuildSandbox,
+		LibvirtConfig:     libvirtConfiguration,
+		CephConfig:        cephConfiguration,
+		ResumableUploads:  resumableUploads,
+	}
+	jobImpls := map[string]JobImplementation{
+		worker.JobTypeOSBuild: osbuildJobImpl,
 		worker.JobTypeKojiInit: &KojiInitJobImpl{
 			KojiServers: kojiServers,
 		},
 		worker.JobTypeKojiFinalize: &KojiFinalizeJobImpl{
 			KojiServers: kojiServers,
 		},
+		worker.JobTypeCompose: &ComposeJobImpl{},
 		worker.JobTypeContainerResolve: &ContainerResolveJobImpl{
 			AuthFilePath: containersAuthFilePath,
 		},
@@ -516,6 +760,54 @@ func main() {
 		worker.JobTypeAWSEC2Share: &AWSEC2ShareJobImpl{
 			AWSCreds: awsCredentials,
 		},
+		worker.JobTypeAWSMarketplacePublish: &AWSMarketplacePublishJobImpl{
+			AWSCreds:     awsCredentials,
+			PollInterval: time.Minute,
+		},
+		worker.JobTypeGCPImageExport: &GCPImageExportJobImpl{
+			GCP:          osbuildJobImpl.getGCP,
+			Timeout:      2 * time.Hour,
+			PollInterval: 30 * time.Second,
+		},
+		worker.JobTypeOSTreeMirror: &OSTreeMirrorJobImpl{
+			Client: &http.Client{},
+		},
+	}
+
+	// packages are only scanned by workers which know the OSV ecosystems
+	// of the distributions
+	if config.VulnerabilityScan != nil {
+		jobImpls[worker.JobTypeVulnerabilityScan] = &VulnerabilityScanJobImpl{
+			OSVURL:     config.VulnerabilityScan.OSVURL,
+			Ecosystems: config.VulnerabilityScan.Ecosystems,
+			Client:     &http.Client{Timeout: 5 * time.Minute},
+		}
+	}
+
+	// only the dedicated workers holding the signing key sign artifacts
+	if config.Signing != nil {
+		entity, err := loadSigningKey(config.Signing.Key, config.Signing.Passphrase)
+		if err != nil {
+			logrus.Fatalf("Error loading the signing key: %v", err)
+		}
+		jobImpls[worker.JobTypeSign] = &SignJobImpl{
+			Entity: entity,
+			S3:     osbuildJobImpl.getAWSForS3Target,
+			Client: &http.Client{},
+		}
+	}
+
+	if vaultCreds != nil {
+		// the osbuild job got a copy of the azure configuration
+		if config.Azure != nil && config.Azure.VaultPath != "" {
+			vaultCreds.Azure(config.Azure.VaultPath, &osbuildJobImpl.AzureConfig)
+		}
+		for jt, impl := range jobImpls {
+			jobImpls[jt] = &VaultJobImpl{
+				Creds: vaultCreds,
+				Impl:  impl,
+			}
+		}
 	}
 
 	acceptedJobTypes := []string{}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// netbootFiles returns the paths of the kernel and the initrd in the /boot
+// directory of the tree. Rescue kernels are skipped, if there are several
+// other kernels the one with the greatest version string is used.
+func netbootFiles(tree string) (string, string, error) {
+	matches, err := filepath.Glob(filepath.Join(tree, "boot", "vmlinuz-*"))
+	if err != nil {
+		return "", "", err
+	}
+	var kernels []string
+	for _, kernel := range matches {
+		if strings.Contains(filepath.Base(kernel), "-rescue-") {
+			continue
+		}
+		kernels = append(kernels, kernel)
+	}
+	if len(kernels) == 0 {
+		return "", "", fmt.Errorf("no kernel found in %s", filepath.Join(tree, "boot"))
+	}
+	sort.Strings(kernels)
+	kernel := kernels[len(kernels)-1]
+
+	version := strings.TrimPrefix(filepath.Base(kernel), "vmlinuz-")
+	initrd := filepath.Join(tree, "boot", "initramfs-"+version+".img")
+	_, err = os.Stat(initrd)
+	if err != nil {
+		return "", "", fmt.Errorf("no initrd of kernel %s found: %v", version, err)
+	}
+	return kernel, initrd, nil
+}
+
+// ipxeScript returns an iPXE script booting the kernel with the initrd.
+func ipxeScript(kernelURL, initrdURL, kernelArgs string) []byte {
+	// EFI kernels find the initrd by the name iPXE gives it
+	args := "initrd=" + path.Base(initrdURL)
+	if kernelArgs != "" {
+		args += " " + kernelArgs
+	}
+	return []byte(fmt.Sprintf("#!ipxe\nkernel %s %s\ninitrd %s\nboot\n", kernelURL, args, initrdURL))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetbootFiles(t *testing.T) {
+	tree := t.TempDir()
+	boot := filepath.Join(tree, "boot")
+	require.NoError(t, os.Mkdir(boot, 0755))
+
+	_, _, err := netbootFiles(tree)
+	require.Error(t, err)
+
+	for _, name := range []string{
+		"vmlinuz-0-rescue-c0ffee",
+		"initramfs-0-rescue-c0ffee.img",
+		"vmlinuz-5.14.0-362.el9.x86_64",
+		"initramfs-5.14.0-362.el9.x86_64.img",
+		"vmlinuz-5.14.0-284.el9.x86_64",
+		"initramfs-5.14.0-284.el9.x86_64.img",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(boot, name), nil, 0600))
+	}
+	kernel, initrd, err := netbootFiles(tree)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(boot, "vmlinuz-5.14.0-362.el9.x86_64"), kernel)
+	require.Equal(t, filepath.Join(boot, "initramfs-5.14.0-362.el9.x86_64.img"), initrd)
+
+	// a kernel without its initrd can't be netbooted
+	require.NoError(t, os.Remove(filepath.Join(boot, "initramfs-5.14.0-362.el9.x86_64.img")))
+	_, _, err = netbootFiles(tree)
+	require.Error(t, err)
+}
+
+func TestIPXEScript(t *testing.T) {
+	require.Equal(t, `#!ipxe
+kernel https://images.example.com/metal/vmlinuz initrd=initrd.img console=ttyS0
+initrd https://images.example.com/metal/initrd.img
+boot
+`, string(ipxeScript("https://images.example.com/metal/vmlinuz", "https://images.example.com/metal/initrd.img", "console=ttyS0")))
+
+	require.Equal(t, `#!ipxe
+kernel https://images.example.com/metal/vmlinuz initrd=initrd.img
+initrd https://images.example.com/metal/initrd.img
+boot
+`, string(ipxeScript("https://images.example.com/metal/vmlinuz", "https://images.example.com/metal/initrd.img", "")))
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// orasManifest returns the manifest of an artifact consisting of the single
+// layer, the way `oras push` creates it: the config is the empty JSON blob
+// and the type of the artifact is set in the manifest.
+func orasManifest(layer imgspecv1.Descriptor, artifactType string, annotations map[string]string) ([]byte, error) {
+	config := imgspecv1.DescriptorEmptyJSON
+	// the config is uploaded as a blob, not embedded
+	config.Data = nil
+	m := manifest.OCI1FromComponents(config, []imgspecv1.Descriptor{layer})
+	m.ArtifactType = artifactType
+	if len(annotations) > 0 {
+		m.Annotations = annotations
+	}
+	return m.Serialize()
+}
+
+// fileDigest returns the sha256 digest and the size of the file.
+func fileDigest(path string) (digest.Digest, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return digest.NewDigest(digest.SHA256, h), size, nil
+}
+
+// pushORASArtifact pushes the file as an OCI artifact to the registry and
+// returns the digest of its manifest. The file is the only layer of the
+// artifact, titled with its name so `oras pull` restores it.
+func pushORASArtifact(ctx context.Context, sys *types.SystemContext, name reference.Named, path, artifactType, mediaType string, annotations map[string]string) (digest.Digest, error) {
+	ref, err := docker.NewReference(name)
+	if err != nil {
+		return "", err
+	}
+	dest, err := ref.NewImageDestination(ctx, sys)
+	if err != nil {
+		return "", err
+	}
+	defer dest.Close()
+
+	_, err = dest.PutBlob(ctx, bytes.NewReader(imgspecv1.DescriptorEmptyJSON.Data), types.BlobInfo{
+		Digest:    imgspecv1.DescriptorEmptyJSON.Digest,
+		Size:      imgspecv1.DescriptorEmptyJSON.Size,
+		MediaType: imgspecv1.MediaTypeEmptyJSON,
+	}, none.NoCache, true)
+	if err != nil {
+		return "", fmt.Errorf("error uploading the artifact config: %w", err)
+	}
+
+	layerDigest, size, err := fileDigest(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	_, err = dest.PutBlob(ctx, f, types.BlobInfo{
+		Digest:    layerDigest,
+		Size:      size,
+		MediaType: mediaType,
+	}, none.NoCache, false)
+	if err != nil {
+		return "", fmt.Errorf("error uploading the artifact: %w", err)
+	}
+
+	m, err := orasManifest(imgspecv1.Descriptor{
+		MediaType: mediaType,
+		Digest:    layerDigest,
+		Size:      size,
+		Annotations: map[string]string{
+			imgspecv1.AnnotationTitle: filepath.Base(path),
+		},
+	}, artifactType, annotations)
+	if err != nil {
+		return "", err
+	}
+	err = dest.PutManifest(ctx, m, nil)
+	if err != nil {
+		return "", fmt.Errorf("error uploading the artifact manifest: %w", err)
+	}
+	err = dest.Commit(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(m), nil
+}
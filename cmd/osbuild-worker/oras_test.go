@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestORASManifest(t *testing.T) {
+	layer := imgspecv1.Descriptor{
+		MediaType: "application/x-qemu-disk",
+		Digest:    digest.FromString("disk"),
+		Size:      4,
+	}
+	data, err := orasManifest(layer, "application/vnd.osbuild.disk-image.v1", map[string]string{"org.opencontainers.image.version": "9.3"})
+	require.NoError(t, err)
+
+	var m imgspecv1.Manifest
+	require.NoError(t, json.Unmarshal(data, &m))
+	require.Equal(t, imgspecv1.MediaTypeImageManifest, m.MediaType)
+	require.Equal(t, "application/vnd.osbuild.disk-image.v1", m.ArtifactType)
+	require.Equal(t, imgspecv1.MediaTypeEmptyJSON, m.Config.MediaType)
+	require.Equal(t, imgspecv1.DescriptorEmptyJSON.Digest, m.Config.Digest)
+	require.Nil(t, m.Config.Data)
+	require.Equal(t, []imgspecv1.Descriptor{layer}, m.Layers)
+	require.Equal(t, "9.3", m.Annotations["org.opencontainers.image.version"])
+}
+
+func TestFileDigest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.qcow2")
+	require.NoError(t, os.WriteFile(path, []byte("disk"), 0600))
+	d, size, err := fileDigest(path)
+	require.NoError(t, err)
+	require.Equal(t, digest.FromString("disk"), d)
+	require.Equal(t, int64(4), size)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/osbuild/images/pkg/manifest"
+	"github.com/osbuild/images/pkg/osbuild"
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+// manifestPartitionTable returns the partition table created by the first
+// sfdisk or sgdisk stage of the manifest, or nil if the manifest doesn't
+// partition any disk.
+func manifestPartitionTable(m manifest.OSBuildManifest) (*target.KojiPartitionTable, error) {
+	var parsed struct {
+		Pipelines []struct {
+			Stages []struct {
+				Type    string          `json:"type"`
+				Options json.RawMessage `json:"options"`
+			} `json:"stages"`
+		} `json:"pipelines"`
+	}
+	err := json.Unmarshal(m, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the manifest: %v", err)
+	}
+
+	for _, pipeline := range parsed.Pipelines {
+		for _, stage := range pipeline.Stages {
+			switch stage.Type {
+			case "org.osbuild.sfdisk":
+				var options osbuild.SfdiskStageOptions
+				err = json.Unmarshal(stage.Options, &options)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse the options of the %s stage: %v", stage.Type, err)
+				}
+				pt := &target.KojiPartitionTable{
+					Type:       options.Label,
+					UUID:       options.UUID,
+					Partitions: []target.KojiPartition{},
+				}
+				for _, p := range options.Partitions {
+					pt.Partitions = append(pt.Partitions, target.KojiPartition{
+						Start:    p.Start,
+						Size:     p.Size,
+						Type:     p.Type,
+						UUID:     p.UUID,
+						Name:     p.Name,
+						Bootable: p.Bootable,
+					})
+				}
+				return pt, nil
+			case "org.osbuild.sgdisk":
+				var options osbuild.SgdiskStageOptions
+				err = json.Unmarshal(stage.Options, &options)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse the options of the %s stage: %v", stage.Type, err)
+				}
+				// sgdisk only creates GPT partition tables
+				pt := &target.KojiPartitionTable{
+					Type:       "gpt",
+					UUID:       options.UUID.String(),
+					Partitions: []target.KojiPartition{},
+				}
+				for _, p := range options.Partitions {
+					partition := target.KojiPartition{
+						Start:    p.Start,
+						Size:     p.Size,
+						Type:     p.Type,
+						Name:     p.Name,
+						Bootable: p.Bootable,
+					}
+					if p.UUID != nil {
+						partition.UUID = p.UUID.String()
+					}
+					pt.Partitions = append(pt.Partitions, partition)
+				}
+				return pt, nil
+			}
+		}
+	}
+	return nil, nil
+}
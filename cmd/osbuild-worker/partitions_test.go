@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/images/pkg/manifest"
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+func TestManifestPartitionTable(t *testing.T) {
+	sfdisk := manifest.OSBuildManifest(`{"version":"2","pipelines":[
+		{"name":"os","stages":[{"type":"org.osbuild.rpm","options":{}}]},
+		{"name":"image","stages":[{"type":"org.osbuild.sfdisk","options":{"label":"gpt","uuid":"D209C89E-EA5E-4FBD-B161-B461CCE297E0","partitions":[
+			{"bootable":true,"size":2048,"start":2048,"type":"21686148-6449-6E6F-744E-656564454649","uuid":"FAC7F1FB-3E8D-4137-A512-961DE09A5549"},
+			{"size":8384479,"start":4096,"type":"0FC63DAF-8483-4772-8E79-3D69D8477DE4","name":"root"}
+		]}}]}
+	]}`)
+	pt, err := manifestPartitionTable(sfdisk)
+	require.NoError(t, err)
+	require.Equal(t, &target.KojiPartitionTable{
+		Type: "gpt",
+		UUID: "D209C89E-EA5E-4FBD-B161-B461CCE297E0",
+		Partitions: []target.KojiPartition{
+			{Start: 2048, Size: 2048, Type: "21686148-6449-6E6F-744E-656564454649", UUID: "FAC7F1FB-3E8D-4137-A512-961DE09A5549", Bootable: true},
+			{Start: 4096, Size: 8384479, Type: "0FC63DAF-8483-4772-8E79-3D69D8477DE4", Name: "root"},
+		},
+	}, pt)
+
+	sgdisk := manifest.OSBuildManifest(`{"version":"2","pipelines":[
+		{"name":"image","stages":[{"type":"org.osbuild.sgdisk","options":{"uuid":"d209c89e-ea5e-4fbd-b161-b461cce297e0","partitions":[
+			{"size":2048,"start":2048,"type":"C12A7328-F81F-11D2-BA4B-00A0C93EC93B","uuid":"68b2905b-df3e-4fb3-80fa-49d1e773aa33"}
+		]}}]}
+	]}`)
+	pt, err = manifestPartitionTable(sgdisk)
+	require.NoError(t, err)
+	require.Equal(t, &target.KojiPartitionTable{
+		Type: "gpt",
+		UUID: "d209c89e-ea5e-4fbd-b161-b461cce297e0",
+		Partitions: []target.KojiPartition{
+			{Start: 2048, Size: 2048, Type: "C12A7328-F81F-11D2-BA4B-00A0C93EC93B", UUID: "68b2905b-df3e-4fb3-80fa-49d1e773aa33"},
+		},
+	}, pt)
+
+	// tarballs and the like aren't partitioned
+	pt, err = manifestPartitionTable(manifest.OSBuildManifest(`{"version":"2","pipelines":[{"name":"os","stages":[]}]}`))
+	require.NoError(t, err)
+	require.Nil(t, pt)
+
+	_, err = manifestPartitionTable(manifest.OSBuildManifest(`{"pipelines":`))
+	require.Error(t, err)
+}
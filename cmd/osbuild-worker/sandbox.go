@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/osbuild/images/pkg/osbuild"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+const (
+	SandboxTypePodman     = "podman"
+	SandboxTypeBubblewrap = "bubblewrap"
+)
+
+// How often the disk usage of a sandboxed job is checked
+const sandboxDiskCheckInterval = 10 * time.Second
+
+var errSandboxDiskLimitExceeded = errors.New("disk limit of the sandbox exceeded")
+
+// SandboxLimits are the resource limits of the processes of a job. Zero
+// values mean no limit.
+type SandboxLimits struct {
+	// Maximum memory in bytes
+	Memory uint64
+	// Maximum size in bytes of the output of the job
+	Disk uint64
+	// Maximum number of processes and threads
+	PIDs uint64
+}
+
+// Sandbox isolates the external programs run by jobs from the host and from
+// each other, either in a podman container or a bubblewrap sandbox.
+type Sandbox struct {
+	Type string
+	// Container image running the programs, only used by podman
+	Image  string
+	Limits SandboxLimits
+}
+
+// newSandbox returns the sandbox for the given job type, or nil if the
+// sandbox isn't configured.
+func newSandbox(config *sandboxConfig, jobType string) (*Sandbox, error) {
+	if config == nil || config.Type == "" {
+		return nil, nil
+	}
+
+	sandbox := &Sandbox{
+		Type:  config.Type,
+		Image: config.Image,
+	}
+
+	limits, ok := config.Limits[jobType]
+	if !ok {
+		return sandbox, nil
+	}
+	var err error
+	if limits.Memory != "" {
+		sandbox.Limits.Memory, err = common.DataSizeToUint64(limits.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory limit of %s jobs: %v", jobType, err)
+		}
+	}
+	if limits.Disk != "" {
+		sandbox.Limits.Disk, err = common.DataSizeToUint64(limits.Disk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid disk limit of %s jobs: %v", jobType, err)
+		}
+	}
+	sandbox.Limits.PIDs = limits.PIDs
+	return sandbox, nil
+}
+
+// Command returns the command line running the given program in the
+// sandbox. The paths are made writable in the sandbox and the environment
+// variables are passed to the program.
+func (s *Sandbox) Command(paths, env []string, name string, args ...string) []string {
+	if s == nil {
+		return append([]string{name}, args...)
+	}
+
+	var cmd []string
+	switch s.Type {
+	case SandboxTypePodman:
+		cmd = []string{
+			"podman", "run", "--rm", "--interactive",
+			// osbuild needs to set up loop devices and mounts
+			"--privileged",
+			"--network=host",
+		}
+		if s.Limits.Memory > 0 {
+			cmd = append(cmd, "--memory="+strconv.FormatUint(s.Limits.Memory, 10))
+		}
+		if s.Limits.PIDs > 0 {
+			cmd = append(cmd, "--pids-limit="+strconv.FormatUint(s.Limits.PIDs, 10))
+		}
+		for _, p := range paths {
+			cmd = append(cmd, "--volume="+p+":"+p)
+		}
+		for _, e := range env {
+			cmd = append(cmd, "--env="+e)
+		}
+		cmd = append(cmd, s.Image)
+
+	case SandboxTypeBubblewrap:
+		// bubblewrap doesn't limit resources, run it in a transient
+		// systemd scope which does
+		if s.Limits.Memory > 0 || s.Limits.PIDs > 0 {
+			cmd = []string{"systemd-run", "--scope", "--quiet", "--collect"}
+			if s.Limits.Memory > 0 {
+				cmd = append(cmd, "--property=MemoryMax="+strconv.FormatUint(s.Limits.Memory, 10))
+			}
+			if s.Limits.PIDs > 0 {
+				cmd = append(cmd, "--property=TasksMax="+strconv.FormatUint(s.Limits.PIDs, 10))
+			}
+		}
+		cmd = append(cmd,
+			"bwrap",
+			"--die-with-parent",
+			"--unshare-pid",
+			"--unshare-ipc",
+			"--unshare-uts",
+			"--dev-bind", "/", "/",
+			"--proc", "/proc",
+			"--tmpfs", "/tmp",
+		)
+		for _, p := range paths {
+			cmd = append(cmd, "--bind", p, p)
+		}
+		for _, e := range env {
+			key, value, _ := strings.Cut(e, "=")
+			cmd = append(cmd, "--setenv", key, value)
+		}
+		cmd = append(cmd, "--")
+	}
+
+	return append(append(cmd, name), args...)
+}
+
+// dirSize returns the size of all the files in the directory.
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
+}
+
+// RunOSBuild runs osbuild in the sandbox, like osbuild.RunOSBuild. If the
+// output directory grows over the disk limit, osbuild is stopped and
+// errSandboxDiskLimitExceeded is returned.
+func (s *Sandbox) RunOSBuild(manifest []byte, store, outputDirectory string, exports, extraEnv []string, errorWriter io.Writer) (*osbuild.Result, error) {
+	args := []string{
+		"--store", store,
+		"--output-directory", outputDirectory,
+		"--json",
+		"-",
+	}
+	for _, export := range exports {
+		args = append(args, "--export", export)
+	}
+
+	paths := []string{store, outputDirectory}
+	// the containers auth file needs to be readable by osbuild
+	for _, e := range extraEnv {
+		if key, value, _ := strings.Cut(e, "="); key == "REGISTRY_AUTH_FILE" {
+			paths = append(paths, value)
+		}
+	}
+
+	cmdline := s.Command(paths, extraEnv, "osbuild", args...)
+	// #nosec G204
+	cmd := exec.Command(cmdline[0], cmdline[1:]...)
+	var stdoutBuffer bytes.Buffer
+	cmd.Stdout = &stdoutBuffer
+	cmd.Stderr = errorWriter
+	cmd.Stdin = bytes.NewReader(manifest)
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("error starting osbuild: %v", err)
+	}
+
+	var diskLimitExceeded atomic.Bool
+	done := make(chan struct{})
+	if s.Limits.Disk > 0 {
+		go func() {
+			ticker := time.NewTicker(sandboxDiskCheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+				}
+
+				size, err := dirSize(outputDirectory)
+				if err != nil {
+					logrus.Warnf("Cannot get the disk usage of %s: %v", outputDirectory, err)
+					continue
+				}
+				if size > s.Limits.Disk {
+					logrus.Errorf("osbuild output is %d bytes, over the limit of %d bytes, stopping osbuild", size, s.Limits.Disk)
+					diskLimitExceeded.Store(true)
+					// podman forwards the signal to the container, bubblewrap
+					// kills the sandbox when it exits
+					_ = cmd.Process.Signal(syscall.SIGTERM)
+					return
+				}
+			}
+		}()
+	}
+
+	err = cmd.Wait()
+	close(done)
+
+	if diskLimitExceeded.Load() {
+		return nil, errSandboxDiskLimitExceeded
+	}
+
+	// try to decode the output even though the job could have failed
+	var res osbuild.Result
+	decodeErr := json.Unmarshal(stdoutBuffer.Bytes(), &res)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("error decoding osbuild output: %v\nthe raw output:\n%s", decodeErr, stdoutBuffer.String())
+	}
+
+	// osbuild returns non-zero when the pipeline fails, which is
+	// communicated in the result
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("running osbuild failed: %v", err)
+	}
+
+	return &res, nil
+}
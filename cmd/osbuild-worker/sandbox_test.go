@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSandbox(t *testing.T) {
+	sandbox, err := newSandbox(nil, "osbuild")
+	require.NoError(t, err)
+	require.Nil(t, sandbox)
+
+	config := &sandboxConfig{
+		Type:  SandboxTypePodman,
+		Image: "quay.io/osbuild/osbuild",
+		Limits: map[string]sandboxLimitsConfig{
+			"osbuild": {
+				Memory: "8 GiB",
+				Disk:   "100 GiB",
+				PIDs:   4096,
+			},
+		},
+	}
+	sandbox, err = newSandbox(config, "osbuild")
+	require.NoError(t, err)
+	require.Equal(t, &Sandbox{
+		Type:  SandboxTypePodman,
+		Image: "quay.io/osbuild/osbuild",
+		Limits: SandboxLimits{
+			Memory: 8 * 1024 * 1024 * 1024,
+			Disk:   100 * 1024 * 1024 * 1024,
+			PIDs:   4096,
+		},
+	}, sandbox)
+
+	// job types without limits are still sandboxed
+	sandbox, err = newSandbox(config, "depsolve")
+	require.NoError(t, err)
+	require.Equal(t, SandboxLimits{}, sandbox.Limits)
+
+	config.Limits["osbuild"] = sandboxLimitsConfig{Memory: "lots"}
+	_, err = newSandbox(config, "osbuild")
+	require.Error(t, err)
+}
+
+func TestSandboxCommand(t *testing.T) {
+	var sandbox *Sandbox
+	require.Equal(t, []string{"osbuild", "-"}, sandbox.Command([]string{"/store"}, nil, "osbuild", "-"))
+
+	sandbox = &Sandbox{
+		Type:  SandboxTypePodman,
+		Image: "quay.io/osbuild/osbuild",
+		Limits: SandboxLimits{
+			Memory: 1024,
+			PIDs:   64,
+		},
+	}
+	require.Equal(t, []string{
+		"podman", "run", "--rm", "--interactive", "--privileged", "--network=host",
+		"--memory=1024", "--pids-limit=64",
+		"--volume=/store:/store",
+		"--env=FOO=bar",
+		"quay.io/osbuild/osbuild",
+		"osbuild", "-",
+	}, sandbox.Command([]string{"/store"}, []string{"FOO=bar"}, "osbuild", "-"))
+
+	sandbox = &Sandbox{
+		Type: SandboxTypeBubblewrap,
+	}
+	require.Equal(t, []string{
+		"bwrap", "--die-with-parent", "--unshare-pid", "--unshare-ipc", "--unshare-uts",
+		"--dev-bind", "/", "/", "--proc", "/proc", "--tmpfs", "/tmp",
+		"--bind", "/store", "/store",
+		"--setenv", "FOO", "bar",
+		"--",
+		"osbuild", "-",
+	}, sandbox.Command([]string{"/store"}, []string{"FOO=bar"}, "osbuild", "-"))
+
+	sandbox.Limits.Memory = 1024
+	cmd := sandbox.Command(nil, nil, "osbuild")
+	require.Equal(t, []string{"systemd-run", "--scope", "--quiet", "--collect", "--property=MemoryMax=1024", "bwrap"}, cmd[:6])
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(path.Join(dir, "export"), 0700))
+	require.NoError(t, os.WriteFile(path.Join(dir, "export", "disk.img"), make([]byte, 100), 0600))
+	require.NoError(t, os.WriteFile(path.Join(dir, "manifest.json"), make([]byte, 20), 0600))
+
+	size, err := dirSize(dir)
+	require.NoError(t, err)
+	require.Equal(t, uint64(120), size)
+
+	size, err = dirSize(path.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), size)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"google.golang.org/api/googleapi"
+
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// targetError returns the error of a target. The given code is replaced by a
+// more specific one if the cause of the error is recognized, so users can
+// tell whether retrying the compose makes sense.
+func targetError(code clienterrors.ClientErrorCode, reason string, err error) *clienterrors.Error {
+	if specific := targetErrorCode(err); specific != 0 {
+		code = specific
+	}
+	return clienterrors.WorkerClientError(code, reason, nil)
+}
+
+// targetErrorCode classifies errors returned by the clients of the cloud
+// providers. It returns 0 if the error isn't recognized.
+func targetErrorCode(err error) clienterrors.ClientErrorCode {
+	if err == nil {
+		return 0
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if code := awsErrorCode(awsErr.Code()); code != 0 {
+			return code
+		}
+	}
+
+	var gcpErr *googleapi.Error
+	if errors.As(err, &gcpErr) {
+		var reasons []string
+		for _, item := range gcpErr.Errors {
+			reasons = append(reasons, item.Reason)
+		}
+		if code := gcpErrorCode(gcpErr.Code, reasons...); code != 0 {
+			return code
+		}
+	}
+
+	// errors of the Compute Engine client
+	var gcpAPIErr interface {
+		HTTPCode() int
+		Reason() string
+	}
+	if errors.As(err, &gcpAPIErr) {
+		if code := gcpErrorCode(gcpAPIErr.HTTPCode(), gcpAPIErr.Reason()); code != 0 {
+			return code
+		}
+	}
+
+	if code := azureErrorCode(err); code != 0 {
+		return code
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return clienterrors.ErrorTargetNetwork
+	}
+
+	return 0
+}
+
+func awsErrorCode(code string) clienterrors.ClientErrorCode {
+	switch code {
+	case "AuthFailure", "UnauthorizedOperation", "AccessDenied", "AccessDeniedException",
+		"InvalidClientTokenId", "InvalidAccessKeyId", "SignatureDoesNotMatch",
+		"ExpiredToken", "ExpiredTokenException", "UnrecognizedClientException",
+		"NoCredentialProviders":
+		return clienterrors.ErrorTargetAuthentication
+	case "Throttling", "ThrottlingException", "RequestLimitExceeded", "RequestThrottled",
+		"RequestThrottledException", "TooManyRequestsException", "SlowDown":
+		return clienterrors.ErrorTargetThrottled
+	case "RequestError", "RequestTimeout", "RequestTimeoutException":
+		return clienterrors.ErrorTargetNetwork
+	case "ValidationError", "MissingParameter", "NoSuchBucket":
+		return clienterrors.ErrorTargetInvalidParameters
+	}
+
+	switch {
+	case strings.HasSuffix(code, "LimitExceeded"), strings.HasSuffix(code, "QuotaExceeded"):
+		return clienterrors.ErrorTargetQuotaExceeded
+	case strings.HasPrefix(code, "Invalid"):
+		return clienterrors.ErrorTargetInvalidParameters
+	}
+	return 0
+}
+
+func gcpErrorCode(status int, reasons ...string) clienterrors.ClientErrorCode {
+	for _, reason := range reasons {
+		switch reason {
+		case "quotaExceeded", "limitExceeded", "QUOTA_EXCEEDED":
+			return clienterrors.ErrorTargetQuotaExceeded
+		case "rateLimitExceeded", "userRateLimitExceeded", "RATE_LIMIT_EXCEEDED":
+			return clienterrors.ErrorTargetThrottled
+		}
+	}
+	return httpStatusErrorCode(status)
+}
+
+func azureErrorCode(err error) clienterrors.ClientErrorCode {
+	switch {
+	case bloberror.HasCode(err, bloberror.AuthenticationFailed, bloberror.AuthorizationFailure,
+		bloberror.AuthorizationPermissionMismatch, bloberror.InsufficientAccountPermissions):
+		return clienterrors.ErrorTargetAuthentication
+	case bloberror.HasCode(err, bloberror.ServerBusy):
+		return clienterrors.ErrorTargetThrottled
+	case bloberror.HasCode(err, bloberror.OperationTimedOut):
+		return clienterrors.ErrorTargetNetwork
+	}
+
+	var detailedErr autorest.DetailedError
+	if errors.As(err, &detailedErr) {
+		if strings.Contains(detailedErr.Error(), "QuotaExceeded") {
+			return clienterrors.ErrorTargetQuotaExceeded
+		}
+		if status, ok := detailedErr.StatusCode.(int); ok {
+			return httpStatusErrorCode(status)
+		}
+	}
+	return 0
+}
+
+func httpStatusErrorCode(status int) clienterrors.ClientErrorCode {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return clienterrors.ErrorTargetAuthentication
+	case http.StatusTooManyRequests:
+		return clienterrors.ErrorTargetThrottled
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusConflict:
+		return clienterrors.ErrorTargetInvalidParameters
+	case http.StatusRequestTimeout, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return clienterrors.ErrorTargetNetwork
+	}
+	return 0
+}
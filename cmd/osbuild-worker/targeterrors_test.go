@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+func TestTargetErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want clienterrors.ClientErrorCode
+	}{
+		{"unknown", errors.New("something failed"), 0},
+		{"aws auth", awserr.New("AuthFailure", "not authorized", nil), clienterrors.ErrorTargetAuthentication},
+		{"aws throttled", awserr.New("RequestLimitExceeded", "slow down", nil), clienterrors.ErrorTargetThrottled},
+		{"aws quota", awserr.New("ResourceLimitExceeded", "too many snapshots", nil), clienterrors.ErrorTargetQuotaExceeded},
+		{"aws invalid", awserr.New("InvalidAMIName.Malformed", "bad name", nil), clienterrors.ErrorTargetInvalidParameters},
+		{"aws network", awserr.New("RequestError", "send request failed", nil), clienterrors.ErrorTargetNetwork},
+		{"aws wrapped", fmt.Errorf("uploading failed: %w", awserr.New("SlowDown", "", nil)), clienterrors.ErrorTargetThrottled},
+		{"gcp auth", &googleapi.Error{Code: http.StatusUnauthorized}, clienterrors.ErrorTargetAuthentication},
+		{"gcp quota", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}, clienterrors.ErrorTargetQuotaExceeded},
+		{"gcp throttled", fmt.Errorf("failed: %w", &googleapi.Error{Code: http.StatusTooManyRequests}), clienterrors.ErrorTargetThrottled},
+		{"gcp invalid", &googleapi.Error{Code: http.StatusBadRequest}, clienterrors.ErrorTargetInvalidParameters},
+		{"azure auth", autorest.DetailedError{StatusCode: http.StatusForbidden}, clienterrors.ErrorTargetAuthentication},
+		{"azure throttled", fmt.Errorf("failed: %w", autorest.DetailedError{StatusCode: http.StatusTooManyRequests}), clienterrors.ErrorTargetThrottled},
+		{"azure quota", autorest.DetailedError{StatusCode: http.StatusConflict, Message: "QuotaExceeded"}, clienterrors.ErrorTargetQuotaExceeded},
+		{"network", fmt.Errorf("failed: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), clienterrors.ErrorTargetNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, targetErrorCode(tt.err))
+		})
+	}
+}
+
+func TestTargetError(t *testing.T) {
+	err := targetError(clienterrors.ErrorUploadingImage, "upload failed", errors.New("unknown"))
+	require.Equal(t, clienterrors.ErrorUploadingImage, err.ID)
+	require.Equal(t, "upload failed", err.Reason)
+
+	err = targetError(clienterrors.ErrorUploadingImage, "upload failed", awserr.New("Throttling", "", nil))
+	require.Equal(t, clienterrors.ErrorTargetThrottled, err.ID)
+	require.True(t, err.IsRetryable())
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/upload/azure"
+	"github.com/osbuild/osbuild-composer/internal/vault"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// vaultCredentials keeps the credentials of upload targets which are stored
+// in Vault up to date. The rest of the worker expects most of the credentials
+// in files, so the secrets are written to a private directory in the runtime
+// directory of the worker and the paths of these files are used instead of
+// the ones from the configuration. The files only exist while a job runs.
+type vaultCredentials struct {
+	client *vault.Client
+	dir    string
+
+	awsPath string
+	awsFile string
+
+	gcpPath string
+	gcpFile string
+
+	azurePath   string
+	azureConfig *AzureConfiguration
+
+	containersPath     string
+	containersFile     string
+	containersRegistry string
+}
+
+func newVaultCredentials(client *vault.Client, runtimeDir string) (*vaultCredentials, error) {
+	// only accessible by the worker
+	dir, err := os.MkdirTemp(runtimeDir, "vault-")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create directory for vault credentials: %v", err)
+	}
+
+	return &vaultCredentials{
+		client: client,
+		dir:    dir,
+	}, nil
+}
+
+// AWS reads the AWS credentials from the given path and returns the path of
+// the shared credentials file they are written to. The secret contains the
+// access_key, secret_key and optionally security_token keys, as returned by
+// the AWS secrets engine.
+func (v *vaultCredentials) AWS(path string) string {
+	v.awsPath = path
+	v.awsFile = filepath.Join(v.dir, "aws-credentials")
+	return v.awsFile
+}
+
+// GCP reads the GCP credentials from the given path and returns the path of
+// the file they are written to. The secret contains either the
+// private_key_data key as returned by the GCP secrets engine or the
+// credentials key with the JSON service account key.
+func (v *vaultCredentials) GCP(path string) string {
+	v.gcpPath = path
+	v.gcpFile = filepath.Join(v.dir, "gcp-credentials.json")
+	return v.gcpFile
+}
+
+// Azure reads the Azure credentials from the given path and keeps the ones in
+// the configuration up to date. The secret contains the client_id and
+// client_secret keys.
+func (v *vaultCredentials) Azure(path string, config *AzureConfiguration) {
+	v.azurePath = path
+	v.azureConfig = config
+}
+
+// Containers reads the container registry credentials from the given path
+// and returns the path of the auth file they are written to. The secret
+// contains the username and password keys and optionally the registry key,
+// the given registry is used otherwise.
+func (v *vaultCredentials) Containers(path, registry string) string {
+	v.containersPath = path
+	v.containersRegistry = registry
+	v.containersFile = filepath.Join(v.dir, "containers-auth.json")
+	return v.containersFile
+}
+
+// Refresh reads all the secrets from Vault, or from the cache if their
+// leases are still valid, and updates the credentials.
+func (v *vaultCredentials) Refresh() error {
+	if v.awsPath != "" {
+		secret, err := v.client.Read(v.awsPath)
+		if err != nil {
+			return fmt.Errorf("cannot read aws credentials from vault: %v", err)
+		}
+		creds := fmt.Sprintf("[default]\naws_access_key_id = %s\naws_secret_access_key = %s\n", secret.String("access_key"), secret.String("secret_key"))
+		if token := secret.String("security_token"); token != "" {
+			creds += fmt.Sprintf("aws_session_token = %s\n", token)
+		}
+		err = writeCredentialsFile(v.awsFile, []byte(creds))
+		if err != nil {
+			return err
+		}
+	}
+
+	if v.gcpPath != "" {
+		secret, err := v.client.Read(v.gcpPath)
+		if err != nil {
+			return fmt.Errorf("cannot read gcp credentials from vault: %v", err)
+		}
+		var creds []byte
+		if keyData := secret.String("private_key_data"); keyData != "" {
+			creds, err = base64.StdEncoding.DecodeString(keyData)
+			if err != nil {
+				return fmt.Errorf("cannot decode gcp credentials from vault: %v", err)
+			}
+		} else {
+			creds = []byte(secret.String("credentials"))
+		}
+		if len(creds) == 0 {
+			return fmt.Errorf("vault secret %s doesn't contain gcp credentials", v.gcpPath)
+		}
+		err = writeCredentialsFile(v.gcpFile, creds)
+		if err != nil {
+			return err
+		}
+	}
+
+	if v.azurePath != "" {
+		secret, err := v.client.Read(v.azurePath)
+		if err != nil {
+			return fmt.Errorf("cannot read azure credentials from vault: %v", err)
+		}
+		v.azureConfig.Creds = azure.NewCredentials(secret.String("client_id"), secret.String("client_secret"))
+	}
+
+	if v.containersPath != "" {
+		secret, err := v.client.Read(v.containersPath)
+		if err != nil {
+			return fmt.Errorf("cannot read container registry credentials from vault: %v", err)
+		}
+		registry := secret.String("registry")
+		if registry == "" {
+			registry = v.containersRegistry
+		}
+		if registry == "" {
+			return fmt.Errorf("no registry set for the container registry credentials in vault")
+		}
+		auth := base64.StdEncoding.EncodeToString([]byte(secret.String("username") + ":" + secret.String("password")))
+		authFile, err := json.Marshal(map[string]interface{}{
+			"auths": map[string]interface{}{
+				registry: map[string]string{"auth": auth},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		err = writeCredentialsFile(v.containersFile, authFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove removes the files the credentials were written to, the next
+// Refresh writes them again.
+func (v *vaultCredentials) Remove() error {
+	for _, file := range []string{v.awsFile, v.gcpFile, v.containersFile} {
+		if file == "" {
+			continue
+		}
+		err := os.Remove(file)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot remove credentials file: %v", err)
+		}
+	}
+	return nil
+}
+
+// Close removes the directory of the credentials.
+func (v *vaultCredentials) Close() error {
+	return os.RemoveAll(v.dir)
+}
+
+// removeOnSignal removes the directory of the credentials when the worker is
+// interrupted or terminated, and then lets the signal terminate it.
+func removeOnSignal(v *vaultCredentials) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		if err := v.Close(); err != nil {
+			logrus.Errorf("Removing the credentials from vault failed: %v", err)
+		}
+		signal.Reset(sig)
+		_ = syscall.Kill(os.Getpid(), sig.(syscall.Signal))
+	}()
+}
+
+// writeCredentialsFile atomically replaces the file, so a job never reads
+// half-written credentials.
+func writeCredentialsFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("cannot write credentials file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write credentials file: %v", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("cannot write credentials file: %v", err)
+	}
+	return nil
+}
+
+// readSecretFile returns the trimmed content of a file, or an empty string
+// if no path is given.
+func readSecretFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// VaultJobImpl writes the credentials stored in Vault before running the
+// wrapped job implementation and removes them once it finished.
+type VaultJobImpl struct {
+	Creds *vaultCredentials
+	Impl  JobImplementation
+}
+
+func (impl *VaultJobImpl) Run(job worker.Job) error {
+	// Don't fail the job, not all of them need the credentials. The ones
+	// which do fail their uploads with a proper target error.
+	if err := impl.Creds.Refresh(); err != nil {
+		logrus.Errorf("Refreshing credentials from vault failed: %v", err)
+	}
+	defer func() {
+		if err := impl.Creds.Remove(); err != nil {
+			logrus.Errorf("Removing the credentials from vault failed: %v", err)
+		}
+	}()
+	return impl.Impl.Run(job)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/vault"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+type jobImplFunc func(job worker.Job) error
+
+func (f jobImplFunc) Run(job worker.Job) error {
+	return f(job)
+}
+
+func TestVaultCredentialsLifetime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"lease_id": "aws/creds/worker/1", "lease_duration": 3600, "data": {"access_key": "AKIA", "secret_key": "secret"}}`))
+	}))
+	defer server.Close()
+	client, err := vault.NewClient(vault.Config{Address: server.URL, Token: "token"})
+	require.NoError(t, err)
+
+	runtimeDir := t.TempDir()
+	creds, err := newVaultCredentials(client, runtimeDir)
+	require.NoError(t, err)
+	awsFile := creds.AWS("aws/creds/worker")
+
+	// the directory is private to the worker and in its runtime directory
+	info, err := os.Stat(creds.dir)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	require.Equal(t, runtimeDir, filepath.Dir(creds.dir))
+
+	// the credentials only exist while a job runs
+	impl := &VaultJobImpl{
+		Creds: creds,
+		Impl: jobImplFunc(func(job worker.Job) error {
+			data, err := os.ReadFile(awsFile)
+			require.NoError(t, err)
+			require.Contains(t, string(data), "aws_secret_access_key = secret")
+			return nil
+		}),
+	}
+	require.NoError(t, impl.Run(nil))
+	require.NoFileExists(t, awsFile)
+
+	require.NoError(t, creds.Close())
+	require.NoDirExists(t, creds.dir)
+}
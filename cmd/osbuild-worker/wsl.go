@@ -0,0 +1,140 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+const (
+	wslConfPath             = "etc/wsl.conf"
+	wslDistributionConfPath = "etc/wsl-distribution.conf"
+)
+
+// wslConf returns the content of /etc/wsl.conf of the options.
+func wslConf(options *target.WSLOptions) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[boot]\nsystemd=%t\n", options.Systemd)
+	if options.DefaultUser != "" {
+		fmt.Fprintf(&b, "\n[user]\ndefault=%s\n", options.DefaultUser)
+	}
+	if options.Conf != "" {
+		b.WriteString("\n")
+		b.WriteString(options.Conf)
+		if !strings.HasSuffix(options.Conf, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// wslDistributionConf returns the content of /etc/wsl-distribution.conf,
+// which marks the archive as a distribution bundle.
+func wslDistributionConf(options *target.WSLOptions) []byte {
+	var b strings.Builder
+	if options.DistributionName != "" {
+		fmt.Fprintf(&b, "[oobe]\ndefaultName=%s\n\n", options.DistributionName)
+	}
+	b.WriteString("[shortcut]\nenabled=true\n")
+	return []byte(b.String())
+}
+
+// customizeWSLArchive copies the gzipped archive of the root file system to
+// dst, replacing its /etc/wsl.conf and adding the configuration of the
+// bundle.
+func customizeWSLArchive(src, dst string, options *target.WSLOptions) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("error reading the WSL archive: %v", err)
+	}
+	tr := tar.NewReader(gr)
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	err = copyWSLArchive(tr, tw, options)
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gw.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+func copyWSLArchive(tr *tar.Reader, tw *tar.Writer, options *target.WSLOptions) error {
+	// the added files are named the way the files of the archive are
+	prefix := ""
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading the WSL archive: %v", err)
+		}
+		if strings.HasPrefix(hdr.Name, "./") {
+			prefix = "./"
+		}
+		switch strings.TrimPrefix(hdr.Name, "./") {
+		case wslConfPath, wslDistributionConfPath:
+			continue
+		}
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, tr)
+		if err != nil {
+			return err
+		}
+	}
+
+	files := map[string][]byte{wslConfPath: wslConf(options)}
+	if options.Bundle {
+		files[wslDistributionConfPath] = wslDistributionConf(options)
+	}
+	for _, name := range []string{wslConfPath, wslDistributionConfPath} {
+		data, ok := files[name]
+		if !ok {
+			continue
+		}
+		err := tw.WriteHeader(&tar.Header{
+			Name:     prefix + name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			ModTime:  time.Now(),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
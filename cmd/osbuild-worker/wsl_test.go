@@ -0,0 +1,71 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+func TestWSLConf(t *testing.T) {
+	require.Equal(t, "[boot]\nsystemd=false\n", string(wslConf(&target.WSLOptions{})))
+	require.Equal(t, "[boot]\nsystemd=true\n\n[user]\ndefault=user1\n\n[network]\nhostname=rhel\n", string(wslConf(&target.WSLOptions{
+		Systemd:     true,
+		DefaultUser: "user1",
+		Conf:        "[network]\nhostname=rhel",
+	})))
+	require.Equal(t, "[oobe]\ndefaultName=RHEL\n\n[shortcut]\nenabled=true\n", string(wslDistributionConf(&target.WSLOptions{DistributionName: "RHEL"})))
+}
+
+func TestCustomizeWSLArchive(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "disk.tar.gz")
+	f, err := os.Create(src)
+	require.NoError(t, err)
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for name, content := range map[string]string{
+		"./etc/os-release": "NAME=RHEL\n",
+		"./etc/wsl.conf":   "[boot]\nsystemd=true\n",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	require.NoError(t, f.Close())
+
+	dst := filepath.Join(dir, "disk.wsl")
+	options := &target.WSLOptions{DefaultUser: "user1", Bundle: true}
+	require.NoError(t, customizeWSLArchive(src, dst, options))
+
+	out, err := os.Open(dst)
+	require.NoError(t, err)
+	defer out.Close()
+	gr, err := gzip.NewReader(out)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+	require.Equal(t, map[string]string{
+		"./etc/os-release":            "NAME=RHEL\n",
+		"./etc/wsl.conf":              string(wslConf(options)),
+		"./etc/wsl-distribution.conf": string(wslDistributionConf(options)),
+	}, files)
+}
@@ -22,6 +22,9 @@ type Blueprint struct {
 	Containers     []Container     `json:"containers,omitempty" toml:"containers,omitempty"`
 	Customizations *Customizations `json:"customizations,omitempty" toml:"customizations"`
 	Distro         string          `json:"distro" toml:"distro"`
+	// Minimal images are depsolved without the weak dependencies of their
+	// packages
+	Minimal bool `json:"minimal,omitempty" toml:"minimal,omitempty"`
 }
 
 type Change struct {
@@ -346,6 +349,7 @@ func Convert(bp Blueprint) iblueprint.Blueprint {
 		Containers:     containers,
 		Customizations: customizations,
 		Distro:         bp.Distro,
+		Minimal:        bp.Minimal,
 	}
 
 	return ibp
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -22,6 +24,10 @@ type AWS struct {
 	uploader *s3manager.Uploader
 	ec2      *ec2.EC2
 	s3       *s3.S3
+	// AWS Marketplace Catalog API
+	marketplace *client.Client
+	// AWS Key Management Service API
+	kms *client.Client
 }
 
 // Create a new session from the credentials and the region and returns an *AWS object initialized with it.
@@ -36,9 +42,11 @@ func newAwsFromCreds(creds *credentials.Credentials, region string) (*AWS, error
 	}
 
 	return &AWS{
-		uploader: s3manager.NewUploader(sess),
-		ec2:      ec2.New(sess),
-		s3:       s3.New(sess),
+		uploader:    s3manager.NewUploader(sess),
+		ec2:         ec2.New(sess),
+		s3:          s3.New(sess),
+		marketplace: newMarketplaceCatalog(sess),
+		kms:         newKMS(sess),
 	}, nil
 }
 
@@ -67,9 +75,9 @@ func NewDefault(region string) (*AWS, error) {
 }
 
 // Create a new session from the credentials and the region and returns an *AWS object initialized with it.
-func newAwsFromCredsWithEndpoint(creds *credentials.Credentials, region, endpoint, caBundle string, skipSSLVerification bool) (*AWS, error) {
+func newAwsFromCredsWithEndpoint(creds *credentials.Credentials, region, endpoint, caBundle string, skipSSLVerification, pathStyle bool) (*AWS, error) {
 	// Create a Session with a custom region
-	s3ForcePathStyle := true
+	s3ForcePathStyle := pathStyle
 	sessionOptions := session.Options{
 		Config: aws.Config{
 			Credentials:      creds,
@@ -102,15 +110,18 @@ func newAwsFromCredsWithEndpoint(creds *credentials.Credentials, region, endpoin
 	}
 
 	return &AWS{
-		uploader: s3manager.NewUploader(sess),
-		ec2:      ec2.New(sess),
-		s3:       s3.New(sess),
+		uploader:    s3manager.NewUploader(sess),
+		ec2:         ec2.New(sess),
+		s3:          s3.New(sess),
+		marketplace: newMarketplaceCatalog(sess),
+		kms:         newKMS(sess),
 	}, nil
 }
 
 // Initialize a new AWS object targeting a specific endpoint from individual bits. SessionToken is optional
-func NewForEndpoint(endpoint, region, accessKeyID, accessKey, sessionToken, caBundle string, skipSSLVerification bool) (*AWS, error) {
-	return newAwsFromCredsWithEndpoint(credentials.NewStaticCredentials(accessKeyID, accessKey, sessionToken), region, endpoint, caBundle, skipSSLVerification)
+// Buckets are addressed in the path of the URLs if pathStyle is set, in their host otherwise.
+func NewForEndpoint(endpoint, region, accessKeyID, accessKey, sessionToken, caBundle string, skipSSLVerification, pathStyle bool) (*AWS, error) {
+	return newAwsFromCredsWithEndpoint(credentials.NewStaticCredentials(accessKeyID, accessKey, sessionToken), region, endpoint, caBundle, skipSSLVerification, pathStyle)
 }
 
 // Initializes a new AWS object targeting a specific endpoint with the credentials info found at filename's location.
@@ -122,8 +133,8 @@ func NewForEndpoint(endpoint, region, accessKeyID, accessKey, sessionToken, caBu
 // If filename is empty the underlying function will look for the
 // "AWS_SHARED_CREDENTIALS_FILE" env variable or will default to
 // $HOME/.aws/credentials.
-func NewForEndpointFromFile(filename, endpoint, region, caBundle string, skipSSLVerification bool) (*AWS, error) {
-	return newAwsFromCredsWithEndpoint(credentials.NewSharedCredentials(filename, "default"), region, endpoint, caBundle, skipSSLVerification)
+func NewForEndpointFromFile(filename, endpoint, region, caBundle string, skipSSLVerification, pathStyle bool) (*AWS, error) {
+	return newAwsFromCredsWithEndpoint(credentials.NewSharedCredentials(filename, "default"), region, endpoint, caBundle, skipSSLVerification, pathStyle)
 }
 
 func (a *AWS) Upload(filename, bucket, key string) (*s3manager.UploadOutput, error) {
@@ -140,6 +151,8 @@ func (a *AWS) Upload(filename, bucket, key string) (*s3manager.UploadOutput, err
 	}()
 
 	logrus.Infof("[AWS] 🚀 Uploading image to S3: %s/%s", bucket, key)
+	// the SDK sends the MD5 checksum of every part, S3 rejects the parts
+	// whose data doesn't match
 	return a.uploader.Upload(
 		&s3manager.UploadInput{
 			Bucket: aws.String(bucket),
@@ -206,42 +219,51 @@ func WaitUntilImportSnapshotTaskCompletedWithContext(c *ec2.EC2, ctx aws.Context
 	return w.WaitWithContext(ctx)
 }
 
-// Register is a function that imports a snapshot, waits for the snapshot to
-// fully import, tags the snapshot, cleans up the image in S3, and registers
-// an AMI in AWS.
-// The caller can optionally specify the boot mode of the AMI. If the boot
-// mode is not specified, then the instances launched from this AMI use the
-// default boot mode value of the instance type.
-func (a *AWS) Register(name, bucket, key string, shareWith []string, rpmArch string, bootMode *string) (*string, error) {
-	rpmArchToEC2Arch := map[string]string{
-		"x86_64":  "x86_64",
-		"aarch64": "arm64",
-	}
-
-	ec2Arch, validArch := rpmArchToEC2Arch[rpmArch]
-	if !validArch {
-		return nil, fmt.Errorf("ec2 doesn't support the following arch: %s", rpmArch)
+// ImportSnapshot is a function that imports a snapshot, waits for the
+// snapshot to fully import, tags the snapshot and cleans up the image in S3.
+// The snapshot is shared with the given accounts.
+// If kmsKeyARN is set, the snapshot is encrypted with the customer managed
+// key, and the accounts it's shared with are granted access to the key.
+func (a *AWS) ImportSnapshot(name, bucket, key string, shareWith []string, kmsKeyARN string) (*string, error) {
+	snapshotID, err := a.importSnapshot(name, bucket, key, kmsKeyARN)
+	if err != nil {
+		return nil, err
 	}
 
-	if bootMode != nil {
-		if !slices.Contains(ec2.BootModeValues_Values(), *bootMode) {
-			return nil, fmt.Errorf("ec2 doesn't support the following boot mode: %s", *bootMode)
+	if len(shareWith) > 0 {
+		err = a.shareSnapshot(snapshotID, shareWith)
+		if err != nil {
+			return nil, err
+		}
+		if kmsKeyARN != "" {
+			err = a.grantKMSKey(kmsKeyARN, shareWith)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
+	return snapshotID, nil
+}
+
+func (a *AWS) importSnapshot(name, bucket, key, kmsKeyARN string) (*string, error) {
 	logrus.Infof("[AWS] 📥 Importing snapshot from image: %s/%s", bucket, key)
 	snapshotDescription := fmt.Sprintf("Image Builder AWS Import of %s", name)
-	importTaskOutput, err := a.ec2.ImportSnapshot(
-		&ec2.ImportSnapshotInput{
-			Description: aws.String(snapshotDescription),
-			DiskContainer: &ec2.SnapshotDiskContainer{
-				UserBucket: &ec2.UserBucket{
-					S3Bucket: aws.String(bucket),
-					S3Key:    aws.String(key),
-				},
+	importInput := &ec2.ImportSnapshotInput{
+		Description: aws.String(snapshotDescription),
+		DiskContainer: &ec2.SnapshotDiskContainer{
+			UserBucket: &ec2.UserBucket{
+				S3Bucket: aws.String(bucket),
+				S3Key:    aws.String(key),
 			},
 		},
-	)
+	}
+	if kmsKeyARN != "" {
+		logrus.Infof("[AWS] 🔐 Encrypting snapshot with KMS key: %s", kmsKeyARN)
+		importInput.Encrypted = aws.Bool(true)
+		importInput.KmsKeyId = aws.String(kmsKeyARN)
+	}
+	importTaskOutput, err := a.ec2.ImportSnapshot(importInput)
 	if err != nil {
 		logrus.Warnf("[AWS] error importing snapshot: %s", err)
 		return nil, err
@@ -300,33 +322,96 @@ func (a *AWS) Register(name, bucket, key string, shareWith []string, rpmArch str
 		return nil, err
 	}
 
-	logrus.Infof("[AWS] 📋 Registering AMI from imported snapshot: %s", *snapshotID)
-	registerOutput, err := a.ec2.RegisterImage(
-		&ec2.RegisterImageInput{
-			Architecture:       aws.String(ec2Arch),
-			BootMode:           bootMode,
-			VirtualizationType: aws.String("hvm"),
-			Name:               aws.String(name),
-			RootDeviceName:     aws.String("/dev/sda1"),
-			EnaSupport:         aws.Bool(true),
-			BlockDeviceMappings: []*ec2.BlockDeviceMapping{
-				{
-					DeviceName: aws.String("/dev/sda1"),
-					Ebs: &ec2.EbsBlockDevice{
-						SnapshotId: snapshotID,
-					},
+	return snapshotID, nil
+}
+
+// RegisterOptions are the optional parameters of registered AMIs.
+type RegisterOptions struct {
+	// Boot mode of the AMI, one of ec2.BootModeValues_Values(). If it's not
+	// specified, then the instances launched from the AMI use the default
+	// boot mode value of the instance type.
+	BootMode *string
+	// If set, the snapshot and thus the AMI are encrypted with the customer
+	// managed key, see ImportSnapshot.
+	KMSKeyARN string
+	// Tags of the AMI and its snapshot, in addition to the Name tag
+	Tags map[string]string
+	// Set to ec2.ImdsSupportValuesV20 to require instances launched from the
+	// AMI to use IMDSv2
+	IMDSSupport *string
+	// Elastic Network Adapter support, enabled if not specified
+	ENASupport *bool
+	// Enhanced networking with the Intel 82599 VF interface
+	SriovNetSupport bool
+}
+
+// Register is a function that imports a snapshot, waits for the snapshot to
+// fully import, tags the snapshot, cleans up the image in S3, and registers
+// an AMI in AWS.
+func (a *AWS) Register(name, bucket, key string, shareWith []string, rpmArch string, options RegisterOptions) (*string, error) {
+	rpmArchToEC2Arch := map[string]string{
+		"x86_64":  "x86_64",
+		"aarch64": "arm64",
+	}
+
+	ec2Arch, validArch := rpmArchToEC2Arch[rpmArch]
+	if !validArch {
+		return nil, fmt.Errorf("ec2 doesn't support the following arch: %s", rpmArch)
+	}
+
+	if options.BootMode != nil {
+		if !slices.Contains(ec2.BootModeValues_Values(), *options.BootMode) {
+			return nil, fmt.Errorf("ec2 doesn't support the following boot mode: %s", *options.BootMode)
+		}
+	}
+
+	if options.IMDSSupport != nil {
+		if !slices.Contains(ec2.ImdsSupportValues_Values(), *options.IMDSSupport) {
+			return nil, fmt.Errorf("ec2 doesn't support the following IMDS support value: %s", *options.IMDSSupport)
+		}
+	}
+
+	snapshotID, err := a.importSnapshot(name, bucket, key, options.KMSKeyARN)
+	if err != nil {
+		return nil, err
+	}
+
+	registerInput := &ec2.RegisterImageInput{
+		Architecture:       aws.String(ec2Arch),
+		BootMode:           options.BootMode,
+		VirtualizationType: aws.String("hvm"),
+		Name:               aws.String(name),
+		RootDeviceName:     aws.String("/dev/sda1"),
+		EnaSupport:         aws.Bool(true),
+		ImdsSupport:        options.IMDSSupport,
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+			{
+				DeviceName: aws.String("/dev/sda1"),
+				Ebs: &ec2.EbsBlockDevice{
+					SnapshotId: snapshotID,
 				},
 			},
 		},
-	)
+	}
+	if options.ENASupport != nil {
+		registerInput.EnaSupport = options.ENASupport
+	}
+	if options.SriovNetSupport {
+		// "simple" is the only supported value
+		registerInput.SriovNetSupport = aws.String("simple")
+	}
+
+	logrus.Infof("[AWS] 📋 Registering AMI from imported snapshot: %s", *snapshotID)
+	registerOutput, err := a.ec2.RegisterImage(registerInput)
 	if err != nil {
 		return nil, err
 	}
 
 	logrus.Infof("[AWS] 🎉 AMI registered: %s", *registerOutput.ImageId)
 
-	// Tag the image with the image name.
-	req, _ = a.ec2.CreateTagsRequest(
+	// Tag the image with the image name and the requested tags, the latter
+	// are added to the snapshot as well.
+	req, _ := a.ec2.CreateTagsRequest(
 		&ec2.CreateTagsInput{
 			Resources: []*string{registerOutput.ImageId},
 			Tags: []*ec2.Tag{
@@ -342,6 +427,13 @@ func (a *AWS) Register(name, bucket, key string, shareWith []string, rpmArch str
 		return nil, err
 	}
 
+	if len(options.Tags) > 0 {
+		err = a.tagResources(options.Tags, registerOutput.ImageId, snapshotID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(shareWith) > 0 {
 		err = a.shareSnapshot(snapshotID, shareWith)
 		if err != nil {
@@ -351,20 +443,55 @@ func (a *AWS) Register(name, bucket, key string, shareWith []string, rpmArch str
 		if err != nil {
 			return nil, err
 		}
+		if options.KMSKeyARN != "" {
+			err = a.grantKMSKey(options.KMSKeyARN, shareWith)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return registerOutput.ImageId, nil
 }
 
+// tagResources adds the tags to the resources, sorted by their key so the
+// requests are reproducible.
+func (a *AWS) tagResources(tags map[string]string, resources ...*string) error {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ec2Tags := make([]*ec2.Tag, 0, len(keys))
+	for _, key := range keys {
+		ec2Tags = append(ec2Tags, &ec2.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tags[key]),
+		})
+	}
+	_, err := a.ec2.CreateTags(&ec2.CreateTagsInput{
+		Resources: resources,
+		Tags:      ec2Tags,
+	})
+	return err
+}
+
 // target region is determined by the region configured in the aws session
-func (a *AWS) CopyImage(name, ami, sourceRegion string) (string, error) {
-	result, err := a.ec2.CopyImage(
-		&ec2.CopyImageInput{
-			Name:          aws.String(name),
-			SourceImageId: aws.String(ami),
-			SourceRegion:  aws.String(sourceRegion),
-		},
-	)
+// If kmsKeyARN is set, the copy is encrypted with the customer managed key,
+// which has to be in the target region. Copies of encrypted AMIs are always
+// encrypted, with the default EBS key of the region if kmsKeyARN isn't set.
+func (a *AWS) CopyImage(name, ami, sourceRegion, kmsKeyARN string) (string, error) {
+	copyInput := &ec2.CopyImageInput{
+		Name:          aws.String(name),
+		SourceImageId: aws.String(ami),
+		SourceRegion:  aws.String(sourceRegion),
+	}
+	if kmsKeyARN != "" {
+		copyInput.Encrypted = aws.Bool(true)
+		copyInput.KmsKeyId = aws.String(kmsKeyARN)
+	}
+	result, err := a.ec2.CopyImage(copyInput)
 	if err != nil {
 		return "", err
 	}
@@ -450,7 +577,10 @@ func (a *AWS) CopyImage(name, ami, sourceRegion string) (string, error) {
 	return *result.ImageId, nil
 }
 
-func (a *AWS) ShareImage(ami string, userIds []string) error {
+// ShareImage shares the AMI and its snapshots with the accounts. If kmsKeyARN
+// is set, the AMI is encrypted with the customer managed key and the accounts
+// are granted access to it.
+func (a *AWS) ShareImage(ami string, userIds []string, kmsKeyARN string) error {
 	imgs, err := a.ec2.DescribeImages(
 		&ec2.DescribeImagesInput{
 			ImageIds: []*string{aws.String(ami)},
@@ -474,6 +604,9 @@ func (a *AWS) ShareImage(ami string, userIds []string) error {
 	if err != nil {
 		return err
 	}
+	if kmsKeyARN != "" {
+		return a.grantKMSKey(kmsKeyARN, userIds)
+	}
 	return nil
 }
 
@@ -579,13 +712,21 @@ func (a *AWS) DescribeImagesByTag(tagKey, tagValue string) ([]*ec2.Image, error)
 	return imgs.Images, err
 }
 
-func (a *AWS) S3ObjectPresignedURL(bucket, objectKey string) (string, error) {
+// MaxPresignedURLExpiry is the maximum lifetime of presigned URLs
+const MaxPresignedURLExpiry = 7 * 24 * time.Hour
+
+// S3ObjectPresignedURL returns a URL of the object valid for the duration,
+// MaxPresignedURLExpiry if it's 0.
+func (a *AWS) S3ObjectPresignedURL(bucket, objectKey string, expiry time.Duration) (string, error) {
+	if expiry == 0 {
+		expiry = MaxPresignedURLExpiry
+	}
 	logrus.Infof("[AWS] 📋 Generating Presigned URL for S3 object %s/%s", bucket, objectKey)
 	req, _ := a.s3.GetObjectRequest(&s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(objectKey),
 	})
-	url, err := req.Presign(7 * 24 * time.Hour) // maximum allowed
+	url, err := req.Presign(expiry)
 	if err != nil {
 		return "", err
 	}
@@ -608,6 +749,18 @@ func (a *AWS) MarkS3ObjectAsPublic(bucket, objectKey string) error {
 	return nil
 }
 
+// SetS3ObjectACL applies a canned ACL, one of s3.ObjectCannedACL_Values(), to
+// the object.
+func (a *AWS) SetS3ObjectACL(bucket, objectKey, acl string) error {
+	logrus.Infof("[AWS] 🔐 Setting ACL %s of S3 object %s/%s", acl, bucket, objectKey)
+	_, err := a.s3.PutObjectAcl(&s3.PutObjectAclInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(objectKey),
+		ACL:    aws.String(acl),
+	})
+	return err
+}
+
 func (a *AWS) Regions() ([]string, error) {
 	out, err := a.ec2.DescribeRegions(&ec2.DescribeRegionsInput{})
 	if err != nil {
@@ -0,0 +1,152 @@
+package awscloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol"
+	"github.com/aws/aws-sdk-go/private/protocol/jsonrpc"
+	"github.com/sirupsen/logrus"
+)
+
+// The SDK service of AWS KMS isn't vendored either, this is a minimal client
+// of the operations needed to share AMIs encrypted with a customer managed
+// key, set up the same way the generated clients are.
+const kmsEndpointsID = "kms"
+
+// Operations the accounts an encrypted AMI is shared with need to launch
+// instances from it, see
+// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/sharingamis-explicit.html
+var kmsGrantOperations = []string{
+	"Decrypt",
+	"DescribeKey",
+	"CreateGrant",
+	"GenerateDataKeyWithoutPlaintext",
+	"ReEncryptFrom",
+	"ReEncryptTo",
+}
+
+func newKMS(p client.ConfigProvider) *client.Client {
+	c := p.ClientConfig(kmsEndpointsID)
+	svc := client.New(
+		*c.Config,
+		metadata.ClientInfo{
+			ServiceName:    "kms",
+			ServiceID:      "KMS",
+			SigningName:    c.SigningName,
+			SigningRegion:  c.SigningRegion,
+			PartitionID:    c.PartitionID,
+			Endpoint:       c.Endpoint,
+			APIVersion:     "2014-11-01",
+			ResolvedRegion: c.ResolvedRegion,
+			JSONVersion:    "1.1",
+			TargetPrefix:   "TrentService",
+		},
+		c.Handlers,
+	)
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(jsonrpc.BuildHandler)
+	svc.Handlers.Unmarshal.PushBackNamed(jsonrpc.UnmarshalHandler)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(jsonrpc.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBackNamed(
+		protocol.NewUnmarshalErrorHandler(jsonrpc.NewUnmarshalTypedError(nil)).NamedHandler(),
+	)
+	return svc
+}
+
+type describeKeyInput struct {
+	_ struct{} `type:"structure"`
+
+	KeyId *string `type:"string"`
+}
+
+type kmsKeyMetadata struct {
+	_ struct{} `type:"structure"`
+
+	Arn        *string `type:"string"`
+	KeyManager *string `type:"string"`
+}
+
+type describeKeyOutput struct {
+	_ struct{} `type:"structure"`
+
+	KeyMetadata *kmsKeyMetadata `type:"structure"`
+}
+
+type createGrantInput struct {
+	_ struct{} `type:"structure"`
+
+	GranteePrincipal *string   `type:"string"`
+	KeyId            *string   `type:"string"`
+	Name             *string   `type:"string"`
+	Operations       []*string `type:"list"`
+}
+
+type createGrantOutput struct {
+	_ struct{} `type:"structure"`
+
+	GrantId *string `type:"string"`
+}
+
+// KMSKeyARN returns the ARN of a KMS key given by its ID, ARN or alias. Only
+// customer managed keys are accepted, AMIs encrypted with AWS managed keys
+// can't be shared with other accounts.
+func (a *AWS) KMSKeyARN(keyID string) (string, error) {
+	output := &describeKeyOutput{}
+	req := a.kms.NewRequest(&request.Operation{
+		Name:       "DescribeKey",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}, &describeKeyInput{KeyId: aws.String(keyID)}, output)
+	err := req.Send()
+	if err != nil {
+		return "", err
+	}
+	if output.KeyMetadata == nil || output.KeyMetadata.Arn == nil {
+		return "", fmt.Errorf("no metadata of KMS key %s returned", keyID)
+	}
+	if aws.StringValue(output.KeyMetadata.KeyManager) != "CUSTOMER" {
+		return "", fmt.Errorf("KMS key %s isn't a customer managed key", keyID)
+	}
+	return *output.KeyMetadata.Arn, nil
+}
+
+// grantKMSKey allows the accounts to use the key to launch instances from
+// AMIs encrypted with it.
+func (a *AWS) grantKMSKey(keyARN string, accounts []string) error {
+	// the grantee is in the partition of the key, e.g. arn:aws-us-gov:kms:...
+	parts := strings.SplitN(keyARN, ":", 3)
+	if len(parts) < 3 || parts[0] != "arn" {
+		return fmt.Errorf("invalid KMS key ARN: %s", keyARN)
+	}
+	partition := parts[1]
+
+	operations := make([]*string, len(kmsGrantOperations))
+	for i := range kmsGrantOperations {
+		operations[i] = &kmsGrantOperations[i]
+	}
+	for _, account := range accounts {
+		logrus.Infof("[AWS] 🔑 Granting account %s access to KMS key %s", account, keyARN)
+		req := a.kms.NewRequest(&request.Operation{
+			Name:       "CreateGrant",
+			HTTPMethod: "POST",
+			HTTPPath:   "/",
+		}, &createGrantInput{
+			GranteePrincipal: aws.String(fmt.Sprintf("arn:%s:iam::%s:root", partition, account)),
+			KeyId:            aws.String(keyARN),
+			Name:             aws.String("composer-ami-share"),
+			Operations:       operations,
+		}, &createGrantOutput{})
+		err := req.Send()
+		if err != nil {
+			logrus.Warnf("[AWS] 🔑 Error granting access to KMS key: %v", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package awscloud
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol"
+	"github.com/aws/aws-sdk-go/private/protocol/restjson"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// The SDK service of the AWS Marketplace Catalog API isn't vendored, this is
+// a minimal client of the two operations composer needs, set up the same way
+// the generated clients are.
+const (
+	marketplaceCatalogEndpointsID = "catalog.marketplace"
+	marketplaceCatalog            = "AWSMarketplace"
+	amiProductEntityType          = "AmiProduct@1.0"
+)
+
+// Statuses of a change set, the change set is finished in all but
+// PREPARING and APPLYING.
+const (
+	ChangeSetStatusPreparing = "PREPARING"
+	ChangeSetStatusApplying  = "APPLYING"
+	ChangeSetStatusSucceeded = "SUCCEEDED"
+	ChangeSetStatusCancelled = "CANCELLED"
+	ChangeSetStatusFailed    = "FAILED"
+)
+
+func newMarketplaceCatalog(p client.ConfigProvider) *client.Client {
+	c := p.ClientConfig(marketplaceCatalogEndpointsID)
+	if c.SigningNameDerived || len(c.SigningName) == 0 {
+		c.SigningName = "aws-marketplace"
+	}
+	svc := client.New(
+		*c.Config,
+		metadata.ClientInfo{
+			ServiceName:    "Marketplace Catalog",
+			ServiceID:      "Marketplace Catalog",
+			SigningName:    c.SigningName,
+			SigningRegion:  c.SigningRegion,
+			PartitionID:    c.PartitionID,
+			Endpoint:       c.Endpoint,
+			APIVersion:     "2018-09-17",
+			ResolvedRegion: c.ResolvedRegion,
+		},
+		c.Handlers,
+	)
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(restjson.BuildHandler)
+	svc.Handlers.Unmarshal.PushBackNamed(restjson.UnmarshalHandler)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(restjson.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBackNamed(
+		protocol.NewUnmarshalErrorHandler(restjson.NewUnmarshalTypedError(nil)).NamedHandler(),
+	)
+	return svc
+}
+
+type marketplaceEntity struct {
+	_ struct{} `type:"structure"`
+
+	Identifier *string `type:"string"`
+	Type       *string `type:"string"`
+}
+
+type marketplaceChange struct {
+	_ struct{} `type:"structure"`
+
+	ChangeType *string            `type:"string"`
+	Details    *string            `type:"string"`
+	Entity     *marketplaceEntity `type:"structure"`
+}
+
+type startChangeSetInput struct {
+	_ struct{} `type:"structure"`
+
+	Catalog            *string              `type:"string"`
+	ChangeSet          []*marketplaceChange `type:"list"`
+	ChangeSetName      *string              `type:"string"`
+	ClientRequestToken *string              `type:"string"`
+}
+
+type startChangeSetOutput struct {
+	_ struct{} `type:"structure"`
+
+	ChangeSetArn *string `type:"string"`
+	ChangeSetId  *string `type:"string"`
+}
+
+type describeChangeSetInput struct {
+	_ struct{} `type:"structure" nopayload:"true"`
+
+	Catalog     *string `location:"querystring" locationName:"catalog" type:"string"`
+	ChangeSetId *string `location:"querystring" locationName:"changeSetId" type:"string"`
+}
+
+type changeSetErrorDetail struct {
+	_ struct{} `type:"structure"`
+
+	ErrorCode    *string `type:"string"`
+	ErrorMessage *string `type:"string"`
+}
+
+type changeSummary struct {
+	_ struct{} `type:"structure"`
+
+	ChangeType      *string                 `type:"string"`
+	ErrorDetailList []*changeSetErrorDetail `type:"list"`
+}
+
+type describeChangeSetOutput struct {
+	_ struct{} `type:"structure"`
+
+	ChangeSet          []*changeSummary `type:"list"`
+	ChangeSetArn       *string          `type:"string"`
+	ChangeSetId        *string          `type:"string"`
+	FailureCode        *string          `type:"string"`
+	FailureDescription *string          `type:"string"`
+	Status             *string          `type:"string"`
+}
+
+// AMIProductVersion describes a new version of an AMI product in AWS
+// Marketplace.
+type AMIProductVersion struct {
+	ProductID               string
+	VersionTitle            string
+	ReleaseNotes            string
+	Ami                     string
+	AccessRoleARN           string
+	UserName                string
+	OperatingSystemName     string
+	OperatingSystemVersion  string
+	UsageInstructions       string
+	RecommendedInstanceType string
+	SecurityGroups          []AMIProductSecurityGroup
+}
+
+// AMIProductSecurityGroup is an ingress rule recommended to the buyers of
+// the product.
+type AMIProductSecurityGroup struct {
+	IpProtocol string   `json:"IpProtocol"`
+	FromPort   int      `json:"FromPort"`
+	ToPort     int      `json:"ToPort"`
+	IpRanges   []string `json:"IpRanges"`
+}
+
+// details of the AddDeliveryOptions change of an AMI product
+func (v AMIProductVersion) details() (string, error) {
+	type amiSource struct {
+		AmiId                  string
+		AccessRoleArn          string
+		UserName               string
+		OperatingSystemName    string
+		OperatingSystemVersion string
+	}
+	type amiDeliveryOptionDetails struct {
+		AmiSource               amiSource
+		UsageInstructions       string
+		RecommendedInstanceType string
+		SecurityGroups          []AMIProductSecurityGroup
+	}
+	type deliveryOption struct {
+		Details struct {
+			AmiDeliveryOptionDetails amiDeliveryOptionDetails
+		}
+	}
+	type version struct {
+		VersionTitle string
+		ReleaseNotes string
+	}
+
+	option := deliveryOption{}
+	option.Details.AmiDeliveryOptionDetails = amiDeliveryOptionDetails{
+		AmiSource: amiSource{
+			AmiId:                  v.Ami,
+			AccessRoleArn:          v.AccessRoleARN,
+			UserName:               v.UserName,
+			OperatingSystemName:    v.OperatingSystemName,
+			OperatingSystemVersion: v.OperatingSystemVersion,
+		},
+		UsageInstructions:       v.UsageInstructions,
+		RecommendedInstanceType: v.RecommendedInstanceType,
+		SecurityGroups:          v.SecurityGroups,
+	}
+	if option.Details.AmiDeliveryOptionDetails.SecurityGroups == nil {
+		option.Details.AmiDeliveryOptionDetails.SecurityGroups = []AMIProductSecurityGroup{}
+	}
+
+	details, err := json.Marshal(struct {
+		Version         version
+		DeliveryOptions []deliveryOption
+	}{
+		Version: version{
+			VersionTitle: v.VersionTitle,
+			ReleaseNotes: v.ReleaseNotes,
+		},
+		DeliveryOptions: []deliveryOption{option},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(details), nil
+}
+
+// ChangeSet is the state of a change set in the AWS Marketplace catalog.
+type ChangeSet struct {
+	ID     string
+	ARN    string
+	Status string
+	// the reason of the failure, only set if the change set failed
+	Failure string
+}
+
+// Finished returns whether the change set won't change its status anymore.
+func (cs *ChangeSet) Finished() bool {
+	return cs.Status != ChangeSetStatusPreparing && cs.Status != ChangeSetStatusApplying
+}
+
+// AddAMIProductVersion starts a change set adding a new version to an AMI
+// product. The AMI is ingested by AWS Marketplace, which assumes the access
+// role to copy it.
+func (a *AWS) AddAMIProductVersion(version AMIProductVersion) (*ChangeSet, error) {
+	details, err := version.details()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &startChangeSetInput{
+		Catalog: aws.String(marketplaceCatalog),
+		ChangeSet: []*marketplaceChange{
+			{
+				ChangeType: aws.String("AddDeliveryOptions"),
+				Details:    aws.String(details),
+				Entity: &marketplaceEntity{
+					Identifier: aws.String(version.ProductID),
+					Type:       aws.String(amiProductEntityType),
+				},
+			},
+		},
+		ChangeSetName:      aws.String(fmt.Sprintf("composer-%s", version.VersionTitle)),
+		ClientRequestToken: aws.String(uuid.New().String()),
+	}
+	output := &startChangeSetOutput{}
+	req := a.marketplace.NewRequest(&request.Operation{
+		Name:       "StartChangeSet",
+		HTTPMethod: "POST",
+		HTTPPath:   "/StartChangeSet",
+	}, input, output)
+	err = req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("[AWS] 🛒 Started change set %s adding version %s to product %s", aws.StringValue(output.ChangeSetId), version.VersionTitle, version.ProductID)
+	return &ChangeSet{
+		ID:     aws.StringValue(output.ChangeSetId),
+		ARN:    aws.StringValue(output.ChangeSetArn),
+		Status: ChangeSetStatusPreparing,
+	}, nil
+}
+
+// DescribeChangeSet returns the current state of a change set.
+func (a *AWS) DescribeChangeSet(id string) (*ChangeSet, error) {
+	output := &describeChangeSetOutput{}
+	req := a.marketplace.NewRequest(&request.Operation{
+		Name:       "DescribeChangeSet",
+		HTTPMethod: "GET",
+		HTTPPath:   "/DescribeChangeSet",
+	}, &describeChangeSetInput{
+		Catalog:     aws.String(marketplaceCatalog),
+		ChangeSetId: aws.String(id),
+	}, output)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &ChangeSet{
+		ID:     aws.StringValue(output.ChangeSetId),
+		ARN:    aws.StringValue(output.ChangeSetArn),
+		Status: aws.StringValue(output.Status),
+	}
+	if cs.Status == ChangeSetStatusFailed {
+		cs.Failure = aws.StringValue(output.FailureDescription)
+		for _, change := range output.ChangeSet {
+			for _, detail := range change.ErrorDetailList {
+				cs.Failure += fmt.Sprintf("; %s: %s", aws.StringValue(detail.ErrorCode), aws.StringValue(detail.ErrorMessage))
+			}
+		}
+	}
+	return cs, nil
+}
@@ -0,0 +1,163 @@
+package awscloud
+
+import (
+	"bytes"
+	"context"
+	// s3 uses MD5 hashes
+	/* #nosec G501 */
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/upload/resumable"
+)
+
+// S3 allows 10000 parts of an upload at most
+const s3MaxParts = 10000
+
+// UploadResumable uploads the file to S3 in a multipart upload whose state is
+// kept in the store, so an upload interrupted by a restart continues with the
+// parts which weren't uploaded yet. Returns the location of the object.
+func (a *AWS) UploadResumable(ctx context.Context, filename, bucket, key string, store *resumable.Store, options resumable.Options) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	stateKey := fmt.Sprintf("s3://%s/%s", bucket, key)
+	partSize := options.PartSizeFor(info.Size(), s3MaxParts)
+	state, err := store.Load(stateKey)
+	if err != nil {
+		return "", err
+	}
+	if state != nil && !state.Matches(info.Size(), partSize) {
+		logrus.Infof("[AWS] Image changed since the upload to %s was interrupted, starting over", stateKey)
+		a.abortMultipartUpload(bucket, key, state.UploadID)
+		state = nil
+	}
+	if state != nil {
+		state.Parts, err = a.uploadedParts(ctx, bucket, key, state)
+		if err != nil {
+			logrus.Infof("[AWS] Cannot resume the upload to %s, starting over: %v", stateKey, err)
+			state = nil
+		}
+	}
+	if state == nil {
+		logrus.Infof("[AWS] 🚀 Starting multipart upload of the image to S3: %s/%s", bucket, key)
+		upload, err := a.s3.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return "", fmt.Errorf("cannot create a multipart upload: %v", err)
+		}
+		state = resumable.NewState(info.Size(), partSize)
+		state.UploadID = aws.StringValue(upload.UploadId)
+		err = store.Save(stateKey, state)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		logrus.Infof("[AWS] 🚀 Resuming multipart upload of the image to S3: %s/%s", bucket, key)
+	}
+
+	err = resumable.UploadParts(ctx, store, stateKey, state, file, options, func(ctx context.Context, number int, offset int64, data []byte) (string, error) {
+		// S3 rejects the part if the data it received doesn't match
+		/* #nosec G401 */
+		sum := md5.Sum(data)
+		part, err := a.s3.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(bucket),
+			Key:        aws.String(key),
+			UploadId:   aws.String(state.UploadID),
+			PartNumber: aws.Int64(int64(number)),
+			Body:       bytes.NewReader(data),
+			ContentMD5: aws.String(base64.StdEncoding.EncodeToString(sum[:])),
+		})
+		if err != nil {
+			return "", err
+		}
+		return aws.StringValue(part.ETag), nil
+	})
+	if err != nil {
+		// the upload is kept, so it can be resumed
+		return "", err
+	}
+
+	completed := make([]*s3.CompletedPart, len(state.Parts))
+	for i, part := range state.Parts {
+		completed[i] = &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(int64(part.Number)),
+		}
+	}
+	result, err := a.s3.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(state.UploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot complete the multipart upload: %v", err)
+	}
+	err = store.Delete(stateKey)
+	if err != nil {
+		logrus.Warn(err)
+	}
+	return aws.StringValue(result.Location), nil
+}
+
+// uploadedParts returns the parts of the state which are still part of the
+// multipart upload in S3, it fails if the upload doesn't exist anymore.
+func (a *AWS) uploadedParts(ctx context.Context, bucket, key string, state *resumable.State) ([]resumable.Part, error) {
+	etags := make(map[int64]string)
+	err := a.s3.ListPartsPagesWithContext(ctx, &s3.ListPartsInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(state.UploadID),
+	}, func(page *s3.ListPartsOutput, lastPage bool) bool {
+		for _, part := range page.Parts {
+			etags[aws.Int64Value(part.PartNumber)] = aws.StringValue(part.ETag)
+		}
+		return true
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchUpload {
+			return nil, fmt.Errorf("multipart upload %s doesn't exist anymore", state.UploadID)
+		}
+		return nil, err
+	}
+
+	var parts []resumable.Part
+	for _, part := range state.Parts {
+		if strings.Trim(etags[int64(part.Number)], `"`) == strings.Trim(part.ETag, `"`) {
+			parts = append(parts, part)
+		}
+	}
+	return parts, nil
+}
+
+func (a *AWS) abortMultipartUpload(bucket, key, uploadID string) {
+	if uploadID == "" {
+		return
+	}
+	_, err := a.s3.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		logrus.Warnf("[AWS] Cannot abort multipart upload %s: %v", uploadID, err)
+	}
+}
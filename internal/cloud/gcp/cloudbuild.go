@@ -0,0 +1,147 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// The Cloud Build client library isn't vendored, the few calls needed to
+// run the image export tool are done with plain REST requests.
+const cloudBuildEndpoint = "https://cloudbuild.googleapis.com/v1"
+
+// The image export tool of Compute Engine, the same one
+// `gcloud compute images export` runs.
+const imageExportBuilder = "gcr.io/compute-image-tools/gce_vm_image_export:release"
+
+type cloudBuildStep struct {
+	Name string   `json:"name"`
+	Args []string `json:"args"`
+	Env  []string `json:"env,omitempty"`
+}
+
+type cloudBuild struct {
+	ID           string           `json:"id,omitempty"`
+	Status       string           `json:"status,omitempty"`
+	StatusDetail string           `json:"statusDetail,omitempty"`
+	LogURL       string           `json:"logUrl,omitempty"`
+	Steps        []cloudBuildStep `json:"steps"`
+	Timeout      string           `json:"timeout,omitempty"`
+	Tags         []string         `json:"tags,omitempty"`
+}
+
+type cloudBuildOperation struct {
+	Name     string `json:"name"`
+	Metadata struct {
+		Build cloudBuild `json:"build"`
+	} `json:"metadata"`
+}
+
+// imageExportBuild returns the build exporting the image to the gs:// URI.
+func imageExportBuild(projectID, imageName, destinationURI string, timeout time.Duration) cloudBuild {
+	return cloudBuild{
+		Steps: []cloudBuildStep{
+			{
+				Name: imageExportBuilder,
+				Args: []string{
+					fmt.Sprintf("-timeout=%ds", int(timeout.Seconds())),
+					fmt.Sprintf("-source_image=projects/%s/global/images/%s", projectID, imageName),
+					fmt.Sprintf("-destination_uri=%s", destinationURI),
+					"-client_id=api",
+				},
+				Env: []string{"BUILD_ID=$BUILD_ID"},
+			},
+		},
+		Timeout: fmt.Sprintf("%ds", int(timeout.Seconds())),
+		Tags:    []string{"gce-daisy", "gce-daisy-image-export"},
+	}
+}
+
+// cloudBuildFinished returns whether the build won't change its status
+// anymore.
+func cloudBuildFinished(status string) bool {
+	switch status {
+	case "STATUS_UNKNOWN", "PENDING", "QUEUED", "WORKING":
+		return false
+	default:
+		return true
+	}
+}
+
+func cloudBuildRequest(ctx context.Context, client *http.Client, method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, cloudBuildEndpoint+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("cloud build returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// ComputeImageExport exports the image to a gzipped tar archive in the Cloud
+// Storage bucket, the way `gcloud compute images export` does, and returns
+// the gs:// URI of the archive. The export runs in Cloud Build, which is
+// polled until the export finishes. The Cloud Build service account must be
+// allowed to create the temporary VM of the export and to write the object.
+//
+// Uses:
+//   - Cloud Build API
+func (g *GCP) ComputeImageExport(ctx context.Context, imageName, bucket, object string, timeout, pollInterval time.Duration) (string, error) {
+	client, _, err := htransport.NewClient(ctx, option.WithCredentials(g.creds))
+	if err != nil {
+		return "", fmt.Errorf("failed to get Cloud Build client: %w", err)
+	}
+
+	destinationURI := fmt.Sprintf("gs://%s/%s", bucket, object)
+	var op cloudBuildOperation
+	err = cloudBuildRequest(ctx, client, http.MethodPost, fmt.Sprintf("/projects/%s/builds", g.GetProjectID()),
+		imageExportBuild(g.GetProjectID(), imageName, destinationURI, timeout), &op)
+	if err != nil {
+		return "", fmt.Errorf("failed to start the image export: %w", err)
+	}
+
+	build := op.Metadata.Build
+	for !cloudBuildFinished(build.Status) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(pollInterval):
+		}
+		err = cloudBuildRequest(ctx, client, http.MethodGet, fmt.Sprintf("/projects/%s/builds/%s", g.GetProjectID(), op.Metadata.Build.ID), nil, &build)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the status of the image export: %w", err)
+		}
+	}
+
+	if build.Status != "SUCCESS" {
+		return "", fmt.Errorf("image export finished with status %s: %s (logs: %s)", build.Status, build.StatusDetail, build.LogURL)
+	}
+	return destinationURI, nil
+}
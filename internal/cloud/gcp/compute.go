@@ -63,6 +63,38 @@ func GuestOsFeaturesByDistro(distroName string) []*computepb.GuestOsFeature {
 	}
 }
 
+// GuestOsFeaturesWith returns the features extended with the features of the
+// given types, which aren't in the list yet.
+func GuestOsFeaturesWith(features []*computepb.GuestOsFeature, types ...string) []*computepb.GuestOsFeature {
+	result := append([]*computepb.GuestOsFeature{}, features...)
+	for _, featureType := range types {
+		found := false
+		for _, feature := range result {
+			if feature.GetType() == featureType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, &computepb.GuestOsFeature{Type: common.ToPtr(featureType)})
+		}
+	}
+	return result
+}
+
+// ComputeImageOptions are the optional properties of imported images.
+type ComputeImageOptions struct {
+	// Features supported by the Guest OS on the imported image
+	GuestOsFeatures []*computepb.GuestOsFeature
+	// Architecture of the image, one of computepb.Image_Architecture, not set
+	// if empty
+	Architecture string
+	// Image family the image belongs to
+	Family string
+	// URLs of the licenses applying to the image
+	Licenses []string
+}
+
 // ComputeImageInsert imports a previously uploaded archive with raw image into Compute Engine.
 //
 // The image must be RAW image named 'disk.raw' inside a gzip-ed tarball.
@@ -78,7 +110,7 @@ func GuestOsFeaturesByDistro(distroName string) []*computepb.GuestOsFeature {
 //	If not provided, the region of the used Storage object is used.
 //	See: https://cloud.google.com/storage/docs/locations
 //
-// guestOsFeatures - A list of features supported by the Guest OS on the imported image.
+// options - Optional properties of the image, e.g. the features supported by the Guest OS.
 //
 // Uses:
 //   - Compute Engine API
@@ -86,16 +118,16 @@ func (g *GCP) ComputeImageInsert(
 	ctx context.Context,
 	bucket, object, imageName string,
 	regions []string,
-	guestOsFeatures []*computepb.GuestOsFeature) (*computepb.Image, error) {
+	options ComputeImageOptions) (*computepb.Image, error) {
 	imagesClient, err := compute.NewImagesRESTClient(ctx, option.WithCredentials(g.creds))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get Compute Engine Images client: %v", err)
+		return nil, fmt.Errorf("failed to get Compute Engine Images client: %w", err)
 	}
 	defer imagesClient.Close()
 
 	operationsClient, err := compute.NewGlobalOperationsRESTClient(ctx, option.WithCredentials(g.creds))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get Compute Engine Operations client: %v", err)
+		return nil, fmt.Errorf("failed to get Compute Engine Operations client: %w", err)
 	}
 	defer operationsClient.Close()
 
@@ -104,17 +136,24 @@ func (g *GCP) ComputeImageInsert(
 		ImageResource: &computepb.Image{
 			Name:             &imageName,
 			StorageLocations: regions,
-			GuestOsFeatures:  guestOsFeatures,
+			GuestOsFeatures:  options.GuestOsFeatures,
+			Licenses:         options.Licenses,
 			RawDisk: &computepb.RawDisk{
 				ContainerType: common.ToPtr(computepb.RawDisk_TAR.String()),
 				Source:        common.ToPtr(fmt.Sprintf("https://storage.googleapis.com/%s/%s", bucket, object)),
 			},
 		},
 	}
+	if options.Architecture != "" {
+		imgInsertReq.ImageResource.Architecture = common.ToPtr(options.Architecture)
+	}
+	if options.Family != "" {
+		imgInsertReq.ImageResource.Family = common.ToPtr(options.Family)
+	}
 
 	operation, err := imagesClient.Insert(ctx, imgInsertReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert provided image into GCE: %v", err)
+		return nil, fmt.Errorf("failed to insert provided image into GCE: %w", err)
 	}
 
 	// wait for the operation to finish
@@ -127,7 +166,7 @@ func (g *GCP) ComputeImageInsert(
 
 		operationResource, err = operationsClient.Wait(ctx, waitOperationReq)
 		if err != nil {
-			return nil, fmt.Errorf("failed to wait for an Image Import operation: %v", err)
+			return nil, fmt.Errorf("failed to wait for an Image Import operation: %w", err)
 		}
 
 		// The operation finished
@@ -150,7 +189,7 @@ func (g *GCP) ComputeImageInsert(
 
 	image, err := imagesClient.Get(ctx, getImageReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get information about the imported Image: %v", err)
+		return nil, fmt.Errorf("failed to get information about the imported Image: %w", err)
 	}
 
 	return image, nil
@@ -184,7 +223,7 @@ func (g *GCP) ComputeImageURL(imageName string) string {
 func (g *GCP) ComputeImageShare(ctx context.Context, imageName string, shareWith []string) error {
 	imagesClient, err := compute.NewImagesRESTClient(ctx, option.WithCredentials(g.creds))
 	if err != nil {
-		return fmt.Errorf("failed to get Compute Engine Images client: %v", err)
+		return fmt.Errorf("failed to get Compute Engine Images client: %w", err)
 	}
 	defer imagesClient.Close()
 
@@ -198,7 +237,7 @@ func (g *GCP) ComputeImageShare(ctx context.Context, imageName string, shareWith
 	}
 	policy, err := imagesClient.GetIamPolicy(ctx, getIamPolicyReq)
 	if err != nil {
-		return fmt.Errorf("failed to get image's policy: %v", err)
+		return fmt.Errorf("failed to get image's policy: %w", err)
 	}
 
 	// Add new members, who can use the image
@@ -220,7 +259,7 @@ func (g *GCP) ComputeImageShare(ctx context.Context, imageName string, shareWith
 	}
 	_, err = imagesClient.SetIamPolicy(ctx, setIamPolicyReq)
 	if err != nil {
-		return fmt.Errorf("failed to set new image policy: %v", err)
+		return fmt.Errorf("failed to set new image policy: %w", err)
 	}
 
 	// Users won't see the shared image in their images.list requests, unless
@@ -253,7 +292,7 @@ func (g *GCP) ComputeImageShare(ctx context.Context, imageName string, shareWith
 func (g *GCP) ComputeImageDelete(ctx context.Context, name string) error {
 	imagesClient, err := compute.NewImagesRESTClient(ctx, option.WithCredentials(g.creds))
 	if err != nil {
-		return fmt.Errorf("failed to get Compute Engine Images client: %v", err)
+		return fmt.Errorf("failed to get Compute Engine Images client: %w", err)
 	}
 	defer imagesClient.Close()
 
@@ -273,7 +312,7 @@ func (g *GCP) ComputeImageDelete(ctx context.Context, name string) error {
 func (g *GCP) ComputeExecuteFunctionForImages(ctx context.Context, f func(*compute.ImageIterator) error) error {
 	imagesClient, err := compute.NewImagesRESTClient(ctx, option.WithCredentials(g.creds))
 	if err != nil {
-		return fmt.Errorf("failed to get Compute Engine Images client: %v", err)
+		return fmt.Errorf("failed to get Compute Engine Images client: %w", err)
 	}
 	defer imagesClient.Close()
 
@@ -5,12 +5,18 @@ import (
 	// gcp uses MD5 hashes
 	/* #nosec G501 */
 	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"hash/crc32"
 	"io"
+	"math"
 	"os"
 
 	"cloud.google.com/go/storage"
+	"github.com/sirupsen/logrus"
 	"google.golang.org/api/option"
+
+	"github.com/osbuild/osbuild-composer/internal/upload/resumable"
 )
 
 const (
@@ -32,14 +38,14 @@ const (
 func (g *GCP) StorageObjectUpload(ctx context.Context, filename, bucket, object string, metadata map[string]string) (*storage.ObjectAttrs, error) {
 	storageClient, err := storage.NewClient(ctx, option.WithCredentials(g.creds))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get Storage client: %v", err)
+		return nil, fmt.Errorf("failed to get Storage client: %w", err)
 	}
 	defer storageClient.Close()
 
 	// Open the image file
 	imageFile, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("cannot open the image: %v", err)
+		return nil, fmt.Errorf("cannot open the image: %w", err)
 	}
 	defer imageFile.Close()
 
@@ -48,11 +54,11 @@ func (g *GCP) StorageObjectUpload(ctx context.Context, filename, bucket, object
 	/* #nosec G401 */
 	imageFileHash := md5.New()
 	if _, err := io.Copy(imageFileHash, imageFile); err != nil {
-		return nil, fmt.Errorf("cannot create md5 of the image: %v", err)
+		return nil, fmt.Errorf("cannot create md5 of the image: %w", err)
 	}
 	// Move the cursor of opened file back to the start
 	if _, err := imageFile.Seek(0, 0); err != nil {
-		return nil, fmt.Errorf("cannot seek the image: %v", err)
+		return nil, fmt.Errorf("cannot seek the image: %w", err)
 	}
 
 	// Upload the image
@@ -68,12 +74,12 @@ func (g *GCP) StorageObjectUpload(ctx context.Context, filename, bucket, object
 	}
 
 	if _, err = io.Copy(wc, imageFile); err != nil {
-		return nil, fmt.Errorf("uploading the image failed: %v", err)
+		return nil, fmt.Errorf("uploading the image failed: %w", err)
 	}
 
 	// The object will not be available until Close has been called.
 	if err := wc.Close(); err != nil {
-		return nil, fmt.Errorf("Writer.Close: %v", err)
+		return nil, fmt.Errorf("Writer.Close: %w", err)
 	}
 
 	return wc.Attrs(), nil
@@ -86,14 +92,142 @@ func (g *GCP) StorageObjectUpload(ctx context.Context, filename, bucket, object
 func (g *GCP) StorageObjectDelete(ctx context.Context, bucket, object string) error {
 	storageClient, err := storage.NewClient(ctx, option.WithCredentials(g.creds))
 	if err != nil {
-		return fmt.Errorf("failed to get Storage client: %v", err)
+		return fmt.Errorf("failed to get Storage client: %w", err)
 	}
 	defer storageClient.Close()
 
 	objectHandle := storageClient.Bucket(bucket).Object(object)
 	if err = objectHandle.Delete(ctx); err != nil {
-		return fmt.Errorf("failed to delete image file object: %v", err)
+		return fmt.Errorf("failed to delete image file object: %w", err)
 	}
 
 	return nil
 }
+
+// GCS composes 32 objects at most in a single request
+const storageMaxComposeSources = 32
+
+// StorageObjectUploadResumable uploads an OS image the way StorageObjectUpload
+// does, but in parts kept in the store. Every part is uploaded as a temporary
+// object next to the final one, which is composed of them in the end. An
+// upload interrupted by a restart continues with the parts which weren't
+// uploaded yet. The CRC32C checksum of the image file and the composed object
+// are compared to verify the integrity of the uploaded image.
+//
+// Uses:
+//   - Storage API
+func (g *GCP) StorageObjectUploadResumable(ctx context.Context, filename, bucket, object string, metadata map[string]string, store *resumable.Store, options resumable.Options) (*storage.ObjectAttrs, error) {
+	storageClient, err := storage.NewClient(ctx, option.WithCredentials(g.creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Storage client: %w", err)
+	}
+	defer storageClient.Close()
+	bucketHandle := storageClient.Bucket(bucket)
+
+	imageFile, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open the image: %w", err)
+	}
+	defer imageFile.Close()
+	stat, err := imageFile.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("cannot stat the image: %w", err)
+	}
+
+	imageFileHash := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	if _, err := io.Copy(imageFileHash, imageFile); err != nil {
+		return nil, fmt.Errorf("cannot create crc32c of the image: %w", err)
+	}
+
+	partName := func(number int) string {
+		return fmt.Sprintf("%s.part-%05d", object, number)
+	}
+	stateKey := fmt.Sprintf("gs://%s/%s", bucket, object)
+	partSize := options.PartSizeFor(stat.Size(), math.MaxInt32)
+	state, err := store.Load(stateKey)
+	if err != nil {
+		return nil, err
+	}
+	if !state.Matches(stat.Size(), partSize) {
+		state = resumable.NewState(stat.Size(), partSize)
+	}
+	// parts can be removed by lifecycle rules of the bucket in the meantime
+	var uploaded []resumable.Part
+	for _, part := range state.Parts {
+		attrs, err := bucketHandle.Object(partName(part.Number)).Attrs(ctx)
+		if err == nil && hex.EncodeToString(attrs.MD5) == part.MD5 {
+			uploaded = append(uploaded, part)
+		}
+	}
+	state.Parts = uploaded
+
+	err = resumable.UploadParts(ctx, store, stateKey, state, imageFile, options, func(ctx context.Context, number int, offset int64, data []byte) (string, error) {
+		wc := bucketHandle.Object(partName(number)).NewWriter(ctx)
+		// the part is in memory already, upload it in a single request
+		wc.ChunkSize = 0
+		/* #nosec G401 */
+		sum := md5.Sum(data)
+		wc.MD5 = sum[:]
+		if _, err := wc.Write(data); err != nil {
+			wc.Close()
+			return "", err
+		}
+		return "", wc.Close()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("uploading the image failed: %w", err)
+	}
+
+	sources := make([]*storage.ObjectHandle, state.PartCount())
+	for i := range sources {
+		sources[i] = bucketHandle.Object(partName(i + 1))
+	}
+	parts := sources
+	// the parts are kept until the image is composed, so it can be resumed
+	var intermediate []*storage.ObjectHandle
+	deleteObjects := func(objects []*storage.ObjectHandle) {
+		for _, o := range objects {
+			if err := o.Delete(context.Background()); err != nil {
+				logrus.Warnf("[GCP] Cannot delete temporary object %s: %v", o.ObjectName(), err)
+			}
+		}
+	}
+	defer func() { deleteObjects(intermediate) }()
+
+	// compose the parts in rounds until there are few enough for the final one
+	for round := 1; len(sources) > storageMaxComposeSources; round++ {
+		var composed []*storage.ObjectHandle
+		for i := 0; i < len(sources); i += storageMaxComposeSources {
+			end := i + storageMaxComposeSources
+			if end > len(sources) {
+				end = len(sources)
+			}
+			dst := bucketHandle.Object(fmt.Sprintf("%s.compose-%d-%05d", object, round, len(composed)))
+			if _, err := dst.ComposerFrom(sources[i:end]...).Run(ctx); err != nil {
+				return nil, fmt.Errorf("composing the image failed: %w", err)
+			}
+			composed = append(composed, dst)
+			intermediate = append(intermediate, dst)
+		}
+		sources = composed
+	}
+
+	composer := bucketHandle.Object(object).ComposerFrom(sources...)
+	// the composed object is rejected if its checksum doesn't match
+	composer.CRC32C = imageFileHash.Sum32()
+	composer.SendCRC32C = true
+	if metadata != nil {
+		composer.ObjectAttrs.Metadata = metadata
+	}
+	attrs, err := composer.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("composing the image failed: %w", err)
+	}
+	deleteObjects(parts)
+
+	err = store.Delete(stateKey)
+	if err != nil {
+		logrus.Warn(err)
+	}
+	return attrs, nil
+}
@@ -0,0 +1,246 @@
+package hcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// The hcloud-go client isn't vendored, the few calls needed to import an
+// image are done with plain REST requests, see
+// https://docs.hetzner.cloud/
+const defaultEndpoint = "https://api.hetzner.cloud/v1"
+
+// HCloud holds the API token of a Hetzner Cloud project and interacts with
+// its API.
+type HCloud struct {
+	endpoint string
+	token    string
+	client   *http.Client
+
+	// how often the status of actions is checked
+	pollInterval time.Duration
+	// writes the image to the disk of the server in the rescue system,
+	// replaceable in tests
+	writeDisk func(ctx context.Context, host, keyPath, imagePath string) error
+}
+
+// New returns a client of the Hetzner Cloud API authenticated with the token.
+func New(token string) *HCloud {
+	return NewForEndpoint(defaultEndpoint, token)
+}
+
+// NewForEndpoint returns a client of the Hetzner Cloud API at the endpoint.
+func NewForEndpoint(endpoint, token string) *HCloud {
+	return &HCloud{
+		endpoint:     strings.TrimSuffix(endpoint, "/"),
+		token:        token,
+		client:       &http.Client{},
+		pollInterval: 5 * time.Second,
+		writeDisk:    writeDiskSSH,
+	}
+}
+
+// NewFromFile reads the API token from a file and returns a client
+// authenticated with it.
+func NewFromFile(path string) (*HCloud, error) {
+	token, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the Hetzner Cloud token: %v", err)
+	}
+	return New(strings.TrimSpace(string(token))), nil
+}
+
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (h *HCloud) request(ctx context.Context, method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, h.endpoint+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+h.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr apiError
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Code != "" {
+			return fmt.Errorf("%s %s failed: %s: %s", method, path, apiErr.Error.Code, apiErr.Error.Message)
+		}
+		return fmt.Errorf("%s %s failed: %s", method, path, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+type action struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	Error  *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type actionResponse struct {
+	Action action `json:"action"`
+}
+
+// waitForAction polls the action until it isn't running anymore.
+func (h *HCloud) waitForAction(ctx context.Context, a action) error {
+	for a.Status == "running" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(h.pollInterval):
+		}
+		var resp actionResponse
+		err := h.request(ctx, http.MethodGet, fmt.Sprintf("/actions/%d", a.ID), nil, &resp)
+		if err != nil {
+			return err
+		}
+		a = resp.Action
+	}
+	if a.Status != "success" {
+		if a.Error != nil {
+			return fmt.Errorf("action %d failed: %s: %s", a.ID, a.Error.Code, a.Error.Message)
+		}
+		return fmt.Errorf("action %d finished with status %s", a.ID, a.Status)
+	}
+	return nil
+}
+
+func (h *HCloud) createSSHKey(ctx context.Context, name, publicKey string) (int64, error) {
+	var resp struct {
+		SSHKey struct {
+			ID int64 `json:"id"`
+		} `json:"ssh_key"`
+	}
+	err := h.request(ctx, http.MethodPost, "/ssh_keys", map[string]string{
+		"name":       name,
+		"public_key": publicKey,
+	}, &resp)
+	return resp.SSHKey.ID, err
+}
+
+func (h *HCloud) deleteSSHKey(ctx context.Context, id int64) error {
+	return h.request(ctx, http.MethodDelete, fmt.Sprintf("/ssh_keys/%d", id), nil, nil)
+}
+
+type server struct {
+	ID        int64 `json:"id"`
+	PublicNet struct {
+		IPv4 struct {
+			IP string `json:"ip"`
+		} `json:"ipv4"`
+	} `json:"public_net"`
+}
+
+type serverLocation struct {
+	Location   string
+	Datacenter string
+}
+
+// createServer creates a stopped server, the image it's created from doesn't
+// matter, its disk is overwritten.
+func (h *HCloud) createServer(ctx context.Context, name, serverType string, location serverLocation, sshKey int64) (*server, error) {
+	body := map[string]interface{}{
+		"name":               name,
+		"server_type":        serverType,
+		"image":              "debian-12",
+		"ssh_keys":           []int64{sshKey},
+		"start_after_create": false,
+	}
+	if location.Datacenter != "" {
+		body["datacenter"] = location.Datacenter
+	} else if location.Location != "" {
+		body["location"] = location.Location
+	}
+
+	var resp struct {
+		Server server `json:"server"`
+		Action action `json:"action"`
+	}
+	err := h.request(ctx, http.MethodPost, "/servers", body, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Server, h.waitForAction(ctx, resp.Action)
+}
+
+func (h *HCloud) deleteServer(ctx context.Context, id int64) error {
+	var resp actionResponse
+	err := h.request(ctx, http.MethodDelete, fmt.Sprintf("/servers/%d", id), nil, &resp)
+	if err != nil {
+		return err
+	}
+	return h.waitForAction(ctx, resp.Action)
+}
+
+// serverAction runs the action on the server and waits until it's finished.
+func (h *HCloud) serverAction(ctx context.Context, id int64, name string, body interface{}) error {
+	var resp actionResponse
+	if body == nil {
+		body = struct{}{}
+	}
+	err := h.request(ctx, http.MethodPost, fmt.Sprintf("/servers/%d/actions/%s", id, name), body, &resp)
+	if err != nil {
+		return err
+	}
+	return h.waitForAction(ctx, resp.Action)
+}
+
+// createSnapshot creates a snapshot of the disk of the server and returns its
+// image ID.
+func (h *HCloud) createSnapshot(ctx context.Context, id int64, description string, labels map[string]string) (int64, error) {
+	body := map[string]interface{}{
+		"type":        "snapshot",
+		"description": description,
+	}
+	if len(labels) > 0 {
+		body["labels"] = labels
+	}
+
+	var resp struct {
+		Image struct {
+			ID int64 `json:"id"`
+		} `json:"image"`
+		Action action `json:"action"`
+	}
+	err := h.request(ctx, http.MethodPost, fmt.Sprintf("/servers/%d/actions/create_image", id), body, &resp)
+	if err != nil {
+		return 0, err
+	}
+	err = h.waitForAction(ctx, resp.Action)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Image.ID, nil
+}
@@ -0,0 +1,201 @@
+package hcloud
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ImportOptions describe where the image is imported and the snapshot
+// created from it.
+type ImportOptions struct {
+	// Location (e.g. fsn1) or datacenter (e.g. fsn1-dc14) of the server the
+	// image is written with, at most one of them is set. Snapshots are
+	// available in every location of the project.
+	Location   string
+	Datacenter string
+	// ServerType of the server the image is written with, its architecture
+	// must match the one of the image and its disk must be large enough.
+	ServerType  string
+	Description string
+	Labels      map[string]string
+}
+
+// ImportImage creates a snapshot from the raw disk image at rawImagePath and
+// returns its ID.
+//
+// Hetzner Cloud can't import disk images directly. A temporary server is
+// created and booted to the rescue system, the image is written over its disk
+// and a snapshot of the disk is taken. The server and its ssh key are always
+// deleted afterwards.
+func (h *HCloud) ImportImage(ctx context.Context, rawImagePath string, options ImportOptions) (int64, error) {
+	if options.Location != "" && options.Datacenter != "" {
+		return 0, fmt.Errorf("only one of location and datacenter can be set")
+	}
+
+	tmpdir, err := os.MkdirTemp("", "hcloud-import-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(tmpdir)
+	keyPath := filepath.Join(tmpdir, "id_ecdsa")
+	publicKey, err := generateSSHKey(keyPath)
+	if err != nil {
+		return 0, fmt.Errorf("cannot generate an ssh key: %v", err)
+	}
+
+	name := "osbuild-image-import-" + uuid.New().String()
+	logrus.Infof("[Hetzner] 🔑 Creating ssh key %s", name)
+	keyID, err := h.createSSHKey(ctx, name, publicKey)
+	if err != nil {
+		return 0, fmt.Errorf("cannot create the ssh key: %v", err)
+	}
+	defer func() {
+		err := h.deleteSSHKey(context.Background(), keyID)
+		if err != nil {
+			logrus.Warnf("[Hetzner] Cannot delete ssh key %d: %v", keyID, err)
+		}
+	}()
+
+	logrus.Infof("[Hetzner] 🖥 Creating server %s", name)
+	srv, err := h.createServer(ctx, name, options.ServerType, serverLocation{
+		Location:   options.Location,
+		Datacenter: options.Datacenter,
+	}, keyID)
+	if srv != nil {
+		defer func() {
+			err := h.deleteServer(context.Background(), srv.ID)
+			if err != nil {
+				logrus.Warnf("[Hetzner] Cannot delete server %d: %v", srv.ID, err)
+			}
+		}()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot create the server: %v", err)
+	}
+
+	logrus.Infof("[Hetzner] 🛟 Booting server %d to the rescue system", srv.ID)
+	err = h.serverAction(ctx, srv.ID, "enable_rescue", map[string]interface{}{
+		"type":     "linux64",
+		"ssh_keys": []int64{keyID},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot enable the rescue system: %v", err)
+	}
+	err = h.serverAction(ctx, srv.ID, "poweron", nil)
+	if err != nil {
+		return 0, fmt.Errorf("cannot power on the server: %v", err)
+	}
+
+	logrus.Infof("[Hetzner] 💾 Writing the image to the disk of server %d", srv.ID)
+	err = h.writeDisk(ctx, srv.PublicNet.IPv4.IP, keyPath, rawImagePath)
+	if err != nil {
+		return 0, fmt.Errorf("cannot write the image: %v", err)
+	}
+	err = h.serverAction(ctx, srv.ID, "poweroff", nil)
+	if err != nil {
+		return 0, fmt.Errorf("cannot power off the server: %v", err)
+	}
+
+	logrus.Infof("[Hetzner] 📸 Creating a snapshot of server %d", srv.ID)
+	imageID, err := h.createSnapshot(ctx, srv.ID, options.Description, options.Labels)
+	if err != nil {
+		return 0, fmt.Errorf("cannot create the snapshot: %v", err)
+	}
+	logrus.Infof("[Hetzner] 🎉 Snapshot %d created", imageID)
+	return imageID, nil
+}
+
+// generateSSHKey writes a new private key to path and returns the public key
+// in the authorized_keys format.
+func generateSSHKey(path string) (string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+	if err != nil {
+		return "", err
+	}
+
+	// RFC 5656, section 3.1
+	var blob []byte
+	for _, field := range [][]byte{
+		[]byte("ecdsa-sha2-nistp256"),
+		[]byte("nistp256"),
+		elliptic.Marshal(elliptic.P256(), key.X, key.Y),
+	} {
+		blob = binary.BigEndian.AppendUint32(blob, uint32(len(field)))
+		blob = append(blob, field...)
+	}
+	return "ecdsa-sha2-nistp256 " + base64.StdEncoding.EncodeToString(blob), nil
+}
+
+// The rescue system takes a while to boot, ssh is retried until it's up.
+const (
+	sshAttempts      = 30
+	sshRetryInterval = 10 * time.Second
+)
+
+// writeDiskSSH streams the image to the disk of the server booted to the
+// rescue system.
+func writeDiskSSH(ctx context.Context, host, keyPath, imagePath string) error {
+	for attempt := 1; ; attempt++ {
+		err := sshDD(ctx, host, keyPath, imagePath)
+		var exitErr *exec.ExitError
+		// ssh exits with 255 when it can't connect
+		if err == nil || !errors.As(err, &exitErr) || exitErr.ExitCode() != 255 || attempt == sshAttempts {
+			return err
+		}
+		logrus.Debugf("[Hetzner] ssh to %s failed (attempt %d), retrying: %v", host, attempt, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sshRetryInterval):
+		}
+	}
+}
+
+func sshDD(ctx context.Context, host, keyPath, imagePath string) error {
+	image, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer image.Close()
+
+	// the rescue system gets a new host key every boot
+	cmd := exec.CommandContext(ctx, "ssh", "-C",
+		"-i", keyPath,
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "UserKnownHostsFile=/dev/null",
+		"-o", "ConnectTimeout=10",
+		"root@"+host,
+		"dd of=/dev/sda bs=4M conv=fsync")
+	cmd.Stdin = image
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
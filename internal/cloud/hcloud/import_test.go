@@ -0,0 +1,114 @@
+package hcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportImage(t *testing.T) {
+	var calls []string
+	var createServer, createImage map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /ssh_keys":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.True(t, strings.HasPrefix(body["public_key"], "ecdsa-sha2-nistp256 "))
+			_, _ = w.Write([]byte(`{"ssh_key": {"id": 7}}`))
+		case "POST /servers":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&createServer))
+			_, _ = w.Write([]byte(`{"server": {"id": 42, "public_net": {"ipv4": {"ip": "192.0.2.1"}}}, "action": {"id": 1, "status": "running"}}`))
+		case "GET /actions/1":
+			_, _ = w.Write([]byte(`{"action": {"id": 1, "status": "success"}}`))
+		case "POST /servers/42/actions/create_image":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&createImage))
+			_, _ = w.Write([]byte(`{"image": {"id": 1234}, "action": {"id": 2, "status": "success"}}`))
+		case "POST /servers/42/actions/enable_rescue", "POST /servers/42/actions/poweron",
+			"POST /servers/42/actions/poweroff", "DELETE /servers/42":
+			_, _ = w.Write([]byte(`{"action": {"id": 3, "status": "success"}}`))
+		case "DELETE /ssh_keys/7":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": "not_found", "message": "not found"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	image := filepath.Join(t.TempDir(), "disk.raw")
+	require.NoError(t, os.WriteFile(image, []byte("disk"), 0600))
+
+	h := NewForEndpoint(srv.URL, "token")
+	h.pollInterval = 0
+	h.writeDisk = func(ctx context.Context, host, keyPath, imagePath string) error {
+		require.Equal(t, "192.0.2.1", host)
+		require.Equal(t, image, imagePath)
+		require.FileExists(t, keyPath)
+		calls = append(calls, "write disk")
+		return nil
+	}
+
+	id, err := h.ImportImage(context.Background(), image, ImportOptions{
+		Location:    "fsn1",
+		ServerType:  "cx22",
+		Description: "my image",
+		Labels:      map[string]string{"os": "fedora"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1234), id)
+	require.Equal(t, []string{
+		"POST /ssh_keys",
+		"POST /servers",
+		"GET /actions/1",
+		"POST /servers/42/actions/enable_rescue",
+		"POST /servers/42/actions/poweron",
+		"write disk",
+		"POST /servers/42/actions/poweroff",
+		"POST /servers/42/actions/create_image",
+		"DELETE /servers/42",
+		"DELETE /ssh_keys/7",
+	}, calls)
+	require.Equal(t, "fsn1", createServer["location"])
+	require.Equal(t, "cx22", createServer["server_type"])
+	require.NotContains(t, createServer, "datacenter")
+	require.Equal(t, "snapshot", createImage["type"])
+	require.Equal(t, "my image", createImage["description"])
+	require.Equal(t, map[string]interface{}{"os": "fedora"}, createImage["labels"])
+
+	// the server and the key are cleaned up on failures too
+	calls = nil
+	h.writeDisk = func(ctx context.Context, host, keyPath, imagePath string) error {
+		return os.ErrDeadlineExceeded
+	}
+	_, err = h.ImportImage(context.Background(), image, ImportOptions{Datacenter: "fsn1-dc14", ServerType: "cx22"})
+	require.EqualError(t, err, "cannot write the image: i/o timeout")
+	require.Equal(t, "fsn1-dc14", createServer["datacenter"])
+	require.Equal(t, []string{"DELETE /servers/42", "DELETE /ssh_keys/7"}, calls[len(calls)-2:])
+
+	_, err = h.ImportImage(context.Background(), image, ImportOptions{Location: "fsn1", Datacenter: "fsn1-dc14"})
+	require.EqualError(t, err, "only one of location and datacenter can be set")
+}
+
+func TestRequestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": {"code": "unauthorized", "message": "unable to authenticate"}}`))
+	}))
+	defer srv.Close()
+
+	h := NewForEndpoint(srv.URL, "wrong")
+	_, err := h.createSSHKey(context.Background(), "key", "ssh-ed25519 AAAA")
+	require.EqualError(t, err, "POST /ssh_keys failed: unauthorized: unable to authenticate")
+}
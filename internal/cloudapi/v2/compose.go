@@ -2,12 +2,26 @@ package v2
 
 // ComposeRequest methods to make it easier to use and test
 import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"path"
 	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/containers/image/v5/docker/reference"
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/osbuild/images/pkg/disk"
 	"github.com/osbuild/images/pkg/subscription"
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"golang.org/x/exp/slices"
 )
 
 // GetBlueprintWithCustomizations returns a new Blueprint with all of the
@@ -38,9 +52,15 @@ func (request *ComposeRequest) GetBlueprintWithCustomizations() (blueprint.Bluep
 			}
 			userCustomizations = append(userCustomizations,
 				blueprint.UserCustomization{
-					Name:   user.Name,
-					Key:    user.Key,
-					Groups: groups,
+					Name:        user.Name,
+					Description: user.Description,
+					Password:    user.Password,
+					Key:         user.Key,
+					Home:        user.Home,
+					Shell:       user.Shell,
+					Groups:      groups,
+					UID:         user.Uid,
+					GID:         user.Gid,
 				},
 			)
 		}
@@ -55,6 +75,10 @@ func (request *ComposeRequest) GetBlueprintWithCustomizations() (blueprint.Bluep
 		}
 	}
 
+	if request.Customizations.InstallWeakDeps != nil {
+		bp.Minimal = !*request.Customizations.InstallWeakDeps
+	}
+
 	if request.Customizations.Containers != nil {
 		for _, c := range *request.Customizations.Containers {
 			bc := blueprint.Container{
@@ -120,6 +144,13 @@ func (request *ComposeRequest) GetBlueprintWithCustomizations() (blueprint.Bluep
 			}
 			if f.Data != nil {
 				fileCustomization.Data = *f.Data
+				if f.DataEncoding != nil && *f.DataEncoding == FileDataEncodingBase64 {
+					data, err := base64.StdEncoding.DecodeString(*f.Data)
+					if err != nil {
+						return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("invalid base64 data of file %s: %v", f.Path, err))
+					}
+					fileCustomization.Data = string(data)
+				}
 			}
 			if f.Mode != nil {
 				fileCustomization.Mode = *f.Mode
@@ -200,9 +231,138 @@ func (request *ComposeRequest) GetBlueprintWithCustomizations() (blueprint.Bluep
 		bp.Customizations.OpenSCAP = openSCAPCustomization
 	}
 
+	if request.Customizations.NetworkConnections != nil {
+		files, err := nmConnectionFiles(*request.Customizations.NetworkConnections)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		for _, file := range files {
+			for _, f := range bp.Customizations.Files {
+				if f.Path == file.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the network connections can't be combined with a custom %s", f.Path))
+				}
+			}
+		}
+		bp.Customizations.Files = append(bp.Customizations.Files, files...)
+	}
+
+	if request.Customizations.Identity != nil {
+		files, services := identityCustomizations(request.Customizations.Identity)
+		for _, file := range files {
+			for _, f := range bp.Customizations.Files {
+				if f.Path == file.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the identity customization can't be combined with a custom %s", f.Path))
+				}
+			}
+		}
+		bp.Customizations.Files = append(bp.Customizations.Files, files...)
+		if len(services) > 0 {
+			if bp.Customizations.Services == nil {
+				bp.Customizations.Services = &blueprint.ServicesCustomization{}
+			}
+			bp.Customizations.Services.Enabled = append(bp.Customizations.Services.Enabled, services...)
+		}
+	}
+
+	if request.Customizations.Sshd != nil {
+		var users []User
+		if request.Customizations.Users != nil {
+			users = *request.Customizations.Users
+		}
+		file, err := sshdConfigFile(request.Customizations.Sshd, users)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		if file != nil {
+			for _, f := range bp.Customizations.Files {
+				if f.Path == file.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the sshd customization can't be combined with a custom %s", f.Path))
+				}
+			}
+			bp.Customizations.Files = append(bp.Customizations.Files, *file)
+		}
+	}
+
+	if request.Customizations.Dracut != nil {
+		file, err := dracutConfigFile(request.Customizations.Dracut)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		if file != nil {
+			for _, f := range bp.Customizations.Files {
+				if f.Path == file.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the dracut customization can't be combined with a custom %s", f.Path))
+				}
+			}
+			bp.Customizations.Files = append(bp.Customizations.Files, *file)
+		}
+	}
+
+	if request.Customizations.Sysctl != nil {
+		file, err := sysctlConfigFile(*request.Customizations.Sysctl)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		if file != nil {
+			for _, f := range bp.Customizations.Files {
+				if f.Path == file.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the sysctl customization can't be combined with a custom %s", f.Path))
+				}
+			}
+			bp.Customizations.Files = append(bp.Customizations.Files, *file)
+		}
+	}
+
+	if request.Customizations.UdevRules != nil {
+		file, err := udevRulesFile(*request.Customizations.UdevRules)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		if file != nil {
+			for _, f := range bp.Customizations.Files {
+				if f.Path == file.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the udev rules customization can't be combined with a custom %s", f.Path))
+				}
+			}
+			bp.Customizations.Files = append(bp.Customizations.Files, *file)
+		}
+	}
+
+	if request.Customizations.NetworkMounts != nil {
+		dirs, files, services, err := networkMountCustomizations(*request.Customizations.NetworkMounts, bp.Customizations.Filesystem)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		for _, dir := range dirs {
+			for _, d := range bp.Customizations.Directories {
+				if d.Path == dir.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the network mounts can't be combined with a custom %s", d.Path))
+				}
+			}
+		}
+		for _, file := range files {
+			for _, f := range bp.Customizations.Files {
+				if f.Path == file.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the network mounts can't be combined with a custom %s", f.Path))
+				}
+			}
+		}
+		bp.Customizations.Directories = append(bp.Customizations.Directories, dirs...)
+		bp.Customizations.Files = append(bp.Customizations.Files, files...)
+		if bp.Customizations.Services == nil {
+			bp.Customizations.Services = &blueprint.ServicesCustomization{}
+		}
+		bp.Customizations.Services.Enabled = append(bp.Customizations.Services.Enabled, services...)
+	}
+
 	if request.Customizations.CustomRepositories != nil {
 		repoCustomizations := []blueprint.RepositoryCustomization{}
+		repoIDs := map[string]bool{}
 		for _, repo := range *request.Customizations.CustomRepositories {
+			if repoIDs[repo.Id] {
+				return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("custom repository %s is specified twice", repo.Id))
+			}
+			repoIDs[repo.Id] = true
 			repoCustomization := blueprint.RepositoryCustomization{
 				Id: repo.Id,
 			}
@@ -254,6 +414,12 @@ func (request *ComposeRequest) GetBlueprintWithCustomizations() (blueprint.Bluep
 			repoCustomizations = append(repoCustomizations, repoCustomization)
 		}
 		bp.Customizations.Repositories = repoCustomizations
+
+		// the image type only validates them when the manifest is generated
+		_, err := bp.Customizations.GetRepositories()
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
 	}
 
 	if request.Customizations.Hostname != nil {
@@ -339,6 +505,26 @@ func (request *ComposeRequest) GetBlueprintWithCustomizations() (blueprint.Bluep
 				Disabled: disabled,
 			}
 		}
+		if request.Customizations.Firewall.Zones != nil {
+			for _, z := range *request.Customizations.Firewall.Zones {
+				zone := blueprint.FirewallZoneCustomization{
+					Name: common.ToPtr(z.Name),
+				}
+				if z.Sources != nil {
+					zone.Sources = append(zone.Sources, *z.Sources...)
+				}
+				firewall.Zones = append(firewall.Zones, zone)
+			}
+		}
+		if sshd := request.Customizations.Sshd; sshd != nil && sshd.Ports != nil {
+			// sshd doesn't listen on port 22 the firewall opens anymore
+			for _, port := range *sshd.Ports {
+				p := fmt.Sprintf("%d:tcp", port)
+				if !slices.Contains(firewall.Ports, p) {
+					firewall.Ports = append(firewall.Ports, p)
+				}
+			}
+		}
 
 		bp.Customizations.Firewall = firewall
 	}
@@ -386,6 +572,80 @@ func (request *ComposeRequest) GetBlueprintWithCustomizations() (blueprint.Bluep
 		bp.Customizations.Ignition = ignition
 	}
 
+	if request.Customizations.Ntp != nil {
+		if bp.Customizations.Timezone != nil && len(bp.Customizations.Timezone.NTPServers) > 0 {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the ntp customization can't be combined with the ntpservers of the timezone customization"))
+		}
+		for _, f := range bp.Customizations.Files {
+			if f.Path == chronyConfPath {
+				return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the ntp customization can't be combined with a custom %s", chronyConfPath))
+			}
+		}
+		conf, err := chronyConf(*request.Customizations.Ntp)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		bp.Customizations.Files = append(bp.Customizations.Files, blueprint.FileCustomization{
+			Path: chronyConfPath,
+			Mode: "0644",
+			Data: conf,
+		})
+	}
+
+	if request.Customizations.OstreeRemote != nil {
+		files, err := ostreeRemoteFiles(*request.Customizations.OstreeRemote, request.Distribution)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		for _, f := range bp.Customizations.Files {
+			for _, remoteFile := range files {
+				if f.Path == remoteFile.Path {
+					return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the ostree remote customization can't be combined with a custom %s", f.Path))
+				}
+			}
+		}
+		bp.Customizations.Files = append(bp.Customizations.Files, files...)
+	}
+
+	if request.Customizations.Subscription != nil {
+		files, err := subscriptionFiles(*request.Customizations.Subscription)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		bp.Customizations.Files = append(bp.Customizations.Files, files...)
+	}
+
+	if request.Customizations.Cacerts != nil {
+		files, err := caTrustFiles(request.Customizations.Cacerts.PemCerts)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		for _, f := range bp.Customizations.Files {
+			if f.Path == "/etc/systemd/system/"+caTrustService {
+				return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the cacerts customization can't be combined with a custom %s", f.Path))
+			}
+		}
+		bp.Customizations.Files = append(bp.Customizations.Files, files...)
+		if bp.Customizations.Services == nil {
+			bp.Customizations.Services = &blueprint.ServicesCustomization{}
+		}
+		bp.Customizations.Services.Enabled = append(bp.Customizations.Services.Enabled, caTrustService)
+	}
+
+	if request.Customizations.Users != nil {
+		unit, err := userPoliciesUnit(*request.Customizations.Users)
+		if err != nil {
+			return bp, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		if unit != nil {
+			bp.Customizations.Files = append(bp.Customizations.Files, *unit)
+			if bp.Customizations.Services == nil {
+				bp.Customizations.Services = &blueprint.ServicesCustomization{}
+			}
+			bp.Customizations.Services.Enabled = append(bp.Customizations.Services.Enabled, userPoliciesService)
+		}
+	}
+
 	// Did bp.Customizations get set at all? If not, set it back to nil
 	if reflect.DeepEqual(*bp.Customizations, blueprint.Customizations{}) {
 		bp.Customizations = nil
@@ -394,8 +654,372 @@ func (request *ComposeRequest) GetBlueprintWithCustomizations() (blueprint.Bluep
 	return bp, nil
 }
 
+const chronyConfPath = "/etc/chrony.conf"
+
+// chronyConf returns the chrony configuration of the ntp customization. Its
+// defaults besides the servers follow the configuration chrony ships with.
+func chronyConf(ntp NTP) (string, error) {
+	var b strings.Builder
+	b.WriteString("# Generated from the ntp customization of the image\n")
+	for _, server := range ntp.Servers {
+		if server.Minpoll != nil && server.Maxpoll != nil && *server.Minpoll > *server.Maxpoll {
+			return "", fmt.Errorf("minpoll of ntp server %s is greater than its maxpoll", server.Hostname)
+		}
+		directive := "server"
+		if server.Pool != nil && *server.Pool {
+			directive = "pool"
+		}
+		b.WriteString(directive + " " + server.Hostname)
+		if server.Iburst != nil && *server.Iburst {
+			b.WriteString(" iburst")
+		}
+		if server.Prefer != nil && *server.Prefer {
+			b.WriteString(" prefer")
+		}
+		if server.Minpoll != nil {
+			fmt.Fprintf(&b, " minpoll %d", *server.Minpoll)
+		}
+		if server.Maxpoll != nil {
+			fmt.Fprintf(&b, " maxpoll %d", *server.Maxpoll)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("driftfile /var/lib/chrony/drift\n")
+	b.WriteString("makestep 1.0 3\n")
+	b.WriteString("rtcsync\n")
+	b.WriteString("keyfile /etc/chrony.keys\n")
+	b.WriteString("ntsdumpdir /var/lib/chrony\n")
+	if ntp.LeapSecondMode != nil {
+		fmt.Fprintf(&b, "leapsecmode %s\n", *ntp.LeapSecondMode)
+	}
+	leapSecondTimezone := "right/UTC"
+	if ntp.LeapSecondTimezone != nil {
+		leapSecondTimezone = *ntp.LeapSecondTimezone
+	}
+	fmt.Fprintf(&b, "leapsectz %s\n", leapSecondTimezone)
+	b.WriteString("logdir /var/log/chrony\n")
+	return b.String(), nil
+}
+
+// ostreeRemoteFiles returns the files configuring the ostree remote in
+// /etc/ostree/remotes.d: its configuration and its GPG key.
+func ostreeRemoteFiles(remote OSTreeRemote, distribution string) ([]blueprint.FileCustomization, error) {
+	name := "rhel-edge"
+	if strings.HasPrefix(distribution, "fedora") {
+		name = "fedora-iot"
+	}
+	if remote.Name != nil {
+		name = *remote.Name
+	}
+
+	var conf strings.Builder
+	fmt.Fprintf(&conf, "[remote \"%s\"]\n", name)
+	fmt.Fprintf(&conf, "url=%s\n", remote.Url)
+	if remote.ContentUrl != nil {
+		fmt.Fprintf(&conf, "contenturl=%s\n", *remote.ContentUrl)
+	}
+	if remote.GpgKey == nil {
+		conf.WriteString("gpg-verify=false\n")
+		return []blueprint.FileCustomization{{
+			Path: fmt.Sprintf("/etc/ostree/remotes.d/%s.conf", name),
+			Mode: "0644",
+			Data: conf.String(),
+		}}, nil
+	}
+
+	if !strings.Contains(*remote.GpgKey, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+		return nil, fmt.Errorf("the GPG key of the ostree remote has to be an ASCII armored public key")
+	}
+	// ostree only reads the .conf files of remotes.d, which the ostree
+	// package already creates
+	keyPath := fmt.Sprintf("/etc/ostree/remotes.d/%s.gpg", name)
+	conf.WriteString("gpg-verify=true\n")
+	fmt.Fprintf(&conf, "gpgkeypath=%s\n", keyPath)
+	return []blueprint.FileCustomization{
+		{
+			Path: fmt.Sprintf("/etc/ostree/remotes.d/%s.conf", name),
+			Mode: "0644",
+			Data: conf.String(),
+		},
+		{
+			Path: keyPath,
+			Mode: "0644",
+			Data: *remote.GpgKey,
+		},
+	}, nil
+}
+
+const (
+	insightsTagsPath     = "/etc/insights-client/tags.yaml"
+	subscriptionFactPath = "/etc/rhsm/facts/osbuild.facts"
+)
+
+// subscriptionFiles returns the Insights tags and the custom facts of the
+// subscription. Both are read when the system is registered on its first
+// boot.
+func subscriptionFiles(sub Subscription) ([]blueprint.FileCustomization, error) {
+	var files []blueprint.FileCustomization
+	if sub.InsightsTags != nil && len(sub.InsightsTags.AdditionalProperties) > 0 {
+		if !sub.Insights && (sub.Rhc == nil || !*sub.Rhc) {
+			return nil, fmt.Errorf("insights tags require insights or rhc to be enabled")
+		}
+		tags := sub.InsightsTags.AdditionalProperties
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			if key == "" {
+				return nil, fmt.Errorf("insights tags can't have an empty name")
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		// JSON strings are valid YAML scalars
+		var b strings.Builder
+		b.WriteString("# Generated from the subscription customization of the image\n")
+		for _, key := range keys {
+			k, err := json.Marshal(key)
+			if err != nil {
+				return nil, err
+			}
+			v, err := json.Marshal(tags[key])
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "%s: %s\n", k, v)
+		}
+		files = append(files, blueprint.FileCustomization{
+			Path: insightsTagsPath,
+			Mode: "0644",
+			Data: b.String(),
+		})
+	}
+
+	if sub.Facts != nil && len(sub.Facts.AdditionalProperties) > 0 {
+		facts, err := json.MarshalIndent(sub.Facts.AdditionalProperties, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, blueprint.FileCustomization{
+			Path: subscriptionFactPath,
+			Mode: "0644",
+			Data: string(facts) + "\n",
+		})
+	}
+	return files, nil
+}
+
+const (
+	caTrustAnchors = "/etc/pki/ca-trust/source/anchors"
+	caTrustService = "osbuild-ca-trust.service"
+	caTrustStamp   = "/var/lib/osbuild-ca-trust"
+)
+
+// caTrustFiles returns the ca-trust anchors of the certificates, named after
+// their serial numbers, and the unit extracting the trust store on the first
+// boot. The trust store can't be extracted when the image is built.
+func caTrustFiles(pemCerts []string) ([]blueprint.FileCustomization, error) {
+	var files []blueprint.FileCustomization
+	for _, pemCert := range pemCerts {
+		block, rest := pem.Decode([]byte(pemCert))
+		if block == nil || block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("the CA certificate isn't a PEM encoded certificate")
+		}
+		if len(bytes.TrimSpace(rest)) > 0 {
+			return nil, fmt.Errorf("each CA certificate has to be a single PEM encoded certificate")
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %v", err)
+		}
+		path := fmt.Sprintf("%s/%x.pem", caTrustAnchors, cert.SerialNumber)
+		for _, f := range files {
+			if f.Path == path {
+				return nil, fmt.Errorf("CA certificate with serial number %x is included twice", cert.SerialNumber)
+			}
+		}
+		files = append(files, blueprint.FileCustomization{
+			Path: path,
+			Mode: "0644",
+			Data: string(pem.EncodeToMemory(block)),
+		})
+	}
+
+	unit := fmt.Sprintf(`[Unit]
+Description=Extract the CA certificates of the image into the trust store
+DefaultDependencies=no
+After=local-fs.target
+Before=sysinit.target shutdown.target
+Conflicts=shutdown.target
+ConditionPathExists=!%[1]s
+
+[Service]
+Type=oneshot
+ExecStart=/usr/bin/update-ca-trust extract
+ExecStartPost=/usr/bin/touch %[1]s
+
+[Install]
+WantedBy=sysinit.target
+`, caTrustStamp)
+
+	return append(files, blueprint.FileCustomization{
+		Path: "/etc/systemd/system/" + caTrustService,
+		Mode: "0644",
+		Data: unit,
+	}), nil
+}
+
+const (
+	userPoliciesService = "osbuild-user-policies.service"
+	userPoliciesStamp   = "/var/lib/osbuild-user-policies"
+)
+
+var userNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.][a-zA-Z0-9_.-]*\$?$`)
+
+// userPoliciesUnit returns the unit which applies the account expiration
+// and the forced password changes of the users on the first boot. The users
+// stage can't set them, so they are applied with chage once the image runs.
+// It returns nil if none of the users needs it.
+func userPoliciesUnit(users []User) (*blueprint.FileCustomization, error) {
+	var commands []string
+	for _, user := range users {
+		if user.ExpirationDate == nil && (user.ForcePasswordChange == nil || !*user.ForcePasswordChange) {
+			continue
+		}
+		if !userNameRegex.MatchString(user.Name) {
+			return nil, fmt.Errorf("invalid user name %q", user.Name)
+		}
+		if user.ExpirationDate != nil {
+			commands = append(commands, fmt.Sprintf("ExecStart=/usr/bin/chage --expiredate %s %s", user.ExpirationDate.Format(openapi_types.DateFormat), user.Name))
+		}
+		if user.ForcePasswordChange != nil && *user.ForcePasswordChange {
+			commands = append(commands, fmt.Sprintf("ExecStart=/usr/bin/chage --lastday 0 %s", user.Name))
+		}
+	}
+	if len(commands) == 0 {
+		return nil, nil
+	}
+
+	unit := fmt.Sprintf(`[Unit]
+Description=Apply the account policies of the image users
+ConditionPathExists=!%[1]s
+
+[Service]
+Type=oneshot
+%[2]s
+ExecStartPost=/usr/bin/touch %[1]s
+
+[Install]
+WantedBy=multi-user.target
+`, userPoliciesStamp, strings.Join(commands, "\n"))
+
+	return &blueprint.FileCustomization{
+		Path: "/etc/systemd/system/" + userPoliciesService,
+		Mode: "0644",
+		Data: unit,
+	}, nil
+}
+
 // GetPayloadRepositories returns the custom repos
 // If there are none it returns a nil slice
+// GetContainerAuths returns the credentials of the registries the containers
+// to embed are pulled from, by registry. The containers of a registry have
+// to be pulled with the same credentials.
+func (request *ComposeRequest) GetContainerAuths() (map[string]worker.ContainerAuth, error) {
+	if request.Customizations == nil || request.Customizations.Containers == nil {
+		return nil, nil
+	}
+
+	var auths map[string]worker.ContainerAuth
+	for _, c := range *request.Customizations.Containers {
+		if c.Auth == nil {
+			continue
+		}
+		registry, err := containerRegistry(c.Source)
+		if err != nil {
+			return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+		auth := worker.ContainerAuth{
+			Username: c.Auth.Username,
+			Password: c.Auth.Password,
+		}
+		if other, ok := auths[registry]; ok && other != auth {
+			return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("containers of registry %s have different credentials", registry))
+		}
+		if auths == nil {
+			auths = map[string]worker.ContainerAuth{}
+		}
+		auths[registry] = auth
+	}
+	return auths, nil
+}
+
+// GetExcludedPackages returns the packages excluded from the payload of the
+// image, they can't match any of the packages of the request.
+func (request *ComposeRequest) GetExcludedPackages() ([]string, error) {
+	if request.Customizations == nil || request.Customizations.ExcludePackages == nil {
+		return nil, nil
+	}
+
+	packages := request.requiredPackages()
+	if request.Customizations.Packages != nil {
+		packages = append(packages, *request.Customizations.Packages...)
+	}
+	for _, pattern := range *request.Customizations.ExcludePackages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("invalid package exclusion %s: %v", pattern, err))
+		}
+		for _, p := range packages {
+			if matched, _ := path.Match(pattern, p); matched {
+				return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("package %s is both included and excluded by %s", p, pattern))
+			}
+		}
+	}
+	return *request.Customizations.ExcludePackages, nil
+}
+
+// GetDepsolveModules returns the DNF modules the packages of the image are
+// depsolved with.
+func (request *ComposeRequest) GetDepsolveModules() (*worker.DepsolveModules, error) {
+	if request.Customizations == nil || request.Customizations.Modules == nil {
+		return nil, nil
+	}
+
+	modules := &worker.DepsolveModules{}
+	streams := map[string]string{}
+	if request.Customizations.Modules.Enable != nil {
+		for _, spec := range *request.Customizations.Modules.Enable {
+			name, stream, _ := strings.Cut(spec, ":")
+			if other, ok := streams[name]; ok && other != stream {
+				return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("streams %s and %s of module %s can't both be enabled", other, stream, name))
+			}
+			streams[name] = stream
+			modules.Enable = append(modules.Enable, spec)
+		}
+	}
+	if request.Customizations.Modules.Disable != nil {
+		for _, name := range *request.Customizations.Modules.Disable {
+			if _, ok := streams[name]; ok {
+				return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("module %s can't be both enabled and disabled", name))
+			}
+			modules.Disable = append(modules.Disable, name)
+		}
+	}
+	if len(modules.Enable) == 0 && len(modules.Disable) == 0 {
+		return nil, nil
+	}
+	return modules, nil
+}
+
+// containerRegistry returns the registry a container is pulled from.
+func containerRegistry(source string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid container source %s: %v", source, err)
+	}
+	return reference.Domain(ref), nil
+}
+
 func (request *ComposeRequest) GetPayloadRepositories() (repos []Repository) {
 	if request.Customizations != nil && request.Customizations.PayloadRepositories != nil {
 		repos = *request.Customizations.PayloadRepositories
@@ -426,6 +1050,83 @@ func (request *ComposeRequest) GetSubscription() (sub *subscription.ImageOptions
 	return
 }
 
+// requiredPackages returns the packages the customizations of the request
+// rely on, which have to end up in the image
+func (request *ComposeRequest) requiredPackages() []string {
+	if request.Customizations == nil {
+		return nil
+	}
+	var packages []string
+	if request.Customizations.Firewall != nil {
+		// the firewall is configured with firewall-offline-cmd of the image
+		packages = append(packages, "firewalld")
+	}
+	if request.Customizations.Ntp != nil {
+		packages = append(packages, "chrony")
+	}
+	if request.Customizations.Cacerts != nil {
+		packages = append(packages, "ca-certificates")
+	}
+	if request.Customizations.NetworkConnections != nil {
+		packages = append(packages, "NetworkManager")
+	}
+	if request.Customizations.NetworkMounts != nil {
+		for _, mount := range *request.Customizations.NetworkMounts {
+			// the mount helpers of the filesystems
+			helper := "nfs-utils"
+			if mount.Type == NetworkMountTypeCifs {
+				helper = "cifs-utils"
+			}
+			if !slices.Contains(packages, helper) {
+				packages = append(packages, helper)
+			}
+		}
+	}
+	return packages
+}
+
+// GetDiskMinSize returns the minimum size of the disk included in the
+// request or 0 if not included
+func (request *ComposeRequest) GetDiskMinSize() uint64 {
+	if request.Customizations == nil || request.Customizations.Disk == nil || request.Customizations.Disk.Minsize == nil {
+		return 0
+	}
+	return *request.Customizations.Disk.Minsize
+}
+
+// hasPartitioningCustomizations returns true if the request changes how the
+// disk of the image is partitioned
+func (request *ComposeRequest) hasPartitioningCustomizations() bool {
+	if request.Customizations == nil {
+		return false
+	}
+	return request.Customizations.Filesystem != nil ||
+		request.Customizations.PartitioningMode != nil ||
+		request.Customizations.Disk != nil
+}
+
+// hasFSNodeCustomizations returns true if the request creates custom files
+// or directories
+func (request *ComposeRequest) hasFSNodeCustomizations() bool {
+	if request.Customizations == nil {
+		return false
+	}
+	return request.Customizations.Files != nil || request.Customizations.Directories != nil ||
+		request.Customizations.NetworkConnections != nil || request.Customizations.Identity != nil ||
+		request.Customizations.Sshd != nil || request.Customizations.Dracut != nil ||
+		request.Customizations.NetworkMounts != nil
+}
+
+// hasKernelCustomizations returns true if the request selects the kernel or
+// changes its command line
+func (request *ComposeRequest) hasKernelCustomizations() bool {
+	if request.Customizations == nil || request.Customizations.Kernel == nil {
+		return false
+	}
+	kernel := request.Customizations.Kernel
+	return (kernel.Name != nil && *kernel.Name != "") || (kernel.Append != nil && *kernel.Append != "")
+}
+
 // GetPartitioningMode returns the partitioning mode included in the request
 // or defaults to AutoLVMPartitioningMode if not included
 func (request *ComposeRequest) GetPartitioningMode() (disk.PartitioningMode, error) {
@@ -1,12 +1,24 @@
 package v2
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/osbuild/images/pkg/container"
 	"github.com/osbuild/images/pkg/disk"
+	"github.com/osbuild/images/pkg/rpmmd"
 	"github.com/osbuild/images/pkg/subscription"
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -86,8 +98,9 @@ func TestGetBlueprintWithCustomizations(t *testing.T) {
 		Openscap: &OpenSCAP{ProfileId: "B 263-59"},
 		CustomRepositories: &[]CustomRepository{
 			CustomRepository{
-				Id:       "custom repo",
+				Id:       "custom-repo",
 				Metalink: common.ToPtr("http://example.org/metalink"),
+				Gpgkey:   &[]string{"http://example.org/RPM-GPG-KEY-custom"},
 				CheckGpg: common.ToPtr(true),
 				Enabled:  common.ToPtr(true),
 			},
@@ -96,6 +109,12 @@ func TestGetBlueprintWithCustomizations(t *testing.T) {
 			Ports: common.ToPtr([]string{
 				"22/tcp",
 			}),
+			Zones: &[]FirewallZone{
+				{
+					Name:    "trusted",
+					Sources: common.ToPtr([]string{"192.168.1.0/24"}),
+				},
+			},
 		},
 		Hostname:           common.ToPtr("hostname"),
 		InstallationDevice: common.ToPtr("/dev/sda"),
@@ -192,8 +211,9 @@ func TestGetBlueprintWithCustomizations(t *testing.T) {
 		},
 		Repositories: []blueprint.RepositoryCustomization{
 			blueprint.RepositoryCustomization{
-				Id:       "custom repo",
+				Id:       "custom-repo",
 				Metalink: "http://example.org/metalink",
+				GPGKeys:  []string{"http://example.org/RPM-GPG-KEY-custom"},
 				Enabled:  common.ToPtr(true),
 				GPGCheck: common.ToPtr(true),
 			},
@@ -202,6 +222,12 @@ func TestGetBlueprintWithCustomizations(t *testing.T) {
 			Ports: []string{
 				"22/tcp",
 			},
+			Zones: []blueprint.FirewallZoneCustomization{
+				{
+					Name:    common.ToPtr("trusted"),
+					Sources: []string{"192.168.1.0/24"},
+				},
+			},
 		},
 		Hostname:           common.ToPtr("hostname"),
 		InstallationDevice: "/dev/sda",
@@ -321,3 +347,746 @@ func TestGetPartitioningMode(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, disk.AutoLVMPartitioningMode, pm)
 }
+
+func TestGetDiskMinSize(t *testing.T) {
+	cr := ComposeRequest{}
+	assert.Equal(t, uint64(0), cr.GetDiskMinSize())
+	assert.False(t, cr.hasPartitioningCustomizations())
+
+	cr = ComposeRequest{Customizations: &Customizations{
+		Disk: &Disk{Minsize: common.ToPtr(uint64(10737418240))},
+	}}
+	assert.Equal(t, uint64(10737418240), cr.GetDiskMinSize())
+	assert.True(t, cr.hasPartitioningCustomizations())
+}
+
+func TestRequiredPackages(t *testing.T) {
+	cr := ComposeRequest{}
+	assert.Empty(t, cr.requiredPackages())
+
+	cr = ComposeRequest{Customizations: &Customizations{
+		Firewall: &FirewallCustomization{
+			Ports: common.ToPtr([]string{"22/tcp"}),
+		},
+	}}
+	assert.Equal(t, []string{"firewalld"}, cr.requiredPackages())
+
+	// only the payload has to contain the packages
+	specs := map[string][]rpmmd.PackageSpec{
+		"build": {{Name: "firewalld"}},
+		"os":    {{Name: "kernel"}},
+	}
+	assert.Equal(t, []string{"firewalld"}, missingPackages(specs, cr.requiredPackages()))
+	specs["os"] = append(specs["os"], rpmmd.PackageSpec{Name: "firewalld"})
+	assert.Empty(t, missingPackages(specs, cr.requiredPackages()))
+}
+
+func TestGetBlueprintWithUserPolicies(t *testing.T) {
+	expiration, err := time.Parse(openapi_types.DateFormat, "2025-12-31")
+	require.NoError(t, err)
+	cr := ComposeRequest{Customizations: &Customizations{
+		Users: &[]User{
+			{
+				Name:     "admin",
+				Groups:   &[]string{"wheel"},
+				Password: common.ToPtr("$6$crypted"),
+				Uid:      common.ToPtr(1042),
+				Gid:      common.ToPtr(1042),
+				Home:     common.ToPtr("/srv/admin"),
+				Shell:    common.ToPtr("/usr/bin/zsh"),
+			},
+			{
+				Name:                "contractor",
+				ExpirationDate:      &openapi_types.Date{Time: expiration},
+				ForcePasswordChange: common.ToPtr(true),
+			},
+		},
+	}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+
+	require.Len(t, bp.Customizations.User, 2)
+	assert.Equal(t, blueprint.UserCustomization{
+		Name:     "admin",
+		Groups:   []string{"wheel"},
+		Password: common.ToPtr("$6$crypted"),
+		UID:      common.ToPtr(1042),
+		GID:      common.ToPtr(1042),
+		Home:     common.ToPtr("/srv/admin"),
+		Shell:    common.ToPtr("/usr/bin/zsh"),
+	}, bp.Customizations.User[0])
+
+	// the expiration and the password change are applied on the first boot
+	require.Len(t, bp.Customizations.Files, 1)
+	unit := bp.Customizations.Files[0]
+	assert.Equal(t, "/etc/systemd/system/"+userPoliciesService, unit.Path)
+	assert.Contains(t, unit.Data, "ExecStart=/usr/bin/chage --expiredate 2025-12-31 contractor\n")
+	assert.Contains(t, unit.Data, "ExecStart=/usr/bin/chage --lastday 0 contractor\n")
+	assert.Equal(t, []string{userPoliciesService}, bp.Customizations.Services.Enabled)
+	_, err = blueprint.FileCustomizationsToFsNodeFiles(bp.Customizations.Files)
+	require.NoError(t, err)
+
+	// no unit without policies
+	cr = ComposeRequest{Customizations: &Customizations{Users: &[]User{{Name: "admin"}}}}
+	bp, err = cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Empty(t, bp.Customizations.Files)
+	assert.Nil(t, bp.Customizations.Services)
+
+	// user names end up in the unit
+	cr = ComposeRequest{Customizations: &Customizations{Users: &[]User{{
+		Name:                "admin\nExecStart=/bin/evil",
+		ForcePasswordChange: common.ToPtr(true),
+	}}}}
+	_, err = cr.GetBlueprintWithCustomizations()
+	require.Error(t, err)
+}
+
+func TestGetBlueprintWithBase64Files(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{
+		Files: &[]File{
+			{
+				Path:         "/etc/binary",
+				Data:         common.ToPtr("AAEC/w=="),
+				DataEncoding: common.ToPtr(FileDataEncodingBase64),
+			},
+			{
+				Path:         "/etc/plain",
+				Data:         common.ToPtr("AAEC/w=="),
+				DataEncoding: common.ToPtr(FileDataEncodingPlain),
+			},
+		},
+	}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	require.Len(t, bp.Customizations.Files, 2)
+	assert.Equal(t, string([]byte{0, 1, 2, 255}), bp.Customizations.Files[0].Data)
+	assert.Equal(t, "AAEC/w==", bp.Customizations.Files[1].Data)
+
+	(*cr.Customizations.Files)[0].Data = common.ToPtr("not base64")
+	_, err = cr.GetBlueprintWithCustomizations()
+	require.Error(t, err)
+}
+
+func TestGetBlueprintWithNTP(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{
+		Ntp: &NTP{
+			Servers: []NTPServer{
+				{
+					Hostname: "time.example.com",
+					Iburst:   common.ToPtr(true),
+					Prefer:   common.ToPtr(true),
+					Minpoll:  common.ToPtr(4),
+					Maxpoll:  common.ToPtr(6),
+				},
+				{
+					Hostname: "pool.example.com",
+					Pool:     common.ToPtr(true),
+				},
+			},
+			LeapSecondMode: common.ToPtr(NTPLeapSecondModeSlew),
+		},
+	}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	require.Len(t, bp.Customizations.Files, 1)
+	conf := bp.Customizations.Files[0]
+	assert.Equal(t, "/etc/chrony.conf", conf.Path)
+	assert.Contains(t, conf.Data, "\nserver time.example.com iburst prefer minpoll 4 maxpoll 6\npool pool.example.com\n")
+	assert.Contains(t, conf.Data, "\nleapsecmode slew\nleapsectz right/UTC\n")
+	assert.Equal(t, []string{"chrony"}, cr.requiredPackages())
+
+	// minpoll greater than maxpoll
+	cr.Customizations.Ntp.Servers[0].Minpoll = common.ToPtr(8)
+	_, err = cr.GetBlueprintWithCustomizations()
+	require.Error(t, err)
+	cr.Customizations.Ntp.Servers[0].Minpoll = nil
+
+	// the servers of the timezone customization would be overwritten
+	cr.Customizations.Timezone = &Timezone{Ntpservers: &[]string{"0.pool.ntp.org"}}
+	_, err = cr.GetBlueprintWithCustomizations()
+	require.Error(t, err)
+}
+
+func TestGetContainerAuths(t *testing.T) {
+	cr := ComposeRequest{}
+	auths, err := cr.GetContainerAuths()
+	require.NoError(t, err)
+	assert.Nil(t, auths)
+
+	auth := &ContainerAuth{Username: "user", Password: "secret"}
+	cr.Customizations = &Customizations{
+		Containers: &[]Container{
+			{Source: "registry.example.com/private/one:1"},
+			{Source: "registry.example.com/private/two@sha256:f29b6cd42a94a574583439addcd6694e6224f0e4b32044c9e3aee4c4856c2a50", Auth: auth},
+			{Source: "fedora:latest", Auth: auth},
+		},
+	}
+	auths, err = cr.GetContainerAuths()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]worker.ContainerAuth{
+		"registry.example.com": {Username: "user", Password: "secret"},
+		"docker.io":            {Username: "user", Password: "secret"},
+	}, auths)
+
+	specs := containerResolveSpecs([]container.SourceSpec{
+		{Source: "registry.example.com/private/one:1", Name: "one"},
+		{Source: "quay.io/public:latest"},
+	}, auths)
+	assert.Equal(t, &worker.ContainerAuth{Username: "user", Password: "secret"}, specs[0].Auth)
+	assert.Equal(t, "one", specs[0].Name)
+	assert.Nil(t, specs[1].Auth)
+
+	// a registry has one set of credentials
+	*cr.Customizations.Containers = append(*cr.Customizations.Containers, Container{
+		Source: "registry.example.com/private/three",
+		Auth:   &ContainerAuth{Username: "other", Password: "secret"},
+	})
+	_, err = cr.GetContainerAuths()
+	assert.Error(t, err)
+
+	cr.Customizations.Containers = &[]Container{{Source: "Invalid Source", Auth: auth}}
+	_, err = cr.GetContainerAuths()
+	assert.Error(t, err)
+}
+
+func TestGetBlueprintWithOSTreeRemote(t *testing.T) {
+	key := "-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nmQINBGAcScoBEADLf8YHkezJ6adlMYw7aGGIlJalt8Jj2x/B2K+hIfIuxGtpVj7e\n-----END PGP PUBLIC KEY BLOCK-----\n"
+	cr := ComposeRequest{
+		Distribution: "rhel-9.3",
+		Customizations: &Customizations{
+			OstreeRemote: &OSTreeRemote{
+				Url:        "https://edge.example.com/repo",
+				ContentUrl: common.ToPtr("mirrorlist=https://edge.example.com/mirrors"),
+				GpgKey:     common.ToPtr(key),
+			},
+		},
+	}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{
+			Path: "/etc/ostree/remotes.d/rhel-edge.conf",
+			Mode: "0644",
+			Data: "[remote \"rhel-edge\"]\nurl=https://edge.example.com/repo\ncontenturl=mirrorlist=https://edge.example.com/mirrors\ngpg-verify=true\ngpgkeypath=/etc/ostree/remotes.d/rhel-edge.gpg\n",
+		},
+		{
+			Path: "/etc/ostree/remotes.d/rhel-edge.gpg",
+			Mode: "0644",
+			Data: key,
+		},
+	}, bp.Customizations.Files)
+
+	// fedora deploys the commits from its own remote, unverified without a key
+	cr.Distribution = "fedora-39"
+	cr.Customizations.OstreeRemote = &OSTreeRemote{Url: "https://iot.example.com/repo"}
+	bp, err = cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{
+			Path: "/etc/ostree/remotes.d/fedora-iot.conf",
+			Mode: "0644",
+			Data: "[remote \"fedora-iot\"]\nurl=https://iot.example.com/repo\ngpg-verify=false\n",
+		},
+	}, bp.Customizations.Files)
+
+	cr.Customizations.OstreeRemote.GpgKey = common.ToPtr("not a key")
+	_, err = cr.GetBlueprintWithCustomizations()
+	assert.Error(t, err)
+
+	cr.Customizations.OstreeRemote = &OSTreeRemote{Name: common.ToPtr("custom"), Url: "https://iot.example.com/repo"}
+	cr.Customizations.Files = &[]File{{Path: "/etc/ostree/remotes.d/custom.conf"}}
+	_, err = cr.GetBlueprintWithCustomizations()
+	assert.Error(t, err)
+}
+
+func testCACert(t *testing.T, serial int64) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestGetBlueprintWithCACerts(t *testing.T) {
+	ca1 := testCACert(t, 0x1a2b)
+	ca2 := testCACert(t, 42)
+	cr := ComposeRequest{
+		Customizations: &Customizations{
+			Cacerts: &CACertsCustomization{PemCerts: []string{ca1, ca2}},
+		},
+	}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	require.Len(t, bp.Customizations.Files, 3)
+	assert.Equal(t, blueprint.FileCustomization{
+		Path: "/etc/pki/ca-trust/source/anchors/1a2b.pem",
+		Mode: "0644",
+		Data: ca1,
+	}, bp.Customizations.Files[0])
+	assert.Equal(t, "/etc/pki/ca-trust/source/anchors/2a.pem", bp.Customizations.Files[1].Path)
+	assert.Equal(t, "/etc/systemd/system/osbuild-ca-trust.service", bp.Customizations.Files[2].Path)
+	assert.Contains(t, bp.Customizations.Files[2].Data, "ExecStart=/usr/bin/update-ca-trust extract\n")
+	assert.Equal(t, []string{"osbuild-ca-trust.service"}, bp.Customizations.Services.Enabled)
+	assert.Equal(t, []string{"ca-certificates"}, cr.requiredPackages())
+
+	for _, invalid := range [][]string{
+		{"not a certificate"},
+		{ca1 + ca2},
+		{ca1, ca1},
+		{"-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n"},
+	} {
+		cr.Customizations.Cacerts.PemCerts = invalid
+		_, err = cr.GetBlueprintWithCustomizations()
+		assert.Error(t, err)
+	}
+}
+
+func TestGetBlueprintWithSubscriptionFiles(t *testing.T) {
+	cr := ComposeRequest{
+		Customizations: &Customizations{
+			Subscription: &Subscription{
+				Organization:  "2040324",
+				ActivationKey: "my-secret-key",
+				ServerUrl:     "subscription.rhsm.redhat.com",
+				BaseUrl:       "http://cdn.redhat.com/",
+				Rhc:           common.ToPtr(true),
+				InsightsTags: &Subscription_InsightsTags{AdditionalProperties: map[string]string{
+					"site":        "brno",
+					"environment": "production: eu",
+				}},
+				Facts: &Subscription_Facts{AdditionalProperties: map[string]string{
+					"osbuild.image": "edge-gateway",
+				}},
+			},
+		},
+	}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{
+			Path: "/etc/insights-client/tags.yaml",
+			Mode: "0644",
+			Data: "# Generated from the subscription customization of the image\n\"environment\": \"production: eu\"\n\"site\": \"brno\"\n",
+		},
+		{
+			Path: "/etc/rhsm/facts/osbuild.facts",
+			Mode: "0644",
+			Data: "{\n  \"osbuild.image\": \"edge-gateway\"\n}\n",
+		},
+	}, bp.Customizations.Files)
+
+	// the tags are only read by insights-client
+	cr.Customizations.Subscription.Rhc = nil
+	_, err = cr.GetBlueprintWithCustomizations()
+	assert.Error(t, err)
+	cr.Customizations.Subscription.Insights = true
+	_, err = cr.GetBlueprintWithCustomizations()
+	assert.NoError(t, err)
+}
+
+func TestGetBlueprintWithInvalidCustomRepositories(t *testing.T) {
+	for _, repos := range [][]CustomRepository{
+		// no URL to fetch the packages from
+		{{Id: "custom"}},
+		// gpg check without keys
+		{{Id: "custom", Baseurl: &[]string{"http://example.org/repo"}, CheckGpg: common.ToPtr(true)}},
+		{{Id: "custom", Baseurl: &[]string{"http://example.org/repo"}, Filename: common.ToPtr("../custom")}},
+		{{Id: "custom", Baseurl: &[]string{"http://example.org/repo"}}, {Id: "custom", Baseurl: &[]string{"http://example.org/repo2"}}},
+	} {
+		cr := ComposeRequest{Customizations: &Customizations{CustomRepositories: &repos}}
+		_, err := cr.GetBlueprintWithCustomizations()
+		assert.Error(t, err, repos)
+	}
+}
+
+func TestGetDepsolveModules(t *testing.T) {
+	cr := ComposeRequest{}
+	modules, err := cr.GetDepsolveModules()
+	require.NoError(t, err)
+	assert.Nil(t, modules)
+
+	cr.Customizations = &Customizations{Modules: &Modules{
+		Enable:  &[]string{"nodejs:18", "ruby:3.1"},
+		Disable: &[]string{"postgresql"},
+	}}
+	modules, err = cr.GetDepsolveModules()
+	require.NoError(t, err)
+	assert.Equal(t, &worker.DepsolveModules{
+		Enable:  []string{"nodejs:18", "ruby:3.1"},
+		Disable: []string{"postgresql"},
+	}, modules)
+
+	cr.Customizations.Modules.Enable = &[]string{"nodejs:18", "nodejs:20"}
+	_, err = cr.GetDepsolveModules()
+	assert.Error(t, err)
+
+	cr.Customizations.Modules.Enable = &[]string{"postgresql:15"}
+	_, err = cr.GetDepsolveModules()
+	assert.Error(t, err)
+}
+
+func TestGetExcludedPackages(t *testing.T) {
+	cr := ComposeRequest{}
+	exclude, err := cr.GetExcludedPackages()
+	require.NoError(t, err)
+	assert.Nil(t, exclude)
+
+	cr.Customizations = &Customizations{
+		Packages:        &[]string{"vim-enhanced"},
+		ExcludePackages: &[]string{"linux-firmware", "iwl*-firmware"},
+	}
+	exclude, err = cr.GetExcludedPackages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"linux-firmware", "iwl*-firmware"}, exclude)
+
+	// packages of the request can't be excluded
+	cr.Customizations.ExcludePackages = &[]string{"vim-*"}
+	_, err = cr.GetExcludedPackages()
+	assert.Error(t, err)
+
+	cr.Customizations.ExcludePackages = &[]string{"chrony"}
+	cr.Customizations.Ntp = &NTP{Servers: []NTPServer{{Hostname: "time.example.com"}}}
+	_, err = cr.GetExcludedPackages()
+	assert.Error(t, err)
+}
+
+func TestGetBlueprintWithInstallWeakDeps(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.False(t, bp.Minimal)
+
+	cr.Customizations.InstallWeakDeps = common.ToPtr(true)
+	bp, err = cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.False(t, bp.Minimal)
+
+	cr.Customizations.InstallWeakDeps = common.ToPtr(false)
+	bp, err = cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.True(t, bp.Minimal)
+}
+
+func TestGetBlueprintWithIdentity(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{
+		Identity: &Identity{ClearMachineId: common.ToPtr(true)},
+	}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{{Path: "/etc/machine-id", Mode: "0444"}}, bp.Customizations.Files)
+	assert.Nil(t, bp.Customizations.Services)
+
+	cr.Customizations = &Customizations{
+		Identity: &Identity{
+			RemoveSshHostKeys:  common.ToPtr(true),
+			RemoveSubscription: common.ToPtr(true),
+		},
+		Services: &Services{Enabled: &[]string{"sshd"}},
+	}
+	bp, err = cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	require.Len(t, bp.Customizations.Files, 2)
+	assert.Equal(t, "/etc/systemd/system/osbuild-identity-cleanup.service", bp.Customizations.Files[0].Path)
+	assert.Contains(t, bp.Customizations.Files[0].Data, "ExecStart=/usr/bin/sh -c 'rm -f /etc/ssh/ssh_host_*_key /etc/ssh/ssh_host_*_key.pub /etc/pki/consumer/*.pem /etc/pki/entitlement/*.pem /etc/insights-client/machine-id'\n")
+	assert.Contains(t, bp.Customizations.Files[0].Data, "ConditionPathExists=/etc/osbuild-identity-cleanup\n")
+	assert.Equal(t, "/etc/osbuild-identity-cleanup", bp.Customizations.Files[1].Path)
+	assert.Equal(t, []string{"sshd", "osbuild-identity-cleanup.service"}, bp.Customizations.Services.Enabled)
+
+	// the files are valid for the images library
+	ibp := blueprint.Convert(bp)
+	_, err = ibp.Customizations.GetFiles()[0].ToFsNodeFile()
+	require.NoError(t, err)
+	_, err = ibp.Customizations.GetFiles()[1].ToFsNodeFile()
+	require.NoError(t, err)
+
+	cr.Customizations.Files = &[]File{{Path: "/etc/machine-id", Data: common.ToPtr("42")}}
+	cr.Customizations.Identity.ClearMachineId = common.ToPtr(true)
+	_, err = cr.GetBlueprintWithCustomizations()
+	assert.Error(t, err)
+}
+
+func TestGetBlueprintWithNetworkConnections(t *testing.T) {
+	keyfile := `[connection]
+id=eth0
+uuid=2a4bd0e9-5e4f-4a5e-9e6b-77ec0d7c2d28
+type=ethernet
+interface-name=eth0
+
+[ipv4]
+method=manual
+address1=192.168.1.10/24,192.168.1.1
+dns=192.168.1.1;
+`
+	wifi := "[connection]\nid=iot\ntype=wifi\n\n[wifi]\nssid=iot\n\n[wifi-security]\nkey-mgmt=wpa-psk\npsk=se#cr;et\n"
+	cr := ComposeRequest{Customizations: &Customizations{
+		NetworkConnections: &[]NetworkConnection{
+			{Name: "eth0", Keyfile: keyfile},
+			{Name: "iot", Keyfile: wifi},
+		},
+	}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{Path: "/etc/NetworkManager/system-connections/eth0.nmconnection", Mode: "0600", Data: keyfile},
+		{Path: "/etc/NetworkManager/system-connections/iot.nmconnection", Mode: "0600", Data: wifi},
+	}, bp.Customizations.Files)
+	assert.Contains(t, cr.requiredPackages(), "NetworkManager")
+
+	for _, connections := range [][]NetworkConnection{
+		{{Name: "eth0", Keyfile: "[connection\nid=eth0\n"}},
+		{{Name: "eth0", Keyfile: "[ipv4]\nmethod=auto\n"}},
+		{{Name: "eth0", Keyfile: "[connection]\nid=eth0\n"}},
+		{{Name: "eth0", Keyfile: "[connection]\nid=eth0\ntype=ethernet\nuuid=eth0\n"}},
+		{{Name: "eth0", Keyfile: keyfile}, {Name: "eth0", Keyfile: wifi}},
+		{{Name: "eth0", Keyfile: keyfile}, {Name: "eth1", Keyfile: keyfile}},
+	} {
+		cr.Customizations.NetworkConnections = &connections
+		_, err = cr.GetBlueprintWithCustomizations()
+		assert.Error(t, err)
+	}
+}
+
+func TestGetBlueprintWithSshd(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{
+		Users: &[]User{
+			{Name: "admin", Key: common.ToPtr("ssh-ed25519 AAAA"), Groups: &[]string{"wheel"}},
+			{Name: "root", Password: common.ToPtr("$6$salt$hash")},
+		},
+		Firewall: &FirewallCustomization{
+			Ports: &[]string{"2222:tcp"},
+		},
+		Sshd: &Sshd{
+			PermitRootLogin:        common.ToPtr(SshdPermitRootLoginNo),
+			PasswordAuthentication: common.ToPtr(false),
+			AllowUsers:             &[]string{"adm*@192.168.1.0/24"},
+			AllowGroups:            &[]string{"wheel"},
+			Ports:                  &[]int{22, 2222},
+		},
+	}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{
+			Path: "/etc/ssh/sshd_config.d/01-customizations.conf",
+			Mode: "0600",
+			Data: "PermitRootLogin no\nPasswordAuthentication no\nAllowUsers adm*@192.168.1.0/24\nAllowGroups wheel\nPort 22\nPort 2222\n",
+		},
+	}, bp.Customizations.Files)
+	assert.Equal(t, []string{"2222:tcp", "22:tcp"}, bp.Customizations.Firewall.Ports)
+
+	for _, sshd := range []Sshd{
+		{PermitRootLogin: common.ToPtr(SshdPermitRootLoginNo)},
+		{AllowUsers: &[]string{"root", "user?"}},
+		{AllowGroups: &[]string{"admin"}},
+		{Ports: &[]int{2222, 2222}},
+	} {
+		sshd := sshd
+		cr.Customizations.Sshd = &sshd
+		cr.Customizations.Users = &[]User{
+			{Name: "admin", Key: common.ToPtr("ssh-ed25519 AAAA"), Groups: &[]string{"wheel"}},
+			{Name: "root", Key: common.ToPtr("ssh-ed25519 AAAA")},
+		}
+		_, err = cr.GetBlueprintWithCustomizations()
+		assert.Error(t, err)
+	}
+}
+
+func TestGetBlueprintWithDracut(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{
+		Dracut: &Dracut{
+			AddModules:   &[]string{"iscsi", "nvmf"},
+			OmitModules:  &[]string{"plymouth"},
+			AddDrivers:   &[]string{"nvme_tcp"},
+			InstallItems: &[]string{"/etc/iscsi/initiatorname.iscsi"},
+		},
+	}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{
+			Path: "/etc/dracut.conf.d/99-customizations.conf",
+			Mode: "0644",
+			Data: `add_dracutmodules+=" iscsi nvmf "
+omit_dracutmodules+=" plymouth "
+add_drivers+=" nvme_tcp "
+install_items+=" /etc/iscsi/initiatorname.iscsi "
+`,
+		},
+	}, bp.Customizations.Files)
+
+	for _, dracut := range []Dracut{
+		{AddModules: &[]string{"iscsi"}, OmitModules: &[]string{"iscsi"}},
+		{ForceDrivers: &[]string{"nvme_tcp"}, OmitDrivers: &[]string{"nvme_tcp"}},
+	} {
+		dracut := dracut
+		cr.Customizations.Dracut = &dracut
+		_, err = cr.GetBlueprintWithCustomizations()
+		assert.Error(t, err)
+	}
+}
+
+func TestGetBlueprintWithNetworkMounts(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{
+		NetworkMounts: &[]NetworkMount{
+			{
+				Type:       NetworkMountTypeNfs,
+				Source:     "nfs.example.com:/exports/kiosk",
+				Mountpoint: "/mnt/kiosk",
+				Options:    &[]string{"ro"},
+			},
+			{
+				Type:       NetworkMountTypeCifs,
+				Source:     "//smb.example.com/media",
+				Mountpoint: "/srv/kiosk-media",
+				Automount:  common.ToPtr(true),
+				Credentials: &NetworkMountCredentials{
+					Username: "kiosk",
+					Password: "secret",
+				},
+			},
+		},
+	}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.DirectoryCustomization{
+		{Path: "/etc/cifs-credentials", Mode: "0700"},
+	}, bp.Customizations.Directories)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{
+			Path: "/etc/systemd/system/mnt-kiosk.mount",
+			Mode: "0644",
+			Data: `[Unit]
+Description=Mount nfs.example.com:/exports/kiosk at /mnt/kiosk
+
+[Mount]
+What=nfs.example.com:/exports/kiosk
+Where=/mnt/kiosk
+Type=nfs
+Options=_netdev,ro
+
+[Install]
+WantedBy=remote-fs.target
+`,
+		},
+		{
+			Path: `/etc/cifs-credentials/srv-kiosk\x2dmedia.cred`,
+			Mode: "0600",
+			Data: "username=kiosk\npassword=secret\n",
+		},
+		{
+			Path: `/etc/systemd/system/srv-kiosk\x2dmedia.mount`,
+			Mode: "0644",
+			Data: `[Unit]
+Description=Mount //smb.example.com/media at /srv/kiosk-media
+
+[Mount]
+What=//smb.example.com/media
+Where=/srv/kiosk-media
+Type=cifs
+Options=_netdev,credentials=/etc/cifs-credentials/srv-kiosk\x2dmedia.cred
+`,
+		},
+		{
+			Path: `/etc/systemd/system/srv-kiosk\x2dmedia.automount`,
+			Mode: "0644",
+			Data: `[Unit]
+Description=Mount //smb.example.com/media at /srv/kiosk-media on access
+
+[Automount]
+Where=/srv/kiosk-media
+
+[Install]
+WantedBy=remote-fs.target
+`,
+		},
+	}, bp.Customizations.Files)
+	assert.Equal(t, []string{"mnt-kiosk.mount", `srv-kiosk\x2dmedia.automount`}, bp.Customizations.Services.Enabled)
+	assert.Equal(t, []string{"nfs-utils", "cifs-utils"}, cr.requiredPackages())
+
+	for _, mounts := range [][]NetworkMount{
+		{{Type: NetworkMountTypeNfs, Source: "nfs.example.com", Mountpoint: "/mnt"}},
+		{{Type: NetworkMountTypeCifs, Source: "smb.example.com:/media", Mountpoint: "/mnt"}},
+		{{Type: NetworkMountTypeNfs, Source: "nfs.example.com:/", Mountpoint: "/mnt", Credentials: &NetworkMountCredentials{}}},
+		{{Type: NetworkMountTypeNfs, Source: "nfs.example.com:/", Mountpoint: "/mnt/"}},
+		{{Type: NetworkMountTypeNfs, Source: "nfs.example.com:/", Mountpoint: "/"}},
+		{
+			{Type: NetworkMountTypeNfs, Source: "nfs.example.com:/a", Mountpoint: "/mnt"},
+			{Type: NetworkMountTypeNfs, Source: "nfs.example.com:/b", Mountpoint: "/mnt"},
+		},
+	} {
+		mounts := mounts
+		cr.Customizations.NetworkMounts = &mounts
+		_, err = cr.GetBlueprintWithCustomizations()
+		assert.Error(t, err)
+	}
+}
+
+func TestGetBlueprintWithSysctl(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{Sysctl: &[]Sysctl{
+		{Key: "vm.max_map_count", Value: common.ToPtr("262144")},
+		{Key: "-net.ipv4.conf.eth0.rp_filter"},
+	}}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{
+			Path: "/etc/sysctl.d/99-customizations.conf",
+			Mode: "0644",
+			Data: "vm.max_map_count = 262144\n-net.ipv4.conf.eth0.rp_filter\n",
+		},
+	}, bp.Customizations.Files)
+
+	for _, sysctl := range []Sysctl{
+		{Key: "vm.max_map_count"},
+		{Key: "vm.max_map_count", Value: common.ToPtr("1\nkernel.sysrq = 1")},
+	} {
+		cr.Customizations.Sysctl = &[]Sysctl{sysctl}
+		_, err = cr.GetBlueprintWithCustomizations()
+		assert.Error(t, err)
+	}
+}
+
+func TestGetBlueprintWithUdevRules(t *testing.T) {
+	cr := ComposeRequest{Customizations: &Customizations{UdevRules: &[]UdevRule{
+		{
+			Comment: common.ToPtr("schedule the disks with none"),
+			Ops: []UdevOp{
+				{Key: "ACTION", Op: "==", Value: "add|change"},
+				{Key: "SUBSYSTEM", Op: "==", Value: "block"},
+				{Key: "ATTR", Arg: common.ToPtr("queue/scheduler"), Op: "=", Value: "none"},
+			},
+		},
+	}}}
+	bp, err := cr.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	assert.Equal(t, []blueprint.FileCustomization{
+		{
+			Path: "/etc/udev/rules.d/99-customizations.rules",
+			Mode: "0644",
+			Data: `# schedule the disks with none
+ACTION=="add|change", SUBSYSTEM=="block", ATTR{queue/scheduler}="none"
+`,
+		},
+	}, bp.Customizations.Files)
+
+	for _, op := range []UdevOp{
+		{Key: "UNKNOWN", Op: "==", Value: "1"},
+		{Key: "ATTR", Op: "=", Value: "none"},
+		{Key: "SUBSYSTEM", Op: "=", Value: "block"},
+		{Key: "SUBSYSTEM", Op: "==", Value: "block\"\nRUN+=\"/bin/sh"},
+	} {
+		cr.Customizations.UdevRules = &[]UdevRule{{Ops: []UdevOp{op}}}
+		_, err = cr.GetBlueprintWithCustomizations()
+		assert.Error(t, err, op.Key)
+	}
+}
@@ -0,0 +1,65 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+// dracutConfigPath is the drop-in with the dracut customization, dracut
+// reads it whenever it generates an initramfs.
+const dracutConfigPath = "/etc/dracut.conf.d/99-customizations.conf"
+
+// dracutConfigFile returns the drop-in configuring dracut, the modules and
+// the drivers can't be both added and omitted.
+func dracutConfigFile(dracut *Dracut) (*blueprint.FileCustomization, error) {
+	for _, conflict := range []struct {
+		kind      string
+		add, omit *[]string
+	}{
+		{"dracut module", dracut.AddModules, dracut.OmitModules},
+		{"driver", dracut.AddDrivers, dracut.OmitDrivers},
+		{"driver", dracut.ForceDrivers, dracut.OmitDrivers},
+	} {
+		if conflict.add == nil || conflict.omit == nil {
+			continue
+		}
+		for _, name := range *conflict.add {
+			for _, omitted := range *conflict.omit {
+				if name == omitted {
+					return nil, fmt.Errorf("the %s %s can't be both added and omitted", conflict.kind, name)
+				}
+			}
+		}
+	}
+
+	var config strings.Builder
+	for _, option := range []struct {
+		key    string
+		values *[]string
+	}{
+		{"add_dracutmodules", dracut.AddModules},
+		{"omit_dracutmodules", dracut.OmitModules},
+		{"add_drivers", dracut.AddDrivers},
+		{"omit_drivers", dracut.OmitDrivers},
+		{"force_drivers", dracut.ForceDrivers},
+		{"install_items", dracut.InstallItems},
+	} {
+		if option.values == nil || len(*option.values) == 0 {
+			continue
+		}
+		// the leading and trailing spaces keep the values apart from the
+		// ones of the other drop-ins
+		fmt.Fprintf(&config, "%s+=\" %s \"\n", option.key, strings.Join(*option.values, " "))
+	}
+	if config.Len() == 0 {
+		return nil, nil
+	}
+
+	return &blueprint.FileCustomization{
+		Path: dracutConfigPath,
+		Mode: "0644",
+		Data: config.String(),
+	}, nil
+}
@@ -49,6 +49,22 @@ const (
 	ErrorLocalSaveNotEnabled          ServiceErrorCode = 36
 	ErrorInvalidPartitioningMode      ServiceErrorCode = 37
 	ErrorInvalidUploadTarget          ServiceErrorCode = 38
+	ErrorComposeRequestNotAvailable   ServiceErrorCode = 39
+	ErrorWorkerPinningNotAllowed      ServiceErrorCode = 40
+	ErrorImageCatalogNotEnabled       ServiceErrorCode = 41
+	ErrorComposeNotScanned            ServiceErrorCode = 42
+	ErrorKojiVulnerabilityScan        ServiceErrorCode = 43
+	ErrorSigningNotSupported          ServiceErrorCode = 44
+	ErrorComposeNotSigned             ServiceErrorCode = 45
+	ErrorNoAMIToClone                 ServiceErrorCode = 46
+	ErrorNoAMIToPublish               ServiceErrorCode = 47
+	ErrorComposeNotExported           ServiceErrorCode = 48
+	ErrorComposeNotMirrored           ServiceErrorCode = 49
+	ErrorChecksumManifestNotSupported ServiceErrorCode = 50
+	ErrorKMSKeyRequired               ServiceErrorCode = 51
+	ErrorKojiServerNotAllowed         ServiceErrorCode = 52
+	ErrorKojiTagNotAllowed            ServiceErrorCode = 53
+	ErrorBlueprintFragmentNotFound    ServiceErrorCode = 54
 
 	// Internal errors, these are bugs
 	ErrorFailedToInitializeBlueprint              ServiceErrorCode = 1000
@@ -72,6 +88,13 @@ const (
 	ErrorGettingAWSEC2JobStatus                   ServiceErrorCode = 1018
 	ErrorGettingJobType                           ServiceErrorCode = 1019
 	ErrorTenantNotInContext                       ServiceErrorCode = 1020
+	ErrorFailedToSignManifest                     ServiceErrorCode = 1021
+	ErrorGettingImageCatalog                      ServiceErrorCode = 1022
+	ErrorGettingVulnerabilityScanStatus           ServiceErrorCode = 1023
+	ErrorGettingSignStatus                        ServiceErrorCode = 1024
+	ErrorGettingMarketplacePublishStatus          ServiceErrorCode = 1025
+	ErrorGettingImageExportStatus                 ServiceErrorCode = 1026
+	ErrorGettingOSTreeMirrorStatus                ServiceErrorCode = 1027
 
 	// Errors contained within this file
 	ErrorUnspecified          ServiceErrorCode = 10000
@@ -129,6 +152,22 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorLocalSaveNotEnabled, http.StatusBadRequest, "local_save is not enabled"},
 		serviceError{ErrorInvalidPartitioningMode, http.StatusBadRequest, "Requested partitioning mode is invalid"},
 		serviceError{ErrorInvalidUploadTarget, http.StatusBadRequest, "Invalid upload target for image type"},
+		serviceError{ErrorComposeRequestNotAvailable, http.StatusBadRequest, "The request of the compose isn't available"},
+		serviceError{ErrorWorkerPinningNotAllowed, http.StatusForbidden, "Only administrators can pin a compose to a worker"},
+		serviceError{ErrorImageCatalogNotEnabled, http.StatusNotFound, "The image catalog is not enabled"},
+		serviceError{ErrorComposeNotScanned, http.StatusNotFound, "The packages of the compose aren't scanned for vulnerabilities"},
+		serviceError{ErrorKojiVulnerabilityScan, http.StatusBadRequest, "Vulnerability scans aren't supported for koji builds"},
+		serviceError{ErrorSigningNotSupported, http.StatusBadRequest, "Only artifacts uploaded to S3 can be signed"},
+		serviceError{ErrorComposeNotSigned, http.StatusNotFound, "The artifacts of the compose aren't signed"},
+		serviceError{ErrorNoAMIToClone, http.StatusBadRequest, "Only a snapshot was imported, there is no AMI to clone"},
+		serviceError{ErrorNoAMIToPublish, http.StatusBadRequest, "The compose didn't produce an AMI which can be published"},
+		serviceError{ErrorComposeNotExported, http.StatusNotFound, "The image of the compose isn't exported"},
+		serviceError{ErrorComposeNotMirrored, http.StatusNotFound, "The ostree commit of the compose isn't mirrored"},
+		serviceError{ErrorChecksumManifestNotSupported, http.StatusBadRequest, "Checksum manifests are only published for artifacts uploaded to S3"},
+		serviceError{ErrorKMSKeyRequired, http.StatusBadRequest, "Copies of encrypted AMIs can only be shared if encrypted with a KMS key of the target region"},
+		serviceError{ErrorKojiServerNotAllowed, http.StatusBadRequest, "Builds can't be imported to the requested Koji server"},
+		serviceError{ErrorKojiTagNotAllowed, http.StatusBadRequest, "Builds can't be tagged into the requested Koji tag"},
+		serviceError{ErrorBlueprintFragmentNotFound, http.StatusBadRequest, "Blueprint fragment not found"},
 
 		serviceError{ErrorFailedToInitializeBlueprint, http.StatusInternalServerError, "Failed to initialize blueprint"},
 		serviceError{ErrorFailedToGenerateManifestSeed, http.StatusInternalServerError, "Failed to generate manifest seed"},
@@ -151,6 +190,13 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorGettingAWSEC2JobStatus, http.StatusInternalServerError, "Unable to get ec2 job status"},
 		serviceError{ErrorGettingJobType, http.StatusInternalServerError, "Unable to get job type of existing job"},
 		serviceError{ErrorTenantNotInContext, http.StatusInternalServerError, "Unable to retrieve tenant from request context"},
+		serviceError{ErrorFailedToSignManifest, http.StatusInternalServerError, "Unable to compute digest or signature of manifest"},
+		serviceError{ErrorGettingImageCatalog, http.StatusInternalServerError, "Unable to get the image catalog"},
+		serviceError{ErrorGettingVulnerabilityScanStatus, http.StatusInternalServerError, "Unable to get the status of the vulnerability scan"},
+		serviceError{ErrorGettingSignStatus, http.StatusInternalServerError, "Unable to get the status of the signing job"},
+		serviceError{ErrorGettingMarketplacePublishStatus, http.StatusInternalServerError, "Unable to get the status of the marketplace publish job"},
+		serviceError{ErrorGettingImageExportStatus, http.StatusInternalServerError, "Unable to get the status of the image export job"},
+		serviceError{ErrorGettingOSTreeMirrorStatus, http.StatusInternalServerError, "Unable to get the status of the ostree mirror job"},
 
 		serviceError{ErrorUnspecified, http.StatusInternalServerError, "Unspecified internal error "},
 		serviceError{ErrorNotHTTPError, http.StatusInternalServerError, "Error is not an instance of HTTPError"},
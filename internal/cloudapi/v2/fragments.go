@@ -0,0 +1,143 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// LoadBlueprintFragments loads the blueprint fragments compose requests can
+// reference from the JSON files of the directory. Each file holds the
+// customizations of a compose request, the fragments are named after the
+// files without the .json extension.
+func LoadBlueprintFragments(dir string) (map[string]Customizations, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	fragments := map[string]Customizations{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		var customizations Customizations
+		err = decoder.Decode(&customizations)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse blueprint fragment %s: %v", path, err)
+		}
+		fragments[strings.TrimSuffix(filepath.Base(path), ".json")] = customizations
+	}
+	return fragments, nil
+}
+
+// mergeBlueprintFragments merges the customizations of the blueprint
+// fragments the request references in order, and then the ones of the
+// request. The lists are concatenated and the objects merged, the other
+// values have to be the same in all of them. The fragments are dropped from
+// the request, so it builds the same image once they change.
+func mergeBlueprintFragments(request *ComposeRequest, fragments map[string]Customizations) error {
+	if request.BlueprintFragments == nil {
+		return nil
+	}
+
+	merged := map[string]interface{}{}
+	seen := map[string]bool{}
+	for _, name := range *request.BlueprintFragments {
+		if seen[name] {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("blueprint fragment %s is referenced twice", name))
+		}
+		seen[name] = true
+		fragment, ok := fragments[name]
+		if !ok {
+			return HTTPErrorWithInternal(ErrorBlueprintFragmentNotFound, fmt.Errorf("blueprint fragment %s doesn't exist", name))
+		}
+		err := mergeCustomizations(merged, &fragment, "blueprint fragment "+name)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+	}
+	if request.Customizations != nil {
+		err := mergeCustomizations(merged, request.Customizations, "the request")
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, err)
+		}
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorJSONMarshallingError, err)
+	}
+	var customizations Customizations
+	err = json.Unmarshal(data, &customizations)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorJSONUnMarshallingError, err)
+	}
+	request.Customizations = &customizations
+	request.BlueprintFragments = nil
+	return nil
+}
+
+// mergeCustomizations merges the customizations into the merged ones, in
+// their JSON representation.
+func mergeCustomizations(merged map[string]interface{}, customizations *Customizations, source string) error {
+	data, err := json.Marshal(customizations)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keep the numbers as they are, integers don't survive float64
+	decoder.UseNumber()
+	var values map[string]interface{}
+	err = decoder.Decode(&values)
+	if err != nil {
+		return err
+	}
+	return mergeValues(merged, values, "", source)
+}
+
+func mergeValues(merged, values map[string]interface{}, prefix, source string) error {
+	// merge in a fixed order, so the errors are the same for each request
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+		existing, ok := merged[key]
+		if !ok {
+			merged[key] = value
+			continue
+		}
+		switch v := value.(type) {
+		case []interface{}:
+			if e, ok := existing.([]interface{}); ok {
+				merged[key] = append(e, v...)
+				continue
+			}
+		case map[string]interface{}:
+			if e, ok := existing.(map[string]interface{}); ok {
+				err := mergeValues(e, v, prefix+key+".", source)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		default:
+			if reflect.DeepEqual(existing, value) {
+				continue
+			}
+		}
+		return fmt.Errorf("%s sets the %s%s customization to another value than the blueprint fragments", source, prefix, key)
+	}
+	return nil
+}
@@ -3,14 +3,20 @@ package v2
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
 	"net/http"
 	"os"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -20,6 +26,7 @@ import (
 	"github.com/osbuild/images/pkg/osbuild"
 	"github.com/osbuild/images/pkg/rhsm/facts"
 	"github.com/osbuild/images/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/worker"
@@ -88,6 +95,80 @@ func (h *apiHandlers) GetError(ctx echo.Context, id string) error {
 	return ctx.JSON(http.StatusOK, apiError)
 }
 
+func (h *apiHandlers) GetImageCatalog(ctx echo.Context, params GetImageCatalogParams) error {
+	page := 0
+	var err error
+	if params.Page != nil {
+		page, err = strconv.Atoi(string(*params.Page))
+		if err != nil || page < 0 {
+			return HTTPError(ErrorInvalidPageParam)
+		}
+	}
+
+	size := 100
+	if params.Size != nil {
+		size, err = strconv.Atoi(string(*params.Size))
+		if err != nil || size < 0 {
+			return HTTPError(ErrorInvalidSizeParam)
+		}
+	}
+
+	// channel is empty if JWT is not enabled
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+
+	images, err := h.server.workers.ImageCatalog(channel)
+	if err != nil {
+		if errors.Is(err, worker.ErrImageCatalogDisabled) {
+			return HTTPError(ErrorImageCatalogNotEnabled)
+		}
+		return HTTPErrorWithInternal(ErrorGettingImageCatalog, err)
+	}
+
+	items := []CatalogImage{}
+	for _, image := range images {
+		us, err := targetResultToUploadStatus(image.Target)
+		if err != nil {
+			ctx.Logger().Warnf("Not listing image of job %s in the catalog: %v", image.JobId, err)
+			continue
+		}
+		if params.Type != nil && us.Type != *params.Type {
+			continue
+		}
+		item := CatalogImage{
+			Id:               image.JobId.String(),
+			DeliveredAt:      image.Delivered,
+			Type:             us.Type,
+			Options:          us.Options,
+			ArtifactChecksum: us.ArtifactChecksum,
+		}
+		if image.Arch != "" {
+			item.Architecture = common.ToPtr(image.Arch)
+		}
+		items = append(items, item)
+	}
+
+	min := func(a, b int) int {
+		if a < b {
+			return a
+		}
+		return b
+	}
+	start := min(page*size, len(items))
+	end := min(start+size, len(items))
+	return ctx.JSON(http.StatusOK, CatalogImageList{
+		List: List{
+			Kind:  "CatalogImageList",
+			Page:  page,
+			Size:  end - start,
+			Total: len(items),
+		},
+		Items: items[start:end],
+	})
+}
+
 // splitExtension returns the extension of the given file. If there's
 // a multipart extension (e.g. file.tar.gz), it returns all parts (e.g.
 // .tar.gz). If there's no extension in the input, it returns an empty
@@ -140,6 +221,16 @@ type imageRequest struct {
 	repositories []rpmmd.RepoConfig
 	imageOptions distro.ImageOptions
 	targets      []*target.Target
+	// packages which have to be part of the depsolved payload
+	requiredPackages []string
+	// credentials of the registries of the embedded containers
+	containerAuths map[string]worker.ContainerAuth
+	// DNF modules the payload packages are depsolved with
+	modules *worker.DepsolveModules
+	// packages excluded from the payload, by name or glob
+	excludePackages []string
+	// the payload is depsolved without weak dependencies
+	minimal bool
 }
 
 func (h *apiHandlers) PostCompose(ctx echo.Context) error {
@@ -155,27 +246,57 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
 	}
 
+	id, err := h.submitCompose(request, channel)
+	if err != nil {
+		return err
+	}
+
+	ctx.Logger().Infof("Job ID %s enqueued for operationID %s", id, ctx.Get(common.OperationIDKey))
+
+	return ctx.JSON(http.StatusCreated, &ComposeId{
+		ObjectReference: ObjectReference{
+			Href: "/api/image-builder-composer/v2/compose",
+			Id:   id.String(),
+			Kind: "ComposeId",
+		},
+		Id: id.String(),
+	})
+}
+
+// submitCompose validates the compose request and enqueues all of its jobs.
+func (h *apiHandlers) submitCompose(request ComposeRequest, channel string) (uuid.UUID, error) {
+	var id uuid.UUID
+
 	distribution := h.server.distros.GetDistro(request.Distribution)
 	if distribution == nil {
-		return HTTPError(ErrorUnsupportedDistribution)
+		return id, HTTPError(ErrorUnsupportedDistribution)
+	}
+
+	err := mergeBlueprintFragments(&request, h.server.config.BlueprintFragments)
+	if err != nil {
+		return id, err
 	}
 
 	// Create a blueprint from the customizations included in the request
 	bp, err := request.GetBlueprintWithCustomizations()
 	if err != nil {
-		return err
+		return id, err
 	}
 
-	// add the user-defined repositories only to the depsolve job for the
-	// payload (the packages for the final image)
-	payloadRepositories := request.GetPayloadRepositories()
-
 	// use the same seed for all images so we get the same IDs
 	bigSeed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
 	if err != nil {
-		return HTTPError(ErrorFailedToGenerateManifestSeed)
+		return id, HTTPError(ErrorFailedToGenerateManifestSeed)
 	}
 	manifestSeed := bigSeed.Int64()
+	if request.Reproducible != nil && *request.Reproducible {
+		// identical requests result in identical manifests, which can
+		// be deduplicated
+		manifestSeed, err = requestManifestSeed(request)
+		if err != nil {
+			return id, HTTPErrorWithInternal(ErrorFailedToGenerateManifestSeed, err)
+		}
+	}
 
 	// For backwards compatibility, we support both a single image request
 	// as well as an array of requests in the API. Exactly one must be
@@ -183,113 +304,297 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 	if request.ImageRequest != nil {
 		if request.ImageRequests != nil {
 			// we should really be using oneOf in the spec
-			return HTTPError(ErrorInvalidNumberOfImageBuilds)
+			return id, HTTPError(ErrorInvalidNumberOfImageBuilds)
 		}
 		request.ImageRequests = &[]ImageRequest{*request.ImageRequest}
+		request.ImageRequest = nil
 	}
 	if request.ImageRequests == nil {
-		return HTTPError(ErrorInvalidNumberOfImageBuilds)
+		return id, HTTPError(ErrorInvalidNumberOfImageBuilds)
 	}
 	var irs []imageRequest
 	for _, ir := range *request.ImageRequests {
-		arch, err := distribution.GetArch(ir.Architecture)
+		r, err := newImageRequest(&request, ir, distribution, bp)
 		if err != nil {
-			return HTTPError(ErrorUnsupportedArchitecture)
+			return id, err
 		}
-		imageType, err := arch.GetImageType(imageTypeFromApiImageType(ir.ImageType, arch))
-		if err != nil {
-			return HTTPError(ErrorUnsupportedImageType)
+		if !h.server.config.EnableMockTarget {
+			for _, t := range r.targets {
+				if t.Name == target.TargetNameMock {
+					return id, HTTPError(ErrorInvalidUploadTarget)
+				}
+			}
 		}
+		irs = append(irs, r)
+	}
 
-		repos, err := convertRepos(ir.Repositories, payloadRepositories, imageType.PayloadPackageSets())
-		if err != nil {
-			return err
+	var workerID string
+	if request.WorkerId != nil && *request.WorkerId != "" {
+		if !h.server.isAdmin(channel) {
+			return id, HTTPError(ErrorWorkerPinningNotAllowed)
 		}
+		workerID = *request.WorkerId
+	}
 
-		// Get the initial ImageOptions with image size set
-		imageOptions := ir.GetImageOptions(imageType, bp)
+	var deadline *time.Time
+	if request.Timeout != nil {
+		deadline = common.ToPtr(time.Now().Add(time.Duration(*request.Timeout) * time.Second))
+	}
 
-		if request.Koji == nil {
-			imageOptions.Facts = &facts.ImageOptions{
-				APIType: facts.CLOUDV2_APITYPE,
+	scanVulnerabilities := request.ScanVulnerabilities != nil && *request.ScanVulnerabilities
+
+	// the signatures are published next to the artifacts, which is only
+	// possible in S3 buckets
+	signArtifacts := request.SignArtifacts != nil && *request.SignArtifacts
+	if signArtifacts {
+		if request.Koji != nil {
+			return id, HTTPError(ErrorSigningNotSupported)
+		}
+		for _, ir := range irs {
+			for _, t := range ir.targets {
+				if t.Name != target.TargetNameAWSS3 {
+					return id, HTTPError(ErrorSigningNotSupported)
+				}
 			}
 		}
+	}
 
-		// Set Subscription from the compose request
-		imageOptions.Subscription = request.GetSubscription()
+	// the checksum manifest is published next to the artifacts too
+	checksumManifest := request.ChecksumManifest != nil
+	signChecksumManifest := checksumManifest && request.ChecksumManifest.Signed != nil && *request.ChecksumManifest.Signed
+	if checksumManifest {
+		if request.Koji != nil {
+			return id, HTTPError(ErrorChecksumManifestNotSupported)
+		}
+		for _, ir := range irs {
+			for _, t := range ir.targets {
+				if t.Name != target.TargetNameAWSS3 {
+					return id, HTTPError(ErrorChecksumManifestNotSupported)
+				}
+			}
+		}
+	}
 
-		// Set PartitioningMode from the compose request
-		imageOptions.PartitioningMode, err = request.GetPartitioningMode()
+	if request.Koji != nil {
+		if scanVulnerabilities {
+			return id, HTTPError(ErrorKojiVulnerabilityScan)
+		}
+		hub, err := h.server.kojiHub(request.Koji.Server)
 		if err != nil {
-			return err
+			return id, err
 		}
-
-		// Set OSTree options from the image request
-		imageOptions.OSTree, err = ir.GetOSTreeOptions()
+		var principal, tag string
+		if hub != nil {
+			principal = hub.Principal
+		}
+		if request.Koji.Tag != nil {
+			tag = *request.Koji.Tag
+			if hub == nil || !hub.allowsTag(tag) {
+				return id, HTTPErrorWithInternal(ErrorKojiTagNotAllowed, fmt.Errorf("tag %s isn't allowed for koji server %s", tag, request.Koji.Server))
+			}
+		}
+		id, err = h.server.enqueueKojiCompose(uint64(request.Koji.TaskId), request.Koji.Server, principal, tag, request.Koji.Name, request.Koji.Version, request.Koji.Release, distribution, bp, manifestSeed, irs, channel, workerID, deadline)
 		if err != nil {
-			return err
+			return id, err
 		}
+	} else {
+		// keep the request, so the compose can be rebuilt later, without
+		// the credentials
+		redacted, redactedFields, err := redactComposeRequest(request)
+		if err != nil {
+			return id, HTTPErrorWithInternal(ErrorJSONMarshallingError, err)
+		}
+		composeRequest, err := json.Marshal(redacted)
+		if err != nil {
+			return id, HTTPErrorWithInternal(ErrorJSONMarshallingError, err)
+		}
+		id, err = h.server.enqueueCompose(composeOptions{
+			distribution:         distribution,
+			blueprint:            bp,
+			manifestSeed:         manifestSeed,
+			channel:              channel,
+			workerID:             workerID,
+			deadline:             deadline,
+			request:              composeRequest,
+			redactedFields:       redactedFields,
+			scanVulnerabilities:  scanVulnerabilities,
+			signArtifacts:        signArtifacts,
+			checksumManifest:     checksumManifest,
+			signChecksumManifest: signChecksumManifest,
+		}, irs)
+		if err != nil {
+			return id, err
+		}
+	}
 
-		// Check to see if local_save is enabled and set
-		localSave, err := isLocalSave(ir.UploadOptions)
+	return id, nil
+}
+
+// newImageRequest validates the image request against the distribution and
+// collects everything needed to build the image.
+func newImageRequest(request *ComposeRequest, ir ImageRequest, distribution distro.Distro, bp blueprint.Blueprint) (imageRequest, error) {
+	arch, err := distribution.GetArch(ir.Architecture)
+	if err != nil {
+		return imageRequest{}, HTTPError(ErrorUnsupportedArchitecture)
+	}
+	imageType, err := arch.GetImageType(imageTypeFromApiImageType(ir.ImageType, arch))
+	if err != nil {
+		return imageRequest{}, HTTPError(ErrorUnsupportedImageType)
+	}
+
+	// the live installer boots its payload as it is installed, only the
+	// packages of the payload can be customized. Fail the request instead
+	// of the manifest generation.
+	if ir.ImageType == ImageTypesLiveInstaller {
+		err = bp.Customizations.CheckAllowed()
 		if err != nil {
-			return err
+			return imageRequest{}, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("live-installer images only support the packages customization: %v", err))
 		}
+	}
 
-		var irTargets []*target.Target
-		if ir.UploadOptions == nil && (ir.UploadTargets == nil || len(*ir.UploadTargets) == 0) {
-			// nowhere to put the image, this is a user error
-			if request.Koji == nil {
-				return HTTPError(ErrorJSONUnMarshallingError)
-			}
-		} else if localSave {
-			// Override the image type upload selection and save it locally
-			// Final image is in /var/lib/osbuild-composer/artifacts/UUID/
-			srvTarget := target.NewWorkerServerTarget()
-			srvTarget.ImageName = imageType.Filename()
-			srvTarget.OsbuildArtifact.ExportFilename = imageType.Filename()
-			srvTarget.OsbuildArtifact.ExportName = imageType.Exports()[0]
-			irTargets = []*target.Target{srvTarget}
-		} else {
-			// Get the target for the selected image type
-			irTargets, err = ir.GetTargets(&request, imageType)
-			if err != nil {
-				return err
-			}
+	// add the user-defined repositories only to the depsolve job for the
+	// payload (the packages for the final image)
+	repos, err := convertRepos(ir.Repositories, request.GetPayloadRepositories(), imageType.PayloadPackageSets())
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	// Get the initial ImageOptions with image size set
+	imageOptions := ir.GetImageOptions(imageType, bp)
+	if minSize := request.GetDiskMinSize(); minSize > imageOptions.Size {
+		imageOptions.Size = imageType.Size(minSize)
+	}
+
+	if request.Koji == nil {
+		imageOptions.Facts = &facts.ImageOptions{
+			APIType: facts.CLOUDV2_APITYPE,
 		}
+	}
 
-		irs = append(irs, imageRequest{
-			imageType:    imageType,
-			arch:         arch,
-			repositories: repos,
-			imageOptions: imageOptions,
-			targets:      irTargets,
-		})
+	// Set Subscription from the compose request
+	imageOptions.Subscription = request.GetSubscription()
+
+	// Set PartitioningMode from the compose request
+	imageOptions.PartitioningMode, err = request.GetPartitioningMode()
+	if err != nil {
+		return imageRequest{}, err
 	}
 
-	var id uuid.UUID
-	if request.Koji != nil {
-		id, err = h.server.enqueueKojiCompose(uint64(request.Koji.TaskId), request.Koji.Server, request.Koji.Name, request.Koji.Version, request.Koji.Release, distribution, bp, manifestSeed, irs, channel)
-		if err != nil {
-			return err
+	// Set OSTree options from the image request
+	imageOptions.OSTree, err = ir.GetOSTreeOptions()
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	wslOptions, err := ir.GetWSLOptions(request, imageType)
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	err = checkKernel(request, imageType)
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	err = checkIgnition(request, ir.ImageType)
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	err = checkOSTreeRemote(request, ir.ImageType)
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	err = checkFDO(request, ir.ImageType)
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	err = checkImageTypeCustomizations(request, imageType, bp, imageOptions, repos)
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	containerAuths, err := request.GetContainerAuths()
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	modules, err := request.GetDepsolveModules()
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	excludePackages, err := request.GetExcludedPackages()
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	err = checkSshd(request, distribution)
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	// Check to see if local_save is enabled and set
+	localSave, err := isLocalSave(ir.UploadOptions)
+	if err != nil {
+		return imageRequest{}, err
+	}
+
+	var irTargets []*target.Target
+	if ir.UploadOptions == nil && (ir.UploadTargets == nil || len(*ir.UploadTargets) == 0) {
+		// nowhere to put the image, this is a user error
+		if request.Koji == nil {
+			return imageRequest{}, HTTPError(ErrorJSONUnMarshallingError)
 		}
+	} else if localSave {
+		// Override the image type upload selection and save it locally
+		// Final image is in /var/lib/osbuild-composer/artifacts/UUID/
+		srvTarget := target.NewWorkerServerTarget()
+		srvTarget.ImageName = imageType.Filename()
+		srvTarget.OsbuildArtifact.ExportFilename = imageType.Filename()
+		srvTarget.OsbuildArtifact.ExportName = imageType.Exports()[0]
+		irTargets = []*target.Target{srvTarget}
 	} else {
-		id, err = h.server.enqueueCompose(distribution, bp, manifestSeed, irs, channel)
+		// Get the target for the selected image type
+		irTargets, err = ir.GetTargets(request, imageType)
 		if err != nil {
-			return err
+			return imageRequest{}, err
 		}
 	}
+	if provider := vagrantProvider(ir.ImageType); provider != "" {
+		for _, t := range irTargets {
+			t.OsbuildArtifact.VagrantProvider = provider
+		}
+	}
+	for _, t := range irTargets {
+		t.OsbuildArtifact.WSL = wslOptions
+	}
+
+	return imageRequest{
+		imageType:        imageType,
+		arch:             arch,
+		repositories:     repos,
+		imageOptions:     imageOptions,
+		targets:          irTargets,
+		requiredPackages: request.requiredPackages(),
+		containerAuths:   containerAuths,
+		modules:          modules,
+		excludePackages:  excludePackages,
+		minimal:          bp.Minimal,
+	}, nil
+}
 
-	ctx.Logger().Infof("Job ID %s enqueued for operationID %s", id, ctx.Get(common.OperationIDKey))
-
-	return ctx.JSON(http.StatusCreated, &ComposeId{
-		ObjectReference: ObjectReference{
-			Href: "/api/image-builder-composer/v2/compose",
-			Id:   id.String(),
-			Kind: "ComposeId",
-		},
-		Id: id.String(),
-	})
+// requestManifestSeed derives the manifest seed from the compose request,
+// so identical requests get the same seed.
+func requestManifestSeed(request ComposeRequest) (int64, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return 0, err
+	}
+	sum := sha256.Sum256(data)
+	return int64(binary.BigEndian.Uint64(sum[:8]) & math.MaxInt64), nil
 }
 
 func imageTypeFromApiImageType(it ImageTypes, arch distro.Arch) string {
@@ -328,6 +633,10 @@ func imageTypeFromApiImageType(it ImageTypes, arch distro.Arch) string {
 		return "rhel-edge-container"
 	case ImageTypesEdgeInstaller:
 		return "rhel-edge-installer"
+	case ImageTypesEdgeRawImage:
+		return "edge-raw-image"
+	case ImageTypesEdgeSimplifiedInstaller:
+		return "edge-simplified-installer"
 	case ImageTypesIotCommit:
 		return "iot-commit"
 	case ImageTypesIotContainer:
@@ -336,12 +645,28 @@ func imageTypeFromApiImageType(it ImageTypes, arch distro.Arch) string {
 		return "iot-installer"
 	case ImageTypesIotRawImage:
 		return "iot-raw-image"
+	case ImageTypesIotSimplifiedInstaller:
+		return "iot-simplified-installer"
 	case ImageTypesLiveInstaller:
 		return "live-installer"
+	case ImageTypesMinimalRaw:
+		return "minimal-raw"
 	case ImageTypesOci:
 		return "oci"
 	case ImageTypesWsl:
 		return "wsl"
+	case ImageTypesRaspberryPi:
+		// the raw image of Fedora for aarch64 has the firmware of the
+		// Raspberry Pi and u-boot in its ESP, other distributions don't
+		// ship them
+		if arch.Name() == "aarch64" && strings.HasPrefix(arch.Distro().Name(), "fedora") {
+			return "minimal-raw"
+		}
+		return ""
+	case ImageTypesVagrantLibvirt:
+		return "qcow2"
+	case ImageTypesVagrantVirtualbox:
+		return "ova"
 	}
 	return ""
 }
@@ -355,16 +680,37 @@ func targetResultToUploadStatus(t *target.TargetResult) (*UploadStatus, error) {
 	case target.TargetNameAWS:
 		uploadType = UploadTypesAws
 		awsOptions := t.Options.(*target.AWSTargetResultOptions)
-		uploadOptions = AWSEC2UploadStatus{
+		awsStatus := AWSEC2UploadStatus{
 			Ami:    awsOptions.Ami,
 			Region: awsOptions.Region,
 		}
+		if awsOptions.SnapshotID != "" {
+			awsStatus.SnapshotId = common.ToPtr(awsOptions.SnapshotID)
+		}
+		if awsOptions.KMSKeyARN != "" {
+			awsStatus.KmsKeyArn = common.ToPtr(awsOptions.KMSKeyARN)
+		}
+		if len(awsOptions.Tags) > 0 {
+			awsStatus.Tags = &AWSEC2UploadStatus_Tags{AdditionalProperties: awsOptions.Tags}
+		}
+		awsStatus.BootMode = awsOptions.BootMode
+		awsStatus.ImdsSupport = awsOptions.IMDSSupport
+		awsStatus.EnaSupport = awsOptions.ENASupport
+		if awsOptions.SriovNetSupport {
+			awsStatus.SriovNetSupport = common.ToPtr(true)
+		}
+		uploadOptions = awsStatus
 	case target.TargetNameAWSS3:
 		uploadType = UploadTypesAwsS3
 		awsOptions := t.Options.(*target.AWSS3TargetResultOptions)
-		uploadOptions = AWSS3UploadStatus{
+		awsS3Status := AWSS3UploadStatus{
 			Url: awsOptions.URL,
 		}
+		if awsOptions.Key != "" {
+			awsS3Status.Bucket = common.ToPtr(awsOptions.Bucket)
+			awsS3Status.Key = common.ToPtr(awsOptions.Key)
+		}
+		uploadOptions = awsS3Status
 	case target.TargetNameGCP:
 		uploadType = UploadTypesGcp
 		gcpOptions := t.Options.(*target.GCPTargetResultOptions)
@@ -375,28 +721,137 @@ func targetResultToUploadStatus(t *target.TargetResult) (*UploadStatus, error) {
 	case target.TargetNameAzureImage:
 		uploadType = UploadTypesAzure
 		gcpOptions := t.Options.(*target.AzureImageTargetResultOptions)
-		uploadOptions = AzureUploadStatus{
+		if gcpOptions.BlobURL != "" {
+			blobStatus := AzureBlobUploadStatus{
+				BlobUrl: gcpOptions.BlobURL,
+			}
+			if gcpOptions.SASToken != "" {
+				blobStatus.SasToken = common.ToPtr(gcpOptions.SASToken)
+			}
+			uploadOptions = blobStatus
+			break
+		}
+		azureStatus := AzureUploadStatus{
 			ImageName: gcpOptions.ImageName,
 		}
+		if gcpOptions.GalleryImageVersionID != "" {
+			azureStatus.GalleryImageVersionId = common.ToPtr(gcpOptions.GalleryImageVersionID)
+		}
+		uploadOptions = azureStatus
 	case target.TargetNameContainer:
 		uploadType = UploadTypesContainer
 		containerOptions := t.Options.(*target.ContainerTargetResultOptions)
-		uploadOptions = ContainerUploadStatus{
+		containerStatus := ContainerUploadStatus{
 			Url:    containerOptions.URL,
 			Digest: containerOptions.Digest,
 		}
+		if containerOptions.Signed {
+			containerStatus.Signed = common.ToPtr(true)
+			containerStatus.Sbom = common.ToPtr(containerOptions.SBOM)
+		}
+		if len(containerOptions.References) > 0 {
+			containerStatus.References = common.ToPtr(containerOptions.References)
+		}
+		uploadOptions = containerStatus
 	case target.TargetNameOCIObjectStorage:
 		uploadType = UploadTypesOciObjectstorage
 		ociOptions := t.Options.(*target.OCIObjectStorageTargetResultOptions)
-		uploadOptions = OCIUploadStatus{
+		ociStatus := OCIUploadStatus{
 			Url: ociOptions.URL,
 		}
+		if ociOptions.ObjectPath != "" {
+			ociStatus.ObjectPath = common.ToPtr(ociOptions.ObjectPath)
+		}
+		uploadOptions = ociStatus
+	case target.TargetNameOCI:
+		uploadType = UploadTypesOciObjectstorage
+		ociOptions := t.Options.(*target.OCITargetResultOptions)
+		uploadOptions = OCIImageUploadStatus{
+			ImageId: ociOptions.ImageID,
+			Region:  ociOptions.Region,
+		}
 	case target.TargetNamePulpOSTree:
 		uploadType = UploadTypesPulpOstree
 		pulpOSTreeOptions := t.Options.(*target.PulpOSTreeTargetResultOptions)
 		uploadOptions = PulpOSTreeUploadStatus{
 			RepoUrl: pulpOSTreeOptions.RepoURL,
 		}
+	case target.TargetNameRBD:
+		uploadType = UploadTypesRbd
+		rbdOptions := t.Options.(*target.RBDTargetResultOptions)
+		uploadOptions = RBDUploadStatus{
+			ImageSpec: rbdOptions.ImageSpec,
+		}
+	case target.TargetNameLibvirt:
+		uploadType = UploadTypesLibvirt
+		libvirtOptions := t.Options.(*target.LibvirtTargetResultOptions)
+		libvirtStatus := LibvirtUploadStatus{
+			VolumePath: libvirtOptions.VolumePath,
+		}
+		if libvirtOptions.DomainName != "" {
+			libvirtStatus.DomainName = common.ToPtr(libvirtOptions.DomainName)
+		}
+		uploadOptions = libvirtStatus
+	case target.TargetNameORAS:
+		uploadType = UploadTypesOras
+		orasOptions := t.Options.(*target.ORASTargetResultOptions)
+		uploadOptions = ORASUploadStatus{
+			Url:    orasOptions.URL,
+			Digest: orasOptions.Digest,
+		}
+	case target.TargetNamePulpFile:
+		uploadType = UploadTypesPulpFile
+		pulpFileOptions := t.Options.(*target.PulpFileTargetResultOptions)
+		uploadOptions = PulpFileUploadStatus{
+			FileUrl: pulpFileOptions.FileURL,
+		}
+	case target.TargetNameMock:
+		uploadType = UploadTypesMock
+		mockOptions := t.Options.(*target.MockTargetResultOptions)
+		uploadOptions = MockUploadStatus{
+			Url: mockOptions.URL,
+		}
+	case target.TargetNameHTTP:
+		uploadType = UploadTypesHttp
+		httpOptions := t.Options.(*target.HTTPTargetResultOptions)
+		uploadOptions = HTTPUploadStatus{
+			Url: httpOptions.URL,
+		}
+	case target.TargetNameBareMetal:
+		uploadType = UploadTypesBaremetal
+		bareMetalOptions := t.Options.(*target.BareMetalTargetResultOptions)
+		bareMetalStatus := BareMetalUploadStatus{
+			ImageUrl:      bareMetalOptions.ImageURL,
+			ImageChecksum: bareMetalOptions.ImageChecksum,
+		}
+		if bareMetalOptions.IPXEScriptURL != "" {
+			bareMetalStatus.KernelUrl = common.ToPtr(bareMetalOptions.KernelURL)
+			bareMetalStatus.InitrdUrl = common.ToPtr(bareMetalOptions.InitrdURL)
+			bareMetalStatus.IpxeScriptUrl = common.ToPtr(bareMetalOptions.IPXEScriptURL)
+		}
+		uploadOptions = bareMetalStatus
+	case target.TargetNameVagrantCloud:
+		uploadType = UploadTypesVagrantCloud
+		vagrantCloudOptions := t.Options.(*target.VagrantCloudTargetResultOptions)
+		uploadOptions = VagrantCloudUploadStatus{
+			Box:      vagrantCloudOptions.Box,
+			Version:  vagrantCloudOptions.Version,
+			Provider: vagrantCloudOptions.Provider,
+			Released: vagrantCloudOptions.Released,
+		}
+	case target.TargetNameVMWare:
+		uploadType = UploadTypesVsphere
+		vsphereStatus := VSphereUploadStatus{}
+		if vmwareOptions, ok := t.Options.(*target.VMWareTargetResultOptions); ok && vmwareOptions.ContentLibraryItemID != "" {
+			vsphereStatus.ContentLibraryItemId = common.ToPtr(vmwareOptions.ContentLibraryItemID)
+		}
+		uploadOptions = vsphereStatus
+	case target.TargetNameHetzner:
+		uploadType = UploadTypesHetzner
+		hetznerOptions := t.Options.(*target.HetznerTargetResultOptions)
+		uploadOptions = HetznerUploadStatus{
+			ImageId: hetznerOptions.ImageID,
+		}
 	default:
 		return nil, fmt.Errorf("unknown upload target: %s", t.Name)
 	}
@@ -408,6 +863,9 @@ func targetResultToUploadStatus(t *target.TargetResult) (*UploadStatus, error) {
 		Type:    uploadType,
 		Options: uploadOptions,
 	}
+	if t.ArtifactChecksum != "" {
+		us.ArtifactChecksum = common.ToPtr(t.ArtifactChecksum)
+	}
 
 	return us, nil
 }
@@ -434,31 +892,82 @@ func (h *apiHandlers) getComposeStatusImpl(ctx echo.Context, id string) error {
 			return HTTPError(ErrorMalformedOSBuildJobResult)
 		}
 
-		jobError, err := h.server.workers.JobDependencyChainErrors(jobId)
+		var job worker.OSBuildJob
+		err = h.server.workers.OSBuildJob(jobId, &job)
 		if err != nil {
-			return HTTPError(ErrorGettingBuildDependencyStatus)
+			return HTTPErrorWithInternal(ErrorComposeNotFound, err)
 		}
 
-		var uploadStatuses *[]UploadStatus
-		var us0 *UploadStatus
-		if result.TargetResults != nil {
-			statuses := make([]UploadStatus, len(result.TargetResults))
-			for idx := range result.TargetResults {
-				tr := result.TargetResults[idx]
-				us, err := targetResultToUploadStatus(tr)
-				if err != nil {
-					return HTTPError(ErrorUnknownUploadTarget)
+		imageStatus, err := h.osbuildImageStatus(jobId, jobInfo, &result)
+		if err != nil {
+			return err
+		}
+
+		status := composeStatusFromOSBuildJobStatus(jobInfo.JobStatus, &result)
+		if osbuildJobTimedOut(jobInfo.JobStatus, &job, &result) {
+			status = ComposeStatusValueTimedOut
+		}
+
+		return ctx.JSON(http.StatusOK, ComposeStatus{
+			ObjectReference: ObjectReference{
+				Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v", jobId),
+				Id:   jobId.String(),
+				Kind: "ComposeStatus",
+			},
+			Status:      status,
+			ImageStatus: *imageStatus,
+		})
+	} else if jobType == worker.JobTypeCompose {
+		var composeJob worker.ComposeJob
+		err = h.server.workers.ComposeJob(jobId, &composeJob)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+		}
+		composeInfo, err := h.server.workers.ComposeJobInfo(jobId, &worker.ComposeJobResult{})
+		if err != nil {
+			return HTTPError(ErrorMalformedOSBuildJobResult)
+		}
+		if len(composeInfo.Deps) < 2 || len(composeInfo.Deps) != len(composeJob.Images) {
+			return HTTPError(ErrorUnexpectedNumberOfImageBuilds)
+		}
+
+		var buildJobStatuses []ImageStatus
+		status := ComposeStatusValueSuccess
+		timedOut := false
+		for i, buildID := range composeInfo.Deps {
+			var buildResult worker.OSBuildJobResult
+			buildInfo, err := h.server.workers.OSBuildJobInfo(buildID, &buildResult)
+			if err != nil {
+				return HTTPError(ErrorMalformedOSBuildJobResult)
+			}
+			var buildJob worker.OSBuildJob
+			err = h.server.workers.OSBuildJob(buildID, &buildJob)
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+			}
+			if osbuildJobTimedOut(buildInfo.JobStatus, &buildJob, &buildResult) {
+				timedOut = true
+			}
+
+			// the compose is pending until all of its builds finished
+			switch composeStatusFromOSBuildJobStatus(buildInfo.JobStatus, &buildResult) {
+			case ComposeStatusValuePending:
+				status = ComposeStatusValuePending
+			case ComposeStatusValueFailure:
+				if status != ComposeStatusValuePending {
+					status = ComposeStatusValueFailure
 				}
-				us.Status = uploadStatusFromJobStatus(jobInfo.JobStatus, result.JobError)
-				statuses[idx] = *us
 			}
 
-			if len(statuses) > 0 {
-				// make sure uploadStatuses remains nil if the array is empty but not nill
-				uploadStatuses = &statuses
-				// get first upload status if there's at least one
-				us0 = &statuses[0]
+			imageStatus, err := h.osbuildImageStatus(buildID, buildInfo, &buildResult)
+			if err != nil {
+				return err
 			}
+			imageStatus.Arch = common.ToPtr(composeJob.Images[i].Arch)
+			buildJobStatuses = append(buildJobStatuses, *imageStatus)
+		}
+		if timedOut {
+			status = ComposeStatusValueTimedOut
 		}
 
 		return ctx.JSON(http.StatusOK, ComposeStatus{
@@ -467,13 +976,9 @@ func (h *apiHandlers) getComposeStatusImpl(ctx echo.Context, id string) error {
 				Id:   jobId.String(),
 				Kind: "ComposeStatus",
 			},
-			Status: composeStatusFromOSBuildJobStatus(jobInfo.JobStatus, &result),
-			ImageStatus: ImageStatus{
-				Status:         imageStatusFromOSBuildJobStatus(jobInfo.JobStatus, &result),
-				Error:          composeStatusErrorFromJobError(jobError),
-				UploadStatus:   us0, // add the first upload status to the old top-level field
-				UploadStatuses: uploadStatuses,
-			},
+			Status:        status,
+			ImageStatus:   buildJobStatuses[0], // backwards compatibility
+			ImageStatuses: &buildJobStatuses,
 		})
 	} else if jobType == worker.JobTypeKojiFinalize {
 		var result worker.KojiFinalizeJobResult
@@ -491,12 +996,21 @@ func (h *apiHandlers) getComposeStatusImpl(ctx echo.Context, id string) error {
 		}
 		var buildJobResults []worker.OSBuildJobResult
 		var buildJobStatuses []ImageStatus
+		timedOut := false
 		for i := 1; i < len(finalizeInfo.Deps); i++ {
 			var buildJobResult worker.OSBuildJobResult
 			buildInfo, err := h.server.workers.OSBuildJobInfo(finalizeInfo.Deps[i], &buildJobResult)
 			if err != nil {
 				return HTTPError(ErrorMalformedOSBuildJobResult)
 			}
+			var buildJob worker.OSBuildJob
+			err = h.server.workers.OSBuildJob(finalizeInfo.Deps[i], &buildJob)
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+			}
+			if osbuildJobTimedOut(buildInfo.JobStatus, &buildJob, &buildJobResult) {
+				timedOut = true
+			}
 			buildJobError, err := h.server.workers.JobDependencyChainErrors(finalizeInfo.Deps[i])
 			if err != nil {
 				return HTTPError(ErrorGettingBuildDependencyStatus)
@@ -535,13 +1049,17 @@ func (h *apiHandlers) getComposeStatusImpl(ctx echo.Context, id string) error {
 				UploadStatuses: uploadStatuses,
 			})
 		}
+		status := composeStatusFromKojiJobStatus(finalizeInfo.JobStatus, &initResult, buildJobResults, &result)
+		if timedOut {
+			status = ComposeStatusValueTimedOut
+		}
 		response := ComposeStatus{
 			ObjectReference: ObjectReference{
 				Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v", jobId),
 				Id:   jobId.String(),
 				Kind: "ComposeStatus",
 			},
-			Status:        composeStatusFromKojiJobStatus(finalizeInfo.JobStatus, &initResult, buildJobResults, &result),
+			Status:        status,
 			ImageStatus:   buildJobStatuses[0], // backwards compatibility
 			ImageStatuses: &buildJobStatuses,
 			KojiStatus:    &KojiStatus{},
@@ -556,6 +1074,50 @@ func (h *apiHandlers) getComposeStatusImpl(ctx echo.Context, id string) error {
 	}
 }
 
+// osbuildImageStatus returns the status of the image built by the osbuild
+// job and of its uploads.
+func (h *apiHandlers) osbuildImageStatus(jobId uuid.UUID, jobInfo *worker.JobInfo, result *worker.OSBuildJobResult) (*ImageStatus, error) {
+	jobError, err := h.server.workers.JobDependencyChainErrors(jobId)
+	if err != nil {
+		return nil, HTTPError(ErrorGettingBuildDependencyStatus)
+	}
+
+	var uploadStatuses *[]UploadStatus
+	var us0 *UploadStatus
+	if result.TargetResults != nil {
+		statuses := make([]UploadStatus, len(result.TargetResults))
+		for idx := range result.TargetResults {
+			tr := result.TargetResults[idx]
+			us, err := targetResultToUploadStatus(tr)
+			if err != nil {
+				return nil, HTTPError(ErrorUnknownUploadTarget)
+			}
+			statusError := result.JobError
+			if statusError != nil && statusError.ID == clienterrors.ErrorDeadlineExceeded {
+				// targets skipped because of the deadline are not part of
+				// the results, the remaining ones finished on their own
+				statusError = tr.TargetError
+			}
+			us.Status = uploadStatusFromJobStatus(jobInfo.JobStatus, statusError)
+			statuses[idx] = *us
+		}
+
+		if len(statuses) > 0 {
+			// make sure uploadStatuses remains nil if the array is empty but not nill
+			uploadStatuses = &statuses
+			// get first upload status if there's at least one
+			us0 = &statuses[0]
+		}
+	}
+
+	return &ImageStatus{
+		Status:         imageStatusFromOSBuildJobStatus(jobInfo.JobStatus, result),
+		Error:          composeStatusErrorFromJobError(jobError),
+		UploadStatus:   us0, // add the first upload status to the old top-level field
+		UploadStatuses: uploadStatuses,
+	}, nil
+}
+
 func composeStatusErrorFromJobError(jobError *clienterrors.Error) *ComposeStatusError {
 	if jobError == nil {
 		return nil
@@ -567,6 +1129,9 @@ func composeStatusErrorFromJobError(jobError *clienterrors.Error) *ComposeStatus
 	if jobError.Details != nil {
 		err.Details = &jobError.Details
 	}
+	if jobError.IsRetryable() {
+		err.Retryable = common.ToPtr(true)
+	}
 	return err
 }
 
@@ -636,6 +1201,32 @@ func composeStatusFromOSBuildJobStatus(js *worker.JobStatus, result *worker.OSBu
 	return ComposeStatusValueFailure
 }
 
+// osbuildJobTimedOut returns true if the deadline of the compose passed
+// before a worker picked up the osbuild job, or if the job failed or skipped
+// some of its targets for the same reason.
+func osbuildJobTimedOut(js *worker.JobStatus, job *worker.OSBuildJob, result *worker.OSBuildJobResult) bool {
+	if job.Deadline == nil || js.Canceled {
+		return false
+	}
+
+	// the job gets failed by the server or the worker picking it up
+	if js.Started.IsZero() {
+		return job.DeadlineExceeded()
+	}
+
+	if result.JobError != nil && result.JobError.ID == clienterrors.ErrorDeadlineExceeded {
+		return true
+	}
+
+	for _, tr := range result.TargetResults {
+		if tr.TargetError != nil && tr.TargetError.ID == clienterrors.ErrorDeadlineExceeded {
+			return true
+		}
+	}
+
+	return false
+}
+
 func composeStatusFromKojiJobStatus(js *worker.JobStatus, initResult *worker.KojiInitJobResult, buildResults []worker.OSBuildJobResult, result *worker.KojiFinalizeJobResult) ComposeStatusValue {
 	if js.Canceled {
 		return ComposeStatusValueFailure
@@ -709,55 +1300,367 @@ func (h *apiHandlers) getComposeMetadataImpl(ctx echo.Context, id string) error
 		})
 	}
 
-	if buildInfo.JobStatus.Canceled || !result.Success {
-		// job canceled or failed, empty response
-		return ctx.JSON(200, ComposeMetadata{
-			ObjectReference: ObjectReference{
-				Href: fmt.Sprintf("/api/image-builder-composer/v2/%v/metadata", jobId),
-				Id:   jobId.String(),
-				Kind: "ComposeMetadata",
-			},
-		})
+	if buildInfo.JobStatus.Canceled || !result.Success {
+		// job canceled or failed, empty response
+		return ctx.JSON(200, ComposeMetadata{
+			ObjectReference: ObjectReference{
+				Href: fmt.Sprintf("/api/image-builder-composer/v2/%v/metadata", jobId),
+				Id:   jobId.String(),
+				Kind: "ComposeMetadata",
+			},
+		})
+	}
+
+	if result.OSBuildOutput == nil || len(result.OSBuildOutput.Log) == 0 {
+		// no osbuild output recorded for job, error
+		return HTTPError(ErrorMalformedOSBuildJobResult)
+	}
+
+	var ostreeCommitMetadata *osbuild.OSTreeCommitStageMetadata
+	var rpmStagesMd []osbuild.RPMStageMetadata // collect rpm stage metadata from payload pipelines
+	for _, plName := range job.PipelineNames.Payload {
+		plMd, hasMd := result.OSBuildOutput.Metadata[plName]
+		if !hasMd {
+			continue
+		}
+		for _, stageMd := range plMd {
+			switch md := stageMd.(type) {
+			case *osbuild.RPMStageMetadata:
+				rpmStagesMd = append(rpmStagesMd, *md)
+			case *osbuild.OSTreeCommitStageMetadata:
+				ostreeCommitMetadata = md
+			}
+		}
+	}
+
+	packages := stagesToPackageMetadata(rpmStagesMd)
+
+	resp := &ComposeMetadata{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/metadata", jobId),
+			Id:   jobId.String(),
+			Kind: "ComposeMetadata",
+		},
+		Packages: &packages,
+	}
+
+	if ostreeCommitMetadata != nil {
+		resp.OstreeCommit = &ostreeCommitMetadata.Compose.OSTreeCommit
+	}
+
+	for _, plName := range job.PipelineNames.Payload {
+		for _, stage := range result.OSBuildOutput.Log[plName] {
+			if stage.Type == openSCAPRemediationStage {
+				resp.Openscap = openSCAPReport(stage.Output)
+			}
+		}
+	}
+
+	if job.ChecksumManifest {
+		resp.Checksums, err = h.checksumManifest(jobId, &result)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ctx.JSON(200, resp)
+}
+
+// checksumManifest returns the checksums of the artifacts of a finished
+// compose and the URLs of the files listing them.
+func (h *apiHandlers) checksumManifest(jobId uuid.UUID, result *worker.OSBuildJobResult) (*ChecksumManifest, error) {
+	checksums := &ChecksumManifest{
+		Artifacts:      []ArtifactChecksum{},
+		Sha256sumsUrls: []string{},
+	}
+	for artifactPath, checksum := range result.ArtifactChecksums {
+		checksums.Artifacts = append(checksums.Artifacts, ArtifactChecksum{
+			Artifact: path.Base(artifactPath),
+			Checksum: checksum,
+		})
+	}
+	sort.Slice(checksums.Artifacts, func(i, j int) bool {
+		return checksums.Artifacts[i].Artifact < checksums.Artifacts[j].Artifact
+	})
+	for _, tr := range result.TargetResults {
+		if options, ok := tr.Options.(*target.AWSS3TargetResultOptions); ok && options.ChecksumsURL != "" {
+			checksums.Sha256sumsUrls = append(checksums.Sha256sumsUrls, options.ChecksumsURL)
+		}
+	}
+
+	signID, err := h.composeDependent(jobId, worker.JobTypeSign)
+	if err != nil {
+		return nil, err
+	}
+	if signID != uuid.Nil {
+		var signResult worker.SignJobResult
+		signInfo, err := h.server.workers.SignJobInfo(signID, &signResult)
+		if err != nil {
+			return nil, HTTPErrorWithInternal(ErrorGettingSignStatus, err)
+		}
+		if !signInfo.JobStatus.Finished.IsZero() && signResult.JobError == nil && len(signResult.ChecksumFileURLs) > 0 {
+			checksums.ChecksumUrls = common.ToPtr(signResult.ChecksumFileURLs)
+		}
+	}
+	return checksums, nil
+}
+
+func (h *apiHandlers) GetComposeVulnerabilities(ctx echo.Context, id string) error {
+	return h.server.EnsureJobChannel(h.getComposeVulnerabilitiesImpl)(ctx, id)
+}
+
+func (h *apiHandlers) getComposeVulnerabilitiesImpl(ctx echo.Context, id string) error {
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return HTTPError(ErrorInvalidComposeId)
+	}
+
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+	if jobType != worker.JobTypeOSBuild {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	// the packages are scanned by a job depending on the build
+	scanID, err := h.composeDependent(jobId, worker.JobTypeVulnerabilityScan)
+	if err != nil {
+		return err
+	}
+	if scanID == uuid.Nil {
+		return HTTPError(ErrorComposeNotScanned)
+	}
+
+	var result worker.VulnerabilityScanJobResult
+	scanInfo, err := h.server.workers.VulnerabilityScanJobInfo(scanID, &result)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingVulnerabilityScanStatus, err)
+	}
+
+	resp := ComposeVulnerabilities{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/vulnerabilities", jobId),
+			Id:   jobId.String(),
+			Kind: "ComposeVulnerabilities",
+		},
+		Status:          ComposeStatusValueSuccess,
+		Vulnerabilities: []Vulnerability{},
+	}
+	switch {
+	case scanInfo.JobStatus.Canceled:
+		resp.Status = ComposeStatusValueFailure
+	case scanInfo.JobStatus.Finished.IsZero():
+		resp.Status = ComposeStatusValuePending
+	case result.JobError != nil:
+		resp.Status = ComposeStatusValueFailure
+		resp.Error = composeStatusErrorFromJobError(result.JobError)
+	default:
+		for _, v := range result.Vulnerabilities {
+			resp.Vulnerabilities = append(resp.Vulnerabilities, Vulnerability{
+				Id:      v.ID,
+				Package: v.Package,
+				Version: v.Version,
+				Arch:    v.Arch,
+			})
+		}
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *apiHandlers) GetComposeSignatures(ctx echo.Context, id string) error {
+	return h.server.EnsureJobChannel(h.getComposeSignaturesImpl)(ctx, id)
+}
+
+func (h *apiHandlers) getComposeSignaturesImpl(ctx echo.Context, id string) error {
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return HTTPError(ErrorInvalidComposeId)
+	}
+
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+	if jobType != worker.JobTypeOSBuild {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	signID, err := h.composeDependent(jobId, worker.JobTypeSign)
+	if err != nil {
+		return err
+	}
+	if signID == uuid.Nil {
+		return HTTPError(ErrorComposeNotSigned)
+	}
+
+	var result worker.SignJobResult
+	signInfo, err := h.server.workers.SignJobInfo(signID, &result)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingSignStatus, err)
+	}
+
+	resp := ComposeSignatures{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/signatures", jobId),
+			Id:   jobId.String(),
+			Kind: "ComposeSignatures",
+		},
+		Status:     ComposeStatusValueSuccess,
+		Signatures: []ArtifactSignature{},
+	}
+	switch {
+	case signInfo.JobStatus.Canceled:
+		resp.Status = ComposeStatusValueFailure
+	case signInfo.JobStatus.Finished.IsZero():
+		resp.Status = ComposeStatusValuePending
+	case result.JobError != nil:
+		resp.Status = ComposeStatusValueFailure
+		resp.Error = composeStatusErrorFromJobError(result.JobError)
+	default:
+		for _, s := range result.Signatures {
+			resp.Signatures = append(resp.Signatures, ArtifactSignature{
+				Artifact:              s.Artifact,
+				Checksum:              s.Checksum,
+				SignatureUrl:          s.SignatureURL,
+				ChecksumsUrl:          s.ChecksumsURL,
+				ChecksumsSignatureUrl: s.ChecksumsSignatureURL,
+			})
+		}
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *apiHandlers) GetComposeImageExport(ctx echo.Context, id string) error {
+	return h.server.EnsureJobChannel(h.getComposeImageExportImpl)(ctx, id)
+}
+
+func (h *apiHandlers) getComposeImageExportImpl(ctx echo.Context, id string) error {
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return HTTPError(ErrorInvalidComposeId)
+	}
+
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+	if jobType != worker.JobTypeOSBuild {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	exportID, err := h.composeDependent(jobId, worker.JobTypeGCPImageExport)
+	if err != nil {
+		return err
+	}
+	if exportID == uuid.Nil {
+		return HTTPError(ErrorComposeNotExported)
+	}
+
+	var result worker.GCPImageExportJobResult
+	exportInfo, err := h.server.workers.GCPImageExportJobInfo(exportID, &result)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingImageExportStatus, err)
+	}
+
+	resp := ComposeImageExport{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/export", jobId),
+			Id:   jobId.String(),
+			Kind: "ComposeImageExport",
+		},
+		Status: ComposeStatusValueSuccess,
+	}
+	switch {
+	case exportInfo.JobStatus.Canceled:
+		resp.Status = ComposeStatusValueFailure
+	case exportInfo.JobStatus.Finished.IsZero():
+		resp.Status = ComposeStatusValuePending
+	case result.JobError != nil:
+		resp.Status = ComposeStatusValueFailure
+		resp.Error = composeStatusErrorFromJobError(result.JobError)
+	default:
+		resp.Uri = common.ToPtr(result.URI)
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *apiHandlers) GetComposeOSTreeMirror(ctx echo.Context, id string) error {
+	return h.server.EnsureJobChannel(h.getComposeOSTreeMirrorImpl)(ctx, id)
+}
+
+func (h *apiHandlers) getComposeOSTreeMirrorImpl(ctx echo.Context, id string) error {
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return HTTPError(ErrorInvalidComposeId)
+	}
+
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
 	}
-
-	if result.OSBuildOutput == nil || len(result.OSBuildOutput.Log) == 0 {
-		// no osbuild output recorded for job, error
-		return HTTPError(ErrorMalformedOSBuildJobResult)
+	if jobType != worker.JobTypeOSBuild {
+		return HTTPError(ErrorInvalidJobType)
 	}
 
-	var ostreeCommitMetadata *osbuild.OSTreeCommitStageMetadata
-	var rpmStagesMd []osbuild.RPMStageMetadata // collect rpm stage metadata from payload pipelines
-	for _, plName := range job.PipelineNames.Payload {
-		plMd, hasMd := result.OSBuildOutput.Metadata[plName]
-		if !hasMd {
-			continue
-		}
-		for _, stageMd := range plMd {
-			switch md := stageMd.(type) {
-			case *osbuild.RPMStageMetadata:
-				rpmStagesMd = append(rpmStagesMd, *md)
-			case *osbuild.OSTreeCommitStageMetadata:
-				ostreeCommitMetadata = md
-			}
-		}
+	mirrorID, err := h.composeDependent(jobId, worker.JobTypeOSTreeMirror)
+	if err != nil {
+		return err
+	}
+	if mirrorID == uuid.Nil {
+		return HTTPError(ErrorComposeNotMirrored)
 	}
 
-	packages := stagesToPackageMetadata(rpmStagesMd)
+	var result worker.OSTreeMirrorJobResult
+	mirrorInfo, err := h.server.workers.OSTreeMirrorJobInfo(mirrorID, &result)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingOSTreeMirrorStatus, err)
+	}
 
-	resp := &ComposeMetadata{
+	resp := ComposeOSTreeMirror{
 		ObjectReference: ObjectReference{
-			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/metadata", jobId),
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/ostree-mirror", jobId),
 			Id:   jobId.String(),
-			Kind: "ComposeMetadata",
+			Kind: "ComposeOSTreeMirror",
 		},
-		Packages: &packages,
+		Status: ComposeStatusValueSuccess,
+	}
+	switch {
+	case mirrorInfo.JobStatus.Canceled:
+		resp.Status = ComposeStatusValueFailure
+	case mirrorInfo.JobStatus.Finished.IsZero():
+		resp.Status = ComposeStatusValuePending
+	case result.JobError != nil:
+		resp.Status = ComposeStatusValueFailure
+		resp.Error = composeStatusErrorFromJobError(result.JobError)
+	default:
+		resp.Url = common.ToPtr(result.URL)
+	}
+	if result.Ref != "" {
+		resp.Ref = common.ToPtr(result.Ref)
+		resp.Checksum = common.ToPtr(result.Checksum)
 	}
+	return ctx.JSON(http.StatusOK, resp)
+}
 
-	if ostreeCommitMetadata != nil {
-		resp.OstreeCommit = &ostreeCommitMetadata.Compose.OSTreeCommit
+// composeDependent returns the ID of the job of the given type which depends
+// on the osbuild job of the compose, or uuid.Nil if there is none.
+func (h *apiHandlers) composeDependent(jobId uuid.UUID, jobType string) (uuid.UUID, error) {
+	buildInfo, err := h.server.workers.OSBuildJobInfo(jobId, &worker.OSBuildJobResult{})
+	if err != nil {
+		return uuid.Nil, HTTPErrorWithInternal(ErrorComposeNotFound, err)
 	}
 
-	return ctx.JSON(200, resp)
+	for _, d := range buildInfo.Dependents {
+		jt, err := h.server.workers.JobType(d)
+		if err != nil {
+			return uuid.Nil, HTTPErrorWithInternal(ErrorGettingJobType, err)
+		}
+		if jt == jobType {
+			return d, nil
+		}
+	}
+	return uuid.Nil, nil
 }
 
 func stagesToPackageMetadata(stages []osbuild.RPMStageMetadata) []PackageMetadata {
@@ -848,6 +1751,21 @@ func (h *apiHandlers) getComposeLogsImpl(ctx echo.Context, id string) error {
 			Import: finalizeResult,
 		}
 
+	case worker.JobTypeCompose:
+		composeInfo, err := h.server.workers.ComposeJobInfo(jobId, &worker.ComposeJobResult{})
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+		}
+
+		for _, buildID := range composeInfo.Deps {
+			var buildResult worker.OSBuildJobResult
+			_, err = h.server.workers.OSBuildJobInfo(buildID, &buildResult)
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+			}
+			buildResultBlobs = append(buildResultBlobs, buildResult)
+		}
+
 	case worker.JobTypeOSBuild:
 		var buildResult worker.OSBuildJobResult
 		_, err = h.server.workers.OSBuildJobInfo(jobId, &buildResult)
@@ -887,6 +1805,30 @@ func manifestJobResultsFromJobDeps(w *worker.Server, deps []uuid.UUID) (*worker.
 	return nil, fmt.Errorf("no %q job found in the dependencies", worker.JobTypeManifestIDOnly)
 }
 
+// osbuildJobManifest returns the manifest of the osbuild job, either passed to
+// it directly or generated by its manifest job.
+func osbuildJobManifest(w *worker.Server, id uuid.UUID) (manifest.OSBuildManifest, error) {
+	var buildJob worker.OSBuildJob
+	err := w.OSBuildJob(id, &buildJob)
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+
+	if len(buildJob.Manifest) != 0 {
+		return buildJob.Manifest, nil
+	}
+
+	buildInfo, err := w.OSBuildJobInfo(id, &worker.OSBuildJobResult{})
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+	manifestResult, err := manifestJobResultsFromJobDeps(w, buildInfo.Deps)
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorComposeNotFound, fmt.Errorf("job %q: %v", id, err))
+	}
+	return manifestResult.Manifest, nil
+}
+
 // GetComposeIdManifests returns the Manifests for a given Compose (one for each image).
 func (h *apiHandlers) GetComposeManifests(ctx echo.Context, id string) error {
 	return h.server.EnsureJobChannel(h.getComposeManifestsImpl)(ctx, id)
@@ -903,7 +1845,7 @@ func (h *apiHandlers) getComposeManifestsImpl(ctx echo.Context, id string) error
 		return HTTPError(ErrorComposeNotFound)
 	}
 
-	var manifestBlobs []interface{}
+	var manifests []manifest.OSBuildManifest
 
 	switch jobType {
 	case worker.JobTypeKojiFinalize:
@@ -947,43 +1889,53 @@ func (h *apiHandlers) getComposeManifestsImpl(ctx echo.Context, id string) error
 				return HTTPErrorWithInternal(ErrorInvalidJobType,
 					fmt.Errorf("unexpected job type in koji compose dependencies: %q", buildJobType))
 			}
-			manifestBlobs = append(manifestBlobs, mf)
+			manifests = append(manifests, mf)
 		}
 
-	case worker.JobTypeOSBuild:
-		var buildJob worker.OSBuildJob
-		err = h.server.workers.OSBuildJob(jobId, &buildJob)
+	case worker.JobTypeCompose:
+		composeInfo, err := h.server.workers.ComposeJobInfo(jobId, &worker.ComposeJobResult{})
 		if err != nil {
 			return HTTPErrorWithInternal(ErrorComposeNotFound, err)
 		}
 
-		var mf manifest.OSBuildManifest
-		if len(buildJob.Manifest) != 0 {
-			mf = buildJob.Manifest
-		} else {
-			buildInfo, err := h.server.workers.OSBuildJobInfo(jobId, &worker.OSBuildJobResult{})
-			if err != nil {
-				return HTTPErrorWithInternal(ErrorComposeNotFound, err)
-			}
-			manifestResult, err := manifestJobResultsFromJobDeps(h.server.workers, buildInfo.Deps)
+		for _, buildID := range composeInfo.Deps {
+			mf, err := osbuildJobManifest(h.server.workers, buildID)
 			if err != nil {
-				return HTTPErrorWithInternal(ErrorComposeNotFound, fmt.Errorf("job %q: %v", jobId, err))
+				return err
 			}
-			mf = manifestResult.Manifest
+			manifests = append(manifests, mf)
 		}
-		manifestBlobs = append(manifestBlobs, mf)
+
+	case worker.JobTypeOSBuild:
+		mf, err := osbuildJobManifest(h.server.workers, jobId)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, mf)
 
 	default:
 		return HTTPError(ErrorInvalidJobType)
 	}
 
+	var manifestBlobs []interface{}
+	var manifestDigests []ManifestDigest
+	for _, mf := range manifests {
+		md, err := newManifestDigest(mf, h.server.config.ManifestSigningKey)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorFailedToSignManifest, err)
+		}
+		manifestBlobs = append(manifestBlobs, mf)
+		manifestDigests = append(manifestDigests, md)
+	}
+
 	resp := &ComposeManifests{
 		ObjectReference: ObjectReference{
 			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/manifests", jobId),
 			Id:   jobId.String(),
 			Kind: "ComposeManifests",
 		},
-		Manifests: manifestBlobs,
+		Manifests:       manifestBlobs,
+		ManifestDigests: &manifestDigests,
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
@@ -1125,6 +2077,9 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, id string) error {
 	// look at the upload status of the osbuild dependency to decide what to do
 	if us.Type == UploadTypesAws {
 		options := us.Options.(AWSEC2UploadStatus)
+		if options.Ami == "" {
+			return HTTPError(ErrorNoAMIToClone)
+		}
 		var img AWSEC2CloneCompose
 		err := ctx.Bind(&img)
 		if err != nil {
@@ -1133,10 +2088,19 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, id string) error {
 
 		shareAmi := options.Ami
 		shareRegion := img.Region
+		var shareKMSKeyARN string
+		if options.KmsKeyArn != nil {
+			shareKMSKeyARN = *options.KmsKeyArn
+		}
+		var copyKMSKeyID string
+		if img.KmsKeyId != nil {
+			copyKMSKeyID = *img.KmsKeyId
+		}
 		if img.Region != options.Region {
 			// Let the share job use dynArgs
 			shareAmi = ""
 			shareRegion = ""
+			shareKMSKeyARN = ""
 
 			// Check dependents if we need to do a copyjob
 			foundDep := false
@@ -1152,7 +2116,13 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, id string) error {
 						return HTTPErrorWithInternal(ErrorGettingAWSEC2JobStatus, err)
 					}
 
-					if cjResult.JobError == nil && options.Region == cjResult.Region {
+					var cj worker.AWSEC2CopyJob
+					err = h.server.workers.AWSEC2CopyJob(d, &cj)
+					if err != nil {
+						return HTTPErrorWithInternal(ErrorGettingAWSEC2JobStatus, err)
+					}
+
+					if cjResult.JobError == nil && options.Region == cjResult.Region && cj.KMSKeyID == copyKMSKeyID {
 						finalJob = d
 						foundDep = true
 						break
@@ -1166,6 +2136,7 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, id string) error {
 					SourceRegion: options.Region,
 					TargetRegion: img.Region,
 					TargetName:   fmt.Sprintf("composer-api-%s", uuid.New().String()),
+					KMSKeyID:     copyKMSKeyID,
 				}
 				finalJob, err = h.server.workers.EnqueueAWSEC2CopyJob(copyJob, finalJob, channel)
 				if err != nil {
@@ -1186,10 +2157,16 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, id string) error {
 			shares = append(shares, (*img.ShareWithAccounts)...)
 		}
 		if len(shares) > 0 {
+			// copies are encrypted with the default EBS key of the region,
+			// which can't be shared
+			if options.KmsKeyArn != nil && img.Region != options.Region && copyKMSKeyID == "" {
+				return HTTPError(ErrorKMSKeyRequired)
+			}
 			shareJob := &worker.AWSEC2ShareJob{
 				Ami:               shareAmi,
 				Region:            shareRegion,
 				ShareWithAccounts: shares,
+				KMSKeyARN:         shareKMSKeyARN,
 			}
 			finalJob, err = h.server.workers.EnqueueAWSEC2ShareJob(shareJob, finalJob, channel)
 			if err != nil {
@@ -1271,6 +2248,263 @@ func (h *apiHandlers) getCloneStatus(ctx echo.Context, id string) error {
 	})
 }
 
+// AWS Marketplace only ingests AMIs from this region
+const awsMarketplaceRegion = "us-east-1"
+
+func (h *apiHandlers) PostMarketplacePublish(ctx echo.Context, id string) error {
+	return h.server.EnsureJobChannel(h.postMarketplacePublishImpl)(ctx, id)
+}
+
+func (h *apiHandlers) postMarketplacePublishImpl(ctx echo.Context, id string) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return HTTPError(ErrorInvalidComposeId)
+	}
+
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+
+	if jobType != worker.JobTypeOSBuild {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	var osbuildResult worker.OSBuildJobResult
+	osbuildInfo, err := h.server.workers.OSBuildJobInfo(jobId, &osbuildResult)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingOSBuildJobStatus, err)
+	}
+
+	if osbuildInfo.JobStatus.Finished.IsZero() || !osbuildResult.Success {
+		return HTTPError(ErrorComposeBadState)
+	}
+
+	if osbuildResult.TargetResults == nil {
+		return HTTPError(ErrorMalformedOSBuildJobResult)
+	}
+	if len(osbuildResult.TargetResults) != 1 {
+		return HTTPError(ErrorSeveralUploadTargets)
+	}
+	us, err := targetResultToUploadStatus(osbuildResult.TargetResults[0])
+	if err != nil {
+		return HTTPError(ErrorUnknownUploadTarget)
+	}
+	if us.Type != UploadTypesAws {
+		return HTTPError(ErrorUnsupportedImage)
+	}
+	options := us.Options.(AWSEC2UploadStatus)
+	if options.Ami == "" {
+		return HTTPError(ErrorNoAMIToPublish)
+	}
+
+	var body AWSMarketplacePublishRequest
+	err = ctx.Bind(&body)
+	if err != nil {
+		return err
+	}
+
+	publishJob := &worker.AWSMarketplacePublishJob{
+		Ami:                     options.Ami,
+		Region:                  options.Region,
+		ProductID:               body.ProductId,
+		VersionTitle:            body.VersionTitle,
+		AccessRoleARN:           body.AccessRoleArn,
+		UserName:                body.UserName,
+		OperatingSystemName:     body.OperatingSystemName,
+		OperatingSystemVersion:  body.OperatingSystemVersion,
+		UsageInstructions:       body.UsageInstructions,
+		RecommendedInstanceType: body.RecommendedInstanceType,
+	}
+	if body.ReleaseNotes != nil {
+		publishJob.ReleaseNotes = *body.ReleaseNotes
+	}
+	if body.SecurityGroups != nil {
+		for _, sg := range *body.SecurityGroups {
+			publishJob.SecurityGroups = append(publishJob.SecurityGroups, worker.AWSMarketplaceSecurityGroup{
+				IpProtocol: string(sg.IpProtocol),
+				FromPort:   sg.FromPort,
+				ToPort:     sg.ToPort,
+				IpRanges:   sg.IpRanges,
+			})
+		}
+	}
+
+	// the AMI has to be copied to us-east-1 first, let the publish job
+	// use the result of the copy job as dynArgs
+	parent := jobId
+	if options.Region != awsMarketplaceRegion {
+		publishJob.Ami = ""
+		publishJob.Region = ""
+
+		copyJob := &worker.AWSEC2CopyJob{
+			Ami:          options.Ami,
+			SourceRegion: options.Region,
+			TargetRegion: awsMarketplaceRegion,
+			TargetName:   fmt.Sprintf("composer-api-%s", uuid.New().String()),
+		}
+		parent, err = h.server.workers.EnqueueAWSEC2CopyJob(copyJob, jobId, channel)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+		}
+	}
+
+	publishJobId, err := h.server.workers.EnqueueAWSMarketplacePublishJob(publishJob, parent, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, MarketplacePublishResponse{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/marketplace", jobId),
+			Id:   publishJobId.String(),
+			Kind: "MarketplacePublishId",
+		},
+		Id: publishJobId.String(),
+	})
+}
+
+func (h *apiHandlers) GetMarketplacePublishStatus(ctx echo.Context, id string) error {
+	return h.server.EnsureJobChannel(h.getMarketplacePublishStatusImpl)(ctx, id)
+}
+
+func (h *apiHandlers) getMarketplacePublishStatusImpl(ctx echo.Context, id string) error {
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return HTTPError(ErrorInvalidComposeId)
+	}
+
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+
+	if jobType != worker.JobTypeAWSMarketplacePublish {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	var result worker.AWSMarketplacePublishJobResult
+	info, err := h.server.workers.AWSMarketplacePublishJobInfo(jobId, &result)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingMarketplacePublishStatus, err)
+	}
+
+	resp := MarketplacePublishStatus{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/marketplace/%v", jobId),
+			Id:   jobId.String(),
+			Kind: "MarketplacePublishStatus",
+		},
+		Status: uploadStatusFromJobStatus(info.JobStatus, result.JobError),
+		Error:  composeStatusErrorFromJobError(result.JobError),
+	}
+	if result.Ami != "" {
+		resp.Ami = common.ToPtr(result.Ami)
+	}
+	if result.ChangeSetID != "" {
+		resp.ChangeSetId = common.ToPtr(result.ChangeSetID)
+		resp.ChangeSetArn = common.ToPtr(result.ChangeSetARN)
+		resp.ChangeSetStatus = common.ToPtr(result.ChangeSetStatus)
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *apiHandlers) PostUpgradeCompose(ctx echo.Context, id string) error {
+	return h.server.EnsureJobChannel(h.postUpgradeComposeImpl)(ctx, id)
+}
+
+func (h *apiHandlers) postUpgradeComposeImpl(ctx echo.Context, id string) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return HTTPError(ErrorInvalidComposeId)
+	}
+
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+
+	if jobType != worker.JobTypeOSBuild {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	var body ComposeUpgradeRequest
+	err = ctx.Bind(&body)
+	if err != nil {
+		return err
+	}
+
+	distribution := h.server.distros.GetDistro(body.Distribution)
+	if distribution == nil {
+		return HTTPError(ErrorUnsupportedDistribution)
+	}
+
+	var osbuildJob worker.OSBuildJob
+	err = h.server.workers.OSBuildJob(jobId, &osbuildJob)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+
+	// composes submitted before the requests were kept can't be upgraded
+	if len(osbuildJob.ComposeRequest) == 0 {
+		return HTTPError(ErrorComposeRequestNotAvailable)
+	}
+
+	var request ComposeRequest
+	err = json.Unmarshal(osbuildJob.ComposeRequest, &request)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorJSONUnMarshallingError, err)
+	}
+	// the composes submitted before the credentials were removed from the
+	// kept requests still have them
+	request, redactedFields, err := redactComposeRequest(request)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorJSONMarshallingError, err)
+	}
+	redactedFields = append(redactedFields, osbuildJob.RedactedFields...)
+
+	issues := checkComposeUpgrade(&request, request.Distribution, distribution)
+	for _, field := range redactedFields {
+		issues = append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityError, nil, "The credentials in %s aren't kept with the compose, set them in the compose request and submit it", field))
+	}
+	request.Distribution = distribution.Name()
+
+	response := ComposeUpgradeResponse{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/upgrade", jobId),
+			Id:   jobId.String(),
+			Kind: "ComposeUpgrade",
+		},
+		Compatible:     upgradeCompatible(issues),
+		Issues:         issues,
+		ComposeRequest: request,
+	}
+
+	if body.Submit == nil || !*body.Submit || !response.Compatible {
+		return ctx.JSON(http.StatusOK, response)
+	}
+
+	newId, err := h.submitCompose(request, channel)
+	if err != nil {
+		return err
+	}
+
+	ctx.Logger().Infof("Job ID %s enqueued for operationID %s", newId, ctx.Get(common.OperationIDKey))
+
+	response.ComposeId = common.ToPtr(newId.String())
+	return ctx.JSON(http.StatusCreated, response)
+}
+
 // TODO: determine upload status based on the target results, not job results
 func uploadStatusFromJobStatus(js *worker.JobStatus, je *clienterrors.Error) UploadStatusValue {
 	if je != nil || js.Canceled {
@@ -0,0 +1,77 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+const (
+	identityMachineIDPath = "/etc/machine-id"
+
+	identityCleanupService = "osbuild-identity-cleanup.service"
+	identityCleanupPath    = "/etc/systemd/system/" + identityCleanupService
+	// the cleanup runs once, at the first boot of each copy of the image
+	identityCleanupFlag = "/etc/osbuild-identity-cleanup"
+)
+
+// identitySSHHostKeys and identitySubscription are the files the cleanup
+// removes for the options.
+var (
+	identitySSHHostKeys  = []string{"/etc/ssh/ssh_host_*_key", "/etc/ssh/ssh_host_*_key.pub"}
+	identitySubscription = []string{"/etc/pki/consumer/*.pem", "/etc/pki/entitlement/*.pem", "/etc/insights-client/machine-id"}
+)
+
+// identityCustomizations returns the files and the services making copies
+// of the image get identities of their own. An empty machine-id is
+// generated by systemd at boot, the other identities are removed early at
+// the first boot, before sshd generates new host keys and the system is
+// registered.
+func identityCustomizations(identity *Identity) ([]blueprint.FileCustomization, []string) {
+	var files []blueprint.FileCustomization
+	if identity.ClearMachineId != nil && *identity.ClearMachineId {
+		files = append(files, blueprint.FileCustomization{
+			Path: identityMachineIDPath,
+			Mode: "0444",
+		})
+	}
+
+	var remove []string
+	if identity.RemoveSshHostKeys != nil && *identity.RemoveSshHostKeys {
+		remove = append(remove, identitySSHHostKeys...)
+	}
+	if identity.RemoveSubscription != nil && *identity.RemoveSubscription {
+		remove = append(remove, identitySubscription...)
+	}
+	if len(remove) == 0 {
+		return files, nil
+	}
+
+	var unit strings.Builder
+	unit.WriteString("[Unit]\n")
+	unit.WriteString("Description=Remove the identities the image was built with\n")
+	unit.WriteString("DefaultDependencies=no\n")
+	fmt.Fprintf(&unit, "ConditionPathExists=%s\n", identityCleanupFlag)
+	unit.WriteString("After=local-fs.target\n")
+	unit.WriteString("Before=sysinit.target\n")
+	unit.WriteString("\n[Service]\n")
+	unit.WriteString("Type=oneshot\n")
+	fmt.Fprintf(&unit, "ExecStart=/usr/bin/sh -c 'rm -f %s'\n", strings.Join(remove, " "))
+	fmt.Fprintf(&unit, "ExecStartPost=/usr/bin/rm -f %s\n", identityCleanupFlag)
+	unit.WriteString("\n[Install]\n")
+	unit.WriteString("WantedBy=sysinit.target\n")
+
+	files = append(files,
+		blueprint.FileCustomization{
+			Path: identityCleanupPath,
+			Mode: "0644",
+			Data: unit.String(),
+		},
+		blueprint.FileCustomization{
+			Path: identityCleanupFlag,
+			Mode: "0644",
+		},
+	)
+	return files, []string{identityCleanupService}
+}
@@ -2,14 +2,23 @@ package v2
 
 // ImageTypes methods to make it easier to use and test
 import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 
+	"cloud.google.com/go/compute/apiv1/computepb"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/google/uuid"
 	"github.com/osbuild/images/pkg/disk"
 	"github.com/osbuild/images/pkg/distro"
 	"github.com/osbuild/images/pkg/ostree"
+	"github.com/osbuild/images/pkg/rpmmd"
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/target"
@@ -36,6 +45,181 @@ func (ir *ImageRequest) GetImageOptions(imageType distro.ImageType, bp blueprint
 	return distro.ImageOptions{Size: size, PartitioningMode: disk.AutoLVMPartitioningMode}
 }
 
+// checkImageTypeCustomizations validates the partitioning, kernel, file and
+// directory customizations against the image type before any job is
+// enqueued. Which mountpoints and paths are allowed and whether the
+// partitioning or the kernel command line can be customized at all is only
+// known to the image type, so the manifest is generated to check them.
+func checkImageTypeCustomizations(request *ComposeRequest, imageType distro.ImageType, bp blueprint.Blueprint, options distro.ImageOptions, repos []rpmmd.RepoConfig) error {
+	if !request.hasPartitioningCustomizations() && !request.hasKernelCustomizations() && !request.hasFSNodeCustomizations() {
+		return nil
+	}
+	ibp := blueprint.Convert(bp)
+	_, _, err := imageType.Manifest(&ibp, options, repos, 0)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("customizations not supported by image type %s: %v", imageType.Name(), err))
+	}
+	return nil
+}
+
+// kernelArches lists the architectures of the kernel variants which aren't
+// built for all of them.
+var kernelArches = map[string][]string{
+	"kernel-64k": {"aarch64"},
+}
+
+// checkKernel validates the kernel customization against the image type:
+// only image types which boot have a kernel to select or a kernel command
+// line to append to.
+func checkKernel(request *ComposeRequest, imageType distro.ImageType) error {
+	if !request.hasKernelCustomizations() {
+		return nil
+	}
+	if imageType.BootMode() == distro.BOOT_NONE {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("image type %s doesn't boot, the kernel can't be customized", imageType.Name()))
+	}
+
+	kernel := request.Customizations.Kernel
+	if kernel.Name == nil {
+		return nil
+	}
+	if arches, ok := kernelArches[*kernel.Name]; ok && !common.IsStringInSortedSlice(arches, imageType.Arch().Name()) {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("kernel %s is not available on %s", *kernel.Name, imageType.Arch().Name()))
+	}
+	return nil
+}
+
+// ignitionImageTypes are the image types which provision the system with
+// Ignition on its first boot.
+var ignitionImageTypes = map[ImageTypes]bool{
+	ImageTypesEdgeRawImage:            true,
+	ImageTypesEdgeSimplifiedInstaller: true,
+	ImageTypesIotRawImage:             true,
+	ImageTypesIotSimplifiedInstaller:  true,
+}
+
+// checkIgnition validates the ignition customization, the embedded config has
+// to be a base64 encoded JSON document.
+func checkIgnition(request *ComposeRequest, imageType ImageTypes) error {
+	if request.Customizations == nil || request.Customizations.Ignition == nil {
+		return nil
+	}
+	if !ignitionImageTypes[imageType] {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("image type %s doesn't support ignition", imageType))
+	}
+
+	ignition := request.Customizations.Ignition
+	if ignition.Embedded != nil && ignition.Firstboot != nil {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the ignition config can either be embedded or fetched on the first boot, not both"))
+	}
+	if ignition.Embedded != nil {
+		config, err := base64.StdEncoding.DecodeString(ignition.Embedded.Config)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the embedded ignition config isn't base64 encoded: %v", err))
+		}
+		var doc struct {
+			Ignition *struct {
+				Version string `json:"version"`
+			} `json:"ignition"`
+		}
+		err = json.Unmarshal(config, &doc)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the embedded ignition config isn't valid JSON: %v", err))
+		}
+		if doc.Ignition == nil || doc.Ignition.Version == "" {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the embedded ignition config has to set ignition.version"))
+		}
+	}
+	return nil
+}
+
+// ostreeRemoteImageTypes are the image types building an ostree commit, the
+// ostree remote customization is written into the commit.
+var ostreeRemoteImageTypes = map[ImageTypes]bool{
+	ImageTypesEdgeCommit:    true,
+	ImageTypesEdgeContainer: true,
+	ImageTypesIotCommit:     true,
+	ImageTypesIotContainer:  true,
+}
+
+func checkOSTreeRemote(request *ComposeRequest, imageType ImageTypes) error {
+	if request.Customizations == nil || request.Customizations.OstreeRemote == nil {
+		return nil
+	}
+	if !ostreeRemoteImageTypes[imageType] {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("image type %s doesn't build an ostree commit, the ostree remote can't be configured", imageType))
+	}
+	return nil
+}
+
+// fdoImageTypes are the installers initializing the device with FDO when
+// they install it.
+var fdoImageTypes = map[ImageTypes]bool{
+	ImageTypesEdgeSimplifiedInstaller: true,
+	ImageTypesIotSimplifiedInstaller:  true,
+}
+
+// fdoDiunPubKeyHash is the format of the hash of the key of the
+// manufacturing server
+var fdoDiunPubKeyHash = regexp.MustCompile(`^sha(256|384):[0-9a-fA-F]+$`)
+
+// checkFDO validates the FDO customization, the manufacturing server URL is
+// required and the device has to verify the manufacturing server in exactly
+// one way. It's validated here instead of the schema, which accepted any
+// string before.
+func checkFDO(request *ComposeRequest, imageType ImageTypes) error {
+	if request.Customizations == nil || request.Customizations.Fdo == nil {
+		return nil
+	}
+	if !fdoImageTypes[imageType] {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("image type %s doesn't support FDO", imageType))
+	}
+
+	fdo := request.Customizations.Fdo
+	if fdo.ManufacturingServerUrl == nil || *fdo.ManufacturingServerUrl == "" {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the FDO manufacturing server URL is required"))
+	}
+	u, err := url.Parse(*fdo.ManufacturingServerUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the FDO manufacturing server URL has to be an http or https URL"))
+	}
+
+	var diun int
+	if fdo.DiunPubKeyInsecure != nil && *fdo.DiunPubKeyInsecure != "" {
+		diun++
+	}
+	if fdo.DiunPubKeyHash != nil && *fdo.DiunPubKeyHash != "" {
+		diun++
+		if !fdoDiunPubKeyHash.MatchString(*fdo.DiunPubKeyHash) {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the FDO DIUN public key hash has to be a sha256 or sha384 hash, e.g. sha256:<hex>"))
+		}
+	}
+	if fdo.DiunPubKeyRootCerts != nil && *fdo.DiunPubKeyRootCerts != "" {
+		diun++
+		rest := []byte(*fdo.DiunPubKeyRootCerts)
+		var certs int
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			_, err := x509.ParseCertificate(block.Bytes)
+			if block.Type != "CERTIFICATE" || err != nil {
+				return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the FDO DIUN root certs have to be PEM encoded certificates"))
+			}
+			certs++
+		}
+		if certs == 0 || len(bytes.TrimSpace(rest)) > 0 {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the FDO DIUN root certs have to be PEM encoded certificates"))
+		}
+	}
+	if diun != 1 {
+		return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("exactly one of the FDO DIUN public key options has to be set"))
+	}
+	return nil
+}
+
 func newAWSTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
 	var awsUploadOptions AWSEC2UploadOptions
 	jsonUploadOptions, err := json.Marshal(options)
@@ -68,7 +252,18 @@ func newAWSTarget(options UploadOptions, imageType distro.ImageType) (*target.Ta
 		Key:               key,
 		ShareWithAccounts: awsUploadOptions.ShareWithAccounts,
 		BootMode:          amiBootMode,
+		SnapshotOnly:      awsUploadOptions.SnapshotOnly != nil && *awsUploadOptions.SnapshotOnly,
 	})
+	if awsUploadOptions.KmsKeyId != nil {
+		if *awsUploadOptions.KmsKeyId == "" {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		t.Options.(*target.AWSTargetOptions).KMSKeyID = *awsUploadOptions.KmsKeyId
+	}
+	err = setAWSRegisterOptions(t.Options.(*target.AWSTargetOptions), awsUploadOptions, imageType)
+	if err != nil {
+		return nil, err
+	}
 	if awsUploadOptions.SnapshotName != nil {
 		t.ImageName = *awsUploadOptions.SnapshotName
 	} else {
@@ -78,6 +273,41 @@ func newAWSTarget(options UploadOptions, imageType distro.ImageType) (*target.Ta
 	return t, nil
 }
 
+// setAWSRegisterOptions sets the parameters the AMI is registered with.
+func setAWSRegisterOptions(targetOptions *target.AWSTargetOptions, awsUploadOptions AWSEC2UploadOptions, imageType distro.ImageType) error {
+	if awsUploadOptions.BootMode != nil {
+		bootMode := string(*awsUploadOptions.BootMode)
+		// only image types booting in both modes can pick one
+		switch imageType.BootMode() {
+		case distro.BOOT_LEGACY:
+			if bootMode != ec2.BootModeValuesLegacyBios {
+				return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("image type %s only supports legacy BIOS boot", imageType.Name()))
+			}
+		case distro.BOOT_UEFI:
+			if bootMode != ec2.BootModeValuesUefi {
+				return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("image type %s only supports UEFI boot", imageType.Name()))
+			}
+		}
+		targetOptions.BootMode = &bootMode
+	}
+
+	if awsUploadOptions.Tags != nil {
+		for key := range awsUploadOptions.Tags.AdditionalProperties {
+			// the Name tag identifies images of the service
+			if key == "Name" || strings.HasPrefix(strings.ToLower(key), "aws:") {
+				return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("tag %s is reserved", key))
+			}
+		}
+		targetOptions.Tags = awsUploadOptions.Tags.AdditionalProperties
+	}
+	if awsUploadOptions.ImdsSupport != nil {
+		targetOptions.IMDSSupport = common.ToPtr(string(*awsUploadOptions.ImdsSupport))
+	}
+	targetOptions.ENASupport = awsUploadOptions.EnaSupport
+	targetOptions.SriovNetSupport = awsUploadOptions.SriovNetSupport != nil && *awsUploadOptions.SriovNetSupport
+	return nil
+}
+
 func newAWSS3Target(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
 	var awsS3UploadOptions AWSS3UploadOptions
 	jsonUploadOptions, err := json.Marshal(options)
@@ -93,13 +323,85 @@ func newAWSS3Target(options UploadOptions, imageType distro.ImageType) (*target.
 	if awsS3UploadOptions.Public != nil && *awsS3UploadOptions.Public {
 		public = true
 	}
+	var acl string
+	if awsS3UploadOptions.Acl != nil {
+		switch *awsS3UploadOptions.Acl {
+		case AWSS3UploadOptionsAclPublicRead:
+			public = true
+		case AWSS3UploadOptionsAclPrivate, AWSS3UploadOptionsAclBucketOwnerRead, AWSS3UploadOptionsAclBucketOwnerFullControl:
+			// public objects are readable by everyone anyway
+			if public {
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+			// private is the default ACL of uploaded objects
+			if *awsS3UploadOptions.Acl != AWSS3UploadOptionsAclPrivate {
+				acl = string(*awsS3UploadOptions.Acl)
+			}
+		default:
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+	}
+	var urlExpiryHours int
+	if awsS3UploadOptions.UrlExpiryHours != nil {
+		// public objects have no presigned URL
+		if public || *awsS3UploadOptions.UrlExpiryHours < 1 || *awsS3UploadOptions.UrlExpiryHours > 168 {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		urlExpiryHours = *awsS3UploadOptions.UrlExpiryHours
+	}
+
+	var ostreeMirror *target.OSTreeMirrorOptions
+	if mirror := awsS3UploadOptions.OstreeMirror; mirror != nil {
+		// only the commit image types produce a commit which can be pushed
+		if imageType.OSTreeRef() == "" || !strings.HasSuffix(imageType.Name(), "-commit") {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		ostreeMirror = &target.OSTreeMirrorOptions{URL: mirror.Url}
+		if mirror.Username != nil {
+			ostreeMirror.Username = *mirror.Username
+		}
+		if mirror.Password != nil {
+			ostreeMirror.Password = *mirror.Password
+		}
+	}
 
 	key := fmt.Sprintf("composer-api-%s", uuid.New().String())
-	t := target.NewAWSS3Target(&target.AWSS3TargetOptions{
-		Region: awsS3UploadOptions.Region,
-		Key:    key,
-		Public: public,
-	})
+	targetOptions := &target.AWSS3TargetOptions{
+		Region:          awsS3UploadOptions.Region,
+		Key:             key,
+		Public:          public,
+		URLExpiryHours:  urlExpiryHours,
+		ACL:             acl,
+		ReturnObjectKey: awsS3UploadOptions.ReturnObjectKey != nil && *awsS3UploadOptions.ReturnObjectKey,
+		OSTreeMirror:    ostreeMirror,
+	}
+	if awsS3UploadOptions.Bucket != nil {
+		targetOptions.Bucket = *awsS3UploadOptions.Bucket
+	}
+	// the generic S3 options only apply to S3-compatible endpoints, the
+	// credentials of the worker are never sent to endpoints of requests
+	if awsS3UploadOptions.Endpoint != nil {
+		if awsS3UploadOptions.AccessKeyId == nil || *awsS3UploadOptions.AccessKeyId == "" ||
+			awsS3UploadOptions.SecretAccessKey == nil || *awsS3UploadOptions.SecretAccessKey == "" {
+			return nil, HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("uploads to an endpoint require its credentials"))
+		}
+		targetOptions.Endpoint = *awsS3UploadOptions.Endpoint
+		targetOptions.AccessKeyID = *awsS3UploadOptions.AccessKeyId
+		targetOptions.SecretAccessKey = *awsS3UploadOptions.SecretAccessKey
+		if awsS3UploadOptions.SessionToken != nil {
+			targetOptions.SessionToken = *awsS3UploadOptions.SessionToken
+		}
+		if awsS3UploadOptions.PathStyle != nil {
+			targetOptions.VirtualHostedStyle = !*awsS3UploadOptions.PathStyle
+		}
+		if awsS3UploadOptions.CaBundle != nil {
+			targetOptions.CABundleData = *awsS3UploadOptions.CaBundle
+		}
+	} else if awsS3UploadOptions.PathStyle != nil || awsS3UploadOptions.CaBundle != nil ||
+		awsS3UploadOptions.AccessKeyId != nil || awsS3UploadOptions.SecretAccessKey != nil || awsS3UploadOptions.SessionToken != nil {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+	t := target.NewAWSS3Target(targetOptions)
 	t.ImageName = key
 	t.OsbuildArtifact.ExportFilename = imageType.Filename()
 	return t, nil
@@ -125,13 +427,90 @@ func newContainerTarget(options UploadOptions, request *ComposeRequest, imageTyp
 		}
 	}
 
-	t := target.NewContainerTarget(&target.ContainerTargetOptions{})
+	targetOptions := &target.ContainerTargetOptions{
+		Sign: containerUploadOptions.Sign != nil && *containerUploadOptions.Sign,
+	}
+	if containerUploadOptions.Username != nil {
+		targetOptions.Username = *containerUploadOptions.Username
+	}
+	if containerUploadOptions.Password != nil {
+		targetOptions.Password = *containerUploadOptions.Password
+	}
+	if containerUploadOptions.Tags != nil {
+		for _, additionalTag := range *containerUploadOptions.Tags {
+			if additionalTag.Tag == "" {
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+			tagName := name
+			if additionalTag.Name != nil {
+				tagName = *additionalTag.Name
+			}
+			targetOptions.AdditionalReferences = append(targetOptions.AdditionalReferences, fmt.Sprintf("%s:%s", tagName, additionalTag.Tag))
+		}
+	}
+
+	t := target.NewContainerTarget(targetOptions)
 	t.ImageName = fmt.Sprintf("%s:%s", name, tag)
 	t.OsbuildArtifact.ExportFilename = imageType.Filename()
 
 	return t, nil
 }
 
+// Types of the ORAS artifacts and of their layers if not set in the request.
+const (
+	defaultORASArtifactType = "application/vnd.osbuild.image.v1"
+	defaultORASMediaType    = "application/octet-stream"
+)
+
+func newORASTarget(options UploadOptions, request *ComposeRequest, imageType distro.ImageType) (*target.Target, error) {
+	var orasUploadOptions ORASUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &orasUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	var name = request.Distribution
+	var tag = uuid.New().String()
+	if orasUploadOptions.Name != nil {
+		name = *orasUploadOptions.Name
+	}
+	if orasUploadOptions.Tag != nil {
+		tag = *orasUploadOptions.Tag
+	}
+
+	targetOptions := &target.ORASTargetOptions{
+		ArtifactType: defaultORASArtifactType,
+		MediaType:    defaultORASMediaType,
+	}
+	if orasUploadOptions.ArtifactType != nil {
+		targetOptions.ArtifactType = *orasUploadOptions.ArtifactType
+	}
+	if orasUploadOptions.MediaType != nil {
+		targetOptions.MediaType = *orasUploadOptions.MediaType
+	}
+	if targetOptions.ArtifactType == "" || targetOptions.MediaType == "" {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+	if orasUploadOptions.Annotations != nil {
+		targetOptions.Annotations = orasUploadOptions.Annotations.AdditionalProperties
+	}
+	if orasUploadOptions.Username != nil {
+		targetOptions.Username = *orasUploadOptions.Username
+	}
+	if orasUploadOptions.Password != nil {
+		targetOptions.Password = *orasUploadOptions.Password
+	}
+
+	t := target.NewORASTarget(targetOptions)
+	t.ImageName = fmt.Sprintf("%s:%s", name, tag)
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
 func newGCPTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
 	var gcpUploadOptions GCPUploadOptions
 	jsonUploadOptions, err := json.Marshal(options)
@@ -167,10 +546,63 @@ func newGCPTarget(options UploadOptions, imageType distro.ImageType) (*target.Ta
 	} else {
 		t.ImageName = imageName
 	}
+	err = setGCPImageOptions(t.Options.(*target.GCPTargetOptions), gcpUploadOptions, imageType)
+	if err != nil {
+		return nil, err
+	}
+	if export := gcpUploadOptions.Export; export != nil {
+		object := fmt.Sprintf("%s.tar.gz", t.ImageName)
+		if export.Object != nil {
+			object = *export.Object
+		}
+		t.Options.(*target.GCPTargetOptions).Export = &target.GCPImageExportOptions{
+			Bucket: export.Bucket,
+			Object: object,
+		}
+	}
 	t.OsbuildArtifact.ExportFilename = imageType.Filename()
 	return t, nil
 }
 
+// Compute Engine architectures of the image architectures
+var gcpArchitectures = map[string]string{
+	"x86_64":  string(GCPUploadOptionsArchitectureX8664),
+	"aarch64": string(GCPUploadOptionsArchitectureARM64),
+}
+
+// names of resources in GCP, e.g. image families
+var gcpNameRegex = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// setGCPImageOptions sets the properties of the image in Compute Engine.
+func setGCPImageOptions(targetOptions *target.GCPTargetOptions, gcpUploadOptions GCPUploadOptions, imageType distro.ImageType) error {
+	if gcpUploadOptions.GuestOsFeatures != nil {
+		for _, feature := range *gcpUploadOptions.GuestOsFeatures {
+			if value, ok := computepb.GuestOsFeature_Type_value[feature]; !ok || value == int32(computepb.GuestOsFeature_UNDEFINED_TYPE) || value == int32(computepb.GuestOsFeature_FEATURE_TYPE_UNSPECIFIED) {
+				return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("unknown guest OS feature %q", feature))
+			}
+		}
+		targetOptions.GuestOsFeatures = *gcpUploadOptions.GuestOsFeatures
+	}
+
+	// the architecture has to be the one of the image anyway
+	architecture := gcpArchitectures[imageType.Arch().Name()]
+	if gcpUploadOptions.Architecture != nil && string(*gcpUploadOptions.Architecture) != architecture {
+		return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("architecture %s doesn't match the %s image", *gcpUploadOptions.Architecture, imageType.Arch().Name()))
+	}
+	targetOptions.Architecture = architecture
+
+	if gcpUploadOptions.Family != nil {
+		if !gcpNameRegex.MatchString(*gcpUploadOptions.Family) {
+			return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("invalid image family %q", *gcpUploadOptions.Family))
+		}
+		targetOptions.Family = *gcpUploadOptions.Family
+	}
+	if gcpUploadOptions.Licenses != nil {
+		targetOptions.Licenses = *gcpUploadOptions.Licenses
+	}
+	return nil
+}
+
 func newAzureTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
 	var azureUploadOptions AzureUploadOptions
 	jsonUploadOptions, err := json.Marshal(options)
@@ -192,6 +624,56 @@ func newAzureTarget(options UploadOptions, imageType distro.ImageType) (*target.
 		ResourceGroup:  azureUploadOptions.ResourceGroup,
 	})
 
+	if gallery := azureUploadOptions.Gallery; gallery != nil {
+		galleryOptions := &target.AzureGalleryOptions{
+			Name:            gallery.GalleryName,
+			ImageDefinition: gallery.ImageDefinition,
+			Version:         gallery.ImageVersion,
+		}
+		if gallery.ReplicaCount != nil {
+			galleryOptions.ReplicaCount = *gallery.ReplicaCount
+		}
+		if gallery.StorageAccountType != nil {
+			galleryOptions.StorageAccountType = string(*gallery.StorageAccountType)
+		}
+		if gallery.TargetRegions != nil {
+			for _, r := range *gallery.TargetRegions {
+				region := target.AzureGalleryTargetRegion{
+					Name: r.Name,
+				}
+				if r.ReplicaCount != nil {
+					region.ReplicaCount = *r.ReplicaCount
+				}
+				if r.StorageAccountType != nil {
+					region.StorageAccountType = string(*r.StorageAccountType)
+				}
+				galleryOptions.TargetRegions = append(galleryOptions.TargetRegions, region)
+			}
+		}
+		t.Options.(*target.AzureImageTargetOptions).Gallery = galleryOptions
+	}
+
+	if azureUploadOptions.BlobOnly != nil && *azureUploadOptions.BlobOnly {
+		// there is no image to publish
+		if azureUploadOptions.Gallery != nil {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		t.Options.(*target.AzureImageTargetOptions).BlobOnly = true
+		if azureUploadOptions.SasExpiryHours != nil {
+			if *azureUploadOptions.SasExpiryHours < 1 || *azureUploadOptions.SasExpiryHours > 8760 {
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+			t.Options.(*target.AzureImageTargetOptions).SASExpiryHours = *azureUploadOptions.SasExpiryHours
+		}
+	} else if azureUploadOptions.SasExpiryHours != nil {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+
+	err = setAzureImageOptions(t.Options.(*target.AzureImageTargetOptions), azureUploadOptions, imageType)
+	if err != nil {
+		return nil, err
+	}
+
 	if azureUploadOptions.ImageName != nil {
 		t.ImageName = *azureUploadOptions.ImageName
 	} else {
@@ -202,6 +684,53 @@ func newAzureTarget(options UploadOptions, imageType distro.ImageType) (*target.
 	return t, nil
 }
 
+// setAzureImageOptions sets the Hyper-V generation and the security type of
+// the registered image.
+func setAzureImageOptions(targetOptions *target.AzureImageTargetOptions, azureUploadOptions AzureUploadOptions, imageType distro.ImageType) error {
+	var generation string
+	if azureUploadOptions.HyperVGeneration != nil {
+		generation = string(*azureUploadOptions.HyperVGeneration)
+	}
+	var securityType string
+	if azureUploadOptions.SecurityType != nil {
+		securityType = string(*azureUploadOptions.SecurityType)
+	}
+	if targetOptions.BlobOnly {
+		// there is no image
+		if generation != "" || securityType != "" {
+			return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("blob_only images have no Hyper-V generation or security type"))
+		}
+		return nil
+	}
+
+	if securityType != "" && securityType != string(AzureUploadOptionsSecurityTypeStandard) {
+		// only gallery image definitions have a security type
+		if targetOptions.Gallery == nil {
+			return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("security type %s requires publishing the image to a gallery", securityType))
+		}
+		if generation == string(AzureUploadOptionsHyperVGenerationV1) {
+			return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("security type %s requires a V2 image", securityType))
+		}
+		generation = string(AzureUploadOptionsHyperVGenerationV2)
+	}
+
+	switch imageType.BootMode() {
+	case distro.BOOT_LEGACY:
+		if generation == string(AzureUploadOptionsHyperVGenerationV2) {
+			return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("image type %s doesn't boot with UEFI, which V2 images require", imageType.Name()))
+		}
+	case distro.BOOT_UEFI:
+		if generation == string(AzureUploadOptionsHyperVGenerationV1) {
+			return HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("image type %s doesn't boot with BIOS, which V1 images require", imageType.Name()))
+		}
+		generation = string(AzureUploadOptionsHyperVGenerationV2)
+	}
+
+	targetOptions.HyperVGeneration = generation
+	targetOptions.SecurityType = securityType
+	return nil
+}
+
 func newOCITarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
 	var ociUploadOptions OCIUploadOptions
 	jsonUploadOptions, err := json.Marshal(options)
@@ -214,7 +743,54 @@ func newOCITarget(options UploadOptions, imageType distro.ImageType) (*target.Ta
 	}
 
 	key := fmt.Sprintf("composer-api-%s", uuid.New().String())
-	t := target.NewOCIObjectStorageTarget(&target.OCIObjectStorageTargetOptions{})
+	var t *target.Target
+	if importOptions := ociUploadOptions.Import; importOptions != nil {
+		// the object is imported as a custom image by the org.osbuild.oci
+		// target, the credentials and compartment come from the worker
+		targetOptions := &target.OCITargetOptions{}
+		if importOptions.LaunchMode != nil {
+			switch *importOptions.LaunchMode {
+			case OCIImageImportOptionsLaunchModeNATIVE, OCIImageImportOptionsLaunchModeEMULATED, OCIImageImportOptionsLaunchModePARAVIRTUALIZED:
+				targetOptions.LaunchMode = string(*importOptions.LaunchMode)
+			default:
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+		}
+		if importOptions.CompatibleShapes != nil {
+			targetOptions.Shapes = *importOptions.CompatibleShapes
+		}
+		t = target.NewOCITarget(targetOptions)
+		if importOptions.ImageName != nil {
+			key = *importOptions.ImageName
+		}
+		// the object is deleted once it's imported
+		if ociUploadOptions.ParExpiryHours != nil || ociUploadOptions.ObjectNamePrefix != nil || ociUploadOptions.StorageTier != nil {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+	} else {
+		targetOptions := &target.OCIObjectStorageTargetOptions{}
+		if ociUploadOptions.ParExpiryHours != nil {
+			if *ociUploadOptions.ParExpiryHours < 1 || *ociUploadOptions.ParExpiryHours > 8760 {
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+			targetOptions.PARExpiryHours = *ociUploadOptions.ParExpiryHours
+		}
+		if prefix := ociUploadOptions.ObjectNamePrefix; prefix != nil {
+			if strings.HasPrefix(*prefix, "/") || strings.Contains(*prefix, "..") {
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+			targetOptions.ObjectNamePrefix = *prefix
+		}
+		if tier := ociUploadOptions.StorageTier; tier != nil {
+			switch *tier {
+			case OCIUploadOptionsStorageTierStandard, OCIUploadOptionsStorageTierInfrequentAccess:
+				targetOptions.StorageTier = string(*tier)
+			default:
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+		}
+		t = target.NewOCIObjectStorageTarget(targetOptions)
+	}
 	t.ImageName = key
 	t.OsbuildArtifact.ExportFilename = imageType.Filename()
 	return t, nil
@@ -249,6 +825,441 @@ func newPulpOSTreeTarget(options UploadOptions, imageType distro.ImageType) (*ta
 	return t, nil
 }
 
+func newPulpFileTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var pulpUploadOptions PulpFileUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &pulpUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+	if pulpUploadOptions.Repository == "" {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+
+	targetOptions := &target.PulpFileTargetOptions{
+		Repository: pulpUploadOptions.Repository,
+	}
+	if pulpUploadOptions.ServerAddress != nil {
+		targetOptions.ServerAddress = *pulpUploadOptions.ServerAddress
+	}
+	if pulpUploadOptions.Basepath != nil {
+		targetOptions.BasePath = *pulpUploadOptions.Basepath
+	}
+
+	t := target.NewPulpFileTarget(targetOptions)
+	if pulpUploadOptions.Filename != nil {
+		if *pulpUploadOptions.Filename == "" || strings.Contains(*pulpUploadOptions.Filename, "/") {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		t.ImageName = *pulpUploadOptions.Filename
+	} else {
+		t.ImageName = fmt.Sprintf("composer-api-%s-%s", uuid.New().String(), imageType.Filename())
+	}
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+// names of the libvirt domains, they end up in the domain XML
+var libvirtDomainNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,63}$`)
+
+func newLibvirtTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var libvirtUploadOptions LibvirtUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &libvirtUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	targetOptions := &target.LibvirtTargetOptions{
+		Pool: "default",
+		Arch: imageType.Arch().Name(),
+	}
+	if libvirtUploadOptions.Uri != nil {
+		targetOptions.URI = *libvirtUploadOptions.Uri
+	}
+	if libvirtUploadOptions.Pool != nil {
+		targetOptions.Pool = *libvirtUploadOptions.Pool
+	}
+	if domain := libvirtUploadOptions.Domain; domain != nil {
+		if !libvirtDomainNameRegex.MatchString(domain.Name) {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		targetOptions.DomainName = domain.Name
+		targetOptions.MemoryMiB = 2048
+		targetOptions.VCPUs = 2
+		if domain.Persistent != nil {
+			targetOptions.Persistent = *domain.Persistent
+		}
+		if domain.MemoryMib != nil {
+			if *domain.MemoryMib < 256 {
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+			targetOptions.MemoryMiB = *domain.MemoryMib
+		}
+		if domain.Vcpus != nil {
+			if *domain.Vcpus < 1 {
+				return nil, HTTPError(ErrorInvalidUploadTarget)
+			}
+			targetOptions.VCPUs = *domain.Vcpus
+		}
+	}
+
+	t := target.NewLibvirtTarget(targetOptions)
+	if libvirtUploadOptions.VolumeName != nil {
+		if *libvirtUploadOptions.VolumeName == "" || strings.Contains(*libvirtUploadOptions.VolumeName, "/") {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		t.ImageName = *libvirtUploadOptions.VolumeName
+	} else {
+		// the volume holds the extracted image
+		t.ImageName = fmt.Sprintf("composer-api-%s-%s", uuid.New().String(), strings.TrimSuffix(imageType.Filename(), ".xz"))
+	}
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+func newRBDTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var rbdUploadOptions RBDUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &rbdUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	// rbd separates the pool, namespace and image name by slashes
+	if rbdUploadOptions.Pool == "" || strings.Contains(rbdUploadOptions.Pool, "/") {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+	targetOptions := &target.RBDTargetOptions{
+		Pool: rbdUploadOptions.Pool,
+	}
+	if rbdUploadOptions.Namespace != nil {
+		if strings.Contains(*rbdUploadOptions.Namespace, "/") {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		targetOptions.Namespace = *rbdUploadOptions.Namespace
+	}
+
+	t := target.NewRBDTarget(targetOptions)
+	if rbdUploadOptions.ImageName != nil {
+		if *rbdUploadOptions.ImageName == "" || strings.Contains(*rbdUploadOptions.ImageName, "/") {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		t.ImageName = *rbdUploadOptions.ImageName
+	} else {
+		t.ImageName = fmt.Sprintf("composer-api-%s", uuid.New().String())
+	}
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+func newMockTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var mockUploadOptions MockUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &mockUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	targetOptions := &target.MockTargetOptions{}
+	if mockUploadOptions.Latency != nil {
+		if *mockUploadOptions.Latency < 0 {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		latency := uint64(*mockUploadOptions.Latency)
+		targetOptions.Latency = &latency
+	}
+	if mockUploadOptions.FailureRate != nil {
+		if *mockUploadOptions.FailureRate < 0 || *mockUploadOptions.FailureRate > 1 {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		failureRate := float64(*mockUploadOptions.FailureRate)
+		targetOptions.FailureRate = &failureRate
+	}
+
+	t := target.NewMockTarget(targetOptions)
+	t.ImageName = fmt.Sprintf("composer-api-%s", uuid.New().String())
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+func newHTTPTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var httpUploadOptions HTTPUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &httpUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	u, err := url.Parse(httpUploadOptions.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+
+	targetOptions := &target.HTTPTargetOptions{
+		URL: httpUploadOptions.Url,
+	}
+	if httpUploadOptions.Headers != nil {
+		targetOptions.Headers = httpUploadOptions.Headers.AdditionalProperties
+	}
+	if httpUploadOptions.Username != nil {
+		targetOptions.Username = *httpUploadOptions.Username
+	}
+	if httpUploadOptions.Password != nil {
+		targetOptions.Password = *httpUploadOptions.Password
+	}
+	if httpUploadOptions.Webdav != nil {
+		targetOptions.WebDAV = *httpUploadOptions.Webdav
+	}
+
+	t := target.NewHTTPTarget(targetOptions)
+	if httpUploadOptions.Filename != nil {
+		if *httpUploadOptions.Filename == "" || strings.Contains(*httpUploadOptions.Filename, "/") {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		t.ImageName = *httpUploadOptions.Filename
+	} else {
+		t.ImageName = fmt.Sprintf("composer-api-%s-%s", uuid.New().String(), imageType.Filename())
+	}
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+// netbootPipeline is the pipeline whose tree has the kernel and the initrd of
+// package based disk images.
+const netbootPipeline = "os"
+
+func newBareMetalTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var bareMetalUploadOptions BareMetalUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &bareMetalUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	u, err := url.Parse(bareMetalUploadOptions.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+
+	targetOptions := &target.BareMetalTargetOptions{
+		URL: bareMetalUploadOptions.Url,
+	}
+	if bareMetalUploadOptions.Headers != nil {
+		targetOptions.Headers = bareMetalUploadOptions.Headers.AdditionalProperties
+	}
+	if bareMetalUploadOptions.Username != nil {
+		targetOptions.Username = *bareMetalUploadOptions.Username
+	}
+	if bareMetalUploadOptions.Password != nil {
+		targetOptions.Password = *bareMetalUploadOptions.Password
+	}
+	if bareMetalUploadOptions.Webdav != nil {
+		targetOptions.WebDAV = *bareMetalUploadOptions.Webdav
+	}
+	if bareMetalUploadOptions.Netboot != nil && *bareMetalUploadOptions.Netboot {
+		// the kernel and initrd are taken from the tree of the OS, which
+		// only package based images have
+		supported := false
+		for _, pipeline := range imageType.PayloadPipelines() {
+			if pipeline == netbootPipeline {
+				supported = true
+			}
+		}
+		if !supported {
+			return nil, HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("netboot isn't supported for image type %s", imageType.Name()))
+		}
+		targetOptions.NetbootExport = netbootPipeline
+		if bareMetalUploadOptions.KernelArgs != nil {
+			targetOptions.KernelArgs = *bareMetalUploadOptions.KernelArgs
+		}
+	} else if bareMetalUploadOptions.KernelArgs != nil {
+		return nil, HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("kernel_args can only be set for netboot"))
+	}
+
+	t := target.NewBareMetalTarget(targetOptions)
+	if bareMetalUploadOptions.Name != nil {
+		if *bareMetalUploadOptions.Name == "" || strings.Contains(*bareMetalUploadOptions.Name, "/") || *bareMetalUploadOptions.Name == ".." {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		t.ImageName = *bareMetalUploadOptions.Name
+	} else {
+		t.ImageName = fmt.Sprintf("composer-api-%s", uuid.New().String())
+	}
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+func newVSphereTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var vsphereUploadOptions VSphereUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &vsphereUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	targetOptions := &target.VMWareTargetOptions{
+		Host:     vsphereUploadOptions.Host,
+		Username: vsphereUploadOptions.Username,
+		Password: vsphereUploadOptions.Password,
+	}
+	if vsphereUploadOptions.Datacenter != nil {
+		targetOptions.Datacenter = *vsphereUploadOptions.Datacenter
+	}
+	if vsphereUploadOptions.Cluster != nil {
+		targetOptions.Cluster = *vsphereUploadOptions.Cluster
+	}
+	if vsphereUploadOptions.Datastore != nil {
+		targetOptions.Datastore = *vsphereUploadOptions.Datastore
+	}
+	if vsphereUploadOptions.Folder != nil {
+		targetOptions.Folder = *vsphereUploadOptions.Folder
+	}
+	if vsphereUploadOptions.ContentLibrary != nil {
+		targetOptions.ContentLibrary = *vsphereUploadOptions.ContentLibrary
+	}
+
+	if targetOptions.Host == "" || targetOptions.Username == "" {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+	// content library items aren't stored in the datastore of a cluster
+	if targetOptions.ContentLibrary == "" &&
+		(targetOptions.Datacenter == "" || targetOptions.Cluster == "" || targetOptions.Datastore == "") {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+
+	t := target.NewVMWareTarget(targetOptions)
+	if vsphereUploadOptions.ImageName != nil {
+		t.ImageName = *vsphereUploadOptions.ImageName
+	} else {
+		t.ImageName = fmt.Sprintf("composer-api-%s", uuid.New().String())
+	}
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+// Hetzner Cloud server types the images are written with by default, the
+// smallest ones of the architecture.
+var defaultHetznerServerTypes = map[string]string{
+	"x86_64":  "cx22",
+	"aarch64": "cax11",
+}
+
+func newHetznerTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var hetznerUploadOptions HetznerUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &hetznerUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	targetOptions := &target.HetznerTargetOptions{
+		ServerType: defaultHetznerServerTypes[imageType.Arch().Name()],
+	}
+	if hetznerUploadOptions.Location != nil {
+		targetOptions.Location = *hetznerUploadOptions.Location
+	}
+	if hetznerUploadOptions.Datacenter != nil {
+		if targetOptions.Location != "" {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		targetOptions.Datacenter = *hetznerUploadOptions.Datacenter
+	}
+	if hetznerUploadOptions.ServerType != nil {
+		targetOptions.ServerType = *hetznerUploadOptions.ServerType
+	}
+	if targetOptions.ServerType == "" {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+	if hetznerUploadOptions.Labels != nil {
+		targetOptions.Labels = hetznerUploadOptions.Labels.AdditionalProperties
+	}
+
+	t := target.NewHetznerTarget(targetOptions)
+	if hetznerUploadOptions.ImageName != nil {
+		t.ImageName = *hetznerUploadOptions.ImageName
+	} else {
+		t.ImageName = fmt.Sprintf("composer-api-%s", uuid.New().String())
+	}
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+func newVagrantCloudTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var vagrantCloudUploadOptions VagrantCloudUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &vagrantCloudUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	parts := strings.Split(vagrantCloudUploadOptions.Box, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, HTTPErrorWithInternal(ErrorInvalidUploadTarget, fmt.Errorf("box %s isn't in the user/name format", vagrantCloudUploadOptions.Box))
+	}
+	if vagrantCloudUploadOptions.Version == "" || vagrantCloudUploadOptions.Token == "" {
+		return nil, HTTPError(ErrorInvalidUploadTarget)
+	}
+
+	targetOptions := &target.VagrantCloudTargetOptions{
+		Token:   vagrantCloudUploadOptions.Token,
+		Box:     vagrantCloudUploadOptions.Box,
+		Version: vagrantCloudUploadOptions.Version,
+		Release: vagrantCloudUploadOptions.Release != nil && *vagrantCloudUploadOptions.Release,
+	}
+	if vagrantCloudUploadOptions.Url != nil {
+		u, err := url.Parse(*vagrantCloudUploadOptions.Url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, HTTPError(ErrorInvalidUploadTarget)
+		}
+		targetOptions.URL = *vagrantCloudUploadOptions.Url
+	}
+
+	t := target.NewVagrantCloudTarget(targetOptions)
+	t.ImageName = fmt.Sprintf("%s/%s", targetOptions.Box, targetOptions.Version)
+	t.OsbuildArtifact.ExportFilename = imageType.Filename()
+	return t, nil
+}
+
+// vagrantProvider returns the provider of the box the image type is
+// packaged into, or an empty string for image types which aren't boxes.
+func vagrantProvider(it ImageTypes) string {
+	switch it {
+	case ImageTypesVagrantLibvirt:
+		return "libvirt"
+	case ImageTypesVagrantVirtualbox:
+		return "virtualbox"
+	}
+	return ""
+}
+
 // Returns the name of the default target for a given image type name or error
 // if the image type name is unknown.
 func getDefaultTarget(imageType ImageTypes) (UploadTypes, error) {
@@ -283,6 +1294,20 @@ func getDefaultTarget(imageType ImageTypes) (UploadTypes, error) {
 	case ImageTypesIotCommit:
 		fallthrough
 	case ImageTypesIotRawImage:
+		fallthrough
+	case ImageTypesEdgeRawImage:
+		fallthrough
+	case ImageTypesEdgeSimplifiedInstaller:
+		fallthrough
+	case ImageTypesIotSimplifiedInstaller:
+		fallthrough
+	case ImageTypesMinimalRaw:
+		fallthrough
+	case ImageTypesVagrantLibvirt:
+		fallthrough
+	case ImageTypesVagrantVirtualbox:
+		fallthrough
+	case ImageTypesRaspberryPi:
 		return UploadTypesAwsS3, nil
 
 	case ImageTypesEdgeContainer:
@@ -321,17 +1346,24 @@ func targetSupportMap() map[UploadTypes]map[ImageTypes]bool {
 			ImageTypesAwsSapRhui: true,
 		},
 		UploadTypesAwsS3: {
-			ImageTypesGuestImage:     true,
-			ImageTypesVsphere:        true,
-			ImageTypesVsphereOva:     true,
-			ImageTypesWsl:            true,
-			ImageTypesImageInstaller: true,
-			ImageTypesEdgeInstaller:  true,
-			ImageTypesIotInstaller:   true,
-			ImageTypesLiveInstaller:  true,
-			ImageTypesEdgeCommit:     true,
-			ImageTypesIotCommit:      true,
-			ImageTypesIotRawImage:    true,
+			ImageTypesGuestImage:              true,
+			ImageTypesVsphere:                 true,
+			ImageTypesVsphereOva:              true,
+			ImageTypesWsl:                     true,
+			ImageTypesImageInstaller:          true,
+			ImageTypesEdgeInstaller:           true,
+			ImageTypesIotInstaller:            true,
+			ImageTypesLiveInstaller:           true,
+			ImageTypesEdgeCommit:              true,
+			ImageTypesIotCommit:               true,
+			ImageTypesIotRawImage:             true,
+			ImageTypesEdgeRawImage:            true,
+			ImageTypesEdgeSimplifiedInstaller: true,
+			ImageTypesIotSimplifiedInstaller:  true,
+			ImageTypesMinimalRaw:              true,
+			ImageTypesVagrantLibvirt:          true,
+			ImageTypesVagrantVirtualbox:       true,
+			ImageTypesRaspberryPi:             true,
 		},
 		UploadTypesContainer: {
 			ImageTypesEdgeContainer: true,
@@ -354,6 +1386,85 @@ func targetSupportMap() map[UploadTypes]map[ImageTypes]bool {
 			ImageTypesEdgeCommit: true,
 			ImageTypesIotCommit:  true,
 		},
+		UploadTypesPulpFile: {
+			ImageTypesGuestImage:              true,
+			ImageTypesVsphere:                 true,
+			ImageTypesVsphereOva:              true,
+			ImageTypesWsl:                     true,
+			ImageTypesImageInstaller:          true,
+			ImageTypesEdgeInstaller:           true,
+			ImageTypesIotInstaller:            true,
+			ImageTypesLiveInstaller:           true,
+			ImageTypesIotRawImage:             true,
+			ImageTypesEdgeRawImage:            true,
+			ImageTypesEdgeSimplifiedInstaller: true,
+			ImageTypesIotSimplifiedInstaller:  true,
+			ImageTypesMinimalRaw:              true,
+			ImageTypesVagrantLibvirt:          true,
+			ImageTypesVagrantVirtualbox:       true,
+			ImageTypesRaspberryPi:             true,
+		},
+		UploadTypesOras: {
+			ImageTypesGuestImage:   true,
+			ImageTypesIotRawImage:  true,
+			ImageTypesEdgeRawImage: true,
+			ImageTypesMinimalRaw:   true,
+			ImageTypesVsphere:      true,
+			ImageTypesVsphereOva:   true,
+			ImageTypesRaspberryPi:  true,
+		},
+		UploadTypesLibvirt: {
+			ImageTypesGuestImage:   true,
+			ImageTypesIotRawImage:  true,
+			ImageTypesEdgeRawImage: true,
+			ImageTypesMinimalRaw:   true,
+		},
+		UploadTypesRbd: {
+			ImageTypesGuestImage:   true,
+			ImageTypesIotRawImage:  true,
+			ImageTypesEdgeRawImage: true,
+			ImageTypesMinimalRaw:   true,
+		},
+		UploadTypesHttp: {
+			ImageTypesGuestImage:              true,
+			ImageTypesVsphere:                 true,
+			ImageTypesVsphereOva:              true,
+			ImageTypesWsl:                     true,
+			ImageTypesImageInstaller:          true,
+			ImageTypesEdgeInstaller:           true,
+			ImageTypesIotInstaller:            true,
+			ImageTypesLiveInstaller:           true,
+			ImageTypesEdgeCommit:              true,
+			ImageTypesIotCommit:               true,
+			ImageTypesIotRawImage:             true,
+			ImageTypesEdgeRawImage:            true,
+			ImageTypesEdgeSimplifiedInstaller: true,
+			ImageTypesIotSimplifiedInstaller:  true,
+			ImageTypesMinimalRaw:              true,
+			ImageTypesVagrantLibvirt:          true,
+			ImageTypesVagrantVirtualbox:       true,
+			ImageTypesRaspberryPi:             true,
+		},
+		UploadTypesHetzner: {
+			ImageTypesGuestImage:   true,
+			ImageTypesIotRawImage:  true,
+			ImageTypesEdgeRawImage: true,
+			ImageTypesMinimalRaw:   true,
+		},
+		UploadTypesBaremetal: {
+			ImageTypesGuestImage:   true,
+			ImageTypesIotRawImage:  true,
+			ImageTypesEdgeRawImage: true,
+			ImageTypesMinimalRaw:   true,
+		},
+		UploadTypesVsphere: {
+			ImageTypesVsphere:    true,
+			ImageTypesVsphereOva: true,
+		},
+		UploadTypesVagrantCloud: {
+			ImageTypesVagrantLibvirt:    true,
+			ImageTypesVagrantVirtualbox: true,
+		},
 	}
 }
 
@@ -364,8 +1475,9 @@ func (ir *ImageRequest) GetTargets(request *ComposeRequest, imageType distro.Ima
 	targets := make([]*target.Target, 0)
 	if ir.UploadTargets != nil {
 		for _, ut := range *ir.UploadTargets {
-			// check if the target type is valid for the image type
-			if !tsm[ut.Type][ir.ImageType] {
+			// check if the target type is valid for the image type, the
+			// mock target accepts any image
+			if ut.Type != UploadTypesMock && !tsm[ut.Type][ir.ImageType] {
 				return nil, HTTPError(ErrorInvalidUploadTarget)
 			}
 			trgt, err := getTarget(ut.Type, ut.UploadOptions, request, imageType)
@@ -416,6 +1528,36 @@ func getTarget(targetType UploadTypes, options UploadOptions, request *ComposeRe
 	case UploadTypesPulpOstree:
 		irTarget, err = newPulpOSTreeTarget(options, imageType)
 
+	case UploadTypesPulpFile:
+		irTarget, err = newPulpFileTarget(options, imageType)
+
+	case UploadTypesOras:
+		irTarget, err = newORASTarget(options, request, imageType)
+
+	case UploadTypesLibvirt:
+		irTarget, err = newLibvirtTarget(options, imageType)
+
+	case UploadTypesRbd:
+		irTarget, err = newRBDTarget(options, imageType)
+
+	case UploadTypesMock:
+		irTarget, err = newMockTarget(options, imageType)
+
+	case UploadTypesHetzner:
+		irTarget, err = newHetznerTarget(options, imageType)
+
+	case UploadTypesHttp:
+		irTarget, err = newHTTPTarget(options, imageType)
+
+	case UploadTypesVsphere:
+		irTarget, err = newVSphereTarget(options, imageType)
+
+	case UploadTypesBaremetal:
+		irTarget, err = newBareMetalTarget(options, imageType)
+
+	case UploadTypesVagrantCloud:
+		irTarget, err = newVagrantCloudTarget(options, imageType)
+
 	default:
 		return nil, HTTPError(ErrorInvalidUploadTarget)
 	}
@@ -428,6 +1570,49 @@ func getTarget(targetType UploadTypes, options UploadOptions, request *ComposeRe
 
 }
 
+// GetWSLOptions returns the WSL options of the image when included in the
+// request. They are applied to the archive of the root file system of the
+// RHEL wsl images, the Fedora one is a container archive.
+func (ir *ImageRequest) GetWSLOptions(request *ComposeRequest, imageType distro.ImageType) (*target.WSLOptions, error) {
+	if ir.Wsl == nil {
+		return nil, nil
+	}
+	if ir.ImageType != ImageTypesWsl || !strings.HasSuffix(imageType.Filename(), ".tar.gz") {
+		return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("wsl options aren't supported for image type %s", imageType.Name()))
+	}
+
+	options := &target.WSLOptions{
+		Systemd: ir.Wsl.Systemd == nil || *ir.Wsl.Systemd,
+		Bundle:  ir.Wsl.Bundle != nil && *ir.Wsl.Bundle,
+	}
+	if ir.Wsl.DefaultUser != nil && *ir.Wsl.DefaultUser != "root" {
+		found := false
+		if request.Customizations != nil && request.Customizations.Users != nil {
+			for _, user := range *request.Customizations.Users {
+				if user.Name == *ir.Wsl.DefaultUser {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("default user %s isn't one of the users of the customizations", *ir.Wsl.DefaultUser))
+		}
+	}
+	if ir.Wsl.DefaultUser != nil {
+		options.DefaultUser = *ir.Wsl.DefaultUser
+	}
+	if ir.Wsl.WslConf != nil {
+		options.Conf = *ir.Wsl.WslConf
+	}
+	if ir.Wsl.DistributionName != nil {
+		if !options.Bundle {
+			return nil, HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("distribution_name can only be set for bundles"))
+		}
+		options.DistributionName = *ir.Wsl.DistributionName
+	}
+	return options, nil
+}
+
 // GetOSTreeOptions returns the image ostree options when included in the request
 // or nil if they are not present.
 func (ir *ImageRequest) GetOSTreeOptions() (ostreeOptions *ostree.ImageOptions, err error) {
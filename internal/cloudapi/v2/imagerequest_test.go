@@ -1,8 +1,14 @@
 package v2
 
 import (
+	"encoding/base64"
+	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/labstack/echo/v4"
+	"github.com/osbuild/images/pkg/distro"
+	"github.com/osbuild/images/pkg/distro/fedora"
 	"github.com/osbuild/images/pkg/distro/rhel9"
 	"github.com/osbuild/images/pkg/distro/test_distro"
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
@@ -262,3 +268,1112 @@ func TestGetTargets(t *testing.T) {
 		})
 	}
 }
+
+func TestNewContainerTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+	cr := &ComposeRequest{
+		Distribution: r9.Name(),
+	}
+
+	unsigned, err := newContainerTarget(map[string]interface{}{"name": "edge", "tag": "latest"}, cr, it)
+	require.NoError(t, err)
+	require.Equal(t, "edge:latest", unsigned.ImageName)
+	require.False(t, unsigned.Options.(*target.ContainerTargetOptions).Sign)
+
+	signed, err := newContainerTarget(map[string]interface{}{"name": "edge", "sign": true}, cr, it)
+	require.NoError(t, err)
+	require.True(t, signed.Options.(*target.ContainerTargetOptions).Sign)
+
+	tagged, err := newContainerTarget(map[string]interface{}{
+		"name":     "quay.io/myaccount/edge",
+		"tag":      "1.0",
+		"username": "robot",
+		"password": "token",
+		"tags": []interface{}{
+			map[string]interface{}{"tag": "latest"},
+			map[string]interface{}{"tag": "stable", "name": "quay.io/myaccount/edge-stable"},
+		},
+	}, cr, it)
+	require.NoError(t, err)
+	require.Equal(t, "quay.io/myaccount/edge:1.0", tagged.ImageName)
+	require.Equal(t, &target.ContainerTargetOptions{
+		Username: "robot",
+		Password: "token",
+		AdditionalReferences: []string{
+			"quay.io/myaccount/edge:latest",
+			"quay.io/myaccount/edge-stable:stable",
+		},
+	}, tagged.Options)
+
+	_, err = newContainerTarget(map[string]interface{}{
+		"name": "edge",
+		"tags": []interface{}{map[string]interface{}{"tag": ""}},
+	}, cr, it)
+	require.Error(t, err)
+}
+
+func TestNewAzureTargetGallery(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("vhd")
+	require.NoError(t, err)
+
+	options := map[string]interface{}{
+		"tenant_id":       "5c7ef5b6-1c3f-4da0-a622-0b060239d7d7",
+		"subscription_id": "4e5d8b2c-ab24-4413-90c5-612306e809e2",
+		"resource_group":  "ToucanResourceGroup",
+	}
+	plain, err := newAzureTarget(options, it)
+	require.NoError(t, err)
+	require.Nil(t, plain.Options.(*target.AzureImageTargetOptions).Gallery)
+
+	options["gallery"] = map[string]interface{}{
+		"gallery_name":         "ToucanGallery",
+		"image_definition":     "toucan",
+		"image_version":        "1.0.0",
+		"storage_account_type": "Standard_ZRS",
+		"target_regions": []interface{}{
+			map[string]interface{}{"name": "northeurope", "replica_count": 2},
+		},
+	}
+	published, err := newAzureTarget(options, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.AzureGalleryOptions{
+		Name:               "ToucanGallery",
+		ImageDefinition:    "toucan",
+		Version:            "1.0.0",
+		StorageAccountType: "Standard_ZRS",
+		TargetRegions: []target.AzureGalleryTargetRegion{
+			{Name: "northeurope", ReplicaCount: 2},
+		},
+	}, published.Options.(*target.AzureImageTargetOptions).Gallery)
+}
+
+func TestNewAzureTargetBlobOnly(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("vhd")
+	require.NoError(t, err)
+
+	options := map[string]interface{}{
+		"tenant_id":        "5c7ef5b6-1c3f-4da0-a622-0b060239d7d7",
+		"subscription_id":  "4e5d8b2c-ab24-4413-90c5-612306e809e2",
+		"resource_group":   "ToucanResourceGroup",
+		"blob_only":        true,
+		"sas_expiry_hours": 24,
+	}
+	blob, err := newAzureTarget(options, it)
+	require.NoError(t, err)
+	require.True(t, blob.Options.(*target.AzureImageTargetOptions).BlobOnly)
+	require.Equal(t, 24, blob.Options.(*target.AzureImageTargetOptions).SASExpiryHours)
+
+	// no image is registered, so it can't be published either
+	options["gallery"] = map[string]interface{}{
+		"gallery_name":     "ToucanGallery",
+		"image_definition": "toucan",
+		"image_version":    "1.0.0",
+	}
+	_, err = newAzureTarget(options, it)
+	require.Error(t, err)
+
+	delete(options, "gallery")
+	options["blob_only"] = false
+	_, err = newAzureTarget(options, it)
+	require.Error(t, err)
+}
+
+func TestNewHetznerTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	x86, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	x86Qcow2, err := x86.GetImageType("qcow2")
+	require.NoError(t, err)
+	aarch64, err := r9.GetArch("aarch64")
+	require.NoError(t, err)
+	aarch64Qcow2, err := aarch64.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	x86Target, err := newHetznerTarget(map[string]interface{}{"location": "fsn1"}, x86Qcow2)
+	require.NoError(t, err)
+	require.Equal(t, &target.HetznerTargetOptions{Location: "fsn1", ServerType: "cx22"}, x86Target.Options)
+	require.Contains(t, x86Target.ImageName, "composer-api-")
+
+	aarch64Target, err := newHetznerTarget(map[string]interface{}{
+		"datacenter": "hel1-dc2",
+		"image_name": "my-image",
+		"labels":     map[string]interface{}{"os": "rhel"},
+	}, aarch64Qcow2)
+	require.NoError(t, err)
+	require.Equal(t, &target.HetznerTargetOptions{
+		Datacenter: "hel1-dc2",
+		ServerType: "cax11",
+		Labels:     map[string]string{"os": "rhel"},
+	}, aarch64Target.Options)
+	require.Equal(t, "my-image", aarch64Target.ImageName)
+
+	custom, err := newHetznerTarget(map[string]interface{}{"server_type": "cx32"}, x86Qcow2)
+	require.NoError(t, err)
+	require.Equal(t, "cx32", custom.Options.(*target.HetznerTargetOptions).ServerType)
+
+	_, err = newHetznerTarget(map[string]interface{}{"location": "fsn1", "datacenter": "hel1-dc2"}, x86Qcow2)
+	require.Error(t, err)
+}
+
+func TestNewOCITarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("oci")
+	require.NoError(t, err)
+
+	objectStorage, err := newOCITarget(map[string]interface{}{}, it)
+	require.NoError(t, err)
+	require.Equal(t, target.TargetNameOCIObjectStorage, objectStorage.Name)
+	require.Equal(t, it.Filename(), objectStorage.OsbuildArtifact.ExportFilename)
+
+	imported, err := newOCITarget(map[string]interface{}{
+		"import": map[string]interface{}{
+			"image_name":        "my-image",
+			"launch_mode":       "NATIVE",
+			"compatible_shapes": []interface{}{"VM.Standard.E4.Flex"},
+		},
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, target.TargetNameOCI, imported.Name)
+	require.Equal(t, "my-image", imported.ImageName)
+	require.Equal(t, &target.OCITargetOptions{
+		LaunchMode: "NATIVE",
+		Shapes:     []string{"VM.Standard.E4.Flex"},
+	}, imported.Options)
+
+	defaults, err := newOCITarget(map[string]interface{}{"import": map[string]interface{}{}}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.OCITargetOptions{}, defaults.Options)
+	require.Contains(t, defaults.ImageName, "composer-api-")
+
+	_, err = newOCITarget(map[string]interface{}{"import": map[string]interface{}{"launch_mode": "CUSTOM"}}, it)
+	require.Error(t, err)
+
+	objectStorage, err = newOCITarget(map[string]interface{}{
+		"par_expiry_hours":   72,
+		"object_name_prefix": "images/rhel-9/",
+		"storage_tier":       "InfrequentAccess",
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.OCIObjectStorageTargetOptions{
+		PARExpiryHours:   72,
+		ObjectNamePrefix: "images/rhel-9/",
+		StorageTier:      "InfrequentAccess",
+	}, objectStorage.Options)
+
+	for _, options := range []map[string]interface{}{
+		{"par_expiry_hours": 0},
+		{"object_name_prefix": "/images/"},
+		{"storage_tier": "Archive"},
+		// the imported object is deleted
+		{"import": map[string]interface{}{}, "par_expiry_hours": 72},
+	} {
+		_, err = newOCITarget(options, it)
+		require.Error(t, err)
+	}
+}
+
+func TestNewAWSS3TargetGenericS3(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	aws, err := newAWSS3Target(map[string]interface{}{"region": "eu-west-1"}, it)
+	require.NoError(t, err)
+	require.Empty(t, aws.Options.(*target.AWSS3TargetOptions).Endpoint)
+
+	generic, err := newAWSS3Target(map[string]interface{}{
+		"region":            "us-east-1",
+		"endpoint":          "https://minio.example.com:9000",
+		"bucket":            "images",
+		"path_style":        false,
+		"ca_bundle":         "-----BEGIN CERTIFICATE-----",
+		"access_key_id":     "key-id",
+		"secret_access_key": "secret",
+		"session_token":     "session",
+	}, it)
+	require.NoError(t, err)
+	options := generic.Options.(*target.AWSS3TargetOptions)
+	require.Equal(t, "key-id", options.AccessKeyID)
+	require.Equal(t, "secret", options.SecretAccessKey)
+	require.Equal(t, "session", options.SessionToken)
+	require.Equal(t, "https://minio.example.com:9000", options.Endpoint)
+	require.Equal(t, "us-east-1", options.Region)
+	require.Equal(t, "images", options.Bucket)
+	require.True(t, options.VirtualHostedStyle)
+	require.Equal(t, "-----BEGIN CERTIFICATE-----", options.CABundleData)
+
+	// the credentials of the worker aren't sent to endpoints of requests
+	_, err = newAWSS3Target(map[string]interface{}{
+		"region":   "us-east-1",
+		"endpoint": "https://minio.example.com:9000",
+		"bucket":   "images",
+	}, it)
+	require.Error(t, err)
+	_, err = newAWSS3Target(map[string]interface{}{
+		"region":        "us-east-1",
+		"endpoint":      "https://minio.example.com:9000",
+		"access_key_id": "key-id",
+	}, it)
+	require.Error(t, err)
+
+	// path style, the CA bundle and the credentials only apply to
+	// S3-compatible endpoints
+	_, err = newAWSS3Target(map[string]interface{}{"region": "eu-west-1", "path_style": true}, it)
+	require.Error(t, err)
+	_, err = newAWSS3Target(map[string]interface{}{"region": "eu-west-1", "ca_bundle": "pem"}, it)
+	require.Error(t, err)
+	_, err = newAWSS3Target(map[string]interface{}{"region": "eu-west-1", "secret_access_key": "secret"}, it)
+	require.Error(t, err)
+}
+
+func TestNewAWSS3TargetURLOptions(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	tgt, err := newAWSS3Target(map[string]interface{}{
+		"region":            "eu-west-1",
+		"url_expiry_hours":  24,
+		"acl":               "bucket-owner-full-control",
+		"return_object_key": true,
+	}, it)
+	require.NoError(t, err)
+	options := tgt.Options.(*target.AWSS3TargetOptions)
+	require.False(t, options.Public)
+	require.Equal(t, 24, options.URLExpiryHours)
+	require.Equal(t, "bucket-owner-full-control", options.ACL)
+	require.True(t, options.ReturnObjectKey)
+
+	tgt, err = newAWSS3Target(map[string]interface{}{"region": "eu-west-1", "acl": "public-read"}, it)
+	require.NoError(t, err)
+	options = tgt.Options.(*target.AWSS3TargetOptions)
+	require.True(t, options.Public)
+	require.Empty(t, options.ACL)
+
+	tgt, err = newAWSS3Target(map[string]interface{}{"region": "eu-west-1", "acl": "private"}, it)
+	require.NoError(t, err)
+	require.Empty(t, tgt.Options.(*target.AWSS3TargetOptions).ACL)
+
+	for _, uploadOptions := range []map[string]interface{}{
+		{"region": "eu-west-1", "public": true, "url_expiry_hours": 24},
+		{"region": "eu-west-1", "acl": "public-read", "url_expiry_hours": 24},
+		{"region": "eu-west-1", "public": true, "acl": "private"},
+		{"region": "eu-west-1", "url_expiry_hours": 169},
+		{"region": "eu-west-1", "url_expiry_hours": 0},
+		{"region": "eu-west-1", "acl": "authenticated-read"},
+	} {
+		_, err = newAWSS3Target(uploadOptions, it)
+		require.Error(t, err)
+	}
+}
+
+func TestNewHTTPTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	plain, err := newHTTPTarget(map[string]interface{}{"url": "https://nexus.example.com/repository/images"}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.HTTPTargetOptions{URL: "https://nexus.example.com/repository/images"}, plain.Options)
+	require.Contains(t, plain.ImageName, "composer-api-")
+	require.True(t, strings.HasSuffix(plain.ImageName, "-"+it.Filename()))
+
+	webdav, err := newHTTPTarget(map[string]interface{}{
+		"url":      "https://dav.example.com/images/",
+		"filename": "my-image.qcow2",
+		"headers":  map[string]interface{}{"X-Api-Key": "key"},
+		"username": "user",
+		"password": "secret",
+		"webdav":   true,
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, "my-image.qcow2", webdav.ImageName)
+	require.Equal(t, &target.HTTPTargetOptions{
+		URL:      "https://dav.example.com/images/",
+		Headers:  map[string]string{"X-Api-Key": "key"},
+		Username: "user",
+		Password: "secret",
+		WebDAV:   true,
+	}, webdav.Options)
+
+	_, err = newHTTPTarget(map[string]interface{}{"url": "ftp://example.com/images"}, it)
+	require.Error(t, err)
+	_, err = newHTTPTarget(map[string]interface{}{"url": "https://example.com/images", "filename": "../image"}, it)
+	require.Error(t, err)
+}
+
+func TestNewBareMetalTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	plain, err := newBareMetalTarget(map[string]interface{}{"url": "https://images.example.com/metal"}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.BareMetalTargetOptions{URL: "https://images.example.com/metal"}, plain.Options)
+	require.Contains(t, plain.ImageName, "composer-api-")
+	require.Equal(t, it.Filename(), plain.OsbuildArtifact.ExportFilename)
+
+	netboot, err := newBareMetalTarget(map[string]interface{}{
+		"url":         "https://dav.example.com/metal/",
+		"name":        "my-image",
+		"headers":     map[string]interface{}{"X-Api-Key": "key"},
+		"webdav":      true,
+		"netboot":     true,
+		"kernel_args": "console=ttyS1,115200n8",
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, "my-image", netboot.ImageName)
+	require.Equal(t, &target.BareMetalTargetOptions{
+		URL:           "https://dav.example.com/metal/",
+		Headers:       map[string]string{"X-Api-Key": "key"},
+		WebDAV:        true,
+		NetbootExport: "os",
+		KernelArgs:    "console=ttyS1,115200n8",
+	}, netboot.Options)
+
+	_, err = newBareMetalTarget(map[string]interface{}{"url": "ftp://example.com/metal"}, it)
+	require.Error(t, err)
+	_, err = newBareMetalTarget(map[string]interface{}{"url": "https://example.com/metal", "name": "../image"}, it)
+	require.Error(t, err)
+	_, err = newBareMetalTarget(map[string]interface{}{"url": "https://example.com/metal", "kernel_args": "quiet"}, it)
+	require.Error(t, err)
+}
+
+func TestNewVagrantCloudTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType(imageTypeFromApiImageType(ImageTypesVagrantLibvirt, arch))
+	require.NoError(t, err)
+	require.Equal(t, "libvirt", vagrantProvider(ImageTypesVagrantLibvirt))
+	require.Equal(t, "virtualbox", vagrantProvider(ImageTypesVagrantVirtualbox))
+	require.Empty(t, vagrantProvider(ImageTypesGuestImage))
+
+	vagrantCloud, err := newVagrantCloudTarget(map[string]interface{}{
+		"box":     "myaccount/rhel",
+		"version": "9.3.0",
+		"token":   "secret",
+		"release": true,
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, "myaccount/rhel/9.3.0", vagrantCloud.ImageName)
+	require.Equal(t, &target.VagrantCloudTargetOptions{
+		Token:   "secret",
+		Box:     "myaccount/rhel",
+		Version: "9.3.0",
+		Release: true,
+	}, vagrantCloud.Options)
+
+	_, err = newVagrantCloudTarget(map[string]interface{}{"box": "rhel", "version": "9.3.0", "token": "secret"}, it)
+	require.Error(t, err)
+	_, err = newVagrantCloudTarget(map[string]interface{}{"box": "myaccount/rhel", "token": "secret"}, it)
+	require.Error(t, err)
+	_, err = newVagrantCloudTarget(map[string]interface{}{"box": "myaccount/rhel", "version": "9.3.0", "token": "secret", "url": "ftp://boxes.example.com"}, it)
+	require.Error(t, err)
+}
+
+func TestNewVSphereTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("ova")
+	require.NoError(t, err)
+
+	options := map[string]interface{}{
+		"host":       "vcenter.example.com",
+		"username":   "administrator@vsphere.local",
+		"password":   "secret",
+		"datacenter": "Datacenter",
+		"cluster":    "Cluster",
+		"datastore":  "datastore1",
+		"folder":     "templates",
+	}
+	datastore, err := newVSphereTarget(options, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.VMWareTargetOptions{
+		Host:       "vcenter.example.com",
+		Username:   "administrator@vsphere.local",
+		Password:   "secret",
+		Datacenter: "Datacenter",
+		Cluster:    "Cluster",
+		Datastore:  "datastore1",
+		Folder:     "templates",
+	}, datastore.Options)
+	require.Contains(t, datastore.ImageName, "composer-api-")
+	require.Equal(t, "image.ova", datastore.OsbuildArtifact.ExportFilename)
+
+	library, err := newVSphereTarget(map[string]interface{}{
+		"host":            "vcenter.example.com",
+		"username":        "administrator@vsphere.local",
+		"password":        "secret",
+		"content_library": "images",
+		"image_name":      "my-image",
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, "images", library.Options.(*target.VMWareTargetOptions).ContentLibrary)
+	require.Equal(t, "my-image", library.ImageName)
+
+	// the datastore is needed without a content library
+	delete(options, "datastore")
+	_, err = newVSphereTarget(options, it)
+	require.Error(t, err)
+}
+
+func TestNewPulpFileTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	plain, err := newPulpFileTarget(map[string]interface{}{"repository": "images"}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.PulpFileTargetOptions{Repository: "images"}, plain.Options)
+	require.Contains(t, plain.ImageName, "composer-api-")
+	require.True(t, strings.HasSuffix(plain.ImageName, "-disk.qcow2"))
+
+	named, err := newPulpFileTarget(map[string]interface{}{
+		"repository":     "images",
+		"basepath":       "images",
+		"filename":       "my-image.qcow2",
+		"server_address": "https://pulp.example.com",
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.PulpFileTargetOptions{
+		ServerAddress: "https://pulp.example.com",
+		Repository:    "images",
+		BasePath:      "images",
+	}, named.Options)
+	require.Equal(t, "my-image.qcow2", named.ImageName)
+
+	_, err = newPulpFileTarget(map[string]interface{}{"repository": "images", "filename": "../my-image.qcow2"}, it)
+	require.Error(t, err)
+}
+
+func TestNewORASTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+	cr := &ComposeRequest{
+		Distribution: r9.Name(),
+	}
+
+	plain, err := newORASTarget(map[string]interface{}{}, cr, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.ORASTargetOptions{
+		ArtifactType: "application/vnd.osbuild.image.v1",
+		MediaType:    "application/octet-stream",
+	}, plain.Options)
+	require.True(t, strings.HasPrefix(plain.ImageName, r9.Name()+":"))
+
+	custom, err := newORASTarget(map[string]interface{}{
+		"name":          "quay.io/myaccount/rhel-guest",
+		"tag":           "9.3",
+		"artifact_type": "application/vnd.example.disk.v1",
+		"media_type":    "application/x-qemu-disk",
+		"annotations":   map[string]interface{}{"org.opencontainers.image.version": "9.3"},
+		"username":      "robot",
+		"password":      "token",
+	}, cr, it)
+	require.NoError(t, err)
+	require.Equal(t, "quay.io/myaccount/rhel-guest:9.3", custom.ImageName)
+	require.Equal(t, &target.ORASTargetOptions{
+		ArtifactType: "application/vnd.example.disk.v1",
+		MediaType:    "application/x-qemu-disk",
+		Annotations:  map[string]string{"org.opencontainers.image.version": "9.3"},
+		Username:     "robot",
+		Password:     "token",
+	}, custom.Options)
+
+	_, err = newORASTarget(map[string]interface{}{"media_type": ""}, cr, it)
+	require.Error(t, err)
+}
+
+func TestNewLibvirtTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	volume, err := newLibvirtTarget(map[string]interface{}{}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.LibvirtTargetOptions{Pool: "default", Arch: "x86_64"}, volume.Options)
+	require.True(t, strings.HasSuffix(volume.ImageName, "-disk.qcow2"))
+
+	domain, err := newLibvirtTarget(map[string]interface{}{
+		"uri":         "qemu+ssh://root@virt.example.com/system",
+		"pool":        "images",
+		"volume_name": "my-image.qcow2",
+		"domain": map[string]interface{}{
+			"name":       "my-vm",
+			"persistent": true,
+			"vcpus":      4,
+		},
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, "my-image.qcow2", domain.ImageName)
+	require.Equal(t, &target.LibvirtTargetOptions{
+		URI:        "qemu+ssh://root@virt.example.com/system",
+		Pool:       "images",
+		Arch:       "x86_64",
+		DomainName: "my-vm",
+		Persistent: true,
+		MemoryMiB:  2048,
+		VCPUs:      4,
+	}, domain.Options)
+
+	_, err = newLibvirtTarget(map[string]interface{}{"domain": map[string]interface{}{"name": "my-vm", "memory_mib": 64}}, it)
+	require.Error(t, err)
+
+	for _, name := range []string{"", "-vm", "my vm", "vm</name><devices>", strings.Repeat("a", 65)} {
+		_, err = newLibvirtTarget(map[string]interface{}{"domain": map[string]interface{}{"name": name}}, it)
+		require.Error(t, err, name)
+	}
+}
+
+func TestNewRBDTarget(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	tgt, err := newRBDTarget(map[string]interface{}{"pool": "images"}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.RBDTargetOptions{Pool: "images"}, tgt.Options)
+	require.True(t, strings.HasPrefix(tgt.ImageName, "composer-api-"))
+	require.Equal(t, "disk.qcow2", tgt.OsbuildArtifact.ExportFilename)
+
+	tgt, err = newRBDTarget(map[string]interface{}{
+		"pool":       "images",
+		"namespace":  "dev",
+		"image_name": "rhel-9",
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.RBDTargetOptions{Pool: "images", Namespace: "dev"}, tgt.Options)
+	require.Equal(t, "rhel-9", tgt.ImageName)
+
+	for _, options := range []map[string]interface{}{
+		{},
+		{"pool": "images/dev"},
+		{"pool": "images", "image_name": "dev/rhel-9"},
+	} {
+		_, err = newRBDTarget(options, it)
+		require.Error(t, err)
+	}
+}
+
+func TestNewAWSTargetKMSKey(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("ami")
+	require.NoError(t, err)
+
+	tgt, err := newAWSTarget(map[string]interface{}{
+		"region":              "eu-central-1",
+		"share_with_accounts": []string{"123456789012"},
+	}, it)
+	require.NoError(t, err)
+	require.Empty(t, tgt.Options.(*target.AWSTargetOptions).KMSKeyID)
+
+	tgt, err = newAWSTarget(map[string]interface{}{
+		"region":              "eu-central-1",
+		"share_with_accounts": []string{"123456789012"},
+		"kms_key_id":          "alias/composer",
+	}, it)
+	require.NoError(t, err)
+	require.Equal(t, "alias/composer", tgt.Options.(*target.AWSTargetOptions).KMSKeyID)
+
+	_, err = newAWSTarget(map[string]interface{}{
+		"region":     "eu-central-1",
+		"kms_key_id": "",
+	}, it)
+	require.Error(t, err)
+}
+
+func TestNewAWSTargetRegisterOptions(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("ami")
+	require.NoError(t, err)
+	require.Equal(t, distro.BOOT_HYBRID, it.BootMode())
+
+	tgt, err := newAWSTarget(map[string]interface{}{
+		"region":              "eu-central-1",
+		"share_with_accounts": []string{"123456789012"},
+	}, it)
+	require.NoError(t, err)
+	options := tgt.Options.(*target.AWSTargetOptions)
+	require.Equal(t, common.ToPtr(ec2.BootModeValuesUefiPreferred), options.BootMode)
+	require.Nil(t, options.Tags)
+	require.Nil(t, options.IMDSSupport)
+	require.Nil(t, options.ENASupport)
+	require.False(t, options.SriovNetSupport)
+
+	tgt, err = newAWSTarget(map[string]interface{}{
+		"region":              "eu-central-1",
+		"share_with_accounts": []string{"123456789012"},
+		"tags":                map[string]string{"team": "images"},
+		"boot_mode":           "uefi",
+		"imds_support":        "v2.0",
+		"ena_support":         false,
+		"sriov_net_support":   true,
+	}, it)
+	require.NoError(t, err)
+	options = tgt.Options.(*target.AWSTargetOptions)
+	require.Equal(t, common.ToPtr(ec2.BootModeValuesUefi), options.BootMode)
+	require.Equal(t, map[string]string{"team": "images"}, options.Tags)
+	require.Equal(t, common.ToPtr(ec2.ImdsSupportValuesV20), options.IMDSSupport)
+	require.Equal(t, common.ToPtr(false), options.ENASupport)
+	require.True(t, options.SriovNetSupport)
+
+	for _, tags := range []map[string]string{
+		{"Name": "my-image"},
+		{"aws:cloudformation:stack-name": "images"},
+	} {
+		_, err = newAWSTarget(map[string]interface{}{
+			"region":              "eu-central-1",
+			"share_with_accounts": []string{"123456789012"},
+			"tags":                tags,
+		}, it)
+		require.Error(t, err)
+	}
+
+	// the boot mode has to be supported by the image type
+	arch, err = r9.GetArch("aarch64")
+	require.NoError(t, err)
+	it, err = arch.GetImageType("ami")
+	require.NoError(t, err)
+	require.Equal(t, distro.BOOT_UEFI, it.BootMode())
+	_, err = newAWSTarget(map[string]interface{}{
+		"region":              "eu-central-1",
+		"share_with_accounts": []string{"123456789012"},
+		"boot_mode":           "uefi",
+	}, it)
+	require.NoError(t, err)
+	_, err = newAWSTarget(map[string]interface{}{
+		"region":              "eu-central-1",
+		"share_with_accounts": []string{"123456789012"},
+		"boot_mode":           "legacy-bios",
+	}, it)
+	require.Error(t, err)
+}
+
+func TestNewGCPTargetImageOptions(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("gce")
+	require.NoError(t, err)
+
+	tgt, err := newGCPTarget(map[string]interface{}{"region": "us-east4"}, it)
+	require.NoError(t, err)
+	options := tgt.Options.(*target.GCPTargetOptions)
+	require.Equal(t, "X86_64", options.Architecture)
+	require.Nil(t, options.GuestOsFeatures)
+	require.Empty(t, options.Family)
+	require.Nil(t, options.Licenses)
+
+	tgt, err = newGCPTarget(map[string]interface{}{
+		"region":            "us-east4",
+		"guest_os_features": []string{"UEFI_COMPATIBLE", "SEV_LIVE_MIGRATABLE"},
+		"architecture":      "X86_64",
+		"family":            "rhel-9-custom",
+		"licenses":          []string{"projects/rhel-cloud/global/licenses/rhel-9-server"},
+	}, it)
+	require.NoError(t, err)
+	options = tgt.Options.(*target.GCPTargetOptions)
+	require.Equal(t, []string{"UEFI_COMPATIBLE", "SEV_LIVE_MIGRATABLE"}, options.GuestOsFeatures)
+	require.Equal(t, "X86_64", options.Architecture)
+	require.Equal(t, "rhel-9-custom", options.Family)
+	require.Equal(t, []string{"projects/rhel-cloud/global/licenses/rhel-9-server"}, options.Licenses)
+
+	for _, uploadOptions := range []map[string]interface{}{
+		{"region": "us-east4", "guest_os_features": []string{"TURBO"}},
+		{"region": "us-east4", "guest_os_features": []string{"UNDEFINED_TYPE"}},
+		{"region": "us-east4", "architecture": "ARM64"},
+		{"region": "us-east4", "family": "RHEL_9"},
+	} {
+		_, err = newGCPTarget(uploadOptions, it)
+		require.Error(t, err)
+	}
+}
+
+func TestNewAzureTargetImageOptions(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	x86, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	hybrid, err := x86.GetImageType("vhd")
+	require.NoError(t, err)
+	require.Equal(t, distro.BOOT_HYBRID, hybrid.BootMode())
+	aarch64, err := r9.GetArch("aarch64")
+	require.NoError(t, err)
+	uefi, err := aarch64.GetImageType("vhd")
+	require.NoError(t, err)
+	require.Equal(t, distro.BOOT_UEFI, uefi.BootMode())
+
+	gallery := map[string]interface{}{
+		"gallery_name":     "birds",
+		"image_definition": "toucan",
+		"image_version":    "1.0.0",
+	}
+	options := func(extra map[string]interface{}) map[string]interface{} {
+		o := map[string]interface{}{
+			"tenant_id":       "tenant",
+			"subscription_id": "subscription",
+			"resource_group":  "group",
+		}
+		for k, v := range extra {
+			o[k] = v
+		}
+		return o
+	}
+
+	testCases := []struct {
+		imageType    distro.ImageType
+		options      map[string]interface{}
+		generation   string
+		securityType string
+	}{
+		{hybrid, options(nil), "", ""},
+		{hybrid, options(map[string]interface{}{"hyper_v_generation": "V2"}), "V2", ""},
+		{hybrid, options(map[string]interface{}{"security_type": "Standard"}), "", "Standard"},
+		{hybrid, options(map[string]interface{}{"gallery": gallery, "security_type": "TrustedLaunch"}), "V2", "TrustedLaunch"},
+		{uefi, options(nil), "V2", ""},
+	}
+	for _, tc := range testCases {
+		tgt, err := newAzureTarget(tc.options, tc.imageType)
+		require.NoError(t, err)
+		targetOptions := tgt.Options.(*target.AzureImageTargetOptions)
+		require.Equal(t, tc.generation, targetOptions.HyperVGeneration)
+		require.Equal(t, tc.securityType, targetOptions.SecurityType)
+	}
+
+	for _, tc := range []struct {
+		imageType distro.ImageType
+		options   map[string]interface{}
+	}{
+		// only gallery image definitions have a security type
+		{hybrid, options(map[string]interface{}{"security_type": "ConfidentialVM"})},
+		{hybrid, options(map[string]interface{}{"gallery": gallery, "security_type": "TrustedLaunch", "hyper_v_generation": "V1"})},
+		{hybrid, options(map[string]interface{}{"blob_only": true, "hyper_v_generation": "V2"})},
+		{uefi, options(map[string]interface{}{"hyper_v_generation": "V1"})},
+	} {
+		_, err := newAzureTarget(tc.options, tc.imageType)
+		require.Error(t, err)
+	}
+}
+
+func TestRaspberryPiImageType(t *testing.T) {
+	f39 := fedora.NewF39()
+	aarch64, err := f39.GetArch("aarch64")
+	require.NoError(t, err)
+	it, err := aarch64.GetImageType(imageTypeFromApiImageType(ImageTypesRaspberryPi, aarch64))
+	require.NoError(t, err)
+	require.Equal(t, "minimal-raw", it.Name())
+
+	uploadType, err := getDefaultTarget(ImageTypesRaspberryPi)
+	require.NoError(t, err)
+	require.Equal(t, UploadTypesAwsS3, uploadType)
+
+	// the firmware is only installed on aarch64 of Fedora
+	x86_64, err := f39.GetArch("x86_64")
+	require.NoError(t, err)
+	require.Empty(t, imageTypeFromApiImageType(ImageTypesRaspberryPi, x86_64))
+	r9aarch64, err := rhel9.NewRHEL93().GetArch("aarch64")
+	require.NoError(t, err)
+	require.Empty(t, imageTypeFromApiImageType(ImageTypesRaspberryPi, r9aarch64))
+}
+
+func TestNewImageRequestLiveInstallerCustomizations(t *testing.T) {
+	f39 := fedora.NewF39()
+	request := &ComposeRequest{
+		Distribution: f39.Name(),
+		Customizations: &Customizations{
+			Users: &[]User{{Name: "admin"}},
+		},
+	}
+	bp, err := request.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+
+	_, err = newImageRequest(request, ImageRequest{
+		Architecture: "x86_64",
+		ImageType:    ImageTypesLiveInstaller,
+	}, f39, bp)
+	require.Error(t, err)
+	detailsError, ok := err.(*echo.HTTPError).Message.(detailsError)
+	require.True(t, ok)
+	require.Equal(t, ErrorInvalidCustomization, detailsError.errorCode)
+}
+
+func TestGetWSLOptions(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType("wsl")
+	require.NoError(t, err)
+	request := &ComposeRequest{
+		Customizations: &Customizations{
+			Users: &[]User{{Name: "user1"}},
+		},
+	}
+
+	ir := ImageRequest{ImageType: ImageTypesWsl}
+	options, err := ir.GetWSLOptions(request, it)
+	require.NoError(t, err)
+	require.Nil(t, options)
+
+	ir.Wsl = &WSLOptions{
+		DefaultUser:      common.ToPtr("user1"),
+		WslConf:          common.ToPtr("[network]\nhostname=rhel\n"),
+		Bundle:           common.ToPtr(true),
+		DistributionName: common.ToPtr("RHEL"),
+	}
+	options, err = ir.GetWSLOptions(request, it)
+	require.NoError(t, err)
+	require.Equal(t, &target.WSLOptions{
+		DefaultUser:      "user1",
+		Systemd:          true,
+		Conf:             "[network]\nhostname=rhel\n",
+		Bundle:           true,
+		DistributionName: "RHEL",
+	}, options)
+
+	_, err = (&ImageRequest{ImageType: ImageTypesWsl, Wsl: &WSLOptions{DefaultUser: common.ToPtr("user2")}}).GetWSLOptions(request, it)
+	require.Error(t, err)
+	_, err = (&ImageRequest{ImageType: ImageTypesWsl, Wsl: &WSLOptions{DistributionName: common.ToPtr("RHEL")}}).GetWSLOptions(request, it)
+	require.Error(t, err)
+
+	// the Fedora image is a container archive
+	f39arch, err := fedora.NewF39().GetArch("x86_64")
+	require.NoError(t, err)
+	f39wsl, err := f39arch.GetImageType("wsl")
+	require.NoError(t, err)
+	_, err = (&ImageRequest{ImageType: ImageTypesWsl, Wsl: &WSLOptions{}}).GetWSLOptions(request, f39wsl)
+	require.Error(t, err)
+}
+
+func TestIgnitionImageTypes(t *testing.T) {
+	r9arch, err := rhel9.NewRHEL93().GetArch("x86_64")
+	require.NoError(t, err)
+	f39arch, err := fedora.NewF39().GetArch("x86_64")
+	require.NoError(t, err)
+
+	for _, it := range []ImageTypes{ImageTypesEdgeRawImage, ImageTypesEdgeSimplifiedInstaller} {
+		_, err = r9arch.GetImageType(imageTypeFromApiImageType(it, r9arch))
+		require.NoError(t, err, it)
+	}
+	for _, it := range []ImageTypes{ImageTypesIotRawImage, ImageTypesIotSimplifiedInstaller} {
+		_, err = f39arch.GetImageType(imageTypeFromApiImageType(it, f39arch))
+		require.NoError(t, err, it)
+	}
+}
+
+func TestCheckIgnition(t *testing.T) {
+	require.NoError(t, checkIgnition(&ComposeRequest{}, ImageTypesGuestImage))
+
+	config := base64.StdEncoding.EncodeToString([]byte(`{"ignition": {"version": "3.3.0"}}`))
+	embedded := &ComposeRequest{Customizations: &Customizations{Ignition: &Ignition{
+		Embedded: &IgnitionEmbedded{Config: config},
+	}}}
+	require.NoError(t, checkIgnition(embedded, ImageTypesEdgeSimplifiedInstaller))
+	require.Error(t, checkIgnition(embedded, ImageTypesGuestImage))
+
+	firstboot := &ComposeRequest{Customizations: &Customizations{Ignition: &Ignition{
+		Firstboot: &IgnitionFirstboot{Url: "https://example.com/config.ign"},
+	}}}
+	require.NoError(t, checkIgnition(firstboot, ImageTypesEdgeRawImage))
+
+	firstboot.Customizations.Ignition.Embedded = &IgnitionEmbedded{Config: config}
+	require.Error(t, checkIgnition(firstboot, ImageTypesEdgeRawImage))
+
+	for _, invalid := range []string{
+		"not base64",
+		base64.StdEncoding.EncodeToString([]byte(`{"ignition":`)),
+		base64.StdEncoding.EncodeToString([]byte(`{"storage": {}}`)),
+	} {
+		embedded.Customizations.Ignition.Embedded.Config = invalid
+		require.Error(t, checkIgnition(embedded, ImageTypesIotRawImage), invalid)
+	}
+}
+
+func TestCheckOSTreeRemote(t *testing.T) {
+	require.NoError(t, checkOSTreeRemote(&ComposeRequest{}, ImageTypesGuestImage))
+
+	request := &ComposeRequest{Customizations: &Customizations{OstreeRemote: &OSTreeRemote{
+		Url: "https://edge.example.com/repo",
+	}}}
+	require.NoError(t, checkOSTreeRemote(request, ImageTypesEdgeCommit))
+	require.NoError(t, checkOSTreeRemote(request, ImageTypesIotContainer))
+	require.Error(t, checkOSTreeRemote(request, ImageTypesEdgeRawImage))
+	require.Error(t, checkOSTreeRemote(request, ImageTypesGuestImage))
+}
+
+func TestMinimalRawImageType(t *testing.T) {
+	arch, err := fedora.NewF39().GetArch("x86_64")
+	require.NoError(t, err)
+	it, err := arch.GetImageType(imageTypeFromApiImageType(ImageTypesMinimalRaw, arch))
+	require.NoError(t, err)
+	require.Equal(t, "minimal-raw", it.Name())
+
+	uploadType, err := getDefaultTarget(ImageTypesMinimalRaw)
+	require.NoError(t, err)
+	require.Equal(t, UploadTypesAwsS3, uploadType)
+	require.True(t, targetSupportMap()[UploadTypesAwsS3][ImageTypesMinimalRaw])
+	require.True(t, targetSupportMap()[UploadTypesHttp][ImageTypesMinimalRaw])
+}
+
+func TestCheckImageTypeCustomizations(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	qcow2, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+	edgeCommit, err := arch.GetImageType("edge-commit")
+	require.NoError(t, err)
+
+	newRequest := func(mountpoint string) (*ComposeRequest, blueprint.Blueprint) {
+		request := &ComposeRequest{Customizations: &Customizations{
+			Filesystem:       &[]Filesystem{{Mountpoint: mountpoint, MinSize: 1073741824}},
+			PartitioningMode: common.ToPtr(CustomizationsPartitioningModeLvm),
+		}}
+		bp, err := request.GetBlueprintWithCustomizations()
+		require.NoError(t, err)
+		return request, bp
+	}
+
+	request, bp := newRequest("/var")
+	require.NoError(t, checkImageTypeCustomizations(request, qcow2, bp, distro.ImageOptions{}, nil))
+
+	// denied by the mountpoint policy
+	request, bp = newRequest("/etc")
+	require.Error(t, checkImageTypeCustomizations(request, qcow2, bp, distro.ImageOptions{}, nil))
+
+	// ostree image types don't support custom mountpoints
+	request, bp = newRequest("/var")
+	require.Error(t, checkImageTypeCustomizations(request, edgeCommit, bp, distro.ImageOptions{}, nil))
+
+	// nothing to check without partitioning customizations
+	require.NoError(t, checkImageTypeCustomizations(&ComposeRequest{}, edgeCommit, blueprint.Blueprint{}, distro.ImageOptions{}, nil))
+}
+
+func TestCheckKernel(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	getImageType := func(archName, name string) distro.ImageType {
+		arch, err := r9.GetArch(archName)
+		require.NoError(t, err)
+		it, err := arch.GetImageType(name)
+		require.NoError(t, err)
+		return it
+	}
+	newRequest := func(kernel Kernel) *ComposeRequest {
+		return &ComposeRequest{Customizations: &Customizations{Kernel: &kernel}}
+	}
+
+	require.NoError(t, checkKernel(&ComposeRequest{}, getImageType("x86_64", "tar")))
+	require.NoError(t, checkKernel(newRequest(Kernel{Append: common.ToPtr("nosmt=force")}), getImageType("x86_64", "qcow2")))
+	require.NoError(t, checkKernel(newRequest(Kernel{Name: common.ToPtr("kernel-64k")}), getImageType("aarch64", "qcow2")))
+
+	// the 64k page size kernel is only built for aarch64
+	require.Error(t, checkKernel(newRequest(Kernel{Name: common.ToPtr("kernel-64k")}), getImageType("x86_64", "qcow2")))
+	// tarballs don't boot
+	require.Error(t, checkKernel(newRequest(Kernel{Name: common.ToPtr("kernel-rt")}), getImageType("x86_64", "tar")))
+
+	// ostree commits don't support appending to the kernel command line
+	request := newRequest(Kernel{Append: common.ToPtr("nosmt=force")})
+	bp, err := request.GetBlueprintWithCustomizations()
+	require.NoError(t, err)
+	require.Error(t, checkImageTypeCustomizations(request, getImageType("x86_64", "edge-commit"), bp, distro.ImageOptions{}, nil))
+	require.NoError(t, checkImageTypeCustomizations(request, getImageType("x86_64", "qcow2"), bp, distro.ImageOptions{}, nil))
+}
+
+func TestCheckFSNodePolicy(t *testing.T) {
+	r9 := rhel9.NewRHEL93()
+	arch, err := r9.GetArch("x86_64")
+	require.NoError(t, err)
+	qcow2, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	check := func(path string) error {
+		request := &ComposeRequest{Customizations: &Customizations{
+			Files: &[]File{{Path: path, Data: common.ToPtr("data")}},
+		}}
+		bp, err := request.GetBlueprintWithCustomizations()
+		require.NoError(t, err)
+		return checkImageTypeCustomizations(request, qcow2, bp, distro.ImageOptions{}, nil)
+	}
+
+	require.NoError(t, check("/etc/myconfig"))
+	require.Error(t, check("/usr/bin/mytool"))
+	require.Error(t, check("/etc/shadow"))
+}
+
+func TestCheckFDO(t *testing.T) {
+	require.NoError(t, checkFDO(&ComposeRequest{}, ImageTypesGuestImage))
+
+	request := &ComposeRequest{Customizations: &Customizations{Fdo: &FDO{
+		ManufacturingServerUrl: common.ToPtr("http://fdo.example.com:8080"),
+		DiunPubKeyHash:         common.ToPtr("sha256:ab12"),
+	}}}
+	require.NoError(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+	require.NoError(t, checkFDO(request, ImageTypesIotSimplifiedInstaller))
+	require.Error(t, checkFDO(request, ImageTypesEdgeInstaller))
+
+	// the manufacturing server URL is required
+	request.Customizations.Fdo.ManufacturingServerUrl = nil
+	require.Error(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+	request.Customizations.Fdo.ManufacturingServerUrl = common.ToPtr("fdo.example.com:8080")
+	require.Error(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+	request.Customizations.Fdo.ManufacturingServerUrl = common.ToPtr("http://fdo.example.com:8080")
+
+	request.Customizations.Fdo.DiunPubKeyHash = common.ToPtr("md5:ab12")
+	require.Error(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+	request.Customizations.Fdo.DiunPubKeyHash = common.ToPtr("sha256:ab12")
+
+	// the manufacturing server is verified in exactly one way, any
+	// diun_pub_key_insecure value enables it as before
+	request.Customizations.Fdo.DiunPubKeyInsecure = common.ToPtr("true")
+	require.Error(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+	request.Customizations.Fdo.DiunPubKeyHash = nil
+	require.NoError(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+	request.Customizations.Fdo.DiunPubKeyInsecure = common.ToPtr("yes")
+	require.NoError(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+	request.Customizations.Fdo.DiunPubKeyInsecure = nil
+	require.Error(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+
+	request.Customizations.Fdo.DiunPubKeyRootCerts = common.ToPtr(testCACert(t, 1) + testCACert(t, 2))
+	require.NoError(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller))
+	for _, invalid := range []string{
+		"not a certificate",
+		testCACert(t, 1) + "trailing garbage",
+		"-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n",
+	} {
+		request.Customizations.Fdo.DiunPubKeyRootCerts = common.ToPtr(invalid)
+		require.Error(t, checkFDO(request, ImageTypesEdgeSimplifiedInstaller), invalid)
+	}
+}
@@ -0,0 +1,148 @@
+package v2
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+const (
+	// the mount units take precedence over the ones the fstab generator
+	// creates
+	networkMountUnitsDir = "/etc/systemd/system"
+	// only root can read the credentials of the CIFS shares
+	cifsCredentialsDir = "/etc/cifs-credentials"
+)
+
+// networkMountCustomizations returns the directories, the files and the
+// services mounting the network shares with systemd mount units, or with
+// automount units mounting them on first access. The mount points can't
+// be mount points of the filesystem customization.
+func networkMountCustomizations(mounts []NetworkMount, filesystems []blueprint.FilesystemCustomization) ([]blueprint.DirectoryCustomization, []blueprint.FileCustomization, []string, error) {
+	var dirs []blueprint.DirectoryCustomization
+	var files []blueprint.FileCustomization
+	var services []string
+	mountpoints := map[string]bool{}
+	for _, fs := range filesystems {
+		mountpoints[fs.Mountpoint] = true
+	}
+	for _, mount := range mounts {
+		if mount.Mountpoint != path.Clean(mount.Mountpoint) || mount.Mountpoint == "/" {
+			return nil, nil, nil, fmt.Errorf("the mount point %s of the network share isn't valid", mount.Mountpoint)
+		}
+		if mountpoints[mount.Mountpoint] {
+			return nil, nil, nil, fmt.Errorf("the mount point %s is used twice", mount.Mountpoint)
+		}
+		mountpoints[mount.Mountpoint] = true
+
+		unit := systemdEscapePath(mount.Mountpoint)
+		options := []string{"_netdev"}
+		if mount.Options != nil {
+			options = append(options, *mount.Options...)
+		}
+		switch mount.Type {
+		case NetworkMountTypeNfs:
+			if !strings.Contains(mount.Source, ":/") {
+				return nil, nil, nil, fmt.Errorf("the NFS share %s has to be in the host:/path format", mount.Source)
+			}
+			if mount.Credentials != nil {
+				return nil, nil, nil, fmt.Errorf("the NFS share %s can't have credentials", mount.Source)
+			}
+		case NetworkMountTypeCifs:
+			if !strings.HasPrefix(mount.Source, "//") {
+				return nil, nil, nil, fmt.Errorf("the CIFS share %s has to be in the //host/share format", mount.Source)
+			}
+			if mount.Credentials != nil {
+				credentialsPath := path.Join(cifsCredentialsDir, unit+".cred")
+				options = append(options, "credentials="+credentialsPath)
+				if len(dirs) == 0 {
+					dirs = append(dirs, blueprint.DirectoryCustomization{
+						Path: cifsCredentialsDir,
+						Mode: "0700",
+					})
+				}
+				files = append(files, blueprint.FileCustomization{
+					Path: credentialsPath,
+					Mode: "0600",
+					Data: cifsCredentials(mount.Credentials),
+				})
+			}
+		}
+
+		var mountUnit strings.Builder
+		mountUnit.WriteString("[Unit]\n")
+		fmt.Fprintf(&mountUnit, "Description=Mount %s at %s\n", mount.Source, mount.Mountpoint)
+		mountUnit.WriteString("\n[Mount]\n")
+		fmt.Fprintf(&mountUnit, "What=%s\n", mount.Source)
+		fmt.Fprintf(&mountUnit, "Where=%s\n", mount.Mountpoint)
+		fmt.Fprintf(&mountUnit, "Type=%s\n", mount.Type)
+		fmt.Fprintf(&mountUnit, "Options=%s\n", strings.Join(options, ","))
+
+		automount := mount.Automount != nil && *mount.Automount
+		if !automount {
+			mountUnit.WriteString("\n[Install]\n")
+			mountUnit.WriteString("WantedBy=remote-fs.target\n")
+			services = append(services, unit+".mount")
+		}
+		files = append(files, blueprint.FileCustomization{
+			Path: path.Join(networkMountUnitsDir, unit+".mount"),
+			Mode: "0644",
+			Data: mountUnit.String(),
+		})
+		if !automount {
+			continue
+		}
+
+		var automountUnit strings.Builder
+		automountUnit.WriteString("[Unit]\n")
+		fmt.Fprintf(&automountUnit, "Description=Mount %s at %s on access\n", mount.Source, mount.Mountpoint)
+		automountUnit.WriteString("\n[Automount]\n")
+		fmt.Fprintf(&automountUnit, "Where=%s\n", mount.Mountpoint)
+		automountUnit.WriteString("\n[Install]\n")
+		automountUnit.WriteString("WantedBy=remote-fs.target\n")
+		files = append(files, blueprint.FileCustomization{
+			Path: path.Join(networkMountUnitsDir, unit+".automount"),
+			Mode: "0644",
+			Data: automountUnit.String(),
+		})
+		services = append(services, unit+".automount")
+	}
+	return dirs, files, services, nil
+}
+
+// cifsCredentials returns the credentials file mount.cifs reads.
+func cifsCredentials(credentials *NetworkMountCredentials) string {
+	var data strings.Builder
+	fmt.Fprintf(&data, "username=%s\n", credentials.Username)
+	fmt.Fprintf(&data, "password=%s\n", credentials.Password)
+	if credentials.Domain != nil {
+		fmt.Fprintf(&data, "domain=%s\n", *credentials.Domain)
+	}
+	return data.String()
+}
+
+// systemdEscapePath escapes the path like systemd-escape --path does, the
+// names of the mount units are the escaped mount points.
+func systemdEscapePath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return "-"
+	}
+	var escaped strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c == '/':
+			escaped.WriteByte('-')
+		case c == '.' && i == 0:
+			fmt.Fprintf(&escaped, `\x%02x`, c)
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == ':', c == '_', c == '.':
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, `\x%02x`, c)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,64 @@
+package v2
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/google/uuid"
+	"gopkg.in/ini.v1"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+// nmConnectionsDir is where NetworkManager reads the keyfile connection
+// profiles from, it ignores the ones other users than root can read.
+const nmConnectionsDir = "/etc/NetworkManager/system-connections"
+
+// nmConnectionFiles returns the files installing the NetworkManager
+// connection profiles, which have to be valid keyfiles with unique IDs.
+func nmConnectionFiles(connections []NetworkConnection) ([]blueprint.FileCustomization, error) {
+	var files []blueprint.FileCustomization
+	names := map[string]bool{}
+	ids := map[string]bool{}
+	for _, connection := range connections {
+		if names[connection.Name] {
+			return nil, fmt.Errorf("network connection %s is specified twice", connection.Name)
+		}
+		names[connection.Name] = true
+
+		keyfile, err := ini.LoadSources(ini.LoadOptions{
+			// values like Wi-Fi keys can contain any characters
+			IgnoreInlineComment: true,
+		}, []byte(connection.Keyfile))
+		if err != nil {
+			return nil, fmt.Errorf("network connection %s isn't a valid keyfile: %v", connection.Name, err)
+		}
+		section, err := keyfile.GetSection("connection")
+		if err != nil {
+			return nil, fmt.Errorf("network connection %s has no [connection] section", connection.Name)
+		}
+		for _, key := range []string{"id", "type"} {
+			if section.Key(key).String() == "" {
+				return nil, fmt.Errorf("network connection %s has no %s", connection.Name, key)
+			}
+		}
+		if id := section.Key("uuid").String(); id != "" {
+			_, err = uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("network connection %s has an invalid uuid: %v", connection.Name, err)
+			}
+		}
+		id := section.Key("id").String()
+		if ids[id] {
+			return nil, fmt.Errorf("network connection ID %s is used twice", id)
+		}
+		ids[id] = true
+
+		files = append(files, blueprint.FileCustomization{
+			Path: path.Join(nmConnectionsDir, connection.Name+".nmconnection"),
+			Mode: "0600",
+			Data: connection.Keyfile,
+		})
+	}
+	return files, nil
+}
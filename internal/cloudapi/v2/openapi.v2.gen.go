@@ -7,21 +7,82 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 )
 
 const (
 	BearerScopes = "Bearer.Scopes"
 )
 
+// Defines values for AWSEC2UploadOptionsBootMode.
+const (
+	AWSEC2UploadOptionsBootModeLegacyBios AWSEC2UploadOptionsBootMode = "legacy-bios"
+
+	AWSEC2UploadOptionsBootModeUefi AWSEC2UploadOptionsBootMode = "uefi"
+
+	AWSEC2UploadOptionsBootModeUefiPreferred AWSEC2UploadOptionsBootMode = "uefi-preferred"
+)
+
+// Defines values for AWSEC2UploadOptionsImdsSupport.
+const (
+	AWSEC2UploadOptionsImdsSupportV20 AWSEC2UploadOptionsImdsSupport = "v2.0"
+)
+
+// Defines values for AWSMarketplaceSecurityGroupIpProtocol.
+const (
+	AWSMarketplaceSecurityGroupIpProtocolTcp AWSMarketplaceSecurityGroupIpProtocol = "tcp"
+
+	AWSMarketplaceSecurityGroupIpProtocolUdp AWSMarketplaceSecurityGroupIpProtocol = "udp"
+)
+
+// Defines values for AWSS3UploadOptionsAcl.
+const (
+	AWSS3UploadOptionsAclBucketOwnerFullControl AWSS3UploadOptionsAcl = "bucket-owner-full-control"
+
+	AWSS3UploadOptionsAclBucketOwnerRead AWSS3UploadOptionsAcl = "bucket-owner-read"
+
+	AWSS3UploadOptionsAclPrivate AWSS3UploadOptionsAcl = "private"
+
+	AWSS3UploadOptionsAclPublicRead AWSS3UploadOptionsAcl = "public-read"
+)
+
+// Defines values for AzureGalleryStorageAccountType.
+const (
+	AzureGalleryStorageAccountTypePremiumLRS AzureGalleryStorageAccountType = "Premium_LRS"
+
+	AzureGalleryStorageAccountTypeStandardLRS AzureGalleryStorageAccountType = "Standard_LRS"
+
+	AzureGalleryStorageAccountTypeStandardZRS AzureGalleryStorageAccountType = "Standard_ZRS"
+)
+
+// Defines values for AzureUploadOptionsHyperVGeneration.
+const (
+	AzureUploadOptionsHyperVGenerationV1 AzureUploadOptionsHyperVGeneration = "V1"
+
+	AzureUploadOptionsHyperVGenerationV2 AzureUploadOptionsHyperVGeneration = "V2"
+)
+
+// Defines values for AzureUploadOptionsSecurityType.
+const (
+	AzureUploadOptionsSecurityTypeConfidentialVM AzureUploadOptionsSecurityType = "ConfidentialVM"
+
+	AzureUploadOptionsSecurityTypeStandard AzureUploadOptionsSecurityType = "Standard"
+
+	AzureUploadOptionsSecurityTypeTrustedLaunch AzureUploadOptionsSecurityType = "TrustedLaunch"
+)
+
 // Defines values for ComposeStatusValue.
 const (
 	ComposeStatusValueFailure ComposeStatusValue = "failure"
@@ -29,6 +90,15 @@ const (
 	ComposeStatusValuePending ComposeStatusValue = "pending"
 
 	ComposeStatusValueSuccess ComposeStatusValue = "success"
+
+	ComposeStatusValueTimedOut ComposeStatusValue = "timed_out"
+)
+
+// Defines values for ComposeUpgradeIssueSeverity.
+const (
+	ComposeUpgradeIssueSeverityError ComposeUpgradeIssueSeverity = "error"
+
+	ComposeUpgradeIssueSeverityWarning ComposeUpgradeIssueSeverity = "warning"
 )
 
 // Defines values for CustomizationsPartitioningMode.
@@ -40,6 +110,20 @@ const (
 	CustomizationsPartitioningModeRaw CustomizationsPartitioningMode = "raw"
 )
 
+// Defines values for FileDataEncoding.
+const (
+	FileDataEncodingBase64 FileDataEncoding = "base64"
+
+	FileDataEncodingPlain FileDataEncoding = "plain"
+)
+
+// Defines values for GCPUploadOptionsArchitecture.
+const (
+	GCPUploadOptionsArchitectureARM64 GCPUploadOptionsArchitecture = "ARM64"
+
+	GCPUploadOptionsArchitectureX8664 GCPUploadOptionsArchitecture = "X86_64"
+)
+
 // Defines values for ImageStatusValue.
 const (
 	ImageStatusValueBuilding ImageStatusValue = "building"
@@ -79,6 +163,10 @@ const (
 
 	ImageTypesEdgeInstaller ImageTypes = "edge-installer"
 
+	ImageTypesEdgeRawImage ImageTypes = "edge-raw-image"
+
+	ImageTypesEdgeSimplifiedInstaller ImageTypes = "edge-simplified-installer"
+
 	ImageTypesGcp ImageTypes = "gcp"
 
 	ImageTypesGcpRhui ImageTypes = "gcp-rhui"
@@ -95,10 +183,20 @@ const (
 
 	ImageTypesIotRawImage ImageTypes = "iot-raw-image"
 
+	ImageTypesIotSimplifiedInstaller ImageTypes = "iot-simplified-installer"
+
 	ImageTypesLiveInstaller ImageTypes = "live-installer"
 
+	ImageTypesMinimalRaw ImageTypes = "minimal-raw"
+
 	ImageTypesOci ImageTypes = "oci"
 
+	ImageTypesRaspberryPi ImageTypes = "raspberry-pi"
+
+	ImageTypesVagrantLibvirt ImageTypes = "vagrant-libvirt"
+
+	ImageTypesVagrantVirtualbox ImageTypes = "vagrant-virtualbox"
+
 	ImageTypesVsphere ImageTypes = "vsphere"
 
 	ImageTypesVsphereOva ImageTypes = "vsphere-ova"
@@ -106,6 +204,51 @@ const (
 	ImageTypesWsl ImageTypes = "wsl"
 )
 
+// Defines values for NTPLeapSecondMode.
+const (
+	NTPLeapSecondModeIgnore NTPLeapSecondMode = "ignore"
+
+	NTPLeapSecondModeSlew NTPLeapSecondMode = "slew"
+
+	NTPLeapSecondModeStep NTPLeapSecondMode = "step"
+
+	NTPLeapSecondModeSystem NTPLeapSecondMode = "system"
+)
+
+// Defines values for NetworkMountType.
+const (
+	NetworkMountTypeCifs NetworkMountType = "cifs"
+
+	NetworkMountTypeNfs NetworkMountType = "nfs"
+)
+
+// Defines values for OCIImageImportOptionsLaunchMode.
+const (
+	OCIImageImportOptionsLaunchModeEMULATED OCIImageImportOptionsLaunchMode = "EMULATED"
+
+	OCIImageImportOptionsLaunchModeNATIVE OCIImageImportOptionsLaunchMode = "NATIVE"
+
+	OCIImageImportOptionsLaunchModePARAVIRTUALIZED OCIImageImportOptionsLaunchMode = "PARAVIRTUALIZED"
+)
+
+// Defines values for OCIUploadOptionsStorageTier.
+const (
+	OCIUploadOptionsStorageTierInfrequentAccess OCIUploadOptionsStorageTier = "InfrequentAccess"
+
+	OCIUploadOptionsStorageTierStandard OCIUploadOptionsStorageTier = "Standard"
+)
+
+// Defines values for SshdPermitRootLogin.
+const (
+	SshdPermitRootLoginForcedCommandsOnly SshdPermitRootLogin = "forced-commands-only"
+
+	SshdPermitRootLoginNo SshdPermitRootLogin = "no"
+
+	SshdPermitRootLoginProhibitPassword SshdPermitRootLogin = "prohibit-password"
+
+	SshdPermitRootLoginYes SshdPermitRootLogin = "yes"
+)
+
 // Defines values for UploadStatusValue.
 const (
 	UploadStatusValueFailure UploadStatusValue = "failure"
@@ -125,36 +268,189 @@ const (
 
 	UploadTypesAzure UploadTypes = "azure"
 
+	UploadTypesBaremetal UploadTypes = "baremetal"
+
 	UploadTypesContainer UploadTypes = "container"
 
 	UploadTypesGcp UploadTypes = "gcp"
 
+	UploadTypesHetzner UploadTypes = "hetzner"
+
+	UploadTypesHttp UploadTypes = "http"
+
+	UploadTypesLibvirt UploadTypes = "libvirt"
+
+	UploadTypesMock UploadTypes = "mock"
+
 	UploadTypesOciObjectstorage UploadTypes = "oci.objectstorage"
 
+	UploadTypesOras UploadTypes = "oras"
+
+	UploadTypesPulpFile UploadTypes = "pulp.file"
+
 	UploadTypesPulpOstree UploadTypes = "pulp.ostree"
+
+	UploadTypesRbd UploadTypes = "rbd"
+
+	UploadTypesVagrantCloud UploadTypes = "vagrant-cloud"
+
+	UploadTypesVsphere UploadTypes = "vsphere"
 )
 
 // AWSEC2CloneCompose defines model for AWSEC2CloneCompose.
 type AWSEC2CloneCompose struct {
+	// ID, ARN or alias of a customer managed KMS key in the region
+	// encrypting the copy of the AMI. Required to share the copy of an
+	// encrypted AMI, the accounts are granted access to the key.
+	KmsKeyId          *string   `json:"kms_key_id,omitempty"`
 	Region            string    `json:"region"`
 	ShareWithAccounts *[]string `json:"share_with_accounts,omitempty"`
 }
 
 // AWSEC2UploadOptions defines model for AWSEC2UploadOptions.
 type AWSEC2UploadOptions struct {
+	// Boot mode of the AMI, the boot mode supported by the image type
+	// if not specified. Image types booting in both modes can be
+	// registered with either of them.
+	BootMode *AWSEC2UploadOptionsBootMode `json:"boot_mode,omitempty"`
+
+	// Enable enhanced networking with the Elastic Network Adapter
+	EnaSupport *bool `json:"ena_support,omitempty"`
+
+	// Require the instances launched from the AMI to use version 2 of
+	// the instance metadata service.
+	ImdsSupport *AWSEC2UploadOptionsImdsSupport `json:"imds_support,omitempty"`
+
+	// ID, ARN or alias of a customer managed KMS key in the region
+	// encrypting the snapshot and the AMI. The accounts in
+	// share_with_accounts are granted access to the key.
+	KmsKeyId          *string  `json:"kms_key_id,omitempty"`
 	Region            string   `json:"region"`
 	ShareWithAccounts []string `json:"share_with_accounts"`
 	SnapshotName      *string  `json:"snapshot_name,omitempty"`
+
+	// Stop after importing the EBS snapshot, without registering an
+	// AMI, e.g. to register it with custom parameters. The snapshot is
+	// shared with the accounts in share_with_accounts.
+	SnapshotOnly *bool `json:"snapshot_only,omitempty"`
+
+	// Enable enhanced networking with the Intel 82599 VF interface
+	SriovNetSupport *bool `json:"sriov_net_support,omitempty"`
+
+	// Tags of the AMI and its snapshot. The Name tag is set by the
+	// service and can't be overridden.
+	Tags *AWSEC2UploadOptions_Tags `json:"tags,omitempty"`
+}
+
+// Boot mode of the AMI, the boot mode supported by the image type
+// if not specified. Image types booting in both modes can be
+// registered with either of them.
+type AWSEC2UploadOptionsBootMode string
+
+// Require the instances launched from the AMI to use version 2 of
+// the instance metadata service.
+type AWSEC2UploadOptionsImdsSupport string
+
+// Tags of the AMI and its snapshot. The Name tag is set by the
+// service and can't be overridden.
+type AWSEC2UploadOptions_Tags struct {
+	AdditionalProperties map[string]string `json:"-"`
 }
 
 // AWSEC2UploadStatus defines model for AWSEC2UploadStatus.
 type AWSEC2UploadStatus struct {
-	Ami    string `json:"ami"`
-	Region string `json:"region"`
+	// Empty if only the snapshot was imported
+	Ami string `json:"ami"`
+
+	// Boot mode the AMI was registered with, if any
+	BootMode    *string `json:"boot_mode,omitempty"`
+	EnaSupport  *bool   `json:"ena_support,omitempty"`
+	ImdsSupport *string `json:"imds_support,omitempty"`
+
+	// The customer managed KMS key encrypting the snapshot and the AMI,
+	// not set if they aren't encrypted
+	KmsKeyArn *string `json:"kms_key_arn,omitempty"`
+	Region    string  `json:"region"`
+
+	// The imported snapshot, set if no AMI was registered
+	SnapshotId      *string `json:"snapshot_id,omitempty"`
+	SriovNetSupport *bool   `json:"sriov_net_support,omitempty"`
+
+	// Tags of the AMI and its snapshot, besides the Name tag
+	Tags *AWSEC2UploadStatus_Tags `json:"tags,omitempty"`
+}
+
+// Tags of the AMI and its snapshot, besides the Name tag
+type AWSEC2UploadStatus_Tags struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// AWSMarketplacePublishRequest defines model for AWSMarketplacePublishRequest.
+type AWSMarketplacePublishRequest struct {
+	// IAM role AWS Marketplace assumes to copy the AMI.
+	AccessRoleArn          string `json:"access_role_arn"`
+	OperatingSystemName    string `json:"operating_system_name"`
+	OperatingSystemVersion string `json:"operating_system_version"`
+
+	// ID of the AMI product the version is added to
+	ProductId               string  `json:"product_id"`
+	RecommendedInstanceType string  `json:"recommended_instance_type"`
+	ReleaseNotes            *string `json:"release_notes,omitempty"`
+
+	// Ingress rules recommended to the buyers
+	SecurityGroups    *[]AWSMarketplaceSecurityGroup `json:"security_groups,omitempty"`
+	UsageInstructions string                         `json:"usage_instructions"`
+
+	// User to log into instances of the AMI as
+	UserName     string `json:"user_name"`
+	VersionTitle string `json:"version_title"`
+}
+
+// AWSMarketplaceSecurityGroup defines model for AWSMarketplaceSecurityGroup.
+type AWSMarketplaceSecurityGroup struct {
+	FromPort   int                                   `json:"from_port"`
+	IpProtocol AWSMarketplaceSecurityGroupIpProtocol `json:"ip_protocol"`
+	IpRanges   []string                              `json:"ip_ranges"`
+	ToPort     int                                   `json:"to_port"`
 }
 
+// AWSMarketplaceSecurityGroupIpProtocol defines model for AWSMarketplaceSecurityGroup.IpProtocol.
+type AWSMarketplaceSecurityGroupIpProtocol string
+
 // AWSS3UploadOptions defines model for AWSS3UploadOptions.
 type AWSS3UploadOptions struct {
+	// Access key ID of the endpoint, required with endpoint.
+	AccessKeyId *string `json:"access_key_id,omitempty"`
+
+	// Canned ACL of the uploaded object. public-read is the same as
+	// setting public, a presigned URL is returned otherwise.
+	Acl *AWSS3UploadOptionsAcl `json:"acl,omitempty"`
+
+	// Bucket the image is uploaded to, the one configured on the worker
+	// if not set
+	Bucket *string `json:"bucket,omitempty"`
+
+	// PEM encoded CA certificates trusted for the endpoint, besides the
+	// ones of the worker. Only allowed with endpoint.
+	CaBundle *string `json:"ca_bundle,omitempty"`
+
+	// Endpoint of an S3-compatible service (e.g. MinIO or Ceph RGW) the
+	// image is uploaded to instead of AWS. Requires the credentials of
+	// the endpoint, the ones of the worker are only used for the
+	// endpoint it's configured with.
+	Endpoint *string `json:"endpoint,omitempty"`
+
+	// Push the ostree commit of the edge-commit and iot-commit image types
+	// to a remote repository once it's uploaded. Repositories served over
+	// http(s) must accept PUT requests of the files of the repository, the
+	// summary of the repository isn't updated. ssh:// repositories are
+	// pushed to with ostree-push, using the ssh configuration of the worker.
+	OstreeMirror *OSTreeMirrorOptions `json:"ostree_mirror,omitempty"`
+
+	// Address the bucket in the path of the URLs of the endpoint
+	// instead of their host. Only allowed with endpoint.
+	PathStyle *bool `json:"path_style,omitempty"`
+
 	// If set to false (the default value), a long, obfuscated URL
 	// is returned. Its expiration might be sooner than for other upload
 	// targets.
@@ -163,15 +459,123 @@ type AWSS3UploadOptions struct {
 	// its expiration is the same as for the other upload targets.
 	Public *bool  `json:"public,omitempty"`
 	Region string `json:"region"`
+
+	// Return the bucket and the key of the uploaded object besides its
+	// URL, e.g. to access it with the credentials of the bucket owner
+	// once the URL expires.
+	ReturnObjectKey *bool `json:"return_object_key,omitempty"`
+
+	// Secret access key of the endpoint, required with endpoint. It isn't
+	// kept with the compose.
+	SecretAccessKey *string `json:"secret_access_key,omitempty"`
+
+	// Session token of temporary credentials of the endpoint. It isn't
+	// kept with the compose.
+	SessionToken *string `json:"session_token,omitempty"`
+
+	// Lifetime of the presigned URL of a non-public object, defaults to
+	// the maximum of 168 hours (7 days). Can't be combined with public.
+	UrlExpiryHours *int `json:"url_expiry_hours,omitempty"`
 }
 
+// Canned ACL of the uploaded object. public-read is the same as
+// setting public, a presigned URL is returned otherwise.
+type AWSS3UploadOptionsAcl string
+
 // AWSS3UploadStatus defines model for AWSS3UploadStatus.
 type AWSS3UploadStatus struct {
-	Url string `json:"url"`
+	// Bucket of the object, if return_object_key was set
+	Bucket *string `json:"bucket,omitempty"`
+
+	// Key of the object, if return_object_key was set
+	Key *string `json:"key,omitempty"`
+	Url string  `json:"url"`
+}
+
+// ArtifactChecksum defines model for ArtifactChecksum.
+type ArtifactChecksum struct {
+	Artifact string `json:"artifact"`
+	Checksum string `json:"checksum"`
+}
+
+// ArtifactSignature defines model for ArtifactSignature.
+type ArtifactSignature struct {
+	// Name of the signed artifact in the bucket
+	Artifact string `json:"artifact"`
+	Checksum string `json:"checksum"`
+
+	// URL of the detached signature of the SHA256SUMS file
+	ChecksumsSignatureUrl string `json:"checksums_signature_url"`
+
+	// URL of the SHA256SUMS file listing the artifact
+	ChecksumsUrl string `json:"checksums_url"`
+
+	// URL of the detached signature of the artifact
+	SignatureUrl string `json:"signature_url"`
+}
+
+// AzureBlobUploadStatus defines model for AzureBlobUploadStatus.
+type AzureBlobUploadStatus struct {
+	BlobUrl string `json:"blob_url"`
+
+	// Read-only SAS token of the blob, if requested
+	SasToken *string `json:"sas_token,omitempty"`
+}
+
+// Publish the uploaded image as a version of an image definition in an
+// Azure Compute Gallery. The gallery and the image definition must exist
+// in the resource group of the image.
+type AzureGalleryOptions struct {
+	GalleryName     string `json:"gallery_name"`
+	ImageDefinition string `json:"image_definition"`
+
+	// Version of the image in the MajorVersion.MinorVersion.Patch format
+	ImageVersion string `json:"image_version"`
+
+	// Number of replicas in the regions which don't set their own,
+	// defaults to 1.
+	ReplicaCount *int `json:"replica_count,omitempty"`
+
+	// Storage account type of the replicas, defaults to Standard_LRS.
+	StorageAccountType *AzureGalleryStorageAccountType `json:"storage_account_type,omitempty"`
+
+	// Regions the image version is replicated to. The image version is
+	// always available in the location of the image.
+	TargetRegions *[]AzureGalleryTargetRegion `json:"target_regions,omitempty"`
+}
+
+// Storage account type of the replicas, defaults to Standard_LRS.
+type AzureGalleryStorageAccountType string
+
+// AzureGalleryTargetRegion defines model for AzureGalleryTargetRegion.
+type AzureGalleryTargetRegion struct {
+	Name         string `json:"name"`
+	ReplicaCount *int   `json:"replica_count,omitempty"`
+
+	// Storage account type of the replicas, defaults to Standard_LRS.
+	StorageAccountType *AzureGalleryStorageAccountType `json:"storage_account_type,omitempty"`
 }
 
 // AzureUploadOptions defines model for AzureUploadOptions.
 type AzureUploadOptions struct {
+	// Only upload the VHD to a page blob in the imagebuilder container
+	// of the storage account of the resource group, without registering
+	// an image. The name of the blob is the image name with the .vhd
+	// extension. Can't be combined with gallery.
+	BlobOnly *bool `json:"blob_only,omitempty"`
+
+	// Publish the uploaded image as a version of an image definition in an
+	// Azure Compute Gallery. The gallery and the image definition must exist
+	// in the resource group of the image.
+	Gallery *AzureGalleryOptions `json:"gallery,omitempty"`
+
+	// Hyper-V generation of the image, it can't be changed once the
+	// image is created. V1 images boot with BIOS, V2 images with UEFI.
+	// If not specified, it's the generation of the gallery image
+	// definition, V2 for image types booting only with UEFI and V1
+	// otherwise.
+	HyperVGeneration *AzureUploadOptionsHyperVGeneration `json:"hyper_v_generation,omitempty"`
+
 	// Name of the uploaded image. It must be unique in the given resource group.
 	// If name is omitted from the request, a random one based on a UUID is
 	// generated.
@@ -186,6 +590,15 @@ type AzureUploadOptions struct {
 	// Name of the resource group where the image should be uploaded.
 	ResourceGroup string `json:"resource_group"`
 
+	// Generate a read-only SAS token of the blob valid for this many
+	// hours, only with blob_only
+	SasExpiryHours *int `json:"sas_expiry_hours,omitempty"`
+
+	// Security type of the virtual machines created from the image.
+	// Trusted Launch and confidential VMs require a V2 image published
+	// to a gallery whose image definition supports the security type.
+	SecurityType *AzureUploadOptionsSecurityType `json:"security_type,omitempty"`
+
 	// ID of subscription where the image should be uploaded.
 	SubscriptionId string `json:"subscription_id"`
 
@@ -195,9 +608,126 @@ type AzureUploadOptions struct {
 	TenantId string `json:"tenant_id"`
 }
 
+// Hyper-V generation of the image, it can't be changed once the
+// image is created. V1 images boot with BIOS, V2 images with UEFI.
+// If not specified, it's the generation of the gallery image
+// definition, V2 for image types booting only with UEFI and V1
+// otherwise.
+type AzureUploadOptionsHyperVGeneration string
+
+// Security type of the virtual machines created from the image.
+// Trusted Launch and confidential VMs require a V2 image published
+// to a gallery whose image definition supports the security type.
+type AzureUploadOptionsSecurityType string
+
 // AzureUploadStatus defines model for AzureUploadStatus.
 type AzureUploadStatus struct {
-	ImageName string `json:"image_name"`
+	// ID of the gallery image version, if the image was published to a gallery
+	GalleryImageVersionId *string `json:"gallery_image_version_id,omitempty"`
+	ImageName             string  `json:"image_name"`
+}
+
+// Publishes the image as a raw disk image to a directory of an
+// HTTP(S) server, for provisioning bare metal machines with Ironic or
+// Equinix Metal custom images. Optionally the kernel, the initrd and
+// an iPXE script booting them are published next to it for netboot.
+type BareMetalUploadOptions struct {
+	// Headers added to the requests, e.g. API keys. They aren't kept
+	// with the compose, neither are the credentials of the basic
+	// authentication or the user of the URL.
+	Headers *BareMetalUploadOptions_Headers `json:"headers,omitempty"`
+
+	// Kernel command line of the iPXE script
+	KernelArgs *string `json:"kernel_args,omitempty"`
+
+	// Name of the subdirectory of the artifacts. If not specified a
+	// random 'composer-api-<uuid>' string is used.
+	Name *string `json:"name,omitempty"`
+
+	// Publish the kernel and the initrd of the image and an iPXE
+	// script booting them as well
+	Netboot *bool `json:"netboot,omitempty"`
+
+	// Password of the basic authentication
+	Password *string `json:"password,omitempty"`
+
+	// URL of the directory the artifacts are published to, they are
+	// uploaded to a subdirectory named after the image
+	Url string `json:"url"`
+
+	// Username of the basic authentication
+	Username *string `json:"username,omitempty"`
+
+	// Create the missing WebDAV collections of the URL with MKCOL
+	// before the upload
+	Webdav *bool `json:"webdav,omitempty"`
+}
+
+// Headers added to the requests, e.g. API keys. They aren't kept
+// with the compose, neither are the credentials of the basic
+// authentication or the user of the URL.
+type BareMetalUploadOptions_Headers struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// BareMetalUploadStatus defines model for BareMetalUploadStatus.
+type BareMetalUploadStatus struct {
+	// SHA256 digest of the raw disk image
+	ImageChecksum string `json:"image_checksum"`
+
+	// URL of the raw disk image
+	ImageUrl      string  `json:"image_url"`
+	InitrdUrl     *string `json:"initrd_url,omitempty"`
+	IpxeScriptUrl *string `json:"ipxe_script_url,omitempty"`
+	KernelUrl     *string `json:"kernel_url,omitempty"`
+}
+
+// CA certificates added to the trust store of the system. They are
+// installed as ca-trust anchors and the trust store is extracted on the
+// first boot. The ca-certificates package has to be part of the image.
+type CACertsCustomization struct {
+	PemCerts []string `json:"pem_certs"`
+}
+
+// CatalogImage defines model for CatalogImage.
+type CatalogImage struct {
+	Architecture     *string   `json:"architecture,omitempty"`
+	ArtifactChecksum *string   `json:"artifact_checksum,omitempty"`
+	DeliveredAt      time.Time `json:"delivered_at"`
+
+	// ID of the compose or clone which delivered the image
+	Id      string      `json:"id"`
+	Options interface{} `json:"options"`
+	Type    UploadTypes `json:"type"`
+}
+
+// CatalogImageList defines model for CatalogImageList.
+type CatalogImageList struct {
+	// Embedded struct due to allOf(#/components/schemas/List)
+	List `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Items []CatalogImage `json:"items"`
+}
+
+// ChecksumManifest defines model for ChecksumManifest.
+type ChecksumManifest struct {
+	Artifacts []ArtifactChecksum `json:"artifacts"`
+
+	// URLs of the clearsigned CHECKSUM files, one for each upload. Only
+	// set once they're signed.
+	ChecksumUrls *[]string `json:"checksum_urls,omitempty"`
+
+	// URLs of the SHA256SUMS files, one for each upload
+	Sha256sumsUrls []string `json:"sha256sums_urls"`
+}
+
+// Publishes a SHA256SUMS file covering all the artifacts of the compose
+// next to them, it's listed in the metadata of the compose. Only
+// supported for uploads to S3.
+type ChecksumManifestOptions struct {
+	// Also publishes a CHECKSUM file like the ones of Fedora releases,
+	// clearsigned with the GPG key of the signing workers.
+	Signed *bool `json:"signed,omitempty"`
 }
 
 // CloneComposeBody defines model for CloneComposeBody.
@@ -227,6 +757,18 @@ type ComposeId struct {
 	Id string `json:"id"`
 }
 
+// ComposeImageExport defines model for ComposeImageExport.
+type ComposeImageExport struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Error  *ComposeStatusError `json:"error,omitempty"`
+	Status ComposeStatusValue  `json:"status"`
+
+	// Cloud Storage URI of the exported image
+	Uri *string `json:"uri,omitempty"`
+}
+
 // ComposeLogs defines model for ComposeLogs.
 type ComposeLogs struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
@@ -241,7 +783,9 @@ type ComposeManifests struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
 	ObjectReference `yaml:",inline"`
 	// Embedded fields due to inline allOf schema
-	Manifests []interface{} `json:"manifests"`
+	// Digests of the manifests, in the same order as the manifests.
+	ManifestDigests *[]ManifestDigest `json:"manifest_digests,omitempty"`
+	Manifests       []interface{}     `json:"manifests"`
 }
 
 // ComposeMetadata defines model for ComposeMetadata.
@@ -249,6 +793,13 @@ type ComposeMetadata struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
 	ObjectReference `yaml:",inline"`
 	// Embedded fields due to inline allOf schema
+	Checksums *ChecksumManifest `json:"checksums,omitempty"`
+
+	// Results of the rules of the OpenSCAP remediation run when the image
+	// was built, as reported by oscap. A rule which was remediated has the
+	// result of its remediation.
+	Openscap *OpenSCAPReport `json:"openscap,omitempty"`
+
 	// ID (hash) of the built commit
 	OstreeCommit *string `json:"ostree_commit,omitempty"`
 
@@ -256,13 +807,85 @@ type ComposeMetadata struct {
 	Packages *[]PackageMetadata `json:"packages,omitempty"`
 }
 
+// ComposeOSTreeMirror defines model for ComposeOSTreeMirror.
+type ComposeOSTreeMirror struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// Checksum of the pushed commit
+	Checksum *string             `json:"checksum,omitempty"`
+	Error    *ComposeStatusError `json:"error,omitempty"`
+	Ref      *string             `json:"ref,omitempty"`
+	Status   ComposeStatusValue  `json:"status"`
+
+	// URL of the repository the commit was pushed to
+	Url *string `json:"url,omitempty"`
+}
+
 // ComposeRequest defines model for ComposeRequest.
 type ComposeRequest struct {
-	Customizations *Customizations `json:"customizations,omitempty"`
-	Distribution   string          `json:"distribution"`
-	ImageRequest   *ImageRequest   `json:"image_request,omitempty"`
-	ImageRequests  *[]ImageRequest `json:"image_requests,omitempty"`
-	Koji           *Koji           `json:"koji,omitempty"`
+	// Blueprint fragments configured on the server, e.g. baselines of
+	// the platform. Their customizations are merged in order, and then
+	// the ones of the request. Lists are concatenated and objects
+	// merged, other values have to be the same in all of them. The
+	// compose request is stored with the merged customizations.
+	BlueprintFragments *[]string `json:"blueprint_fragments,omitempty"`
+
+	// Publishes a SHA256SUMS file covering all the artifacts of the compose
+	// next to them, it's listed in the metadata of the compose. Only
+	// supported for uploads to S3.
+	ChecksumManifest *ChecksumManifestOptions `json:"checksum_manifest,omitempty"`
+	Customizations   *Customizations          `json:"customizations,omitempty"`
+	Distribution     string                   `json:"distribution"`
+	ImageRequest     *ImageRequest            `json:"image_request,omitempty"`
+
+	// Images built from the same blueprint, e.g. for different
+	// architectures. Their statuses are reported in the order of the
+	// requests, as part of a single compose.
+	ImageRequests *[]ImageRequest `json:"image_requests,omitempty"`
+	Koji          *Koji           `json:"koji,omitempty"`
+
+	// Generates the manifests with a seed derived from the request
+	// instead of a random one, so identical requests result in
+	// identical images, e.g. with the same filesystem UUIDs. When the
+	// server deduplicates builds, identical builds of the tenant
+	// finished recently are reused instead of building them again.
+	Reproducible *bool `json:"reproducible,omitempty"`
+
+	// Scans the packages of the image for known vulnerabilities after
+	// it's built, the findings are available at
+	// `/composes/{id}/vulnerabilities`. Not supported for koji builds.
+	ScanVulnerabilities *bool `json:"scan_vulnerabilities,omitempty"`
+
+	// Signs the artifacts with the GPG key of the signing workers after
+	// they're uploaded, the signatures are published next to them and
+	// listed at `/composes/{id}/signatures`. Only supported for uploads
+	// to S3.
+	SignArtifacts *bool `json:"sign_artifacts,omitempty"`
+
+	// Maximum duration of the compose in seconds. When the deadline
+	// passes, the compose is reported as timed out. Images which
+	// haven't started building are skipped, images which are still
+	// building get 10 minutes to finish the upload targets they are
+	// working on. Images and upload targets which were already
+	// finished keep their results, Koji builds import the images which
+	// were uploaded in time.
+	Timeout *int `json:"timeout,omitempty"`
+
+	// Builds the images on the worker with this ID only, bypassing the
+	// normal routing of jobs, e.g. to reproduce a failure specific to
+	// a worker. Only available to administrators.
+	WorkerId *string `json:"worker_id,omitempty"`
+}
+
+// ComposeSignatures defines model for ComposeSignatures.
+type ComposeSignatures struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Error      *ComposeStatusError `json:"error,omitempty"`
+	Signatures []ArtifactSignature `json:"signatures"`
+	Status     ComposeStatusValue  `json:"status"`
 }
 
 // ComposeStatus defines model for ComposeStatus.
@@ -281,30 +904,111 @@ type ComposeStatusError struct {
 	Details *interface{} `json:"details,omitempty"`
 	Id      int          `json:"id"`
 	Reason  string       `json:"reason"`
+
+	// Set if the error is most likely transient, for example because the upload
+	// target throttled the requests, and the compose can be retried as is.
+	Retryable *bool `json:"retryable,omitempty"`
 }
 
 // ComposeStatusValue defines model for ComposeStatusValue.
 type ComposeStatusValue string
 
+// ComposeUpgradeIssue defines model for ComposeUpgradeIssue.
+type ComposeUpgradeIssue struct {
+	// Index of the image request the issue applies to
+	ImageRequest *int   `json:"image_request,omitempty"`
+	Reason       string `json:"reason"`
+
+	// Errors prevent the compose from being built for the new
+	// distribution, warnings should be reviewed before submitting it.
+	Severity ComposeUpgradeIssueSeverity `json:"severity"`
+}
+
+// Errors prevent the compose from being built for the new
+// distribution, warnings should be reviewed before submitting it.
+type ComposeUpgradeIssueSeverity string
+
+// ComposeUpgradeRequest defines model for ComposeUpgradeRequest.
+type ComposeUpgradeRequest struct {
+	// The distribution the compose is rebuilt for
+	Distribution string `json:"distribution"`
+
+	// Submit the upgraded compose when no incompatibilities were found.
+	Submit *bool `json:"submit,omitempty"`
+}
+
+// ComposeUpgradeResponse defines model for ComposeUpgradeResponse.
+type ComposeUpgradeResponse struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// Whether the compose can be built for the new distribution
+	Compatible bool `json:"compatible"`
+
+	// ID of the upgraded compose, set only if it was submitted
+	ComposeId      *string               `json:"compose_id,omitempty"`
+	ComposeRequest ComposeRequest        `json:"compose_request"`
+	Issues         []ComposeUpgradeIssue `json:"issues"`
+}
+
+// ComposeVulnerabilities defines model for ComposeVulnerabilities.
+type ComposeVulnerabilities struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Error           *ComposeStatusError `json:"error,omitempty"`
+	Status          ComposeStatusValue  `json:"status"`
+	Vulnerabilities []Vulnerability     `json:"vulnerabilities"`
+}
+
 // Container defines model for Container.
 type Container struct {
+	// Credentials of the registry the container is pulled from. They are
+	// used to resolve the container and to pull it during the build.
+	Auth *ContainerAuth `json:"auth,omitempty"`
+
 	// Name to use for the container from the image
 	Name *string `json:"name,omitempty"`
 
-	// Reference to the container to embed
+	// Reference to the container to embed, optionally with a tag or a
+	// digest. The latest tag is used if none of them is given.
 	Source string `json:"source"`
 
 	// Control TLS verifification
 	TlsVerify *bool `json:"tls_verify,omitempty"`
 }
 
+// Credentials of the registry the container is pulled from. They are
+// used to resolve the container and to pull it during the build.
+type ContainerAuth struct {
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
 // ContainerUploadOptions defines model for ContainerUploadOptions.
 type ContainerUploadOptions struct {
 	// Name for the created container image
 	Name *string `json:"name,omitempty"`
 
+	// Password or token of the registry, only used if the name includes
+	// the domain of the registry
+	Password *string `json:"password,omitempty"`
+
+	// Sign the container with the cosign key of the worker, the
+	// signature and an SPDX SBOM of the container are attached to it
+	// in the registry
+	Sign *bool `json:"sign,omitempty"`
+
 	// Tag for the created container image
 	Tag *string `json:"tag,omitempty"`
+
+	// Additional tags the container is pushed with, e.g. latest next to
+	// the version tag
+	Tags *[]ContainerUploadTag `json:"tags,omitempty"`
+
+	// Username of the registry, only used if the name includes the
+	// domain of the registry
+	Username *string `json:"username,omitempty"`
 }
 
 // ContainerUploadStatus defines model for ContainerUploadStatus.
@@ -312,62 +1016,165 @@ type ContainerUploadStatus struct {
 	// Digest of the manifest of the uploaded container on the registry
 	Digest string `json:"digest"`
 
+	// All pushed references of the container, starting with url
+	References *[]string `json:"references,omitempty"`
+
+	// Reference of the SPDX SBOM attached to the signed container
+	Sbom *string `json:"sbom,omitempty"`
+
+	// The container was signed with cosign
+	Signed *bool `json:"signed,omitempty"`
+
 	// FQDN of the uploaded image
 	Url string `json:"url"`
 }
 
-// CustomRepository defines model for CustomRepository.
+// ContainerUploadTag defines model for ContainerUploadTag.
+type ContainerUploadTag struct {
+	// Repository the tag is pushed to, defaults to the name of the
+	// container
+	Name *string `json:"name,omitempty"`
+	Tag  string  `json:"tag"`
+}
+
+// Repository written to the image. At least one of the 'baseurl',
+// 'mirrorlist' and 'metalink' properties has to be specified.
 type CustomRepository struct {
 	Baseurl      *[]string `json:"baseurl,omitempty"`
 	CheckGpg     *bool     `json:"check_gpg,omitempty"`
 	CheckRepoGpg *bool     `json:"check_repo_gpg,omitempty"`
 	Enabled      *bool     `json:"enabled,omitempty"`
-	Filename     *string   `json:"filename,omitempty"`
-	Gpgkey       *[]string `json:"gpgkey,omitempty"`
-	Id           string    `json:"id"`
-	Metalink     *string   `json:"metalink,omitempty"`
-	Mirrorlist   *string   `json:"mirrorlist,omitempty"`
-	Name         *string   `json:"name,omitempty"`
-	Priority     *int      `json:"priority,omitempty"`
-	SslVerify    *bool     `json:"ssl_verify,omitempty"`
+
+	// Name of the .repo file the repository is written to, defaults to
+	// the ID. Repositories with the same filename share the file.
+	Filename *string `json:"filename,omitempty"`
+
+	// GPG keys of the repository, as URLs or ASCII armored public keys.
+	// The keys are written to /etc/pki/rpm-gpg of the image and the
+	// repository refers to them.
+	Gpgkey *[]string `json:"gpgkey,omitempty"`
+
+	// ID of the repository, unique among the repositories
+	Id         string  `json:"id"`
+	Metalink   *string `json:"metalink,omitempty"`
+	Mirrorlist *string `json:"mirrorlist,omitempty"`
+	Name       *string `json:"name,omitempty"`
+	Priority   *int    `json:"priority,omitempty"`
+	SslVerify  *bool   `json:"ssl_verify,omitempty"`
 }
 
 // Customizations defines model for Customizations.
 type Customizations struct {
-	Containers *[]Container `json:"containers,omitempty"`
-
-	// Extra repositories for packages specified in customizations. These
-	// repositories will be used to depsolve and retrieve packages. Additionally,
-	// these packages will be saved and imported to the `/etc/yum.repos.d/` directory
-	// on the image
+	// CA certificates added to the trust store of the system. They are
+	// installed as ca-trust anchors and the trust store is extracted on the
+	// first boot. The ca-certificates package has to be part of the image.
+	Cacerts    *CACertsCustomization `json:"cacerts,omitempty"`
+	Containers *[]Container          `json:"containers,omitempty"`
+
+	// Repositories written to the `/etc/yum.repos.d/` directory of the
+	// image, so packages can be installed from them once it runs. Unlike
+	// the payload repositories, they aren't used to depsolve the packages
+	// of the image.
 	CustomRepositories *[]CustomRepository `json:"custom_repositories,omitempty"`
 	Directories        *[]Directory        `json:"directories,omitempty"`
-
-	// FIDO device onboard configuration
-	Fdo        *FDO          `json:"fdo,omitempty"`
-	Files      *[]File       `json:"files,omitempty"`
+	Disk               *Disk               `json:"disk,omitempty"`
+
+	// Configuration of dracut written to
+	// /etc/dracut.conf.d/99-customizations.conf, it's used whenever an
+	// initramfs is generated. The initramfs of the kernel of the image is
+	// generated before, so it's only used for the kernels installed
+	// later. The image types have to support the files customization.
+	Dracut *Dracut `json:"dracut,omitempty"`
+
+	// Packages to keep out of the image, by name or glob, the way dnf
+	// --exclude does. They aren't pulled in as dependencies or weak
+	// dependencies either, so the depsolve fails if a package of the
+	// image requires one of them.
+	ExcludePackages *[]string `json:"exclude_packages,omitempty"`
+
+	// FIDO device onboard configuration, the device is initialized by the
+	// manufacturing server when it's installed. Only supported by the
+	// edge-simplified-installer and iot-simplified-installer image types.
+	// The manufacturing server URL and exactly one of the diun_pub_key
+	// options have to be set, it's how the device verifies the
+	// manufacturing server.
+	Fdo   *FDO    `json:"fdo,omitempty"`
+	Files *[]File `json:"files,omitempty"`
+
+	// Additional mountpoints and the minimum sizes of their filesystems.
+	// Depending on the partitioning mode and on the base partition table
+	// of the image type, a mountpoint is created as a partition, an LVM
+	// logical volume or, on image types with a btrfs root filesystem, a
+	// btrfs subvolume. The mountpoints are validated against the policy
+	// of the image type before the compose is started.
 	Filesystem *[]Filesystem `json:"filesystem,omitempty"`
 
-	// Firewalld configuration
+	// Firewalld configuration. The firewalld package has to be part of the
+	// image, either by default or as one of the requested packages.
 	Firewall *FirewallCustomization `json:"firewall,omitempty"`
 
 	// List of groups to create
 	Group *[]Group `json:"group,omitempty"`
 
-	// Configures the hostname
+	// Configures the hostname, it has to be a valid host name according
+	// to RFC 1123
 	Hostname *string `json:"hostname,omitempty"`
 
-	// Ignition configuration
+	// Keeps the copies of the image from sharing the identities of the
+	// image, each option can be enabled on its own. The SSH host keys and
+	// the subscription are removed early at the first boot of each copy,
+	// before sshd generates new host keys and the system is registered.
+	Identity *Identity `json:"identity,omitempty"`
+
+	// Ignition configuration provisioning the system on its first boot.
+	// Only supported by the edge-raw-image, edge-simplified-installer,
+	// iot-raw-image and iot-simplified-installer image types. Either the
+	// embedded config or the firstboot URL can be set, not both.
 	Ignition *Ignition `json:"ignition,omitempty"`
 
+	// Install the weak dependencies (Recommends and Supplements) of the
+	// packages of the image. Disable it to build minimal images.
+	InstallWeakDeps *bool `json:"install_weak_deps,omitempty"`
+
 	// Name of the installation device, currently only useful for the edge-simplified-installer type
 	InstallationDevice *string `json:"installation_device,omitempty"`
-	Kernel             *Kernel `json:"kernel,omitempty"`
+
+	// Kernel of the image. Only image types which boot can customize the
+	// kernel, the customization is validated against the image type and
+	// the architecture before the compose is started.
+	Kernel *Kernel `json:"kernel,omitempty"`
 
 	// Locale configuration
-	Locale   *Locale   `json:"locale,omitempty"`
+	Locale *Locale `json:"locale,omitempty"`
+
+	// DNF modules used when the packages of the image are depsolved. A
+	// module can either be enabled or disabled, and only one stream of a
+	// module can be enabled.
+	Modules *Modules `json:"modules,omitempty"`
+
+	// NetworkManager connection profiles installed into the image, the
+	// image types have to support the files customization.
+	NetworkConnections *[]NetworkConnection `json:"network_connections,omitempty"`
+
+	// NFS and CIFS shares mounted by systemd, the image types have to
+	// support the files customization.
+	NetworkMounts *[]NetworkMount `json:"network_mounts,omitempty"`
+
+	// Configuration of chrony, which replaces /etc/chrony.conf of the image.
+	// The chrony package has to be part of the image. It can't be combined
+	// with the ntpservers of the timezone customization.
+	Ntp      *NTP      `json:"ntp,omitempty"`
 	Openscap *OpenSCAP `json:"openscap,omitempty"`
-	Packages *[]string `json:"packages,omitempty"`
+
+	// The ostree remote the deployments of the commit are updated from. Its
+	// configuration and GPG key are written to /etc/ostree/remotes.d of the
+	// commit, so only the edge-commit, edge-container, iot-commit and
+	// iot-container image types support it. The images deploying the
+	// commit use the remote then. RHEL images deploying the commit from an
+	// ostree URL configure a remote of the same name themselves, use
+	// another name for them.
+	OstreeRemote *OSTreeRemote `json:"ostree_remote,omitempty"`
+	Packages     *[]string     `json:"packages,omitempty"`
 
 	// Select how the disk image will be partitioned. 'auto-lvm' will use raw unless
 	// there are one or more mountpoints in which case it will use LVM. 'lvm' always
@@ -383,13 +1190,29 @@ type Customizations struct {
 	PayloadRepositories *[]Repository `json:"payload_repositories,omitempty"`
 	Services            *Services     `json:"services,omitempty"`
 
+	// Configuration of sshd written to
+	// /etc/ssh/sshd_config.d/01-customizations.conf, it takes precedence
+	// over the configuration of the image. Not supported by RHEL 7 and 8,
+	// their sshd doesn't read the directory. The users with SSH keys have
+	// to be able to log in with them.
+	Sshd *Sshd `json:"sshd,omitempty"`
+
 	// List of ssh keys
 	Sshkey       *[]SSHKey     `json:"sshkey,omitempty"`
 	Subscription *Subscription `json:"subscription,omitempty"`
 
+	// Kernel parameters set at boot, written to
+	// /etc/sysctl.d/99-customizations.conf. The image types have to
+	// support the files customization.
+	Sysctl *[]Sysctl `json:"sysctl,omitempty"`
+
 	// Timezone configuration
 	Timezone *Timezone `json:"timezone,omitempty"`
-	Users    *[]User   `json:"users,omitempty"`
+
+	// Rules written to /etc/udev/rules.d/99-customizations.rules, the
+	// image types have to support the files customization.
+	UdevRules *[]UdevRule `json:"udev_rules,omitempty"`
+	Users     *[]User     `json:"users,omitempty"`
 }
 
 // Select how the disk image will be partitioned. 'auto-lvm' will use raw unless
@@ -416,6 +1239,38 @@ type Directory struct {
 	User *interface{} `json:"user,omitempty"`
 }
 
+// Disk defines model for Disk.
+type Disk struct {
+	// Minimum size of the disk in bytes. The disk is still grown to fit
+	// the size of the image request and the filesystems.
+	Minsize *uint64 `json:"minsize,omitempty"`
+}
+
+// Configuration of dracut written to
+// /etc/dracut.conf.d/99-customizations.conf, it's used whenever an
+// initramfs is generated. The initramfs of the kernel of the image is
+// generated before, so it's only used for the kernels installed
+// later. The image types have to support the files customization.
+type Dracut struct {
+	// Kernel modules added to the initramfs
+	AddDrivers *[]string `json:"add_drivers,omitempty"`
+
+	// Dracut modules added to the initramfs
+	AddModules *[]string `json:"add_modules,omitempty"`
+
+	// Kernel modules added to the initramfs and loaded early at boot
+	ForceDrivers *[]string `json:"force_drivers,omitempty"`
+
+	// Files of the image copied into the initramfs
+	InstallItems *[]string `json:"install_items,omitempty"`
+
+	// Kernel modules left out of the initramfs
+	OmitDrivers *[]string `json:"omit_drivers,omitempty"`
+
+	// Dracut modules left out of the initramfs
+	OmitModules *[]string `json:"omit_modules,omitempty"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
@@ -435,19 +1290,36 @@ type ErrorList struct {
 	Items []Error `json:"items"`
 }
 
-// FIDO device onboard configuration
+// FIDO device onboard configuration, the device is initialized by the
+// manufacturing server when it's installed. Only supported by the
+// edge-simplified-installer and iot-simplified-installer image types.
+// The manufacturing server URL and exactly one of the diun_pub_key
+// options have to be set, it's how the device verifies the
+// manufacturing server.
 type FDO struct {
-	DiunPubKeyHash         *string `json:"diun_pub_key_hash,omitempty"`
-	DiunPubKeyInsecure     *string `json:"diun_pub_key_insecure,omitempty"`
+	// Hash of the key of the manufacturing server
+	DiunPubKeyHash *string `json:"diun_pub_key_hash,omitempty"`
+
+	// Don't verify the key of the manufacturing server
+	DiunPubKeyInsecure *string `json:"diun_pub_key_insecure,omitempty"`
+
+	// PEM encoded CA certificates the certificate of the manufacturing
+	// server has to be signed by
 	DiunPubKeyRootCerts    *string `json:"diun_pub_key_root_certs,omitempty"`
 	ManufacturingServerUrl *string `json:"manufacturing_server_url,omitempty"`
 }
 
-// A custom file to create in the final artifact.
+// A custom file to create in the final artifact. Only some paths are
+// allowed, e.g. in /etc, the path is validated against the policy of
+// the image type before the compose is started.
 type File struct {
-	// Contents of the file as plain text
+	// Contents of the file, encoded as set by data_encoding
 	Data *string `json:"data,omitempty"`
 
+	// Encoding of the data, base64 allows to create files with binary
+	// content
+	DataEncoding *FileDataEncoding `json:"data_encoding,omitempty"`
+
 	// Ensure that the parent directories exist
 	EnsureParents *bool `json:"ensure_parents,omitempty"`
 
@@ -464,6 +1336,10 @@ type File struct {
 	User *interface{} `json:"user,omitempty"`
 }
 
+// Encoding of the data, base64 allows to create files with binary
+// content
+type FileDataEncoding string
+
 // Filesystem defines model for Filesystem.
 type Filesystem struct {
 	// size of the filesystem in bytes
@@ -471,7 +1347,8 @@ type Filesystem struct {
 	Mountpoint string `json:"mountpoint"`
 }
 
-// Firewalld configuration
+// Firewalld configuration. The firewalld package has to be part of the
+// image, either by default or as one of the requested packages.
 type FirewallCustomization struct {
 	// List of ports (or port ranges) and protocols to open
 	Ports *[]string `json:"ports,omitempty"`
@@ -484,19 +1361,64 @@ type FirewallCustomization struct {
 		// List of services to enable
 		Enabled *[]string `json:"enabled,omitempty"`
 	} `json:"services,omitempty"`
+
+	// Firewalld zones to bind sources to
+	Zones *[]FirewallZone `json:"zones,omitempty"`
+}
+
+// FirewallZone defines model for FirewallZone.
+type FirewallZone struct {
+	// Name of the zone
+	Name string `json:"name"`
+
+	// Sources to bind to the zone, e.g. addresses or networks
+	Sources *[]string `json:"sources,omitempty"`
+}
+
+// Export the imported Compute Engine image to a gzipped tar archive in a
+// Cloud Storage bucket, so it can be downloaded. The export runs in
+// Cloud Build once the image is imported.
+type GCPImageExportOptions struct {
+	// Name of an existing bucket the image is exported to
+	Bucket string `json:"bucket"`
+
+	// Name of the exported object, defaults to the image name with a
+	// '.tar.gz' extension.
+	Object *string `json:"object,omitempty"`
 }
 
 // GCPUploadOptions defines model for GCPUploadOptions.
 type GCPUploadOptions struct {
+	// Architecture of the image, the architecture of the image request
+	// if not specified.
+	Architecture *GCPUploadOptionsArchitecture `json:"architecture,omitempty"`
+
 	// Name of an existing STANDARD Storage class Bucket.
 	Bucket *string `json:"bucket,omitempty"`
 
+	// Export the imported Compute Engine image to a gzipped tar archive in a
+	// Cloud Storage bucket, so it can be downloaded. The export runs in
+	// Cloud Build once the image is imported.
+	Export *GCPImageExportOptions `json:"export,omitempty"`
+
+	// Image family the image is added to
+	Family *string `json:"family,omitempty"`
+
+	// Guest OS features of the image, in addition to the ones the
+	// distribution of the image is known to support. UEFI_COMPATIBLE
+	// allows creating Shielded VM instances from the image. See
+	// https://cloud.google.com/compute/docs/images/create-custom#guest-os-features
+	GuestOsFeatures *[]string `json:"guest_os_features,omitempty"`
+
 	// The name to use for the imported and shared Compute Engine image.
 	// The image name must be unique within the GCP project, which is used
 	// for the OS image upload and import. If not specified a random
 	// 'composer-api-<uuid>' string is used as the image name.
 	ImageName *string `json:"image_name,omitempty"`
 
+	// URLs of the licenses applying to the image
+	Licenses *[]string `json:"licenses,omitempty"`
+
 	// The GCP region where the OS image will be imported to and shared from.
 	// The value must be a valid GCP location. See https://cloud.google.com/storage/docs/locations.
 	// If not specified, the multi-region location closest to the source
@@ -518,6 +1440,10 @@ type GCPUploadOptions struct {
 	ShareWithAccounts *[]string `json:"share_with_accounts,omitempty"`
 }
 
+// Architecture of the image, the architecture of the image request
+// if not specified.
+type GCPUploadOptionsArchitecture string
+
 // GCPUploadStatus defines model for GCPUploadStatus.
 type GCPUploadStatus struct {
 	ImageName string `json:"image_name"`
@@ -533,26 +1459,126 @@ type Group struct {
 	Name string `json:"name"`
 }
 
-// Ignition configuration
+// Uploads the image with a PUT request to a directory of an HTTP(S)
+// server, e.g. a raw repository of Nexus or Artifactory, or a WebDAV
+// collection.
+type HTTPUploadOptions struct {
+	// Name of the uploaded file. If not specified a random
+	// 'composer-api-<uuid>-<filename of the image>' string is used.
+	Filename *string `json:"filename,omitempty"`
+
+	// Headers added to the requests, e.g. API keys. They aren't kept
+	// with the compose, neither is the user of the URL.
+	Headers *HTTPUploadOptions_Headers `json:"headers,omitempty"`
+
+	// Password of the basic authentication
+	Password *string `json:"password,omitempty"`
+
+	// URL of the directory the image is uploaded to
+	Url string `json:"url"`
+
+	// Username of the basic authentication
+	Username *string `json:"username,omitempty"`
+
+	// Create the missing WebDAV collections of the URL with MKCOL
+	// before the upload
+	Webdav *bool `json:"webdav,omitempty"`
+}
+
+// Headers added to the requests, e.g. API keys. They aren't kept
+// with the compose, neither is the user of the URL.
+type HTTPUploadOptions_Headers struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// HTTPUploadStatus defines model for HTTPUploadStatus.
+type HTTPUploadStatus struct {
+	Url string `json:"url"`
+}
+
+// Imports the image as a snapshot to the Hetzner Cloud project of the
+// worker. The image is written to the disk of a temporary server, which
+// is deleted once the snapshot is created.
+type HetznerUploadOptions struct {
+	// Datacenter of the temporary server, mutually exclusive with
+	// location.
+	Datacenter *string `json:"datacenter,omitempty"`
+
+	// Description of the snapshot. If not specified a random
+	// 'composer-api-<uuid>' string is used.
+	ImageName *string `json:"image_name,omitempty"`
+
+	// Labels of the snapshot
+	Labels *HetznerUploadOptions_Labels `json:"labels,omitempty"`
+
+	// Location of the temporary server, mutually exclusive with
+	// datacenter. Hetzner Cloud picks one if neither is set.
+	Location *string `json:"location,omitempty"`
+
+	// Type of the temporary server, its disk must be large enough for
+	// the image. Defaults to cx22 for x86_64 and cax11 for aarch64
+	// images.
+	ServerType *string `json:"server_type,omitempty"`
+}
+
+// Labels of the snapshot
+type HetznerUploadOptions_Labels struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// HetznerUploadStatus defines model for HetznerUploadStatus.
+type HetznerUploadStatus struct {
+	// ID of the snapshot the image was imported as, servers are created
+	// from it with this ID as their image
+	ImageId int64 `json:"image_id"`
+}
+
+// Keeps the copies of the image from sharing the identities of the
+// image, each option can be enabled on its own. The SSH host keys and
+// the subscription are removed early at the first boot of each copy,
+// before sshd generates new host keys and the system is registered.
+type Identity struct {
+	// Empty /etc/machine-id, systemd generates a new one at boot
+	ClearMachineId *bool `json:"clear_machine_id,omitempty"`
+
+	// Remove the SSH host keys
+	RemoveSshHostKeys *bool `json:"remove_ssh_host_keys,omitempty"`
+
+	// Remove the consumer and entitlement certificates of RHSM and the
+	// machine-id of insights-client
+	RemoveSubscription *bool `json:"remove_subscription,omitempty"`
+}
+
+// Ignition configuration provisioning the system on its first boot.
+// Only supported by the edge-raw-image, edge-simplified-installer,
+// iot-raw-image and iot-simplified-installer image types. Either the
+// embedded config or the firstboot URL can be set, not both.
 type Ignition struct {
-	Embedded  *IgnitionEmbedded  `json:"embedded,omitempty"`
+	// Ignition config embedded into the image
+	Embedded *IgnitionEmbedded `json:"embedded,omitempty"`
+
+	// Ignition config fetched from a URL on the first boot
 	Firstboot *IgnitionFirstboot `json:"firstboot,omitempty"`
 }
 
-// IgnitionEmbedded defines model for IgnitionEmbedded.
+// Ignition config embedded into the image
 type IgnitionEmbedded struct {
+	// The Ignition config (JSON), base64 encoded
 	Config string `json:"config"`
 }
 
-// IgnitionFirstboot defines model for IgnitionFirstboot.
+// Ignition config fetched from a URL on the first boot
 type IgnitionFirstboot struct {
-	// Provisioning URL
+	// Provisioning URL the Ignition config is fetched from
 	Url string `json:"url"`
 }
 
 // ImageRequest defines model for ImageRequest.
 type ImageRequest struct {
-	Architecture string       `json:"architecture"`
+	Architecture string `json:"architecture"`
+
+	// The live-installer type only supports the packages customization,
+	// requests with other customizations are rejected.
 	ImageType    ImageTypes   `json:"image_type"`
 	Ostree       *OSTree      `json:"ostree,omitempty"`
 	Repositories []Repository `json:"repositories"`
@@ -574,10 +1600,16 @@ type ImageRequest struct {
 	// different targets as well as multiple targets of the same kind are
 	// supported.
 	UploadTargets *[]UploadTarget `json:"upload_targets,omitempty"`
+
+	// Options of the wsl image type, applied to the archive of its root
+	// file system
+	Wsl *WSLOptions `json:"wsl,omitempty"`
 }
 
 // ImageStatus defines model for ImageStatus.
 type ImageStatus struct {
+	// Architecture of the image, set for composes of several images.
+	Arch           *string             `json:"arch,omitempty"`
 	Error          *ComposeStatusError `json:"error,omitempty"`
 	Status         ImageStatusValue    `json:"status"`
 	UploadStatus   *UploadStatus       `json:"upload_status,omitempty"`
@@ -587,15 +1619,20 @@ type ImageStatus struct {
 // ImageStatusValue defines model for ImageStatusValue.
 type ImageStatusValue string
 
-// ImageTypes defines model for ImageTypes.
+// The live-installer type only supports the packages customization,
+// requests with other customizations are rejected.
 type ImageTypes string
 
-// Kernel defines model for Kernel.
+// Kernel of the image. Only image types which boot can customize the
+// kernel, the customization is validated against the image type and
+// the architecture before the compose is started.
 type Kernel struct {
-	// Appends arguments to the bootloader kernel command line
+	// Appends arguments to the bootloader kernel command line. Most
+	// ostree image types don't support it.
 	Append *string `json:"append,omitempty"`
 
-	// Name of the kernel to use
+	// Name of the kernel package to use, e.g. kernel-rt or kernel-64k.
+	// kernel-64k is only available on aarch64.
 	Name *string `json:"name,omitempty"`
 }
 
@@ -604,8 +1641,12 @@ type Koji struct {
 	Name    string `json:"name"`
 	Release string `json:"release"`
 	Server  string `json:"server"`
-	TaskId  int    `json:"task_id"`
-	Version string `json:"version"`
+
+	// Koji tag the build is tagged into once it's imported, it has to
+	// be allowed for the server in the configuration of composer.
+	Tag     *string `json:"tag,omitempty"`
+	TaskId  int     `json:"task_id"`
+	Version string  `json:"version"`
 }
 
 // KojiLogs defines model for KojiLogs.
@@ -619,6 +1660,43 @@ type KojiStatus struct {
 	BuildId *int `json:"build_id,omitempty"`
 }
 
+// The domain booting the imported volume
+type LibvirtDomainOptions struct {
+	MemoryMib *int   `json:"memory_mib,omitempty"`
+	Name      string `json:"name"`
+
+	// Define a persistent domain, transient domains disappear once
+	// they're shut down
+	Persistent *bool `json:"persistent,omitempty"`
+	Vcpus      *int  `json:"vcpus,omitempty"`
+}
+
+// Imports the image as a volume to a libvirt host and optionally boots
+// a domain from it. Meant for development, the target is only available
+// for the hosts enabled in the configuration of the worker.
+type LibvirtUploadOptions struct {
+	// The domain booting the imported volume
+	Domain *LibvirtDomainOptions `json:"domain,omitempty"`
+
+	// Storage pool the volume is created in
+	Pool *string `json:"pool,omitempty"`
+
+	// Connection URI of the libvirt host, the default one of the worker
+	// if not specified
+	Uri *string `json:"uri,omitempty"`
+
+	// Name of the volume. If not specified a random
+	// 'composer-api-<uuid>-<filename of the image>' string is used.
+	VolumeName *string `json:"volume_name,omitempty"`
+}
+
+// LibvirtUploadStatus defines model for LibvirtUploadStatus.
+type LibvirtUploadStatus struct {
+	// Name of the started domain, if any
+	DomainName *string `json:"domain_name,omitempty"`
+	VolumePath string  `json:"volume_path"`
+}
+
 // List defines model for List.
 type List struct {
 	Kind  string `json:"kind"`
@@ -637,21 +1715,269 @@ type LocalUploadOptions struct {
 
 // Locale configuration
 type Locale struct {
-	// Sets the keyboard layout
+	// Sets the keyboard layout, the name of a console keymap
 	Keyboard *string `json:"keyboard,omitempty"`
 
 	// List of locales to be installed, the first one becomes primary, subsequent ones are secondary
 	Languages *[]string `json:"languages,omitempty"`
 }
 
+// ManifestDigest defines model for ManifestDigest.
+type ManifestDigest struct {
+	// SHA256 digest of the manifest serialized as compact JSON, as it is
+	// returned in the manifests array.
+	Digest string `json:"digest"`
+
+	// SHA256 digest of the public key the signature can be verified
+	// with. Only set if the manifest is signed.
+	KeyId *string `json:"key_id,omitempty"`
+
+	// Base64 encoded ed25519 signature of the manifest serialized as
+	// compact JSON. Only set if composer is configured with a manifest
+	// signing key.
+	Signature *string `json:"signature,omitempty"`
+}
+
+// MarketplacePublishResponse defines model for MarketplacePublishResponse.
+type MarketplacePublishResponse struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Id string `json:"id"`
+}
+
+// MarketplacePublishStatus defines model for MarketplacePublishStatus.
+type MarketplacePublishStatus struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// The published AMI in us-east-1
+	Ami          *string `json:"ami,omitempty"`
+	ChangeSetArn *string `json:"change_set_arn,omitempty"`
+	ChangeSetId  *string `json:"change_set_id,omitempty"`
+
+	// Status of the change set in AWS Marketplace, PREPARING,
+	// APPLYING, SUCCEEDED, CANCELLED or FAILED.
+	ChangeSetStatus *string             `json:"change_set_status,omitempty"`
+	Error           *ComposeStatusError `json:"error,omitempty"`
+	Status          UploadStatusValue   `json:"status"`
+}
+
+// Simulates an upload without uploading the image anywhere. Only
+// available if enabled in the configuration of composer, meant for
+// testing and integration environments.
+type MockUploadOptions struct {
+	// Probability of the simulated upload failing, the default of the
+	// worker if not set
+	FailureRate *float32 `json:"failure_rate,omitempty"`
+
+	// Duration of the simulated upload in seconds, the default of the
+	// worker if not set
+	Latency *int `json:"latency,omitempty"`
+}
+
+// MockUploadStatus defines model for MockUploadStatus.
+type MockUploadStatus struct {
+	Url string `json:"url"`
+}
+
+// DNF modules used when the packages of the image are depsolved. A
+// module can either be enabled or disabled, and only one stream of a
+// module can be enabled.
+type Modules struct {
+	// Modules to disable, their default streams aren't used then
+	Disable *[]string `json:"disable,omitempty"`
+
+	// Module streams to enable, as name:stream
+	Enable *[]string `json:"enable,omitempty"`
+}
+
+// Configuration of chrony, which replaces /etc/chrony.conf of the image.
+// The chrony package has to be part of the image. It can't be combined
+// with the ntpservers of the timezone customization.
+type NTP struct {
+	// How leap seconds are corrected (leapsecmode of chrony). Use slew
+	// or ignore with leap-smearing servers, so the clock isn't stepped.
+	LeapSecondMode *NTPLeapSecondMode `json:"leap_second_mode,omitempty"`
+
+	// Timezone used to determine when leap seconds occur
+	LeapSecondTimezone *string     `json:"leap_second_timezone,omitempty"`
+	Servers            []NTPServer `json:"servers"`
+}
+
+// How leap seconds are corrected (leapsecmode of chrony). Use slew
+// or ignore with leap-smearing servers, so the clock isn't stepped.
+type NTPLeapSecondMode string
+
+// NTPServer defines model for NTPServer.
+type NTPServer struct {
+	Hostname string `json:"hostname"`
+
+	// Speed up the initial synchronization
+	Iburst *bool `json:"iburst,omitempty"`
+
+	// Maximum polling interval, as a power of 2 in seconds
+	Maxpoll *int `json:"maxpoll,omitempty"`
+
+	// Minimum polling interval, as a power of 2 in seconds
+	Minpoll *int `json:"minpoll,omitempty"`
+
+	// The hostname resolves to a pool of servers
+	Pool *bool `json:"pool,omitempty"`
+
+	// Prefer the server over the other ones
+	Prefer *bool `json:"prefer,omitempty"`
+}
+
+// NetworkConnection defines model for NetworkConnection.
+type NetworkConnection struct {
+	// The profile in the keyfile format, its [connection] section
+	// needs an id, unique among the profiles, and a type
+	Keyfile string `json:"keyfile"`
+
+	// Name of the profile in /etc/NetworkManager/system-connections,
+	// without the .nmconnection extension
+	Name string `json:"name"`
+}
+
+// Network share mounted with a systemd mount unit written to
+// /etc/systemd/system, named after the mount point.
+type NetworkMount struct {
+	// Mount the share on the first access with an automount unit
+	// instead of at boot
+	Automount *bool `json:"automount,omitempty"`
+
+	// Credentials of a CIFS share, written to a file in
+	// /etc/cifs-credentials only root can read. They aren't kept with the
+	// compose request, prefer accounts limited to the share.
+	Credentials *NetworkMountCredentials `json:"credentials,omitempty"`
+	Mountpoint  string                   `json:"mountpoint"`
+
+	// Mount options, _netdev is always set
+	Options *[]string `json:"options,omitempty"`
+
+	// The share, in the host:/path format for NFS and in the
+	// //host/share format for CIFS
+	Source string           `json:"source"`
+	Type   NetworkMountType `json:"type"`
+}
+
+// NetworkMountType defines model for NetworkMount.Type.
+type NetworkMountType string
+
+// Credentials of a CIFS share, written to a file in
+// /etc/cifs-credentials only root can read. They aren't kept with the
+// compose request, prefer accounts limited to the share.
+type NetworkMountCredentials struct {
+	Domain   *string `json:"domain,omitempty"`
+	Password string  `json:"password"`
+	Username string  `json:"username"`
+}
+
+// Imports the uploaded image as a Compute custom image in the
+// compartment configured on the worker. The uploaded object is deleted
+// once it's imported, the status of the upload has the OCID of the image
+// instead of a URL of the object.
+type OCIImageImportOptions struct {
+	// Shapes the image is compatible with besides the default ones
+	CompatibleShapes *[]string `json:"compatible_shapes,omitempty"`
+
+	// Display name of the image. If not specified a random
+	// 'composer-api-<uuid>' string is used.
+	ImageName *string `json:"image_name,omitempty"`
+
+	// Default launch mode of the instances of the image
+	LaunchMode *OCIImageImportOptionsLaunchMode `json:"launch_mode,omitempty"`
+}
+
+// Default launch mode of the instances of the image
+type OCIImageImportOptionsLaunchMode string
+
+// OCIImageUploadStatus defines model for OCIImageUploadStatus.
+type OCIImageUploadStatus struct {
+	// OCID of the imported Compute custom image
+	ImageId string `json:"image_id"`
+	Region  string `json:"region"`
+}
+
 // OCIUploadOptions defines model for OCIUploadOptions.
-type OCIUploadOptions map[string]interface{}
+type OCIUploadOptions struct {
+	// Imports the uploaded image as a Compute custom image in the
+	// compartment configured on the worker. The uploaded object is deleted
+	// once it's imported, the status of the upload has the OCID of the image
+	// instead of a URL of the object.
+	Import *OCIImageImportOptions `json:"import,omitempty"`
+
+	// Prefix of the name of the uploaded object, e.g. a folder of the
+	// bucket. Can't be combined with import.
+	ObjectNamePrefix *string `json:"object_name_prefix,omitempty"`
+
+	// Lifetime of the pre-authenticated request of the uploaded object,
+	// 24 hours by default. Can't be combined with import.
+	ParExpiryHours *int `json:"par_expiry_hours,omitempty"`
+
+	// Storage tier of the uploaded object, the default tier of the
+	// bucket if not specified. Archived objects can't be downloaded
+	// with a pre-authenticated request, so the Archive tier isn't
+	// supported. Can't be combined with import.
+	StorageTier *OCIUploadOptionsStorageTier `json:"storage_tier,omitempty"`
+}
+
+// Storage tier of the uploaded object, the default tier of the
+// bucket if not specified. Archived objects can't be downloaded
+// with a pre-authenticated request, so the Archive tier isn't
+// supported. Can't be combined with import.
+type OCIUploadOptionsStorageTier string
 
 // OCIUploadStatus defines model for OCIUploadStatus.
 type OCIUploadStatus struct {
+	// Native path of the object in Object Storage
+	ObjectPath *string `json:"object_path,omitempty"`
+
+	// URL of the pre-authenticated request of the object
 	Url string `json:"url"`
 }
 
+// Pushes the image to a registry as an OCI artifact, the way `oras push`
+// does. The image is the only layer of the artifact.
+type ORASUploadOptions struct {
+	// Annotations of the manifest of the artifact
+	Annotations *ORASUploadOptions_Annotations `json:"annotations,omitempty"`
+
+	// Type of the artifact set in its manifest
+	ArtifactType *string `json:"artifact_type,omitempty"`
+
+	// Media type of the layer holding the image
+	MediaType *string `json:"media_type,omitempty"`
+
+	// Repository of the artifact, the default registry of the worker is
+	// used if it doesn't include a domain
+	Name *string `json:"name,omitempty"`
+
+	// Password or token of the registry, only used if the name includes
+	// the domain of the registry
+	Password *string `json:"password,omitempty"`
+
+	// Tag of the artifact, a random UUID if not specified
+	Tag *string `json:"tag,omitempty"`
+
+	// Username of the registry, only used if the name includes the
+	// domain of the registry
+	Username *string `json:"username,omitempty"`
+}
+
+// Annotations of the manifest of the artifact
+type ORASUploadOptions_Annotations struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// ORASUploadStatus defines model for ORASUploadStatus.
+type ORASUploadStatus struct {
+	// Digest of the manifest of the artifact on the registry
+	Digest string `json:"digest"`
+	Url    string `json:"url"`
+}
+
 // OSTree defines model for OSTree.
 type OSTree struct {
 	// A URL which, if set, is used for fetching content. Implies that `url` is set as well,
@@ -669,6 +1995,46 @@ type OSTree struct {
 	Url  *string `json:"url,omitempty"`
 }
 
+// Push the ostree commit of the edge-commit and iot-commit image types
+// to a remote repository once it's uploaded. Repositories served over
+// http(s) must accept PUT requests of the files of the repository, the
+// summary of the repository isn't updated. ssh:// repositories are
+// pushed to with ostree-push, using the ssh configuration of the worker.
+type OSTreeMirrorOptions struct {
+	// Password of the http(s) basic authentication
+	Password *string `json:"password,omitempty"`
+
+	// URL of an existing archive-z2 repository
+	Url string `json:"url"`
+
+	// Username of the http(s) basic authentication
+	Username *string `json:"username,omitempty"`
+}
+
+// The ostree remote the deployments of the commit are updated from. Its
+// configuration and GPG key are written to /etc/ostree/remotes.d of the
+// commit, so only the edge-commit, edge-container, iot-commit and
+// iot-container image types support it. The images deploying the
+// commit use the remote then. RHEL images deploying the commit from an
+// ostree URL configure a remote of the same name themselves, use
+// another name for them.
+type OSTreeRemote struct {
+	// URL the content is fetched from, the url is used for the metadata
+	// then
+	ContentUrl *string `json:"content_url,omitempty"`
+
+	// ASCII armored public GPG key the commits are verified with. The
+	// commits aren't verified without it.
+	GpgKey *string `json:"gpg_key,omitempty"`
+
+	// Name of the remote, defaults to the remote the images of the
+	// distribution deploy their commit from (rhel-edge, fedora-iot)
+	Name *string `json:"name,omitempty"`
+
+	// URL of the ostree repository
+	Url string `json:"url"`
+}
+
 // ObjectReference defines model for ObjectReference.
 type ObjectReference struct {
 	Href string `json:"href"`
@@ -682,6 +2048,20 @@ type OpenSCAP struct {
 	Tailoring *OpenSCAPTailoring `json:"tailoring,omitempty"`
 }
 
+// Results of the rules of the OpenSCAP remediation run when the image
+// was built, as reported by oscap. A rule which was remediated has the
+// result of its remediation.
+type OpenSCAPReport struct {
+	Rules []OpenSCAPRuleResult `json:"rules"`
+}
+
+// OpenSCAPRuleResult defines model for OpenSCAPRuleResult.
+type OpenSCAPRuleResult struct {
+	Result string  `json:"result"`
+	RuleId string  `json:"rule_id"`
+	Title  *string `json:"title,omitempty"`
+}
+
 // OpenSCAPTailoring defines model for OpenSCAPTailoring.
 type OpenSCAPTailoring struct {
 	Selected   *[]string `json:"selected,omitempty"`
@@ -700,6 +2080,27 @@ type PackageMetadata struct {
 	Version   string  `json:"version"`
 }
 
+// Adds the image to a Pulp file repository, which is created if it
+// doesn't exist, and publishes the repository
+type PulpFileUploadOptions struct {
+	// Basepath for distributing the repository, required if the
+	// repository isn't distributed yet
+	Basepath *string `json:"basepath,omitempty"`
+
+	// Name of the file in the repository. If not specified a random
+	// 'composer-api-<uuid>-<filename of the image>' string is used.
+	Filename *string `json:"filename,omitempty"`
+
+	// File repository to add the image to
+	Repository    string  `json:"repository"`
+	ServerAddress *string `json:"server_address,omitempty"`
+}
+
+// PulpFileUploadStatus defines model for PulpFileUploadStatus.
+type PulpFileUploadStatus struct {
+	FileUrl string `json:"file_url"`
+}
+
 // PulpOSTreeUploadOptions defines model for PulpOSTreeUploadOptions.
 type PulpOSTreeUploadOptions struct {
 	// Basepath for distributing the repository
@@ -715,6 +2116,26 @@ type PulpOSTreeUploadStatus struct {
 	RepoUrl string `json:"repo_url"`
 }
 
+// Imports the image as a raw RBD image to the Ceph cluster configured
+// on the worker, e.g. one backing an OpenStack or Proxmox cluster.
+type RBDUploadOptions struct {
+	// Name of the RBD image. If not specified a random
+	// 'composer-api-<uuid>' string is used.
+	ImageName *string `json:"image_name,omitempty"`
+
+	// Namespace of the pool, the default one if not specified
+	Namespace *string `json:"namespace,omitempty"`
+
+	// Pool the image is imported to
+	Pool string `json:"pool"`
+}
+
+// RBDUploadStatus defines model for RBDUploadStatus.
+type RBDUploadStatus struct {
+	// The imported image as <pool>[/<namespace>]/<image name>
+	ImageSpec string `json:"image_spec"`
+}
+
 // Repository configuration.
 // At least one of the 'baseurl', 'mirrorlist', 'metalink' properties must
 // be specified. If more of them are specified, the order of precedence is
@@ -758,27 +2179,98 @@ type Services struct {
 	Enabled *[]string `json:"enabled,omitempty"`
 }
 
+// Configuration of sshd written to
+// /etc/ssh/sshd_config.d/01-customizations.conf, it takes precedence
+// over the configuration of the image. Not supported by RHEL 7 and 8,
+// their sshd doesn't read the directory. The users with SSH keys have
+// to be able to log in with them.
+type Sshd struct {
+	// Only the members of these groups can log in
+	AllowGroups *[]string `json:"allow_groups,omitempty"`
+
+	// Only these users can log in, the patterns can restrict the hosts
+	// they log in from
+	AllowUsers             *[]string            `json:"allow_users,omitempty"`
+	PasswordAuthentication *bool                `json:"password_authentication,omitempty"`
+	PermitRootLogin        *SshdPermitRootLogin `json:"permit_root_login,omitempty"`
+
+	// Ports sshd listens on instead of port 22. They're opened in the
+	// firewall if the request customizes it, ports other than 22 have
+	// to be allowed for sshd by the SELinux policy of the image.
+	Ports *[]int `json:"ports,omitempty"`
+}
+
+// SshdPermitRootLogin defines model for Sshd.PermitRootLogin.
+type SshdPermitRootLogin string
+
 // Subscription defines model for Subscription.
 type Subscription struct {
 	ActivationKey string `json:"activation_key"`
 	BaseUrl       string `json:"base_url"`
-	Insights      bool   `json:"insights"`
-	Organization  string `json:"organization"`
+
+	// Custom facts uploaded to the subscription service when the system
+	// is registered, written to /etc/rhsm/facts/osbuild.facts
+	Facts    *Subscription_Facts `json:"facts,omitempty"`
+	Insights bool                `json:"insights"`
+
+	// Tags the system reports to Insights with, written to
+	// /etc/insights-client/tags.yaml. Requires insights or rhc.
+	InsightsTags *Subscription_InsightsTags `json:"insights_tags,omitempty"`
+	Organization string                     `json:"organization"`
 
 	// Optional flag to use rhc to register the system, which also always enables Insights.
 	Rhc       *bool  `json:"rhc,omitempty"`
 	ServerUrl string `json:"server_url"`
 }
 
+// Custom facts uploaded to the subscription service when the system
+// is registered, written to /etc/rhsm/facts/osbuild.facts
+type Subscription_Facts struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// Tags the system reports to Insights with, written to
+// /etc/insights-client/tags.yaml. Requires insights or rhc.
+type Subscription_InsightsTags struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// Sysctl defines model for Sysctl.
+type Sysctl struct {
+	// Name of the kernel parameter, or glob. A key starting with -
+	// without a value excludes the parameters from being set by a
+	// matching glob.
+	Key   string  `json:"key"`
+	Value *string `json:"value,omitempty"`
+}
+
 // Timezone configuration
 type Timezone struct {
-	// List of ntp servers
+	// List of ntp servers, as host names or IP addresses
 	Ntpservers *[]string `json:"ntpservers,omitempty"`
 
-	// Name of the timezone, defaults to UTC
+	// Name of the timezone of the tz database, defaults to UTC
 	Timezone *string `json:"timezone,omitempty"`
 }
 
+// UdevOp defines model for UdevOp.
+type UdevOp struct {
+	// Argument of the keys which take one, like ATTR{arg}
+	Arg   *string `json:"arg,omitempty"`
+	Key   string  `json:"key"`
+	Op    string  `json:"op"`
+	Value string  `json:"value"`
+}
+
+// UdevRule defines model for UdevRule.
+type UdevRule struct {
+	// Comment written before the rule
+	Comment *string `json:"comment,omitempty"`
+
+	// Match and assignment keys of the rule
+	Ops []UdevOp `json:"ops"`
+}
+
 // Options for a given upload destination.
 // This should really be oneOf but AWSS3UploadOptions is a subset of
 // AWSEC2UploadOptions. This means that all AWSEC2UploadOptions objects
@@ -789,9 +2281,12 @@ type UploadOptions interface{}
 
 // UploadStatus defines model for UploadStatus.
 type UploadStatus struct {
-	Options interface{}       `json:"options"`
-	Status  UploadStatusValue `json:"status"`
-	Type    UploadTypes       `json:"type"`
+	// SHA256 checksum of the image artifact as it was built, before it
+	// was uploaded to the target.
+	ArtifactChecksum *string           `json:"artifact_checksum,omitempty"`
+	Options          interface{}       `json:"options"`
+	Status           UploadStatusValue `json:"status"`
+	Type             UploadTypes       `json:"type"`
 }
 
 // UploadStatusValue defines model for UploadStatusValue.
@@ -815,9 +2310,126 @@ type UploadTypes string
 
 // User defines model for User.
 type User struct {
+	// Description (GECOS) of the user
+	Description *string `json:"description,omitempty"`
+
+	// Date on which the account expires, in the YYYY-MM-DD format. It
+	// is set on the first boot of the image.
+	ExpirationDate *openapi_types.Date `json:"expiration_date,omitempty"`
+
+	// Expire the password on the first boot of the image, so the user
+	// has to change it on the first login.
+	ForcePasswordChange *bool `json:"force_password_change,omitempty"`
+
+	// ID of the primary group of the user
+	Gid *int `json:"gid,omitempty"`
+
+	// Supplementary groups of the user
 	Groups *[]string `json:"groups,omitempty"`
-	Key    *string   `json:"key,omitempty"`
-	Name   string    `json:"name"`
+
+	// Home directory of the user
+	Home *string `json:"home,omitempty"`
+	Key  *string `json:"key,omitempty"`
+	Name string  `json:"name"`
+
+	// Password of the user, either in plain text or already crypted. An
+	// empty password locks the account. Prefer crypted passwords, they
+	// end up in the image.
+	Password *string `json:"password,omitempty"`
+
+	// Login shell of the user
+	Shell *string `json:"shell,omitempty"`
+
+	// User ID of the user
+	Uid *int `json:"uid,omitempty"`
+}
+
+// Uploads the vmdk or ova image to vCenter. Either the image is imported
+// to the datastore, which requires the datacenter, cluster and datastore,
+// or it's uploaded as a new item of the content library.
+type VSphereUploadOptions struct {
+	Cluster *string `json:"cluster,omitempty"`
+
+	// Name of the content library the image is uploaded to instead of
+	// the datastore. ova images are imported as OVF templates.
+	ContentLibrary *string `json:"content_library,omitempty"`
+	Datacenter     *string `json:"datacenter,omitempty"`
+	Datastore      *string `json:"datastore,omitempty"`
+
+	// VM folder of the imported image
+	Folder *string `json:"folder,omitempty"`
+	Host   string  `json:"host"`
+
+	// Name of the imported image or of the content library item. If not
+	// specified a random 'composer-api-<uuid>' string is used.
+	ImageName *string `json:"image_name,omitempty"`
+	Password  string  `json:"password"`
+	Username  string  `json:"username"`
+}
+
+// VSphereUploadStatus defines model for VSphereUploadStatus.
+type VSphereUploadStatus struct {
+	// ID of the content library item, only set if the image was uploaded
+	// to a content library
+	ContentLibraryItemId *string `json:"content_library_item_id,omitempty"`
+}
+
+// Uploads the box of the vagrant image types as a provider of a version
+// of a box on Vagrant Cloud or a server implementing its API. The
+// version is created if it doesn't exist yet.
+type VagrantCloudUploadOptions struct {
+	// Name of the box, in the user/name format
+	Box string `json:"box"`
+
+	// Release the version once the box is uploaded, otherwise it has
+	// to be released once all its providers are uploaded
+	Release *bool `json:"release,omitempty"`
+
+	// Access token of the account owning the box
+	Token string `json:"token"`
+
+	// URL of the server, the public Vagrant Cloud if not specified
+	Url     *string `json:"url,omitempty"`
+	Version string  `json:"version"`
+}
+
+// VagrantCloudUploadStatus defines model for VagrantCloudUploadStatus.
+type VagrantCloudUploadStatus struct {
+	Box      string `json:"box"`
+	Provider string `json:"provider"`
+	Released bool   `json:"released"`
+	Version  string `json:"version"`
+}
+
+// Vulnerability defines model for Vulnerability.
+type Vulnerability struct {
+	Arch string `json:"arch"`
+
+	// ID of the vulnerability in the OSV database
+	Id      string `json:"id"`
+	Package string `json:"package"`
+	Version string `json:"version"`
+}
+
+// Options of the wsl image type, applied to the archive of its root
+// file system
+type WSLOptions struct {
+	// Package the image as a .wsl distribution bundle, which is
+	// installed by opening it
+	Bundle *bool `json:"bundle,omitempty"`
+
+	// User WSL logs in as, either root or one of the users of the
+	// customizations
+	DefaultUser *string `json:"default_user,omitempty"`
+
+	// Default name of the distribution installed from the bundle
+	DistributionName *string `json:"distribution_name,omitempty"`
+
+	// Boot the distribution with systemd
+	Systemd *bool `json:"systemd,omitempty"`
+
+	// Content appended to /etc/wsl.conf
+	WslConf *string `json:"wsl_conf,omitempty"`
 }
 
 // Page defines model for page.
@@ -832,6 +2444,12 @@ type PostComposeJSONBody ComposeRequest
 // PostCloneComposeJSONBody defines parameters for PostCloneCompose.
 type PostCloneComposeJSONBody CloneComposeBody
 
+// PostMarketplacePublishJSONBody defines parameters for PostMarketplacePublish.
+type PostMarketplacePublishJSONBody AWSMarketplacePublishRequest
+
+// PostUpgradeComposeJSONBody defines parameters for PostUpgradeCompose.
+type PostUpgradeComposeJSONBody ComposeUpgradeRequest
+
 // GetErrorListParams defines parameters for GetErrorList.
 type GetErrorListParams struct {
 	// Page index
@@ -841,41 +2459,507 @@ type GetErrorListParams struct {
 	Size *Size `json:"size,omitempty"`
 }
 
+// GetImageCatalogParams defines parameters for GetImageCatalog.
+type GetImageCatalogParams struct {
+	// Page index
+	Page *Page `json:"page,omitempty"`
+
+	// Number of items in each page
+	Size *Size `json:"size,omitempty"`
+
+	// Only list images delivered to targets of this type
+	Type *UploadTypes `json:"type,omitempty"`
+}
+
 // PostComposeJSONRequestBody defines body for PostCompose for application/json ContentType.
 type PostComposeJSONRequestBody PostComposeJSONBody
 
 // PostCloneComposeJSONRequestBody defines body for PostCloneCompose for application/json ContentType.
 type PostCloneComposeJSONRequestBody PostCloneComposeJSONBody
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// The status of a cloned compose
-	// (GET /clones/{id})
-	GetCloneStatus(ctx echo.Context, id string) error
-	// Create compose
-	// (POST /compose)
-	PostCompose(ctx echo.Context) error
-	// The status of a compose
-	// (GET /composes/{id})
-	GetComposeStatus(ctx echo.Context, id string) error
-	// Clone an existing compose
-	// (POST /composes/{id}/clone)
-	PostCloneCompose(ctx echo.Context, id string) error
-	// Get logs for a compose.
-	// (GET /composes/{id}/logs)
-	GetComposeLogs(ctx echo.Context, id string) error
-	// Get the manifests for a compose.
-	// (GET /composes/{id}/manifests)
-	GetComposeManifests(ctx echo.Context, id string) error
-	// Get the metadata for a compose.
-	// (GET /composes/{id}/metadata)
-	GetComposeMetadata(ctx echo.Context, id string) error
-	// Get a list of all possible errors
-	// (GET /errors)
-	GetErrorList(ctx echo.Context, params GetErrorListParams) error
-	// Get error description
-	// (GET /errors/{id})
+// PostMarketplacePublishJSONRequestBody defines body for PostMarketplacePublish for application/json ContentType.
+type PostMarketplacePublishJSONRequestBody PostMarketplacePublishJSONBody
+
+// PostUpgradeComposeJSONRequestBody defines body for PostUpgradeCompose for application/json ContentType.
+type PostUpgradeComposeJSONRequestBody PostUpgradeComposeJSONBody
+
+// Getter for additional properties for AWSEC2UploadOptions_Tags. Returns the specified
+// element and whether it was found
+func (a AWSEC2UploadOptions_Tags) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for AWSEC2UploadOptions_Tags
+func (a *AWSEC2UploadOptions_Tags) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for AWSEC2UploadOptions_Tags to handle AdditionalProperties
+func (a *AWSEC2UploadOptions_Tags) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for AWSEC2UploadOptions_Tags to handle AdditionalProperties
+func (a AWSEC2UploadOptions_Tags) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for AWSEC2UploadStatus_Tags. Returns the specified
+// element and whether it was found
+func (a AWSEC2UploadStatus_Tags) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for AWSEC2UploadStatus_Tags
+func (a *AWSEC2UploadStatus_Tags) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for AWSEC2UploadStatus_Tags to handle AdditionalProperties
+func (a *AWSEC2UploadStatus_Tags) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for AWSEC2UploadStatus_Tags to handle AdditionalProperties
+func (a AWSEC2UploadStatus_Tags) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for BareMetalUploadOptions_Headers. Returns the specified
+// element and whether it was found
+func (a BareMetalUploadOptions_Headers) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for BareMetalUploadOptions_Headers
+func (a *BareMetalUploadOptions_Headers) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for BareMetalUploadOptions_Headers to handle AdditionalProperties
+func (a *BareMetalUploadOptions_Headers) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for BareMetalUploadOptions_Headers to handle AdditionalProperties
+func (a BareMetalUploadOptions_Headers) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for HTTPUploadOptions_Headers. Returns the specified
+// element and whether it was found
+func (a HTTPUploadOptions_Headers) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for HTTPUploadOptions_Headers
+func (a *HTTPUploadOptions_Headers) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for HTTPUploadOptions_Headers to handle AdditionalProperties
+func (a *HTTPUploadOptions_Headers) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for HTTPUploadOptions_Headers to handle AdditionalProperties
+func (a HTTPUploadOptions_Headers) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for HetznerUploadOptions_Labels. Returns the specified
+// element and whether it was found
+func (a HetznerUploadOptions_Labels) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for HetznerUploadOptions_Labels
+func (a *HetznerUploadOptions_Labels) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for HetznerUploadOptions_Labels to handle AdditionalProperties
+func (a *HetznerUploadOptions_Labels) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for HetznerUploadOptions_Labels to handle AdditionalProperties
+func (a HetznerUploadOptions_Labels) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for ORASUploadOptions_Annotations. Returns the specified
+// element and whether it was found
+func (a ORASUploadOptions_Annotations) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ORASUploadOptions_Annotations
+func (a *ORASUploadOptions_Annotations) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ORASUploadOptions_Annotations to handle AdditionalProperties
+func (a *ORASUploadOptions_Annotations) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ORASUploadOptions_Annotations to handle AdditionalProperties
+func (a ORASUploadOptions_Annotations) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for Subscription_Facts. Returns the specified
+// element and whether it was found
+func (a Subscription_Facts) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for Subscription_Facts
+func (a *Subscription_Facts) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for Subscription_Facts to handle AdditionalProperties
+func (a *Subscription_Facts) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for Subscription_Facts to handle AdditionalProperties
+func (a Subscription_Facts) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for Subscription_InsightsTags. Returns the specified
+// element and whether it was found
+func (a Subscription_InsightsTags) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for Subscription_InsightsTags
+func (a *Subscription_InsightsTags) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for Subscription_InsightsTags to handle AdditionalProperties
+func (a *Subscription_InsightsTags) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for Subscription_InsightsTags to handle AdditionalProperties
+func (a Subscription_InsightsTags) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// The status of a cloned compose
+	// (GET /clones/{id})
+	GetCloneStatus(ctx echo.Context, id string) error
+	// Create compose
+	// (POST /compose)
+	PostCompose(ctx echo.Context) error
+	// The status of a compose
+	// (GET /composes/{id})
+	GetComposeStatus(ctx echo.Context, id string) error
+	// Clone an existing compose
+	// (POST /composes/{id}/clone)
+	PostCloneCompose(ctx echo.Context, id string) error
+	// Get the export of the image of a compose.
+	// (GET /composes/{id}/export)
+	GetComposeImageExport(ctx echo.Context, id string) error
+	// Get logs for a compose.
+	// (GET /composes/{id}/logs)
+	GetComposeLogs(ctx echo.Context, id string) error
+	// Get the manifests for a compose.
+	// (GET /composes/{id}/manifests)
+	GetComposeManifests(ctx echo.Context, id string) error
+	// Publish the AMI of a compose in AWS Marketplace
+	// (POST /composes/{id}/marketplace)
+	PostMarketplacePublish(ctx echo.Context, id string) error
+	// Get the metadata for a compose.
+	// (GET /composes/{id}/metadata)
+	GetComposeMetadata(ctx echo.Context, id string) error
+	// Get the mirroring of the ostree commit of a compose.
+	// (GET /composes/{id}/ostree-mirror)
+	GetComposeOSTreeMirror(ctx echo.Context, id string) error
+	// Get the signatures of the artifacts of a compose.
+	// (GET /composes/{id}/signatures)
+	GetComposeSignatures(ctx echo.Context, id string) error
+	// Rebuild an existing compose for a newer distribution
+	// (POST /composes/{id}/upgrade)
+	PostUpgradeCompose(ctx echo.Context, id string) error
+	// Get the known vulnerabilities of the packages of a compose.
+	// (GET /composes/{id}/vulnerabilities)
+	GetComposeVulnerabilities(ctx echo.Context, id string) error
+	// Get a list of all possible errors
+	// (GET /errors)
+	GetErrorList(ctx echo.Context, params GetErrorListParams) error
+	// Get error description
+	// (GET /errors/{id})
 	GetError(ctx echo.Context, id string) error
+	// Get the catalog of images delivered to targets
+	// (GET /images)
+	GetImageCatalog(ctx echo.Context, params GetImageCatalogParams) error
+	// The status of a publication in AWS Marketplace
+	// (GET /marketplace/{id})
+	GetMarketplacePublishStatus(ctx echo.Context, id string) error
 	// Get the openapi spec in json format
 	// (GET /openapi)
 	GetOpenapi(ctx echo.Context) error
@@ -949,6 +3033,24 @@ func (w *ServerInterfaceWrapper) PostCloneCompose(ctx echo.Context) error {
 	return err
 }
 
+// GetComposeImageExport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeImageExport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeImageExport(ctx, id)
+	return err
+}
+
 // GetComposeLogs converts echo context to params.
 func (w *ServerInterfaceWrapper) GetComposeLogs(ctx echo.Context) error {
 	var err error
@@ -981,6 +3083,24 @@ func (w *ServerInterfaceWrapper) GetComposeManifests(ctx echo.Context) error {
 	return err
 }
 
+// PostMarketplacePublish converts echo context to params.
+func (w *ServerInterfaceWrapper) PostMarketplacePublish(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostMarketplacePublish(ctx, id)
+	return err
+}
+
 // GetComposeMetadata converts echo context to params.
 func (w *ServerInterfaceWrapper) GetComposeMetadata(ctx echo.Context) error {
 	var err error
@@ -999,6 +3119,78 @@ func (w *ServerInterfaceWrapper) GetComposeMetadata(ctx echo.Context) error {
 	return err
 }
 
+// GetComposeOSTreeMirror converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeOSTreeMirror(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeOSTreeMirror(ctx, id)
+	return err
+}
+
+// GetComposeSignatures converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeSignatures(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeSignatures(ctx, id)
+	return err
+}
+
+// PostUpgradeCompose converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUpgradeCompose(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostUpgradeCompose(ctx, id)
+	return err
+}
+
+// GetComposeVulnerabilities converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeVulnerabilities(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeVulnerabilities(ctx, id)
+	return err
+}
+
 // GetErrorList converts echo context to params.
 func (w *ServerInterfaceWrapper) GetErrorList(ctx echo.Context) error {
 	var err error
@@ -1044,6 +3236,58 @@ func (w *ServerInterfaceWrapper) GetError(ctx echo.Context) error {
 	return err
 }
 
+// GetImageCatalog converts echo context to params.
+func (w *ServerInterfaceWrapper) GetImageCatalog(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetImageCatalogParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "size", ctx.QueryParams(), &params.Size)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter size: %s", err))
+	}
+
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", ctx.QueryParams(), &params.Type)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter type: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetImageCatalog(ctx, params)
+	return err
+}
+
+// GetMarketplacePublishStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMarketplacePublishStatus(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMarketplacePublishStatus(ctx, id)
+	return err
+}
+
 // GetOpenapi converts echo context to params.
 func (w *ServerInterfaceWrapper) GetOpenapi(ctx echo.Context) error {
 	var err error
@@ -1087,11 +3331,19 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.POST(baseURL+"/compose", wrapper.PostCompose)
 	router.GET(baseURL+"/composes/:id", wrapper.GetComposeStatus)
 	router.POST(baseURL+"/composes/:id/clone", wrapper.PostCloneCompose)
+	router.GET(baseURL+"/composes/:id/export", wrapper.GetComposeImageExport)
 	router.GET(baseURL+"/composes/:id/logs", wrapper.GetComposeLogs)
 	router.GET(baseURL+"/composes/:id/manifests", wrapper.GetComposeManifests)
+	router.POST(baseURL+"/composes/:id/marketplace", wrapper.PostMarketplacePublish)
 	router.GET(baseURL+"/composes/:id/metadata", wrapper.GetComposeMetadata)
+	router.GET(baseURL+"/composes/:id/ostree-mirror", wrapper.GetComposeOSTreeMirror)
+	router.GET(baseURL+"/composes/:id/signatures", wrapper.GetComposeSignatures)
+	router.POST(baseURL+"/composes/:id/upgrade", wrapper.PostUpgradeCompose)
+	router.GET(baseURL+"/composes/:id/vulnerabilities", wrapper.GetComposeVulnerabilities)
 	router.GET(baseURL+"/errors", wrapper.GetErrorList)
 	router.GET(baseURL+"/errors/:id", wrapper.GetError)
+	router.GET(baseURL+"/images", wrapper.GetImageCatalog)
+	router.GET(baseURL+"/marketplace/:id", wrapper.GetMarketplacePublishStatus)
 	router.GET(baseURL+"/openapi", wrapper.GetOpenapi)
 
 }
@@ -1099,159 +3351,428 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+y9e3PiONY4/FVUPG9VuivcLwlJ1dQ+hJCEBHKDXJeurLCFrWBLjiRDyFR/97ck2cYG",
-	"E6C7Z/bZ/fX8MR1s6ejoSOfoXOU/MwZ1PUoQETxz+GfGgwy6SCAW/LKQ/NdE3GDYE5iSzGHmGloIYGKi",
-	"90w2g96h6zko0XwCHR9lDjOlzPfv2QyWfd58xGaZbIZAV75RLbMZbtjIhbKLmHnyORcME0t14/gjZexL",
-	"3x0iBugIYIFcDjABCBo2CADGsQkBRNgUiyvxUW0/w+d7+FKBbjz0Ws1y06EENSX5uBoImiaWaELnmlEP",
-	"MYElIiPocJTNeLFHf2YYstR8lgbKZrgNGXqZYmG/QMOgfrAwwcwyh//MlMqVam1vv35QLJUz37IZRYlU",
-	"WMEDyBicqbkz9OZjhkwJJsDhW9SMDl+RIWQ/Pb87z6HQvFKk5z88wQjxDPJzU8RFrpTJ/p3TzmY4gR63",
-	"qXjRqx3HyZ3lwrfLWKUTLB3XdWTsCSh8zSUJQkEXJzGCLs4VjXqluH9Q2d+v1Q5qZnWYRrEtSbwwGTlu",
-	"ds0e6FV+Zgt4/tDBhmbhEfQdEbVLsnR7BDgSQFCgXoMvwkYg6AIU837NAggcSqwsoMORzw0okAnubjsD",
-	"gjlgSPiMIDMP2oID9O5hBiVo4GLLFmCIAKeUIAaEDQkYUQaosBEDvprbgAjILCR4fkAGZI6LYD6Sw3Kb",
-	"MoGYHA3EBgOQmAOCkwNiDiTuHLoIQK6Gkr/jw4H5aPMlGlLqIEh+flE3W85VW9FnTroojg8hG6XC//AZ",
-	"+pntgl1ooYhDF6S+pCgdKWpqOiITqA5y0YHrc7XOPsFvvjyaVEMLTxABDHHqMwMBi1Hfy6slloPIxaIu",
-	"FnInjRh1VRc5UcSFXHcGiUldQAkCQ8iRCSgBENzdtY8B5gNiIYKY3IZ6IRMCRSGWxrEONaAIljc5wU7w",
-	"Jpykx+gEy0mG6L8o9LNgaiOGVBM1ityevmOqyYd0gUR2szAXiCn8zuhU7mgHcwGg44AQDX44ILYQHj8s",
-	"FExq8LyLDUY5HYm8Qd0CIjmfFwwHF6Bc20Ig6v4xwWj6h3qUMxycc6BAXPwP/Ahl4Ysc6CUaZEeRXGIc",
-	"PpKkJ1QA7iEDjzAyswAL+dBEpm8kFmQFHRaJLtkD+XI7pQvKeN/Pd1dyu2xA7kVU+tQ3ILkNwJyqEdOO",
-	"O38YofCCzWWk2scSpXizH0CmimpmfVg2cnBYruaq1VIld1A0arm9UrlS3EP14gEqp2EnEIFEfIKXREI3",
-	"2gyrYAuOMDHVWmsOVTIDXFMmoLPJXgz3ocATlDMxQ4agbFYY+cSELiICOnzpbc6m05ygOTl0TqO8QKSa",
-	"sY9GteFermRURrmqCYs5uFcu54rD4l6xXDkw9839tZJ3TrHltV3agWvk5yr5nJSQm4icBSRjANJQiOuz",
-	"R9ScyVEoQVejzOE//8z8fwyNMoeZ/ynMDYZCoBIXUvTh798WIN4i7lESaMqOswHUK4XZLRohhoiBMt+z",
-	"SxQxk5QolStI6og5VD8Y5kpls5KD1dperlre26vVqtVisVjMZDMjylwoMocZ31fLs4ZqZgq1otnNF+vH",
-	"J/VZ+8SW0MNqerbN/yJK6il1qMV/6aTUfh/62DH17wWLIUAhm3nPWTQXPMREIDaCBvrze5otMaavSmH/",
-	"DLML+orVXNIZMEDoU1J0IcEjxMUvpYcbB/rzxFiY3Bz65zNDAppQwF85McoFQ+jFoK6LReqZ9cWG3P4a",
-	"Hl1yBQQImqecfx40xtDSsBd9H+qNVqYwMRzfxMQCl63720YmZpN+Np8ARkSINMKupt+t1lG31K4Nnwvq",
-	"4g8YqeafYdhMtv6ezZhYUmfoiyXrhNnIydXTqKh3O5vj+9mQbdk4nNti5+SG3QbMj7Lv0u5OECC2HL9C",
-	"/qdJLR7BXTvd8HDIJrqiLYk2h5JGsw3xkaSbA9qsT4KQ98pZt0j8AFBygp+LGQ2uxRhly5qUiQTEjvxT",
-	"Es2MSUEp8CzEtNkAeaqPbvlUixovIaDnIxmG+K6aim8YiMu5jCB2fCa1Ng8RKUXkhOZ8NW+4xFhNSgTE",
-	"BKXM7BP7WVDgcxR5JYwQyNzaWmm4au11GW60jZXHJAFUUIDcITITqrY2S9ksHzxSur0a9VBAK9UScfjL",
-	"BDE8mi2PLsnAqAP6nR5QbfAIBwZ2bFDlyFnytCxuMD3BVK04nNLPODc+WZZoPRhSbq05CRVhFowVypX2",
-	"kEoqaC0P0YfWliNoez5Vl1tHm5gs3Jw0JraC4yGJ+bF6Hp7YoXKx5AWaT4aSwILXeyzuYJsTKfBxJYc6",
-	"uTm+THcvLdDmzYezPKYFdxb4OgrBehx+QrVF71k2nHLqblMn7y3yKMfSfF3m8CHkKJhFJNznGIY2tGGS",
-	"PEOmDbX9LKmEiCjIU6wgT+x6oV54r++97FULEiDlBcoLCYWe4dRNtnA2GDYyxi+WZ8XkZMydqV8z5NHV",
-	"bRCBQweZ6S9H2EEh8ywhY3nWGM3SlNnVCCek/byZiwR0MBmnU9PF8hzh+REyKYMeo3K58pRZhbDfP+Qc",
-	"/9Dvc5XywC8Wy3uQGfYfmsobkFYPIhXLZSQiHOTrvIGIoFyN/w+GHAQ5+qOekzowdGMjQ/n/vap+ovA7",
-	"ghxd9TbAZSXJPYYpw2KWfmRy7sSk9RqZm2oNhhwQ11S3UXNDabC58jM/TNO2t0JGbWDFjzjNJmi9CwZB",
-	"vI0SuaERMfdzAkxAUhHPg76NOBqQRO8pdhzlQOPIlAepiTxOnQkKXLuCYTRBEfw8aEQEcmbZARES5Hz4",
-	"EBqHk8A7jF2PMqFhS5n3rwISRmHmu3mFRt4s/AtEDrQBCQTrXCBuRtdFSZZC3nAQvIWyehwilgZwZNJ1",
-	"/U+Or0LBsvmgJ9hBqeNJKDMukLsVqKBLKkCGptBx1kPR7RLcomRiuo+7g/XZqV5zufBaG9h0NbUjOwVh",
-	"m3KRrt00KRlhy2dIR8WihsloSezxsgFpERwanJ/aL2E7Fd3nAjqOoseLiSbYWBNPincAukMWGD5jiAhn",
-	"BihxZpIRR74TKVLItFCOY9dzFFvnAhBS6515izpDwUSTAjdh2gTHiBG0dq0vdKsggOSgde07utX3bIZ6",
-	"iHADeut6XHmI9JqN60XfRyz67lEuLIb4dpF3DzKhlgYT68WlJkqEgzPQFzTnTNzMYky4hxxkCGDTqSK4",
-	"ifk4CC+E0iyCjMw82AkB7ej30s5hcAp84iDOlURkCECGVDyPMuBShoArNTiPYiJUHsnUxoYNDMgRwGIO",
-	"p3PfzYMdBRs6UzjjAyKNa/k8C9AEqbiMkpDBEIQCpE6EGPw82GFwugNUT4lZhD4fkDQgK/AMAjyBLcng",
-	"NJPNaPpFpPyW6s+aSa3233KOKQba+DAbkJDJrnoAC46ckUoImGlghKpAL5xA7EitMWJJpYUDRqkAlA0I",
-	"JLMg7C4JHXf7mcBjVBrWXxXO4cAvHAkORhg5ZghzaTqYA2wRysI420aC8/MDkCMmBc5aKL2wnVKy7EDr",
-	"TRfxnNtgjGZ8Uwx7vbMLlI5dLJC1Fkq8rYSFXfRByVph1Q/bSeOMb6O43fE0nS3NTJ2rDEtEawQbea7v",
-	"zM/GMEw5wgQ6QDLsCBpCr31S7USE+wy9eJCFeXSfp7y0VHsgbCh0mFt1BDF1CKB3HLcnYybRihNendDh",
-	"Tp/PBnIAg2C2SnugTP7GC34ZqtKf5sG+RQmyrOx/kxZLJNATLnLEXMy5FAtAA4i4dI4WJoAaAqozVVoi",
-	"cWyK+7Vaulde2GkeeWGHimwEP3kCS+3WnZmYpXoEuPajJaFeTYlOM0yhpuwRI6b/K4i5YBupqaZZR5FD",
-	"81d5m41gDZfokvCRyh4wlquQkmOxmbNUDRc1XwCc7tBVU+4EZvFm01atUzzroVjZSL5oUq+LemlQ6ZhL",
-	"U2Od/brgiWofXwVKKKBkSCFTHi6lR4eezUX3mU9ePH/4MkazFxtyO30x460w4cjwGVrfUm7lFwMxka7t",
-	"uZD4UiT68sGLPMsQe1mZRba0l5VRtVoiS9vqR4RxGFxcdhbL5Q15WkGHHHgOlJDRe2og8C8U7Gsc1JvJ",
-	"+XAWSqQHsj2S9f8WEa8w+lS671WrPybdJeg0wR48/xHJPqefH9Ivku5/n1A/SXgRFmL2mLykp8LLp/F5",
-	"aAiS9sOZQDyOfrlU3a/WK3vVejK872Mi9qqKlSMbI+l8LEwgW+vVjnXOzhFOn2ma22JLGRnAWCcZPcoE",
-	"X60mq9fgizRwKBOAQWIh/lVZJR6jghrUUX4SaUPHafnPTLl8KAwvk83Ui8Ef2IWe+nO7tPSY8v9D8w8B",
-	"qGCb8qLLLWxiLv9MOSl45GhfYTnE4M2hxGYukEOQ2G6WMff++lF14+SgIyFJTIS3Za3DwuZLO4FOm9c/",
-	"E9Ub+sYYidXuJUi0tJfysddvXB43bo9BT1AGLQQMB3IOjhSI/GIKcfAjF4ywMrki3fMm7VqSEvKN/L5y",
-	"k6siBhM0qev5AoEWsTAJnLz5AelH+ZwK0EKG9RQLOziPT5vXIAiIZAMXCubK2E+a8gpWkAM/90HnQXuU",
-	"zAWOUq8HZMfQkXSWgx7ODfxisWL4PjbVX2gnPHmC4aQYFwmst0nNnufdL5NSTlG/jyW7RnMKHVJxp3qM",
-	"viNG3YCeqpYhIiWUv7GpoIeZ0XnQQwhEMTyH+mbeotQKIuVcbx2VIFuIEqyDnPZkQrWKmfqOwLkA8yj5",
-	"2nAoR1yEh6qOfA/IlyDvOdyeemNG3b5KMhs25YgA6AvqQoEN6DizRSIjf4vqnnSBENBFzRuEzSW+Ckpy",
-	"J6dtX7U98wPSgoYdbhJF9SA6BGBEqUgRCIZRDtw8uFcYaOWFA8jQ4YAAkAM7Ujk4/BO5EDvY/L5zCBoE",
-	"qF8AmiZDnGvVjyGPIa7UzWgsQ4IAC9PKgxPKQEC9LNiBDjbQ/8ayI3bywciBlGzoflvioIcOQKwa253l",
-	"lMssBz3vf6HncY+KvBV0CvvEUVKa5rbUCOYfpvFLvBZIYLqY8FQamNSFmBz+qf+VAyr2BD0fCwT0U/DF",
-	"Y9iFbPZ1eXDH0QOqOD9HLDAGoAj6LlJkzno78mDdWcApnes+35ph6YMWDnKjAkhmAxLSd7Cga6gNt7Qr",
-	"MpHyEO6HTRcvE9gVh8tkzmQzAYHjD/+S+sLo3P11qe7qbJbwXxZTmSE3EDEhEbkhg9jMVYqVWqmyVqmN",
-	"gcuuy5w/DU21LZQHK626Qht30hTRYkkbc3Mj+Av1NPivc/xj8ff11VMLANdSYeWU27HY3BbKa9htje6u",
-	"UsdMrTVuEvlrhe11CJWLobTXNux8EnVIVRKXxtg6JWGErU08Y6rdZ7Q+ic9sCxRSk56uGZ1grkNz4O62",
-	"s1HuUip28ZTb7RCDzLCxQIYIvFFzpo2yZlYovvrxBmmt/ZmnwyY6R3xtJLTXl63U1JOxsl8R7Yns+MCH",
-	"VFyKewY2vZpkNrLl8+DBRiQsTS3Gq61kBywPVhcT7PrugJhohAkywXAWa6f0muThUi0fVA/29ssHe6uc",
-	"Alpdf6HeRknjSUtq3j2oeE3XreWYSl0OBlG2ilJcPQct1swCpdHJhQB6knxAIODIg0wKx6C1iaTFpZVd",
-	"dcBiwQGdknCIPOgG8AfExCPlGhfhGNKKmCLHkf9GaITvAhmq6nvHmJhSMxwQ7nv6xN8iKqhp1Vdw1x6k",
-	"CS5JMMDCLv0WcuOqYxWF0YON87EjJ/jW+ehBJne0DTYDkCx3Wui8BSMuwvmUwGE+eZJ8W6VuZzMquKz/",
-	"1Ejrv8NK3CC/e0mcxYRUbCg4lcPAKc/ZMMdsHwe/Yn9y6EU/PzQyuigXQW8/8Sb5I9ZP5bFEVTDBrzAb",
-	"LngQ5bZIzVE5uywjAmBJmR9pZOrfRAdMxRy+/jEHL38vNmZwGoFz8CQJjRpyzAn3pBE+/ytHJzCTzUy5",
-	"k0rgiyjHZpuDyZMLmxKcUM+lSWj5LgrMUpVaQKlQicMM6KQeVV0kBZuDSdKVTCh3xR8jygz0Werlah0u",
-	"GEA7dxKg9ZuciYa+tVkG90VQD/MDuezzYU902mvTob6ZO4J8hYdFJaome5aL5WLxoLifL6Z6DVREKT0l",
-	"d0xfcUo+rnxs+8NNMpkhHy/aCtVymlY9QYwvlT1V1t/GEKA/HypY3DnEOVW+rVibsCRy0TySJ05QvkJU",
-	"ydtSXJJoptMtV4FfdVAoYbYJddL2VBirTYKUB2Z6SnFwa1BKOnGgLy2/EVRAJ+3VAhXUoNnouiF9y4/u",
-	"nF0Zus2q6xicn/EMq0S9Fw4naH3MsG9jHjkxsbSM3GFCf9HuxqO7duf4pXPVbHR6jfsWQGSCGSW67n1A",
-	"JpBhHQHQflm9+WKRAQ4nUtPXVTrqhFfOPceZ5YFEQd2lIrUvE02QQz0JWHmPKXFmWe2z1c6LefaVFkFs",
-	"xW0mC2sRo8lKmqMtzUndaY0xOUYzFUlflqo9qdVpkaqbAAfOqJ8MWPqpNVgOJJafXiMa+jF1tqY6IoZR",
-	"nmnoJlJWqr5eBBnURRwEfqusuvRBmlNEvVf+R8CRQYkJg7KWmIMIkZe7Xv6uf5Kr/1x8JJu5ara32/Or",
-	"IfwlV8wEttnhnylJ/4iIVCu3oS7uUXGJLMDqdp9sxGxyt4+QMGzJGAGUPGi7noNR4Lv8l8+cf8kO0vYK",
-	"bIPsgOhARyJPXxkuQUmv4pl8egWSTglISVCARMJCWKUtwqA6GXwJ1voQFMt7xeqwbMI9dFCrDs1KdVgf",
-	"1suwXqmhGtzfN8vDveJoBL9mdSB7yCAx7JyDxwiwqFBvDo/ZyJlXAUll7+uCL3+5RfrBPlquCN6gm83d",
-	"9cLxGAnEXGnqgamNAtLoAEHibhQXEmghBr4YkJgO8jD5CrCJiMBipu9t0vsLCKqcrdo5rV6EFnseNCnh",
-	"vosYMOTmUsWEi9UYkAPDwZI1k21sRAYk2kvRPpBSM9xYK2582jxfZjGZa4kR7GApln0m6SfviiM5rb41",
-	"OEjVCKm8GWaQLyHlMTrCDlqVOCYgdqj6sWGOej/qkOKxDUf6DMV+fMQkrlylnWsX3+YRbp/8SL+0FV68",
-	"GWD5tjhmpCd4IY+ueLOylCumkC9r3thyzdqqVwSKVeljoV9u6UVMiV5zk452bKzUlLOaCBGO0mq/9h1P",
-	"nw4/Fc6HHKVnIR0Fb7R+FN0EEKhTcxGSLh7jtZyL9dPhO6kkaF1dXxmnnJXhGSDoauvoJQi1SeCfmzwL",
-	"dI5mm8YriwRddZ6rys6NDvWoZdpwt5vRKKHm5QekIYDcE1qNCozjnaA+dicLduYlk+pXUKq5A+ZzUHHh",
-	"ARmieRRPpSSougsN0dX6VzLIR5mpY8ceQwYy1cmKdaFJdP2fHFeeGEM6QWl5irFC3r+vfnfret11+Y7S",
-	"DuDA8qygBD95j91880dn4opjcF7LuxARuz6V2nlUPiLFz7wkRSWhJE/xhAaTk/8dtU7bl+D69Bpc3x11",
-	"2k1w0XoCR52r5oV6PSAD4t60L49OG0bPoEetxnFnVH86G6OP8z1oOt2n6T48PW0759AR9fPX8nvhqHyx",
-	"a7dHbf/9VHj3r/toQDq31vHd/t4r7Ne8++Oae9I9r3hjRNBtwei7b28348vZDbcfy/Tmcdr6uOsNS83L",
-	"bnPUPLXGj/Wb8oB8PI9Z22iyk+JNecouhg70TftuF99D0jjmbqn+1Hrjw1rjrrJvijvWrdw8mQ/Wwe3u",
-	"I74e3ddvB+Ti6LVfrEzuj67Mbo8/VQ46sEn22l7pauLV2y1aaKPW/VPpzW1eXTfgRXF4flbxR1a16aMx",
-	"3+33BmR689BHzc67/9zZu+o+0qvri+mkezN6H1qlx+P6xH8uXojXgnF5Vn6HfvHd5Q3/4OzcQ+PJ1fXt",
-	"uzMgszfxOnseMXqP0cnMmz5bk5upIKRbL1i9ll84v++zp2Kt7Lbu+vtNY7hfHRtnJ/2TUXfskPFpYUCK",
-	"o7tq4xbWitWzyvtrcSyGqDK5MK4f6fWVf3F0z896k2Lx7vSpMbtG/my3vm/cFZ5adnd/XOndX7wOyB5q",
-	"P1sz3L0qTp3S0+nx7YXhO9MxP2js+s7YKtH+sMorH+7z5Lq4f0r77w/V8iu8qD30di/tZ4QGpL5XfKT3",
-	"9tAoXXi93dfRM33lrCWe69fDu+fdp8lJ/dZj5kODvZ4Nz8flc+/2ovHet9/5TYMf2aelASl2/PfyA+we",
-	"Fa1yu3ZtdM3zgvH2Sot1w2CvR48+fn9guIb9g+6jV3/rF0a9j0uXm22L1AtvzxcDgus3vjPy9/f9N/uh",
-	"MBXloSBYWLf87dV+7/qvT3fV52HVHouTun1xV3h83K+W3+xO7WLauG3cNI4GRByfnD4/3E4Mt2VdHHdL",
-	"F71G/dm9Hw8r53an3y11Ho9m8KFkG8RphM+Ns/MJdO9fzWZtMiCGa+zim/Oro6PuUbPRqJ7gVgud7bnM",
-	"Pjnb9+/5TafbLRefasazTd6f6icNV/FQ83RaP2lOx+0BOZq2T09u6HmzwZtHR0/NxrTVPLNazZNqo9G0",
-	"xjfz3ruXT43C/tGTZzmzXuP56cx+nV3YA1LYHe19XI/uJ8OzcrH1Vhm3969Oji6LpPO4e3RXcv1Jb/et",
-	"7/cqDx12VHErp74jvIvb1vlFR7i11vGAlNjpx2OD9ksz7+CpXe80js1us3k1e228cvpwV99/uvObu4Uh",
-	"eWV9dFvu3F41R7Pr5v7ew0G9hq/uB8St9XaH/OZ4ut8sd5hjNrrV7rFPZ8+lHhan8Ll6cdO5F7v9FixV",
-	"MX/qnTZfP+j+9VP9vnJ+Na4VB8R6e7Dq5cvC0C23Pnr7/XrloXU8LDmT12rbmbxb7bcLZJVKH49P7y57",
-	"6j2fnzdHk4/RrnPZ2/PfrbMBeX0vnBdnznO5g4enbO+00ZhdHdw9sMZzb9rrFlvGa78+bTXJ+7h37M/e",
-	"3Ifp/eTy6NFvte/rV6jyNCBdfFcanV/Wubl/7PGT91p399EkXXLT2z1jr/3ri+OK+8Cchklafdt8uq+/",
-	"Po+9B/t4xiuFgwN0NSD2uMg6ZFZ8vZyOoT8q4Lv6lbH3OOmOXzu33XOrdndwfzE79x8exMf0kbx2L2sP",
-	"tydHbxdV/kzdbndARmLYPyvt1mbD24dCozI5GsL324ey2L/7uHw1PtC499zCsHN50CmcGefN9m3p5qS+",
-	"Vy8fmw2ndXJgDsi4bN3gp95NA8Lz4vl54+Nscju+Pe90rIvy080TPru8n5VF5Xx2MuIMurVpr/lwNbKv",
-	"UXvWOeo/nw/IhHmXzvUQjXj/oLbfH5WPLtu+9fHMmrX79+PexfjZurVL96eTXvuGNGcf45vZXuuu/Hbt",
-	"4YfagZRR9nX78ZldUOOictHpHRTwx/lN/9YRr93GHwPyx/Wovz8g6nRpXR5/dvSsKHumDL1w7qQf0r/v",
-	"qki7rE1VcKYGTqSeHjQCusxT+UdiugnkUq3gQOnasZQ9VT06IF887CEHE/Q1tZJ0KWkrvKKHblkt/Wtd",
-	"IkmvB1jh9Ej32y5p6EGR6HYGVapC1zDNyOcaRs98jtgOB9AXNmX4A5kvYzTjywUfnNs5ZJZrtdIBaDQa",
-	"jWbl8gM2S87zcbt02W/V5LN2o/eAxfjqrHpX36+2TH50R2ZiWBlOJ7eWdebcOMOnR2eflIqTgxV3BKXW",
-	"jdxx7U4PjRCdiBoU28otlcBUpdetT6nhKj4k6ZRmFvU2LRD4BYn+YDgLL12PJzHMZxTeTGGmywPS1l1K",
-	"v6QCYC02ZCSU5bElMqlbe6HKecHjYgg80RWKwXZOfjwAGQyJnHwVk1Qe5HxKWSqppLn2kmr3LZt9G0g/",
-	"TDi27IWPJawqKaPMgiRWdRMPxFaLlXI13VG7wSX+V0FeIhg50ArrDphtyD/DFAjNMKpKKSwVgA6nwbUK",
-	"wcpz0A5mtCBWV80pWXYYvz9vvqx5KVljhF1L1wU+TdAtu7gnEjjEFji2OGnc3Y9VyG8R8gq7rQl6EeFp",
-	"rD4JUBHhgbBR4gAr5gllws5BFzFswLxHqZMnwpPHeCabKX32eqsTL35LwOqEh7BVNpQJSlLc9ZsJkXvX",
-	"K7Sg3Gdks9SHZVchmW181/VistvaPovf0ljXZak0ae0Yyx9gWNdlxdWG67qlRMfXdVkKLa7rsMqj+/1b",
-	"uuQJlTr90YflTEBVgoN5eDs9Q9DR15Koik4w9AVYXiSdWKnCsZJfBiRl7XXwHLgIkiBkCB0HpDQEeufx",
-	"AYEMacGnlbalcWHUNpCSE0zV/YLa9SgRHhDmO0hfu8LQiDKUBVMEbDiJir7UbgaqXknObogAnMKwNll9",
-	"b4HsiAHxKOc4iOW7+F1FrFwoDFv7QIP1AIJaStWUQjninVVe4VjC6Db3xy/k7G3MUhv2WCw62IKhNuyR",
-	"fh3mxryxYfsVvnlVrr19kmWUprlJRnWQtqpTqlfd0RsEcMJN8G1hu2yZVsl8QlblTiayaJd24dYT+smE",
-	"5/Q41gLIbysPotU5oHleiZIvw1TPeCIlNXA+EBi6eE8S0He8fJDynkq6wMbZpmBFXR234t5R9bK0yY2h",
-	"S1r0RkbdJTu9aLHuE97tdu+m/hm8bZy7tx3a/rgdld+Oy+Zx7aN41H8v7L1/llQZz+pBrPSj5S9K7TR8",
-	"hsWsJzeDJtARgkxTdaj+OglVzPOHfvhxOaW86nYRVKn760/MYTKiaVlKum5P0MDvoHKxdDqRTu/neZUv",
-	"a6Dg+xrBV+0aHjRsBMoqu1IpyJGXaDqd5qF6rVwzQV9e6LSbrcteK1fOF/O2cB2tpAlFsqvekRo+SBBn",
-	"QBWoAujhWKD2MFMOb56TLw4zlXwxX8ro+x0UmQqGQwnihT+x+V3tq7QS6lOkA6FaqqhiahCIAkCZymBz",
-	"kAiv6dVXWMMwsS087PX9/DE/CWUqgW1eFqGqoDAlQAkhZCIzH7+Upm1qVOJf+sgmvpH4z/Tv4wTFGRp5",
-	"QYGlirjVlwdVzDX68GBweXi447SRM/8M4S//IMc39TEa9SEWtRjlYjGWQhWkPjtBFK/wGtzpM0fo0+Mv",
-	"RiW1nZOUidNEbpHqLxw6KFNYHrRNtJIVpjxiUw9d+uuHbvjq6pIxUq44rBHRo1f++tHvyNybJnegh5jc",
-	"GyDa2xqT6t+ByZjQKVlYgtrfsfp3BL17KjMHqNIXQA11s6eZEOGKi0Ph/c9vkke477qQzYIipbgQUsIr",
-	"2k8KTsGYfwPUo2l3mTd19SYEBE3DrlngUTl1rCwRgxIe3BShHGITxGAo3JW8D0wa9bFT7TXFLG7g8GXB",
-	"dU25CD/HpIUM4iL8sNOv4fjkJ0m+J49PKcy+L8mb0q8evW2mLX3wEtiQy/VjApn/NqHD5t8j+S15fkue",
-	"DSVPIDTSJM2vUp620JdCGq5RlBIfxdlIVYoA/z+mLCUolbKDknT5rTD9Flv/oQrTSvmlDcG41pSiv8S/",
-	"KbmRPIkJq/9DUuQv0L0Wv9b5d2tfad/2TNlS6jIyNJ3ffzNEqtxGf4woXa4J9C4K6mbOJD4pX+XfTHpV",
-	"f9UAabz5PXFqS7Ikbn77hAGcoLrzR07xESaY27FDHHx6hmMxP7p1NZ8KQbhIQICJ3sOYEgCH1Bfhp5B9",
-	"R3x2zKvi1N+H/NpDPvgWaCpryC0QXdCno1eRgYgJIFR/KsDwHciCG8nAF2FT37KD+NF57+rya/6/jpFO",
-	"1e1zVhjYC3d5Ghslvmv6KS9FLTdgp1v1qX+uiguiz49JZJQNHogzEv9ufHBBSdTYoIqxoksCguULL2iB",
-	"AsTdscGnxHSqHiThp8VyIbh87RNWnH8v9jc/ruXHObFWMGViuZcY87+T15LssQHTxYrUPue5qChWstwS",
-	"n+m7MdE7NETiIGKK/ZAJTKTv3KAJXotc/+pmo884I8TzN2OsZ4zok8Qr+CJcym344reR+ttI/b9mpC7J",
-	"pjR5p4DHdYolETP/NMWScEmb2bxJQd1IsirTI9ZOXVnyl7L+fA5pu11/6JyOQECM32z272EzvdH/85gM",
-	"RhsIOg6Iks3C3TRns/UebUh00gMxosRQjdn87vDhDKijM51RN/cfoaD5T536lb/5DF+5lOoFiD/7zcW/",
-	"uXgbLkbLO0hybpTks/qEvAqa/OS+X8y/WppogIqSBdIqlyDCr8/8B+oln07ne1T1kCbFusEl6NT0DX1z",
-	"f3QPXTIFDHo4rz4QauORLjeBHtZf488pzwNiufALDIVJWWkrC4lpAlqYWJ8NwAW00E8OE3yMNbikPRpm",
-	"HZxv3///AAAA//+4oHJX3ZYAAA==",
+	"H4sIAAAAAAAC/+y9C3fbOLIu+ldwdfZdSSZ6++27es3IspM4sWPHsp3HKEcNkZCEmAQZArSt9PR/vwsF",
+	"gAQpUI8kPb1n756zzu5YxLNQKBQKVV/9VvOiMI4YYYLXDn+rxTjBIREk0X9NifyvT7iX0FjQiNUOa5d4",
+	"ShBlPnms1WvkEYdxQArF73GQktphrVP7/fd6jco6X1OSzGv1GsOh/AIl6zXuzUiIZRUxj+XvXCSUTaEa",
+	"p98cfb9NwzFJUDRBVJCQI8oQwd4M6Qbt0ZgGstG025XjgbLLxvO7+QhN994PTvrdfhAx0pfk49AR9n0q",
+	"h4mDyySKSSKoHMgEB5zUa7H102+1u5CP7sh8RP3FKZ4e11Hv6i2KEoQDirmcLEZeykUUkgSFmOEp8dGb",
+	"8wG6I3NJATEjKCFTGrEhI8xL5rGgbAo/e1E8lw3If/fOT5voinxNaUJ8JCLEZzghhWI4b4H4skIdPmPP",
+	"i1ImOJLlpwlm8iv2PMK5bEcWuSPz5pDlS1A7rMHoW+G8cUckqUskrdfUkB3UrtdgZKMHKmYj07csl7X9",
+	"z1qnu7W9s7u3f9DudGuf6zVgB2db+gecJHgODJBoEshm9Bg+Z8Wi8RfiCVlPLfJNHETYv4DF4Ruu8jiK",
+	"xCiMfAcfH0WRQPKTtTiK1uPsC0/jOEokqcdz+ERDufPkQIeMThCLBOIx8eiEEr+JTrOvHBqRLEAZGkdi",
+	"Bu1x5GGGxmTI5KS5IJILJIkRoWKmNpWYkVAvI0tDSaCATLE3b4xpxGv1WkomVP+nESdkQhJJx8+OxSUM",
+	"j/QE1OwnOA1E7VAkKamXiHHC8DggiLAZZh7xESPiIUru5ARgfHLuJwHmgnrorfqGej6OBUlyvhpHUUAw",
+	"k33T0OfFzu3e9A5QFGVcyD45CnDKvBnx0SSJQrMikrlTTtA9STiNGOqiaDJkdkUUEoF9LDDiJLmnHilS",
+	"777bbDvJ828TAJzhmM8igTDzcylwbW9qyobMseH+qM2e1yFp44Fw0ei4KvxxIqBeM0QZKfFvjymcN8xX",
+	"56hMzYgF8wJjawlQXMqBiGKEJ4IkiIaSHc2ynBwNsqWpA5dHqUBmY8pSUhSDUCDNaVMS3nxEVEAFzREo",
+	"P7LVumYrTrleVz/fRtaiIweF1aou7iie0Oh+xIhw7mnn1NfZ1KdMkADtd3cODtDtC0SZIMkEe8Q5BoGn",
+	"SwSwY9WL47nGU24JW9gPVPCMXIp4b3FIkMBTRDniRGjJO2R6d0MtD7MnAo0Jiu5JklDfJ6y0G36rCYLD",
+	"2mENJDav/b5wvLiPITfXrzqcBgKLVClgBYLgkC4Kl5MwFnNEJ0gycFFCPGCuuZT45d0d0kbb299q7x1s",
+	"7e3t7Bzs+Ntj1/5Y68gzSyA7LJ1FdTk0zOaF3kvHzerTZvWRkDcOInqJhMYJW5yLZJVKgbyGBK4PGZze",
+	"RMj5ihmZS2kr2SrTvsorkLBD/MAP70J+mMnNQ1sEHt6ReUv+gMee3+h08bixte35jZ1dMmnkBfH454hn",
+	"Iwip7yaP4SRLzOnpssix+qXpykqNNu6Mu96Wv012JjB250BcounfLj3qaEw49QlHwpIiPyQT5Patr1BQ",
+	"z3FyR0QcYI9cpuOA8pnUbggXG2qq6ngfJVFA3Ax/2jtH8ivqvR8gq1eEOU9DApoBXCKMilHBvhSHh0Wu",
+	"la22eg/carQX0lM2JVwombiwNHLkWO6vEZ9zQULHMX716uRsrapatSvWPmhuuyrHSeSnnqhQ2mz20CXh",
+	"b90Dohxh34ebV4E0smxD7lkyUYRx708vCkPCfOKPjO45UqXsgYutZkh8mobuNgKCORmxSFTwPCdemlAx",
+	"H02TKI25Y5ZsmhDOUZIGhCNrUEYzHKdzkvCapYz9V0ImtcPa/2nlhoaWvkq3ihw80L2/lJ271LaU4ymB",
+	"6Sepl13IFmaRcpJkLFEc/w0niRxqEMm7kYisC4C9uXlhgYjXbcg2XTTVizsSVASlteg0nSdLaZdbPFVu",
+	"rb6wLe25VW2DJTzupOAy3lotdYprtpnQkTet0cKB3O1mnVImyJQkslcaj+IkEpEXBVBa36+EF8tZ+bHz",
+	"kkXjUYKlICldHNpN+H+t9ma3BhGtN9rSCttDr1uTzhu0R1pB8sHWjxgiNB9VXTh78Bl0l1yMEebHEWWi",
+	"jsxktLFA/950bQfsBYvN9zFj0prUPzNtpzAX4iM1xyaK5eHlNRKCfUTVGcrlGYrlFYYI0KVUmTrCKE4I",
+	"p1PZ5s3VmSyfEJEm8u9IzEjyQHnpGh4n9B4LUqvXrI5q9do49e6IaEQPjCTO3yZpEDS8iIkkCpwspko7",
+	"lF343bLaUJ7PWkTK1BMxgryITeg0leSN1EVe3pJIklt4iCidpVqBcIzGw6NxyvzAZbQ9OUeEeZHsv99D",
+	"nmSOCfWwIByJJOVCmj6ipLT0llIzZBHLxaQaZBNdyFsEDoLoYYE/SqNuyP8dnbw8fYv6J1fXpy9O+73r",
+	"E/h1yM5PT/vNZtOt2qv2HFcZ/UUZLtFgqyGPGCyovHeaC9tTuD6fU3Z6gaIE9Uk8Q1cv3z9TU3KtDZwJ",
+	"khGjidR2MpOpYksvIT5hguKAZ8agnF56VUtkAksK3LdSnpN5yEw9RMUTbnOCJGSZfjMhYn7YaoWU0aip",
+	"f296UXh40G7Lc2YSJSEWtcNamlCn6sNFQsgopEkSJatO5ovBdULIOZQ1MkdqQFjMRlzMA1IwALiMej3f",
+	"B1VBaQWwG7SlSjZiCHRzdcbLEmfIrBUQM0ITNIu4WM1si1q/2u6rjRWnE7iciAjBZ/RUjkdXQfCA8EwK",
+	"niBi0zqKxpOUy50D8mfILAHURKeCI/IYU3kCRwyFdDoDWwGPIkbkumMGDACSSrPdkAmcTAmYX4YsHwuQ",
+	"FWHEZ1EiSLIg7TDzh4wWOyxKz2xL292hvDcn0Ta+C6oBjZQwl6fMaoJfQRWbOcztWJ5C7mMik0ZU8CG7",
+	"uTrLbWPaPGksY4sb1e4JRLsUZx4xPKgoSCpJwomXEDHKz9FFaTSAImYk1ixWHqToVCDK2RMxZHcktqeg",
+	"3pYKg7KVda6UxeiOMNd44DOCzzAWIm/kOJm7SPOjg0mTYAQ0nI9mUZo4rg5ndEIEDbMHj+IhDiZuFrGG",
+	"2rF6xetmD3IkIiVrQ/xIwzSUFTq7+wg6Q0/3kI/n/FkT9Y1tzovCMWWG1qrVkkjtbtdrurnaYWd3v16T",
+	"slX9tVKrW34vH2wtN82tUBs0iQwR6AQtbDEwn3Ai1tQMnCz7JmfSjbvS7JA0cEwb22TH3x93vQYed7cb",
+	"29udrcZB29tp7Ha6W+1dst8+IN2GT/ld86sXPXQrGMj9DmwTXRZyUlwqM9gT/Rnx7ngaLhIc6xJFqbZ8",
+	"SJ7VWl6Hz3B3Z/fwYLK/67f3O/v7296ev7tzgLsTgnHb29nBfruzg7fGk+1JZ9wdt8f73a7nd3b8Xa+z",
+	"M25P2m3c3l95McxGbA1k2dwHdMqwSBOyfPKl53Scb0i9G01hc1prVrXXfjweN4DV/tvQLu+Qj7ghxEjz",
+	"VMkEcJVdQ3wiMLz5ZVXMl8GrXndnd3BzPkATGpDlHa7qptQYCijPjMPWKi/08DMmUt3+GuxWHkJ50tVU",
+	"dzLqtzQhR0E0XiEag2hsJryo/dJxO7MeKpuZ+bspKza9KCHNB8r86IE3GREt4NNxSgOfJPJ5UvHt/cz5",
+	"jMAxrzpOrwj2G6DDD3oD61CVOySIxlpygu2V+LY2HmPOH6LEX7kC2cQrifcSBwFJ5uvaAEoXQGUfLipW",
+	"6v6DOcKZmVJdptQHn0woo0qvZOpFUo4DSaeXVBCkB6Sezqbqj0yRW2giTLlA5JFy0PD1ozWP0sQjCAyO",
+	"hqBqjeCwLvKG7sJh772OUg8zPR7X0kKbo3w0xeoCqlfXs6zERare5lTL56wnd46/RIku0DynLP/jEgtv",
+	"hjSL1As2w7b7NSohcUA9PIInwWVuUbogL7oFcPQwo94M+ZFUj7iyTNBEqsL1IbOULNQpKUmd5VpRvcZF",
+	"lEgS6efKzCi91O5rcfNA1e+p6teyNjzXyCvKSI/etR3VtHKiW2Z2TQMB13nFnOUyQ4aDBzznCN9jGsBL",
+	"tSZYEHlYlJdUEWU9m7Y1t2uYhRrrSlekAnM7GLbMi6vEhIOwi5cEVca4BYD3kJm44aSCEo4GAjMfJ/7o",
+	"7GpQNLLZX2r1/M9P8OdlQkKahvDRZUirJNtmls5FycCiRMxIKkuttbHy68GfwfklnoDpVC70D/mmydNm",
+	"PScWMLsY08GMoNtXx3DnBqdLOP3M3rEPW2nPEpiqq7bWMEvcFk0ch4DTG2bIzJmktjOz9FY1AFsUwNfs",
+	"4ioP+yEjj4IwEL5Vd0S9/6pMAPrzJgtsGc5m85gko/vRlDCiLDWLm/GVLNO4RXmZggyqIypy3xNvJt8L",
+	"fGSsGJYt00uIlH1NdNtRNZU/oJrl0enFoI5uu+YL/Hhz8kK+2J6WfArryjApR7A4JnPcQztDlgsqaF3a",
+	"najDIRE0qKxP0BVuO0NWYbe/leam2677cQeEofuZz77WFHUdsG+AIjImKGX0a5oJ/im9J6zEjJoosjnK",
+	"URRSIWwXQa3wSRtdgpkfhWDSH2MOC4Mwurk5PYbTRtOP+GWzrlFJXbLJHEUOY0rpkIqT6J7KSZrhj8xe",
+	"mhHj6girwWdRGvhobNFFrkHuh9EcslfRA7yRUi6ktTU7EfnhkBk93I883gypl0Q8mghphm4R1kh5ywto",
+	"C8s90NK7/O/3lDz8Aj81vIA2AiwIF/8Hf8vkpuxolHXyBEheOIkpX+RL+aNP5NOpvSAVdCgTXZoylx0J",
+	"dt3l3FVSYFeTuzwUpbhe6WbUM2rFzWS5fe2l5jDJi8vvKtKgTc0zBOUoxGw+ZNBs3dqg2QmxxGq2v7fb",
+	"XnlMGqcC4VZB9OeC7nFPE5HiAIXYm1FGMpmWr7RRy67129UZ+O8q/zz5kKINnOj2nBu7K8KZ3FMGQT6T",
+	"fkdwlBlp9jCLuOPqol2LtG3dHrFbB6rVa3pgaly1eq1vjer23CnSeDrOKLPEycQu9h0ct46xzsWCgjDM",
+	"Vjm/qELrjUrLmQkFXyojhtUN8zJKBA7WEThG2Ah6Txo+TYgnomTemqTMxyFhAgd84WtjFj00RNSQXTfU",
+	"kEtE2vH2yGRnvNvoeFuTxraP2w282+022uP2bru7deDv+Xsrb/Q5xRbXdkHMrNDyqswl5tZQuBusWKTC",
+	"0W0uRXXtiah/lTbfbJMge48U6NSy58Vb6/BWK7GFHW85JGBLy/GEt86zJddGh5YaBiWmpla2lKWHt9RV",
+	"vqVnxVuVV+qiArHOiVxaXqsB1+Id4YScE4GDzdR0p9WG2OotmGsS/ICk+Vr/BguU8bcJ5Xl1fX35dPAM",
+	"HsNJUgeRD6SVpJHq2BgnKoTBErUg/E+TiFEPRcmQnXxNKaOPCOZinM4VsZtIzQoH2pf4jiSMBOodXMrO",
+	"RD9SSu398sMJUlPLtEExIyE8juecJhV1ORsqYLSMCFnYZQyaEeyTZAlBVzp1vlItZG55tk7H9eNi7/JU",
+	"PuYp3/rMS1c+jQ1Z+W2sjpgOpcniqhyPkJhTb8hwKmbyi9Hj1DNtyrM4HPkstuBW3kvFLEroN2zeiwhO",
+	"wKNN2ix/dzChWpARTqbc9f4jP8rRh/LcDCjLDmBrtUrPPoxHAflFiPmgU+90drrtNnMa5Fdr5jwdFzjW",
+	"tlfzJirfRhAeMq1lPym8Pg3TdnvLS1Pqw7/IE6RGAX4dfDOVW/Pb6kuxbU5VRM4Nn4rxCyZB+U1vgiFz",
+	"7gKOHkgQVPkxGCOyIxhTfSmwFypy13rm6OwNrvqZIVutwlKVdrD2boK9MmS2Yw0uLrnkEF9Hx2SUqvB6",
+	"0fLGcntpgdhaw+9F7qlqb9CCJcFNu4UWH8jYx/ereaQPSis0HVLO5Vq/J+Pj3i3yoiAgygHT2u5K9J6/",
+	"6V+cDdmYTCItRYyXiIM11nwgLZ1FVcqEOtHst7uSrg4vWcinU8Jz803hJCo+FB5s+9298cHB1ra/Rdr7",
+	"eKdLdrr+no/3fDyeYG97f5tMyNYe3tnabxNy0N7fn+xhj3TJxPPJQfWxvYpVlwxqFUtlr0QteB5O8INz",
+	"GLDJlzxUrWxdtdCk4dTZfvxIRmpyP9IJHJ6yLbdTABwOP9D8fRhQln5bU1VSb4YlJnOxa7/XJ4ngfdA1",
+	"stNuI8Wp7ONYOOPB4RHMkflhBK7S+SmvnNCkaulL2ezhhqqEmTeLEp4Je7spyhF5FAkGm4Ty5hyyCU24",
+	"kvXQuGypMLAYe3fyhJhhsK+PCYpxIlY+hcUkHMl24I/sYWJdn08XN4SUnap2OiteKvK+nYuHBQ6iKYQj",
+	"u3wRvBkVxDOeCjnXPe7vjnadcRfmpBkt9Sr4I4SNTwJ6TxLijzDoBNlZ42NBGoKGTkouv31p3UWqfF4Q",
+	"MWKe5kxX+boXDreU+u6AluxaETFyMakd/nNlzEU5cvD3+soqg62NarzsX27Ww8JFd60aC94Eq2r1zZvE",
+	"RrUu+qeblgfu36jSZRrEyut242ovaLBZpYur3mCjCmd0LE1yG9W5OjreqPx55N1tVOEVEd82XUp5I96o",
+	"wu0gnpENedOtba3sCUOwfT+IUr9Y8XN2s1vegqolHxL54gkspUdBnOk2cxGySpifUR1XGARryBko/Xu9",
+	"LP+zo2qtx3S7+5UP6KrFxVlI8hnPwHPM6ETHR7qd5NYf3ILXoSNmyJxYUvfhTo01uwB4AcGJdsLrvzrp",
+	"vxncnIPDGFjlCZhDAOtGXQeUOzyEx2SPgPMnifHjK3ksrIZEgEPUuHatGGrJo809wNp3g7LkS7E4LieT",
+	"lhb3R01tuDxB5MlQf0BlCILS5bd4qg+ZMWDJW71+QA0oPFFoA3eGGFKsma1nhvoi6alIqTwvtlx6oFrt",
+	"1XfRXsAjFFtTLLAYCugdKUSvvCB+lGCkY0Z5fchs/swsXy8vX9q+7vIzIDxA3EuFO73LTmVjKR1F/nxT",
+	"fcaur3e89csV4XHEOFlfel3AyK7IhCSEecQlyPxSuGd3i0g/xAbZPxg3Ol1/q4G3d3Yb293d3Z2d7e12",
+	"OUrHqdAtSu0KeSZnl1/jv39Sq88T+xTS9Dz1/wdRUk9JHjEnjybA82fNjawTbKWHoAh9AjXAvcis7tp1",
+	"bwHqDCx5DugP0CyQcfm6uTo1u5Y8aomzaCyZQsjZvKF/aShv8NyRVuCkOV19/9dzWboCZ9GU/1S2AjsD",
+	"eCMVz/TiEOq1x8Y0aljv1gBB89vvrlPyLvpCV63Im+gLhbm4jSB6QEtJYQ6yn0qPUDc6UuY7xxF/rD4Y",
+	"tjAVeN0cXRBVFiU+SRDmxTIbeEia2anuXGQO7fn/+LqV1iFvffki6HP6Z65B5i+/cluX9VW46hPGPRyv",
+	"jN2MCRv0e5dXBKRZHvopH3qocNomns4wnz3LA+VoIJAu7gLCUDYrl8lJfVHOQ5R5QepLfeDtye1Vb13+",
+	"0G1k9HetZ/Wy2ZGrf8TSOeSq/mKoF6fwDpKRL5em7e5ue3vc9fEuOdjZHvtb2+P98X4X72/tkB28t+d3",
+	"x7vtyQS7aP4DJwlUsE/YZEaC1kFLWdxaxHe/hf3YAbTCPk/iiNPsLUnRSjsd6Fckp9FecXLBJC2b+ikH",
+	"0PdB54yDlMQJZWI0SfA0NECqpbg+UwhlhRyR/+aZHh6ex5iTgCptXIU+xgEWUukBOzJNkGebx9UrXEiS",
+	"qbppgJSuGzM1GzJbubfeuZtIXtRVbS9iHhaEgaeVrKmIxIdMtVvXYcQQEM3RDN8TbbLODgfK4IpkoCTl",
+	"SIfMWDx1l4hyZTC37hF64MUplR5u/1mTNGnMcOITecuo1WvRWNIMj2lAxbyBp0TjpWVyJsZCkEQuwf/9",
+	"J2586zU+tRsHo2bj8/P/crF85fU9tOwGm8htywW4OLeVDRVLS1s0leMcp4vRK3I7N/arH8uSnLGXdQkq",
+	"sNkE5couMCDtWwyHReaYB2yQbQrNzPIq69MJSFcxZPYjADfsrLYoUayYEKOSqq2hlA7FVkNmxlRHmGfP",
+	"JRjJF9agFKm81olTnvn3qn06oAAgfei4BJLgvJYbr82SNqU2BkacEB/5JKH3DrfjAk6C7YFcRzxCytfQ",
+	"w4EpzlFCeBoIwPvMv8IqG2+XbD/CKoJtB97FwJGZN9H7GWE5ICJJwAVXB9soTvCltpg1rn4pOgfKJzGm",
+	"XAUS4hEmgrleckDHsOYEtXMPiSmmrDJA38NsdJ8GkpogDSjhq6k/8LCOITIaTdFrQ7LtHYseGCo1rbwW",
+	"JPjCE70BlM+TdCikbKp4OA8twmLIfm1pvuSt36j/e6vU4q9N9DYSqGj9kXynaVg5cTplo4LtcsWU6VRP",
+	"Oau0rjXHTNoYGo1rRz0rDAGgvMKjSy2i9AbT9jAsUJkoeSO/argPpzlsyCx72CJNBA1JlDoU3XMNHuCn",
+	"xVgGc0BRhjjxIuZbrI58gn15EA9ZjDmXW6VQheeyCnMku/ZRlAqNx6wD74ZMHpYQeycwlM14WxKL39E4",
+	"loSkVh31RVDpFJSVnhKBOm0UUpYKhbqnNpPlJ2LgPSwnHAO8GrFsWPJ4L5VXvT4QybpBQrA/t7bqHSGx",
+	"DhpUYoTX0ZucPTXcY753solDe3kQBgMSlU72rd32SjdyxYROz9YjNQSr7wKUkuFvygHeigXzOhrP5WJq",
+	"2SLBOJNQSsooVVEqE/QlGnMbe1cJdYIwmmAapAkxjmkeQFPgEiJStvVFhLAvZ8ZFgkWU8IVYBKjX2LJ1",
+	"2pXqbEEXsFTYDASA/3cxYhUGtNGzSjYX53PFd99N3BeDwkiX3hJ+htHVZahab0awe7MHxULVDUhcasWl",
+	"7qw5HikC8oZ+/qoUaLPGupwYVi2S2CcCU3jVytwzFiVMQjCvyEeQEJHM8Thwxq8YDF8E+wRRjsKIC3hS",
+	"kW7RCWacEqkKwwOZ2uVoTDyc8qJ/nxLESMySSIhAe4JYuq52OTJHj4LzR3JsVJ0+tPrFZeEtWM92gYJq",
+	"QSyMRZ4CpFGtXtOSr1avxQT0nJo6a/2RPG0/21Itr7RAS93bTTxNsE9OOU9JlSeidXEp45D65LGoq+my",
+	"6hfZKMJxHFA4JGv1pcudD/vGen7Lwxdds+BEvggKB6oOsCBHcULuCROFFQMNfkzA817dmrTfNyMPQ2YL",
+	"9Tp6wAkDVTKPnUmIjKWTyoPyDeXpOKQKHJGKYiCSEtn1mm7FEW5U3nFmPjlnuJ7pCmv3fQaT8j12ETra",
+	"LrGobGWUq9XLd+AK3F5FpzV0YyintyTM0M+6fpDqIIsQZQZk0FwFQMGZRCnz19p8xaN7DRr//KfLHCZx",
+	"kf7vZwRsPA5Bs8CyhYVyauK6hRXxSGViK6RwiESkE6TtgprZiV9Y9p/zVpgPdE1TScliKA8VKXI2cGxx",
+	"CMFV7xbWsmX9LY586SF5u3hD/g9/6XTc+ddaAJsS85Wkz/SRcndV1NZuhg43o1TMVs9UV5fhPitiaXSG",
+	"GrMrM9CFUqSsUyxCwJ0LW0WvsnGXzhsVESLhGCzBeeCXtlUJPIWUNfIgm4JlWcpyFe5tUmooC48M7MlC",
+	"jUL5O8Tel29GKig9mRes/TCbQ4WtvzAjEXAZAkknjmO5r1B80fXZAEEZ5QqtJFfWqUIyXSHCNeHcwtte",
+	"ug1d1heDxQwJSstA5TMJuKbLVbad1oHAcGHlUXBPSvVAg4ygLqJCmkEMDhnc4p1+5lbk0XpBRFa4zQo0",
+	"P1PSim9aStMfwTtZsoWyvaNjzC06OyKSIg60cj+Lro7SSorx+GaB6xY+sL5NKNQJeEIlXL3f+FGI6ULd",
+	"4doBXvKOu56JsMQ4VqSjbMM2Eir7RV1bhTP8OR3wNrg8/oAGRxfnuaktY0ZZSmjgOoj4tGDBrJk5c3k4",
+	"FEc83XAllWhyyhHsCpfsZdwmxRl37Ukwlak8NmA6Ul0YE6haRANCJfB0/ReK0h64xlN3goR1Q93W5Tu1",
+	"rEv4bnGLr9q/lgFlk0vD1HkRPC6Eopn3kwXsl3yZIrZyDvBgrk5AFxcEgVnovNgCd9eVoTfLdpUmQfkx",
+	"82uK500atcK5BkJpadFy2IGA9ervmnE3S3k2jsJlR73x7M32q701LXhQC9WpsJmqR6s8eBs/CvrZhBlU",
+	"CDVSkYTIkmCYI9t5VQmyCgHj9F548e74rRtUaG1SVEkcRxRn3bD8GifiNZ5uuJ3cQuKq6JShdbbMIaMI",
+	"BCeKMFzylX9DzmgsFcBFw8yalJP1nASD1/R8fkvn/pBQIQgzk9S4UT2BAoKlbMmj5J+MMSdpEjypD9kT",
+	"BawfUC6ewOn3BGIlKbt7gnLiW2F+eepOh9qlGy5cZxa9YTyfNRPiz7ACRJErQJiQcauiJQ0i+6194+Qj",
+	"G4x4K+KtNUKmnW4Qo2k8defWUp8TEkfVZQikA/TdH+VD82q8gKbsQbmrl1yIKLcWzQEafnosszjo4pRw",
+	"xzs3sHKeEFf+VAF1Po2nTjRt/XjKF12cwE1BxU8kqDfon54inITgAKPhzmU9CWak8O/VC6rFhi0ivFZ8",
+	"R1tJHDam8XQRW0C7RWQUgaPJ7NNw02CQ5eYae2IaQg2HEZsWP1I3ELnZFG6GVnuINycQfRAnEaSHiZJp",
+	"y9T7u+zgF/W9sdWViA/dXelP8ksWP7qKu/ONujiIbAzyc9MjTEQc+v+7joT4Zb/BRUJwaPWM5f/d3Va/",
+	"wPiOsHREXGMsFTclKQ5oZOzLDkwtHlgX3dXm/mqZaPsjbXCAeDgLPl6qtroCucHcps+KTSxmuopTQEEH",
+	"owLzVYt4kABFIf8rbLF5GoKQ4U2/9Ssqg5NoiEXwrsn8RbRtNA8WN3aXUEVlUYGSlPEmumHyQUh78uE5",
+	"vLzb460X0kCae7xP4vwibzrNADU3xaZdOAYdpDSz3sSYdmwo5W6Q361ugN9B2QR76Urr67EqJU+VR7im",
+	"jFY6JIMoBB+GKBUlWM3xXCsyCZoClDdca/Ec+WwyZI2G7gT5ESnBAGkjDGVSwPtEvooR5lECgv6B4Lsh",
+	"K/yq0IGAgZRniV5c+bDGIfOpWeMiyxkEO24pH+GCd6SEYnhsTGgSPmB4paMPwd/yv1e6RTZHz//29+Hw",
+	"n8Ph53X9Iyd+tGqxXhxfmCN+fYaSEc3O/jKPtKXX81CqmZDPJAdq0N4lSKb653lmobxFeQAfE/2yae6J",
+	"sbzECY2WBclrZXv6o9Sp8hJISA2nuDfh+bCOsDUiC6FVIXllDcgXXnR2ez5kQTQF97n7KEiBMeU13Wox",
+	"8w8ci2TCURJFwppIXdpg1ReejlUbyhhboEtCFBCkGol0rtOvp3EUUG/umAiyMGmsJznt0rSBHHqRL6Nz",
+	"kRPygINgdSuq3MLpUoHbKR2d5cLDZ5WxFNZh3VFX5qWcRVy41de+8fFW1iJTEABM85sAVgsBn5Uokjel",
+	"xAfgYxGhqxd91Ol0txZwrLKOAZLzjLCpmNUOuztbdfcO//w0/3fj82/t+m7nd+vrs78/HQ6bGxR/9rf/",
+	"cuNfECa07rLUDcWUk3WmOS7/0jqmnKyjDt2RlLQjKUxXJiU7VTWUhCf4rii0n16ZTJhKbAzSOA4IeOs/",
+	"ywSy01O0iY4pVyjy4OsI91slcjIX2ypvRT0L4N6RT2TWuuX3ILsCUhXqyEuTRPnSGoPeJA3yjH7+lDQ4",
+	"DeMAbpwN3QRJYF+X+Krlk/sW93E1ZNBKryBVSkMYByuBC85UKambR36qD4rlWBGqmEJskybokRcxRvKs",
+	"sCXyqULnkKk7QXlZeTUHyWlpcJAY1tIPrHNYCV8T+KB9RbILIy/GL2wgEPX4+tm4XGLGzDRUaeEXJ/li",
+	"AGzbP30xUFdZriS+9BiZa4Qj7a3rmE4Wev7z5nMue3dORayMYnt7ffk9QW9WuFtCwkiQ9TIdXqmypdg2",
+	"S7mKIy6m2jlw/Yu0rT5YafG1hKrhVESN4D6sLTzBkIB4As2iB6Uo5kCfDzQIDEgUtCyB3p+Yhp6o7ylX",
+	"CGgpCwhXb0cJ0VkoQc8N5TFuawOUafdfD3Oi8ujpds5uz5voCbStUmbAIyOXv9eRdHFSrjF5FyxSKFh2",
+	"+030JMEPTxDUlCPLhs+HzNVIxTiLTk4Kmk3RLyPlZ+fDHNy3VtwPT2DUdhkFmGoEfo5ESVk5UkmqV9w2",
+	"wSgrUxAocTwmizc6hbsuEkru7audEdkXA0QFJ8EEklHOVWMsAuD63L3YlFYHDiiCErMVs7mO1bLBxFSh",
+	"OIk8wvkzpRLqjkecCI4mlARZftKF6VCO6JRFyUaq3vLLps7SurKVgSkn6/CZv7K8LKPKOm11RhXkfAYG",
+	"t3VnMxi8ekPcM7FwkFe2YpeVdefcE0ElPmuMExwSQRKOOBEIKzS5umXCGDIwX6h2mn7r4KBR4k8ZbGhn",
+	"wvnDJP9AzcVBIUFD8i1iKwXytSknH2J8cj9KjEZQMufInxespLJGC2o46QBf/i1H+o1P7uUQq95p178M",
+	"y7fbdYKg67XcDrN4O9YTsUFczQXIBNlNqLw8m7gg17MEYVymeItxkse5LnMlOIHySMywMNdpwgSybEwq",
+	"IZg744r7GvfSThWWzwbu0lAls+ZgNKVFd0EpIGt1C1mmfFQsGls/K63UlVKbJADrGjFuEIeNOM6HRRmK",
+	"PIEDV7av9t7OjtuPRMwc3WExM+bKrP2i4i43QDj3aVLlmLPY6sUDyyGnS9SUNSxipj+DmGUoSTnVz05W",
+	"VlbDIv+FlEn7jSOyy7Lu5LORWhND47nQhjv9E1dhVZJbHpiKoRLKMGtXL7qVGzNS0V5UyJTW3tva2+7s",
+	"d7fbRWyKlDKxu12xYzOD5yaOYtqqkMWwKbPp4oGgflfCv+pU0BhVoJ5IJUz6gQN4PODT4nACB3+ev0Yd",
+	"Itk3TSwNgV0gXSHvjbYdqdBU2eFCPnTdhnUTG7IAC5LoLr9XWpf8L31/5Mt42qQaEF1fQouYsdmUi0ZX",
+	"dh+SkfDiNaLO1zWqyiFa9+CSw4ta6k2GSLnHaa0uhzr5icOcRDJ5xY/REraVdqUgOAnmRrspnrM/NlBj",
+	"JqpAyQVjZJFzvSimBUOAm7Cwx4C6AOZMsYjAyaoJv1WRuvXP/zsc8mHt89/WGn0UUrE2lQMyEYUXDmvg",
+	"P4maMJ512XPZeOzLdTAPo1SdBD9lmC5Re/LTEWG0VuBADbaizWQNbGX0WShdGXa24P7vkzw6p9Sw2w8d",
+	"pvwnwHZmnv3fjdcpX402OxRfnB5faGsoitg4womfQaxg9byi3tygBOVIbdmAflPmMbgShJilUvdVjtEa",
+	"4wBME3BmZSfTQnC6aaDa0ColHY2E+6N1uGk3EOdIJICObIc8Yk+Zei1lJ2WjOB3LhO1DpiFdbYgWToQ+",
+	"6jOrkiKFcoY3rp6ufl3nqN3fSIJHOfIZYj7L9YO55aS50ENBr9R43nvjfc/fI5Pt8Tbxdv1tf3vf2x7v",
+	"+J3xnt/G3jbeJ9vjtrc36eI9rz0+IB1/a7KNd8a73p6/X4HnbY+aMsjk5dAmjyFNrnKw2Hj4IknJyr6l",
+	"7pzjt68L2659jvMfnIPKwDksXzPl+Tiu8HctVB+p2u60AIet1sSPGoUKdnzG4X57v72eWzC88VbfU5Wf",
+	"14orqt6GUUiQvElwFQSBgyB6IL72waYMLAN1fQEVM7n5l718ZoBLm718lvaHRo5bDEIhLIfYk5OsZ4uN",
+	"lYFnPEey9gh+1lG2C8xUKFAwKscBpqy2eBNXZTN5gQWuwwv27jYCglkvolqZVmkAKcPST1o7FxYMsKYr",
+	"1YzT9PoHWgxWBO2sZ0AANlO2A+pro0FmRPhTbAcwoqVmg93t7e8zG8imXRYD/fv3mAxy+qWGfpnZ4N9n",
+	"LXhR8AtZsBmM3EYD+7af3+szk0Eh62Vne297f2t3e999t6/X8leKotRs3eNkpd+yVbmeD9g9U5fTw4aq",
+	"km6jpCCpe/Yk+7g0M0nmDKcznkmhpTY2MIHtqWRsKCRrkjsjzSLnaWhM9fAZPZUPMvLKn2A2JfwZqENx",
+	"EonIiwIYZhSTkl9Ut3sovLhWr+239T9oiOPD8qV9dQiF9VjxXdQ2DchhKn9oBLho4Dng0LB45jLtJond",
+	"Xt6KNXNBAkY2DBQhbINeCVvsdCLimnrh/bwRAvwCq8tHAOd93dATCgBrUuYjFZWp4R7WdENSLX3Srw2r",
+	"h1So8dMCEPU2kdMpa5JcBbxXxO46qDPIqKCoIqKsba0PYd9PCOfKSVH7FJTu452DbrOzu9/sNNut7vaG",
+	"67hW/vqX/UsLcfv7APtVXW220fcwnSQUnbApZcROjjn9BhhbSOAEAf7gvUKrHLIiLrZCuNZ2SuPe60cP",
+	"TOetRdcZYjZ49gKcnmoCcKiyZAyZFTQbnTPQA7qrZgzMlL6jMENk2WLbGXZ3CTAVMLvlJ64xu11cpNdj",
+	"KVdmHajCi0FA5Zz7eMieaFzwJyhPu1+RhHFdBHE9iQpe+pHI4HJGqtJ9xPpa8hsWAKJX8dkCaSzlsSz6",
+	"MHwwUQu9q/MKFXoTFhlc994e966OM3b2Asw5OoImmoscYqO6uziEZIj4K9I9OXaztBHjkAbzCuhQpL4W",
+	"+dnYiR3gLvr1wjXMqST1KOKjCcnRxkpavywivSpMkdJqSlmgmcZwtjpeIADWRqQpPXJohMj8OaKJbk5e",
+	"nI76F+eXvevTo7MTfSPVfr+wTDNKAjnR23NlVIIg0lJyczQgJE+A7UkR05xG0VQjIXg6H7IfedwkP4YO",
+	"iCbU/wGqNCLeMFMue4y/vH172q/Va4OT29Hg7eWo37vsHZ2dbKYwFPMoL8ZgMgdMRSavpfoGrmpu0a2N",
+	"YZaICVMOLjA68EhKHG0aeNm/RDpiqK6dmTTiRNGpBtrS0IeyezUWV75ZDak6ZJvlmzXY+PmoN8lAG1CP",
+	"ME5WZOAxpQDkai47t6VxybquiMIhJLABfNSSgQ44aJlmWnqHaXPWRuufkGklkpNcE/XdSgmfLYLxZcu4",
+	"QUQ2QwC2hWIAQJzO1t74S8vWpYOpurwMCEGVm4Urcag2i6kjryHlVVdiPUwDQRt65KY48oKIE27ATLXC",
+	"OWRP1T8ykauEbVbtGXj8zyJOGJJeaiEGbNxgXuYKkjo1PUmMkeTzkY6fXXJJ0nSBeSNTHERTFtm4XFWS",
+	"/TSH7AR7M8PVQHUdsIVwRqnMAqC7ATtZE93CCJTVAsxxh0OGUAM9STlJDn8jIaYB9X9/coh6DMFfRiFV",
+	"Np+ExAnhYCPL+vJkE6g0rSZ6kcPp1dETLHn5H5Yp8klT96wvLD1Vb8MxqK51E1V9h/MGeNs1cBz/A8cx",
+	"jyPRnOpKpo49JDAxbUoNPX+o21TjKpHADynjThooBIfD39R/ZYewPdEgpSLDFXkaJzTEyfzZYudBoDo0",
+	"Scq1IMJC1y1TJN96T+Q140lpTO5dt5w1KVd1lHBQqiabD5mhb/lwA4Zb4IpavVbih3UXr6YNioeLZK7V",
+	"a5rA9o/ff23SInWpsrs8h7M5jtc7c/QJMSpnYsLcI8zHTDTGCaZ+Y6u9tdPZWqmrW80V1APnfIyNdgON",
+	"feqKT4aGEM0SocNaWSbtpwax6pkTDXL19bzU4EoqVE45z0H5fffeG5MTbmZLbYTR5c11DoMp77yFCFbM",
+	"kOz56eCZeSAyBgHwyLaCx6MJekseUxWurp9aINobzLsqhfmQ5TnMXffa9UL6MxwNWfwHVDD9g+m0oKK7",
+	"lbTK++hXL3roujbJjGCfJEsWy+kJUHgQVS0UfWFyfFdYjN7lqUIDKASa3pFYDJmFwqRxEpm2vlKeSWYr",
+	"n3xpjr/VPjRev0iiaaOXiEYvprXD2l3Bmznn0XWgrJYmzV8PK2xFlpicfwsXroxrKpLEMMm8CzliFHPr",
+	"m9IaofnrgylVkGChxQcy9vH96jewvpJYKmpVoZSrTYfyPcetdVb7//xN/+JsyKyXSoMrvBqUVC7EclFV",
+	"ddxUJo9fYxFaqzbduqO0EwF/n0w9DdX7Qs5n8KbFGY75LMpUft0TUvY+fc5lLyIGAv7aZtYS0AB4oEpp",
+	"jASRfeJknuX90Yj5lCOfBEQQy5aYDSSPHq56dfYIE65nu+PsW56PozyCMBUpYDpCzDuXNlLJWzIi2bNc",
+	"KvPVnnDWafheZ7s6+4x7Dx3nf5nhmDn+xKv4RhdvPCbBj4j3M2igPJuiBI64JBqglzjlrqGz44qnv3zH",
+	"4uVc0SxzMPXu1FOdtFLmhwknwrXSzvup8hZRvy/YAOYxqR4wFVztB3OxD3AyJYiwKJ3O5B3ScsNoomPL",
+	"7uw9druyAFKgL2A18PBjpwM/GjwWZgfe5jORlddzUXFlGK9QuJdD5uRyJNfZMLfMYLyuqaIzcaktPmRg",
+	"EoRwPCuFhbIvUSeqYaezvXvQ3trfsw449Ua9qPU681NWoMWcWjHdG8jVN4TEBikxpgv5deTs5IXOwJDq",
+	"yPG8YP7MLC0SSoU3jzL6pRKgEQRH0YN+wR4MXqlQegWlJDPOwBpYUVc64VAY3duOx8oPIOHKBVmOAHr1",
+	"onhezw5WGX6WucNzgMEudKbWW3sScA00SBK3vIacyqMQezPKch5a6h8TxmKuQp10tQb167pDe2AYhia3",
+	"tu1Svegko6gw4nw2kvOQ3mlruOlcQS0kyuRe2kUpRG7tHryI8TTUPpTAICoqv+gaF03Q1avBeQ6HldNH",
+	"fqOM0+lM8IYXUOPHtE5m6lMLmGAThUJXK7pYSJXhnnKNJ2JxiubhnPuaQ+Z0MVWh/Al+aJiNUeVxWh8y",
+	"6W+aFV3fAxWdUIMBP2SA96wxNCcUIJ6zfQLbRCqgekeCjynEqEZi5uJ309i6KA8npryCBVE9rlv5RVZh",
+	"6cKeWGP6/gVGGZ2K8AGLWx6Ku83l5Tafvh5cvH2WuelpP8GVerLu4vOSSb+wifkDs54QASChIMkx8ELE",
+	"SoJ0gQTOa9+lvTNkO8JBEMoLPTpvf/aVQ1Vr0imwYh5Q8BQK//1fYiLiZ4ctFZLhjCxY8wpSyCD4Y+/P",
+	"+YwyRLsKzdroXCtT/Ug1jOfICOtBIuhkhoWA+Z8R8p254mnp317En1ZuefnDrI7gg5RsnMBtrG2pEbJJ",
+	"eDs2IE8SdmtCWS40c+lWUga3uwfbB7t73YPdKr8+dYkeRfFaaTuLd9C8us6x5t72sk+FK6XqgRILT1Bx",
+	"UM7q1kTwNiMXAqlJcpl9jBMZJS6y0j7hgjJ15oDuqDUk00UTnev2hyxLzGn6QJijBxIE8r/ZMMw3o9Hi",
+	"kKA7ynzlcp0dU5tESGvwWtmui1Me+EqgmfeDs4zWpZ1a2FaFHVNi689m+1Yp+LKljRxDOFFpUkxuQwRe",
+	"c/ckKWEBrbPT//C0GtbU8+zNimnXa6BwOypX3kBslNtZJyFHae02zF1lsirWzKDVv43K7s6bVK9ZItW5",
+	"nQN6T0oISyraVW+SUsbRQshqPU9uq/atQvFwpHpOiDx7yu5E+EFOFj/wxgw3kllK9V/WPzmOsz+/KZLA",
+	"fxsEx3uFL8U/rHqgc+b5zdVfBp5T/5ARwPyQKaLmB5ceWqvXpuCiO/Wy3pQbi6kK/y1UkDptNhj1Rz4W",
+	"+Xe5sD2SKoW4Vq8V17GmE1TioKFwZyJPDi7BPB6TJJk3YvnnPZ4m8r0soON7mgjrF/lnioNx9Ch/5PGM",
+	"JCT/VyO6xzUl8lws9yaD3dro7r0Qk60DZgpIguApA5q81OINpxGl/auYbJ3y1GbC6iCavPH87l1wk9s4",
+	"lgbHcs86RDD8LrfDNFU51bXmLWcj9zNJTGC65A+INaaMNNF5JB3zlDJUIIYPgV96n+ZJ1TI5zSIeil8g",
+	"8nkZmm/1g9edgXSBza/dovTTj/rWSMCDXv+xu33XHLL8D0mpqJhhVFozlMGrPFpdzSfjdLqeweuNTnT9",
+	"HX7NebcvFDgzWBgbEgnZnWIB4JSLNbvtbrt90N5rtqstje7XBpmy0oEaLX+epeN1IM9dOUUkOQAGP0dP",
+	"olz+MDX3PI3x+yS35VnYjtJmhHQwWub6pmZh4tm8MpSDMW8vmF+3DpTrVsPDzIdt554Gvys/4293XQ/e",
+	"Og1JoWRtq1NbnShQRzyarjTb5y3mi/u5gsXOoqnTkKp9TSFmHxL2lTuHn+umZFXzVYocrOA61HFtjTMl",
+	"0Y/Bz+P7Hpmu8xQ+UkJlRk9jBVZQrQviLyRhlMxHIR0X7k3d9va+PpPk2d/d2V3m11B4A7kPi9dhC9Qz",
+	"/+eoqTA/t353BtzHcrW5IGyNxIrHcFFBGOWVNCHqeX5U/Qs8CUiJjxPYXXm+cT5LBXjiV1gy7704LZou",
+	"u8uTSq/rsKGX/g94X1Qrrpw1tMqgbKn5bRBedCS7wD1Ps49+Fmiic4KZumz45J4EURxCplmVvgOyyS4c",
+	"GLk3rOyJZ2b0KomUJ3lyvjjCgFYCfLo2j2SiKAoKK5b9a8EooJ0rZQ0YkiadhaZMmdvHgDrjcA0A6M3V",
+	"ae5Um6+AQQvQIW2MFEmx6NZfznpCwvQ557PDViuJIvEP2XDhNVyHKzpGrGY2Wq1MGGjn/8ZuM7+v2k5V",
+	"slrx1RpEMAn1jTyh0tNpXqs7JF4VpU3gbDFosxXQcUuzRNlhYeUpabfsFilcLE5a2lPc2SBkn+5MEPRb",
+	"xRcRCRy4PpWGCp3qLnR7pnK9EqOjDu/RwY/E3QA08Ijje7L6ALmeUZ7nj5Tm4HBcMG8pv/Kjm9Oz49HZ",
+	"Rb93NujdniDC7mkSMSkTcTBk9zihRmW2VLE8GpPje3Mom5sJjDKYy5dFiWdFeVnYyjGBhFUJurWXaq6e",
+	"KxU/WS8zt0WTSpqTDY8eVako1xfE+B2ZA2SKM785NxgYUAQFeB6lWkAasYFl+zwKoFiI48L+S3mVulEJ",
+	"5xNgNk3dGR2MrzvQipjQ5AyapW69PkixPSZeFBKOtG9zHd6BpYmFwXdlTOHEi5iPdT44y4mYsNHNoHlz",
+	"/aKxXwVOBCDpn3/r1rd+fzr6Z6/x6fNv3d+f/f1f/X9dXgxOPzwDTPVe4xNufAMc9efP/v70H1Dn+bO/",
+	"fyeW0blOdHecpcVbL13e4FWvu7OLfHfWPE4SA4iDOewA7Akk36AggxEVAOmWEJEmLFcYTHVJyQQvRDVo",
+	"NJcd3MYdfxt3x1vetr9Ddid77f3OQRdvjbe9HX+X7E322wedyu9uMPK50+/COcs801KWzk5lqtRvlxoG",
+	"x1dulgZZJE/un1GJmkRyFTNt+93xPtmZtPGBt006k73xLt7xtvwu6cjfxvsy/R3ZmWzjrXHX6/htcjDZ",
+	"x3vjXW/H3yZbk6ocd9gdoHhUeBtExO/u7HQOrPktXeUhs5e5OGujOoCOpaVH5nmftaeSfkqxeUfmzYqc",
+	"kMUE6JV57c5xckdEHGCPXMrl4rM/Iv152cv95+QQXyfp0mfnHK2EmD9phjikbtN0rHokPuqdn8oNnPIG",
+	"wVw0OgVOxiFttL39rfbewdbe3s7OwY6/PXbxpTfDTIFHj3DCnKqLVaRM+O37Np3thkn89dtO4PMJGd/f",
+	"e/v33x5XdJW/S5TvCPJ3w/CqgmJmhnrvB8gifR1dXp1c9q5O376sD1nv8vLso/wnGtz0+ycnxyfHddTv",
+	"ve2fnJ2dHKMoQS96p2cnx+Udb+r9KQ83tv6sX24qHkncfBh5dz9yox3QMA2UpxEzr45SNESpQNlrSiFL",
+	"3RxCAJWMGbJcQ6KTlXdQI4rqKDQX3iETRMU8g9ollVtd3tL6nEAj+ilolGBBnH4IY50WPrti6Kn6Zp6y",
+	"BcqmpTtiwQsYmfshEUWmaTc7kK0ls0pkFop2tk4sDcdKiZfdMs8RQX1cuqEvjJEyrdXw7xrmVts5sqUm",
+	"spyl1vYWDyPv7rC1/r2qyv/iPAek3ICHj9++yKAqMwDc4utcMdNikift8puoN2SqNugQBgjHckzMwF38",
+	"us4bpUH7VApB2TgutJFXdmPuQWMO9GM9hxwHpq49RM2qqw55Mb3cjLCyvmvSXHwN1soXti5oqJpU1cCz",
+	"0WU3MdA35d3iUH0qDpJFPvnCDzv7647x8DsG7WJwmZPkB6GavVkSsbkJUE8InEZcOXSqb4DKjEoZ/iCZ",
+	"MXxeDsukK6BTeM17Al7V6rJsNFsoxURs3I11PYPOvxpBOSA4HinRMnKDm72KHpAsZQSQcmmOkgQerNFT",
+	"+Y0TT1bOSfKsiW44QTwgD0MWJTrVhNI2ZYUGDwm2MBd5lkbPCyJPPozJ6XJB4rj8KJ6Z2uRX+Z+AyAdc",
+	"1YPzvdWeo523ILdTJtKZtHVz3V+wVJr8BVbGD0GSkDKixEuBMpHnpUnpdpzdFYFVn7ZKP1Qk3dJUWdvd",
+	"4u315QCq1CAlKjtVlTqrHC90N5/d22OQvdFtYAmy86flR4Oke7MYZOve4odVG5qO04Sv8TwxiAmcmxlE",
+	"McUB4nMGjKl3gvPFIcSPcRQ4nBnP1fmO5FewnjJBknsc1HXKv+hBReF0rWO6ZqkF3W3r9G04H3ZCyir6",
+	"puyP7nvBbF/53GWWFiUETk2u3jpkAwZLjCRur/EYchiv7uYSytkGvehe/62cZyJG+GrTW8aETs5eyM+1",
+	"GYffEYWx6L6UqRRkRvnVZTWGgoqS+Weesewz4uofQ8YIgUx1SEYALGRi1s1ypXpgV6Y3u9kho/4vRMza",
+	"QyZLyn+ShEm1EBhogj3SkPTRZYbsnzS+3/48ZCERs8j/RULCSiOrxi/o/JKDiHUkiljd+nvIfMbtAv+f",
+	"G5d2tfXfoh0cocVEb/qRpZFPk9eHzNxSZP0mC/OPOVpViU5yymu8mDZHDSeou+udsZ7xxBJ+U/nTNtM4",
+	"dFWN+GHyv2mjjQkTgZ8lywhnFiFZRv+3DmqYj/BE6E2l6gJYpdNZJxVRaMa9fOfC9NTGnamkX5btFoPn",
+	"nh64Rk7JRi2ZkguCIbJjeXyLlxAIKsLBRlnr+la1ZeieIROtOxrxuyJ/KI/y/7cC499yJnZRRH+uoxEj",
+	"wif34NsMudcQJ6KoCyeR9rz4ZbvZrdSHYTD1NZV1BWfjllWwUHUjqqTMPGwBprKSVvI/yOQhVIWGrNWS",
+	"5Vpqja1yMk9h2b9qUggZPmxpDDsXiTWFl80pdwRlkHLAoxNe+7xqf8LXjAyFtV+1V/tFZtvkopDXVO8p",
+	"eRZHO9MXwgYfWO9WOaWGZ1eWd8zEuPMlBPuLUALZm9mQmXcu7XVaR+rYzcGDAhpSkcMVwIiWOwKU1ohV",
+	"LJENMbBQxb1v7JD8tXopWw9Mfat315Je9E/ByVd5bfy4x0cGWGC5fhiMGw13rr5Qlq8KToQKcstN71pA",
+	"2nHmWdNq9CgPHh8yl3OafjK37KSqBXWdlK+o/TxsVceX2rIWWfAMqktnXKMcv6DjgIz4DDs9pQfwexHY",
+	"Ia+msccJp74uZLliLODY3Z43BwLLBzy/2es0XwREmpDtX0+21a8/DdnumPI4wHO04Dfxp0Wvp8ybOXKM",
+	"XvauerenV9c3vbPTTyfHNYdvFtBVNYDMpTxLNAzIhPYErYv129716e1JrV47Ob85611D6+X+Pq/lE2J2",
+	"3PeGWhe5tgQjZW+xAkEjj/qdplq2yOs0SdqYJJjdTdJENDpNrP/n9l+dLnhPFquvfiIys6kvA3y66J/+",
+	"iJdF5lS5/EnJKfAyhFjYAyN5MtBHl8Vc/m6oz1zwPgY7VuMNTaLAz2AohkzBjzZRv2yx0qFLCqCxtBm0",
+	"R44CLmy5D5hkRB5jmsxHsyhNnK4EEyKofZsgDQvGhfgZnFLFhIasu42gcQsAfbOJ7HWtu/f+3m57uc9i",
+	"vaahDEeCulA+jJ+csBD6FpbBlqeCLqwEWoCsRT2FmGya4Ll1MYdG1vZFXE3GzGSnm1Odg+XODiFbi35a",
+	"BBkJX6vXTtkkUY4dPRX1s7boWS519B5wp1d4iwW913lFCseiPMvVS60BhixmXmAtuVN4jD3SGrcU4VtR",
+	"K+LgpdxQa9bodLe2vwc5aSUn6/l/73vLxVVv8COvh5cpnxVOf1BtVeSVTj/JpCqSpXpRTPuA5+jXKMEc",
+	"xSmf/TpkfmRSO2ZKBMxOKsEBnud7YFlWU8xYJPCKaayEf+nlrSz4XJQGUcKESabNKCYsC1vi+kjKXPRr",
+	"B80tJ1yMadCCX8lyi8dxoLGoWvfMb2rGMk13Fu3WFlaLade8nlPBs8m42DEkPsUrBhF5gohG9p5TuvjK",
+	"BpCwhqBWbxYFxXfkop+C1fxjQ7rcNiSezPqmpKsC6J0986KQzBiz4AQM7lDKJXGCqECSGaXgoswLUp8g",
+	"465ddg1O8bxJo1Y415csdYhBmNuqi1IVFluCRHRHWJ5xQ423bqXZpNb5rEeoUsPrUZbrDteGcXPGzlzj",
+	"6SJNjSaMbm5Oj9EKF2rJ9D+Ey7YuFTTGdzUV1jlFMoFY6dFc4ZN37HbGK+/EiK0cV33xfX0Zrx06Cew4",
+	"AOrLfLY0WMDhYnZEyBflPKh6CrVOPoGCw7bKTcfzdLAA8iD3vW6liU5liCbRsLi/pknwq8apMsHq0rAr",
+	"G8xQpbPGQiKwjwUGLmi6CafSTDkCBfSjvHrbx0jFmaKnmsKHqN3dbW+Puz7eJQc722N/a3u8P97v4v2t",
+	"HbKD9/b87ni3PZngZxo9c5xg5s0aAb2Ta6lduKz25PK09lsqILwl42SflbbFYgn3/WRS5IQ1q814uE48",
+	"j37RlOGjRJNGYU8XUJZCZYZHT2XMWkBiKsGwNZCUAkdSjAbGZ23wBZCrHCqwifoG/aeA9lNYZcyRQvUp",
+	"lQEHh4yXMj4AtDDNWBVWY82262x84P9zmiRR8v26kNJaVECq5jEtAKxY6wzBR/9pRa4OmVagwkiQApZr",
+	"ZgMyt4AmurLgD9SbmQ9vZirZwFP+TAGyyQWJhY0qW8hbx3NRaXrTifV5GkpX68Xv+pE+jX2VSlrFxyAb",
+	"jkHBSUjtTtkcVRA8EKYhf62jlGfYSXy2abjS+rimhhR/FL6pnbVD56NpfOtaxHKC2yhKLMBq/tg5uWKq",
+	"33lDgH1xBQz5HXGSei9ohla6WBxE89BOn2i2RUIMT6mkAehUgGezzRpy77y8fAkO4LKCZVMHQ7rqsKU6",
+	"5E0/uxOrTuDqCmpEaVPWURH/oG7vUAiAL0ASFMLNrUDz/A7D9Uw1l5sBQJyJ2k6GJKyJrl6dnDmrGdoo",
+	"YCaWRbtL7jOEIbnEsFFdgDXEjIScyBd7ueNkUk2mXtPhq44gDN22X5Cso0rmV6ODQmVAJ6V1p0lQ0Abk",
+	"b0Z4g8aqNGoHnlMIYjigXPyiflkO7lSvTeOpRLxzqCiD/qm8fYaRPJ908IDhn5y+yrfJxA6o9AnoOl+1",
+	"zOOuUES+PlPR/N5Hb7Vmi5mYrM2iWSKaOPLXKD7RDoI2mzwF3VCycx2pWPoGjYRL/2ho/cEd1NOscshZ",
+	"ZbXINv1SGSj7dklAazBq8X8GsFfZ2X5Bz51pTWthshXpviti/BbDB3TRuurBObaYsEG/d7mhSVj7TFTl",
+	"IxeYBlGi08ouNRrr7q+zCo5EAKanZeO/IsZGXb6dc2Bvw/appXSYupLppe0AODtJWe7Cqx+vJM6qtHwI",
+	"8H9KSI6mGHEPx03Ug4a1Q+YD5lmLJHsUk0FPXDtPU5GXqPCQTIwr8lqOeBkV0oCoGa/ODwEdLCVp3tgC",
+	"xybZ7/nemtBHd6LBJFXr5+QUKgKympdNE3XT87KBX9vcVxw3JwE4kBYou/IRL2XfU8+l6V8qx9tzfRJV",
+	"A4UttE3iqOKLEfbL8FFc0WChv1P1KQ8Uq/SKWPhggYGs5RtRifhRV0TIxigjTS7TIJYJen/EXtzz/QVr",
+	"sWxXeUPYN5As81cGCyD3rLIUy4MYlG7lE2eioXjpmuJK04g5cT8AHOkvKi44O2fZtNRoPb/t0okRKqWL",
+	"UVad+GhOhFs/WC+zhu1RaN+m/1un2MgH6kr7WlhoYAHfL/DEEnhy7ZUom12OBFSWXfmIXFKryNpVlj84",
+	"AysTJcRpEBfUGflDSyvI35kpIeuxatDqdvYjD8s/viM25YCrwuKrx0CH3eQP4YNstusQtIoP5ORGlZal",
+	"MttVrt/V0fEfAEUjcwBdHR3nAlZ+75N4hmQyAUESyxFJuhZZhhb9rg/R9ti7UzF5SkMT2LtDUYIuk+gx",
+	"jB5NWy6taZm3jS3ZskH+SZ422bOte5jwyYw1jqJgEUmm/O5R6Non965ec5f7gtHKoOEs5Bou58bJ0t4s",
+	"ZzzoZinTLffNkXNye45mA8t4Ti2K7BH+Rf7ZUr9kBFY/f9Y/5+k01e+O6a0fR2iN1jnb9cRQMX3+kPWE",
+	"jO7hBbygJ1J0pEnwROYJzMwT8BcROKDs7gnKKQmWV8CNs5wvTicojLJI/lAhVhRT50WJdqiJE+IRHx4V",
+	"qH7eA6MOlp6ckIMfj6N7pwenHqj7lPJ81kyIP8PCAGvD8STFOzwp7edvC7KdiLci3loDd8+bEe9uNI2n",
+	"llC0vbfhM4hDXWZFegQIH+RoGk+1yaWYNcVSIHKLkvMFYBpPnYYhYwMy0V1S487DRSlbeMAo8GlD/u/o",
+	"5OXpW3T58hJd3hydnfbRm5OP6Ojsov8GPsvgivDd6dujlz1v4EVHJ73js8n+x1d35NvrXewH5x8f9vDL",
+	"l6fBaxyI/ddfuo+to+6b57PTyWn6+FLEt1/2yJCdXU2Pb/Z2v+Drnfj2eCd8cf56K74jjFy1vOvw69d3",
+	"d2/n7/jsQzd69+Hh5NvNYNzpvz3vT/ovp3cf9t91h+zbp7vk1OsnL9rvug/Jm3GAU39285zeYtY75mFn",
+	"/+PJVz7e6d1s7fniJjnfevfRfz89uHr+gV5ObvevhuzN0Zfr9tb97dGFfz7gH7cOznCf7Z7GnYv7eP/0",
+	"JGqdkpPbj52vYf/isofftMevX22lk+l2PyV3/Pn1YMge3r2/Jv2zx/TT2e7F+Yfo4vLNw/35u8njeNr5",
+	"cLx/n35qvxFfWt7bV91HnLYfQ95LD169jsnd/cXl1WMwZPOv4sv80ySJbil5MY8fPk3v3z0Ixs73W9PB",
+	"Sdp6fXudfGzvdMOTm+u9vjfe277zXr24fjE5vwvY3cvWkLUnN9u9K7zT3n619filfSfGZOv+jXf5Ibq8",
+	"SN8c3fJXg/t2++blx978kqTz5/t73k3r48nsfO9ua3D75suQ7ZLTT9M5Pb9oPwSdjy+Pr954afBwxw96",
+	"z9PgbtqJrsfbfOtb+On+sr33Mrp+fL/d/YLf7LwfPH87+ySzQO/vtj9Et7Ox13kTD55/mXyKvvDkRHza",
+	"vxzffHr+8f7F/lWc+O97yZdX49d33dfx1Zve4/Xskb/r8aPZy86Qtc/Sx+57fH7UnnZPdy69c/91y/v6",
+	"JWrve17y5ehDSh/fJ3SHpgfnH+L9r9etyeDb25D7p1O23/r66c2Q0f13aTBJ9/bSr7P3rQfRHQtGxfSK",
+	"f/0yezxPv3y82f403p7diRf7szc3rQ8f9ra7X2dnO28eele9d72jIRPHL15+en9174Un0zfH5503g97+",
+	"p/D2brz1enZ2fd45+3A0x+87M48FPfO79+r1PQ5vv/j9nfsh80LvOX33+uLo6Pyo3+ttv6AnJ+TVbpjM",
+	"XrzaS2/5u7Pz82774473acYeP+6/6IWwh/ovH/Zf9B/uTofs6OH05Yt30et+j/ePjj72ew8n/VfTk/6L",
+	"7V6vP717l9d+/vZjr7V39DGeBvNB79PHV7Mv8zezIWs9n+x+u5zc3o9fddsnX7fuTvcuXhy9bbOzD8+P",
+	"bjphej94/vU6HWy9P0uOtsKtl2kg4jdXJ6/fnIlw5+R4yDrJy28fetF1Zx4ffDzdP+sd++f9/sX8S+8L",
+	"j97f7O99vEn7z1tj9iW5Jlfds6uL/mR+2d/bfX+wv0Mvbocs3Bk8H/N3xw97/e5ZEvi98+3z4zSaf+oM",
+	"qHiJP22/eXd2K55fn+DONuUfBy/7X75Fe5cf92+3Xl/c7bSHbPr1/XS/+7Y1Drsn3wZ71/tb70+Ox53g",
+	"/sv2aXD/OD39+oZMO51vHz4+hsnHwafXr/uT+2+T58HbwW76OH01ZF8eW6/b8+BT94yOXya7L3u9+cXB",
+	"zfuk92nwMDhvn3hfrvcfTvrs8W5wnM6/hu8fbu/fHn1IT05v9y/I1schO6c3ncnrt/vc3zuO+YvHnfPn",
+	"H3x2zt4Nnr9KvlxfvjneCt8nQc9nJ9cz/+Pt/pdPd/H72fGcb7UODsjFkM3u2skZm7e/vH24w+mkRW/2",
+	"L7zdD/fnd1/Ors5fT3duDm7fzF+n79+Lbw8f2Jfztzvvr14cfX2zzT9F4fn5kE3E+PpV5/nOfHz1vtXb",
+	"uj8a48er912xd/Pt7RfvG7kbfDqh+OztwVnrlfe6f3rVefdif3e/e+z3gpMXB/6Q3XWn7+jHwbsexq/b",
+	"r1/3vr26v7q7en12Nn3T/fjuI3319nbeFVuv5y8mPMHhzsOg//5iMrskp/Ozo+tPr4fsPonfBpdjMuHX",
+	"Bzt715Pu0dvTdPrtU9LfuX08Hry5+zS9mnVuX94PTt+x/vzb3bv57slN9+tlTN/vHEgZNbs8/fApeRN5",
+	"b7benA0OWvTb63fXV4H4ct77Zch+uZxc7w0ZnC4nb4+XHT3OOFoIlB5xHrgPaaPIuDUHpfRwBzqvqfd3",
+	"eVr+ol8ctrpSvevuSjvSLxku/io1ItesFgeRjUF+bnqEiYhD/3/XVqtf9rVbmtWzySEGv8D45LX2YrDG",
+	"WLQyIKFquPOOIC8euhCShVTSMls3wVyqFQC5BaYukwgbQv2H7GlMYxJQRp5lKSgBnDhOIo9wvpAKGb7W",
+	"6rWIb5ja/ad6gxQdPlCFv8eaaZkGg1dvyHzzMFzHS58xLcLLXpQlUn3C4Sk8SiRUFqTFAqNaEfKLzxoG",
+	"cavX6/X6W2+/4X4n+HR82nl7fbIjfzvtDd5TcXfxavtmf2/7xOdHN2wuxlvjh/ur6fRV8C4Yf/wQ7LFO",
+	"+/6gwquLk8T9ii/Hm7/mGp8IOZFJlBRGCkmr1wqQUqGpzmvRQCXF3tRUZEBQqrH8dLZtG8HEcqi3E4rY",
+	"TxYJecBB4LvlQSWggUEjWXM4hK01GjYRshzfcDBO1uYz/0dhRiBL3WJoL5/J/++PdF4kv9XuNIq5JQB9",
+	"BDDMBb4j3LpPDlkWV+90utE2mbeRKCYtAxeFPTC479fhRkoTNT5jkk9kcFkh36+ObYNE8+ABJJPMwRac",
+	"4XsCzk4SWl3jawaRRDnIghqdXgkAwz6CHN4OoXxhHDtCEo5zVBSus35DuIPuxr38DzNCguoH8b/9/b/W",
+	"hcpRA4WpV4+TG+Lk46pr3CLoX31IiOzGEznYM5B/bggmH/2HFRMCefGPPERfhvCvmt/Tf+jn9rVwLXPH",
+	"5lHJ5cipZMTyjBGjJIrEKIimKtTURIHMYeOxSC37jI6paFiOWZCwwW/oJBC8If14nLgvYBl1mdkSwRXP",
+	"ghGFcUjYl4dFynqo21WRthKrPIpJDpE5ZEZWGfdjE4xh9h6RR30dmuEatULMMEPdbpHhrVwCMBqd3mpw",
+	"ckZZ+ojiKKDevIwdZC9wFmm0u7OztbMq1GgNWVXK6VjadJ6g97CmxsmmYGLlxEuIaMhPazrUSdOS+yVl",
+	"0US1hqYmHat/JOSjD8uHoBk7KzlayDeqT5TcO0GhKwCgcJ4itL7glSY1sBa0byKDmvBXObN7IbqjdqgS",
+	"7EyxIA/YneDdpMMsUFIkKXHZwkzhkcDTH6HXNZ5ya/baGwOO2VPdBUjx+uLRVUrf2ZIjac5xGEh3VlBg",
+	"eJbiE0UJSmbeQvp7CxhQ8lkS+amnfRw5FTDnhEVOckXJFGdQQKWcJdvtre6225naW609q0ccHKBJgKca",
+	"LVqOXv7TcIZFM/PAjQMeGVAIoo2ehoaliVetqn4SW9hONuM2JQNau2rlpioplAW61csCoTAGa3db7OlU",
+	"Q+fcE8HP0P2dqXkSHBJBEnDYnwbRWLoISZUakObl3Q0UjUYOJAO3nZSovNsmXj1rhyvnujGhgF0mpNCW",
+	"qH9Yu6VDF8UVq92HzRA/jkIcjyBqo3jyNv5unb1/K6Ts+FurUYGdcJ/lRstZd7fb2d5euYZVt4FrCxht",
+	"E+/eDGluKSJ5DlFXraczEedocJir1BnwfCTX7vQS6ede0A6s+3C7yaJEzBo4JAn1cFO+QTWZiKVVoFav",
+	"dZZ9Xg05eLiuqldElqviS1Mq+/sbks8WcrMUXUAVGF2+vDeD1gnmMMAfB5hzCcUbn9xfxBunO526kimq",
+	"DF75XpybzGTyGoIiRuoIYmZ619dXv+Fk+nsFIHiRwwc3R4OPg+uTc1fpKC4W/uWXklPxL7/86//55V+/",
+	"/Gs4fP7Lvxq//Ovwl2frbi1GxFr7CkZhWvhcQWPpzLchlaWq6w5oUh+yA9ZKxib99Nx0ciEVSfGlkMXA",
+	"VgWNwrJZ3pprZwJVjLQREKEclZNgC64RbL4GqHbv/eCk3y2nb11ZZ7C1WZWX/csN+5C5Fzer0jexBptV",
+	"cyT0WFVlAZtiVYUq16N16i26EK4c3kKQ+koauDI9raq04I+zqsIi9PaqGq+I+Lbxgr66vt6Q2W4HkIxy",
+	"s0pHOAGf2A1551blxYQsgaWan906srGTT+k9YY5ExwCTSznisygNfJQQlToLTo+LCRqnAi3uWJU3GnKC",
+	"yNNnyByCQCV/AehxHYAqr/KOggYdY8hwQpSKruzgC/3irKw+5u5ppNDUFXwBuZgMGbh5y85JAlK6jh4I",
+	"2APMNQFEG5KfYXbSQvCAQZpjodJ1ALJGHHFOta0spI8gtUH9VG4lekWQiKZgvZfKaCZIq5Mia+QB8NDg",
+	"aViZhsMUKENpq/o6sYjlpa9PJOmzK38tX6tVWrOK3Bvjg22/uzc+ONja9rdIex/vdMlO19/z8Z6PxxPs",
+	"be9vkwnZ2sM7W/ttQg7a+/uTPeyRLpl4PjlYAdYH67LRUZKlM177JFmzRnaQrNtDfo5sUuMoiMYb1Sod",
+	"PmvWKmOw/F5fD69oo0oVbpqbnT3rDrAMB7DRybNmnbJP3vrnzpoVCsfOunWyU2fNCoVDZ806pTNn3Z4W",
+	"jhxT8fOPZN3I4ypWV1RpwysSddRNeIUROZ9LYnjD/OZJylhVEvNC8v0F6b7xhLK075a0XF25Kne/JkOp",
+	"yWptP0vGvpAGvcm3spTiJtu5nR488mhTtcYzkCZwyFc+5eYv/fAbJZhDYnCT3DsZ+7U6JLGo1WsztVvk",
+	"v4SIC1m+xzgh4O1gJQSHbL7uteEbQ6gXTt4FhJH8L/T05Un/YpD5DegH34UhAG6aMtT5zjQpx1gAYK++",
+	"pM+IQQxFUJXwDCT248ePHxvn543jYw3+KsOzwfANKpeN+QtZyBdfLwoJoncanW4D8hRnhsiqZMjw7DPK",
+	"XplUQqA1fEhhAtqIp+quGGaGaybJOWQ6M4PqT2o3hdrweFWFOzF1gRvm0IY6iZ16lSytYUalTtud8bnq",
+	"8XOQxnFAIEmiaZqX2nY8EEK5zjpmrlkUOpNEhNaDb9Vcai1ZuyV/7qxl8VnLI+Nt8vLNSXL+kT4/P795",
+	"SF/hq97r8OosOv12Nel+Pe76xzvf2kfXj63dx2UR2naGw4rxrY83kUJ6Iw00QxmKAyw3EHmEdPA4kI/k",
+	"c+Ql8xigM3psyEgYi3nOozINBre3YhNpbHxdKyuqUgLNh4wwyHtAWXnLLUyEz4gr2cCZZGYEH6uXMOVJ",
+	"a0yZdAyfudpOXUwP7i2nx1Wtupl83YzOzovuZmZsVVfR+z70Icolusd5/Mx9nzAZ6oJOqH7QdYRpwMuu",
+	"/CJNulyoS55ODaNftcxXD5qrZ+E48haX11I5U2ycFxXWw8gDkrs3h8xQwAsBHSc4mTsBHFQHRQ7v6x8d",
+	"y2cAH3STy63Zpf6LVLHvevnrugYpM1Nt5mRW6AuGlnLCF7cvkCBhDHdpN2Soawo5fYuzPs5/r6gFQypW",
+	"yn7uuA+lwHe5c92eF2FRSwEzhYlkM3R1MIvKTpD3agqlZCoLFdcNwCoODPjevbaS70yQ1pAtRmmhPy5I",
+	"y5a760H1WGg5JS8YykWCRZT8Q+tzTcg8u9K8D+tQXxtz3HUNqgJVM1ttJCk8Wq4yuBZFA+JZ2U3VUtr2",
+	"Fo0oVapeWoJxF297HX+3sUN2Jo1tvE0aB97euNGddPwdb4/s44P2em9J1ebA7xfL4yhDJdZKdwF9R6XD",
+	"SaJ7qncdRjqgfcjgL6jPkB4agrEpFDedaIaGWnECLhUc9S5PNfyLbmkhEB0V4tDRnDgRScfR4/I9OI4e",
+	"MwVbcljLwPJITi9uEgP8p/yr3UG3GdbAcs34ShVUFNUTBIAxQ21LhteVH9MD5aACzzA3Xky6O19VBa8o",
+	"wbOF4BrOyXChU00GsEvHM6J2KbahMM21JHpgJgRZUvcnIHjlGYfqdobhIruswLg0TvI4jpuaR9N4LUeL",
+	"AmhD3uDWQXM18rgigKlvyPl5rW1ZJZo0y27GeWbRizXz+3UVq/puH8GfR5FsYFaXTvqkASOJzhdajQiS",
+	"jyaLaKjAC6oS4/d2R2bnXwxuM1+AAltdvRr0Gt12d/uw3W53lgQpFAcXxYRxHqzNbJ3DrWa7udfobjdJ",
+	"cLBOTuS8Y5vaQCYXed8Pzr7vGMgeZDQgIA8s0V9HABycvylo/L0M3geyCQGChnHTW5TQqUS0XCM7mZpu",
+	"Odq+KUdUQOVSDeb4JUOWpbQHsKKYMHXKVIhEPYzRkmCB94MzaX2AKFXMs8tmAuaMxA5aVs7FGQJewUO8",
+	"JMEqr7727KqSaKgxF3IGFIiSkwB8qFSki6RTaRDSx9w1BrV8fmGZlDtcCbJCkmChd/DzMk24aP7AA3Cl",
+	"d7lZKLUJx9IcqzgN/BgfeAAu9sU8bEzlEPo8ZFnCvF8ACnY9+GE5U+KlCRXzgTSwKhY9IjhRvDCGf70w",
+	"58nr99e1eg1MsTAhVS5rFayXv/8Ofp+TyGEv0r6s8jSHsCOV7AlWSt/LmmAl9YjO3K5Wv9aLsTcjqNts",
+	"1/TJmp1/Dw8PTQyfITJL1+Wts9P+ydvBSaPbbDdnIgws2KnaxeAIuu+bPPVgUUU4ppZwOax11SMeYfLD",
+	"YU1KrI5y9JkBmVpeEDHCW79R/3f5tzaIl6K+iSilzsFIm9fl1pH3GEi6o/c4cCs2aavNw3SGN23CpKIE",
+	"lINcNoCqKFkPDPtEorXCcwBRtthTXw2lL0c8MI8GuachPE26ThDVuh68iJCco1xe0H7EzCA6HdY0TJeR",
+	"2WqvKKP9H5I0/7PsTaX4h8XottvWPUf+08Z4/8LVCZQPaOlTpEUlYOciZWyaSBbZ/old61Tui52eMuUQ",
+	"oDkDUV913fnju+6lYqZVY+BFGIjqfeuP7/2G5cF0kgNjkkjeQBlvq5Fs/ztGcseiB1Zagp1/x+rfMPIY",
+	"qyTIRJZR6X/lTrNFOOxiI7z/+VnuEY2obJLwWUIIhFfGT9BOy/wh1dHIBTnfhxuptg7q0nUUR0IlkQsA",
+	"2YRrcGKIh7snCQ4yoxvLsswT7M20FgXZxjNnHL4ouC4jLrSs1kKGcHEU+fOft+NV61eqabUCRWH2+4K8",
+	"6fzs3k9919Lrj4AoCY7kxP/ThE5i6POX5PlL8qwtebTQcEman6U8baAvGRquUJRUqU1Upazh/2XKUoFS",
+	"Dg4q0uUvhekvsfUfqjBVyi91EbS1Jof+IovkSswa8sQSVv+NpMgfoHtZlIGG/93al9X/le7ExVKSH+BR",
+	"3Dw6q8A8/UjjlmvSC6MFDhnF8ZRJu7b02v5ZHbj25u+FU1uSpZBsZMkGUNm2NzjH5V+qkvnL5Fw9YVPK",
+	"jFlDbrw81bSI9NuIzotYL2CvA2eajI+yxV9VB78Omb5zKH/AZec9+AafqMlscuj/rznmbQJV7JHismbr",
+	"aImz5l9KwP9mJQBFRZ8m9aitXEP+kxQEI9UqGB5b7L4oMeVzyvfeeyaUUUhzZTpAS289VOSXHYVfDwFG",
+	"IREYSUN9EirTMR5HqepXpVtYJijP5PD/uhatlJdApwpBCS9qJk2Rik3LTGqUIRYBaBv10gAn2kMDPRWz",
+	"KJ3OdHTY68HF22fN/3Gqh2T/jDjLt5HJerl6L2Ul19hOV0SkCWD15PVgMGC11HKL2YA8TXQiP2WF5Utd",
+	"lIRZhiW9fD6ZQFZqLJD9gGVAWADbELMsl7NprrmzZCueZyT4az+u3I85sSo2ZWG5Fzbm/8y9Vtwe62y6",
+	"5I6IOMBe4dJbDg4YQ1IFmbT9/LRwIOYOxpkvGKQ1lOU0uI7cXr33gyE7z/tqyl+Q9YNyRqRsCuPunZ9q",
+	"xJSUNwjmotGpw49DBr8qdKyETMG/AydSHY3BlwPiYCHGQsHHWS542PeVG4W8hqiwDMg5myWLEQn27oiP",
+	"UiZosDBA4y4SJTJpyxelb1DhfuKwKl6qBDN/GQpKsa6LJPqTnmxcA1ltOrAYSxkPTCIh/0+8ERl13LMe",
+	"mlgkd86faihcVwvX5HcLGsrKW9Ipz6zEXMt1CF1QdbKgN8jXB3kdwCC/csU6AXWCSMyBmDCf5ym5jc0i",
+	"dzFbpnRnCcT+OuhXH/SGVlXnvFnKTc75vywUfz1T/He1QhQYern+prNiK2zzDY22MpV2KetqnnY8F7wi",
+	"khrTQlbxVRZb1eJIjWwTw62dTP0vy61LIBYoVCUU4av23bFyQf9lvv1LOLr0xdBABWnO+c+03y5wfbVc",
+	"c4rTLHXqaiOUTwSGnOUyGVRez3RssI2KL85aaA7ZA0lIWWz+KlsZZRV/VTfYvCFI+UWnTEdNaRxvO1JK",
+	"xSBZgwELsamksJgGN+cDlRkUJ2TIsmsLYjLMXNm4wqWONDmN/pLOLveZnD4VsnkFt/wln/+Sz0X5XJAB",
+	"UkarHf2fKKHXlZRO8ZzG0wT7SyyVV6QB3IMFKWDsRxOX+wPCU0wZFwgzsCgOWSH0RwpPhU4ObVFwXsCC",
+	"Qvwd1ah8SI/J2jl8yDhYTAXxtV1zopD4LIkvG2eRIidHcBxMopTJAPd+QnzlhM014K2N2SH3iRbsGZA2",
+	"gK7WDXPckVgoYOqCMQiqqBLGilFXryAmpyS1cuNLu66EF5Hjc9s4b9TE/3KEqj4KNIk2Mmy2/7BBLDdq",
+	"qofiPFYedhGNdE6RBS5/wKAsZowuZdEf4Ei/5uBdwyuO7U+0yKYsz4VjC5j/CJvsFTHxfYviU5knGHkg",
+	"SWlii6Lbjl2ma6jXEwoIdtwd+8w9zFyKtVx3aaso6dUeZqPSALR2nSVtBeXaw6ygXVsOgild7kVxW5rf",
+	"X6qxYzeXiVSxm0tLldmrzFr9pSP/pSNXvnmZg0nt5f9EFVnNcI1NUFaWoWNbtC4IKxi+zJyxKJ9cs86L",
+	"tGI8JZXgqlY5Tr+R2h8qS/I5uPYJJOeSxNHE+GuD/jkbVG2C/7z3F5wxkEQyyFDTDTfl22x1uBvW2BXM",
+	"y7ym1chyFLTxHMFZ7N6o69+piC7+Q2rE1r9ZKahcSviA7N/+2sV/7eJNdjFZ5CC5czX4Y9WmlYcKzz2/",
+	"TVoGMM5olO1JKiPjbYxKrLMR6DyMWYiLstEoXBGzTQVhmAl1ow4jLlBCPMJEIPPKBvSeJMTXzmuA+bIg",
+	"FSBko48FDqLpH3yC151pR0E2auLkQxaRpoGeKOVIo3eDPPqakmSeCyT9aT1GKSKm/6FXFEVWIHGVdiEv",
+	"J54qJ2eaU0Az1r/7IhJr7E25ZHmyub+k5b9ZWl7n2D2aOSiHcA2TZPo/8BJisfmS/a7EquVEvCkIAHSV",
+	"OeNKH10D0LjgAChlLRuykhOg8TJ22mYWXTs3QQHI8RxNdsL/4VaaSnI5WM0izJ8FB2AP4S9TzJ+mIy4u",
+	"w38qLEBhJhXuxhmIXLWR5UIX+cGdWsb3W6CAHgpcJ+V4ZRMG/vc/8MRZOp3fs1zFLnl9jilDT/Nkzs80",
+	"Ju8CxCCOaVP2w2d0ojKE45iqa0ED3jlI0tDnTdK67zrU4IHAU5XEt7IDLmQKhR/rBojIBPKjEFOWdbOq",
+	"nc+///8DAKwrIIfNswEA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,55 @@
+package v2
+
+import (
+	"strings"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+const openSCAPRemediationStage = "org.osbuild.oscap.remediation"
+
+// openSCAPReport collects the results of the rules from the output of the
+// OpenSCAP remediation stage, which lists the title, the ID and the result
+// of each rule it evaluated and then of each rule it remediated.
+func openSCAPReport(output string) *OpenSCAPReport {
+	report := &OpenSCAPReport{
+		Rules: []OpenSCAPRuleResult{},
+	}
+	rules := map[string]int{}
+	var title string
+	current := -1
+	for _, line := range strings.Split(output, "\n") {
+		// oscap overwrites the lines of the rules being evaluated
+		line = strings.ReplaceAll(line, "\r", "")
+		key, value, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Title":
+			title = value
+		case "Rule":
+			idx, ok := rules[value]
+			if !ok {
+				idx = len(report.Rules)
+				rules[value] = idx
+				report.Rules = append(report.Rules, OpenSCAPRuleResult{RuleId: value})
+			}
+			if title != "" {
+				report.Rules[idx].Title = common.ToPtr(title)
+			}
+			title = ""
+			current = idx
+		case "Result":
+			if current >= 0 {
+				report.Rules[current].Result = value
+			}
+			current = -1
+		}
+	}
+	if len(report.Rules) == 0 {
+		return nil
+	}
+	return report
+}
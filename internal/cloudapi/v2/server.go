@@ -2,9 +2,11 @@ package v2
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -24,6 +26,7 @@ import (
 	"github.com/osbuild/images/pkg/distroregistry"
 	"github.com/osbuild/images/pkg/manifest"
 	"github.com/osbuild/images/pkg/ostree"
+	"github.com/osbuild/images/pkg/rpmmd"
 	"github.com/osbuild/osbuild-composer/internal/auth"
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/common"
@@ -48,6 +51,71 @@ type Server struct {
 type ServerConfig struct {
 	TenantProviderFields []string
 	JWTEnabled           bool
+	// ManifestSigningKey is used to sign the manifests returned by the
+	// API, manifests are only digested if it isn't set.
+	ManifestSigningKey ed25519.PrivateKey
+	// EnableMockTarget allows uploads to the mock target, which only
+	// simulates them
+	EnableMockTarget bool
+	// AdminTenants can use the administrative options of the API, like
+	// pinning a compose to a worker. If JWT is disabled, all the clients
+	// are administrators.
+	AdminTenants []string
+	// KojiHubs are the Koji instances builds can be imported to, any
+	// instance is allowed if empty
+	KojiHubs []KojiHub
+	// BlueprintFragments are the customizations compose requests can
+	// reference by name
+	BlueprintFragments map[string]Customizations
+}
+
+// KojiHub is a Koji instance builds can be imported to.
+type KojiHub struct {
+	URL string
+	// Kerberos principal the workers authenticate to the hub as, their
+	// default one for the hub if empty
+	Principal string
+	// Tags builds can be tagged into once they're imported
+	AllowedTags []string
+}
+
+func (h *KojiHub) allowsTag(tag string) bool {
+	for _, t := range h.AllowedTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// sameKojiURL returns whether both URLs refer to the same hub, regardless of
+// the case of the host and trailing slashes.
+func sameKojiURL(a, b string) bool {
+	urlA, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	urlB, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(urlA.Scheme, urlB.Scheme) &&
+		strings.EqualFold(urlA.Host, urlB.Host) &&
+		strings.TrimSuffix(urlA.Path, "/") == strings.TrimSuffix(urlB.Path, "/")
+}
+
+// kojiHub returns the configured hub of the server, or nil if no hubs are
+// configured. Servers which aren't configured are rejected.
+func (s *Server) kojiHub(server string) (*KojiHub, error) {
+	if len(s.config.KojiHubs) == 0 {
+		return nil, nil
+	}
+	for i := range s.config.KojiHubs {
+		if sameKojiURL(s.config.KojiHubs[i].URL, server) {
+			return &s.config.KojiHubs[i], nil
+		}
+	}
+	return nil, HTTPErrorWithInternal(ErrorKojiServerNotAllowed, fmt.Errorf("koji server %s isn't configured", server))
 }
 
 func NewServer(workers *worker.Server, distros *distroregistry.Registry, config ServerConfig) *Server {
@@ -110,25 +178,142 @@ func (s *Server) Shutdown() {
 	s.goroutinesGroup.Wait()
 }
 
-func (s *Server) enqueueCompose(distribution distro.Distro, bp blueprint.Blueprint, manifestSeed int64, irs []imageRequest, channel string) (uuid.UUID, error) {
+// isAdmin returns true if the tenant of the channel is an administrator.
+func (s *Server) isAdmin(channel string) bool {
+	if !s.config.JWTEnabled {
+		return true
+	}
+	for _, tenant := range s.config.AdminTenants {
+		if "org-"+tenant == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// buildChannel returns the channel of the osbuild jobs of a compose, which
+// is the channel of the worker if the compose is pinned to one.
+func buildChannel(channel, workerID string) string {
+	if workerID == "" {
+		return channel
+	}
+	return worker.PinnedChannel(channel, workerID)
+}
+
+// composeOptions are the settings shared by the builds of all the images
+// of a compose.
+type composeOptions struct {
+	distribution distro.Distro
+	blueprint    blueprint.Blueprint
+	manifestSeed int64
+	channel      string
+	// the worker all the builds are pinned to, if any
+	workerID string
+	deadline *time.Time
+	// the request the compose was submitted with, kept to rebuild it, and
+	// the paths of the credentials removed from it
+	request              json.RawMessage
+	redactedFields       []string
+	scanVulnerabilities  bool
+	signArtifacts        bool
+	checksumManifest     bool
+	signChecksumManifest bool
+}
+
+// enqueueCompose enqueues the jobs of the images of the compose. The ID of a
+// compose of a single image is the ID of its osbuild job, composes of
+// several images, e.g. of different architectures, are tracked by a compose
+// job depending on all of the osbuild jobs.
+func (s *Server) enqueueCompose(opts composeOptions, irs []imageRequest) (uuid.UUID, error) {
 	var id uuid.UUID
-	if len(irs) != 1 {
+	if len(irs) == 0 {
 		return id, HTTPError(ErrorInvalidNumberOfImageBuilds)
 	}
-	ir := irs[0]
 
-	ibp := blueprint.Convert(bp)
-	manifestSource, _, err := ir.imageType.Manifest(&ibp, ir.imageOptions, ir.repositories, manifestSeed)
+	if len(irs) == 1 {
+		id, err := s.enqueueImageBuild(opts, irs[0])
+		if err != nil {
+			if id != uuid.Nil {
+				s.cancelImageBuilds([]uuid.UUID{id})
+			}
+			return id, err
+		}
+		return id, nil
+	}
+
+	var buildIDs []uuid.UUID
+	composeJob := &worker.ComposeJob{}
+	for _, ir := range irs {
+		buildID, err := s.enqueueImageBuild(opts, ir)
+		if err != nil {
+			// the images of a compose are built all or none
+			if buildID != uuid.Nil {
+				buildIDs = append(buildIDs, buildID)
+			}
+			s.cancelImageBuilds(buildIDs)
+			return id, err
+		}
+		buildIDs = append(buildIDs, buildID)
+		composeJob.Images = append(composeJob.Images, worker.ComposeJobImage{
+			Arch:      ir.arch.Name(),
+			ImageType: ir.imageType.Name(),
+		})
+	}
+
+	id, err := s.workers.EnqueueCompose(composeJob, buildIDs, opts.channel)
+	if err != nil {
+		s.cancelImageBuilds(buildIDs)
+		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+
+	return id, nil
+}
+
+// cancelImageBuilds cancels the unfinished jobs of the builds and the jobs
+// depending on them, e.g. the signing jobs.
+func (s *Server) cancelImageBuilds(buildIDs []uuid.UUID) {
+	for _, buildID := range buildIDs {
+		logWithId := logrus.WithField("jobId", buildID)
+		buildInfo, err := s.workers.OSBuildJobInfo(buildID, &worker.OSBuildJobResult{})
+		if err != nil {
+			logWithId.Errorf("Error reading the status of the build: %v", err)
+			continue
+		}
+		for _, dependent := range buildInfo.Dependents {
+			err = s.workers.CancelUnfinishedJobs(dependent)
+			if err != nil {
+				logWithId.Errorf("Error canceling job %s depending on the build: %v", dependent, err)
+			}
+		}
+		err = s.workers.CancelUnfinishedJobs(buildID)
+		if err != nil {
+			logWithId.Errorf("Error canceling the build: %v", err)
+		}
+	}
+}
+
+// enqueueImageBuild enqueues the jobs building and publishing the image and
+// returns the ID of its osbuild job.
+func (s *Server) enqueueImageBuild(opts composeOptions, ir imageRequest) (uuid.UUID, error) {
+	var id uuid.UUID
+	distribution := opts.distribution
+	channel := opts.channel
+
+	ibp := blueprint.Convert(opts.blueprint)
+	manifestSource, _, err := ir.imageType.Manifest(&ibp, ir.imageOptions, ir.repositories, opts.manifestSeed)
 	if err != nil {
 		logrus.Warningf("ErrorEnqueueingJob, failed generating manifest: %v", err)
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
 
+	packageSets := excludePayloadPackages(manifestSource.GetPackageSetChains(), ir.imageType.PayloadPipelines(), ir.excludePackages)
 	depsolveJobID, err := s.workers.EnqueueDepsolve(&worker.DepsolveJob{
-		PackageSets:      manifestSource.GetPackageSetChains(),
+		PackageSets:      packageSets,
 		ModulePlatformID: distribution.ModulePlatformID(),
 		Arch:             ir.arch.Name(),
 		Releasever:       distribution.Releasever(),
+		Modules:          payloadModules(packageSets, ir.imageType.PayloadPipelines(), ir.modules),
+		NoWeakDeps:       payloadNoWeakDeps(packageSets, ir.imageType.PayloadPipelines(), ir.minimal),
 	}, channel)
 	if err != nil {
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -147,18 +332,9 @@ func (s *Server) enqueueCompose(distribution distro.Distro, bp blueprint.Bluepri
 	}
 
 	for _, sources := range containerSources {
-		workerResolveSpecs := make([]worker.ContainerSpec, len(sources))
-		for idx, source := range sources {
-			workerResolveSpecs[idx] = worker.ContainerSpec{
-				Source:    source.Source,
-				Name:      source.Name,
-				TLSVerify: source.TLSVerify,
-			}
-		}
-
 		job := worker.ContainerResolveJob{
 			Arch:  ir.arch.Name(),
-			Specs: workerResolveSpecs,
+			Specs: containerResolveSpecs(sources, ir.containerAuths),
 		}
 
 		jobId, err := s.workers.EnqueueContainerResolveJob(&job, channel)
@@ -208,29 +384,82 @@ func (s *Server) enqueueCompose(distribution distro.Distro, bp blueprint.Bluepri
 			Build:   ir.imageType.BuildPipelines(),
 			Payload: ir.imageType.PayloadPipelines(),
 		},
-	}, []uuid.UUID{manifestJobID}, channel)
+		Deadline:         opts.deadline,
+		ComposeRequest:   opts.request,
+		RedactedFields:   opts.redactedFields,
+		PinnedWorkerID:   opts.workerID,
+		ChecksumManifest: opts.checksumManifest,
+		ContainerAuths:   ir.containerAuths,
+	}, []uuid.UUID{manifestJobID}, buildChannel(channel, opts.workerID))
 	if err != nil {
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
 
+	if opts.scanVulnerabilities {
+		_, err = s.workers.EnqueueVulnerabilityScanJob(&worker.VulnerabilityScanJob{
+			Distribution: distribution.Name(),
+		}, depsolveJobID, id, channel)
+		if err != nil {
+			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+		}
+	}
+
+	// the signing job doesn't have access to the arguments of the build,
+	// it needs the targets to find the uploaded artifacts
+	if opts.signArtifacts || opts.signChecksumManifest {
+		_, err = s.workers.EnqueueSignJob(&worker.SignJob{
+			Targets:       ir.targets,
+			ChecksumFile:  opts.signChecksumManifest,
+			SkipArtifacts: !opts.signArtifacts,
+		}, id, channel)
+		if err != nil {
+			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+		}
+	}
+
+	for _, t := range ir.targets {
+		if gcpOptions, ok := t.Options.(*target.GCPTargetOptions); ok && gcpOptions.Export != nil {
+			_, err = s.workers.EnqueueGCPImageExportJob(&worker.GCPImageExportJob{
+				Bucket:      gcpOptions.Export.Bucket,
+				Object:      gcpOptions.Export.Object,
+				Credentials: gcpOptions.Credentials,
+			}, id, channel)
+			if err != nil {
+				return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+			}
+		}
+		if s3Options, ok := t.Options.(*target.AWSS3TargetOptions); ok && s3Options.OSTreeMirror != nil {
+			_, err = s.workers.EnqueueOSTreeMirrorJob(&worker.OSTreeMirrorJob{
+				Target:   t,
+				URL:      s3Options.OSTreeMirror.URL,
+				Username: s3Options.OSTreeMirror.Username,
+				Password: s3Options.OSTreeMirror.Password,
+			}, id, channel)
+			if err != nil {
+				return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+			}
+		}
+	}
+
 	s.goroutinesGroup.Add(1)
 	go func() {
-		serializeManifest(s.goroutinesCtx, manifestSource, s.workers, depsolveJobID, containerResolveJobID, ostreeResolveJobID, manifestJobID, manifestSeed)
+		serializeManifest(s.goroutinesCtx, manifestSource, s.workers, depsolveJobID, containerResolveJobID, ostreeResolveJobID, manifestJobID, opts.manifestSeed, ir.requiredPackages)
 		defer s.goroutinesGroup.Done()
 	}()
 
 	return id, nil
 }
 
-func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, release string, distribution distro.Distro, bp blueprint.Blueprint, manifestSeed int64, irs []imageRequest, channel string) (uuid.UUID, error) {
+func (s *Server) enqueueKojiCompose(taskID uint64, server, principal, tag, name, version, release string, distribution distro.Distro, bp blueprint.Blueprint, manifestSeed int64, irs []imageRequest, channel, workerID string, deadline *time.Time) (uuid.UUID, error) {
 	var id uuid.UUID
 	kojiDirectory := "osbuild-cg/osbuild-composer-koji-" + uuid.New().String()
 
 	initID, err := s.workers.EnqueueKojiInit(&worker.KojiInitJob{
-		Server:  server,
-		Name:    name,
-		Version: version,
-		Release: release,
+		Server:    server,
+		Name:      name,
+		Version:   version,
+		Release:   release,
+		Principal: principal,
 	}, channel)
 	if err != nil {
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -238,7 +467,22 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 
 	var kojiFilenames []string
 	var buildIDs []uuid.UUID
+	composeRequest := &worker.KojiComposeRequest{
+		Distribution:     distribution.Name(),
+		BlueprintName:    bp.Name,
+		BlueprintVersion: bp.Version,
+		Images:           []worker.KojiComposeRequestImage{},
+	}
 	for _, ir := range irs {
+		image := worker.KojiComposeRequestImage{
+			Arch:      ir.arch.Name(),
+			ImageType: ir.imageType.Name(),
+		}
+		for _, t := range ir.targets {
+			image.UploadTargets = append(image.UploadTargets, string(t.Name))
+		}
+		composeRequest.Images = append(composeRequest.Images, image)
+
 		ibp := blueprint.Convert(bp)
 		manifestSource, _, err := ir.imageType.Manifest(&ibp, ir.imageOptions, ir.repositories, manifestSeed)
 		if err != nil {
@@ -246,11 +490,14 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
 
+		packageSets := excludePayloadPackages(manifestSource.GetPackageSetChains(), ir.imageType.PayloadPipelines(), ir.excludePackages)
 		depsolveJobID, err := s.workers.EnqueueDepsolve(&worker.DepsolveJob{
-			PackageSets:      manifestSource.GetPackageSetChains(),
+			PackageSets:      packageSets,
 			ModulePlatformID: distribution.ModulePlatformID(),
 			Arch:             ir.arch.Name(),
 			Releasever:       distribution.Releasever(),
+			Modules:          payloadModules(packageSets, ir.imageType.PayloadPipelines(), ir.modules),
+			NoWeakDeps:       payloadNoWeakDeps(packageSets, ir.imageType.PayloadPipelines(), ir.minimal),
 		}, channel)
 		if err != nil {
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -269,18 +516,9 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 		}
 
 		for _, sources := range containerSources {
-			workerResolveSpecs := make([]worker.ContainerSpec, len(sources))
-			for idx, source := range sources {
-				workerResolveSpecs[idx] = worker.ContainerSpec{
-					Source:    source.Source,
-					Name:      source.Name,
-					TLSVerify: source.TLSVerify,
-				}
-			}
-
 			job := worker.ContainerResolveJob{
 				Arch:  ir.arch.Name(),
-				Specs: make([]worker.ContainerSpec, len(bp.Containers)),
+				Specs: containerResolveSpecs(sources, ir.containerAuths),
 			}
 
 			jobId, err := s.workers.EnqueueContainerResolveJob(&job, channel)
@@ -335,6 +573,7 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 		kojiTarget := target.NewKojiTarget(&target.KojiTargetOptions{
 			Server:          server,
 			UploadDirectory: kojiDirectory,
+			Principal:       principal,
 		})
 		kojiTarget.OsbuildArtifact.ExportFilename = ir.imageType.Filename()
 		kojiTarget.OsbuildArtifact.ExportName = ir.imageType.Exports()[0]
@@ -354,7 +593,10 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 			Targets:            targets,
 			ManifestDynArgsIdx: common.ToPtr(1),
 			ImageBootMode:      ir.imageType.BootMode().String(),
-		}, []uuid.UUID{initID, manifestJobID}, channel)
+			Deadline:           deadline,
+			PinnedWorkerID:     workerID,
+			ContainerAuths:     ir.containerAuths,
+		}, []uuid.UUID{initID, manifestJobID}, buildChannel(channel, workerID))
 		if err != nil {
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
@@ -364,19 +606,22 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 		// copy the image request while passing it into the goroutine to prevent data races
 		s.goroutinesGroup.Add(1)
 		go func(ir imageRequest) {
-			serializeManifest(s.goroutinesCtx, manifestSource, s.workers, depsolveJobID, containerResolveJobID, ostreeResolveJobID, manifestJobID, manifestSeed)
+			serializeManifest(s.goroutinesCtx, manifestSource, s.workers, depsolveJobID, containerResolveJobID, ostreeResolveJobID, manifestJobID, manifestSeed, ir.requiredPackages)
 			defer s.goroutinesGroup.Done()
 		}(ir)
 	}
 	id, err = s.workers.EnqueueKojiFinalize(&worker.KojiFinalizeJob{
-		Server:        server,
-		Name:          name,
-		Version:       version,
-		Release:       release,
-		KojiFilenames: kojiFilenames,
-		KojiDirectory: kojiDirectory,
-		TaskID:        taskID,
-		StartTime:     uint64(time.Now().Unix()),
+		Server:         server,
+		Name:           name,
+		Version:        version,
+		Release:        release,
+		KojiFilenames:  kojiFilenames,
+		KojiDirectory:  kojiDirectory,
+		TaskID:         taskID,
+		StartTime:      uint64(time.Now().Unix()),
+		Principal:      principal,
+		Tag:            tag,
+		ComposeRequest: composeRequest,
 	}, initID, buildIDs, channel)
 	if err != nil {
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -385,7 +630,108 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 	return id, nil
 }
 
-func serializeManifest(ctx context.Context, manifestSource *manifest.Manifest, workers *worker.Server, depsolveJobID, containerResolveJobID, ostreeResolveJobID, manifestJobID uuid.UUID, seed int64) {
+// missingPackages returns the required packages which aren't part of any of
+// the depsolved payload package sets.
+func missingPackages(packageSpecs map[string][]rpmmd.PackageSpec, required []string) []string {
+	var missing []string
+	for _, name := range required {
+		found := false
+		for pipeline, specs := range packageSpecs {
+			if pipeline == "build" {
+				continue
+			}
+			for _, spec := range specs {
+				if spec.Name == name {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// containerResolveSpecs returns the specs resolving the container sources,
+// with the credentials of their registries.
+func containerResolveSpecs(sources []container.SourceSpec, auths map[string]worker.ContainerAuth) []worker.ContainerSpec {
+	specs := make([]worker.ContainerSpec, len(sources))
+	for idx, source := range sources {
+		specs[idx] = worker.ContainerSpec{
+			Source:    source.Source,
+			Name:      source.Name,
+			TLSVerify: source.TLSVerify,
+		}
+		// the sources were validated with the credentials
+		if registry, err := containerRegistry(source.Source); err == nil {
+			if auth, ok := auths[registry]; ok {
+				specs[idx].Auth = &auth
+			}
+		}
+	}
+	return specs
+}
+
+// payloadNoWeakDeps returns the names of the package sets depsolved without
+// weak dependencies, the ones of the payload pipelines of minimal images.
+func payloadNoWeakDeps(packageSets map[string][]rpmmd.PackageSet, payloadPipelines []string, minimal bool) []string {
+	if !minimal {
+		return nil
+	}
+	return payloadPackageSets(packageSets, payloadPipelines)
+}
+
+// excludePayloadPackages returns the package sets with the packages excluded
+// from all package sets of the payload pipelines. The package sets of the
+// manifest aren't modified.
+func excludePayloadPackages(packageSets map[string][]rpmmd.PackageSet, payloadPipelines []string, exclude []string) map[string][]rpmmd.PackageSet {
+	if len(exclude) == 0 {
+		return packageSets
+	}
+	excluded := make(map[string][]rpmmd.PackageSet, len(packageSets))
+	for name, chain := range packageSets {
+		excluded[name] = chain
+	}
+	for _, name := range payloadPackageSets(packageSets, payloadPipelines) {
+		chain := packageSets[name]
+		excludedChain := make([]rpmmd.PackageSet, len(chain))
+		for idx, set := range chain {
+			set.Exclude = append(append([]string{}, set.Exclude...), exclude...)
+			excludedChain[idx] = set
+		}
+		excluded[name] = excludedChain
+	}
+	return excluded
+}
+
+// payloadModules returns the DNF modules of the package sets of the payload
+// pipelines, the build root is depsolved without them.
+func payloadModules(packageSets map[string][]rpmmd.PackageSet, payloadPipelines []string, modules *worker.DepsolveModules) map[string]worker.DepsolveModules {
+	if modules == nil {
+		return nil
+	}
+	payloadModules := map[string]worker.DepsolveModules{}
+	for _, name := range payloadPackageSets(packageSets, payloadPipelines) {
+		payloadModules[name] = *modules
+	}
+	return payloadModules
+}
+
+// payloadPackageSets returns the names of the package sets of the payload
+// pipelines.
+func payloadPackageSets(packageSets map[string][]rpmmd.PackageSet, payloadPipelines []string) []string {
+	var names []string
+	for _, name := range payloadPipelines {
+		if _, ok := packageSets[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func serializeManifest(ctx context.Context, manifestSource *manifest.Manifest, workers *worker.Server, depsolveJobID, containerResolveJobID, ostreeResolveJobID, manifestJobID uuid.UUID, seed int64, requiredPackages []string) {
 	ctx, cancel := context.WithTimeout(ctx, time.Minute*5)
 	defer cancel()
 
@@ -483,6 +829,12 @@ func serializeManifest(ctx context.Context, manifestSource *manifest.Manifest, w
 		return
 	}
 
+	if missing := missingPackages(depsolveResults.PackageSpecs, requiredPackages); len(missing) > 0 {
+		reason := "Packages required by the customizations are not part of the image"
+		jobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorMissingPackages, reason, missing)
+		return
+	}
+
 	var containerSpecs map[string][]container.Spec
 	if containerResolveJobID != uuid.Nil {
 		// Container resolve job
@@ -0,0 +1,67 @@
+package v2
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/osbuild/images/pkg/manifest"
+)
+
+// ParseManifestSigningKey parses a PEM encoded PKCS #8 ed25519 private key,
+// as generated by `openssl genpkey -algorithm ed25519`.
+func ParseManifestSigningKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an ed25519 private key, got %T", key)
+	}
+	return edKey, nil
+}
+
+// manifestKeyID returns the identifier of the public key, the digest of its
+// DER encoding.
+func manifestKeyID(key ed25519.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(der)), nil
+}
+
+// newManifestDigest computes the digest of the manifest and signs it if a
+// key is given. Both are computed over the manifest as it is serialized in
+// the response, so clients can verify the bytes they received.
+func newManifestDigest(mf manifest.OSBuildManifest, key ed25519.PrivateKey) (ManifestDigest, error) {
+	data, err := json.Marshal(mf)
+	if err != nil {
+		return ManifestDigest{}, fmt.Errorf("cannot serialize manifest: %v", err)
+	}
+
+	md := ManifestDigest{
+		Digest: fmt.Sprintf("sha256:%x", sha256.Sum256(data)),
+	}
+	if key == nil {
+		return md, nil
+	}
+
+	keyID, err := manifestKeyID(key)
+	if err != nil {
+		return ManifestDigest{}, err
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	md.Signature = &signature
+	md.KeyId = &keyID
+	return md, nil
+}
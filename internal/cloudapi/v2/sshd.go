@@ -0,0 +1,130 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osbuild/images/pkg/distro"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+// sshdConfigPath is the drop-in with the sshd customization. sshd uses the
+// first value it reads for each keyword and reads the drop-ins in order,
+// so it takes precedence over the ones of the image and of cloud-init.
+const sshdConfigPath = "/etc/ssh/sshd_config.d/01-customizations.conf"
+
+// sshdConfigFile returns the drop-in configuring sshd, after checking the
+// users with SSH keys can still log in with them.
+func sshdConfigFile(sshd *Sshd, users []User) (*blueprint.FileCustomization, error) {
+	for _, user := range users {
+		if user.Key == nil {
+			continue
+		}
+		if user.Name == "root" && sshd.PermitRootLogin != nil && *sshd.PermitRootLogin == SshdPermitRootLoginNo {
+			return nil, fmt.Errorf("the SSH key of root can't be used when the root login isn't permitted")
+		}
+		if sshd.AllowUsers != nil && !sshdAllowed(user.Name, *sshd.AllowUsers) {
+			return nil, fmt.Errorf("the SSH key of %s can't be used when the user isn't allowed to log in", user.Name)
+		}
+		if sshd.AllowGroups != nil {
+			// the primary group of the users is named after them
+			groups := []string{user.Name}
+			if user.Groups != nil {
+				groups = append(groups, *user.Groups...)
+			}
+			allowed := false
+			for _, group := range groups {
+				allowed = allowed || sshdAllowed(group, *sshd.AllowGroups)
+			}
+			if !allowed {
+				return nil, fmt.Errorf("the SSH key of %s can't be used when none of its groups are allowed to log in", user.Name)
+			}
+		}
+	}
+
+	var config strings.Builder
+	if sshd.PermitRootLogin != nil {
+		fmt.Fprintf(&config, "PermitRootLogin %s\n", *sshd.PermitRootLogin)
+	}
+	if sshd.PasswordAuthentication != nil {
+		value := "no"
+		if *sshd.PasswordAuthentication {
+			value = "yes"
+		}
+		fmt.Fprintf(&config, "PasswordAuthentication %s\n", value)
+	}
+	if sshd.AllowUsers != nil && len(*sshd.AllowUsers) > 0 {
+		fmt.Fprintf(&config, "AllowUsers %s\n", strings.Join(*sshd.AllowUsers, " "))
+	}
+	if sshd.AllowGroups != nil && len(*sshd.AllowGroups) > 0 {
+		fmt.Fprintf(&config, "AllowGroups %s\n", strings.Join(*sshd.AllowGroups, " "))
+	}
+	if sshd.Ports != nil {
+		ports := map[int]bool{}
+		for _, port := range *sshd.Ports {
+			if ports[port] {
+				return nil, fmt.Errorf("the sshd port %d is specified twice", port)
+			}
+			ports[port] = true
+			fmt.Fprintf(&config, "Port %d\n", port)
+		}
+	}
+	if config.Len() == 0 {
+		return nil, nil
+	}
+
+	return &blueprint.FileCustomization{
+		Path: sshdConfigPath,
+		Mode: "0600",
+		Data: config.String(),
+	}, nil
+}
+
+// sshdAllowed returns true if the name matches one of the patterns of
+// AllowUsers or AllowGroups. Patterns restricting the hosts always match,
+// the host users log in from isn't known at build time.
+func sshdAllowed(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern, _, _ = strings.Cut(pattern, "@")
+		if sshdMatch(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sshdMatch matches the name against the pattern with the * and ?
+// wildcards of the sshd_config patterns.
+func sshdMatch(pattern, name string) bool {
+	if pattern == "" {
+		return name == ""
+	}
+	switch pattern[0] {
+	case '*':
+		for i := 0; i <= len(name); i++ {
+			if sshdMatch(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	case '?':
+		return name != "" && sshdMatch(pattern[1:], name[1:])
+	default:
+		return name != "" && pattern[0] == name[0] && sshdMatch(pattern[1:], name[1:])
+	}
+}
+
+// checkSshd checks sshd of the distribution reads the drop-ins, the
+// sshd_config of RHEL 7 and 8 doesn't include them.
+func checkSshd(request *ComposeRequest, distribution distro.Distro) error {
+	if request.Customizations == nil || request.Customizations.Sshd == nil {
+		return nil
+	}
+	for _, prefix := range []string{"rhel-7", "rhel-8", "centos-7", "centos-8"} {
+		if strings.HasPrefix(distribution.Name(), prefix) {
+			return HTTPErrorWithInternal(ErrorInvalidCustomization, fmt.Errorf("the sshd customization isn't supported by %s", distribution.Name()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+// sysctlConfigPath is the file the kernel parameters of the request are
+// written to, it's read after the ones of the image.
+const sysctlConfigPath = "/etc/sysctl.d/99-customizations.conf"
+
+// sysctlConfigFile returns the file setting the kernel parameters, only the
+// keys starting with - can be without a value.
+func sysctlConfigFile(sysctl []Sysctl) (*blueprint.FileCustomization, error) {
+	if len(sysctl) == 0 {
+		return nil, nil
+	}
+
+	var config strings.Builder
+	for _, param := range sysctl {
+		if param.Value == nil {
+			if !strings.HasPrefix(param.Key, "-") {
+				return nil, fmt.Errorf("the kernel parameter %s has no value", param.Key)
+			}
+			fmt.Fprintf(&config, "%s\n", param.Key)
+			continue
+		}
+		if strings.Contains(*param.Value, "\n") {
+			return nil, fmt.Errorf("the value of the kernel parameter %s can't span lines", param.Key)
+		}
+		fmt.Fprintf(&config, "%s = %s\n", param.Key, *param.Value)
+	}
+
+	return &blueprint.FileCustomization{
+		Path: sysctlConfigPath,
+		Mode: "0644",
+		Data: config.String(),
+	}, nil
+}
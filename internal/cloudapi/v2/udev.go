@@ -0,0 +1,73 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osbuild/images/pkg/osbuild"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+// udevRulesPath is where the udev rules of the request are written to, the
+// file is read after the rules of the image.
+const udevRulesPath = "/etc/udev/rules.d/99-customizations.rules"
+
+// udevRulesFile returns the file with the udev rules, the keys and the
+// operators are validated the way the images library does.
+func udevRulesFile(rules []UdevRule) (*blueprint.FileCustomization, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	var data strings.Builder
+	for idx, rule := range rules {
+		if rule.Comment != nil {
+			for _, line := range strings.Split(*rule.Comment, "\n") {
+				fmt.Fprintf(&data, "# %s\n", line)
+			}
+		}
+		var kvs []osbuild.UdevKV
+		var ops []string
+		for _, op := range rule.Ops {
+			kv := osbuild.UdevKV{
+				K: op.Key,
+				O: op.Op,
+				V: op.Value,
+			}
+			key := op.Key
+			if op.Arg != nil {
+				kv.A = *op.Arg
+				key = fmt.Sprintf("%s{%s}", op.Key, *op.Arg)
+			}
+			if strings.ContainsAny(op.Value, "\"\n") {
+				return nil, fmt.Errorf("udev rule %d is invalid: the value of %s can't contain quotes or newlines", idx, key)
+			}
+			kvs = append(kvs, kv)
+			ops = append(ops, fmt.Sprintf("%s%s\"%s\"", key, op.Op, op.Value))
+		}
+		err := validateUdevRule(kvs)
+		if err != nil {
+			return nil, fmt.Errorf("udev rule %d is invalid: %v", idx, err)
+		}
+		fmt.Fprintf(&data, "%s\n", strings.Join(ops, ", "))
+	}
+
+	return &blueprint.FileCustomization{
+		Path: udevRulesPath,
+		Mode: "0644",
+		Data: data.String(),
+	}, nil
+}
+
+// validateUdevRule validates the rule the way the images library does,
+// which panics on invalid rules.
+func validateUdevRule(kvs []osbuild.UdevKV) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	osbuild.NewUdevRule(kvs)
+	return nil
+}
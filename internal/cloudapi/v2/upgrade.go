@@ -0,0 +1,265 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/osbuild/images/pkg/distro"
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+// splitDistroName splits a distribution name like "rhel-8.9" into the name
+// of the product and its version.
+func splitDistroName(name string) (string, string) {
+	idx := strings.LastIndex(name, "-")
+	if idx == -1 {
+		return name, ""
+	}
+	return name[:idx], name[idx+1:]
+}
+
+// compareVersions compares two dot separated numeric versions and returns
+// -1, 0 or 1 if a is older, equal or newer than b. The second return value
+// is false if any of the versions isn't numeric.
+func compareVersions(a, b string) (int, bool) {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aNum, bNum int
+		var err error
+		if i < len(aParts) {
+			aNum, err = strconv.Atoi(aParts[i])
+			if err != nil {
+				return 0, false
+			}
+		}
+		if i < len(bParts) {
+			bNum, err = strconv.Atoi(bParts[i])
+			if err != nil {
+				return 0, false
+			}
+		}
+		if aNum < bNum {
+			return -1, true
+		}
+		if aNum > bNum {
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+// errorReason returns a user facing description of an error returned while
+// validating a compose request.
+func errorReason(err error) string {
+	he, ok := err.(*echo.HTTPError)
+	if !ok {
+		return err.Error()
+	}
+	de, ok := he.Message.(detailsError)
+	if !ok {
+		return fmt.Sprintf("%v", he.Message)
+	}
+	reason := find(de.errorCode).reason
+	if details, ok := de.details.(string); ok && details != "" {
+		reason = fmt.Sprintf("%s: %s", reason, details)
+	}
+	return reason
+}
+
+// repositoryURLs returns all the URLs of the repository.
+func repositoryURLs(repo Repository) []string {
+	var urls []string
+	for _, url := range []*string{repo.Baseurl, repo.Mirrorlist, repo.Metalink} {
+		if url != nil && *url != "" {
+			urls = append(urls, *url)
+		}
+	}
+	return urls
+}
+
+func newUpgradeIssue(severity ComposeUpgradeIssueSeverity, imageRequest *int, format string, a ...interface{}) ComposeUpgradeIssue {
+	return ComposeUpgradeIssue{
+		Severity:     severity,
+		ImageRequest: imageRequest,
+		Reason:       fmt.Sprintf(format, a...),
+	}
+}
+
+// checkComposeUpgrade validates the request of an existing compose against
+// a new distribution. The request is expected to be normalized, which means
+// it only contains image_requests. Issues of the severity error prevent the
+// compose from being built, warnings point at things which probably need to
+// be changed by the user, like repositories of the previous version.
+func checkComposeUpgrade(request *ComposeRequest, from string, to distro.Distro) []ComposeUpgradeIssue {
+	issues := []ComposeUpgradeIssue{}
+
+	fromName, fromVersion := splitDistroName(from)
+	toName, toVersion := splitDistroName(to.Name())
+	if from == to.Name() {
+		issues = append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityError, nil, "The compose already uses distribution %s", from))
+	} else if fromName != toName {
+		issues = append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityWarning, nil, "Distribution %s is not a newer version of %s", to.Name(), from))
+	} else if cmp, ok := compareVersions(toVersion, fromVersion); !ok || cmp < 0 {
+		issues = append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityWarning, nil, "Distribution %s is not newer than %s", to.Name(), from))
+	}
+
+	bp, err := request.GetBlueprintWithCustomizations()
+	if err != nil {
+		return append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityError, nil, "%s", errorReason(err)))
+	}
+
+	// Repositories are usually specific to a distribution version, look
+	// for the previous version in their URLs
+	var oldVersionRe *regexp.Regexp
+	if fromVersion != "" && fromName == toName {
+		oldVersionRe = regexp.MustCompile(`(^|[^0-9.])` + regexp.QuoteMeta(fromVersion) + `([^0-9.]|$)`)
+	}
+	checkRepos := func(repos []Repository, idx *int) {
+		if oldVersionRe == nil {
+			return
+		}
+		for _, repo := range repos {
+			for _, url := range repositoryURLs(repo) {
+				if oldVersionRe.MatchString(url) {
+					issues = append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityWarning, idx, "Repository %s seems to be specific to %s", url, from))
+				}
+			}
+		}
+	}
+	checkRepos(request.GetPayloadRepositories(), nil)
+
+	if request.ImageRequests == nil {
+		return issues
+	}
+	for i, ir := range *request.ImageRequests {
+		idx := i
+		checkRepos(ir.Repositories, &idx)
+
+		r, err := newImageRequest(request, ir, to, bp)
+		if err != nil {
+			issues = append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityError, &idx, "%s", errorReason(err)))
+			continue
+		}
+
+		// generating the manifest validates the customizations
+		ibp := blueprint.Convert(bp)
+		_, warnings, err := r.imageType.Manifest(&ibp, r.imageOptions, r.repositories, 0)
+		if err != nil {
+			issues = append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityError, &idx, "%s", err.Error()))
+			continue
+		}
+		for _, warning := range warnings {
+			issues = append(issues, newUpgradeIssue(ComposeUpgradeIssueSeverityWarning, &idx, "%s", warning))
+		}
+	}
+
+	return issues
+}
+
+// secretFields are the names of the fields of the compose request holding
+// credentials: the passwords of the users, the activation key of the
+// subscription and the credentials of the registries, the mirrors, the
+// network mounts and the upload targets. The users of URLs are removed
+// along with their passwords too.
+var secretFields = map[string]bool{
+	"username":          true,
+	"password":          true,
+	"token":             true,
+	"sas_token":         true,
+	"activation_key":    true,
+	"secret_access_key": true,
+	"session_token":     true,
+}
+
+// secretMaps are the names of the objects of the compose request whose
+// values are credentials, e.g. the Authorization header of uploads.
+var secretMaps = map[string]bool{
+	"headers": true,
+}
+
+// redactComposeRequest returns the request without the credentials it
+// holds, so it can be kept with the compose and returned to the users, and
+// the sorted paths of the removed fields.
+func redactComposeRequest(request ComposeRequest) (ComposeRequest, []string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return ComposeRequest{}, nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return ComposeRequest{}, nil, err
+	}
+	paths := redactSecrets(value, "")
+	data, err = json.Marshal(value)
+	if err != nil {
+		return ComposeRequest{}, nil, err
+	}
+	var redacted ComposeRequest
+	err = json.Unmarshal(data, &redacted)
+	if err != nil {
+		return ComposeRequest{}, nil, err
+	}
+	sort.Strings(paths)
+	return redacted, paths, nil
+}
+
+// redactSecrets removes the secret fields from the decoded JSON value and
+// returns their paths.
+func redactSecrets(value interface{}, path string) []string {
+	var paths []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			// empty passwords lock the accounts, they're kept
+			if secret, isString := field.(string); isString && secret != "" && secretFields[key] {
+				delete(v, key)
+				paths = append(paths, fieldPath)
+				continue
+			}
+			if secrets, isMap := field.(map[string]interface{}); isMap && len(secrets) > 0 && secretMaps[key] {
+				delete(v, key)
+				paths = append(paths, fieldPath)
+				continue
+			}
+			// the password of URLs is kept out along with the user
+			if s, isString := field.(string); isString {
+				if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.User != nil {
+					u.User = nil
+					v[key] = u.String()
+					paths = append(paths, fieldPath)
+					continue
+				}
+			}
+			paths = append(paths, redactSecrets(field, fieldPath)...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			paths = append(paths, redactSecrets(item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return paths
+}
+
+// upgradeCompatible returns true if none of the issues prevents the compose
+// from being built.
+func upgradeCompatible(issues []ComposeUpgradeIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == ComposeUpgradeIssueSeverityError {
+			return false
+		}
+	}
+	return true
+}
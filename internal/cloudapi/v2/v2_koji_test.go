@@ -510,7 +510,7 @@ func TestKojiCompose(t *testing.T) {
 			test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v", finalizeID), ``, http.StatusOK, c.composeStatus, `href`, `id`)
 
 			// get the manifests
-			test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/manifests", finalizeID), ``, http.StatusOK, expectedManifests, `href`, `id`)
+			test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/manifests", finalizeID), ``, http.StatusOK, expectedManifests, `href`, `id`, `manifest_digests`)
 
 			// get the logs
 			test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/logs", finalizeID), ``, http.StatusOK, `{"kind":"ComposeLogs"}`, `koji`, `image_builds`, `href`, `id`)
@@ -518,6 +518,93 @@ func TestKojiCompose(t *testing.T) {
 	}
 }
 
+func TestKojiComposeTimeout(t *testing.T) {
+	kojiServer, workerServer, _, cancel := newV2Server(t, t.TempDir(), []string{""}, false, false)
+	handler := kojiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	composeRawReply := test.TestRouteWithReply(t, handler, false, "POST", "/api/image-builder-composer/v2/compose", fmt.Sprintf(`
+	{
+		"distribution":"%[1]s",
+		"timeout": 1,
+		"image_requests": [
+			{
+				"architecture": "%[2]s",
+				"image_type": "%[3]s",
+				"repositories": [{"baseurl": "https://repo.example.com/"}]
+			},
+			{
+				"architecture": "%[2]s",
+				"image_type": "%[3]s",
+				"repositories": [{"baseurl": "https://repo.example.com/"}]
+			}
+		],
+		"koji": {
+			"server": "koji.example.com",
+			"name": "foo",
+			"version": "1",
+			"release": "2",
+			"task_id": 42
+		}
+	}`, test_distro.TestDistroName, test_distro.TestArch3Name, string(v2.ImageTypesGuestImage)),
+		http.StatusCreated, `{"href":"/api/image-builder-composer/v2/compose", "kind":"ComposeId"}`, "id")
+
+	var composeReply v2.ComposeId
+	err := json.Unmarshal(composeRawReply, &composeReply)
+	require.NoError(t, err)
+	composeId, err := uuid.Parse(composeReply.Id)
+	require.NoError(t, err)
+
+	_, token, _, _, _, err := workerServer.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeKojiInit}, []string{""})
+	require.NoError(t, err)
+	initResult, err := json.Marshal(worker.KojiInitJobResult{BuildID: 42, Token: `"foobar"`})
+	require.NoError(t, err)
+	require.NoError(t, workerServer.FinishJob(token, initResult))
+
+	// the first image is uploaded, the second one is still building when
+	// the deadline passes
+	_, token, _, _, _, err = workerServer.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{""})
+	require.NoError(t, err)
+	buildResult, err := json.Marshal(worker.OSBuildJobResult{
+		Arch:          test_distro.TestArch3Name,
+		OSBuildOutput: &osbuild.Result{Success: true},
+		TargetResults: []*target.TargetResult{target.NewKojiTargetResult(&target.KojiTargetResultOptions{
+			Image: &target.KojiOutputInfo{Filename: "test.img", ChecksumType: target.ChecksumTypeMD5},
+		}, nil)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, workerServer.FinishJob(token, buildResult))
+	_, _, _, _, _, err = workerServer.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{""})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		var buildJob worker.OSBuildJob
+		finalizeInfo, err := workerServer.KojiFinalizeJobInfo(composeId, &worker.KojiFinalizeJobResult{})
+		require.NoError(t, err)
+		require.NoError(t, workerServer.OSBuildJob(finalizeInfo.Deps[1], &buildJob))
+		return buildJob.DeadlineExceeded()
+	}, 5*time.Second, 100*time.Millisecond)
+	workerServer.FailJobsPastDeadline(0)
+
+	// koji-finalize still runs, with the result of the first image
+	ctx, cancelRequest := context.WithTimeout(context.Background(), time.Second)
+	defer cancelRequest()
+	finalizeID, _, jobType, _, dynArgs, err := workerServer.RequestJob(ctx, test_distro.TestArch3Name, []string{worker.JobTypeKojiFinalize}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, worker.JobTypeKojiFinalize, jobType)
+	require.Equal(t, composeId, finalizeID)
+	require.Len(t, dynArgs, 3)
+	var timedOut int
+	for _, arg := range dynArgs[1:] {
+		var result worker.OSBuildJobResult
+		require.NoError(t, json.Unmarshal(arg, &result))
+		if result.JobError != nil && result.JobError.ID == clienterrors.ErrorDeadlineExceeded {
+			timedOut++
+		}
+	}
+	require.Equal(t, 1, timedOut)
+}
+
 func TestKojiJobTypeValidation(t *testing.T) {
 	server, workers, _, cancel := newV2Server(t, t.TempDir(), []string{""}, false, false)
 	handler := server.Handler("/api/image-builder-composer/v2")
@@ -600,3 +687,97 @@ func TestKojiJobTypeValidation(t *testing.T) {
 		test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%s%s", badID, path), ``, http.StatusNotFound, `{"code":"IMAGE-BUILDER-COMPOSER-15", "details": "", "href":"/api/image-builder-composer/v2/errors/15","id":"15","kind":"Error","reason":"Compose with given id not found"}`, `operation_id`)
 	}
 }
+
+func TestKojiHubs(t *testing.T) {
+	srv, workerServer, q, cancel := newV2ServerWithConfig(t, t.TempDir(), []string{""}, v2.ServerConfig{
+		KojiHubs: []v2.KojiHub{
+			{
+				URL:         "https://koji.example.com/kojihub",
+				Principal:   "composer-example@EXAMPLE.COM",
+				AllowedTags: []string{"cloud-candidate"},
+			},
+		},
+	}, false)
+	handler := srv.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	request := func(server, tag string) string {
+		tagOption := ""
+		if tag != "" {
+			tagOption = fmt.Sprintf(`"tag": "%s",`, tag)
+		}
+		return fmt.Sprintf(`
+		{
+			"distribution": "%s",
+			"image_requests": [
+				{
+					"architecture": "%s",
+					"image_type": "%s",
+					"repositories": [
+						{
+							"baseurl": "https://repo.example.com/"
+						}
+					]
+				}
+			],
+			"koji": {
+				"server": "%s",
+				%s
+				"name": "foo",
+				"version": "1",
+				"release": "2",
+				"task_id": 42
+			}
+		}`, test_distro.TestDistroName, test_distro.TestArch3Name, string(v2.ImageTypesGuestImage), server, tagOption)
+	}
+
+	test.TestRoute(t, handler, false, "POST", "/api/image-builder-composer/v2/compose", request("https://koji.other.example.com/kojihub", ""), http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/52",
+		"id": "52",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-52",
+		"reason": "Builds can't be imported to the requested Koji server"
+	}`, "operation_id", "details")
+
+	test.TestRoute(t, handler, false, "POST", "/api/image-builder-composer/v2/compose", request("https://koji.example.com/kojihub", "rawhide"), http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/53",
+		"id": "53",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-53",
+		"reason": "Builds can't be tagged into the requested Koji tag"
+	}`, "operation_id", "details")
+
+	// the URL is matched regardless of the case of the host and trailing slashes
+	composeRawReply := test.TestRouteWithReply(t, handler, false, "POST", "/api/image-builder-composer/v2/compose", request("https://KOJI.example.com/kojihub/", "cloud-candidate"), http.StatusCreated, `
+	{
+		"href": "/api/image-builder-composer/v2/compose",
+		"kind": "ComposeId"
+	}`, "id")
+	var composeReply v2.ComposeId
+	err := json.Unmarshal(composeRawReply, &composeReply)
+	require.NoError(t, err)
+	composeID, err := uuid.Parse(composeReply.Id)
+	require.NoError(t, err)
+
+	_, _, jobType, rawJob, _, err := workerServer.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeKojiInit}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, worker.JobTypeKojiInit, jobType)
+	var initJob worker.KojiInitJob
+	require.NoError(t, json.Unmarshal(rawJob, &initJob))
+	require.Equal(t, "composer-example@EXAMPLE.COM", initJob.Principal)
+
+	_, rawJob, deps, _, err := q.Job(composeID)
+	require.NoError(t, err)
+	var finalizeJob worker.KojiFinalizeJob
+	require.NoError(t, json.Unmarshal(rawJob, &finalizeJob))
+	require.Equal(t, "composer-example@EXAMPLE.COM", finalizeJob.Principal)
+	require.Equal(t, "cloud-candidate", finalizeJob.Tag)
+
+	_, rawJob, _, _, err = q.Job(deps[1])
+	require.NoError(t, err)
+	var buildJob worker.OSBuildJob
+	require.NoError(t, json.Unmarshal(rawJob, &buildJob))
+	require.Equal(t, "composer-example@EXAMPLE.COM", buildJob.Targets[0].Options.(*target.KojiTargetOptions).Principal)
+}
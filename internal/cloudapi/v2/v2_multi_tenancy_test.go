@@ -318,3 +318,56 @@ func TestMultitenancy(t *testing.T) {
 		}
 	}
 }
+
+func TestComposePinnedToWorker(t *testing.T) {
+	apiServer, workerServer, q, cancel := newV2ServerWithConfig(t, t.TempDir(), []string{}, v2.ServerConfig{
+		JWTEnabled:           true,
+		TenantProviderFields: []string{"rh-org-id", "account_id"},
+		AdminTenants:         []string{"42"},
+	}, false)
+	defer cancel()
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+
+	var request map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s3Request()), &request))
+	request["worker_id"] = "worker-1"
+	pinnedRequest, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	// only administrators can pin composes
+	test.APICall{
+		Handler:        handler,
+		Context:        reqContext("123"),
+		Method:         http.MethodPost,
+		Path:           "/api/image-builder-composer/v2/compose",
+		RequestBody:    test.JSONRequestBody(pinnedRequest),
+		ExpectedStatus: http.StatusForbidden,
+	}.Do(t)
+
+	id := scheduleRequest(t, handler, "42", string(pinnedRequest))
+
+	// the build is pinned, its dependencies aren't
+	_, args, deps, channel, err := q.Job(id)
+	require.NoError(t, err)
+	require.Equal(t, worker.PinnedChannel("org-42", "worker-1"), channel)
+	var job worker.OSBuildJob
+	require.NoError(t, json.Unmarshal(args, &job))
+	require.Equal(t, "worker-1", job.PinnedWorkerID)
+	for _, dep := range deps {
+		_, _, _, channel, err := q.Job(dep)
+		require.NoError(t, err)
+		require.Equal(t, "org-42", channel)
+	}
+
+	// the compose still belongs to the tenant
+	channel, err = workerServer.JobChannel(id)
+	require.NoError(t, err)
+	require.Equal(t, "org-42", channel)
+	test.APICall{
+		Handler:        handler,
+		Context:        reqContext("42"),
+		Method:         http.MethodGet,
+		Path:           "/api/image-builder-composer/v2/composes/" + id.String(),
+		ExpectedStatus: http.StatusOK,
+	}.Do(t)
+}
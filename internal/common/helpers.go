@@ -1,8 +1,10 @@
 package common
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 	"runtime"
 	"sort"
@@ -101,3 +103,19 @@ type nopSeekCloser struct {
 }
 
 func (nopSeekCloser) Close() error { return nil }
+
+// FileSHA256 returns the SHA256 checksum of the file in the form of
+// "sha256:<hex digest>".
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
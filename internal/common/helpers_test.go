@@ -2,6 +2,8 @@ package common
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -91,3 +93,15 @@ func TestDataSizeToUint64(t *testing.T) {
 		}
 	}
 }
+
+func TestFileSHA256(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(p, []byte("hello world"), 0600))
+
+	checksum, err := FileSHA256(p)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", checksum)
+
+	_, err = FileSHA256(filepath.Join(t.TempDir(), "does-not-exist"))
+	assert.Error(t, err)
+}
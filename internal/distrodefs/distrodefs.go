@@ -0,0 +1,184 @@
+// Package distrodefs loads definitions of additional distributions, which
+// are derived from the ones supported by osbuild-composer, e.g. custom spins
+// of Fedora using their own repositories.
+//
+// The definitions are read from the "distros" subdirectory of a directory,
+// one JSON file per distribution. Its "repositories" subdirectory uses the
+// same format as the repository configuration of composer, so the directory
+// can be added to the repository configuration paths as well.
+package distrodefs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/osbuild/images/pkg/distro"
+	"github.com/osbuild/images/pkg/distroregistry"
+)
+
+// Definition describes a distribution derived from a base distribution.
+// Images of the distribution are built the way the base distribution builds
+// them, the other fields only override what composer reports and uses to
+// depsolve packages and look up repositories.
+type Definition struct {
+	Name             string `json:"name"`
+	Base             string `json:"base"`
+	Releasever       string `json:"releasever,omitempty"`
+	ModulePlatformID string `json:"module_platform_id,omitempty"`
+	OSTreeRef        string `json:"ostree_ref,omitempty"`
+}
+
+// LoadDefinitions reads the definitions from the "distros" subdirectory of
+// dir, sorted by name. A missing directory contains no definitions.
+func LoadDefinitions(dir string) ([]Definition, error) {
+	defsPath := filepath.Join(dir, "distros")
+	entries, err := os.ReadDir(defsPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var defs []Definition
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		def, err := loadDefinition(filepath.Join(defsPath, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, *def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs, nil
+}
+
+func loadDefinition(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var def Definition
+	err = json.Unmarshal(data, &def)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse distro definition %s: %v", path, err)
+	}
+	if def.Name == "" || def.Base == "" {
+		return nil, fmt.Errorf("distro definition %s must set both name and base", path)
+	}
+	return &def, nil
+}
+
+// NewRegistry returns a registry of the distributions of base and of the
+// distributions defined in dir.
+func NewRegistry(base *distroregistry.Registry, dir string) (*distroregistry.Registry, error) {
+	defs, err := LoadDefinitions(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var distros []distro.Distro
+	for _, name := range base.List() {
+		distros = append(distros, base.GetDistro(name))
+	}
+	for _, def := range defs {
+		baseDistro := base.GetDistro(def.Base)
+		if baseDistro == nil {
+			return nil, fmt.Errorf("base distro %s of distro %s doesn't exist", def.Base, def.Name)
+		}
+		distros = append(distros, newDistro(def, baseDistro))
+	}
+
+	registry, err := distroregistry.New(base.FromHost(), distros...)
+	if err != nil {
+		return nil, err
+	}
+	registry.SetHostArchName(base.HostArchName())
+	return registry, nil
+}
+
+type definedDistro struct {
+	distro.Distro
+	def Definition
+}
+
+func newDistro(def Definition, base distro.Distro) *definedDistro {
+	return &definedDistro{
+		Distro: base,
+		def:    def,
+	}
+}
+
+func (d *definedDistro) Name() string {
+	return d.def.Name
+}
+
+func (d *definedDistro) Releasever() string {
+	if d.def.Releasever != "" {
+		return d.def.Releasever
+	}
+	return d.Distro.Releasever()
+}
+
+func (d *definedDistro) ModulePlatformID() string {
+	if d.def.ModulePlatformID != "" {
+		return d.def.ModulePlatformID
+	}
+	return d.Distro.ModulePlatformID()
+}
+
+func (d *definedDistro) OSTreeRef() string {
+	if d.def.OSTreeRef != "" {
+		return d.def.OSTreeRef
+	}
+	return d.Distro.OSTreeRef()
+}
+
+func (d *definedDistro) GetArch(name string) (distro.Arch, error) {
+	arch, err := d.Distro.GetArch(name)
+	if err != nil {
+		return nil, err
+	}
+	return &definedArch{Arch: arch, distro: d}, nil
+}
+
+// definedArch and definedImageType link the architectures and image types
+// of the base distribution back to the defined one.
+type definedArch struct {
+	distro.Arch
+	distro *definedDistro
+}
+
+func (a *definedArch) Distro() distro.Distro {
+	return a.distro
+}
+
+func (a *definedArch) GetImageType(name string) (distro.ImageType, error) {
+	imageType, err := a.Arch.GetImageType(name)
+	if err != nil {
+		return nil, err
+	}
+	return &definedImageType{ImageType: imageType, arch: a}, nil
+}
+
+type definedImageType struct {
+	distro.ImageType
+	arch *definedArch
+}
+
+func (t *definedImageType) Arch() distro.Arch {
+	return t.arch
+}
+
+func (t *definedImageType) OSTreeRef() string {
+	ref := t.ImageType.OSTreeRef()
+	if ref == "" || t.arch.distro.def.OSTreeRef == "" {
+		return ref
+	}
+	return fmt.Sprintf(t.arch.distro.def.OSTreeRef, t.arch.Name())
+}
@@ -0,0 +1,90 @@
+package distrodefs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/osbuild/images/pkg/distro/test_distro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	distro_mock "github.com/osbuild/osbuild-composer/internal/mocks/distro"
+)
+
+func writeDefinition(t *testing.T, dir, name, content string) {
+	t.Helper()
+	defsPath := filepath.Join(dir, "distros")
+	require.NoError(t, os.MkdirAll(defsPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(defsPath, name), []byte(content), 0600))
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	dir := t.TempDir()
+
+	defs, err := LoadDefinitions(dir)
+	require.NoError(t, err)
+	assert.Empty(t, defs)
+
+	writeDefinition(t, dir, "b.json", `{"name": "spin-b", "base": "test-distro"}`)
+	writeDefinition(t, dir, "a.json", `{"name": "spin-a", "base": "test-distro", "releasever": "42"}`)
+	writeDefinition(t, dir, "README", `not a definition`)
+	defs, err = LoadDefinitions(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []Definition{
+		{Name: "spin-a", Base: "test-distro", Releasever: "42"},
+		{Name: "spin-b", Base: "test-distro"},
+	}, defs)
+
+	writeDefinition(t, dir, "c.json", `{"name": "spin-c"}`)
+	_, err = LoadDefinitions(dir)
+	assert.Error(t, err)
+}
+
+func TestNewRegistry(t *testing.T) {
+	base, err := distro_mock.NewDefaultRegistry()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeDefinition(t, dir, "spin.json", `{
+		"name": "spin-1",
+		"base": "`+test_distro.TestDistroName+`",
+		"releasever": "42",
+		"module_platform_id": "platform:spin1"
+	}`)
+
+	registry, err := NewRegistry(base, dir)
+	require.NoError(t, err)
+	assert.Equal(t, append([]string{"spin-1"}, base.List()...), registry.List())
+	assert.Equal(t, base.HostArchName(), registry.HostArchName())
+
+	d := registry.GetDistro("spin-1")
+	require.NotNil(t, d)
+	assert.Equal(t, "spin-1", d.Name())
+	assert.Equal(t, "42", d.Releasever())
+	assert.Equal(t, "platform:spin1", d.ModulePlatformID())
+	assert.Equal(t, base.GetDistro(test_distro.TestDistroName).ListArches(), d.ListArches())
+
+	arch, err := d.GetArch(test_distro.TestArchName)
+	require.NoError(t, err)
+	assert.Equal(t, "spin-1", arch.Distro().Name())
+	imageType, err := arch.GetImageType(test_distro.TestImageTypeName)
+	require.NoError(t, err)
+	assert.Equal(t, "spin-1", imageType.Arch().Distro().Name())
+	assert.Equal(t, test_distro.TestArchName, imageType.Arch().Name())
+}
+
+func TestNewRegistryErrors(t *testing.T) {
+	base, err := distro_mock.NewDefaultRegistry()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeDefinition(t, dir, "spin.json", `{"name": "spin-1", "base": "unknown-1"}`)
+	_, err = NewRegistry(base, dir)
+	assert.Error(t, err)
+
+	// names of the defined distros have to be unique
+	writeDefinition(t, dir, "spin.json", `{"name": "`+test_distro.TestDistroName+`", "base": "`+test_distro.TestDistroName+`"}`)
+	_, err = NewRegistry(base, dir)
+	assert.Error(t, err)
+}
@@ -108,6 +108,17 @@ type Solver struct {
 	// for each distribution.
 	distro string
 
+	// DNF modules to enable, as name:stream, and to disable when depsolving
+	enableModules  []string
+	disableModules []string
+
+	// Hashes of the repositories whose packages aren't filtered by the
+	// enabled modules
+	moduleHotfixes map[string]bool
+
+	// Don't install the weak dependencies of the first package set either
+	noWeakDeps bool
+
 	subscriptions *rhsm.Subscriptions
 }
 
@@ -117,6 +128,31 @@ func NewSolver(modulePlatformID, releaseVer, arch, distro, cacheDir string) *Sol
 	return s.NewWithConfig(modulePlatformID, releaseVer, arch, distro)
 }
 
+// SetModules sets the module streams to enable, as name:stream, and the
+// modules to disable before the package sets are depsolved. Disabled modules
+// don't provide their default streams.
+func (s *Solver) SetModules(enable, disable []string) {
+	s.enableModules = enable
+	s.disableModules = disable
+}
+
+// SetModuleHotfixes sets the repositories whose packages are available even
+// when they are part of a module stream that isn't enabled, like the
+// module_hotfixes option of dnf.
+func (s *Solver) SetModuleHotfixes(repos []rpmmd.RepoConfig) {
+	s.moduleHotfixes = make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		s.moduleHotfixes[repo.Hash()] = true
+	}
+}
+
+// SetInstallWeakDeps sets whether the weak dependencies of the first package
+// set are installed. They are never installed for the package sets chained
+// to it.
+func (s *Solver) SetInstallWeakDeps(install bool) {
+	s.noWeakDeps = !install
+}
+
 // GetCacheDir returns a distro specific rpm cache directory
 // It ensures that the distro name is below the root cache directory, and if there is
 // a problem it returns the root cache intead of an error.
@@ -267,6 +303,7 @@ func (s *Solver) reposFromRPMMD(rpmRepos []rpmmd.RepoConfig) ([]repoConfig, erro
 			MirrorList:     rr.MirrorList,
 			GPGKeys:        rr.GPGKeys,
 			MetadataExpire: rr.MetadataExpire,
+			ModuleHotfixes: s.moduleHotfixes[rr.Hash()],
 			repoHash:       rr.Hash(),
 		}
 
@@ -315,6 +352,7 @@ type repoConfig struct {
 	SSLClientKey   string   `json:"sslclientkey,omitempty"`
 	SSLClientCert  string   `json:"sslclientcert,omitempty"`
 	MetadataExpire string   `json:"metadata_expire,omitempty"`
+	ModuleHotfixes bool     `json:"module_hotfixes,omitempty"`
 	// set the repo hass from `rpmmd.RepoConfig.Hash()` function
 	// rather than re-calculating it
 	repoHash string
@@ -387,8 +425,11 @@ func (s *Solver) makeDepsolveRequest(pkgSets []rpmmd.PackageSet) (*Request, map[
 		return nil, nil, err
 	}
 	args := arguments{
-		Repos:        dnfRepoMap,
-		Transactions: transactions,
+		Repos:          dnfRepoMap,
+		Transactions:   transactions,
+		EnableModules:  s.enableModules,
+		DisableModules: s.disableModules,
+		NoWeakDeps:     s.noWeakDeps,
 	}
 
 	req := Request{
@@ -500,9 +541,13 @@ func (r *Request) Hash() string {
 	h.Write([]byte(r.Arch))
 	for _, repo := range r.Arguments.Repos {
 		h.Write([]byte(repo.Hash()))
+		h.Write([]byte(fmt.Sprintf("%t", repo.ModuleHotfixes)))
 	}
 	h.Write([]byte(fmt.Sprintf("%T", r.Arguments.Search.Latest)))
 	h.Write([]byte(strings.Join(r.Arguments.Search.Packages, "")))
+	h.Write([]byte(strings.Join(r.Arguments.EnableModules, ",")))
+	h.Write([]byte(strings.Join(r.Arguments.DisableModules, ",")))
+	h.Write([]byte(fmt.Sprintf("%t", r.Arguments.NoWeakDeps)))
 
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
@@ -517,6 +562,15 @@ type arguments struct {
 
 	// Depsolve package sets and repository mappings for this request
 	Transactions []transactionArgs `json:"transactions"`
+
+	// Module streams to enable before depsolving, as name:stream
+	EnableModules []string `json:"enable-modules,omitempty"`
+
+	// Modules to disable before depsolving
+	DisableModules []string `json:"disable-modules,omitempty"`
+
+	// Don't install weak dependencies in any of the transactions
+	NoWeakDeps bool `json:"no-weak-deps,omitempty"`
 }
 
 type searchArgs struct {
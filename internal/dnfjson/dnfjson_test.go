@@ -12,6 +12,7 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/mocks/rpmrepo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var forceDNF = flag.Bool("force-dnf", false, "force dnf testing, making them fail instead of skip if dnf isn't installed")
@@ -380,6 +381,71 @@ func TestMakeDepsolveRequest(t *testing.T) {
 	}
 }
 
+func TestMakeDepsolveRequestModules(t *testing.T) {
+	packageSets := []rpmmd.PackageSet{
+		{
+			Include:      []string{"nodejs"},
+			Repositories: []rpmmd.RepoConfig{{Name: "appstream", BaseURLs: []string{"https://example.com/appstream"}}},
+		},
+	}
+	solver := NewSolver("platform:el8", "8", "x86_64", "rhel-8", "/tmp/cache")
+	req, _, err := solver.makeDepsolveRequest(packageSets)
+	require.NoError(t, err)
+	assert.Empty(t, req.Arguments.EnableModules)
+	assert.Empty(t, req.Arguments.DisableModules)
+	hash := req.Hash()
+
+	solver.SetModules([]string{"nodejs:18"}, []string{"postgresql"})
+	req, _, err = solver.makeDepsolveRequest(packageSets)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nodejs:18"}, req.Arguments.EnableModules)
+	assert.Equal(t, []string{"postgresql"}, req.Arguments.DisableModules)
+	assert.NotEqual(t, hash, req.Hash())
+}
+
+func TestMakeDepsolveRequestModuleHotfixes(t *testing.T) {
+	appstream := rpmmd.RepoConfig{Name: "appstream", BaseURLs: []string{"https://example.com/appstream"}}
+	copr := rpmmd.RepoConfig{Name: "copr", BaseURLs: []string{"https://example.com/copr"}}
+	packageSets := []rpmmd.PackageSet{
+		{
+			Include:      []string{"nodejs"},
+			Repositories: []rpmmd.RepoConfig{appstream, copr},
+		},
+	}
+	solver := NewSolver("platform:el8", "8", "x86_64", "rhel-8", "/tmp/cache")
+	req, _, err := solver.makeDepsolveRequest(packageSets)
+	require.NoError(t, err)
+	assert.False(t, req.Arguments.Repos[1].ModuleHotfixes)
+	hash := req.Hash()
+
+	solver.SetModuleHotfixes([]rpmmd.RepoConfig{copr})
+	req, _, err = solver.makeDepsolveRequest(packageSets)
+	require.NoError(t, err)
+	assert.False(t, req.Arguments.Repos[0].ModuleHotfixes)
+	assert.True(t, req.Arguments.Repos[1].ModuleHotfixes)
+	assert.NotEqual(t, hash, req.Hash())
+}
+
+func TestMakeDepsolveRequestNoWeakDeps(t *testing.T) {
+	packageSets := []rpmmd.PackageSet{
+		{
+			Include:      []string{"@core"},
+			Repositories: []rpmmd.RepoConfig{{Name: "baseos", BaseURLs: []string{"https://example.com/baseos"}}},
+		},
+	}
+	solver := NewSolver("platform:el9", "9", "x86_64", "rhel-9", "/tmp/cache")
+	req, _, err := solver.makeDepsolveRequest(packageSets)
+	require.NoError(t, err)
+	assert.False(t, req.Arguments.NoWeakDeps)
+	hash := req.Hash()
+
+	solver.SetInstallWeakDeps(false)
+	req, _, err = solver.makeDepsolveRequest(packageSets)
+	require.NoError(t, err)
+	assert.True(t, req.Arguments.NoWeakDeps)
+	assert.NotEqual(t, hash, req.Hash())
+}
+
 func expectedResult(repo rpmmd.RepoConfig) []rpmmd.PackageSpec {
 	// need to change the url for the RemoteLocation and the repo ID since the port is different each time and we don't want to have a fixed one
 	expectedTemplate := []rpmmd.PackageSpec{
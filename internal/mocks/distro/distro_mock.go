@@ -10,5 +10,6 @@ func NewDefaultRegistry() (*distroregistry.Registry, error) {
 	if testDistro == nil {
 		panic("Attempt to register test distro failed")
 	}
-	return distroregistry.New(nil, testDistro)
+	testDistro2 := test_distro.NewTestDistro(test_distro.TestDistro2Name, test_distro.TestDistro2ModulePlatformID, test_distro.TestDistro2Releasever)
+	return distroregistry.New(nil, testDistro, testDistro2)
 }
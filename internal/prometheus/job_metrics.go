@@ -55,6 +55,15 @@ var (
 	}, []string{"type", "tenant", "arch"})
 )
 
+var (
+	DepsolveCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "depsolve_cache_requests_total",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Depsolve jobs run by workers with a depsolve cache, by whether the cached result was used.",
+	}, []string{"result", "tenant"})
+)
+
 func EnqueueJobMetrics(jobType, tenant string) {
 	PendingJobs.WithLabelValues(jobType, tenant).Inc()
 }
@@ -84,3 +93,11 @@ func FinishJobMetrics(started time.Time, finished time.Time, canceled bool, jobT
 		RunningJobs.WithLabelValues(jobType, tenant).Dec()
 	}
 }
+
+func DepsolveCacheMetrics(hit bool, tenant string) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	DepsolveCacheRequests.WithLabelValues(result, tenant).Inc()
+}
@@ -35,6 +35,11 @@ type ImageBuild struct {
 	// before the move to the job queue use this to store whether they
 	// finished successfully.
 	QueueStatus common.ImageBuildState
+	// The job of a deferred image build isn't enqueued yet, it waits for
+	// one of the running builds to finish. The ones with priority go
+	// first.
+	Deferred bool
+	Priority bool
 }
 
 // DeepCopy creates a copy of the ImageBuild structure
@@ -56,6 +61,8 @@ func (ib *ImageBuild) DeepCopy() ImageBuild {
 		JobFinished: ib.JobFinished,
 		Size:        ib.Size,
 		JobID:       ib.JobID,
+		Deferred:    ib.Deferred,
+		Priority:    ib.Priority,
 	}
 }
 
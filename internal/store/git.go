@@ -0,0 +1,240 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+// BlueprintsGitConfig is the git repository every change to the blueprints
+// is committed to.
+type BlueprintsGitConfig struct {
+	// Working tree of the repository, it's initialized if it doesn't exist
+	Dir string
+	// URL of the repository the commits and the tags are pushed to, if set.
+	// Its branch has to be empty or only pushed to by this repository.
+	Remote string
+	// Branch the blueprints are committed to, main if empty
+	Branch string
+}
+
+// pushTimeout is how long a push to the remote may take, a remote that
+// doesn't respond doesn't hold up the next pushes.
+const pushTimeout = 2 * time.Minute
+
+// blueprintsGit commits the blueprints to the repository as TOML files
+// named after them. The commits are the ones of the blueprint changes, so
+// they can be looked up in the repository too.
+type blueprintsGit struct {
+	dir    string
+	remote string
+	branch string
+	log    *log.Logger
+
+	// refs waiting to be pushed to the remote, the pushes run in a
+	// goroutine of their own, outside of the lock of the store
+	pushMu      sync.Mutex
+	pendingRefs []string
+	pushReady   chan struct{}
+}
+
+// newBlueprintsGit initializes the repository if it doesn't exist yet.
+func newBlueprintsGit(config BlueprintsGitConfig, log *log.Logger) (*blueprintsGit, error) {
+	g := &blueprintsGit{
+		dir:    config.Dir,
+		remote: config.Remote,
+		branch: config.Branch,
+		log:    log,
+	}
+	if g.branch == "" {
+		g.branch = "main"
+	}
+	if g.remote != "" {
+		g.pushReady = make(chan struct{}, 1)
+		go g.pushPending()
+	}
+
+	_, err := os.Stat(filepath.Join(g.dir, ".git"))
+	if err == nil {
+		return g, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	err = os.MkdirAll(g.dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	_, err = g.git("init", "--quiet")
+	if err != nil {
+		return nil, err
+	}
+	_, err = g.git("symbolic-ref", "HEAD", "refs/heads/"+g.branch)
+	if err != nil {
+		return nil, err
+	}
+	if g.remote != "" {
+		_, err = g.git("remote", "add", "origin", g.remote)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// git runs git in the repository, with the identity of osbuild-composer.
+func (g *blueprintsGit) git(command string, args ...string) (string, error) {
+	return g.gitContext(context.Background(), command, args...)
+}
+
+func (g *blueprintsGit) gitContext(ctx context.Context, command string, args ...string) (string, error) {
+	args = append([]string{
+		"-c", "user.name=osbuild-composer",
+		"-c", "user.email=osbuild-composer@localhost",
+		"-c", "commit.gpgsign=false",
+		"-c", "tag.gpgsign=false",
+		command,
+	}, args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %v: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// commit commits the changes of the working tree, even if there are none,
+// and returns the hash of the commit.
+func (g *blueprintsGit) commit(message string) (string, error) {
+	_, err := g.git("commit", "--quiet", "--allow-empty", "--allow-empty-message", "--message", message)
+	if err != nil {
+		return "", err
+	}
+	commit, err := g.git("rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	g.push("HEAD:refs/heads/" + g.branch)
+	return commit, nil
+}
+
+// push queues the ref to be pushed to the remote, it doesn't wait for the
+// push.
+func (g *blueprintsGit) push(ref string) {
+	if g.remote == "" {
+		return
+	}
+	g.pushMu.Lock()
+	g.addPendingRefs(ref)
+	g.pushMu.Unlock()
+
+	select {
+	case g.pushReady <- struct{}{}:
+	default:
+		// a push is already due, it picks the ref up
+	}
+}
+
+// addPendingRefs adds the refs which aren't pending yet, g.pushMu has to be
+// held.
+func (g *blueprintsGit) addPendingRefs(refs ...string) {
+	for _, ref := range refs {
+		pending := false
+		for _, p := range g.pendingRefs {
+			pending = pending || p == ref
+		}
+		if !pending {
+			g.pendingRefs = append(g.pendingRefs, ref)
+		}
+	}
+}
+
+// pushPending pushes the pending refs whenever there are new ones. The
+// changes are already stored, a failed push is only logged, its refs are
+// pushed again with the next one.
+func (g *blueprintsGit) pushPending() {
+	for range g.pushReady {
+		g.pushMu.Lock()
+		refs := g.pendingRefs
+		g.pendingRefs = nil
+		g.pushMu.Unlock()
+		if len(refs) == 0 {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+		_, err := g.gitContext(ctx, "push", append([]string{"--quiet", "origin"}, refs...)...)
+		cancel()
+		if err != nil {
+			if g.log != nil {
+				g.log.Printf("cannot push the blueprints to %s: %v", g.remote, err)
+			}
+			g.pushMu.Lock()
+			g.addPendingRefs(refs...)
+			g.pushMu.Unlock()
+		}
+	}
+}
+
+// commitBlueprint writes the blueprint and commits it with the message.
+func (g *blueprintsGit) commitBlueprint(bp blueprint.Blueprint, message string) (string, error) {
+	var data bytes.Buffer
+	encoder := toml.NewEncoder(&data)
+	encoder.Indent = ""
+	err := encoder.Encode(bp)
+	if err != nil {
+		return "", err
+	}
+	filename := bp.Name + ".toml"
+	err = os.WriteFile(filepath.Join(g.dir, filename), data.Bytes(), 0600)
+	if err != nil {
+		return "", err
+	}
+	_, err = g.git("add", "--", filename)
+	if err != nil {
+		return "", err
+	}
+	return g.commit(message)
+}
+
+// deleteBlueprint removes the blueprint, it doesn't have to be in the
+// repository if it was pushed before the repository was configured.
+func (g *blueprintsGit) deleteBlueprint(name string) error {
+	_, err := g.git("rm", "--quiet", "--ignore-unmatch", "--", name+".toml")
+	if err != nil {
+		return err
+	}
+	_, err = g.commit(fmt.Sprintf("Recipe %s deleted", name))
+	return err
+}
+
+// tagBlueprint tags the commit with the revision of the blueprint. The
+// commits from before the repository was configured aren't in it, they
+// aren't tagged.
+func (g *blueprintsGit) tagBlueprint(name, commit string, revision int) error {
+	_, err := g.git("rev-parse", "--quiet", "--verify", commit+"^{commit}")
+	if err != nil {
+		return nil
+	}
+	tag := fmt.Sprintf("%s/r%d", name, revision)
+	_, err = g.git("tag", "--", tag, commit)
+	if err != nil {
+		return err
+	}
+	g.push("refs/tags/" + tag)
+	return nil
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
+
+// remoteRef returns the commit of the ref, empty if it doesn't exist (yet)
+func remoteRef(remote, ref string) string {
+	cmd := exec.Command("git", "rev-parse", "--quiet", "--verify", ref)
+	cmd.Dir = remote
+	out, _ := cmd.Output()
+	return strings.TrimSpace(string(out))
+}
+
+func TestBlueprintsGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tmpDir := t.TempDir()
+	remote := filepath.Join(tmpDir, "remote.git")
+	gitOutput(t, tmpDir, "init", "--quiet", "--bare", remote)
+	dir := filepath.Join(tmpDir, "blueprints")
+
+	s := New(nil, nil, nil)
+	err := s.EnableBlueprintsGit(BlueprintsGitConfig{Dir: dir, Remote: remote}, nil)
+	require.NoError(t, err)
+
+	bp := blueprint.Blueprint{Name: "test", Version: "0.0.1", Packages: []blueprint.Package{{Name: "tmux"}}}
+	require.NoError(t, s.PushBlueprint(bp, "first"))
+	bp.Packages = append(bp.Packages, blueprint.Package{Name: "vim"})
+	require.NoError(t, s.PushBlueprint(bp, "second"))
+
+	// the commits of the changes are the ones of the repository
+	changes := s.GetBlueprintChanges("test")
+	require.Len(t, changes, 2)
+	require.Equal(t, gitOutput(t, dir, "rev-parse", "HEAD~1"), changes[0].Commit)
+	require.Equal(t, gitOutput(t, dir, "rev-parse", "HEAD"), changes[1].Commit)
+	require.Equal(t, "second", gitOutput(t, dir, "log", "-1", "--format=%s"))
+
+	// the repository has the stored blueprint, with the bumped version
+	data, err := os.ReadFile(filepath.Join(dir, "test.toml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `version = "0.0.2"`)
+	require.Contains(t, string(data), `name = "vim"`)
+	first := gitOutput(t, dir, "show", changes[0].Commit+":test.toml")
+	require.NotContains(t, first, "vim")
+
+	require.NoError(t, s.TagBlueprint("test"))
+	require.Equal(t, changes[1].Commit, gitOutput(t, dir, "rev-parse", "test/r1^{commit}"))
+
+	require.NoError(t, s.DeleteBlueprint("test"))
+	require.NoFileExists(t, filepath.Join(dir, "test.toml"))
+	require.Equal(t, "Recipe test deleted", gitOutput(t, dir, "log", "-1", "--format=%s"))
+
+	// everything is pushed to the remote, in the background
+	head := gitOutput(t, dir, "rev-parse", "HEAD")
+	require.Eventually(t, func() bool {
+		return remoteRef(remote, "main") == head && remoteRef(remote, "test/r1^{commit}") == changes[1].Commit
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// the existing repository is reused
+	s = New(nil, nil, nil)
+	err = s.EnableBlueprintsGit(BlueprintsGitConfig{Dir: dir, Remote: remote}, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.PushBlueprint(bp, "third"))
+	require.Equal(t, "4", gitOutput(t, dir, "rev-list", "--count", "HEAD"))
+}
+
+func TestBlueprintsGitPushFailure(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// the remote doesn't exist yet, the changes are stored anyway
+	tmpDir := t.TempDir()
+	remote := filepath.Join(tmpDir, "remote.git")
+	dir := filepath.Join(tmpDir, "blueprints")
+
+	s := New(nil, nil, nil)
+	err := s.EnableBlueprintsGit(BlueprintsGitConfig{Dir: dir, Remote: remote}, nil)
+	require.NoError(t, err)
+
+	bp := blueprint.Blueprint{Name: "test", Version: "0.0.1"}
+	require.NoError(t, s.PushBlueprint(bp, "first"))
+	require.NoError(t, s.TagBlueprint("test"))
+	require.Eventually(t, func() bool {
+		s.blueprintsGit.pushMu.Lock()
+		defer s.blueprintsGit.pushMu.Unlock()
+		return len(s.blueprintsGit.pendingRefs) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// the refs of the failed pushes are pushed with the next one
+	gitOutput(t, tmpDir, "init", "--quiet", "--bare", remote)
+	require.NoError(t, s.PushBlueprint(bp, "second"))
+	head := gitOutput(t, dir, "rev-parse", "HEAD")
+	first := gitOutput(t, dir, "rev-parse", "HEAD~1")
+	require.Eventually(t, func() bool {
+		return remoteRef(remote, "main") == head && remoteRef(remote, "test/r1^{commit}") == first
+	}, 10*time.Second, 10*time.Millisecond)
+}
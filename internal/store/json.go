@@ -1,6 +1,7 @@
 package store
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -18,12 +19,15 @@ import (
 )
 
 type storeV0 struct {
-	Blueprints blueprintsV0 `json:"blueprints"`
-	Workspace  workspaceV0  `json:"workspace"`
-	Composes   composesV0   `json:"composes"`
-	Sources    sourcesV0    `json:"sources"`
-	Changes    changesV0    `json:"changes"`
-	Commits    commitsV0    `json:"commits"`
+	Blueprints     blueprintsV0     `json:"blueprints"`
+	Workspace      workspaceV0      `json:"workspace"`
+	Composes       composesV0       `json:"composes"`
+	Sources        sourcesV0        `json:"sources"`
+	Changes        changesV0        `json:"changes"`
+	Commits        commitsV0        `json:"commits"`
+	Schedules      schedulesV0      `json:"schedules,omitempty"`
+	UploadProfiles uploadProfilesV0 `json:"upload_profiles,omitempty"`
+	Ownership      ownershipV0      `json:"blueprints_ownership,omitempty"`
 }
 
 type blueprintsV0 map[string]blueprint.Blueprint
@@ -56,19 +60,24 @@ type imageBuildV0 struct {
 	// before the move to the job queue use this to store whether they
 	// finished successfully.
 	QueueStatus common.ImageBuildState `json:"queue_status,omitempty"`
+
+	Deferred bool `json:"deferred,omitempty"`
+	Priority bool `json:"priority,omitempty"`
 }
 
 type sourceV0 struct {
-	Name         string   `json:"name"`
-	Type         string   `json:"type"`
-	URL          string   `json:"url"`
-	CheckGPG     bool     `json:"check_gpg"`
-	CheckSSL     bool     `json:"check_ssl"`
-	System       bool     `json:"system"`
-	Distros      []string `json:"distros"`
-	RHSM         bool     `json:"rhsm"`
-	CheckRepoGPG bool     `json:"check_repogpg"`
-	GPGKeys      []string `json:"gpgkeys"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	URL            string   `json:"url"`
+	CheckGPG       bool     `json:"check_gpg"`
+	CheckSSL       bool     `json:"check_ssl"`
+	System         bool     `json:"system"`
+	Distros        []string `json:"distros"`
+	RHSM           bool     `json:"rhsm"`
+	CheckRepoGPG   bool     `json:"check_repogpg"`
+	GPGKeys        []string `json:"gpgkeys"`
+	ModuleHotfixes bool     `json:"module_hotfixes,omitempty"`
+	ModuleStreams  []string `json:"module_streams,omitempty"`
 }
 
 type sourcesV0 map[string]sourceV0
@@ -85,6 +94,33 @@ type changesV0 map[string]map[string]changeV0
 
 type commitsV0 map[string][]string
 
+type scheduleV0 struct {
+	Cron    string               `json:"cron"`
+	Owner   string               `json:"owner,omitempty"`
+	Request json.RawMessage      `json:"request"`
+	Created time.Time            `json:"created"`
+	Next    time.Time            `json:"next"`
+	History []scheduledComposeV0 `json:"history"`
+}
+
+type scheduledComposeV0 struct {
+	Started   time.Time `json:"started"`
+	ComposeID uuid.UUID `json:"compose_id"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type schedulesV0 map[uuid.UUID]scheduleV0
+
+// the encrypted settings of the upload profiles, by provider and name
+type uploadProfilesV0 map[string]map[string][]byte
+
+type blueprintOwnershipV0 struct {
+	Owner   string `json:"owner"`
+	Private bool   `json:"private,omitempty"`
+}
+
+type ownershipV0 map[string]blueprintOwnershipV0
+
 func newBlueprintsFromV0(blueprintsStruct blueprintsV0) map[string]blueprint.Blueprint {
 	blueprints := make(map[string]blueprint.Blueprint)
 	for name, blueprint := range blueprintsStruct {
@@ -132,7 +168,10 @@ func newImageBuildFromV0(imageBuildStruct imageBuildV0, arch distro.Arch) (Image
 	queueStatus := imageBuildStruct.QueueStatus
 	switch queueStatus {
 	case common.IBRunning, common.IBWaiting:
-		queueStatus = common.IBFailed
+		// deferred builds are still waiting for their job
+		if !imageBuildStruct.Deferred {
+			queueStatus = common.IBFailed
+		}
 	}
 	return ImageBuild{
 		ID:          imageBuildStruct.ID,
@@ -145,6 +184,8 @@ func newImageBuildFromV0(imageBuildStruct imageBuildV0, arch distro.Arch) (Image
 		Size:        imageBuildStruct.Size,
 		JobID:       imageBuildStruct.JobID,
 		QueueStatus: queueStatus,
+		Deferred:    imageBuildStruct.Deferred,
+		Priority:    imageBuildStruct.Priority,
 	}, nil
 }
 
@@ -257,14 +298,60 @@ func newCommitsFromV0(commitsMapStruct commitsV0, changesMapStruct changesV0) ma
 	return commitsMap
 }
 
+func newSchedulesFromV0(schedulesStruct schedulesV0) map[uuid.UUID]ComposeSchedule {
+	schedules := make(map[uuid.UUID]ComposeSchedule)
+	for id, schedule := range schedulesStruct {
+		history := make([]ScheduledCompose, 0, len(schedule.History))
+		for _, compose := range schedule.History {
+			history = append(history, ScheduledCompose(compose))
+		}
+		schedules[id] = ComposeSchedule{
+			ID:      id,
+			Cron:    schedule.Cron,
+			Owner:   schedule.Owner,
+			Request: schedule.Request,
+			Created: schedule.Created,
+			Next:    schedule.Next,
+			History: history,
+		}
+	}
+	return schedules
+}
+
+func newUploadProfilesFromV0(profilesStruct uploadProfilesV0) map[string]map[string]UploadProfile {
+	profiles := make(map[string]map[string]UploadProfile)
+	for provider, providerProfiles := range profilesStruct {
+		profiles[provider] = make(map[string]UploadProfile)
+		for name, settings := range providerProfiles {
+			profiles[provider][name] = UploadProfile{
+				Provider:          provider,
+				Name:              name,
+				EncryptedSettings: settings,
+			}
+		}
+	}
+	return profiles
+}
+
+func newOwnershipFromV0(ownershipStruct ownershipV0) map[string]BlueprintOwnership {
+	ownership := make(map[string]BlueprintOwnership)
+	for name, o := range ownershipStruct {
+		ownership[name] = BlueprintOwnership(o)
+	}
+	return ownership
+}
+
 func newStoreFromV0(storeStruct storeV0, dr *distroregistry.Registry, log *log.Logger) *Store {
 	return &Store{
-		blueprints:        newBlueprintsFromV0(storeStruct.Blueprints),
-		workspace:         newWorkspaceFromV0(storeStruct.Workspace),
-		composes:          newComposesFromV0(storeStruct.Composes, dr, log),
-		sources:           newSourceConfigsFromV0(storeStruct.Sources),
-		blueprintsChanges: newChangesFromV0(storeStruct.Changes),
-		blueprintsCommits: newCommitsFromV0(storeStruct.Commits, storeStruct.Changes),
+		blueprints:          newBlueprintsFromV0(storeStruct.Blueprints),
+		workspace:           newWorkspaceFromV0(storeStruct.Workspace),
+		composes:            newComposesFromV0(storeStruct.Composes, dr, log),
+		sources:             newSourceConfigsFromV0(storeStruct.Sources),
+		blueprintsChanges:   newChangesFromV0(storeStruct.Changes),
+		blueprintsCommits:   newCommitsFromV0(storeStruct.Commits, storeStruct.Changes),
+		composeSchedules:    newSchedulesFromV0(storeStruct.Schedules),
+		uploadProfiles:      newUploadProfilesFromV0(storeStruct.UploadProfiles),
+		blueprintsOwnership: newOwnershipFromV0(storeStruct.Ownership),
 	}
 }
 
@@ -304,6 +391,8 @@ func newComposeV0(compose Compose) composeV0 {
 				Size:        compose.ImageBuild.Size,
 				JobID:       compose.ImageBuild.JobID,
 				QueueStatus: compose.ImageBuild.QueueStatus,
+				Deferred:    compose.ImageBuild.Deferred,
+				Priority:    compose.ImageBuild.Priority,
 			},
 		},
 		Packages: pkgs,
@@ -352,14 +441,55 @@ func newCommitsV0(commits map[string][]string) commitsV0 {
 	return commitsStruct
 }
 
+func newSchedulesV0(schedules map[uuid.UUID]ComposeSchedule) schedulesV0 {
+	schedulesStruct := make(schedulesV0)
+	for id, schedule := range schedules {
+		history := make([]scheduledComposeV0, 0, len(schedule.History))
+		for _, compose := range schedule.History {
+			history = append(history, scheduledComposeV0(compose))
+		}
+		schedulesStruct[id] = scheduleV0{
+			Cron:    schedule.Cron,
+			Owner:   schedule.Owner,
+			Request: schedule.Request,
+			Created: schedule.Created,
+			Next:    schedule.Next,
+			History: history,
+		}
+	}
+	return schedulesStruct
+}
+
+func newUploadProfilesV0(profiles map[string]map[string]UploadProfile) uploadProfilesV0 {
+	profilesStruct := make(uploadProfilesV0)
+	for provider, providerProfiles := range profiles {
+		profilesStruct[provider] = make(map[string][]byte)
+		for name, profile := range providerProfiles {
+			profilesStruct[provider][name] = profile.EncryptedSettings
+		}
+	}
+	return profilesStruct
+}
+
+func newOwnershipV0(ownership map[string]BlueprintOwnership) ownershipV0 {
+	ownershipStruct := make(ownershipV0)
+	for name, o := range ownership {
+		ownershipStruct[name] = blueprintOwnershipV0(o)
+	}
+	return ownershipStruct
+}
+
 func (store *Store) toStoreV0() *storeV0 {
 	return &storeV0{
-		Blueprints: newBlueprintsV0(store.blueprints),
-		Workspace:  newWorkspaceV0(store.workspace),
-		Composes:   newComposesV0(store.composes),
-		Sources:    newSourcesV0(store.sources),
-		Changes:    newChangesV0(store.blueprintsChanges),
-		Commits:    newCommitsV0(store.blueprintsCommits),
+		Blueprints:     newBlueprintsV0(store.blueprints),
+		Workspace:      newWorkspaceV0(store.workspace),
+		Composes:       newComposesV0(store.composes),
+		Sources:        newSourcesV0(store.sources),
+		Changes:        newChangesV0(store.blueprintsChanges),
+		Commits:        newCommitsV0(store.blueprintsCommits),
+		Schedules:      newSchedulesV0(store.composeSchedules),
+		UploadProfiles: newUploadProfilesV0(store.uploadProfiles),
+		Ownership:      newOwnershipV0(store.blueprintsOwnership),
 	}
 }
 
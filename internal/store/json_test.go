@@ -163,12 +163,15 @@ func TestStore_toStoreV0(t *testing.T) {
 			name:   "empty",
 			fields: fields{},
 			want: &storeV0{
-				Blueprints: make(blueprintsV0),
-				Workspace:  make(workspaceV0),
-				Composes:   make(composesV0),
-				Sources:    make(sourcesV0),
-				Changes:    make(changesV0),
-				Commits:    make(commitsV0),
+				Blueprints:     make(blueprintsV0),
+				Workspace:      make(workspaceV0),
+				Composes:       make(composesV0),
+				Sources:        make(sourcesV0),
+				Changes:        make(changesV0),
+				Commits:        make(commitsV0),
+				Schedules:      make(schedulesV0),
+				UploadProfiles: make(uploadProfilesV0),
+				Ownership:      make(ownershipV0),
 			},
 		},
 	}
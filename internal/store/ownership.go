@@ -0,0 +1,31 @@
+package store
+
+// BlueprintOwnership is the user who created the blueprint and whether the
+// other users can see it. Blueprints created by unknown users have none,
+// anyone can change them.
+type BlueprintOwnership struct {
+	Owner   string
+	Private bool
+}
+
+func (s *Store) GetBlueprintOwnership(name string) (BlueprintOwnership, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ownership, exists := s.blueprintsOwnership[name]
+	return ownership, exists
+}
+
+// SetBlueprintOwnership sets the ownership of the blueprint, it's deleted with
+// the blueprint.
+func (s *Store) SetBlueprintOwnership(name string, ownership BlueprintOwnership) error {
+	return s.change(func() error {
+		_, inWorkspace := s.workspace[name]
+		_, committed := s.blueprints[name]
+		if !inWorkspace && !committed {
+			return &NotFoundError{"blueprint does not exist"}
+		}
+		s.blueprintsOwnership[name] = ownership
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"sort"
+)
+
+// An UploadProfile is named upload settings of a provider, so composes can
+// be uploaded without passing the credentials each time. The settings are
+// encrypted by the API, the store never has them in plain text.
+type UploadProfile struct {
+	Provider          string
+	Name              string
+	EncryptedSettings []byte
+}
+
+func (s *Store) GetUploadProfile(provider, name string) (UploadProfile, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profile, exists := s.uploadProfiles[provider][name]
+	return profile, exists
+}
+
+// GetUploadProfiles returns the profiles of all the providers, sorted by
+// provider and name.
+func (s *Store) GetUploadProfiles() []UploadProfile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var profiles []UploadProfile
+	for _, providerProfiles := range s.uploadProfiles {
+		for _, profile := range providerProfiles {
+			profiles = append(profiles, profile)
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Provider != profiles[j].Provider {
+			return profiles[i].Provider < profiles[j].Provider
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+// PushUploadProfile adds the profile, or replaces the one of the provider
+// with the same name.
+func (s *Store) PushUploadProfile(profile UploadProfile) error {
+	return s.change(func() error {
+		if s.uploadProfiles[profile.Provider] == nil {
+			s.uploadProfiles[profile.Provider] = make(map[string]UploadProfile)
+		}
+		s.uploadProfiles[profile.Provider][profile.Name] = profile
+		return nil
+	})
+}
+
+func (s *Store) DeleteUploadProfile(provider, name string) error {
+	return s.change(func() error {
+		if _, exists := s.uploadProfiles[provider][name]; !exists {
+			return &NotFoundError{"upload profile does not exist"}
+		}
+		delete(s.uploadProfiles[provider], name)
+		if len(s.uploadProfiles[provider]) == 0 {
+			delete(s.uploadProfiles, provider)
+		}
+		return nil
+	})
+}
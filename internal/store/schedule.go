@@ -0,0 +1,104 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxScheduledComposes is the number of composes kept in the history of a
+// schedule, the older ones are dropped.
+const maxScheduledComposes = 100
+
+// A ComposeSchedule starts a compose whenever its cron expression matches.
+type ComposeSchedule struct {
+	ID   uuid.UUID
+	Cron string
+	// User who created the schedule, only they can see and delete it.
+	// Schedules created by unknown users have none.
+	Owner string
+	// Compose request of the Weldr API the composes are started with,
+	// including the upload settings
+	Request json.RawMessage
+	Created time.Time
+	// When the next compose is started
+	Next time.Time
+	// Composes started by the schedule, the most recent one last
+	History []ScheduledCompose
+}
+
+// A ScheduledCompose is a compose started by a schedule.
+type ScheduledCompose struct {
+	Started time.Time
+	// ID of the compose, the nil UUID if it couldn't be started
+	ComposeID uuid.UUID
+	// Why the compose couldn't be started
+	Error string
+}
+
+func (s *ComposeSchedule) deepCopy() ComposeSchedule {
+	schedule := *s
+	schedule.Request = append(json.RawMessage(nil), s.Request...)
+	schedule.History = append([]ScheduledCompose(nil), s.History...)
+	return schedule
+}
+
+func (s *Store) GetComposeSchedule(id uuid.UUID) (ComposeSchedule, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	schedule, exists := s.composeSchedules[id]
+	if !exists {
+		return ComposeSchedule{}, false
+	}
+	return schedule.deepCopy(), true
+}
+
+func (s *Store) GetAllComposeSchedules() map[uuid.UUID]ComposeSchedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	schedules := make(map[uuid.UUID]ComposeSchedule)
+	for id, schedule := range s.composeSchedules {
+		schedules[id] = schedule.deepCopy()
+	}
+	return schedules
+}
+
+func (s *Store) PushComposeSchedule(schedule ComposeSchedule) error {
+	return s.change(func() error {
+		s.composeSchedules[schedule.ID] = schedule.deepCopy()
+		return nil
+	})
+}
+
+// DeleteComposeSchedule deletes the schedule, the composes it started are
+// kept.
+func (s *Store) DeleteComposeSchedule(id uuid.UUID) error {
+	return s.change(func() error {
+		if _, exists := s.composeSchedules[id]; !exists {
+			return &NotFoundError{"schedule does not exist"}
+		}
+		delete(s.composeSchedules, id)
+		return nil
+	})
+}
+
+// PushScheduledCompose adds the compose to the history of the schedule and
+// sets when the next one is started.
+func (s *Store) PushScheduledCompose(id uuid.UUID, compose ScheduledCompose, next time.Time) error {
+	return s.change(func() error {
+		schedule, exists := s.composeSchedules[id]
+		if !exists {
+			return &NotFoundError{"schedule does not exist"}
+		}
+		schedule.History = append(schedule.History, compose)
+		if len(schedule.History) > maxScheduledComposes {
+			schedule.History = schedule.History[len(schedule.History)-maxScheduledComposes:]
+		}
+		schedule.Next = next
+		s.composeSchedules[id] = schedule
+		return nil
+	})
+}
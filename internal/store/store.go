@@ -44,30 +44,39 @@ const StoreDBName = "state"
 // blueprintsChanges contains the blueprint change, using the blueprint name string and
 // the hash string from blueprintsCommits
 type Store struct {
-	blueprints        map[string]blueprint.Blueprint
-	workspace         map[string]blueprint.Blueprint
-	composes          map[uuid.UUID]Compose
-	sources           map[string]SourceConfig
-	blueprintsChanges map[string]map[string]blueprint.Change
-	blueprintsCommits map[string][]string
-
-	mu       sync.RWMutex // protects all fields
-	stateDir *string
-	db       *jsondb.JSONDatabase
+	blueprints          map[string]blueprint.Blueprint
+	workspace           map[string]blueprint.Blueprint
+	composes            map[uuid.UUID]Compose
+	sources             map[string]SourceConfig
+	blueprintsChanges   map[string]map[string]blueprint.Change
+	blueprintsCommits   map[string][]string
+	composeSchedules    map[uuid.UUID]ComposeSchedule
+	uploadProfiles      map[string]map[string]UploadProfile
+	blueprintsOwnership map[string]BlueprintOwnership
+
+	mu            sync.RWMutex // protects all fields
+	stateDir      *string
+	db            *jsondb.JSONDatabase
+	blueprintsGit *blueprintsGit
 }
 
 type SourceConfig struct {
-	Name         string   `json:"name" toml:"name"`
-	Type         string   `json:"type" toml:"type"`
-	URL          string   `json:"url" toml:"url"`
-	CheckGPG     bool     `json:"check_gpg" toml:"check_gpg"`
-	CheckSSL     bool     `json:"check_ssl" toml:"check_ssl"`
-	System       bool     `json:"system" toml:"system"`
-	Distros      []string `json:"distros" toml:"distros"`
-	RHSM         bool     `json:"rhsm" toml:"rhsm"`
-	CheckRepoGPG bool     `json:"check_repogpg" toml:"check_repogpg"`
-	GPGKeys      []string `json:"gpgkeys"`
-}
+	Name           string   `json:"name" toml:"name"`
+	Type           string   `json:"type" toml:"type"`
+	URL            string   `json:"url" toml:"url"`
+	CheckGPG       bool     `json:"check_gpg" toml:"check_gpg"`
+	CheckSSL       bool     `json:"check_ssl" toml:"check_ssl"`
+	System         bool     `json:"system" toml:"system"`
+	Distros        []string `json:"distros" toml:"distros"`
+	RHSM           bool     `json:"rhsm" toml:"rhsm"`
+	CheckRepoGPG   bool     `json:"check_repogpg" toml:"check_repogpg"`
+	GPGKeys        []string `json:"gpgkeys"`
+	ModuleHotfixes bool     `json:"module_hotfixes" toml:"module_hotfixes"`
+	ModuleStreams  []string `json:"module_streams" toml:"module_streams"`
+}
+
+// coprResultsURL is where COPR publishes the repositories of its projects.
+const coprResultsURL = "https://download.copr.fedorainfracloud.org/results/"
 
 type NotFoundError struct {
 	message string
@@ -105,6 +114,21 @@ func New(stateDir *string, dr *distroregistry.Registry, log *log.Logger) *Store
 	return store
 }
 
+// EnableBlueprintsGit commits every change to the blueprints to the git
+// repository from now on, and uses the hashes of the git commits as the
+// commits of the changes.
+func (s *Store) EnableBlueprintsGit(config BlueprintsGitConfig, log *log.Logger) error {
+	g, err := newBlueprintsGit(config, log)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blueprintsGit = g
+	return nil
+}
+
 func randomSHA1String() (string, error) {
 	// The use of SHA1 is accepted here
 	/* #nosec G401 */
@@ -221,7 +245,19 @@ func (s *Store) PushBlueprint(bp blueprint.Blueprint, commitMsg string) error {
 			return err
 		}
 
-		commit, err := randomSHA1String()
+		stored := bp
+		if old, ok := s.blueprints[bp.Name]; ok {
+			if bp.Version == "" || bp.Version == old.Version {
+				stored.BumpVersion(old.Version)
+			}
+		}
+
+		var commit string
+		if s.blueprintsGit != nil {
+			commit, err = s.blueprintsGit.commitBlueprint(stored, commitMsg)
+		} else {
+			commit, err = randomSHA1String()
+		}
 		if err != nil {
 			return err
 		}
@@ -241,13 +277,7 @@ func (s *Store) PushBlueprint(bp blueprint.Blueprint, commitMsg string) error {
 		s.blueprintsChanges[bp.Name][commit] = change
 		// Keep track of the order of the commits
 		s.blueprintsCommits[bp.Name] = append(s.blueprintsCommits[bp.Name], commit)
-
-		if old, ok := s.blueprints[bp.Name]; ok {
-			if bp.Version == "" || bp.Version == old.Version {
-				bp.BumpVersion(old.Version)
-			}
-		}
-		s.blueprints[bp.Name] = bp
+		s.blueprints[bp.Name] = stored
 		return nil
 	})
 }
@@ -278,7 +308,14 @@ func (s *Store) DeleteBlueprint(name string) error {
 		if _, ok := s.blueprints[name]; !ok {
 			return fmt.Errorf("Unknown blueprint: %s", name)
 		}
+		if s.blueprintsGit != nil {
+			err := s.blueprintsGit.deleteBlueprint(name)
+			if err != nil {
+				return err
+			}
+		}
 		delete(s.blueprints, name)
+		delete(s.blueprintsOwnership, name)
 		return nil
 	})
 }
@@ -291,6 +328,9 @@ func (s *Store) DeleteBlueprintFromWorkspace(name string) error {
 			return fmt.Errorf("Unknown blueprint: %s", name)
 		}
 		delete(s.workspace, name)
+		if _, ok := s.blueprints[name]; !ok {
+			delete(s.blueprintsOwnership, name)
+		}
 		return nil
 	})
 }
@@ -334,6 +374,12 @@ func (s *Store) TagBlueprint(name string) error {
 
 		// Bump the revision (if there was none it will start at 1) of the latest commit
 		revision++
+		if s.blueprintsGit != nil {
+			err := s.blueprintsGit.tagBlueprint(name, latest, revision)
+			if err != nil {
+				return err
+			}
+		}
 		change := s.blueprintsChanges[name][latest]
 		change.Revision = &revision
 		s.blueprintsChanges[name][latest] = change
@@ -401,6 +447,74 @@ func (s *Store) PushCompose(composeID uuid.UUID,
 	return nil
 }
 
+// PushDeferredCompose adds a compose whose job isn't enqueued yet, it's
+// waiting until SetComposeJob is called with the job.
+func (s *Store) PushDeferredCompose(composeID uuid.UUID,
+	manifest manifest.OSBuildManifest,
+	imageType distro.ImageType,
+	bp *blueprint.Blueprint,
+	size uint64,
+	targets []*target.Target,
+	priority bool,
+	packages []rpmmd.PackageSpec) error {
+
+	if _, exists := s.GetCompose(composeID); exists {
+		panic("a compose with this id already exists")
+	}
+
+	if targets == nil {
+		targets = []*target.Target{}
+	}
+
+	return s.change(func() error {
+		s.composes[composeID] = Compose{
+			Blueprint: bp,
+			ImageBuild: ImageBuild{
+				QueueStatus: common.IBWaiting,
+				Manifest:    manifest,
+				ImageType:   imageType,
+				Targets:     targets,
+				JobCreated:  time.Now(),
+				Size:        size,
+				Deferred:    true,
+				Priority:    priority,
+			},
+			Packages: packages,
+		}
+		return nil
+	})
+}
+
+// SetComposeJob sets the job of the deferred compose once it's enqueued.
+func (s *Store) SetComposeJob(composeID uuid.UUID, jobID uuid.UUID) error {
+	return s.change(func() error {
+		compose, exists := s.composes[composeID]
+		if !exists || !compose.ImageBuild.Deferred {
+			return &NotFoundError{"deferred compose does not exist"}
+		}
+		compose.ImageBuild.JobID = jobID
+		compose.ImageBuild.Deferred = false
+		s.composes[composeID] = compose
+		return nil
+	})
+}
+
+// CancelDeferredCompose fails the deferred compose, its job is never
+// enqueued.
+func (s *Store) CancelDeferredCompose(composeID uuid.UUID) error {
+	return s.change(func() error {
+		compose, exists := s.composes[composeID]
+		if !exists || !compose.ImageBuild.Deferred {
+			return &NotFoundError{"deferred compose does not exist"}
+		}
+		compose.ImageBuild.QueueStatus = common.IBFailed
+		compose.ImageBuild.JobFinished = time.Now()
+		compose.ImageBuild.Deferred = false
+		s.composes[composeID] = compose
+		return nil
+	})
+}
+
 // PushTestCompose is used for testing
 // Set testSuccess to create a fake successful compose, otherwise it will create a failed compose
 // It does not actually run a compose job
@@ -644,3 +758,36 @@ func (s *SourceConfig) RepoConfig(name string) rpmmd.RepoConfig {
 
 	return repo
 }
+
+// DistroRepoConfig returns the repository of the source for the distribution.
+// The URL of "copr" sources is a COPR project, owner/project or
+// @group/project, which has a repository for each distribution.
+func (s *SourceConfig) DistroRepoConfig(name, distro string) rpmmd.RepoConfig {
+	repo := s.RepoConfig(name)
+	if s.Type == "copr" {
+		repo.BaseURLs = []string{coprResultsURL + s.URL + "/" + coprChroot(distro) + "-$basearch/"}
+		if len(repo.GPGKeys) == 0 {
+			repo.GPGKeys = []string{coprResultsURL + s.URL + "/pubkey.gpg"}
+		}
+	}
+	return repo
+}
+
+// coprChroot returns the name of the COPR chroot of the distribution, without
+// the architecture. The packages for RHEL are built in the EPEL chroots.
+func coprChroot(distro string) string {
+	name, version, _ := strings.Cut(distro, "-")
+	switch name {
+	case "rhel":
+		// rhel-93 is RHEL 9.3, rhel-10.0 is RHEL 10.0
+		if major, _, found := strings.Cut(version, "."); found {
+			return "epel-" + major
+		}
+		if len(version) > 0 {
+			return "epel-" + version[:1]
+		}
+	case "centos":
+		return "centos-stream-" + version
+	}
+	return distro
+}
@@ -322,6 +322,33 @@ func (suite *storeTest) TestPushCompose() {
 	suite.NoError(err)
 }
 
+func (suite *storeTest) TestPushDeferredCompose() {
+	ID := uuid.New()
+	err := suite.myStore.PushDeferredCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, true, []rpmmd.PackageSpec{})
+	suite.NoError(err)
+	suite.True(suite.myStore.composes[ID].ImageBuild.Deferred)
+	suite.True(suite.myStore.composes[ID].ImageBuild.Priority)
+	suite.Equal(common.IBWaiting, suite.myStore.composes[ID].ImageBuild.QueueStatus)
+
+	jobID := uuid.New()
+	err = suite.myStore.SetComposeJob(ID, jobID)
+	suite.NoError(err)
+	suite.False(suite.myStore.composes[ID].ImageBuild.Deferred)
+	suite.Equal(jobID, suite.myStore.composes[ID].ImageBuild.JobID)
+	err = suite.myStore.SetComposeJob(ID, uuid.New())
+	suite.Error(err)
+	err = suite.myStore.CancelDeferredCompose(ID)
+	suite.Error(err)
+
+	ID = uuid.New()
+	err = suite.myStore.PushDeferredCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, false, []rpmmd.PackageSpec{})
+	suite.NoError(err)
+	err = suite.myStore.CancelDeferredCompose(ID)
+	suite.NoError(err)
+	suite.False(suite.myStore.composes[ID].ImageBuild.Deferred)
+	suite.Equal(common.IBFailed, suite.myStore.composes[ID].ImageBuild.QueueStatus)
+}
+
 func (suite *storeTest) TestPushTestCompose() {
 	ID := uuid.New()
 	err := suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, true, []rpmmd.PackageSpec{})
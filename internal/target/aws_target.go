@@ -22,6 +22,29 @@ type AWSTargetOptions struct {
 	// If not provided, then the Boot mode will be determined by the default
 	// boot mode of the instance provisioned from the AMI.
 	BootMode *string `json:"bootMode,omitempty"`
+
+	// Only import the snapshot, without registering an AMI
+	SnapshotOnly bool `json:"snapshotOnly,omitempty"`
+
+	// Customer managed KMS key encrypting the snapshot and the AMI, its ID,
+	// ARN or alias. The accounts the AMI is shared with are granted access
+	// to the key.
+	KMSKeyID string `json:"kmsKeyId,omitempty"`
+
+	// Tags of the AMI and its snapshot, in addition to the Name tag
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Instance metadata service support of the AMI (optional)
+	// Supported values:
+	//  - ec2.ImdsSupportValuesV20
+	// If not provided, instances launched from the AMI allow IMDSv1.
+	IMDSSupport *string `json:"imdsSupport,omitempty"`
+
+	// Elastic Network Adapter support of the AMI, enabled if not provided
+	ENASupport *bool `json:"enaSupport,omitempty"`
+
+	// Enhanced networking with the Intel 82599 VF interface
+	SriovNetSupport bool `json:"sriovNetSupport,omitempty"`
 }
 
 func (AWSTargetOptions) isTargetOptions() {}
@@ -31,8 +54,20 @@ func NewAWSTarget(options *AWSTargetOptions) *Target {
 }
 
 type AWSTargetResultOptions struct {
-	Ami    string `json:"ami"`
-	Region string `json:"region"`
+	// Empty if only the snapshot was imported
+	Ami        string `json:"ami"`
+	Region     string `json:"region"`
+	SnapshotID string `json:"snapshotId,omitempty"`
+	// ARN of the KMS key encrypting the snapshot, empty if not encrypted
+	KMSKeyARN string `json:"kmsKeyArn,omitempty"`
+
+	// Parameters the AMI was registered with, not set if only the snapshot
+	// was imported
+	BootMode        *string           `json:"bootMode,omitempty"`
+	Tags            map[string]string `json:"tags,omitempty"`
+	IMDSSupport     *string           `json:"imdsSupport,omitempty"`
+	ENASupport      *bool             `json:"enaSupport,omitempty"`
+	SriovNetSupport bool              `json:"sriovNetSupport,omitempty"`
 }
 
 func (AWSTargetResultOptions) isTargetResultOptions() {}
@@ -52,6 +87,28 @@ type AWSS3TargetOptions struct {
 	CABundle            string `json:"ca_bundle"`
 	SkipSSLVerification bool   `json:"skip_ssl_verification"`
 	Public              bool   `json:"public,omitempty"`
+	// PEM encoded certificates trusted for the endpoint, used instead of the
+	// CABundle file on the worker
+	CABundleData string `json:"ca_bundle_data,omitempty"`
+	// Address buckets in the host of the URLs instead of their path
+	VirtualHostedStyle bool `json:"virtual_hosted_style,omitempty"`
+	// Lifetime of the presigned URL of a non-public object, the maximum
+	// of 7 days if 0
+	URLExpiryHours int `json:"url_expiry_hours,omitempty"`
+	// Canned ACL of a non-public object, e.g. bucket-owner-full-control
+	ACL string `json:"acl,omitempty"`
+	// Return the bucket and the key of the object besides its URL
+	ReturnObjectKey bool `json:"return_object_key,omitempty"`
+
+	// If set, the uploaded ostree commit is pushed to a remote repository by
+	// a job following the osbuild job.
+	OSTreeMirror *OSTreeMirrorOptions `json:"ostree_mirror,omitempty"`
+}
+
+type OSTreeMirrorOptions struct {
+	URL      string `json:"url"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
 }
 
 func (AWSS3TargetOptions) isTargetOptions() {}
@@ -62,6 +119,11 @@ func NewAWSS3Target(options *AWSS3TargetOptions) *Target {
 
 type AWSS3TargetResultOptions struct {
 	URL string `json:"url"`
+	// Location of the object, if requested
+	Bucket string `json:"bucket,omitempty"`
+	Key    string `json:"key,omitempty"`
+	// URL of the SHA256SUMS file of the artifacts of the job, if requested
+	ChecksumsURL string `json:"checksums_url,omitempty"`
 }
 
 func (AWSS3TargetResultOptions) isTargetResultOptions() {}
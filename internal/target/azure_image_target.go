@@ -7,6 +7,44 @@ type AzureImageTargetOptions struct {
 	Location       string `json:"location,omitempty"`
 	SubscriptionID string `json:"subscription_id"`
 	ResourceGroup  string `json:"resource_group"`
+	// publish the registered image as a version of an image definition in
+	// an Azure Compute Gallery, optional
+	Gallery *AzureGalleryOptions `json:"gallery,omitempty"`
+	// only upload the VHD to a page blob, without registering an image
+	BlobOnly bool `json:"blob_only,omitempty"`
+	// generate a read-only SAS token of the blob valid for this many hours,
+	// only with BlobOnly
+	SASExpiryHours int `json:"sas_expiry_hours,omitempty"`
+	// Hyper-V generation of the image, "V1" or "V2". If empty, it's the
+	// generation of the gallery image definition, or V1 without a gallery.
+	HyperVGeneration string `json:"hyper_v_generation,omitempty"`
+	// security type of the VMs created from the image, "Standard",
+	// "TrustedLaunch" or "ConfidentialVM". It's a property of the gallery
+	// image definition, which has to support it.
+	SecurityType string `json:"security_type,omitempty"`
+}
+
+// AzureGalleryOptions describes the gallery image version the image is
+// published as. The gallery and the image definition must already exist in
+// the resource group of the target.
+type AzureGalleryOptions struct {
+	Name            string `json:"name"`
+	ImageDefinition string `json:"image_definition"`
+	// semantic version of the image version, e.g. 1.0.0
+	Version string `json:"version"`
+	// regions the image version is replicated to, the location of the
+	// target is always one of them
+	TargetRegions []AzureGalleryTargetRegion `json:"target_regions,omitempty"`
+	// default number of replicas per region
+	ReplicaCount int `json:"replica_count,omitempty"`
+	// default storage account type of the replicas, e.g. Standard_LRS
+	StorageAccountType string `json:"storage_account_type,omitempty"`
+}
+
+type AzureGalleryTargetRegion struct {
+	Name               string `json:"name"`
+	ReplicaCount       int    `json:"replica_count,omitempty"`
+	StorageAccountType string `json:"storage_account_type,omitempty"`
 }
 
 func (AzureImageTargetOptions) isTargetOptions() {}
@@ -34,7 +72,14 @@ func NewAzureImageTarget(options *AzureImageTargetOptions) *Target {
 }
 
 type AzureImageTargetResultOptions struct {
+	// Empty if only the blob was uploaded
 	ImageName string `json:"image_name"`
+	// ID of the gallery image version, if the image was published
+	GalleryImageVersionID string `json:"gallery_image_version_id,omitempty"`
+	// URL of the page blob, if only the blob was uploaded
+	BlobURL string `json:"blob_url,omitempty"`
+	// read-only SAS token of the blob, if requested
+	SASToken string `json:"sas_token,omitempty"`
 }
 
 func (AzureImageTargetResultOptions) isTargetResultOptions() {}
@@ -0,0 +1,43 @@
+package target
+
+const TargetNameBareMetal TargetName = "org.osbuild.baremetal"
+
+// BareMetalTargetOptions describe a directory of an HTTP(S) server the raw
+// image and the netboot artifacts are published to, for provisioning bare
+// metal machines with Ironic or Equinix Metal custom images. The artifacts
+// are uploaded to a subdirectory named by the image name of the target.
+type BareMetalTargetOptions struct {
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	// Create the missing WebDAV collections of the URL first
+	WebDAV bool `json:"webdav,omitempty"`
+	// Name of the pipeline whose tree has the kernel and the initrd of the
+	// image in /boot. It's exported in addition to the image and the
+	// netboot artifacts are only published if it's set.
+	NetbootExport string `json:"netboot_export,omitempty"`
+	// Kernel command line of the iPXE script
+	KernelArgs string `json:"kernel_args,omitempty"`
+}
+
+func (BareMetalTargetOptions) isTargetOptions() {}
+
+func NewBareMetalTarget(options *BareMetalTargetOptions) *Target {
+	return newTarget(TargetNameBareMetal, options)
+}
+
+type BareMetalTargetResultOptions struct {
+	ImageURL string `json:"image_url"`
+	// Hex encoded SHA256 digest of the raw image
+	ImageChecksum string `json:"image_checksum"`
+	KernelURL     string `json:"kernel_url,omitempty"`
+	InitrdURL     string `json:"initrd_url,omitempty"`
+	IPXEScriptURL string `json:"ipxe_script_url,omitempty"`
+}
+
+func (BareMetalTargetResultOptions) isTargetResultOptions() {}
+
+func NewBareMetalTargetResult(options *BareMetalTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameBareMetal, options, artifact)
+}
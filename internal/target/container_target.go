@@ -9,6 +9,13 @@ type ContainerTargetOptions struct {
 	Password string `json:"password,omitempty"`
 
 	TlsVerify *bool `json:"tls_verify,omitempty"`
+
+	// Sign the image with the cosign key of the worker and attach an SBOM
+	Sign bool `json:"sign,omitempty"`
+
+	// References the image is pushed to in addition to the image name of
+	// the target, e.g. the same repository with another tag
+	AdditionalReferences []string `json:"additional_references,omitempty"`
 }
 
 func (ContainerTargetOptions) isTargetOptions() {}
@@ -20,6 +27,11 @@ func NewContainerTarget(options *ContainerTargetOptions) *Target {
 type ContainerTargetResultOptions struct {
 	URL    string `json:"url"`
 	Digest string `json:"digest"`
+	Signed bool   `json:"signed,omitempty"`
+	// Reference of the attached SBOM
+	SBOM string `json:"sbom,omitempty"`
+	// All pushed references, the first one is URL
+	References []string `json:"references,omitempty"`
 }
 
 func (ContainerTargetResultOptions) isTargetResultOptions() {}
@@ -13,6 +13,25 @@ type GCPTargetOptions struct {
 	// to GCP. If not provided, the worker will try to authenticate using the
 	// credentials from worker's configuration.
 	Credentials []byte `json:"credentials,omitempty"`
+
+	// If set, the imported image is exported to a tar.gz archive in a
+	// Cloud Storage bucket by a job following the osbuild job.
+	Export *GCPImageExportOptions `json:"export,omitempty"`
+
+	// Guest OS features of the image in addition to the ones of the Os,
+	// e.g. "SEV_SNP_CAPABLE"
+	GuestOsFeatures []string `json:"guestOsFeatures,omitempty"`
+	// Architecture of the image, "X86_64" or "ARM64"
+	Architecture string `json:"architecture,omitempty"`
+	// Image family the image belongs to
+	Family string `json:"family,omitempty"`
+	// URLs of the licenses applying to the image
+	Licenses []string `json:"licenses,omitempty"`
+}
+
+type GCPImageExportOptions struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
 }
 
 func (GCPTargetOptions) isTargetOptions() {}
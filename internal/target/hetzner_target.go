@@ -0,0 +1,29 @@
+package target
+
+const TargetNameHetzner TargetName = "org.osbuild.hetzner"
+
+type HetznerTargetOptions struct {
+	// At most one of Location and Datacenter is set, Hetzner Cloud picks
+	// one if neither is
+	Location   string `json:"location,omitempty"`
+	Datacenter string `json:"datacenter,omitempty"`
+	// Type of the temporary server the image is written with
+	ServerType string            `json:"server_type"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+func (HetznerTargetOptions) isTargetOptions() {}
+
+func NewHetznerTarget(options *HetznerTargetOptions) *Target {
+	return newTarget(TargetNameHetzner, options)
+}
+
+type HetznerTargetResultOptions struct {
+	ImageID int64 `json:"image_id"`
+}
+
+func (HetznerTargetResultOptions) isTargetResultOptions() {}
+
+func NewHetznerTargetResult(options *HetznerTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameHetzner, options, artifact)
+}
@@ -0,0 +1,30 @@
+package target
+
+const TargetNameHTTP TargetName = "org.osbuild.http"
+
+// HTTPTargetOptions describe a directory of an HTTP(S) server the image is
+// uploaded to with a PUT request.
+type HTTPTargetOptions struct {
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	// Create the missing WebDAV collections of the URL first
+	WebDAV bool `json:"webdav,omitempty"`
+}
+
+func (HTTPTargetOptions) isTargetOptions() {}
+
+func NewHTTPTarget(options *HTTPTargetOptions) *Target {
+	return newTarget(TargetNameHTTP, options)
+}
+
+type HTTPTargetResultOptions struct {
+	URL string `json:"url"`
+}
+
+func (HTTPTargetResultOptions) isTargetResultOptions() {}
+
+func NewHTTPTargetResult(options *HTTPTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameHTTP, options, artifact)
+}
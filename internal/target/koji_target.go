@@ -10,6 +10,9 @@ const TargetNameKoji TargetName = "org.osbuild.koji"
 type KojiTargetOptions struct {
 	UploadDirectory string `json:"upload_directory"`
 	Server          string `json:"server"`
+	// Kerberos principal to authenticate as, the default one of the server
+	// if empty
+	Principal string `json:"principal,omitempty"`
 }
 
 func (KojiTargetOptions) isTargetOptions() {}
@@ -57,11 +60,34 @@ type ManifestInfo struct {
 	OSBuildComposerDeps []*OSBuildComposerDepModule `json:"osbuild_composer_deps,omitempty"`
 }
 
+// KojiPartition is a partition of a KojiPartitionTable, its start and size are
+// in sectors.
+type KojiPartition struct {
+	Start    uint64 `json:"start,omitempty"`
+	Size     uint64 `json:"size,omitempty"`
+	Type     string `json:"type,omitempty"`
+	UUID     string `json:"uuid,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Bootable bool   `json:"bootable,omitempty"`
+}
+
+// KojiPartitionTable is the partition layout of a disk image.
+type KojiPartitionTable struct {
+	// "gpt" or "dos"
+	Type       string          `json:"type"`
+	UUID       string          `json:"uuid,omitempty"`
+	Partitions []KojiPartition `json:"partitions"`
+}
+
 type KojiTargetResultOptions struct {
 	Image               *KojiOutputInfo `json:"image"`
 	Log                 *KojiOutputInfo `json:"log,omitempty"`
 	OSBuildManifest     *KojiOutputInfo `json:"osbuild_manifest,omitempty"`
 	OSBuildManifestInfo *ManifestInfo   `json:"osbuild_manifest_info,omitempty"`
+	// SPDX document listing the packages of the image
+	SBOM *KojiOutputInfo `json:"sbom,omitempty"`
+	// Partition table of disk images, as partitioned by the manifest
+	PartitionTable *KojiPartitionTable `json:"partition_table,omitempty"`
 }
 
 func (o *KojiTargetResultOptions) UnmarshalJSON(data []byte) error {
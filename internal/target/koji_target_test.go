@@ -43,7 +43,7 @@ func TestKojiTargetResultOptionsUnmarshalJSON(t *testing.T) {
 		},
 		{
 			name: "full format",
-			JSON: []byte(`{"image":{"checksum_type":"md5","checksum":"hash","filename":"image.raw","size":123456},"log":{"checksum_type":"md5","checksum":"hash","filename":"log.txt","size":123456},"osbuild_manifest":{"checksum_type":"md5","checksum":"hash","filename":"manifest.json","size":123456}}`),
+			JSON: []byte(`{"image":{"checksum_type":"md5","checksum":"hash","filename":"image.raw","size":123456},"log":{"checksum_type":"md5","checksum":"hash","filename":"log.txt","size":123456},"osbuild_manifest":{"checksum_type":"md5","checksum":"hash","filename":"manifest.json","size":123456},"sbom":{"checksum_type":"md5","checksum":"hash","filename":"image.raw.spdx.json","size":1234}}`),
 			expectedResult: &KojiTargetResultOptions{
 				Image: &KojiOutputInfo{
 					Filename:     "image.raw",
@@ -63,6 +63,12 @@ func TestKojiTargetResultOptionsUnmarshalJSON(t *testing.T) {
 					Checksum:     "hash",
 					Size:         123456,
 				},
+				SBOM: &KojiOutputInfo{
+					Filename:     "image.raw.spdx.json",
+					ChecksumType: "md5",
+					Checksum:     "hash",
+					Size:         1234,
+				},
 			},
 		},
 		{
@@ -131,8 +137,14 @@ func TestKojiTargetResultOptionsMarshalJSON(t *testing.T) {
 					Checksum:     "hash",
 					Size:         123321,
 				},
+				SBOM: &KojiOutputInfo{
+					Filename:     "image.raw.spdx.json",
+					ChecksumType: ChecksumTypeMD5,
+					Checksum:     "hash",
+					Size:         1234,
+				},
 			},
-			expectedJSON: []byte(`{"image":{"filename":"image.raw","checksum_type":"md5","checksum":"hash","size":123456},"log":{"filename":"log.txt","checksum_type":"md5","checksum":"hash","size":654321},"osbuild_manifest":{"filename":"manifest.json","checksum_type":"md5","checksum":"hash","size":123321},"image_md5":"hash","image_size":123456}`),
+			expectedJSON: []byte(`{"image":{"filename":"image.raw","checksum_type":"md5","checksum":"hash","size":123456},"log":{"filename":"log.txt","checksum_type":"md5","checksum":"hash","size":654321},"osbuild_manifest":{"filename":"manifest.json","checksum_type":"md5","checksum":"hash","size":123321},"sbom":{"filename":"image.raw.spdx.json","checksum_type":"md5","checksum":"hash","size":1234},"image_md5":"hash","image_size":123456}`),
 		},
 		{
 			name: "invalid checksum type",
@@ -0,0 +1,37 @@
+package target
+
+const TargetNameLibvirt TargetName = "org.osbuild.libvirt"
+
+// LibvirtTargetOptions describe the libvirt host the image is imported to as
+// a volume named after the image name of the target, and the domain booting
+// it, if any.
+type LibvirtTargetOptions struct {
+	// Connection URI of the host, the default one of the worker if empty
+	URI  string `json:"uri,omitempty"`
+	Pool string `json:"pool"`
+	// Architecture of the image
+	Arch string `json:"arch"`
+
+	// No domain is created if empty
+	DomainName string `json:"domain_name,omitempty"`
+	Persistent bool   `json:"persistent,omitempty"`
+	MemoryMiB  int    `json:"memory_mib,omitempty"`
+	VCPUs      int    `json:"vcpus,omitempty"`
+}
+
+func (LibvirtTargetOptions) isTargetOptions() {}
+
+func NewLibvirtTarget(options *LibvirtTargetOptions) *Target {
+	return newTarget(TargetNameLibvirt, options)
+}
+
+type LibvirtTargetResultOptions struct {
+	VolumePath string `json:"volume_path"`
+	DomainName string `json:"domain_name,omitempty"`
+}
+
+func (LibvirtTargetResultOptions) isTargetResultOptions() {}
+
+func NewLibvirtTargetResult(options *LibvirtTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameLibvirt, options, artifact)
+}
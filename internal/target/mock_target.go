@@ -0,0 +1,28 @@
+package target
+
+const TargetNameMock TargetName = "org.osbuild.mock"
+
+// MockTargetOptions configure a simulated upload, which doesn't upload the
+// image anywhere. Unset options use the defaults of the worker.
+type MockTargetOptions struct {
+	// Duration of the upload in seconds
+	Latency *uint64 `json:"latency,omitempty"`
+	// Probability of the upload failing, between 0 and 1
+	FailureRate *float64 `json:"failure_rate,omitempty"`
+}
+
+func (MockTargetOptions) isTargetOptions() {}
+
+func NewMockTarget(options *MockTargetOptions) *Target {
+	return newTarget(TargetNameMock, options)
+}
+
+type MockTargetResultOptions struct {
+	URL string `json:"url"`
+}
+
+func (MockTargetResultOptions) isTargetResultOptions() {}
+
+func NewMockTargetResult(options *MockTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameMock, options, artifact)
+}
@@ -11,6 +11,10 @@ type OCITargetOptions struct {
 	Bucket      string `json:"bucket"`
 	Namespace   string `json:"namespace"`
 	Compartment string `json:"compartment_id"`
+	// Launch mode of the instances, PARAVIRTUALIZED if empty
+	LaunchMode string `json:"launch_mode,omitempty"`
+	// Shapes the image is compatible with besides the default ones
+	Shapes []string `json:"shapes,omitempty"`
 }
 
 func (OCITargetOptions) isTargetOptions() {}
@@ -45,12 +49,20 @@ type OCIObjectStorageTargetOptions struct {
 	Bucket      string `json:"bucket"`
 	Namespace   string `json:"namespace"`
 	Compartment string `json:"compartment_id"`
+	// Lifetime of the pre-authenticated request, 24 hours if 0
+	PARExpiryHours int `json:"par_expiry_hours,omitempty"`
+	// Prefix of the name of the uploaded object
+	ObjectNamePrefix string `json:"object_name_prefix,omitempty"`
+	// Storage tier of the object, the default tier of the bucket if empty
+	StorageTier string `json:"storage_tier,omitempty"`
 }
 
 func (OCIObjectStorageTargetOptions) isTargetOptions() {}
 
 type OCIObjectStorageTargetResultOptions struct {
 	URL string `json:"url"`
+	// Native path of the object, /n/<namespace>/b/<bucket>/o/<object>
+	ObjectPath string `json:"object_path,omitempty"`
 }
 
 func (OCIObjectStorageTargetResultOptions) isTargetResultOptions() {}
@@ -0,0 +1,35 @@
+package target
+
+const TargetNameORAS TargetName = "org.osbuild.oras"
+
+// ORASTargetOptions describe an OCI artifact the image is pushed as to the
+// registry, the reference is the image name of the target.
+type ORASTargetOptions struct {
+	// Type of the artifact set in the manifest
+	ArtifactType string `json:"artifact_type"`
+	// Media type of the layer holding the image
+	MediaType   string            `json:"media_type"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	TlsVerify *bool `json:"tls_verify,omitempty"`
+}
+
+func (ORASTargetOptions) isTargetOptions() {}
+
+func NewORASTarget(options *ORASTargetOptions) *Target {
+	return newTarget(TargetNameORAS, options)
+}
+
+type ORASTargetResultOptions struct {
+	URL    string `json:"url"`
+	Digest string `json:"digest"`
+}
+
+func (ORASTargetResultOptions) isTargetResultOptions() {}
+
+func NewORASTargetResult(options *ORASTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameORAS, options, artifact)
+}
@@ -31,3 +31,36 @@ func (PulpOSTreeTargetResultOptions) isTargetResultOptions() {}
 func NewPulpOSTreeTargetResult(options *PulpOSTreeTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
 	return newTargetResult(TargetNamePulpOSTree, options, artifact)
 }
+
+const TargetNamePulpFile TargetName = "org.osbuild.pulp.file"
+
+type PulpFileTargetOptions struct {
+	// ServerAddress for the pulp instance
+	ServerAddress string `json:"server_address,omitempty"`
+
+	// Repository to add the image to as a file
+	Repository string `json:"repository"`
+
+	// BasePath for distributing the repository (if not distributed yet)
+	BasePath string `json:"basepath,omitempty"`
+
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+func (PulpFileTargetOptions) isTargetOptions() {}
+
+func NewPulpFileTarget(options *PulpFileTargetOptions) *Target {
+	return newTarget(TargetNamePulpFile, options)
+}
+
+type PulpFileTargetResultOptions struct {
+	// URL of the image in the distribution of the repository
+	FileURL string `json:"file_url"`
+}
+
+func (PulpFileTargetResultOptions) isTargetResultOptions() {}
+
+func NewPulpFileTargetResult(options *PulpFileTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNamePulpFile, options, artifact)
+}
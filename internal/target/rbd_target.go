@@ -0,0 +1,27 @@
+package target
+
+const TargetNameRBD TargetName = "org.osbuild.rbd"
+
+// RBDTargetOptions describe where the image is imported to in the Ceph
+// cluster of the worker, the image name of the target is the RBD image name.
+type RBDTargetOptions struct {
+	Pool      string `json:"pool"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (RBDTargetOptions) isTargetOptions() {}
+
+func NewRBDTarget(options *RBDTargetOptions) *Target {
+	return newTarget(TargetNameRBD, options)
+}
+
+type RBDTargetResultOptions struct {
+	// <pool>[/<namespace>]/<image name>
+	ImageSpec string `json:"image_spec"`
+}
+
+func (RBDTargetResultOptions) isTargetResultOptions() {}
+
+func NewRBDTargetResult(options *RBDTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameRBD, options, artifact)
+}
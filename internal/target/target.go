@@ -18,6 +18,26 @@ type OsbuildArtifact struct {
 	ExportFilename string `json:"export_filename"`
 	// Name of the osbuild pipeline, which should be exported for this target
 	ExportName string `json:"export_name"`
+	// Provider of the Vagrant box the exported image is packaged into before
+	// it's uploaded, the export filename is replaced with the name of the
+	// box then
+	VagrantProvider string `json:"vagrant_provider,omitempty"`
+	// Options applied to the archive of the root file system of WSL images
+	// before it's uploaded
+	WSL *WSLOptions `json:"wsl,omitempty"`
+}
+
+// WSLOptions configure WSL in the root file system of the image.
+type WSLOptions struct {
+	// User logged in as, the WSL default if empty
+	DefaultUser string `json:"default_user,omitempty"`
+	Systemd     bool   `json:"systemd"`
+	// Appended to the generated /etc/wsl.conf
+	Conf string `json:"conf,omitempty"`
+	// Package the archive as a .wsl distribution bundle, the export
+	// filename is replaced with the name of the bundle then
+	Bundle           bool   `json:"bundle,omitempty"`
+	DistributionName string `json:"distribution_name,omitempty"`
 }
 
 type Target struct {
@@ -95,6 +115,24 @@ func (target *Target) UnmarshalJSON(data []byte) error {
 		options = new(WorkerServerTargetOptions)
 	case TargetNamePulpOSTree:
 		options = new(PulpOSTreeTargetOptions)
+	case TargetNamePulpFile:
+		options = new(PulpFileTargetOptions)
+	case TargetNameORAS:
+		options = new(ORASTargetOptions)
+	case TargetNameLibvirt:
+		options = new(LibvirtTargetOptions)
+	case TargetNameRBD:
+		options = new(RBDTargetOptions)
+	case TargetNameMock:
+		options = new(MockTargetOptions)
+	case TargetNameHetzner:
+		options = new(HetznerTargetOptions)
+	case TargetNameHTTP:
+		options = new(HTTPTargetOptions)
+	case TargetNameBareMetal:
+		options = new(BareMetalTargetOptions)
+	case TargetNameVagrantCloud:
+		options = new(VagrantCloudTargetOptions)
 	default:
 		return fmt.Errorf("unexpected target name: %s", rawTarget.Name)
 	}
@@ -284,6 +322,42 @@ func (target Target) MarshalJSON() ([]byte, error) {
 			// added after incompatibility change
 			rawOptions, err = json.Marshal(target.Options)
 
+		case *MockTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
+		case *HetznerTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
+		case *HTTPTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
+		case *PulpFileTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
+		case *ORASTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
+		case *LibvirtTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
+		case *RBDTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
+		case *BareMetalTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
+		case *VagrantCloudTargetOptions:
+			// added after incompatibility change
+			rawOptions, err = json.Marshal(target.Options)
+
 		default:
 			return nil, fmt.Errorf("unexpected target options type: %t", t)
 		}
@@ -11,8 +11,11 @@ type TargetResult struct {
 	Name    TargetName          `json:"name"`
 	Options TargetResultOptions `json:"options,omitempty"`
 	// Configuration used to produce osbuild artifact specific to this target
-	OsbuildArtifact *OsbuildArtifact    `json:"osbuild_artifact,omitempty"`
-	TargetError     *clienterrors.Error `json:"target_error,omitempty"`
+	OsbuildArtifact *OsbuildArtifact `json:"osbuild_artifact,omitempty"`
+	// SHA256 checksum of the exported artifact, in the form of
+	// "sha256:<hex digest>"
+	ArtifactChecksum string              `json:"artifact_checksum,omitempty"`
+	TargetError      *clienterrors.Error `json:"target_error,omitempty"`
 }
 
 func newTargetResult(name TargetName, options TargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
@@ -28,10 +31,11 @@ type TargetResultOptions interface {
 }
 
 type rawTargetResult struct {
-	Name            TargetName          `json:"name"`
-	Options         json.RawMessage     `json:"options,omitempty"`
-	OsbuildArtifact *OsbuildArtifact    `json:"osbuild_artifact,omitempty"`
-	TargetError     *clienterrors.Error `json:"target_error,omitempty"`
+	Name             TargetName          `json:"name"`
+	Options          json.RawMessage     `json:"options,omitempty"`
+	OsbuildArtifact  *OsbuildArtifact    `json:"osbuild_artifact,omitempty"`
+	ArtifactChecksum string              `json:"artifact_checksum,omitempty"`
+	TargetError      *clienterrors.Error `json:"target_error,omitempty"`
 }
 
 func (targetResult *TargetResult) UnmarshalJSON(data []byte) error {
@@ -53,6 +57,7 @@ func (targetResult *TargetResult) UnmarshalJSON(data []byte) error {
 	targetResult.Name = rawTR.Name
 	targetResult.Options = options
 	targetResult.OsbuildArtifact = rawTR.OsbuildArtifact
+	targetResult.ArtifactChecksum = rawTR.ArtifactChecksum
 	targetResult.TargetError = rawTR.TargetError
 	return nil
 }
@@ -78,6 +83,26 @@ func UnmarshalTargetResultOptions(trName TargetName, rawOptions json.RawMessage)
 		options = new(ContainerTargetResultOptions)
 	case TargetNamePulpOSTree:
 		options = new(PulpOSTreeTargetResultOptions)
+	case TargetNamePulpFile:
+		options = new(PulpFileTargetResultOptions)
+	case TargetNameORAS:
+		options = new(ORASTargetResultOptions)
+	case TargetNameLibvirt:
+		options = new(LibvirtTargetResultOptions)
+	case TargetNameRBD:
+		options = new(RBDTargetResultOptions)
+	case TargetNameMock:
+		options = new(MockTargetResultOptions)
+	case TargetNameHetzner:
+		options = new(HetznerTargetResultOptions)
+	case TargetNameHTTP:
+		options = new(HTTPTargetResultOptions)
+	case TargetNameVMWare:
+		options = new(VMWareTargetResultOptions)
+	case TargetNameBareMetal:
+		options = new(BareMetalTargetResultOptions)
+	case TargetNameVagrantCloud:
+		options = new(VagrantCloudTargetResultOptions)
 	default:
 		return nil, fmt.Errorf("unexpected target result name: %s", trName)
 	}
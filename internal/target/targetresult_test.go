@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 	"github.com/stretchr/testify/assert"
 )
@@ -29,6 +30,32 @@ func TestTargetResultUnmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.aws","options":{"ami":"ami-123456789","region":"eu"},"artifact_checksum":"sha256:b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameAWS,
+				Options: &AWSTargetResultOptions{
+					Ami:    "ami-123456789",
+					Region: "eu",
+				},
+				ArtifactChecksum: "sha256:b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.aws","options":{"ami":"ami-123456789","region":"eu","bootMode":"uefi","tags":{"team":"images"},"imdsSupport":"v2.0","enaSupport":true,"sriovNetSupport":true}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameAWS,
+				Options: &AWSTargetResultOptions{
+					Ami:             "ami-123456789",
+					Region:          "eu",
+					BootMode:        common.ToPtr("uefi"),
+					Tags:            map[string]string{"team": "images"},
+					IMDSSupport:     common.ToPtr("v2.0"),
+					ENASupport:      common.ToPtr(true),
+					SriovNetSupport: true,
+				},
+			},
+		},
 		{
 			resultJSON: []byte(`{"name":"org.osbuild.aws.s3","options":{"url":"https://example.org/image"}}`),
 			expectedResult: &TargetResult{
@@ -57,6 +84,58 @@ func TestTargetResultUnmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.mock","options":{"url":"mock://image"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameMock,
+				Options: &MockTargetResultOptions{
+					URL: "mock://image",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.hetzner","options":{"image_id":1234}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameHetzner,
+				Options: &HetznerTargetResultOptions{
+					ImageID: 1234,
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.http","options":{"url":"https://nexus.example.com/repository/images/image.qcow2"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameHTTP,
+				Options: &HTTPTargetResultOptions{
+					URL: "https://nexus.example.com/repository/images/image.qcow2",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.baremetal","options":{"image_url":"https://images.example.com/metal/disk.raw","image_checksum":"cafe","kernel_url":"https://images.example.com/metal/vmlinuz","initrd_url":"https://images.example.com/metal/initrd.img","ipxe_script_url":"https://images.example.com/metal/boot.ipxe"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameBareMetal,
+				Options: &BareMetalTargetResultOptions{
+					ImageURL:      "https://images.example.com/metal/disk.raw",
+					ImageChecksum: "cafe",
+					KernelURL:     "https://images.example.com/metal/vmlinuz",
+					InitrdURL:     "https://images.example.com/metal/initrd.img",
+					IPXEScriptURL: "https://images.example.com/metal/boot.ipxe",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.vagrant-cloud","options":{"box":"osbuild/fedora","version":"39.1","provider":"libvirt","released":true}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameVagrantCloud,
+				Options: &VagrantCloudTargetResultOptions{
+					Box:      "osbuild/fedora",
+					Version:  "39.1",
+					Provider: "libvirt",
+					Released: true,
+				},
+			},
+		},
 		{
 			resultJSON: []byte(`{"name":"org.osbuild.koji","options":{"image":{"checksum_type":"md5","checksum":"hash","filename":"image.raw","size":123456}}}`),
 			expectedResult: &TargetResult{
@@ -81,12 +160,59 @@ func TestTargetResultUnmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.pulp.file","options":{"file_url":"https://pulp.example.com/pulp/content/images/disk.qcow2"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNamePulpFile,
+				Options: &PulpFileTargetResultOptions{
+					FileURL: "https://pulp.example.com/pulp/content/images/disk.qcow2",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.oras","options":{"url":"quay.io/myaccount/disk:9.3","digest":"sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameORAS,
+				Options: &ORASTargetResultOptions{
+					URL:    "quay.io/myaccount/disk:9.3",
+					Digest: "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.libvirt","options":{"volume_path":"/var/lib/libvirt/images/disk.qcow2","domain_name":"test"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameLibvirt,
+				Options: &LibvirtTargetResultOptions{
+					VolumePath: "/var/lib/libvirt/images/disk.qcow2",
+					DomainName: "test",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.rbd","options":{"image_spec":"images/dev/rhel-9"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameRBD,
+				Options: &RBDTargetResultOptions{
+					ImageSpec: "images/dev/rhel-9",
+				},
+			},
+		},
 		{
 			resultJSON: []byte(`{"name":"org.osbuild.vmware"}`),
 			expectedResult: &TargetResult{
 				Name: TargetNameVMWare,
 			},
 		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.vmware","options":{"content_library_item_id":"b2a4c1d6-5e5f-4a4e-9c7b-2f1d5c7e8a90"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameVMWare,
+				Options: &VMWareTargetResultOptions{
+					ContentLibraryItemID: "b2a4c1d6-5e5f-4a4e-9c7b-2f1d5c7e8a90",
+				},
+			},
+		},
 		// target results with error without options
 		{
 			resultJSON: []byte(`{"name":"org.osbuild.aws","target_error":{"id":11,"reason":"failed to uplad image","details":"detail"}}`),
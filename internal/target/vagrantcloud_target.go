@@ -0,0 +1,36 @@
+package target
+
+const TargetNameVagrantCloud TargetName = "org.osbuild.vagrant-cloud"
+
+// VagrantCloudTargetOptions describe the version of a box on a Vagrant Cloud
+// compatible server the box is uploaded to. The provider of the box is the
+// one it was packaged for, see OsbuildArtifact.VagrantProvider.
+type VagrantCloudTargetOptions struct {
+	// URL of the server, the public Vagrant Cloud if empty
+	URL   string `json:"url,omitempty"`
+	Token string `json:"token"`
+	// Name of the box, user/name
+	Box     string `json:"box"`
+	Version string `json:"version"`
+	// Release the version once the box is uploaded
+	Release bool `json:"release,omitempty"`
+}
+
+func (VagrantCloudTargetOptions) isTargetOptions() {}
+
+func NewVagrantCloudTarget(options *VagrantCloudTargetOptions) *Target {
+	return newTarget(TargetNameVagrantCloud, options)
+}
+
+type VagrantCloudTargetResultOptions struct {
+	Box      string `json:"box"`
+	Version  string `json:"version"`
+	Provider string `json:"provider"`
+	Released bool   `json:"released,omitempty"`
+}
+
+func (VagrantCloudTargetResultOptions) isTargetResultOptions() {}
+
+func NewVagrantCloudTargetResult(options *VagrantCloudTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameVagrantCloud, options, artifact)
+}
@@ -10,6 +10,9 @@ type VMWareTargetOptions struct {
 	Cluster    string `json:"cluster"`
 	Datastore  string `json:"datastore"`
 	Folder     string `json:"folder"`
+	// Name of the content library the image is uploaded to as a new item
+	// instead of importing it to the datastore
+	ContentLibrary string `json:"content_library,omitempty"`
 }
 
 func (VMWareTargetOptions) isTargetOptions() {}
@@ -18,6 +21,13 @@ func NewVMWareTarget(options *VMWareTargetOptions) *Target {
 	return newTarget(TargetNameVMWare, options)
 }
 
-func NewVMWareTargetResult(artifact *OsbuildArtifact) *TargetResult {
-	return newTargetResult(TargetNameVMWare, nil, artifact)
+type VMWareTargetResultOptions struct {
+	// Only set if the image was uploaded to a content library
+	ContentLibraryItemID string `json:"content_library_item_id,omitempty"`
+}
+
+func (VMWareTargetResultOptions) isTargetResultOptions() {}
+
+func NewVMWareTargetResult(options *VMWareTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameVMWare, options, artifact)
 }
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/compute/mgmt/compute"
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/resources/mgmt/resources"
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/storage/mgmt/storage"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 )
 
@@ -23,7 +25,7 @@ func NewClient(credentials Credentials, tenantID string) (*Client, error) {
 	credentialsConfig := auth.NewClientCredentialsConfig(credentials.clientID, credentials.clientSecret, tenantID)
 	authorizer, err := credentialsConfig.Authorizer()
 	if err != nil {
-		return nil, fmt.Errorf("creating an azure authorizer failed: %v", err)
+		return nil, fmt.Errorf("creating an azure authorizer failed: %w", err)
 	}
 
 	return &Client{
@@ -48,7 +50,7 @@ func (ac Client) GetResourceNameByTag(ctx context.Context, subscriptionID, resou
 	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", tag.Name, tag.Value)
 	result, err := c.ListByResourceGroup(ctx, resourceGroup, filter, "", nil)
 	if err != nil {
-		return "", fmt.Errorf("listing resources failed: %v", err)
+		return "", fmt.Errorf("listing resources failed: %w", err)
 	}
 
 	if len(result.Values()) < 1 {
@@ -65,7 +67,7 @@ func (ac Client) GetResourceGroupLocation(ctx context.Context, subscriptionID, r
 
 	group, err := c.Get(ctx, resourceGroup)
 	if err != nil {
-		return "", fmt.Errorf("retrieving resource group failed: %v", err)
+		return "", fmt.Errorf("retrieving resource group failed: %w", err)
 	}
 
 	return *group.Location, nil
@@ -84,7 +86,7 @@ func (ac Client) CreateStorageAccount(ctx context.Context, subscriptionID, resou
 	if location == "" {
 		location, err = ac.GetResourceGroupLocation(ctx, subscriptionID, resourceGroup)
 		if err != nil {
-			return fmt.Errorf("retrieving resource group location failed: %v", err)
+			return fmt.Errorf("retrieving resource group location failed: %w", err)
 		}
 	}
 
@@ -99,17 +101,17 @@ func (ac Client) CreateStorageAccount(ctx context.Context, subscriptionID, resou
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("sending the create storage account request failed: %v", err)
+		return fmt.Errorf("sending the create storage account request failed: %w", err)
 	}
 
 	err = result.WaitForCompletionRef(ctx, c.Client)
 	if err != nil {
-		return fmt.Errorf("waiting for the create storage account request failed: %v", err)
+		return fmt.Errorf("waiting for the create storage account request failed: %w", err)
 	}
 
 	_, err = result.Result(c)
 	if err != nil {
-		return fmt.Errorf("create storage account request failed: %v", err)
+		return fmt.Errorf("create storage account request failed: %w", err)
 	}
 
 	return nil
@@ -124,7 +126,7 @@ func (ac Client) GetStorageAccountKey(ctx context.Context, subscriptionID, resou
 
 	keys, err := c.ListKeys(ctx, resourceGroup, storageAccount)
 	if err != nil {
-		return "", fmt.Errorf("retrieving keys for a storage account failed: %v", err)
+		return "", fmt.Errorf("retrieving keys for a storage account failed: %w", err)
 	}
 
 	if len(*keys.Keys) == 0 {
@@ -134,10 +136,45 @@ func (ac Client) GetStorageAccountKey(ctx context.Context, subscriptionID, resou
 	return *(*keys.Keys)[0].Value, nil
 }
 
-// RegisterImage creates a generalized V1 Linux image from a given blob.
+// Hyper-V generations of images, V1 boots with BIOS and V2 with UEFI
+const (
+	HyperVGenerationV1 = "V1"
+	HyperVGenerationV2 = "V2"
+)
+
+// The vendored compute API predates the Hyper-V generation of images, they
+// are created with a request prepared the same way the generated clients do.
+const imageAPIVersion = "2022-03-01"
+
+type imageBody struct {
+	Location   string `json:"location"`
+	Properties struct {
+		HyperVGeneration string `json:"hyperVGeneration,omitempty"`
+		StorageProfile   struct {
+			OsDisk struct {
+				OsType  compute.OperatingSystemTypes      `json:"osType"`
+				BlobURI string                            `json:"blobUri"`
+				OsState compute.OperatingSystemStateTypes `json:"osState"`
+			} `json:"osDisk"`
+		} `json:"storageProfile"`
+	} `json:"properties"`
+}
+
+func newImageBody(blobURI, location, hyperVGeneration string) imageBody {
+	var body imageBody
+	body.Location = location
+	body.Properties.HyperVGeneration = hyperVGeneration
+	body.Properties.StorageProfile.OsDisk.OsType = compute.Linux
+	body.Properties.StorageProfile.OsDisk.BlobURI = blobURI
+	body.Properties.StorageProfile.OsDisk.OsState = compute.Generalized
+	return body
+}
+
+// RegisterImage creates a generalized Linux image from a given blob.
 // The location is optional and if not provided, it is determined
-// from the resource group.
-func (ac Client) RegisterImage(ctx context.Context, subscriptionID, resourceGroup, storageAccount, storageContainer, blobName, imageName, location string) error {
+// from the resource group. The Hyper-V generation of the image is V1 if
+// hyperVGeneration is empty, it can't be changed once the image exists.
+func (ac Client) RegisterImage(ctx context.Context, subscriptionID, resourceGroup, storageAccount, storageContainer, blobName, imageName, location, hyperVGeneration string) error {
 	c := compute.NewImagesClient(subscriptionID)
 	c.Authorizer = ac.authorizer
 
@@ -147,36 +184,44 @@ func (ac Client) RegisterImage(ctx context.Context, subscriptionID, resourceGrou
 	if location == "" {
 		location, err = ac.GetResourceGroupLocation(ctx, subscriptionID, resourceGroup)
 		if err != nil {
-			return fmt.Errorf("retrieving resource group location failed: %v", err)
+			return fmt.Errorf("retrieving resource group location failed: %w", err)
 		}
 	}
 
-	imageFuture, err := c.CreateOrUpdate(ctx, resourceGroup, imageName, compute.Image{
-		Response: autorest.Response{},
-		ImageProperties: &compute.ImageProperties{
-			SourceVirtualMachine: nil,
-			StorageProfile: &compute.ImageStorageProfile{
-				OsDisk: &compute.ImageOSDisk{
-					OsType:  compute.Linux,
-					BlobURI: &blobURI,
-					OsState: compute.Generalized,
-				},
-			},
-		},
-		Location: &location,
-	})
+	req, err := autorest.CreatePreparer(
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPut(),
+		autorest.WithBaseURL(c.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/images/{imageName}", map[string]interface{}{
+			"subscriptionId":    autorest.Encode("path", subscriptionID),
+			"resourceGroupName": autorest.Encode("path", resourceGroup),
+			"imageName":         autorest.Encode("path", imageName),
+		}),
+		autorest.WithJSON(newImageBody(blobURI, location, hyperVGeneration)),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": imageAPIVersion,
+		}),
+	).Prepare((&http.Request{}).WithContext(ctx))
 	if err != nil {
-		return fmt.Errorf("sending the create image request failed: %v", err)
+		return fmt.Errorf("preparing the create image request failed: %w", err)
 	}
 
-	err = imageFuture.WaitForCompletionRef(ctx, c.Client)
+	resp, err := c.Send(req, azure.DoRetryWithRegistration(c.Client))
 	if err != nil {
-		return fmt.Errorf("waiting for the create image request failed: %v", err)
+		return fmt.Errorf("sending the create image request failed: %w", err)
+	}
+	err = autorest.Respond(resp, azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated))
+	if err != nil {
+		return fmt.Errorf("create image request failed: %w", err)
 	}
 
-	_, err = imageFuture.Result(c)
+	future, err := azure.NewFutureFromResponse(resp)
+	if err != nil {
+		return fmt.Errorf("create image request failed: %w", err)
+	}
+	err = future.WaitForCompletionRef(ctx, c.Client)
 	if err != nil {
-		return fmt.Errorf("create image request failed: %v", err)
+		return fmt.Errorf("waiting for the create image request failed: %w", err)
 	}
 
 	return nil
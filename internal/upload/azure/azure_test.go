@@ -0,0 +1,43 @@
+package azure
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageBody(t *testing.T) {
+	blobURI := "https://ib123.blob.core.windows.net/imagebuilder/toucan.vhd"
+
+	body, err := json.Marshal(newImageBody(blobURI, "westeurope", ""))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"location": "westeurope",
+		"properties": {
+			"storageProfile": {
+				"osDisk": {
+					"osType": "Linux",
+					"blobUri": "https://ib123.blob.core.windows.net/imagebuilder/toucan.vhd",
+					"osState": "Generalized"
+				}
+			}
+		}
+	}`, string(body))
+
+	body, err = json.Marshal(newImageBody(blobURI, "westeurope", HyperVGenerationV2))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"location": "westeurope",
+		"properties": {
+			"hyperVGeneration": "V2",
+			"storageProfile": {
+				"osDisk": {
+					"osType": "Linux",
+					"blobUri": "https://ib123.blob.core.windows.net/imagebuilder/toucan.vhd",
+					"osState": "Generalized"
+				}
+			}
+		}
+	}`, string(body))
+}
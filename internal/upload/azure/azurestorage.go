@@ -10,20 +10,25 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/url"
 	"os"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/pageblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/upload/resumable"
 )
 
 // StorageClient is a client for the Azure Storage API,
@@ -39,7 +44,7 @@ type StorageClient struct {
 func NewStorageClient(storageAccount, storageAccessKey string) (*StorageClient, error) {
 	credential, err := azblob.NewSharedKeyCredential(storageAccount, storageAccessKey)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create shared key credential: %v", err)
+		return nil, fmt.Errorf("cannot create shared key credential: %w", err)
 	}
 
 	return &StorageClient{
@@ -93,14 +98,14 @@ func (c StorageClient) UploadPageBlob(metadata BlobMetadata, fileName string, th
 	// Open the image file for reading
 	imageFile, err := os.Open(fileName)
 	if err != nil {
-		return fmt.Errorf("cannot open the image: %v", err)
+		return fmt.Errorf("cannot open the image: %w", err)
 	}
 	defer imageFile.Close()
 
 	// Stat image to get the file size
 	stat, err := imageFile.Stat()
 	if err != nil {
-		return fmt.Errorf("cannot stat the image: %v", err)
+		return fmt.Errorf("cannot stat the image: %w", err)
 	}
 
 	if stat.Size()%512 != 0 {
@@ -112,11 +117,11 @@ func (c StorageClient) UploadPageBlob(metadata BlobMetadata, fileName string, th
 	/* #nosec G401 */
 	imageFileHash := md5.New()
 	if _, err := io.Copy(imageFileHash, imageFile); err != nil {
-		return fmt.Errorf("cannot create md5 of the image: %v", err)
+		return fmt.Errorf("cannot create md5 of the image: %w", err)
 	}
 	// Move the cursor back to the start of the imageFile
 	if _, err := imageFile.Seek(0, 0); err != nil {
-		return fmt.Errorf("cannot seek the image: %v", err)
+		return fmt.Errorf("cannot seek the image: %w", err)
 	}
 
 	// Create page blob. Page blob is required for VM images
@@ -148,7 +153,7 @@ func (c StorageClient) UploadPageBlob(metadata BlobMetadata, fileName string, th
 			if err == io.EOF {
 				run = false
 			} else {
-				return fmt.Errorf("reading the image failed: %v", err)
+				return fmt.Errorf("reading the image failed: %w", err)
 			}
 		}
 		if n == 0 {
@@ -171,9 +176,9 @@ func (c StorageClient) UploadPageBlob(metadata BlobMetadata, fileName string, th
 				Offset: counter * PageBlobMaxUploadPagesBytes,
 				Count:  int64(n),
 			}
-			_, err := client.UploadPages(ctx, common.NopSeekCloser(bytes.NewReader(buffer[:n])), uploadRange, nil)
+			_, err := client.UploadPages(ctx, common.NopSeekCloser(bytes.NewReader(buffer[:n])), uploadRange, uploadPagesOptions(buffer[:n]))
 			if err != nil {
-				err = fmt.Errorf("uploading a page failed: %v", err)
+				err = fmt.Errorf("uploading a page failed: %w", err)
 				// Send the error to the error channel in a non-blocking way. If there is already an error, just discard this one
 				select {
 				case errorInGoroutine <- err:
@@ -242,12 +247,36 @@ func (c StorageClient) TagBlob(ctx context.Context, metadata BlobMetadata, tags
 
 	_, err = client.SetTags(ctx, tags, nil)
 	if err != nil {
-		return fmt.Errorf("cannot tag the blob: %v", err)
+		return fmt.Errorf("cannot tag the blob: %w", err)
 	}
 
 	return nil
 }
 
+// BlobURL returns the URL of the blob.
+func BlobURL(metadata BlobMetadata) string {
+	return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", metadata.StorageAccount, metadata.ContainerName, metadata.BlobName)
+}
+
+// BlobSASToken returns a SAS token granting read access to the blob until the
+// expiry, see https://learn.microsoft.com/en-us/azure/storage/common/storage-sas-overview
+func (c StorageClient) BlobSASToken(metadata BlobMetadata, expiry time.Time) (string, error) {
+	client, err := blob.NewClientWithSharedKeyCredential(BlobURL(metadata), c.credential, nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot create a blob client: %w", err)
+	}
+
+	sasURL, err := client.GetSASURL(sas.BlobPermissions{Read: true}, expiry, nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate a SAS token: %w", err)
+	}
+	u, err := url.Parse(sasURL)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse the SAS URL: %w", err)
+	}
+	return u.RawQuery, nil
+}
+
 // RandomStorageAccountName returns a randomly generated name that can be used
 // for a storage account. This means that it must use only alphanumeric
 // characters and its length must be 24 or lower.
@@ -267,3 +296,129 @@ func EnsureVHDExtension(s string) string {
 
 	return s + ".vhd"
 }
+
+// uploadPagesOptions sends the MD5 checksum of the pages along with them,
+// Azure rejects the pages if the data it received doesn't match. The MD5 of
+// the whole blob is only stored as a property, Azure doesn't verify it.
+func uploadPagesOptions(pages []byte) *pageblob.UploadPagesOptions {
+	// azure uses MD5 hashes
+	/* #nosec G401 */
+	sum := md5.Sum(pages)
+	return &pageblob.UploadPagesOptions{
+		TransactionalValidation: blob.TransferValidationTypeMD5(sum[:]),
+	}
+}
+
+// UploadPageBlobResumable uploads the image the way UploadPageBlob does, but
+// keeps the state of the upload in the store. An upload interrupted by a
+// restart continues with the parts which weren't uploaded yet, as long as
+// the blob still exists.
+func (c StorageClient) UploadPageBlobResumable(ctx context.Context, metadata BlobMetadata, fileName string, store *resumable.Store, options resumable.Options) error {
+	client, err := pageblob.NewClientWithSharedKeyCredential(BlobURL(metadata), c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create a pageblob client: %w", err)
+	}
+
+	imageFile, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("cannot open the image: %w", err)
+	}
+	defer imageFile.Close()
+	stat, err := imageFile.Stat()
+	if err != nil {
+		return fmt.Errorf("cannot stat the image: %w", err)
+	}
+	if stat.Size()%512 != 0 {
+		return errors.New("size for azure image must be aligned to 512 bytes")
+	}
+
+	// parts are uploaded in calls of PageBlobMaxUploadPagesBytes
+	if rem := options.PartSize % PageBlobMaxUploadPagesBytes; rem != 0 {
+		options.PartSize += PageBlobMaxUploadPagesBytes - rem
+	}
+	partSize := options.PartSizeFor(stat.Size(), math.MaxInt32)
+
+	stateKey := BlobURL(metadata)
+	state, err := store.Load(stateKey)
+	if err != nil {
+		return err
+	}
+	if state != nil && !state.Matches(stat.Size(), partSize) {
+		state = nil
+	}
+	// a blob left behind by an interrupted upload may contain data where the
+	// image has zeros now, so its zero pages are cleared instead of skipped
+	reused := false
+	if state != nil {
+		props, err := client.GetProperties(ctx, nil)
+		if err == nil && props.ContentLength != nil && *props.ContentLength == stat.Size() {
+			reused = true
+		} else {
+			state = nil
+		}
+	}
+
+	/* #nosec G401 */
+	imageFileHash := md5.New()
+	if _, err := io.Copy(imageFileHash, imageFile); err != nil {
+		return fmt.Errorf("cannot create md5 of the image: %w", err)
+	}
+
+	if state == nil {
+		_, err = client.Create(ctx, stat.Size(), &pageblob.CreateOptions{
+			HTTPHeaders: &blob.HTTPHeaders{
+				BlobContentMD5: imageFileHash.Sum(nil),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("cannot create a new page blob: %w", err)
+		}
+		state = resumable.NewState(stat.Size(), partSize)
+		err = store.Save(stateKey, state)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = resumable.UploadParts(ctx, store, stateKey, state, imageFile, options, func(ctx context.Context, number int, offset int64, data []byte) (string, error) {
+		for start := 0; start < len(data); start += PageBlobMaxUploadPagesBytes {
+			end := start + PageBlobMaxUploadPagesBytes
+			if end > len(data) {
+				end = len(data)
+			}
+			uploadRange := blob.HTTPRange{
+				Offset: offset + int64(start),
+				Count:  int64(end - start),
+			}
+			if allZerosSlice(data[start:end]) {
+				if reused {
+					_, err := client.ClearPages(ctx, uploadRange, nil)
+					if err != nil {
+						return "", fmt.Errorf("clearing pages failed: %w", err)
+					}
+				}
+				continue
+			}
+			_, err := client.UploadPages(ctx, common.NopSeekCloser(bytes.NewReader(data[start:end])), uploadRange, uploadPagesOptions(data[start:end]))
+			if err != nil {
+				return "", fmt.Errorf("uploading a page failed: %w", err)
+			}
+		}
+		return "", nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if reused {
+		_, err = client.SetHTTPHeaders(ctx, blob.HTTPHeaders{BlobContentMD5: imageFileHash.Sum(nil)}, nil)
+		if err != nil {
+			return fmt.Errorf("cannot set the md5 of the page blob: %w", err)
+		}
+	}
+	err = store.Delete(stateKey)
+	if err != nil {
+		logrus.Warn(err)
+	}
+	return nil
+}
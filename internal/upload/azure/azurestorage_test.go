@@ -1,9 +1,13 @@
 package azure
 
 import (
+	"encoding/hex"
+	"net/url"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,3 +35,31 @@ func TestEnsureVHDExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestBlobSASToken(t *testing.T) {
+	// the key must be base64 encoded
+	c, err := NewStorageClient("ibstorage", "a2V5")
+	require.NoError(t, err)
+	metadata := BlobMetadata{
+		StorageAccount: "ibstorage",
+		ContainerName:  "imagebuilder",
+		BlobName:       "image.vhd",
+	}
+	require.Equal(t, "https://ibstorage.blob.core.windows.net/imagebuilder/image.vhd", BlobURL(metadata))
+
+	token, err := c.BlobSASToken(metadata, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	query, err := url.ParseQuery(token)
+	require.NoError(t, err)
+	require.Equal(t, "r", query.Get("sp"))
+	require.Equal(t, "b", query.Get("sr"))
+	require.Equal(t, "2030-01-02T03:04:05Z", query.Get("se"))
+	require.NotEmpty(t, query.Get("sig"))
+}
+
+func TestUploadPagesOptions(t *testing.T) {
+	sum, err := hex.DecodeString("5eb63bbbe01eeed093cb22bb8f5acdc3")
+	require.NoError(t, err)
+	options := uploadPagesOptions([]byte("hello world"))
+	require.Equal(t, blob.TransferValidationTypeMD5(sum), options.TransactionalValidation)
+}
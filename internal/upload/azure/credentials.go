@@ -33,3 +33,11 @@ func ParseAzureCredentialsFile(filename string) (*Credentials, error) {
 		clientSecret: creds.ClientSecret,
 	}, nil
 }
+
+// NewCredentials returns credentials for the given client ID and secret.
+func NewCredentials(clientID, clientSecret string) *Credentials {
+	return &Credentials{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
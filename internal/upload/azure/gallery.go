@@ -0,0 +1,220 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/compute/mgmt/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+// The vendored compute API predates Azure Compute Galleries, the gallery
+// image versions are created with a request prepared the same way the
+// generated clients do.
+const galleryAPIVersion = "2022-03-03"
+
+// GalleryImageVersion describes an image version in an Azure Compute
+// Gallery, see
+// https://learn.microsoft.com/en-us/rest/api/compute/gallery-image-versions/create-or-update
+type GalleryImageVersion struct {
+	Gallery         string
+	ImageDefinition string
+	Version         string
+	// number of replicas and storage account type of the regions which
+	// don't set their own
+	ReplicaCount       int
+	StorageAccountType string
+	TargetRegions      []GalleryTargetRegion
+}
+
+type GalleryTargetRegion struct {
+	Name               string `json:"name"`
+	ReplicaCount       int    `json:"regionalReplicaCount,omitempty"`
+	StorageAccountType string `json:"storageAccountType,omitempty"`
+}
+
+type galleryImageVersionBody struct {
+	Location   string `json:"location"`
+	Properties struct {
+		PublishingProfile struct {
+			TargetRegions      []GalleryTargetRegion `json:"targetRegions"`
+			ReplicaCount       int                   `json:"replicaCount,omitempty"`
+			StorageAccountType string                `json:"storageAccountType,omitempty"`
+		} `json:"publishingProfile"`
+		StorageProfile struct {
+			Source struct {
+				ID string `json:"id"`
+			} `json:"source"`
+		} `json:"storageProfile"`
+	} `json:"properties"`
+}
+
+// body returns the body of the request creating the image version from the
+// image. The location of the image is always one of the target regions, it's
+// where the source of the version is stored.
+func (v GalleryImageVersion) body(imageID, location string) galleryImageVersionBody {
+	var body galleryImageVersionBody
+	body.Location = location
+	body.Properties.PublishingProfile.ReplicaCount = v.ReplicaCount
+	body.Properties.PublishingProfile.StorageAccountType = v.StorageAccountType
+	body.Properties.StorageProfile.Source.ID = imageID
+
+	hasLocation := false
+	for _, r := range v.TargetRegions {
+		if r.Name == location {
+			hasLocation = true
+		}
+		body.Properties.PublishingProfile.TargetRegions = append(body.Properties.PublishingProfile.TargetRegions, r)
+	}
+	if !hasLocation {
+		body.Properties.PublishingProfile.TargetRegions = append(body.Properties.PublishingProfile.TargetRegions, GalleryTargetRegion{
+			Name: location,
+		})
+	}
+	return body
+}
+
+// CreateGalleryImageVersion publishes a registered image as a new version of
+// an image definition in a gallery of the same resource group and returns
+// the ID of the version. It waits until the version is replicated to all
+// target regions.
+// The location is optional and if not provided, it is determined
+// from the resource group.
+func (ac Client) CreateGalleryImageVersion(ctx context.Context, subscriptionID, resourceGroup, imageName, location string, version GalleryImageVersion) (string, error) {
+	c := compute.NewImagesClient(subscriptionID)
+	c.Authorizer = ac.authorizer
+
+	var err error
+	if location == "" {
+		location, err = ac.GetResourceGroupLocation(ctx, subscriptionID, resourceGroup)
+		if err != nil {
+			return "", fmt.Errorf("retrieving resource group location failed: %w", err)
+		}
+	}
+
+	imageID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/images/%s", subscriptionID, resourceGroup, imageName)
+	versionID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/galleries/%s/images/%s/versions/%s",
+		subscriptionID, resourceGroup, version.Gallery, version.ImageDefinition, version.Version)
+
+	req, err := autorest.CreatePreparer(
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPut(),
+		autorest.WithBaseURL(c.BaseURI),
+		autorest.WithPath(versionID),
+		autorest.WithJSON(version.body(imageID, location)),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": galleryAPIVersion,
+		}),
+	).Prepare((&http.Request{}).WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("preparing the create gallery image version request failed: %w", err)
+	}
+
+	resp, err := c.Send(req, azure.DoRetryWithRegistration(c.Client))
+	if err != nil {
+		return "", fmt.Errorf("sending the create gallery image version request failed: %w", err)
+	}
+	err = autorest.Respond(resp, azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated))
+	if err != nil {
+		return "", fmt.Errorf("create gallery image version request failed: %w", err)
+	}
+
+	future, err := azure.NewFutureFromResponse(resp)
+	if err != nil {
+		return "", fmt.Errorf("create gallery image version request failed: %w", err)
+	}
+	err = future.WaitForCompletionRef(ctx, c.Client)
+	if err != nil {
+		return "", fmt.Errorf("waiting for the create gallery image version request failed: %w", err)
+	}
+
+	return versionID, nil
+}
+
+// Security types of virtual machines
+const (
+	SecurityTypeStandard       = "Standard"
+	SecurityTypeTrustedLaunch  = "TrustedLaunch"
+	SecurityTypeConfidentialVM = "ConfidentialVM"
+)
+
+// GalleryImageDefinition are the properties of an image definition in an
+// Azure Compute Gallery its versions inherit, see
+// https://learn.microsoft.com/en-us/rest/api/compute/gallery-images/get
+type GalleryImageDefinition struct {
+	HyperVGeneration string
+	// value of the SecurityType feature, empty for standard VMs only
+	SecurityType string
+}
+
+type galleryImageDefinitionBody struct {
+	Properties struct {
+		HyperVGeneration string `json:"hyperVGeneration"`
+		Features         []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"features"`
+	} `json:"properties"`
+}
+
+// SupportsSecurityType returns whether VMs of the security type can be
+// created from versions of the image definition.
+func (d GalleryImageDefinition) SupportsSecurityType(securityType string) bool {
+	switch securityType {
+	case "", SecurityTypeStandard:
+		return d.SecurityType != SecurityTypeTrustedLaunch && d.SecurityType != SecurityTypeConfidentialVM
+	case SecurityTypeTrustedLaunch:
+		return d.SecurityType == "TrustedLaunch" || d.SecurityType == "TrustedLaunchSupported" || d.SecurityType == "TrustedLaunchAndConfidentialVmSupported"
+	case SecurityTypeConfidentialVM:
+		return d.SecurityType == "ConfidentialVM" || d.SecurityType == "ConfidentialVMSupported" || d.SecurityType == "TrustedLaunchAndConfidentialVmSupported"
+	}
+	return false
+}
+
+// GetGalleryImageDefinition returns the properties of an image definition in
+// a gallery of the resource group.
+func (ac Client) GetGalleryImageDefinition(ctx context.Context, subscriptionID, resourceGroup, gallery, imageDefinition string) (*GalleryImageDefinition, error) {
+	c := compute.NewImagesClient(subscriptionID)
+	c.Authorizer = ac.authorizer
+
+	definitionID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/galleries/%s/images/%s",
+		subscriptionID, resourceGroup, gallery, imageDefinition)
+
+	req, err := autorest.CreatePreparer(
+		autorest.AsGet(),
+		autorest.WithBaseURL(c.BaseURI),
+		autorest.WithPath(definitionID),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": galleryAPIVersion,
+		}),
+	).Prepare((&http.Request{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("preparing the get gallery image definition request failed: %w", err)
+	}
+
+	resp, err := c.Send(req, azure.DoRetryWithRegistration(c.Client))
+	if err != nil {
+		return nil, fmt.Errorf("sending the get gallery image definition request failed: %w", err)
+	}
+	var body galleryImageDefinitionBody
+	err = autorest.Respond(resp,
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&body),
+		autorest.ByClosing(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get gallery image definition request failed: %w", err)
+	}
+
+	definition := &GalleryImageDefinition{
+		HyperVGeneration: body.Properties.HyperVGeneration,
+	}
+	for _, feature := range body.Properties.Features {
+		if feature.Name == "SecurityType" {
+			definition.SecurityType = feature.Value
+		}
+	}
+	return definition, nil
+}
@@ -0,0 +1,72 @@
+package azure
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGalleryImageVersionBody(t *testing.T) {
+	imageID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/images/toucan"
+	version := GalleryImageVersion{
+		Gallery:            "birds",
+		ImageDefinition:    "toucan",
+		Version:            "1.0.0",
+		ReplicaCount:       2,
+		StorageAccountType: "Standard_ZRS",
+		TargetRegions: []GalleryTargetRegion{
+			{Name: "northeurope", ReplicaCount: 3, StorageAccountType: "Premium_LRS"},
+		},
+	}
+
+	body, err := json.Marshal(version.body(imageID, "westeurope"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"location": "westeurope",
+		"properties": {
+			"publishingProfile": {
+				"targetRegions": [
+					{"name": "northeurope", "regionalReplicaCount": 3, "storageAccountType": "Premium_LRS"},
+					{"name": "westeurope"}
+				],
+				"replicaCount": 2,
+				"storageAccountType": "Standard_ZRS"
+			},
+			"storageProfile": {
+				"source": {"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/images/toucan"}
+			}
+		}
+	}`, string(body))
+
+	// the location isn't added twice
+	version.TargetRegions = append(version.TargetRegions, GalleryTargetRegion{Name: "westeurope", ReplicaCount: 1})
+	regions := version.body(imageID, "westeurope").Properties.PublishingProfile.TargetRegions
+	require.Equal(t, version.TargetRegions, regions)
+}
+
+func TestGalleryImageDefinitionSupportsSecurityType(t *testing.T) {
+	testCases := []struct {
+		securityType string
+		supported    []string
+	}{
+		{"", []string{SecurityTypeStandard, ""}},
+		{"TrustedLaunch", []string{SecurityTypeTrustedLaunch}},
+		{"TrustedLaunchSupported", []string{SecurityTypeStandard, "", SecurityTypeTrustedLaunch}},
+		{"ConfidentialVM", []string{SecurityTypeConfidentialVM}},
+		{"ConfidentialVMSupported", []string{SecurityTypeStandard, "", SecurityTypeConfidentialVM}},
+		{"TrustedLaunchAndConfidentialVmSupported", []string{SecurityTypeStandard, "", SecurityTypeTrustedLaunch, SecurityTypeConfidentialVM}},
+	}
+	for _, tc := range testCases {
+		definition := GalleryImageDefinition{HyperVGeneration: HyperVGenerationV2, SecurityType: tc.securityType}
+		for _, securityType := range []string{"", SecurityTypeStandard, SecurityTypeTrustedLaunch, SecurityTypeConfidentialVM} {
+			expected := false
+			for _, s := range tc.supported {
+				if s == securityType {
+					expected = true
+				}
+			}
+			require.Equal(t, expected, definition.SupportsSecurityType(securityType), "%s on %s", securityType, tc.securityType)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package httpupload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// Destination is a directory of an HTTP(S) server accepting PUT requests,
+// e.g. a raw repository of Nexus or Artifactory, or a WebDAV collection.
+type Destination struct {
+	// URL of the directory the files are uploaded to
+	URL string
+	// Headers added to all requests, e.g. API keys
+	Headers  map[string]string
+	Username string
+	Password string
+	// Create the missing collections of the URL with MKCOL before the
+	// upload, plain HTTP servers are expected to create them on PUT
+	WebDAV bool
+}
+
+// Upload uploads the file as name to the destination and returns its URL.
+func Upload(ctx context.Context, client *http.Client, dest Destination, filePath, name string) (string, error) {
+	base, err := url.Parse(dest.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid destination URL: %v", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return "", fmt.Errorf("unsupported destination URL scheme '%s'", base.Scheme)
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid file name '%s'", name)
+	}
+
+	if dest.WebDAV {
+		err = makeCollections(ctx, client, dest, base)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	fileURL := *base
+	fileURL.Path = path.Join("/", base.Path, name)
+	err = putFile(ctx, client, dest, fileURL.String(), filePath)
+	if err != nil {
+		return "", err
+	}
+	return fileURL.String(), nil
+}
+
+func newRequest(ctx context.Context, dest Destination, method, u string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range dest.Headers {
+		req.Header.Set(name, value)
+	}
+	if dest.Username != "" || dest.Password != "" {
+		req.SetBasicAuth(dest.Username, dest.Password)
+	}
+	return req, nil
+}
+
+// makeCollections creates the collections of the path of the URL, from the
+// top one down, ignoring the existing ones.
+func makeCollections(ctx context.Context, client *http.Client, dest Destination, base *url.URL) error {
+	collection := *base
+	collection.Path = "/"
+	for _, segment := range strings.Split(strings.Trim(base.Path, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		collection.Path = path.Join(collection.Path, segment) + "/"
+
+		req, err := newRequest(ctx, dest, "MKCOL", collection.String(), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		// 405 is returned for existing collections
+		case http.StatusCreated, http.StatusMethodNotAllowed:
+		default:
+			return fmt.Errorf("creating the collection %s failed: %s", collection.Path, resp.Status)
+		}
+	}
+	return nil
+}
+
+func putFile(ctx context.Context, client *http.Client, dest Destination, u, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := newRequest(ctx, dest, http.MethodPut, u, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("uploading %s failed: %s", path.Base(filePath), resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package httpupload
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "disk.qcow2")
+	require.NoError(t, os.WriteFile(file, []byte("image"), 0600))
+
+	var requests []string
+	collections := map[string]bool{"/": true, "/repo/": true}
+	var uploaded string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "secret" || r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "MKCOL":
+			if collections[r.URL.Path] {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			collections[r.URL.Path] = true
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPut:
+			require.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			uploaded = string(body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	dest := Destination{
+		URL:      srv.URL + "/repo/images",
+		Headers:  map[string]string{"X-Api-Key": "key"},
+		Username: "user",
+		Password: "secret",
+	}
+
+	u, err := Upload(context.Background(), srv.Client(), dest, file, "my-image.qcow2")
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/repo/images/my-image.qcow2", u)
+	require.Equal(t, "image", uploaded)
+	require.Equal(t, []string{"PUT /repo/images/my-image.qcow2"}, requests)
+
+	requests = nil
+	dest.WebDAV = true
+	_, err = Upload(context.Background(), srv.Client(), dest, file, "my-image.qcow2")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"MKCOL /repo/",
+		"MKCOL /repo/images/",
+		"PUT /repo/images/my-image.qcow2",
+	}, requests)
+
+	dest.Password = "wrong"
+	_, err = Upload(context.Background(), srv.Client(), dest, file, "my-image.qcow2")
+	require.EqualError(t, err, "creating the collection /repo/ failed: 401 Unauthorized")
+	dest.WebDAV = false
+	_, err = Upload(context.Background(), srv.Client(), dest, file, "my-image.qcow2")
+	require.EqualError(t, err, "uploading disk.qcow2 failed: 401 Unauthorized")
+
+	_, err = Upload(context.Background(), srv.Client(), dest, file, "../escape")
+	require.EqualError(t, err, "invalid file name '../escape'")
+	_, err = Upload(context.Background(), srv.Client(), Destination{URL: "ftp://example.com/"}, file, "image")
+	require.EqualError(t, err, "unsupported destination URL scheme 'ftp'")
+}
@@ -57,6 +57,24 @@ type BuildExtra struct {
 	// It is a map whose keys are the filenames of the manifests, and
 	// the values are the extra metadata for the manifest.
 	Manifest map[string]*ManifestExtraInfo `json:"osbuild_manifest,omitempty"`
+	// ComposeRequest summarizes the compose request the build was made for.
+	ComposeRequest *ComposeRequestInfo `json:"compose_request,omitempty"`
+}
+
+// ComposeRequestImage summarizes an image requested in the compose.
+type ComposeRequestImage struct {
+	Arch      string `json:"arch"`
+	ImageType string `json:"image_type"`
+	// Names of the upload targets of the image, besides Koji
+	UploadTargets []string `json:"upload_targets,omitempty"`
+}
+
+// ComposeRequestInfo summarizes the compose request of a build.
+type ComposeRequestInfo struct {
+	Distribution     string                `json:"distribution"`
+	BlueprintName    string                `json:"blueprint_name,omitempty"`
+	BlueprintVersion string                `json:"blueprint_version,omitempty"`
+	Images           []ComposeRequestImage `json:"images"`
 }
 
 // Build represents a Koji build and holds metadata about it.
@@ -132,10 +150,54 @@ type ImageExtraInfo struct {
 	// Results from any upload targets associated with the image
 	// except for the Koji target.
 	UploadTargetResults []*target.TargetResult `json:"upload_target_results,omitempty"`
+	// Digest of the image, e.g. "sha256:<hex>"
+	Digest string `json:"digest,omitempty"`
+	// Partition table of disk images
+	PartitionTable *PartitionTable `json:"partition_table,omitempty"`
+	// Packages installed in the image
+	Packages []Package `json:"packages,omitempty"`
 }
 
 func (ImageExtraInfo) isImageOutputTypeMD() {}
 
+// Partition of a PartitionTable, the start and size are in sectors.
+type Partition struct {
+	Start    uint64 `json:"start,omitempty"`
+	Size     uint64 `json:"size,omitempty"`
+	Type     string `json:"type,omitempty"`
+	UUID     string `json:"uuid,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Bootable bool   `json:"bootable,omitempty"`
+}
+
+// PartitionTable holds the partition layout of a disk image.
+type PartitionTable struct {
+	// "gpt" or "dos"
+	Type       string      `json:"type"`
+	UUID       string      `json:"uuid,omitempty"`
+	Partitions []Partition `json:"partitions"`
+}
+
+// Package is a package installed in an image.
+type Package struct {
+	NEVRA string `json:"nevra"`
+	// Signature of the package, if it's signed
+	Signature string `json:"signature,omitempty"`
+}
+
+// PackagesFromRPMs returns the NEVRAs and signatures of the RPMs.
+func PackagesFromRPMs(rpms []rpmmd.RPM) []Package {
+	packages := make([]Package, 0, len(rpms))
+	for _, rpm := range rpms {
+		pkg := Package{NEVRA: rpm.String()}
+		if rpm.Signature != nil {
+			pkg.Signature = *rpm.Signature
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
 type OSBuildComposerDepModule struct {
 	Path    string                    `json:"path"`
 	Version string                    `json:"version"`
@@ -176,6 +238,7 @@ const (
 	BuildOutputTypeImage    BuildOutputType = "image"
 	BuildOutputTypeLog      BuildOutputType = "log"
 	BuildOutputTypeManifest BuildOutputType = "osbuild-manifest"
+	BuildOutputTypeSBOM     BuildOutputType = "sbom"
 )
 
 // ChecksumType represents the type of a checksum used for a BuildOutput.
@@ -379,6 +442,17 @@ func (k *Koji) CGCancelBuild(buildID int, token string) error {
 	return k.xmlrpc.Call("CGRefundBuild", []interface{}{"osbuild", buildID, token, buildStateCanceled}, nil)
 }
 
+// TagBuild tags the build into the tag and returns the ID of the tagging
+// task.
+func (k *Koji) TagBuild(tag string, buildID int) (int, error) {
+	var taskID int
+	err := k.xmlrpc.Call("tagBuild", []interface{}{tag, buildID}, &taskID)
+	if err != nil {
+		return 0, err
+	}
+	return taskID, nil
+}
+
 // CGImport imports previously uploaded content, by specifying its metadata, and the temporary
 // directory where it is located.
 func (k *Koji) CGImport(build Build, buildRoots []BuildRoot, outputs []BuildOutput, directory, token string) (*CGImportResult, error) {
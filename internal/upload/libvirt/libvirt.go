@@ -0,0 +1,178 @@
+package libvirt
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultDomainTemplate is a KVM domain booting from the imported disk with
+// a NIC in the default network and a serial console.
+const DefaultDomainTemplate = `<domain type='kvm'>
+  <name>{{.Name}}</name>
+  <memory unit='MiB'>{{.MemoryMiB}}</memory>
+  <vcpu>{{.VCPUs}}</vcpu>
+  <os>
+    <type arch='{{.Arch}}'>hvm</type>
+    <boot dev='hd'/>
+  </os>
+  <features>
+    <acpi/>
+  </features>
+  <cpu mode='host-passthrough'/>
+  <devices>
+    <disk type='file' device='disk'>
+      <driver name='qemu' type='{{.DiskFormat}}'/>
+      <source file='{{.DiskPath}}'/>
+      <target dev='vda' bus='virtio'/>
+    </disk>
+    <interface type='network'>
+      <source network='default'/>
+      <model type='virtio'/>
+    </interface>
+    <serial type='pty'/>
+    <console type='pty'/>
+  </devices>
+</domain>
+`
+
+// DomainOptions describe the domain booting the imported disk.
+type DomainOptions struct {
+	Name      string
+	MemoryMiB int
+	VCPUs     int
+	// Persistent domains are defined and started, transient ones are only
+	// started and disappear once they're shut down.
+	Persistent bool
+	// Template of the domain XML, DefaultDomainTemplate if empty. It's
+	// executed with DomainTemplateData, it comes from the configuration of
+	// the worker.
+	Template string
+}
+
+// DomainTemplateData are the values domain templates are executed with.
+// The strings are escaped for XML.
+type DomainTemplateData struct {
+	Name       string
+	MemoryMiB  int
+	VCPUs      int
+	Arch       string
+	DiskPath   string
+	DiskFormat string
+}
+
+// Client imports images to the libvirt host of a connection URI, e.g.
+// qemu:///system or qemu+ssh://root@host/system, with virsh.
+type Client struct {
+	uri string
+	// runs virsh with the arguments, replaceable in tests
+	virsh func(ctx context.Context, args ...string) (string, error)
+}
+
+func NewClient(uri string) *Client {
+	c := &Client{uri: uri}
+	c.virsh = c.runVirsh
+	return c
+}
+
+func (c *Client) runVirsh(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "virsh", append([]string{"--connect", c.uri}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("virsh %s failed: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// ImportVolume uploads the image as a new volume of the storage pool and
+// returns the path of the volume on the host.
+func (c *Client) ImportVolume(ctx context.Context, pool, name, imagePath, format string) (string, error) {
+	info, err := os.Stat(imagePath)
+	if err != nil {
+		return "", err
+	}
+
+	logrus.Infof("[libvirt] 💾 Creating volume %s in pool %s", name, pool)
+	_, err = c.virsh(ctx, "vol-create-as", pool, name, strconv.FormatInt(info.Size(), 10), "--format", format)
+	if err != nil {
+		return "", err
+	}
+	_, err = c.virsh(ctx, "vol-upload", "--pool", pool, name, imagePath)
+	if err != nil {
+		_, deleteErr := c.virsh(context.Background(), "vol-delete", "--pool", pool, name)
+		if deleteErr != nil {
+			logrus.Warnf("[libvirt] Cannot delete volume %s: %v", name, deleteErr)
+		}
+		return "", err
+	}
+	return c.virsh(ctx, "vol-path", "--pool", pool, name)
+}
+
+// CreateDomain defines or creates the domain booting the disk and starts it.
+func (c *Client) CreateDomain(ctx context.Context, options DomainOptions, arch, diskPath, diskFormat string) error {
+	tmpl := options.Template
+	if tmpl == "" {
+		tmpl = DefaultDomainTemplate
+	}
+	t, err := template.New("domain").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("cannot parse the domain template: %v", err)
+	}
+	var domainXML bytes.Buffer
+	err = t.Execute(&domainXML, DomainTemplateData{
+		Name:       escapeXML(options.Name),
+		MemoryMiB:  options.MemoryMiB,
+		VCPUs:      options.VCPUs,
+		Arch:       escapeXML(arch),
+		DiskPath:   escapeXML(diskPath),
+		DiskFormat: escapeXML(diskFormat),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot execute the domain template: %v", err)
+	}
+
+	tmpdir, err := os.MkdirTemp("", "libvirt-domain-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpdir)
+	xmlPath := filepath.Join(tmpdir, "domain.xml")
+	err = os.WriteFile(xmlPath, domainXML.Bytes(), 0600)
+	if err != nil {
+		return err
+	}
+
+	if !options.Persistent {
+		logrus.Infof("[libvirt] 🖥 Starting transient domain %s", options.Name)
+		_, err = c.virsh(ctx, "create", xmlPath)
+		return err
+	}
+	logrus.Infof("[libvirt] 🖥 Defining and starting domain %s", options.Name)
+	_, err = c.virsh(ctx, "define", xmlPath)
+	if err != nil {
+		return err
+	}
+	_, err = c.virsh(ctx, "start", options.Name)
+	return err
+}
+
+// escapeXML escapes the value for the text and the attributes of the domain
+// XML.
+func escapeXML(value string) string {
+	var escaped strings.Builder
+	// writes to a strings.Builder don't fail
+	_ = xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
@@ -0,0 +1,72 @@
+package libvirt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportAndCreateDomain(t *testing.T) {
+	image := filepath.Join(t.TempDir(), "disk.qcow2")
+	require.NoError(t, os.WriteFile(image, []byte("disk"), 0600))
+
+	var calls []string
+	var domainXML string
+	c := NewClient("qemu:///system")
+	c.virsh = func(ctx context.Context, args ...string) (string, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch args[0] {
+		case "vol-path":
+			return "/var/lib/libvirt/images/test", nil
+		case "create", "define":
+			data, err := os.ReadFile(args[1])
+			require.NoError(t, err)
+			domainXML = string(data)
+		}
+		return "", nil
+	}
+
+	path, err := c.ImportVolume(context.Background(), "default", "test", image, "qcow2")
+	require.NoError(t, err)
+	require.Equal(t, "/var/lib/libvirt/images/test", path)
+
+	err = c.CreateDomain(context.Background(), DomainOptions{Name: "test", MemoryMiB: 2048, VCPUs: 2}, "x86_64", path, "qcow2")
+	require.NoError(t, err)
+	require.Contains(t, domainXML, "<memory unit='MiB'>2048</memory>")
+	require.Contains(t, domainXML, "<source file='/var/lib/libvirt/images/test'/>")
+	require.Contains(t, domainXML, "<driver name='qemu' type='qcow2'/>")
+	require.Equal(t, "vol-create-as default test 4 --format qcow2", calls[0])
+	require.Equal(t, "vol-upload --pool default test "+image, calls[1])
+	require.True(t, strings.HasPrefix(calls[3], "create "))
+
+	calls = nil
+	err = c.CreateDomain(context.Background(), DomainOptions{Name: "test", Persistent: true, Template: "<domain><name>{{.Name}}</name></domain>"}, "x86_64", path, "qcow2")
+	require.NoError(t, err)
+	require.Equal(t, "<domain><name>test</name></domain>", domainXML)
+	require.True(t, strings.HasPrefix(calls[0], "define "))
+	require.Equal(t, "start test", calls[1])
+
+	// the values are escaped for XML
+	err = c.CreateDomain(context.Background(), DomainOptions{Name: "a</name><b>", MemoryMiB: 2048, VCPUs: 2}, "x86_64", "/images/it's", "qcow2")
+	require.NoError(t, err)
+	require.Contains(t, domainXML, "<name>a&lt;/name&gt;&lt;b&gt;</name>")
+	require.Contains(t, domainXML, "<source file='/images/it&#39;s'/>")
+
+	// the volume is deleted if the upload fails
+	calls = nil
+	c.virsh = func(ctx context.Context, args ...string) (string, error) {
+		calls = append(calls, args[0])
+		if args[0] == "vol-upload" {
+			return "", fmt.Errorf("virsh vol-upload failed")
+		}
+		return "", nil
+	}
+	_, err = c.ImportVolume(context.Background(), "default", "test", image, "qcow2")
+	require.EqualError(t, err, "virsh vol-upload failed")
+	require.Equal(t, []string{"vol-create-as", "vol-upload", "vol-delete"}, calls)
+}
@@ -34,12 +34,45 @@ type Client struct {
 
 // Upload uploads a file into an objectName under the bucketName in the namespace.
 func (c Client) Upload(objectName, bucketName, namespace string, file *os.File) error {
-	err := c.uploadToBucket(objectName, bucketName, namespace, file)
-	return err
+	return c.UploadWithOptions(objectName, bucketName, namespace, file, UploadOptions{})
+}
+
+// UploadOptions configure the uploaded object.
+type UploadOptions struct {
+	// StorageTier of the object (Standard, InfrequentAccess or Archive),
+	// the default tier of the bucket if empty
+	StorageTier string
+}
+
+// UploadWithOptions uploads a file the same way as Upload, configured with
+// the options.
+func (c Client) UploadWithOptions(objectName, bucketName, namespace string, file *os.File, options UploadOptions) error {
+	return c.uploadToBucket(objectName, bucketName, namespace, file, options)
+}
+
+// ObjectPath returns the native path of an object, as used in the URLs of
+// the Object Storage API.
+func ObjectPath(objectName, bucketName, namespace string) string {
+	return fmt.Sprintf("/n/%s/b/%s/o/%s", namespace, bucketName, objectName)
+}
+
+// ImageOptions configure how instances are launched from a custom image.
+type ImageOptions struct {
+	// LaunchMode of the instances (NATIVE, EMULATED or PARAVIRTUALIZED),
+	// PARAVIRTUALIZED if empty
+	LaunchMode string
+	// Shapes the image is compatible with besides the default ones
+	Shapes []string
 }
 
 // Creates an image from an existing storage object, deletes the storage object
 func (c Client) CreateImage(objectName, bucketName, namespace, compartmentID, imageName string) (string, error) {
+	return c.CreateImageWithOptions(objectName, bucketName, namespace, compartmentID, imageName, ImageOptions{})
+}
+
+// CreateImageWithOptions creates an image from an existing storage object the
+// same way as CreateImage, configured with the options.
+func (c Client) CreateImageWithOptions(objectName, bucketName, namespace, compartmentID, imageName string, options ImageOptions) (string, error) {
 	// clean up the object even if we fail
 	defer func() {
 		if err := c.deleteObjectFromBucket(objectName, bucketName, namespace); err != nil {
@@ -47,7 +80,7 @@ func (c Client) CreateImage(objectName, bucketName, namespace, compartmentID, im
 		}
 	}()
 
-	imageID, err := c.createImage(objectName, bucketName, namespace, compartmentID, imageName)
+	imageID, err := c.createImage(objectName, bucketName, namespace, compartmentID, imageName, options.LaunchMode)
 	if err != nil {
 		return "", fmt.Errorf("failed to create a custom image using object '%s' bucket '%s' in namespace '%s': %w",
 			objectName,
@@ -55,17 +88,60 @@ func (c Client) CreateImage(objectName, bucketName, namespace, compartmentID, im
 			namespace,
 			err)
 	}
+
+	for _, shape := range options.Shapes {
+		err = c.addShapeCompatibility(imageID, shape)
+		if err != nil {
+			return imageID, err
+		}
+	}
 	return imageID, nil
 }
 
+// addShapeCompatibility allows launching instances of the shape from the image.
+func (c Client) addShapeCompatibility(imageID, shape string) error {
+	resp, err := c.computeClient.AddImageShapeCompatibilityEntry(context.Background(), core.AddImageShapeCompatibilityEntryRequest{
+		ImageId:   common.String(imageID),
+		ShapeName: common.String(shape),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to make the image compatible with shape '%s': %w", shape, err)
+	}
+	if resp.HTTPResponse().StatusCode != 200 {
+		return fmt.Errorf("failed to make the image compatible with shape '%s': %d", shape, resp.HTTPResponse().StatusCode)
+	}
+	return nil
+}
+
+// launchModes returns the launch modes allowed by the capability schema of an
+// image launched in the mode by default.
+func launchModes(launchMode string) []string {
+	modes := []string{"NATIVE", "PARAVIRTUALIZED"}
+	for _, m := range modes {
+		if m == launchMode {
+			return modes
+		}
+	}
+	return append(modes, launchMode)
+}
+
+// DefaultPARExpiry is the lifetime of pre-authenticated requests
+const DefaultPARExpiry = 24 * time.Hour
+
 // https://docs.oracle.com/en-us/iaas/Content/Object/Tasks/usingpreauthenticatedrequests.htm
 func (c Client) PreAuthenticatedRequest(objectName, bucketName, namespace string) (string, error) {
+	return c.PreAuthenticatedRequestWithExpiry(objectName, bucketName, namespace, DefaultPARExpiry)
+}
+
+// PreAuthenticatedRequestWithExpiry creates a pre-authenticated request of
+// the object the same way as PreAuthenticatedRequest, valid for the duration.
+func (c Client) PreAuthenticatedRequestWithExpiry(objectName, bucketName, namespace string, expiry time.Duration) (string, error) {
 	req := objectstorage.CreatePreauthenticatedRequestRequest{
 		BucketName:    common.String(bucketName),
 		NamespaceName: common.String(namespace),
 		CreatePreauthenticatedRequestDetails: objectstorage.CreatePreauthenticatedRequestDetails{
 			ObjectName:          common.String(objectName),
-			TimeExpires:         &common.SDKTime{Time: time.Now().Add(24 * time.Hour)},
+			TimeExpires:         &common.SDKTime{Time: time.Now().Add(expiry)},
 			AccessType:          objectstorage.CreatePreauthenticatedRequestDetailsAccessTypeObjectread,
 			BucketListingAction: objectstorage.PreauthenticatedRequestBucketListingActionDeny,
 			Name:                common.String(fmt.Sprintf("pre-auth-req-for-%s", objectName)),
@@ -84,12 +160,13 @@ func (c Client) PreAuthenticatedRequest(objectName, bucketName, namespace string
 	return fmt.Sprintf("https://%s.objectstorage.%s.oci.customer-oci.com%s", namespace, c.region, *resp.AccessUri), nil
 }
 
-func (c Client) uploadToBucket(objectName string, bucketName string, namespace string, file *os.File) error {
+func (c Client) uploadToBucket(objectName string, bucketName string, namespace string, file *os.File, options UploadOptions) error {
 	req := transfer.UploadFileRequest{
 		UploadRequest: transfer.UploadRequest{
 			NamespaceName: common.String(namespace),
 			BucketName:    common.String(bucketName),
 			ObjectName:    common.String(objectName),
+			StorageTier:   objectstorage.PutObjectStorageTierEnum(options.StorageTier),
 			CallBack: func(multiPartUploadPart transfer.MultiPartUploadPart) {
 				if multiPartUploadPart.Err != nil {
 					log.Printf("upload failure: %s\n", multiPartUploadPart.Err)
@@ -123,7 +200,7 @@ func (c Client) uploadToBucket(objectName string, bucketName string, namespace s
 
 // Create creates an image from the storageObjectName stored in the bucketName.
 // The result is an image ID or an error if the operation failed.
-func (c Client) createImage(objectName, bucketName, namespace, compartmentID, imageName string) (string, error) {
+func (c Client) createImage(objectName, bucketName, namespace, compartmentID, imageName, launchMode string) (string, error) {
 	request := core.CreateImageRequest{
 		CreateImageDetails: core.CreateImageDetails{
 			DisplayName:   common.String(imageName),
@@ -140,6 +217,12 @@ func (c Client) createImage(objectName, bucketName, namespace, compartmentID, im
 			},
 		},
 	}
+	// the launch mode is detected from the image unless it's set explicitly
+	if launchMode != "" {
+		request.LaunchMode = core.CreateImageDetailsLaunchModeEnum(launchMode)
+	} else {
+		launchMode = string(core.CreateImageDetailsLaunchModeParavirtualized)
+	}
 
 	createImageResponse, err := c.computeClient.CreateImage(context.Background(), request)
 	if err != nil {
@@ -214,12 +297,9 @@ func (c Client) createImage(objectName, bucketName, namespace, compartmentID, im
 					DefaultValue: common.String("PARAVIRTUALIZED"),
 				},
 				"Compute.LaunchMode": core.EnumStringImageCapabilitySchemaDescriptor{
-					Source: core.ImageCapabilitySchemaDescriptorSourceImage,
-					Values: []string{
-						"NATIVE",
-						"PARAVIRTUALIZED",
-					},
-					DefaultValue: common.String("PARAVIRTUALIZED"),
+					Source:       core.ImageCapabilitySchemaDescriptorSourceImage,
+					Values:       launchModes(launchMode),
+					DefaultValue: common.String(launchMode),
 				},
 			},
 		},
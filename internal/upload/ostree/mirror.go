@@ -0,0 +1,259 @@
+package ostree
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Commit is the ostree commit built by the commit image types, extracted from
+// their archive.
+type Commit struct {
+	// path of the archive-z2 repository containing the commit
+	RepoPath string
+	Ref      string
+	Checksum string
+}
+
+// Remote is the ostree repository a commit is pushed to. Repositories served
+// over http(s) must accept PUT requests of the files of the repository, ssh://
+// repositories are pushed to with ostree-push.
+type Remote struct {
+	URL      string
+	Username string
+	Password string
+}
+
+// ExtractCommit extracts the commit archive to dir. The repository of the
+// archive has a single ref, pointing to the built commit.
+func ExtractCommit(archive io.Reader, dir string) (*Commit, error) {
+	tr := tar.NewReader(archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading the commit archive: %v", err)
+		}
+
+		// never write outside of dir
+		name := filepath.Join(dir, filepath.Clean("/"+hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(name, 0755)
+		case tar.TypeReg:
+			err = extractFile(tr, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error extracting %s: %v", hdr.Name, err)
+		}
+	}
+
+	commit := &Commit{RepoPath: filepath.Join(dir, "repo")}
+	headsPath := filepath.Join(commit.RepoPath, "refs", "heads")
+	var refs []string
+	err := filepath.Walk(headsPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		refs = append(refs, p)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("the archive doesn't contain an ostree repository: %v", err)
+	}
+	if len(refs) != 1 {
+		return nil, fmt.Errorf("expected a single ref in the repository, found %d", len(refs))
+	}
+
+	ref, err := filepath.Rel(headsPath, refs[0])
+	if err != nil {
+		return nil, err
+	}
+	checksum, err := os.ReadFile(refs[0])
+	if err != nil {
+		return nil, err
+	}
+	commit.Ref = filepath.ToSlash(ref)
+	commit.Checksum = strings.TrimSpace(string(checksum))
+	return commit, nil
+}
+
+func extractFile(r io.Reader, name string) error {
+	err := os.MkdirAll(filepath.Dir(name), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Push copies the commit to the remote repository and points its ref to it.
+func (c *Commit) Push(ctx context.Context, client *http.Client, remote Remote) error {
+	u, err := url.Parse(remote.URL)
+	if err != nil {
+		return fmt.Errorf("invalid remote repository URL: %v", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return c.pushHTTP(ctx, client, remote)
+	case "ssh":
+		// ostree-push authenticates with the keys of the ssh agent or
+		// configuration of the worker
+		if remote.Username != "" || remote.Password != "" {
+			return fmt.Errorf("credentials of ssh remotes can't be passed to ostree-push")
+		}
+		cmd := exec.CommandContext(ctx, "ostree-push", "--repo", c.RepoPath, remote.URL, c.Ref)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("ostree-push failed: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported remote repository URL scheme '%s'", u.Scheme)
+	}
+}
+
+// Number of objects uploaded at the same time to http(s) remotes.
+const maxUploadGoroutines = 8
+
+// pushHTTP uploads the objects missing in the remote first, and the ref last,
+// so the ref never points to an incomplete commit. The summary of the remote
+// isn't regenerated, remotes with a summary must update it themselves.
+func (c *Commit) pushHTTP(ctx context.Context, client *http.Client, remote Remote) error {
+	var objects []string
+	err := filepath.Walk(filepath.Join(c.RepoPath, "objects"), func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(c.RepoPath, p)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	paths := make(chan string)
+	errs := make(chan error, maxUploadGoroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < maxUploadGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range paths {
+				exists, err := remoteFileExists(ctx, client, remote, p)
+				if err == nil && !exists {
+					err = c.uploadFile(ctx, client, remote, p)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	var uploadErr error
+send:
+	for _, o := range objects {
+		select {
+		case paths <- o:
+		case uploadErr = <-errs:
+			break send
+		}
+	}
+	close(paths)
+	wg.Wait()
+	close(errs)
+	if uploadErr == nil {
+		uploadErr = <-errs
+	}
+	if uploadErr != nil {
+		return uploadErr
+	}
+
+	return c.uploadFile(ctx, client, remote, path.Join("refs", "heads", c.Ref))
+}
+
+func remoteRequest(ctx context.Context, method string, remote Remote, p string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(remote.URL, "/")+"/"+p, body)
+	if err != nil {
+		return nil, err
+	}
+	if remote.Username != "" || remote.Password != "" {
+		req.SetBasicAuth(remote.Username, remote.Password)
+	}
+	return req, nil
+}
+
+func remoteFileExists(ctx context.Context, client *http.Client, remote Remote, p string) (bool, error) {
+	req, err := remoteRequest(ctx, http.MethodHead, remote, p, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("checking %s in the remote repository failed: %s", p, resp.Status)
+	}
+}
+
+func (c *Commit) uploadFile(ctx context.Context, client *http.Client, remote Remote, p string) error {
+	f, err := os.Open(filepath.Join(c.RepoPath, filepath.FromSlash(p)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := remoteRequest(ctx, http.MethodPut, remote, p, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("uploading %s to the remote repository failed: %s", p, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package ostree
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func commitArchive(t *testing.T, files map[string]string) io.Reader {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return &buf
+}
+
+func TestExtractCommit(t *testing.T) {
+	dir := t.TempDir()
+	commit, err := ExtractCommit(commitArchive(t, map[string]string{
+		"compose.json":                       "{}",
+		"repo/config":                        "[core]\nmode=archive-z2\n",
+		"repo/objects/02/604b.commit":        "commit",
+		"repo/refs/heads/rhel/9/x86_64/edge": "02604b\n",
+		"../../outside":                      "not extracted outside of the directory",
+	}), dir)
+	require.NoError(t, err)
+	require.Equal(t, dir+"/repo", commit.RepoPath)
+	require.Equal(t, "rhel/9/x86_64/edge", commit.Ref)
+	require.Equal(t, "02604b", commit.Checksum)
+	require.FileExists(t, dir+"/outside")
+
+	_, err = ExtractCommit(commitArchive(t, map[string]string{
+		"repo/refs/heads/a": "1",
+		"repo/refs/heads/b": "2",
+	}), t.TempDir())
+	require.EqualError(t, err, "expected a single ref in the repository, found 2")
+}
+
+func TestPushHTTP(t *testing.T) {
+	commit, err := ExtractCommit(commitArchive(t, map[string]string{
+		"repo/objects/02/604b.commit":  "commit",
+		"repo/objects/aa/1111.dirtree": "dirtree",
+		"repo/objects/bb/2222.filez":   "file",
+		"repo/refs/heads/fedora/iot":   "02604b",
+	}), t.TempDir())
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var uploaded []string
+	remote := map[string]string{
+		"/repo/objects/aa/1111.dirtree": "dirtree",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodHead:
+			if _, ok := remote[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			remote[r.URL.Path] = string(body)
+			uploaded = append(uploaded, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	err = commit.Push(context.Background(), srv.Client(), Remote{URL: srv.URL + "/repo/", Username: "user", Password: "secret"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"/repo/objects/02/604b.commit",
+		"/repo/objects/bb/2222.filez",
+		"/repo/refs/heads/fedora/iot",
+	}, uploaded)
+	// the ref is only updated once the objects are there
+	require.Equal(t, "/repo/refs/heads/fedora/iot", uploaded[len(uploaded)-1])
+	require.Equal(t, "02604b", remote["/repo/refs/heads/fedora/iot"])
+
+	err = commit.Push(context.Background(), srv.Client(), Remote{URL: srv.URL + "/repo", Username: "user", Password: "wrong"})
+	require.ErrorContains(t, err, "401 Unauthorized")
+
+	err = commit.Push(context.Background(), srv.Client(), Remote{URL: "ftp://example.com/repo"})
+	require.EqualError(t, err, "unsupported remote repository URL scheme 'ftp'")
+}
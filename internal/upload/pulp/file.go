@@ -0,0 +1,201 @@
+package pulp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osbuild/pulp-client/pulpclient"
+	"github.com/sirupsen/logrus"
+)
+
+// GetFileRepositoryByName returns the href for a file repository based on its
+// name. Returns an empty string without an error if the repository does not
+// exist.
+func (cl *Client) GetFileRepositoryByName(name string) (string, error) {
+	list, resp, err := cl.client.RepositoriesFileAPI.RepositoriesFileFileList(cl.ctx).Name(name).Execute()
+	if err != nil {
+		return "", fmt.Errorf("repository list request returned an error: %s (%s)", err.Error(), readBody(resp))
+	}
+
+	if list.GetCount() == 0 {
+		logrus.Infof("no file repository named %s was found", name)
+		return "", nil
+	}
+
+	if list.GetCount() > 1 {
+		return "", fmt.Errorf("more than one file repository named %s was found", name)
+	}
+
+	repoHref := list.GetResults()[0].GetPulpHref()
+	logrus.Infof("found file repository %s: %s", name, repoHref)
+	return repoHref, nil
+}
+
+// CreateFileRepository creates a new file repository with a name and returns
+// the pulp href.
+func (cl *Client) CreateFileRepository(name string) (string, error) {
+	repo := *pulpclient.NewFileFileRepository(name)
+	result, resp, err := cl.client.RepositoriesFileAPI.RepositoriesFileFileCreate(cl.ctx).FileFileRepository(repo).Execute()
+	if err != nil {
+		return "", fmt.Errorf("file repository creation failed: %s (%s)", err.Error(), readBody(resp))
+	}
+
+	return result.GetPulpHref(), nil
+}
+
+// AddFile adds an uploaded artifact to a file repository under the relative
+// path, which creates a new repository version. This task is asynchronous.
+// The returned value is the href for the task.
+func (cl *Client) AddFile(artifactHref, relativePath, repoHref string) (string, error) {
+	req := cl.client.ContentFilesAPI.ContentFileFilesCreate(cl.ctx).
+		Artifact(artifactHref).
+		RelativePath(relativePath).
+		Repository(repoHref)
+	result, resp, err := req.Execute()
+	if err != nil {
+		return "", fmt.Errorf("adding file %q to the repository failed: %s (%s)", relativePath, err.Error(), readBody(resp))
+	}
+
+	return result.Task, nil
+}
+
+// PublishFileRepo publishes the latest version of a file repository. This
+// task is asynchronous. The returned value is the href for the publish task.
+func (cl *Client) PublishFileRepo(repoHref string) (string, error) {
+	publication := *pulpclient.NewFileFilePublication()
+	publication.SetRepository(repoHref)
+	result, resp, err := cl.client.PublicationsFileAPI.PublicationsFileFileCreate(cl.ctx).FileFilePublication(publication).Execute()
+	if err != nil {
+		return "", fmt.Errorf("error publishing file repository: %s (%s)", err.Error(), readBody(resp))
+	}
+
+	return result.Task, nil
+}
+
+// DistributeFileRepo makes the latest publication of a file repository
+// available for download. This task is asynchronous. The returned value is the
+// href for the distribute task.
+func (cl *Client) DistributeFileRepo(basePath, name, repoHref string) (string, error) {
+	dist := *pulpclient.NewFileFileDistribution(basePath, name)
+	dist.SetRepository(repoHref)
+	res, resp, err := cl.client.DistributionsFileAPI.DistributionsFileFileCreate(cl.ctx).FileFileDistribution(dist).Execute()
+	if err != nil {
+		return "", fmt.Errorf("error distributing file repository: %s (%s)", err.Error(), readBody(resp))
+	}
+
+	return res.Task, nil
+}
+
+// GetDistributionURLForFileRepo returns the base URL of a file distribution of
+// a given repository. Returns an empty string without an error if no
+// distribution for the repo exists.
+func (cl *Client) GetDistributionURLForFileRepo(repoHref string) (string, error) {
+	list, resp, err := cl.client.DistributionsFileAPI.DistributionsFileFileList(cl.ctx).Repository(repoHref).Execute()
+	if err != nil {
+		return "", fmt.Errorf("error looking up distribution for repo %s: %s (%s)", repoHref, err.Error(), readBody(resp))
+	}
+
+	if list.GetCount() == 0 {
+		logrus.Infof("no distribution for repo %s was found", repoHref)
+		return "", nil
+	}
+
+	// if there's more than one distribution, return the first one
+	return list.GetResults()[0].GetBaseUrl(), nil
+}
+
+// waitForCompleted blocks until all tasks are done and returns an error if
+// any of them didn't complete.
+func (cl *Client) waitForCompleted(tasks []string) error {
+	cl.waitFor(tasks)
+	for _, task := range tasks {
+		state, err := cl.TaskState(task)
+		if err != nil {
+			return err
+		}
+		if state != TASK_COMPLETED {
+			return fmt.Errorf("task %s finished in state %s", task, state)
+		}
+	}
+	return nil
+}
+
+// UploadAndDistributeFile uploads a file, creates a file repository if
+// necessary, adds the file to the repository under its name, publishes the
+// repository, and distributes it. Returns the URL of the distributed file.
+func (cl *Client) UploadAndDistributeFile(path, name, repoName, basePath string) (string, error) {
+	// As for commits, fail before uploading the file if the repository can't
+	// be created.
+	logrus.Infof("checking if file repository %q already exists", repoName)
+	repoHref, err := cl.GetFileRepositoryByName(repoName)
+	if err != nil {
+		return "", err
+	}
+
+	if repoHref == "" && basePath == "" {
+		return "", fmt.Errorf("repository %q does not exist and needs to be created, but no basepath for distribution was provided", repoName)
+	}
+
+	logrus.Infof("uploading %s to pulp", name)
+	fileHref, err := cl.UploadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	if repoHref == "" {
+		logrus.Infof("repository not found - creating file repository %q", repoName)
+		repoHref, err = cl.CreateFileRepository(repoName)
+		if err != nil {
+			return "", err
+		}
+		logrus.Infof("created file repository %q (%s)", repoName, repoHref)
+	}
+
+	// the publication has to include the new repository version
+	logrus.Infof("adding %q to repo %q", name, repoHref)
+	addTask, err := cl.AddFile(fileHref, name, repoHref)
+	if err != nil {
+		return "", err
+	}
+	if err := cl.waitForCompleted([]string{addTask}); err != nil {
+		return "", fmt.Errorf("adding %q to the repository failed: %v", name, err)
+	}
+
+	publishTask, err := cl.PublishFileRepo(repoHref)
+	if err != nil {
+		return "", err
+	}
+	tasks := []string{publishTask}
+
+	repoURL, err := cl.GetDistributionURLForFileRepo(repoHref)
+	if err != nil {
+		return "", err
+	}
+	if repoURL == "" {
+		if basePath == "" {
+			return "", fmt.Errorf("repository %q is not distributed and no basepath for distribution was provided", repoName)
+		}
+		logrus.Infof("creating distribution at %q", basePath)
+		distTask, err := cl.DistributeFileRepo(basePath, repoName, repoHref)
+		if err != nil {
+			return "", err
+		}
+		tasks = append(tasks, distTask)
+	}
+
+	logrus.Infof("blocking on %d tasks", len(tasks))
+	if err := cl.waitForCompleted(tasks); err != nil {
+		return "", err
+	}
+
+	if repoURL == "" {
+		repoURL, err = cl.GetDistributionURLForFileRepo(repoHref)
+		if err != nil {
+			return "", err
+		}
+	}
+	fileURL := strings.TrimSuffix(repoURL, "/") + "/" + name
+	logrus.Infof("file url: %s", fileURL)
+
+	return fileURL, nil
+}
@@ -0,0 +1,83 @@
+package rbd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Client imports images to the Ceph cluster of a configuration file with the
+// rbd tool, which talks to the cluster over librados.
+type Client struct {
+	// ceph.conf of the cluster, the default one of rbd if empty
+	ConfigPath string
+	// keyring of the user, the default one of rbd if empty
+	Keyring string
+	// the user without the "client." prefix, e.g. "osbuild"
+	User string
+
+	// runs rbd with the arguments, replaceable in tests
+	rbd func(ctx context.Context, args ...string) error
+}
+
+func NewClient(configPath, keyring, user string) *Client {
+	c := &Client{
+		ConfigPath: configPath,
+		Keyring:    keyring,
+		User:       user,
+	}
+	c.rbd = c.runRBD
+	return c
+}
+
+func (c *Client) commonArgs() []string {
+	var args []string
+	if c.ConfigPath != "" {
+		args = append(args, "--conf", c.ConfigPath)
+	}
+	if c.Keyring != "" {
+		args = append(args, "--keyring", c.Keyring)
+	}
+	if c.User != "" {
+		args = append(args, "--id", c.User)
+	}
+	return args
+}
+
+func (c *Client) runRBD(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "rbd", append(c.commonArgs(), args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("rbd %s failed: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// ImageSpec returns the spec of the image in the pool and namespace, the way
+// rbd prints them.
+func ImageSpec(pool, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s", pool, name)
+	}
+	return fmt.Sprintf("%s/%s/%s", pool, namespace, name)
+}
+
+// ImportImage imports the raw disk image as a new RBD image of the pool and
+// returns its spec. The image has the layering feature OpenStack and Proxmox
+// need to clone it.
+func (c *Client) ImportImage(ctx context.Context, rawImagePath, pool, namespace, name string) (string, error) {
+	spec := ImageSpec(pool, namespace, name)
+	logrus.Infof("[rbd] 💾 Importing %s as %s", rawImagePath, spec)
+	err := c.rbd(ctx, "import", "--image-format", "2", "--image-feature", "layering", rawImagePath, spec)
+	if err != nil {
+		return "", err
+	}
+	logrus.Infof("[rbd] 🎉 Image %s imported", spec)
+	return spec, nil
+}
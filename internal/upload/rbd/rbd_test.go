@@ -0,0 +1,32 @@
+package rbd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportImage(t *testing.T) {
+	var calls []string
+	c := NewClient("/etc/ceph/ceph.conf", "/etc/ceph/ceph.client.osbuild.keyring", "osbuild")
+	c.rbd = func(ctx context.Context, args ...string) error {
+		calls = append(calls, strings.Join(append(c.commonArgs(), args...), " "))
+		return nil
+	}
+
+	spec, err := c.ImportImage(context.Background(), "/tmp/disk.raw", "images", "", "rhel-9")
+	require.NoError(t, err)
+	require.Equal(t, "images/rhel-9", spec)
+
+	spec, err = c.ImportImage(context.Background(), "/tmp/disk.raw", "images", "dev", "rhel-9")
+	require.NoError(t, err)
+	require.Equal(t, "images/dev/rhel-9", spec)
+	require.Equal(t, []string{
+		"--conf /etc/ceph/ceph.conf --keyring /etc/ceph/ceph.client.osbuild.keyring --id osbuild import --image-format 2 --image-feature layering /tmp/disk.raw images/rhel-9",
+		"--conf /etc/ceph/ceph.conf --keyring /etc/ceph/ceph.client.osbuild.keyring --id osbuild import --image-format 2 --image-feature layering /tmp/disk.raw images/dev/rhel-9",
+	}, calls)
+
+	require.Empty(t, NewClient("", "", "").commonArgs())
+}
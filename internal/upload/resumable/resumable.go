@@ -0,0 +1,297 @@
+// Package resumable keeps the state of multipart uploads on disk, so that an
+// upload interrupted by a restart of the worker continues where it stopped
+// instead of uploading the whole artifact again.
+//
+// Uploads are split into parts of the same size. Once a part is uploaded, its
+// MD5 hash is recorded in the state together with whatever the cloud returned
+// for it. When an upload of the same destination is resumed, parts whose
+// content still matches the recorded hash are skipped.
+package resumable
+
+import (
+	"context"
+	// the clouds use MD5 hashes to verify parts
+	/* #nosec G501 */
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	MiB = 1024 * 1024
+
+	DefaultPartSize    = 64 * MiB
+	DefaultConcurrency = 4
+)
+
+// Options of multipart uploads.
+type Options struct {
+	// Size of the parts, DefaultPartSize if 0
+	PartSize int64
+	// Number of parts uploaded in parallel, DefaultConcurrency if 0
+	Concurrency int
+}
+
+// PartSizeFor returns the part size of an upload of size bytes which is split
+// into maxParts parts at most. The configured size is doubled until the parts
+// fit, so it stays aligned the way the configured one is.
+func (o Options) PartSizeFor(size, maxParts int64) int64 {
+	partSize := o.PartSize
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+	for (size+partSize-1)/partSize > maxParts {
+		partSize *= 2
+	}
+	return partSize
+}
+
+func (o Options) concurrency() int {
+	if o.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return o.Concurrency
+}
+
+// Part is an uploaded part, numbered from 1.
+type Part struct {
+	Number int `json:"number"`
+	// hex encoded MD5 hash of the uploaded data
+	MD5 string `json:"md5"`
+	// ETag of the part, if the cloud returns one
+	ETag string `json:"etag,omitempty"`
+}
+
+// State of a multipart upload.
+type State struct {
+	// Identifies the upload in the cloud, e.g. the ID of an S3 multipart
+	// upload. Empty if the cloud doesn't need one.
+	UploadID string `json:"upload_id,omitempty"`
+	Size     int64  `json:"size"`
+	PartSize int64  `json:"part_size"`
+	// The uploaded parts, sorted by their number
+	Parts []Part `json:"parts"`
+}
+
+func NewState(size, partSize int64) *State {
+	return &State{
+		Size:     size,
+		PartSize: partSize,
+	}
+}
+
+// Matches returns whether parts of the state can be reused by an upload of
+// size bytes in parts of partSize.
+func (s *State) Matches(size, partSize int64) bool {
+	return s != nil && s.Size == size && s.PartSize == partSize
+}
+
+// PartCount returns the number of parts of the upload, there's always at least
+// one.
+func (s *State) PartCount() int {
+	if s.Size == 0 {
+		return 1
+	}
+	return int((s.Size + s.PartSize - 1) / s.PartSize)
+}
+
+func (s *State) setPart(part Part) {
+	for i := range s.Parts {
+		if s.Parts[i].Number == part.Number {
+			s.Parts[i] = part
+			return
+		}
+	}
+	s.Parts = append(s.Parts, part)
+	sort.Slice(s.Parts, func(i, j int) bool { return s.Parts[i].Number < s.Parts[j].Number })
+}
+
+// Store keeps the states of uploads as JSON files in a directory. A nil store
+// is valid and doesn't keep anything, uploads using it can't be resumed.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store keeping the states in dir, which is created if
+// it doesn't exist.
+func NewStore(dir string) (*Store, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the upload state directory: %v", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// keys are URLs of the destinations, which can't be used as file names
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Load returns the state of the upload to the destination identified by key,
+// or nil if there's none.
+func (s *Store) Load(key string) (*State, error) {
+	if s == nil {
+		return nil, nil
+	}
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the state of the upload to %s: %v", key, err)
+	}
+	var state State
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		// a restart while writing the state isn't a reason to fail the upload
+		logrus.Warnf("Discarding the corrupted state of the upload to %s: %v", key, err)
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// Save replaces the state of the upload to the destination identified by key.
+func (s *Store) Save(key string, state *State) error {
+	if s == nil {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	// write and rename, the state is never half written
+	tmp, err := os.CreateTemp(s.dir, ".state-*")
+	if err != nil {
+		return fmt.Errorf("cannot save the state of the upload to %s: %v", key, err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cannot save the state of the upload to %s: %v", key, err)
+	}
+	return nil
+}
+
+// Delete removes the state of the upload to the destination identified by
+// key, once it's finished.
+func (s *Store) Delete(key string) error {
+	if s == nil {
+		return nil
+	}
+	err := os.Remove(s.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot delete the state of the upload to %s: %v", key, err)
+	}
+	return nil
+}
+
+// UploadPartFunc uploads the data of a part and returns its ETag, if any.
+type UploadPartFunc func(ctx context.Context, number int, offset int64, data []byte) (string, error)
+
+// UploadParts uploads the parts of the file which aren't in the state yet, or
+// whose content changed. The state is saved in the store under key after each
+// uploaded part. The parts are uploaded concurrently, so upload must be safe
+// to call from several goroutines.
+func UploadParts(ctx context.Context, store *Store, key string, state *State, file io.ReaderAt, options Options, upload UploadPartFunc) error {
+	uploaded := make(map[int]string, len(state.Parts))
+	for _, part := range state.Parts {
+		uploaded[part.Number] = part.MD5
+	}
+
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var uploadErr error
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if uploadErr == nil {
+			uploadErr = err
+			cancel()
+		}
+	}
+
+	// bounds both the goroutines and the parts held in memory
+	semaphore := make(chan struct{}, options.concurrency())
+	skipped := 0
+	for number := 1; number <= state.PartCount(); number++ {
+		select {
+		case semaphore <- struct{}{}:
+		case <-uploadCtx.Done():
+		}
+		if uploadCtx.Err() != nil {
+			break
+		}
+
+		offset := int64(number-1) * state.PartSize
+		length := state.PartSize
+		if offset+length > state.Size {
+			length = state.Size - offset
+		}
+		data := make([]byte, length)
+		// ReadAt returns an error if it reads less
+		n, err := file.ReadAt(data, offset)
+		if n < len(data) {
+			<-semaphore
+			fail(fmt.Errorf("cannot read part %d: %v", number, err))
+			break
+		}
+		/* #nosec G401 */
+		sum := md5.Sum(data)
+		hash := hex.EncodeToString(sum[:])
+		if uploaded[number] == hash {
+			skipped++
+			<-semaphore
+			continue
+		}
+
+		wg.Add(1)
+		go func(number int, offset int64, data []byte, hash string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			etag, err := upload(uploadCtx, number, offset, data)
+			if err != nil {
+				fail(fmt.Errorf("uploading part %d failed: %w", number, err))
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			state.setPart(Part{Number: number, MD5: hash, ETag: etag})
+			err = store.Save(key, state)
+			if err != nil {
+				// the upload can go on, it just can't be resumed
+				logrus.Warn(err)
+			}
+		}(number, offset, data, hash)
+	}
+	wg.Wait()
+
+	if skipped > 0 {
+		logrus.Infof("Resumed the upload to %s, %d of %d parts were already uploaded", key, skipped, state.PartCount())
+	}
+	if uploadErr != nil {
+		return uploadErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,95 @@
+package resumable
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	state, err := store.Load("s3://bucket/key")
+	require.NoError(t, err)
+	require.Nil(t, state)
+
+	state = NewState(10, 4)
+	state.UploadID = "upload"
+	state.setPart(Part{Number: 2, MD5: "b"})
+	state.setPart(Part{Number: 1, MD5: "a"})
+	require.NoError(t, store.Save("s3://bucket/key", state))
+
+	loaded, err := store.Load("s3://bucket/key")
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+	require.Equal(t, []Part{{Number: 1, MD5: "a"}, {Number: 2, MD5: "b"}}, loaded.Parts)
+	require.True(t, loaded.Matches(10, 4))
+	require.False(t, loaded.Matches(10, 5))
+
+	require.NoError(t, store.Delete("s3://bucket/key"))
+	require.NoError(t, store.Delete("s3://bucket/key"))
+	state, err = store.Load("s3://bucket/key")
+	require.NoError(t, err)
+	require.Nil(t, state)
+
+	// a nil store doesn't keep anything
+	var nilStore *Store
+	require.NoError(t, nilStore.Save("key", NewState(1, 1)))
+	state, err = nilStore.Load("key")
+	require.NoError(t, err)
+	require.Nil(t, state)
+}
+
+func TestPartSizeFor(t *testing.T) {
+	require.Equal(t, int64(DefaultPartSize), Options{}.PartSizeFor(10*1024*MiB, 10000))
+	require.Equal(t, int64(8*MiB), Options{PartSize: 2 * MiB}.PartSizeFor(25*MiB, 4))
+	require.Equal(t, int64(5*MiB), Options{PartSize: 5 * MiB}.PartSizeFor(25*MiB, 5))
+}
+
+func TestUploadParts(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	data := []byte("0123456789")
+
+	var mu sync.Mutex
+	var uploaded []string
+	upload := func(ctx context.Context, number int, offset int64, part []byte) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if string(part) == "89" {
+			return "", fmt.Errorf("connection reset")
+		}
+		uploaded = append(uploaded, fmt.Sprintf("%d@%d:%s", number, offset, part))
+		return fmt.Sprintf("etag-%d", number), nil
+	}
+
+	// the last part fails, the others are kept in the state
+	state := NewState(int64(len(data)), 4)
+	require.Equal(t, 3, state.PartCount())
+	err = UploadParts(context.Background(), store, "key", state, bytes.NewReader(data), Options{Concurrency: 1}, upload)
+	require.ErrorContains(t, err, "uploading part 3 failed: connection reset")
+	require.Equal(t, []string{"1@0:0123", "2@4:4567"}, uploaded)
+
+	// resuming skips the parts which didn't change
+	state, err = store.Load("key")
+	require.NoError(t, err)
+	require.Len(t, state.Parts, 2)
+	require.Equal(t, "etag-1", state.Parts[0].ETag)
+	uploaded = nil
+	data = []byte("0123abcdXY")
+	err = UploadParts(context.Background(), store, "key", state, bytes.NewReader(data), Options{Concurrency: 2}, upload)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"2@4:abcd", "3@8:XY"}, uploaded)
+	require.Len(t, state.Parts, 3)
+
+	// an empty file is uploaded as a single empty part
+	uploaded = nil
+	err = UploadParts(context.Background(), nil, "empty", NewState(0, 4), bytes.NewReader(nil), Options{}, upload)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1@0:"}, uploaded)
+}
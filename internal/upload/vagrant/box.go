@@ -0,0 +1,220 @@
+// Package vagrant packages disk images into Vagrant boxes and uploads them
+// to Vagrant Cloud compatible servers.
+package vagrant
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Providers of the boxes.
+const (
+	ProviderLibvirt    = "libvirt"
+	ProviderVirtualBox = "virtualbox"
+)
+
+const gib = 1024 * 1024 * 1024
+
+const libvirtVagrantfile = `Vagrant.configure("2") do |config|
+  config.vm.provider :libvirt do |libvirt|
+    libvirt.driver = "kvm"
+  end
+end
+`
+
+const virtualBoxVagrantfile = `Vagrant.configure("2") do |config|
+  config.vm.base_mac = nil
+end
+`
+
+// BoxFilename returns the name of the box of the provider.
+func BoxFilename(provider string) string {
+	return provider + ".box"
+}
+
+// PackageBox packages the image into a box of the provider at boxPath.
+// Libvirt boxes are made of a qcow2 image, VirtualBox boxes of an OVA.
+func PackageBox(provider, imagePath, boxPath string) error {
+	f, err := os.Create(boxPath)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	switch provider {
+	case ProviderLibvirt:
+		err = packageLibvirt(tw, imagePath)
+	case ProviderVirtualBox:
+		err = packageVirtualBox(tw, imagePath)
+	default:
+		err = fmt.Errorf("unsupported vagrant provider '%s'", provider)
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gw.Close()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(boxPath)
+		return fmt.Errorf("packaging the %s box failed: %v", provider, err)
+	}
+	return nil
+}
+
+func packageLibvirt(tw *tar.Writer, imagePath string) error {
+	size, err := qcow2VirtualSize(imagePath)
+	if err != nil {
+		return err
+	}
+	metadata := map[string]interface{}{
+		"provider": ProviderLibvirt,
+		"format":   "qcow2",
+		// in GiB, rounded up
+		"virtual_size": (size + gib - 1) / gib,
+	}
+	err = writeMetadata(tw, metadata, libvirtVagrantfile)
+	if err != nil {
+		return err
+	}
+	return addFile(tw, "box.img", imagePath)
+}
+
+// packageVirtualBox copies the members of the OVA to the box, its OVF
+// descriptor has to be named box.ovf. The manifest of the OVA lists the
+// original names, so it's dropped.
+func packageVirtualBox(tw *tar.Writer, ovaPath string) error {
+	err := writeMetadata(tw, map[string]interface{}{"provider": ProviderVirtualBox}, virtualBoxVagrantfile)
+	if err != nil {
+		return err
+	}
+
+	ova, err := os.Open(ovaPath)
+	if err != nil {
+		return err
+	}
+	defer ova.Close()
+	tr := tar.NewReader(ova)
+	foundOVF := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading the OVA: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := filepath.Base(hdr.Name)
+		switch filepath.Ext(name) {
+		case ".mf":
+			continue
+		case ".ovf":
+			if foundOVF {
+				return fmt.Errorf("the OVA contains more than one OVF descriptor")
+			}
+			foundOVF = true
+			name = "box.ovf"
+		}
+		err = tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     hdr.Size,
+			ModTime:  hdr.ModTime,
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, tr)
+		if err != nil {
+			return err
+		}
+	}
+	if !foundOVF {
+		return fmt.Errorf("the OVA doesn't contain an OVF descriptor")
+	}
+	return nil
+}
+
+func writeMetadata(tw *tar.Writer, metadata map[string]interface{}, vagrantfile string) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	err = addData(tw, "metadata.json", data)
+	if err != nil {
+		return err
+	}
+	return addData(tw, "Vagrantfile", []byte(vagrantfile))
+}
+
+func addData(tw *tar.Writer, name string, data []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+func addFile(tw *tar.Writer, name, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// qcow2VirtualSize reads the size of the disk from the header of the image.
+func qcow2VirtualSize(imagePath string) (int64, error) {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// magic, version, backing file offset and size, cluster bits, size
+	header := make([]byte, 32)
+	_, err = io.ReadFull(f, header)
+	if err != nil || !strings.HasPrefix(string(header), "QFI\xfb") {
+		return 0, fmt.Errorf("%s isn't a qcow2 image", filepath.Base(imagePath))
+	}
+	return int64(binary.BigEndian.Uint64(header[24:32])), nil
+}
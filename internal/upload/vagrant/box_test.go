@@ -0,0 +1,84 @@
+package vagrant
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readBox(t *testing.T, boxPath string) map[string]string {
+	f, err := os.Open(boxPath)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+	return files
+}
+
+func TestPackageBoxLibvirt(t *testing.T) {
+	dir := t.TempDir()
+	image := make([]byte, 64)
+	copy(image, "QFI\xfb")
+	binary.BigEndian.PutUint64(image[24:], 10*gib+1)
+	imagePath := filepath.Join(dir, "disk.qcow2")
+	require.NoError(t, os.WriteFile(imagePath, image, 0600))
+
+	boxPath := filepath.Join(dir, BoxFilename(ProviderLibvirt))
+	require.NoError(t, PackageBox(ProviderLibvirt, imagePath, boxPath))
+	files := readBox(t, boxPath)
+	require.JSONEq(t, `{"provider": "libvirt", "format": "qcow2", "virtual_size": 11}`, files["metadata.json"])
+	require.Equal(t, libvirtVagrantfile, files["Vagrantfile"])
+	require.Equal(t, string(image), files["box.img"])
+
+	require.NoError(t, os.WriteFile(imagePath, []byte("not an image"), 0600))
+	require.Error(t, PackageBox(ProviderLibvirt, imagePath, boxPath))
+	require.NoFileExists(t, boxPath)
+}
+
+func TestPackageBoxVirtualBox(t *testing.T) {
+	dir := t.TempDir()
+	ovaPath := filepath.Join(dir, "image.ova")
+	f, err := os.Create(ovaPath)
+	require.NoError(t, err)
+	tw := tar.NewWriter(f)
+	for _, file := range []struct{ name, content string }{
+		{"image.ovf", "<Envelope/>"},
+		{"image.mf", "SHA256(image.ovf)= 00"},
+		{"image.vmdk", "disk"},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.content)), Typeflag: tar.TypeReg}))
+		_, err = tw.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, f.Close())
+
+	boxPath := filepath.Join(dir, BoxFilename(ProviderVirtualBox))
+	require.NoError(t, PackageBox(ProviderVirtualBox, ovaPath, boxPath))
+	require.Equal(t, map[string]string{
+		"metadata.json": `{"provider":"virtualbox"}`,
+		"Vagrantfile":   virtualBoxVagrantfile,
+		"box.ovf":       "<Envelope/>",
+		"image.vmdk":    "disk",
+	}, readBox(t, boxPath))
+
+	require.Error(t, PackageBox("hyperv", ovaPath, boxPath))
+}
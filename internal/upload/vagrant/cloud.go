@@ -0,0 +1,168 @@
+package vagrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// DefaultCloudURL is the URL of the public Vagrant Cloud.
+const DefaultCloudURL = "https://app.vagrantup.com"
+
+// Cloud is a server implementing the box API of Vagrant Cloud.
+type Cloud struct {
+	URL   string
+	Token string
+}
+
+// Box is a version of a box, named user/name, a provider is uploaded to.
+type Box struct {
+	Name    string
+	Version string
+	// Checksum of the box file, verified by vagrant when it's downloaded
+	SHA256 string
+}
+
+// Upload creates the version of the box, if it doesn't exist yet, and
+// uploads the box file as the provider of the version. The version is
+// released if release is set, otherwise it has to be released once all its
+// providers are uploaded.
+func (c Cloud) Upload(ctx context.Context, client *http.Client, box Box, provider, boxPath string, release bool) error {
+	parts := strings.Split(box.Name, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid box name '%s', expected user/name", box.Name)
+	}
+	boxPrefix := path.Join("api/v2/box", box.Name)
+	versionPrefix := path.Join(boxPrefix, "version", box.Version)
+
+	err := c.request(ctx, client, http.MethodPost, path.Join(boxPrefix, "versions"), map[string]interface{}{
+		"version": map[string]string{"version": box.Version},
+	}, nil, http.StatusUnprocessableEntity)
+	if err != nil {
+		return fmt.Errorf("creating version %s of box %s failed: %v", box.Version, box.Name, err)
+	}
+
+	providerOptions := map[string]string{"name": provider}
+	if box.SHA256 != "" {
+		providerOptions["checksum"] = box.SHA256
+		providerOptions["checksum_type"] = "sha256"
+	}
+	err = c.request(ctx, client, http.MethodPost, path.Join(versionPrefix, "providers"), map[string]interface{}{
+		"provider": providerOptions,
+	}, nil, http.StatusUnprocessableEntity)
+	if err != nil {
+		return fmt.Errorf("creating provider %s of box %s failed: %v", provider, box.Name, err)
+	}
+
+	var upload struct {
+		UploadPath string `json:"upload_path"`
+	}
+	err = c.request(ctx, client, http.MethodGet, path.Join(versionPrefix, "provider", provider, "upload"), nil, &upload, 0)
+	if err != nil {
+		return fmt.Errorf("getting the upload URL of box %s failed: %v", box.Name, err)
+	}
+	if upload.UploadPath == "" {
+		return fmt.Errorf("no upload URL of box %s returned", box.Name)
+	}
+	err = putBox(ctx, client, upload.UploadPath, boxPath)
+	if err != nil {
+		return err
+	}
+
+	if release {
+		err = c.request(ctx, client, http.MethodPut, path.Join(versionPrefix, "release"), nil, nil, 0)
+		if err != nil {
+			return fmt.Errorf("releasing version %s of box %s failed: %v", box.Version, box.Name, err)
+		}
+	}
+	return nil
+}
+
+// request sends the body as JSON and decodes the response into result, if
+// not nil. The status allowedStatus, e.g. of already existing resources,
+// isn't an error.
+func (c Cloud) request(ctx context.Context, client *http.Client, method, p string, body, result interface{}, allowedStatus int) error {
+	base := c.URL
+	if base == "" {
+		base = DefaultCloudURL
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return fmt.Errorf("invalid Vagrant Cloud URL: %v", err)
+	}
+	u.Path = path.Join("/", u.Path, p)
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == allowedStatus {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, u.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+// putBox uploads the box to the URL returned by the server, it's
+// authenticated by the URL itself.
+func putBox(ctx context.Context, client *http.Client, u, boxPath string) error {
+	f, err := os.Open(boxPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("uploading the box failed: %s", resp.Status)
+	}
+	return nil
+}
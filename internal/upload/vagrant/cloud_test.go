@@ -0,0 +1,68 @@
+package vagrant
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudUpload(t *testing.T) {
+	boxPath := filepath.Join(t.TempDir(), "libvirt.box")
+	require.NoError(t, os.WriteFile(boxPath, []byte("box"), 0600))
+
+	var requests []string
+	var provider map[string]map[string]string
+	var uploaded string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/upload/token" {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			uploaded = string(body)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v2/box/osbuild/fedora/versions":
+			// the version already exists
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		case "/api/v2/box/osbuild/fedora/version/39.1/providers":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&provider))
+			w.WriteHeader(http.StatusCreated)
+		case "/api/v2/box/osbuild/fedora/version/39.1/provider/libvirt/upload":
+			_, err := w.Write([]byte(`{"upload_path": "` + srv.URL + `/upload/token"}`))
+			require.NoError(t, err)
+		case "/api/v2/box/osbuild/fedora/version/39.1/release":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cloud := Cloud{URL: srv.URL, Token: "secret"}
+	box := Box{Name: "osbuild/fedora", Version: "39.1", SHA256: "abcd"}
+	require.NoError(t, cloud.Upload(context.Background(), srv.Client(), box, ProviderLibvirt, boxPath, true))
+	require.Equal(t, []string{
+		"POST /api/v2/box/osbuild/fedora/versions",
+		"POST /api/v2/box/osbuild/fedora/version/39.1/providers",
+		"GET /api/v2/box/osbuild/fedora/version/39.1/provider/libvirt/upload",
+		"PUT /upload/token",
+		"PUT /api/v2/box/osbuild/fedora/version/39.1/release",
+	}, requests)
+	require.Equal(t, map[string]string{"name": "libvirt", "checksum": "abcd", "checksum_type": "sha256"}, provider["provider"])
+	require.Equal(t, "box", uploaded)
+
+	require.Error(t, cloud.Upload(context.Background(), srv.Client(), Box{Name: "fedora", Version: "39.1"}, ProviderLibvirt, boxPath, false))
+	require.Error(t, Cloud{URL: srv.URL}.Upload(context.Background(), srv.Client(), box, ProviderLibvirt, boxPath, false))
+}
@@ -0,0 +1,176 @@
+package vmware
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// uploadFunc uploads size bytes read from r as the named file of a content
+// library item.
+type uploadFunc func(name string, size int64, r io.Reader) error
+
+// ImportToContentLibrary uploads the image to a new item of the content
+// library and returns the ID of the item. OVAs are imported as OVF templates
+// VMs can be deployed from, other images as plain files named after the item.
+//
+// Items are stored in the storage backing the library, so only the host and
+// the credentials are used.
+func ImportToContentLibrary(ctx context.Context, creds Credentials, libraryName, itemName, imagePath string) (string, error) {
+	u, err := soap.ParseURL(creds.Host)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse the vSphere host: %v", err)
+	}
+	u.User = url.UserPassword(creds.Username, creds.Password)
+
+	// certificates aren't verified, the same as when importing to a datastore
+	vimClient, err := vim25.NewClient(ctx, soap.NewClient(u, true))
+	if err != nil {
+		return "", fmt.Errorf("cannot connect to vSphere: %v", err)
+	}
+	restClient := rest.NewClient(vimClient)
+	err = restClient.Login(ctx, u.User)
+	if err != nil {
+		return "", fmt.Errorf("cannot log in to vSphere: %v", err)
+	}
+	defer func() {
+		err := restClient.Logout(context.Background())
+		if err != nil {
+			logrus.Warnf("[vSphere] Cannot log out: %v", err)
+		}
+	}()
+
+	m := library.NewManager(restClient)
+	lib, err := m.GetLibraryByName(ctx, libraryName)
+	if err != nil {
+		return "", fmt.Errorf("cannot find content library %s: %v", libraryName, err)
+	}
+
+	itemType := "file"
+	if strings.HasSuffix(imagePath, ".ova") {
+		itemType = "ovf"
+	}
+	logrus.Infof("[vSphere] 📚 Creating item %s in content library %s", itemName, libraryName)
+	itemID, err := m.CreateLibraryItem(ctx, library.Item{
+		Name:      itemName,
+		LibraryID: lib.ID,
+		Type:      itemType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot create the content library item: %v", err)
+	}
+	imported := false
+	defer func() {
+		if imported {
+			return
+		}
+		err := m.DeleteLibraryItem(context.Background(), &library.Item{ID: itemID})
+		if err != nil {
+			logrus.Warnf("[vSphere] Cannot delete content library item %s: %v", itemID, err)
+		}
+	}()
+
+	sessionID, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: itemID})
+	if err != nil {
+		return "", fmt.Errorf("cannot create an update session of the content library item: %v", err)
+	}
+	upload := func(name string, size int64, r io.Reader) error {
+		logrus.Infof("[vSphere] ⬆ Uploading %s", name)
+		file, err := m.AddLibraryItemFile(ctx, sessionID, library.UpdateFile{
+			Name:       name,
+			SourceType: "PUSH",
+			Size:       size,
+		})
+		if err != nil {
+			return err
+		}
+		if file.UploadEndpoint == nil {
+			return fmt.Errorf("no upload endpoint returned for %s", name)
+		}
+		endpoint, err := url.Parse(file.UploadEndpoint.URI)
+		if err != nil {
+			return err
+		}
+		params := soap.DefaultUpload
+		params.ContentLength = size
+		return restClient.Upload(ctx, r, endpoint, &params)
+	}
+
+	if itemType == "ovf" {
+		err = walkOVA(imagePath, upload)
+	} else {
+		err = uploadImage(imagePath, itemName+filepath.Ext(imagePath), upload)
+	}
+	if err != nil {
+		failErr := m.FailLibraryItemUpdateSession(context.Background(), sessionID)
+		if failErr != nil {
+			logrus.Warnf("[vSphere] Cannot fail update session %s: %v", sessionID, failErr)
+		}
+		return "", fmt.Errorf("importing %s into the content library failed: %v", imagePath, err)
+	}
+
+	err = m.CompleteLibraryItemUpdateSession(ctx, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("cannot complete the update session of the content library item: %v", err)
+	}
+	err = m.WaitOnLibraryItemUpdateSession(ctx, sessionID, 3*time.Second, nil)
+	if err != nil {
+		return "", fmt.Errorf("importing %s into the content library failed: %v", imagePath, err)
+	}
+	logrus.Infof("[vSphere] 🎉 Content library item %s created", itemID)
+	imported = true
+	return itemID, nil
+}
+
+func uploadImage(imagePath, name string, upload uploadFunc) error {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return upload(name, info.Size(), f)
+}
+
+// walkOVA calls upload for every file of the OVA archive, an OVF template
+// consists of the same files as the archive.
+func walkOVA(imagePath string, upload uploadFunc) error {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %v", imagePath, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		err = upload(filepath.Base(header.Name), header.Size, tr)
+		if err != nil {
+			return err
+		}
+	}
+}
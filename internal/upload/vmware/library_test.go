@@ -0,0 +1,44 @@
+package vmware
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkOVA(t *testing.T) {
+	ova := filepath.Join(t.TempDir(), "image.ova")
+	f, err := os.Create(ova)
+	require.NoError(t, err)
+	tw := tar.NewWriter(f)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "image/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, file := range []struct{ name, content string }{
+		{"image/image.ovf", "<Envelope/>"},
+		{"image/image.mf", "SHA256(image.ovf)= 00"},
+		{"image/image-disk1.vmdk", "disk"},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: file.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(file.content))}))
+		_, err = tw.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, f.Close())
+
+	uploaded := map[string]string{}
+	var order []string
+	err = walkOVA(ova, func(name string, size int64, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, size, int64(len(data)))
+		uploaded[name] = string(data)
+		order = append(order, name)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"image.ovf", "image.mf", "image-disk1.vmdk"}, order)
+	require.Equal(t, "disk", uploaded["image-disk1.vmdk"])
+}
@@ -0,0 +1,348 @@
+// Package vault implements a minimal client for HashiCorp Vault which is
+// used by the worker to fetch credentials of upload targets. Secrets are
+// cached and their leases are renewed, so the worker doesn't need to
+// contact Vault for every job.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Secrets and tokens are refreshed once less than this fraction of their
+// lease duration remains.
+const renewThreshold = 3
+
+// Secrets without a lease, e.g. the ones of the KV engines, are read again
+// after this duration by default, so rotated credentials are picked up.
+const defaultSecretTTL = 5 * time.Minute
+
+type Config struct {
+	// Address of the Vault server, e.g. https://vault.example.com:8200
+	Address string
+	// Namespace to use for all the requests (Vault Enterprise only)
+	Namespace string
+	// CA certificate used to verify the Vault server
+	CACertFile string
+
+	// Static token used to authenticate. Takes precedence over AppRole.
+	Token string
+
+	// AppRole credentials used to log in when no static token is given.
+	RoleID   string
+	SecretID string
+
+	// How long secrets without a lease are cached, 5 minutes if not set.
+	SecretTTL time.Duration
+}
+
+// Secret is a secret read from Vault.
+type Secret struct {
+	Data          map[string]interface{}
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+
+	fetched time.Time
+	// how long the secret is cached if it has no lease
+	ttl time.Duration
+}
+
+// expiresAt returns the time the lease of the secret runs out. Secrets
+// without a lease expire once they were cached for their TTL.
+func (s *Secret) expiresAt() time.Time {
+	if s.LeaseDuration == 0 {
+		return s.fetched.Add(s.ttl)
+	}
+	return s.fetched.Add(s.LeaseDuration)
+}
+
+// needsRefresh returns true if the lease of the secret is close to expiring,
+// or if a secret without a lease has been cached for its TTL.
+func (s *Secret) needsRefresh(now time.Time) bool {
+	if s.LeaseDuration == 0 {
+		return !now.Before(s.expiresAt())
+	}
+	return now.After(s.expiresAt().Add(-s.LeaseDuration / renewThreshold))
+}
+
+// String returns the value of the key as a string or an empty string if
+// the key doesn't exist or isn't a string.
+func (s *Secret) String(key string) string {
+	v, ok := s.Data[key].(string)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+type Client struct {
+	address    string
+	namespace  string
+	httpClient *http.Client
+
+	roleID   string
+	secretID string
+
+	secretTTL time.Duration
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	secrets     map[string]*Secret
+}
+
+func NewClient(conf Config) (*Client, error) {
+	if conf.Address == "" {
+		return nil, fmt.Errorf("vault address is not set")
+	}
+	if conf.Token == "" && (conf.RoleID == "" || conf.SecretID == "") {
+		return nil, fmt.Errorf("either a vault token or AppRole credentials have to be set")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf.CACertFile != "" {
+		caCertPEM, err := os.ReadFile(conf.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read vault CA certificate: %v", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("failed to append vault CA certificate")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    roots,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	secretTTL := conf.SecretTTL
+	if secretTTL == 0 {
+		secretTTL = defaultSecretTTL
+	}
+
+	return &Client{
+		address:   strings.TrimSuffix(conf.Address, "/"),
+		namespace: conf.Namespace,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   time.Minute,
+		},
+		roleID:    conf.RoleID,
+		secretID:  conf.SecretID,
+		secretTTL: secretTTL,
+		token:     conf.Token,
+		secrets:   make(map[string]*Secret),
+	}, nil
+}
+
+// response is the common envelope of all Vault API responses.
+type response struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int                    `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+	Auth          *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+func (c *Client) request(method, path, token string, body interface{}) (*response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, fmt.Sprintf("%s/v1/%s", c.address, strings.TrimPrefix(path, "/")), reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var r response
+	if resp.StatusCode != http.StatusNoContent {
+		err = json.NewDecoder(resp.Body).Decode(&r)
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("cannot decode vault response (status %d): %v", resp.StatusCode, err)
+		}
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("vault request %s %s failed with status %d: %s", method, path, resp.StatusCode, strings.Join(r.Errors, ", "))
+	}
+	return &r, nil
+}
+
+// login returns a valid token, logging in with the AppRole credentials if
+// there is no token yet or if it is about to expire. Needs to be called
+// with c.mu held.
+func (c *Client) login() (string, error) {
+	if c.roleID == "" {
+		return c.token, nil
+	}
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	r, err := c.request(http.MethodPost, "auth/approle/login", "", map[string]string{
+		"role_id":   c.roleID,
+		"secret_id": c.secretID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("vault AppRole login failed: %v", err)
+	}
+	if r.Auth == nil || r.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault AppRole login didn't return a token")
+	}
+
+	c.token = r.Auth.ClientToken
+	ttl := time.Duration(r.Auth.LeaseDuration) * time.Second
+	c.tokenExpiry = time.Now().Add(ttl - ttl/renewThreshold)
+	return c.token, nil
+}
+
+// Read returns the secret stored at the given path. Secrets are cached until
+// their lease is close to expiring, renewable leases are renewed instead of
+// reading the secret again. Secrets without a lease are cached for the TTL
+// of the client. Values of the KV version 2 engine are unwrapped,
+// so the data of the secret is returned in both cases.
+func (c *Client) Read(path string) (*Secret, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if secret, ok := c.secrets[path]; ok {
+		if !secret.needsRefresh(now) {
+			return secret, nil
+		}
+		if secret.Renewable && now.Before(secret.expiresAt()) {
+			err := c.renew(secret)
+			if err == nil && !secret.needsRefresh(time.Now()) {
+				return secret, nil
+			}
+			if err != nil {
+				logrus.Warnf("Renewing vault lease of %s failed, reading the secret again: %v", path, err)
+			}
+		}
+	}
+
+	token, err := c.login()
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.request(http.MethodGet, path, token, nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.Data == nil {
+		return nil, fmt.Errorf("vault secret %s has no data", path)
+	}
+
+	data := r.Data
+	// KV version 2 nests the secret in a data field next to its metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	secret := &Secret{
+		Data:          data,
+		LeaseID:       r.LeaseID,
+		LeaseDuration: time.Duration(r.LeaseDuration) * time.Second,
+		Renewable:     r.Renewable,
+		fetched:       now,
+		ttl:           c.secretTTL,
+	}
+	c.secrets[path] = secret
+	return secret, nil
+}
+
+// renew extends the lease of the secret. Needs to be called with c.mu held.
+func (c *Client) renew(secret *Secret) error {
+	token, err := c.login()
+	if err != nil {
+		return err
+	}
+	r, err := c.request(http.MethodPut, "sys/leases/renew", token, map[string]interface{}{
+		"lease_id":  secret.LeaseID,
+		"increment": int(secret.LeaseDuration.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	secret.fetched = time.Now()
+	secret.LeaseDuration = time.Duration(r.LeaseDuration) * time.Second
+	secret.Renewable = r.Renewable
+	return nil
+}
+
+// RenewLeases renews the leases of all the cached secrets which are close to
+// expiring. Secrets whose leases can't be renewed anymore are dropped from
+// the cache, so they are read again on the next access.
+func (c *Client) RenewLeases() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for path, secret := range c.secrets {
+		if !secret.needsRefresh(now) {
+			continue
+		}
+		if !secret.Renewable || now.After(secret.expiresAt()) {
+			delete(c.secrets, path)
+			continue
+		}
+		if err := c.renew(secret); err != nil {
+			logrus.Warnf("Renewing vault lease of %s failed: %v", path, err)
+			delete(c.secrets, path)
+		}
+	}
+}
+
+// RunRenewer renews the leases of the cached secrets in the given interval
+// until the context is canceled.
+func (c *Client) RunRenewer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.RenewLeases()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockVault struct {
+	mu       sync.Mutex
+	logins   int
+	reads    map[string]int
+	renewals int
+}
+
+func (m *mockVault) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if r.URL.Path == "/v1/auth/approle/login" {
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["role_id"] != "role" || body["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors": ["invalid role or secret ID"]}`))
+				return
+			}
+			m.logins++
+			_, _ = w.Write([]byte(`{"auth": {"client_token": "approle-token", "lease_duration": 3600}}`))
+			return
+		}
+
+		token := r.Header.Get("X-Vault-Token")
+		if token != "static-token" && token != "approle-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+
+		m.reads[r.URL.Path]++
+		switch r.URL.Path {
+		case "/v1/aws/creds/worker":
+			_, _ = w.Write([]byte(`{"lease_id": "aws/creds/worker/1", "lease_duration": 3600, "renewable": true, "data": {"access_key": "AKIA", "secret_key": "secret"}}`))
+		case "/v1/aws/creds/short":
+			_, _ = w.Write([]byte(`{"lease_id": "aws/creds/short/1", "lease_duration": 1, "renewable": false, "data": {"access_key": "AKIA", "secret_key": "secret"}}`))
+		case "/v1/secret/data/azure":
+			_, _ = w.Write([]byte(`{"data": {"data": {"client_id": "id", "client_secret": "secret"}, "metadata": {"version": 1}}}`))
+		case "/v1/sys/leases/renew":
+			m.renewals++
+			_, _ = w.Write([]byte(`{"lease_id": "aws/creds/worker/1", "lease_duration": 3600, "renewable": true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": []}`))
+		}
+	})
+}
+
+func newMockVault(t *testing.T) (*mockVault, *httptest.Server) {
+	m := &mockVault{reads: make(map[string]int)}
+	server := httptest.NewServer(m.handler(t))
+	t.Cleanup(server.Close)
+	return m, server
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(Config{Token: "token"})
+	require.Error(t, err)
+
+	_, err = NewClient(Config{Address: "https://vault.example.com", RoleID: "role"})
+	require.Error(t, err)
+
+	_, err = NewClient(Config{Address: "https://vault.example.com", CACertFile: "/non-existing/ca.pem", Token: "token"})
+	require.Error(t, err)
+
+	_, err = NewClient(Config{Address: "https://vault.example.com", Token: "token"})
+	require.NoError(t, err)
+}
+
+func TestReadCached(t *testing.T) {
+	m, server := newMockVault(t)
+
+	client, err := NewClient(Config{Address: server.URL, Token: "static-token"})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		secret, err := client.Read("aws/creds/worker")
+		require.NoError(t, err)
+		require.Equal(t, "AKIA", secret.String("access_key"))
+		require.Equal(t, "secret", secret.String("secret_key"))
+		require.Equal(t, "", secret.String("security_token"))
+	}
+	require.Equal(t, 1, m.reads["/v1/aws/creds/worker"])
+
+	// a lease which is about to expire is read again
+	for i := 0; i < 2; i++ {
+		_, err = client.Read("aws/creds/short")
+		require.NoError(t, err)
+		time.Sleep(time.Second)
+	}
+	require.Equal(t, 2, m.reads["/v1/aws/creds/short"])
+
+	_, err = client.Read("does/not/exist")
+	require.Error(t, err)
+}
+
+func TestReadKVv2(t *testing.T) {
+	_, server := newMockVault(t)
+
+	client, err := NewClient(Config{Address: server.URL, Token: "static-token"})
+	require.NoError(t, err)
+
+	secret, err := client.Read("secret/data/azure")
+	require.NoError(t, err)
+	require.Equal(t, "id", secret.String("client_id"))
+	require.Equal(t, "secret", secret.String("client_secret"))
+}
+
+func TestReadWithoutLease(t *testing.T) {
+	m, server := newMockVault(t)
+
+	client, err := NewClient(Config{Address: server.URL, Token: "static-token", SecretTTL: time.Hour})
+	require.NoError(t, err)
+
+	secret, err := client.Read("secret/data/azure")
+	require.NoError(t, err)
+	_, err = client.Read("secret/data/azure")
+	require.NoError(t, err)
+	require.Equal(t, 1, m.reads["/v1/secret/data/azure"])
+
+	// the secret is read again once it's cached for longer than the TTL,
+	// so rotated credentials are picked up
+	secret.fetched = time.Now().Add(-2 * time.Hour)
+	_, err = client.Read("secret/data/azure")
+	require.NoError(t, err)
+	require.Equal(t, 2, m.reads["/v1/secret/data/azure"])
+
+	// and the renewer drops it from the cache
+	secret, err = client.Read("secret/data/azure")
+	require.NoError(t, err)
+	secret.fetched = time.Now().Add(-2 * time.Hour)
+	client.RenewLeases()
+	require.Equal(t, 0, m.renewals)
+	_, err = client.Read("secret/data/azure")
+	require.NoError(t, err)
+	require.Equal(t, 3, m.reads["/v1/secret/data/azure"])
+}
+
+func TestAppRoleLogin(t *testing.T) {
+	m, server := newMockVault(t)
+
+	client, err := NewClient(Config{Address: server.URL, RoleID: "role", SecretID: "secret"})
+	require.NoError(t, err)
+
+	_, err = client.Read("aws/creds/worker")
+	require.NoError(t, err)
+	_, err = client.Read("secret/data/azure")
+	require.NoError(t, err)
+	require.Equal(t, 1, m.logins)
+
+	client, err = NewClient(Config{Address: server.URL, RoleID: "role", SecretID: "wrong"})
+	require.NoError(t, err)
+	_, err = client.Read("aws/creds/worker")
+	require.Error(t, err)
+}
+
+func TestRenewLeases(t *testing.T) {
+	m, server := newMockVault(t)
+
+	client, err := NewClient(Config{Address: server.URL, Token: "static-token"})
+	require.NoError(t, err)
+
+	secret, err := client.Read("aws/creds/worker")
+	require.NoError(t, err)
+
+	// nothing to renew yet
+	client.RenewLeases()
+	require.Equal(t, 0, m.renewals)
+
+	// pretend most of the lease is gone
+	secret.fetched = time.Now().Add(-50 * time.Minute)
+	client.RenewLeases()
+	require.Equal(t, 1, m.renewals)
+	require.False(t, secret.needsRefresh(time.Now()))
+
+	// the renewed secret is still served from the cache
+	_, err = client.Read("aws/creds/worker")
+	require.NoError(t, err)
+	require.Equal(t, 1, m.reads["/v1/aws/creds/worker"])
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	errors_package "errors"
@@ -23,6 +25,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -30,6 +33,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
 	"github.com/osbuild/osbuild-composer/pkg/jobqueue"
+	"golang.org/x/exp/slices"
 
 	"github.com/osbuild/images/pkg/container"
 	"github.com/osbuild/images/pkg/distro"
@@ -51,9 +55,18 @@ type API struct {
 	store   *store.Store
 	workers *worker.Server
 
-	solver       *dnfjson.BaseSolver
-	archName     string
-	repoRegistry *reporegistry.RepoRegistry
+	solver   *dnfjson.BaseSolver
+	archName string
+
+	// the repositories can be reloaded while the API is serving requests,
+	// the distros with repositories depend on them
+	repoPaths        []string
+	reposMu          sync.RWMutex
+	repoRegistry     *reporegistry.RepoRegistry
+	distros          []string // Supported distro names
+	reposFingerprint string
+	reposLoaded      time.Time
+	reposError       error
 
 	logger *log.Logger
 	router *httprouter.Router
@@ -63,10 +76,17 @@ type API struct {
 
 	hostDistroName string                   // Name of the host distro
 	distroRegistry *distroregistry.Registry // Available distros
-	distros        []string                 // Supported distro names
 
 	//  List of ImageType names, which should not be exposed by the API
 	distrosImageTypeDenylist map[string][]string
+
+	// key the settings of the upload profiles are encrypted with
+	uploadProfilesKey []byte
+
+	// the maximum number of composes building at the same time, the other
+	// ones are deferred until one of them finishes. 0 means no limit.
+	maxParallelComposes int
+	composeQueueMu      sync.Mutex
 }
 
 type ComposeState int
@@ -97,7 +117,7 @@ func (cs ComposeState) ToString() string {
 // systemRepoNames returns a list of the system repos
 // NOTE: The system repos have no concept of id vs. name so the id is returned
 func (api *API) systemRepoNames() (names []string) {
-	repos, err := api.repoRegistry.ReposByArchName(api.hostDistroName, api.archName, false)
+	repos, err := api.getRepoRegistry().ReposByArchName(api.hostDistroName, api.archName, false)
 	if err == nil {
 		for _, repo := range repos {
 			names = append(names, repo.Name)
@@ -124,6 +144,15 @@ func validDistros(rr *reporegistry.RepoRegistry, dr *distroregistry.Registry, ar
 
 var ValidBlueprintName = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
 
+// ValidCoprProject matches the URL of the "copr" sources, a COPR project
+var ValidCoprProject = regexp.MustCompile(`^@?[a-zA-Z0-9._-]+/[a-zA-Z0-9._+-]+$`)
+
+// ValidModuleStream matches the module streams of the sources, name:stream
+var ValidModuleStream = regexp.MustCompile(`^[a-zA-Z0-9._+-]+:[a-zA-Z0-9._+-]+$`)
+
+// ValidContainerTag matches the additional tags of container uploads
+var ValidContainerTag = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
 // NewTestAPI is used for the test framework, sets up a single distro
 func NewTestAPI(solver *dnfjson.BaseSolver, arch distro.Arch, dr *distroregistry.Registry,
 	rr *reporegistry.RepoRegistry, logger *log.Logger,
@@ -132,18 +161,23 @@ func NewTestAPI(solver *dnfjson.BaseSolver, arch distro.Arch, dr *distroregistry
 
 	// Use the first entry as the host distribution
 	hostDistro := dr.GetDistro(dr.List()[0])
+	uploadProfilesKey := make([]byte, 32)
+	_, err := rand.Read(uploadProfilesKey)
+	common.PanicOnError(err)
 	api := &API{
 		store:                    store,
 		workers:                  workers,
 		solver:                   solver,
 		archName:                 arch.Name(),
 		repoRegistry:             rr,
+		reposLoaded:              time.Now(),
 		logger:                   logger,
 		compatOutputDir:          compatOutputDir,
 		hostDistroName:           hostDistro.Name(),
 		distroRegistry:           dr,
 		distros:                  validDistros(rr, dr, arch.Name(), logger),
 		distrosImageTypeDenylist: distrosImageTypeDenylist,
+		uploadProfilesKey:        uploadProfilesKey,
 	}
 	return setupRouter(api)
 }
@@ -160,6 +194,10 @@ func New(repoPaths []string, stateDir string, solver *dnfjson.BaseSolver, dr *di
 	}
 	archName := common.CurrentArch()
 
+	reposFingerprint, err := repositoriesFingerprint(repoPaths)
+	if err != nil {
+		return nil, fmt.Errorf("error loading repository definitions: %v", err)
+	}
 	rr, err := reporegistry.New(repoPaths)
 	if err != nil {
 		return nil, fmt.Errorf("error loading repository definitions: %v", err)
@@ -188,23 +226,38 @@ func New(repoPaths []string, stateDir string, solver *dnfjson.BaseSolver, dr *di
 	store := store.New(&stateDir, dr, logger)
 	compatOutputDir := path.Join(stateDir, "outputs")
 
+	uploadProfilesKey, err := loadUploadProfilesKey(path.Join(stateDir, uploadProfilesKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("error loading the upload profiles key: %v", err)
+	}
+
 	api := &API{
 		store:                    store,
 		workers:                  workers,
 		solver:                   solver,
 		archName:                 archName,
+		repoPaths:                repoPaths,
 		repoRegistry:             rr,
+		reposFingerprint:         reposFingerprint,
+		reposLoaded:              time.Now(),
 		logger:                   logger,
 		compatOutputDir:          compatOutputDir,
 		hostDistroName:           hostDistroName,
 		distroRegistry:           dr,
 		distros:                  validDistros(rr, dr, archName, logger),
 		distrosImageTypeDenylist: distrosImageTypeDenylist,
+		uploadProfilesKey:        uploadProfilesKey,
 	}
 	return setupRouter(api), nil
 }
 
 func setupRouter(api *API) *API {
+	api.server = http.Server{
+		Handler:           api,
+		ReadHeaderTimeout: 5 * time.Second,
+		ConnContext:       connContext,
+	}
+
 	api.router = httprouter.New()
 	api.router.RedirectTrailingSlash = false
 	api.router.RedirectFixedPath = false
@@ -236,6 +289,7 @@ func setupRouter(api *API) *API {
 	api.router.GET("/api/v:version/blueprints/info/*blueprints", api.blueprintsInfoHandler)
 	api.router.GET("/api/v:version/blueprints/depsolve/*blueprints", api.blueprintsDepsolveHandler)
 	api.router.GET("/api/v:version/blueprints/freeze/*blueprints", api.blueprintsFreezeHandler)
+	api.router.GET("/api/v:version/blueprints/preview/:blueprint", api.blueprintsPreviewHandler)
 	api.router.GET("/api/v:version/blueprints/diff/:blueprint/:from/:to", api.blueprintsDiffHandler)
 	api.router.GET("/api/v:version/blueprints/change/:blueprint/:commit", api.blueprintsChangeHandler)
 	api.router.GET("/api/v:version/blueprints/changes/*blueprints", api.blueprintsChangesHandler)
@@ -245,6 +299,8 @@ func setupRouter(api *API) *API {
 	api.router.POST("/api/v:version/blueprints/tag/:blueprint", api.blueprintsTagHandler)
 	api.router.DELETE("/api/v:version/blueprints/delete/:blueprint", api.blueprintDeleteHandler)
 	api.router.DELETE("/api/v:version/blueprints/workspace/:blueprint", api.blueprintDeleteWorkspaceHandler)
+	api.router.GET("/api/v:version/blueprints/owner/:blueprint", api.blueprintsOwnerHandler)
+	api.router.POST("/api/v:version/blueprints/owner/:blueprint", api.blueprintsSetOwnerHandler)
 
 	api.router.POST("/api/v:version/compose", api.composeHandler)
 	api.router.DELETE("/api/v:version/compose/delete/:uuids", api.composeDeleteHandler)
@@ -255,12 +311,17 @@ func setupRouter(api *API) *API {
 	api.router.GET("/api/v:version/compose/finished", api.composeFinishedHandler)
 	api.router.GET("/api/v:version/compose/failed", api.composeFailedHandler)
 	api.router.GET("/api/v:version/compose/image/:uuid", api.composeImageHandler)
+	api.router.HEAD("/api/v:version/compose/image/:uuid", api.composeImageHandler)
 	api.router.GET("/api/v:version/compose/metadata/:uuid", api.composeMetadataHandler)
 	api.router.GET("/api/v:version/compose/results/:uuid", api.composeResultsHandler)
 	api.router.GET("/api/v:version/compose/logs/:uuid", api.composeLogsHandler)
 	api.router.GET("/api/v:version/compose/log/:uuid", api.composeLogHandler)
 	api.router.POST("/api/v:version/compose/uploads/schedule/:uuid", api.uploadsScheduleHandler)
 	api.router.DELETE("/api/v:version/compose/cancel/:uuid", api.composeCancelHandler)
+	api.router.POST("/api/v:version/compose/schedules/new", api.composeScheduleNewHandler)
+	api.router.GET("/api/v:version/compose/schedules/list", api.composeScheduleListHandler)
+	api.router.GET("/api/v:version/compose/schedules/info/:uuid", api.composeScheduleInfoHandler)
+	api.router.DELETE("/api/v:version/compose/schedules/delete/:uuid", api.composeScheduleDeleteHandler)
 
 	api.router.DELETE("/api/v:version/upload/delete/:uuid", api.uploadsDeleteHandler)
 	api.router.GET("/api/v:version/upload/info/:uuid", api.uploadsInfoHandler)
@@ -273,15 +334,15 @@ func setupRouter(api *API) *API {
 	api.router.DELETE("/api/v:version/upload/providers/delete/:provider/:profile", api.providersDeleteHandler)
 
 	api.router.GET("/api/v:version/distros/list", api.distrosListHandler)
+
+	api.router.GET("/api/v:version/repositories/status", api.repositoriesStatusHandler)
+	api.router.POST("/api/v:version/repositories/reload", api.repositoriesReloadHandler)
 	return api
 }
 
+// Serve serves the API on the listener until Shutdown is called. It can be
+// called for several listeners, e.g. the weldr socket and a TLS one.
 func (api *API) Serve(listener net.Listener) error {
-	api.server = http.Server{
-		Handler:           api,
-		ReadHeaderTimeout: 5 * time.Second,
-	}
-
 	err := api.server.Serve(listener)
 	if err != nil && err != http.ErrServerClosed {
 		return err
@@ -303,12 +364,18 @@ func (api *API) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	api.router.ServeHTTP(writer, request)
 }
 
+// EnableBlueprintsGit commits every change to the blueprints to the git
+// repository.
+func (api *API) EnableBlueprintsGit(config store.BlueprintsGitConfig) error {
+	return api.store.EnableBlueprintsGit(config, api.logger)
+}
+
 // PreloadMetadata loads the metadata for all supported distros
 // This starts a background depsolve for all known distros in order to preload the
 // metadata.
 func (api *API) PreloadMetadata() {
 	log.Printf("Starting metadata preload goroutines")
-	for _, distro := range api.distros {
+	for _, distro := range api.getDistros() {
 		go func(distro string) {
 			startTime := time.Now()
 			d := api.getDistro(distro)
@@ -437,6 +504,41 @@ func (api *API) openImageFile(composeId uuid.UUID, compose store.Compose) (io.Re
 	return reader, size, nil
 }
 
+// imageChecksum returns the SHA256 checksum of the image of the compose,
+// which the worker computed once the image was built. It's empty for the
+// images built before the workers computed it.
+func (api *API) imageChecksum(compose store.Compose) ([]byte, error) {
+	if compose.ImageBuild.JobID == uuid.Nil {
+		return nil, nil
+	}
+
+	var job worker.OSBuildJob
+	err := api.workers.OSBuildJob(compose.ImageBuild.JobID, &job)
+	if err != nil {
+		return nil, err
+	}
+	var result worker.OSBuildJobResult
+	_, err = api.workers.OSBuildJobInfo(compose.ImageBuild.JobID, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range job.Targets {
+		if t.Name != target.TargetNameWorkerServer {
+			continue
+		}
+		checksum, ok := result.ArtifactChecksums[path.Join(t.OsbuildArtifact.ExportName, t.OsbuildArtifact.ExportFilename)]
+		if !ok {
+			break
+		}
+		if !strings.HasPrefix(checksum, "sha256:") {
+			return nil, fmt.Errorf("unexpected checksum of the image: %s", checksum)
+		}
+		return hex.DecodeString(strings.TrimPrefix(checksum, "sha256:"))
+	}
+	return nil, nil
+}
+
 // isImageTypeAllowed checks the given ImageType and Distro names against
 // the distro-specific ImageType Denylist provided to the API from configuration.
 // If the given ImageType is not allowed the method returns an `false`.
@@ -470,6 +572,7 @@ func (api *API) isImageTypeAllowed(distroName, imageType string) (bool, error) {
 // This is necessary because different distros support different image types, and the image
 // type may have a different package set than other distros.
 func (api *API) getImageType(distroName, imageType string) (distro.ImageType, error) {
+	imageType = api.resolveImageTypeAlias(distroName, imageType)
 	imgAllowed, err := api.isImageTypeAllowed(distroName, imageType)
 	if err != nil {
 		return nil, fmt.Errorf("error while checking if image type is allowed: %v", err)
@@ -491,7 +594,7 @@ func (api *API) getImageType(distroName, imageType string) (distro.ImageType, er
 
 func (api *API) parseDistro(query url.Values) (string, error) {
 	if distro := query.Get("distro"); distro != "" {
-		if common.IsStringInSortedSlice(api.distros, distro) {
+		if common.IsStringInSortedSlice(api.getDistros(), distro) {
 			return distro, nil
 		}
 		return "", errors_package.New("Invalid distro: " + distro)
@@ -502,7 +605,7 @@ func (api *API) parseDistro(query url.Values) (string, error) {
 // getDistro returns the named distro or nil
 // It excludes unsupported distros by first checking the api.distros list
 func (api *API) getDistro(name string) distro.Distro {
-	if !common.IsStringInSortedSlice(api.distros, name) {
+	if !common.IsStringInSortedSlice(api.getDistros(), name) {
 		return nil
 	}
 	return api.distroRegistry.GetDistro(name)
@@ -664,7 +767,7 @@ func (api *API) getSourceConfigs(params httprouter.Params) (map[string]store.Sou
 	sources := map[string]store.SourceConfig{}
 	errors := []responseError{}
 
-	repos, err := api.repoRegistry.ReposByArchName(api.hostDistroName, api.archName, false)
+	repos, err := api.getRepoRegistry().ReposByArchName(api.hostDistroName, api.archName, false)
 	if err != nil {
 		error := responseError{
 			ID:  "InternalError",
@@ -885,6 +988,27 @@ func DecodeSourceConfigV1(body io.Reader, contentType string) (source SourceConf
 	return source, err
 }
 
+// checkSourceConfig validates the COPR project and the module streams of the
+// source.
+func checkSourceConfig(source store.SourceConfig) error {
+	if source.Type == "copr" && !ValidCoprProject.MatchString(source.URL) {
+		return fmt.Errorf("'url' of a copr source must be owner/project or @group/project, not %q", source.URL)
+	}
+
+	streams := make(map[string]string)
+	for _, moduleStream := range source.ModuleStreams {
+		if !ValidModuleStream.MatchString(moduleStream) {
+			return fmt.Errorf("invalid module stream %q, it must be name:stream", moduleStream)
+		}
+		name, stream, _ := strings.Cut(moduleStream, ":")
+		if other, exists := streams[name]; exists && other != stream {
+			return fmt.Errorf("more than one stream of module %s: %s and %s", name, other, stream)
+		}
+		streams[name] = stream
+	}
+	return nil
+}
+
 func (api *API) sourceNewHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	if !verifyRequestVersion(writer, params, 0) { // TODO: version 1 API
 		return
@@ -925,6 +1049,8 @@ func (api *API) sourceNewHandler(writer http.ResponseWriter, request *http.Reque
 			err = errors_package.New("'type' field is missing from request")
 		} else if len(source.SourceConfig().URL) == 0 {
 			err = errors_package.New("'url' field is missing from request")
+		} else {
+			err = checkSourceConfig(source.SourceConfig())
 		}
 	}
 	if err != nil {
@@ -939,7 +1065,7 @@ func (api *API) sourceNewHandler(writer http.ResponseWriter, request *http.Reque
 	// If there is a list of distros, check to make sure they are valid
 	invalid := []string{}
 	for _, d := range source.SourceConfig().Distros {
-		if !common.IsStringInSortedSlice(api.distros, d) {
+		if !common.IsStringInSortedSlice(api.getDistros(), d) {
 			invalid = append(invalid, d)
 		}
 	}
@@ -1269,7 +1395,7 @@ func (api *API) modulesInfoHandler(writer http.ResponseWriter, request *http.Req
 			return
 		}
 
-		solver := api.solver.NewWithConfig(d.ModulePlatformID(), d.Releasever(), api.archName, d.Name())
+		solver := api.newSolver(d)
 		for i := range packageInfos {
 			pkgName := packageInfos[i].Name
 			solved, err := solver.Depsolve([]rpmmd.PackageSet{{Include: []string{pkgName}, Repositories: repos}})
@@ -1353,7 +1479,7 @@ func (api *API) projectsDepsolveHandler(writer http.ResponseWriter, request *htt
 		return
 	}
 
-	solver := api.solver.NewWithConfig(d.ModulePlatformID(), d.Releasever(), api.archName, d.Name())
+	solver := api.newSolver(d)
 	deps, err := solver.Depsolve([]rpmmd.PackageSet{{Include: names, Repositories: repos}})
 	if err != nil {
 		errors := responseError{
@@ -1393,7 +1519,12 @@ func (api *API) blueprintsListHandler(writer http.ResponseWriter, request *http.
 		return
 	}
 
-	names := api.store.ListBlueprints()
+	names := []string{}
+	for _, name := range api.store.ListBlueprints() {
+		if api.canReadBlueprint(request, name) {
+			names = append(names, name)
+		}
+	}
 	total := uint(len(names))
 	offset = min(offset, total)
 	limit = min(limit, total-offset)
@@ -1439,6 +1570,9 @@ func (api *API) blueprintsInfoHandler(writer http.ResponseWriter, request *http.
 	if !verifyStringsWithRegex(writer, names, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintsReadable(writer, request, names) {
+		return
+	}
 
 	query, err := url.ParseQuery(request.URL.RawQuery)
 	if err != nil {
@@ -1536,6 +1670,9 @@ func (api *API) blueprintsDepsolveHandler(writer http.ResponseWriter, request *h
 	if !verifyStringsWithRegex(writer, names, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintsReadable(writer, request, names) {
+		return
+	}
 
 	blueprints := []entry{}
 	blueprintsErrors := []responseError{}
@@ -1569,6 +1706,121 @@ func (api *API) blueprintsDepsolveHandler(writer http.ResponseWriter, request *h
 	common.PanicOnError(err)
 }
 
+// blueprintsPreviewHandler depsolves the blueprint for another distribution
+// or architecture than its own, e.g. before changing its distro. The
+// packages which would change compared to its own distro on the host
+// architecture are listed too.
+func (api *API) blueprintsPreviewHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type reply struct {
+		Blueprint    blueprint.Blueprint `json:"blueprint"`
+		Distro       string              `json:"distro"`
+		Arch         string              `json:"arch"`
+		Dependencies []rpmmd.PackageSpec `json:"dependencies"`
+		Changes      *packagesDiff       `json:"changes,omitempty"`
+		Errors       []responseError     `json:"errors"`
+	}
+
+	name := params.ByName("blueprint")
+	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
+		return
+	}
+	if !api.verifyBlueprintsReadable(writer, request, []string{name}) {
+		return
+	}
+	bp, _ := api.store.GetBlueprint(name)
+	if bp == nil {
+		errors := responseError{
+			ID:  "UnknownBlueprint",
+			Msg: fmt.Sprintf("Unknown blueprint name: %s", name),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	currentDistro := bp.Distro
+	if currentDistro == "" {
+		currentDistro = api.hostDistroName
+	}
+	distroName := request.URL.Query().Get("distro")
+	if distroName == "" {
+		distroName = currentDistro
+	}
+	d := api.getDistro(distroName)
+	if d == nil {
+		errors := responseError{
+			ID:  "DistroError",
+			Msg: fmt.Sprintf("Unknown distribution: %s", distroName),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+	arch := request.URL.Query().Get("arch")
+	if arch == "" {
+		arch = api.archName
+	}
+	if _, err := d.GetArch(arch); err != nil {
+		errors := responseError{
+			ID:  "DistroError",
+			Msg: fmt.Sprintf("Unknown arch: %s", arch),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	preview := *bp
+	preview.Distro = distroName
+	dependencies, err := api.depsolveBlueprintArch(preview, arch)
+	if err != nil {
+		errors := responseError{
+			ID:  "BlueprintsError",
+			Msg: fmt.Sprintf("%s: %s", name, err.Error()),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	r := reply{
+		Blueprint:    preview,
+		Distro:       distroName,
+		Arch:         arch,
+		Dependencies: dependencies,
+		Errors:       []responseError{},
+	}
+	if distroName != currentDistro || arch != api.archName {
+		current, err := api.depsolveBlueprint(*bp)
+		if err != nil {
+			r.Errors = append(r.Errors, responseError{
+				ID:  "BlueprintsError",
+				Msg: fmt.Sprintf("%s: cannot depsolve for %s: %s", name, currentDistro, err.Error()),
+			})
+		} else {
+			changes := diffPackages(packageVersions(current), packageVersions(dependencies))
+			r.Changes = &changes
+		}
+	}
+
+	err = json.NewEncoder(writer).Encode(r)
+	common.PanicOnError(err)
+}
+
+// packageVersions returns the packages with their epoch, version and
+// release, without the architecture so they can be compared across them.
+func packageVersions(specs []rpmmd.PackageSpec) []blueprint.Package {
+	packages := make([]blueprint.Package, 0, len(specs))
+	for _, spec := range specs {
+		version := fmt.Sprintf("%s-%s", spec.Version, spec.Release)
+		if spec.Epoch != 0 {
+			version = fmt.Sprintf("%d:%s", spec.Epoch, version)
+		}
+		packages = append(packages, blueprint.Package{Name: spec.Name, Version: version})
+	}
+	return packages
+}
+
 // expandBlueprintGlobs will expand package name globs and versions using the depsolve results
 // The result is a sorted list of Package structs with the full package name and version
 // It will return an error if it cannot find a non-glob package name in the dependency list
@@ -1650,6 +1902,9 @@ func (api *API) blueprintsFreezeHandler(writer http.ResponseWriter, request *htt
 	if !verifyStringsWithRegex(writer, names, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintsReadable(writer, request, names) {
+		return
+	}
 
 	blueprints := []blueprintFrozen{}
 	errors := []responseError{}
@@ -1756,10 +2011,29 @@ func (api *API) blueprintsDiffHandler(writer http.ResponseWriter, request *http.
 		Diffs []diff `json:"diff"`
 	}
 
+	type structuredReply struct {
+		From string        `json:"from"`
+		To   string        `json:"to"`
+		Diff blueprintDiff `json:"diff"`
+	}
+
+	format := request.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "structured" {
+		errors := responseError{
+			ID:  "InvalidChars",
+			Msg: fmt.Sprintf("invalid format parameter: %s", format),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
 	name := params.ByName("blueprint")
 	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintsReadable(writer, request, []string{name}) {
+		return
+	}
 
 	fromCommit := params.ByName("from")
 	if !verifyStringsWithRegex(writer, []string{fromCommit}, ValidBlueprintName) {
@@ -1809,6 +2083,16 @@ func (api *API) blueprintsDiffHandler(writer http.ResponseWriter, request *http.
 		return
 	}
 
+	if format == "structured" {
+		err := json.NewEncoder(writer).Encode(structuredReply{
+			From: fromCommit,
+			To:   toCommit,
+			Diff: diffBlueprints(oldBlueprint, newBlueprint),
+		})
+		common.PanicOnError(err)
+		return
+	}
+
 	newSlice := newBlueprint.Packages
 	oldMap := make(map[string]blueprint.Package)
 	diffs := []diff{}
@@ -1850,6 +2134,9 @@ func (api *API) blueprintsChangeHandler(writer http.ResponseWriter, request *htt
 	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintsReadable(writer, request, []string{name}) {
+		return
+	}
 
 	commit := params.ByName("commit")
 	if !verifyStringsWithRegex(writer, []string{commit}, ValidBlueprintName) {
@@ -1940,6 +2227,9 @@ func (api *API) blueprintsChangesHandler(writer http.ResponseWriter, request *ht
 	if !verifyStringsWithRegex(writer, names, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintsReadable(writer, request, names) {
+		return
+	}
 
 	offset, limit, err := parseOffsetAndLimit(request.URL.Query())
 	if err != nil {
@@ -2031,10 +2321,15 @@ func (api *API) blueprintsNewHandler(writer http.ResponseWriter, request *http.R
 	if !verifyStringsWithRegex(writer, []string{blueprint.Name}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintWritable(writer, request, blueprint.Name) {
+		return
+	}
+	bp, _ := api.store.GetBlueprint(blueprint.Name)
+	created := bp == nil
 
 	// Check the blueprint's distro to make sure it is valid
 	if len(blueprint.Distro) > 0 {
-		if !common.IsStringInSortedSlice(api.distros, blueprint.Distro) {
+		if !common.IsStringInSortedSlice(api.getDistros(), blueprint.Distro) {
 			errors := responseError{
 				ID:  "BlueprintsError",
 				Msg: fmt.Sprintf("'%s' is not a valid distribution", blueprint.Distro),
@@ -2057,6 +2352,9 @@ func (api *API) blueprintsNewHandler(writer http.ResponseWriter, request *http.R
 
 	commitMsg := "Recipe " + blueprint.Name + ", version " + blueprint.Version + " saved."
 	err = api.store.PushBlueprint(blueprint, commitMsg)
+	if err == nil && created {
+		err = api.setNewBlueprintOwner(request, blueprint.Name)
+	}
 	if err != nil {
 		errors := responseError{
 			ID:  "BlueprintsError",
@@ -2115,8 +2413,16 @@ func (api *API) blueprintsWorkspaceHandler(writer http.ResponseWriter, request *
 	if !verifyStringsWithRegex(writer, []string{blueprint.Name}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintWritable(writer, request, blueprint.Name) {
+		return
+	}
+	bp, _ := api.store.GetBlueprint(blueprint.Name)
+	created := bp == nil
 
 	err = api.store.PushBlueprintToWorkspace(blueprint)
+	if err == nil && created {
+		err = api.setNewBlueprintOwner(request, blueprint.Name)
+	}
 	if err != nil {
 		errors := responseError{
 			ID:  "BlueprintsError",
@@ -2138,6 +2444,9 @@ func (api *API) blueprintUndoHandler(writer http.ResponseWriter, request *http.R
 	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintWritable(writer, request, name) {
+		return
+	}
 
 	commit := params.ByName("commit")
 	if !verifyStringsWithRegex(writer, []string{commit}, ValidBlueprintName) {
@@ -2186,6 +2495,9 @@ func (api *API) blueprintDeleteHandler(writer http.ResponseWriter, request *http
 	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintWritable(writer, request, name) {
+		return
+	}
 
 	if err := api.store.DeleteBlueprint(name); err != nil {
 		errors := responseError{
@@ -2207,6 +2519,9 @@ func (api *API) blueprintDeleteWorkspaceHandler(writer http.ResponseWriter, requ
 	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintWritable(writer, request, name) {
+		return
+	}
 
 	if err := api.store.DeleteBlueprintFromWorkspace(name); err != nil {
 		errors := responseError{
@@ -2229,6 +2544,9 @@ func (api *API) blueprintsTagHandler(writer http.ResponseWriter, request *http.R
 	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintWritable(writer, request, name) {
+		return
+	}
 
 	err := api.store.TagBlueprint(name)
 	if err != nil {
@@ -2243,16 +2561,28 @@ func (api *API) blueprintsTagHandler(writer http.ResponseWriter, request *http.R
 }
 
 // depsolve handles depsolving package sets required for serializing a manifest for a given distribution.
-func (api *API) depsolve(packageSets map[string][]rpmmd.PackageSet, distro distro.Distro) (map[string][]rpmmd.PackageSpec, error) {
+// The package sets named in noWeakDeps are depsolved without weak dependencies.
+// The module streams of the sources are enabled for the payloadSets.
+func (api *API) depsolve(packageSets map[string][]rpmmd.PackageSet, distro distro.Distro, payloadSets, noWeakDeps []string) (map[string][]rpmmd.PackageSpec, error) {
 
 	platformID := distro.ModulePlatformID()
 	releasever := distro.Releasever()
 	distroName := distro.Name()
 	solver := api.solver.NewWithConfig(platformID, releasever, api.archName, distroName)
+	hotfixes, streams := api.sourceModules(distroName)
+	solver.SetModuleHotfixes(hotfixes)
 
 	depsolvedSets := make(map[string][]rpmmd.PackageSpec, len(packageSets))
 
 	for name, pkgSet := range packageSets {
+		solver.SetInstallWeakDeps(!slices.Contains(noWeakDeps, name))
+		// the module streams of the sources are only enabled for the
+		// payload, the sources aren't used for the build root
+		if slices.Contains(payloadSets, name) {
+			solver.SetModules(streams, nil)
+		} else {
+			solver.SetModules(nil, nil)
+		}
 		res, err := solver.Depsolve(pkgSet)
 		if err != nil {
 			return nil, err
@@ -2368,15 +2698,6 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		return
 	}
 
-	// https://weldr.io/lorax/pylorax.api.html#pylorax.api.v0.v0_compose_start
-	type ComposeRequest struct {
-		BlueprintName string              `json:"blueprint_name"`
-		ComposeType   string              `json:"compose_type"`
-		Size          uint64              `json:"size"`
-		OSTree        ostree.ImageOptions `json:"ostree"`
-		Branch        string              `json:"branch"`
-		Upload        *uploadRequest      `json:"upload"`
-	}
 	type ComposeReply struct {
 		BuildID  uuid.UUID `json:"build_id"`
 		Status   bool      `json:"status"`
@@ -2393,7 +2714,7 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		return
 	}
 
-	var cr ComposeRequest
+	var cr composeRequest
 	err := json.NewDecoder(request.Body).Decode(&cr)
 	if err != nil {
 		errors := responseError{
@@ -2408,39 +2729,94 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 	if !verifyStringsWithRegex(writer, []string{cr.BlueprintName}, ValidBlueprintName) {
 		return
 	}
+	if !api.verifyBlueprintsReadable(writer, request, []string{cr.BlueprintName}) {
+		return
+	}
 
-	bp := api.store.GetBlueprintCommitted(cr.BlueprintName)
-	if bp == nil {
+	// Check for test parameter
+	q, err := url.ParseQuery(request.URL.RawQuery)
+	if err != nil {
 		errors := responseError{
-			ID:  "UnknownBlueprint",
-			Msg: fmt.Sprintf("Unknown blueprint name: %s", cr.BlueprintName),
+			ID:  "InvalidChars",
+			Msg: fmt.Sprintf("invalid query string: %v", err),
 		}
 		statusResponseError(writer, http.StatusBadRequest, errors)
 		return
 	}
 
+	composeID, warnings, composeErr := api.startCompose(cr, isRequestVersionAtLeast(params, 1), q.Get("test"))
+	if composeErr != nil {
+		statusResponseError(writer, composeErr.status, composeErr.err)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(ComposeReply{
+		BuildID:  composeID,
+		Status:   true,
+		Warnings: warnings,
+	})
+	common.PanicOnError(err)
+}
+
+// composeRequest is the request starting a compose.
+// https://weldr.io/lorax/pylorax.api.html#pylorax.api.v0.v0_compose_start
+type composeRequest struct {
+	BlueprintName string              `json:"blueprint_name"`
+	ComposeType   string              `json:"compose_type"`
+	Size          uint64              `json:"size"`
+	OSTree        ostree.ImageOptions `json:"ostree"`
+	Branch        string              `json:"branch"`
+	Upload        *uploadRequest      `json:"upload"`
+	// Priority composes are started before the other deferred ones
+	Priority bool `json:"priority,omitempty"`
+}
+
+// composeError is an error starting a compose, with the status of the
+// reply.
+type composeError struct {
+	status int
+	err    responseError
+}
+
+// startCompose translates the blueprint of the request into a manifest and
+// queues its build, the image is uploaded if upload is set. The test mode
+// 1 creates a failed compose and 2 a finished one, without building them.
+func (api *API) startCompose(cr composeRequest, upload bool, testMode string) (uuid.UUID, []string, *composeError) {
+	bp := api.store.GetBlueprintCommitted(cr.BlueprintName)
+	if bp == nil {
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusBadRequest,
+			err: responseError{
+				ID:  "UnknownBlueprint",
+				Msg: fmt.Sprintf("Unknown blueprint name: %s", cr.BlueprintName),
+			},
+		}
+	}
+
 	distroName := bp.Distro
 	if distroName == "" {
 		distroName = api.hostDistroName
 	}
 	if api.getDistro(distroName) == nil {
-		errors := responseError{
-			ID:  "DistroError",
-			Msg: fmt.Sprintf("Unknown distribution: %s", distroName),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusBadRequest,
+			err: responseError{
+				ID:  "DistroError",
+				Msg: fmt.Sprintf("Unknown distribution: %s", distroName),
+			},
 		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
 	}
 
 	// Get the imageType that corresponds to the distribution selected by the blueprint
 	imageType, err := api.getImageType(distroName, cr.ComposeType)
 	if err != nil {
-		errors := responseError{
-			ID:  "ComposeError",
-			Msg: fmt.Sprintf("Failed to get compose type %q: %v", cr.ComposeType, err),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusBadRequest,
+			err: responseError{
+				ID:  "ComposeError",
+				Msg: fmt.Sprintf("Failed to get compose type %q: %v", cr.ComposeType, err),
+			},
 		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
 	}
 
 	composeID := uuid.New()
@@ -2452,20 +2828,34 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 	workerServerTarget.OsbuildArtifact.ExportFilename = imageType.Filename()
 	workerServerTarget.OsbuildArtifact.ExportName = imageType.Exports()[0]
 	targets = append(targets, workerServerTarget)
-	if isRequestVersionAtLeast(params, 1) && cr.Upload != nil {
-		t := uploadRequestToTarget(*cr.Upload, imageType)
-		targets = append(targets, t)
-	}
-
-	// Check for test parameter
-	q, err := url.ParseQuery(request.URL.RawQuery)
-	if err != nil {
-		errors := responseError{
-			ID:  "InvalidChars",
-			Msg: fmt.Sprintf("invalid query string: %v", err),
+	if upload && cr.Upload != nil {
+		u := *cr.Upload
+		if u.Profile != "" {
+			u.Settings, err = api.uploadProfileSettings(u.Provider, u.Profile)
+			if err != nil {
+				return uuid.Nil, nil, &composeError{
+					status: http.StatusBadRequest,
+					err: responseError{
+						ID:  "UnknownProfile",
+						Msg: err.Error(),
+					},
+				}
+			}
 		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
+		if settings, ok := u.Settings.(*containerUploadSettings); ok {
+			err = checkContainerUpload(u.ImageName, settings)
+			if err != nil {
+				return uuid.Nil, nil, &composeError{
+					status: http.StatusBadRequest,
+					err: responseError{
+						ID:  "UploadError",
+						Msg: err.Error(),
+					},
+				}
+			}
+		}
+		t := uploadRequestToTarget(u, imageType)
+		targets = append(targets, t)
 	}
 
 	var size uint64
@@ -2488,12 +2878,13 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 	// Get the partitioning mode
 	pm, err := bp.Customizations.GetPartitioningMode()
 	if err != nil {
-		errors := responseError{
-			ID:  "BlueprintsError",
-			Msg: err.Error(),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusBadRequest,
+			err: responseError{
+				ID:  "BlueprintsError",
+				Msg: err.Error(),
+			},
 		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
 	}
 
 	options := distro.ImageOptions{
@@ -2507,65 +2898,76 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 
 	imageRepos, err := api.allRepositoriesByImageType(imageType)
 	if err != nil {
-		errors := responseError{
-			ID:  "InternalError",
-			Msg: err.Error(),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusInternalServerError,
+			err: responseError{
+				ID:  "InternalError",
+				Msg: err.Error(),
+			},
 		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
 	}
 
 	ibp := blueprint.Convert(*bp)
 	manifest, warnings, err := imageType.Manifest(&ibp, options, imageRepos, seed)
 	if err != nil {
-		errors := responseError{
-			ID:  "ManifestCreationFailed",
-			Msg: fmt.Sprintf("failed to initialize osbuild manifest: %v", err),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusBadRequest,
+			err: responseError{
+				ID:  "ManifestCreationFailed",
+				Msg: fmt.Sprintf("failed to initialize osbuild manifest: %v", err),
+			},
 		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
+	}
+	if imageType.Name() != cr.ComposeType {
+		warnings = append(warnings, imageTypeDeprecation(cr.ComposeType, imageType.Name()))
 	}
 
-	packageSets, err := api.depsolve(manifest.GetPackageSetChains(), imageType.Arch().Distro())
+	var noWeakDeps []string
+	if bp.Minimal {
+		noWeakDeps = imageType.PayloadPipelines()
+	}
+	packageSets, err := api.depsolve(manifest.GetPackageSetChains(), imageType.Arch().Distro(), imageType.PayloadPackageSets(), noWeakDeps)
 	if err != nil {
-		errors := responseError{
-			ID:  "DepsolveError",
-			Msg: err.Error(),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusInternalServerError,
+			err: responseError{
+				ID:  "DepsolveError",
+				Msg: err.Error(),
+			},
 		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
 	}
 
 	containerSpecs, err := api.resolveContainers(manifest.GetContainerSourceSpecs())
 	if err != nil {
-		errors := responseError{
-			ID:  "ContainerResolveError",
-			Msg: err.Error(),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusInternalServerError,
+			err: responseError{
+				ID:  "ContainerResolveError",
+				Msg: err.Error(),
+			},
 		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
 	}
 
-	testMode := q.Get("test")
-
 	ostreeCommitSpecs, err := api.resolveOSTreeCommits(manifest.GetOSTreeSourceSpecs(), testMode == "1" || testMode == "2")
 	if err != nil {
-		errors := responseError{
-			ID:  "OSTreeOptionsError",
-			Msg: err.Error(),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusBadRequest,
+			err: responseError{
+				ID:  "OSTreeOptionsError",
+				Msg: err.Error(),
+			},
 		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
 	}
 
 	mf, err := manifest.Serialize(packageSets, containerSpecs, ostreeCommitSpecs)
 	if err != nil {
-		errors := responseError{
-			ID:  "ManifestCreationFailed",
-			Msg: fmt.Sprintf("failed to serialize osbuild manifest: %v", err),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusBadRequest,
+			err: responseError{
+				ID:  "ManifestCreationFailed",
+				Msg: fmt.Sprintf("failed to serialize osbuild manifest: %v", err),
+			},
 		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
 	}
 
 	var packages []rpmmd.PackageSpec
@@ -2584,19 +2986,18 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		// Create a successful compose
 		err = api.store.PushTestCompose(composeID, mf, imageType, bp, size, targets, true, packages)
 	} else {
-		var jobId uuid.UUID
-
-		jobId, err = api.workers.EnqueueOSBuild(api.archName, &worker.OSBuildJob{
-			Manifest: mf,
-			Targets:  targets,
-			PipelineNames: &worker.PipelineNames{
-				Build:   imageType.BuildPipelines(),
-				Payload: imageType.PayloadPipelines(),
-			},
-			ImageBootMode: imageType.BootMode().String(),
-		}, "")
-		if err == nil {
-			err = api.store.PushCompose(composeID, mf, imageType, bp, size, targets, jobId, packages)
+		if api.maxParallelComposes > 0 {
+			err = api.store.PushDeferredCompose(composeID, mf, imageType, bp, size, targets, cr.Priority, packages)
+			if err == nil {
+				api.dispatchComposes()
+			}
+		} else {
+			var jobId uuid.UUID
+
+			jobId, err = api.enqueueCompose(mf, imageType, targets)
+			if err == nil {
+				err = api.store.PushCompose(composeID, mf, imageType, bp, size, targets, jobId, packages)
+			}
 		}
 	}
 
@@ -2604,20 +3005,16 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 	// for now, let's just 500 and bail out
 	if err != nil {
 		log.Println("error when pushing new compose: ", err.Error())
-		errors := responseError{
-			ID:  "ComposePushErrored",
-			Msg: err.Error(),
+		return uuid.Nil, nil, &composeError{
+			status: http.StatusInternalServerError,
+			err: responseError{
+				ID:  "ComposePushErrored",
+				Msg: err.Error(),
+			},
 		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
 	}
 
-	err = json.NewEncoder(writer).Encode(ComposeReply{
-		BuildID:  composeID,
-		Status:   true,
-		Warnings: warnings,
-	})
-	common.PanicOnError(err)
+	return composeID, warnings, nil
 }
 
 func (api *API) composeDeleteHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -2749,7 +3146,7 @@ func (api *API) composeCancelHandler(writer http.ResponseWriter, request *http.R
 		return
 	}
 
-	err = api.workers.Cancel(compose.ImageBuild.JobID)
+	err = api.cancelCompose(id)
 	if err != nil {
 		errors := responseError{
 			ID:  "InternalServerError",
@@ -2758,6 +3155,8 @@ func (api *API) composeCancelHandler(writer http.ResponseWriter, request *http.R
 		statusResponseError(writer, http.StatusBadRequest, errors)
 		return
 	}
+	// the canceled compose doesn't count against the limit anymore
+	api.dispatchComposes()
 
 	reply := CancelComposeStatusV0{id, true}
 	_ = json.NewEncoder(writer).Encode(reply)
@@ -2803,6 +3202,11 @@ func (api *API) composeTypesHandler(writer http.ResponseWriter, request *http.Re
 	type composeType struct {
 		Name    string `json:"name"`
 		Enabled bool   `json:"enabled"`
+		// the deprecated names of the image types are listed with
+		// their current names
+		Deprecated  bool   `json:"deprecated,omitempty"`
+		ReplacedBy  string `json:"replaced_by,omitempty"`
+		Deprecation string `json:"deprecation,omitempty"`
 	}
 
 	var reply struct {
@@ -2822,9 +3226,30 @@ func (api *API) composeTypesHandler(writer http.ResponseWriter, request *http.Re
 		if !imgAllowed {
 			continue
 		}
-		reply.Types = append(reply.Types, composeType{format, true})
+		reply.Types = append(reply.Types, composeType{Name: format, Enabled: true})
 	}
 
+	var aliases []composeType
+	for alias, name := range imageTypeAliases {
+		if api.resolveImageTypeAlias(distroName, alias) != name {
+			continue
+		}
+		for _, t := range reply.Types {
+			if t.Name == name {
+				aliases = append(aliases, composeType{
+					Name:        alias,
+					Enabled:     true,
+					Deprecated:  true,
+					ReplacedBy:  name,
+					Deprecation: imageTypeDeprecation(alias, name),
+				})
+				break
+			}
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Name < aliases[j].Name })
+	reply.Types = append(reply.Types, aliases...)
+
 	err = json.NewEncoder(writer).Encode(reply)
 	common.PanicOnError(err)
 }
@@ -3085,12 +3510,48 @@ func (api *API) composeImageHandler(writer http.ResponseWriter, request *http.Re
 		return
 	}
 
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// the artifacts are files, which can be downloaded in parts and resumed
+	image, seekable := reader.(io.ReadSeeker)
+	if !seekable {
+		writer.Header().Set("Content-Disposition", "attachment; filename="+uuid.String()+"-"+imageName)
+		writer.Header().Set("Content-Type", imageMime)
+		writer.Header().Set("Content-Length", fmt.Sprintf("%d", fileSize))
+
+		_, err = io.Copy(writer, reader)
+		common.PanicOnError(err)
+		return
+	}
+
+	checksum, err := api.imageChecksum(compose)
+	if err != nil {
+		errors := responseError{
+			ID:  "InternalServerError",
+			Msg: fmt.Sprintf("Error reading image file for compose %s: %v", uuid, err),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+	var modtime time.Time
+	if f, ok := reader.(*os.File); ok {
+		info, err := f.Stat()
+		if err == nil {
+			modtime = info.ModTime()
+		}
+	}
+
 	writer.Header().Set("Content-Disposition", "attachment; filename="+uuid.String()+"-"+imageName)
 	writer.Header().Set("Content-Type", imageMime)
-	writer.Header().Set("Content-Length", fmt.Sprintf("%d", fileSize))
-
-	_, err = io.Copy(writer, reader)
-	common.PanicOnError(err)
+	// the checksum of the whole image, also of the responses to range
+	// requests, which are only served if it didn't change since If-Range
+	if checksum != nil {
+		writer.Header().Set("ETag", fmt.Sprintf(`"%x"`, checksum))
+		writer.Header().Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(checksum)+":")
+	}
+	http.ServeContent(writer, request, "", modtime, image)
 }
 
 // composeMetadataHandler returns a tar of the metadata used to compose the requested UUID
@@ -3452,7 +3913,7 @@ func (api *API) fetchPackageList(distroName string, names []string) (packages rp
 		return nil, err
 	}
 
-	solver := api.solver.NewWithConfig(d.ModulePlatformID(), d.Releasever(), api.archName, d.Name())
+	solver := api.newSolver(d)
 	if len(names) == 0 {
 		packages, err = solver.FetchMetadata(repos)
 	} else {
@@ -3474,11 +3935,46 @@ func (api *API) payloadRepositories(distroName string) []rpmmd.RepoConfig {
 	distroSourceConfigs := api.store.GetAllDistroSources(distroName)
 	payloadRepos := make([]rpmmd.RepoConfig, 0, len(distroSourceConfigs))
 	for id, source := range distroSourceConfigs {
-		payloadRepos = append(payloadRepos, source.RepoConfig(id))
+		payloadRepos = append(payloadRepos, source.DistroRepoConfig(id, distroName))
 	}
 	return payloadRepos
 }
 
+// sourceModules returns the repositories of the sources of the distribution
+// which are module hotfixes and the module streams the sources enable.
+func (api *API) sourceModules(distroName string) ([]rpmmd.RepoConfig, []string) {
+	var hotfixes []rpmmd.RepoConfig
+	var streams []string
+	for id, source := range api.store.GetAllDistroSources(distroName) {
+		if source.ModuleHotfixes {
+			hotfixes = append(hotfixes, source.DistroRepoConfig(id, distroName))
+		}
+		for _, stream := range source.ModuleStreams {
+			if !slices.Contains(streams, stream) {
+				streams = append(streams, stream)
+			}
+		}
+	}
+	sort.Strings(streams)
+	return hotfixes, streams
+}
+
+// newSolver returns a solver for the distribution, with the module settings
+// of its sources.
+func (api *API) newSolver(d distro.Distro) *dnfjson.Solver {
+	return api.newArchSolver(d, api.archName)
+}
+
+// newArchSolver returns a solver for the distribution on another
+// architecture than the host one.
+func (api *API) newArchSolver(d distro.Distro, arch string) *dnfjson.Solver {
+	solver := api.solver.NewWithConfig(d.ModulePlatformID(), d.Releasever(), arch, d.Name())
+	hotfixes, streams := api.sourceModules(d.Name())
+	solver.SetModuleHotfixes(hotfixes)
+	solver.SetModules(streams, nil)
+	return solver
+}
+
 // Returns all configured repositories as rpmmd.RepoConfig.
 // Payload repositories (defined by the user) are assigned the payload package
 // set names from the image type.
@@ -3487,7 +3983,7 @@ func (api *API) payloadRepositories(distroName string) []rpmmd.RepoConfig {
 // which are needed to build the specific image type. The allRepositories() can't do this, because
 // it is used in places where image types are not considered.
 func (api *API) allRepositoriesByImageType(imageType distro.ImageType) ([]rpmmd.RepoConfig, error) {
-	repos, err := api.repoRegistry.ReposByImageType(imageType)
+	repos, err := api.getRepoRegistry().ReposByImageType(imageType)
 	if err != nil {
 		return nil, err
 	}
@@ -3504,7 +4000,13 @@ func (api *API) allRepositoriesByImageType(imageType distro.ImageType) ([]rpmmd.
 
 // Returns all configured repositories (base + sources) as rpmmd.RepoConfig
 func (api *API) allRepositories(distroName string) ([]rpmmd.RepoConfig, error) {
-	repos, err := api.repoRegistry.ReposByArchName(distroName, api.archName, false)
+	return api.allArchRepositories(distroName, api.archName)
+}
+
+// allArchRepositories returns the repositories of the distribution on the
+// architecture, the sources are used for all the architectures.
+func (api *API) allArchRepositories(distroName, arch string) ([]rpmmd.RepoConfig, error) {
+	repos, err := api.getRepoRegistry().ReposByArchName(distroName, arch, false)
 	if err != nil {
 		return nil, err
 	}
@@ -3516,6 +4018,12 @@ func (api *API) allRepositories(distroName string) ([]rpmmd.RepoConfig, error) {
 }
 
 func (api *API) depsolveBlueprint(bp blueprint.Blueprint) ([]rpmmd.PackageSpec, error) {
+	return api.depsolveBlueprintArch(bp, api.archName)
+}
+
+// depsolveBlueprintArch depsolves the blueprint for the architecture, which
+// doesn't have to be the host one.
+func (api *API) depsolveBlueprintArch(bp blueprint.Blueprint, arch string) ([]rpmmd.PackageSpec, error) {
 	// Depsolve using the host distro if none has been specified
 	if bp.Distro == "" {
 		bp.Distro = api.hostDistroName
@@ -3525,12 +4033,12 @@ func (api *API) depsolveBlueprint(bp blueprint.Blueprint) ([]rpmmd.PackageSpec,
 	if d == nil {
 		return nil, fmt.Errorf("GetDistro - unknown distribution: %s", bp.Distro)
 	}
-	repos, err := api.allRepositories(bp.Distro)
+	repos, err := api.allArchRepositories(bp.Distro, arch)
 	if err != nil {
 		return nil, err
 	}
 
-	solver := api.solver.NewWithConfig(d.ModulePlatformID(), d.Releasever(), api.archName, d.Name())
+	solver := api.newArchSolver(d, arch)
 	solved, err := solver.Depsolve([]rpmmd.PackageSet{{Include: bp.GetPackages(), Repositories: repos}})
 	if err != nil {
 		return nil, err
@@ -3597,33 +4105,6 @@ func (api *API) uploadsCancelHandler(writer http.ResponseWriter, request *http.R
 	notImplementedHandler(writer, request, params)
 }
 
-func (api *API) providersHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-	if !verifyRequestVersion(writer, params, 1) {
-		return
-	}
-
-	// TODO: implement this route (it is v1 only)
-	notImplementedHandler(writer, request, params)
-}
-
-func (api *API) providersSaveHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-	if !verifyRequestVersion(writer, params, 1) {
-		return
-	}
-
-	// TODO: implement this route (it is v1 only)
-	notImplementedHandler(writer, request, params)
-}
-
-func (api *API) providersDeleteHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-	if !verifyRequestVersion(writer, params, 1) {
-		return
-	}
-
-	// TODO: implement this route (it is v1 only)
-	notImplementedHandler(writer, request, params)
-}
-
 func (api *API) distrosListHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	if !verifyRequestVersion(writer, params, 1) {
 		return
@@ -3632,7 +4113,7 @@ func (api *API) distrosListHandler(writer http.ResponseWriter, request *http.Req
 	var reply struct {
 		Distros []string `json:"distros"`
 	}
-	reply.Distros = api.distros
+	reply.Distros = api.getDistros()
 
 	err := json.NewEncoder(writer).Encode(reply)
 	common.PanicOnError(err)
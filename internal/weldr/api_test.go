@@ -3,15 +3,19 @@ package weldr
 import (
 	"archive/tar"
 	"bytes"
+	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strconv"
 	"testing"
@@ -22,21 +26,25 @@ import (
 	"github.com/osbuild/images/pkg/distro"
 	"github.com/osbuild/images/pkg/distro/test_distro"
 	"github.com/osbuild/images/pkg/distroregistry"
+	"github.com/osbuild/images/pkg/osbuild"
 	"github.com/osbuild/images/pkg/ostree"
 	"github.com/osbuild/images/pkg/ostree/mock_ostree_repo"
 	"github.com/osbuild/images/pkg/rpmmd"
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/dnfjson"
+	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
 	dnfjson_mock "github.com/osbuild/osbuild-composer/internal/mocks/dnfjson"
 	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
 	"github.com/osbuild/osbuild-composer/internal/reporegistry"
 	"github.com/osbuild/osbuild-composer/internal/store"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/test"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 
 	"github.com/BurntSushi/toml"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -221,6 +229,33 @@ func TestBasic(t *testing.T) {
 	}
 }
 
+func TestServeListeners(t *testing.T) {
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+
+	// e.g. the weldr socket and the TLS one
+	var listeners []net.Listener
+	served := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		listeners = append(listeners, listener)
+		go func() {
+			served <- api.Serve(listener)
+		}()
+	}
+
+	for _, listener := range listeners {
+		response, err := http.Get("http://" + listener.Addr().String() + "/api/status")
+		require.NoError(t, err)
+		response.Body.Close()
+		require.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	require.NoError(t, api.Shutdown(context.Background()))
+	require.NoError(t, <-served)
+	require.NoError(t, <-served)
+}
+
 func TestBlueprintsNew(t *testing.T) {
 	var cases = []struct {
 		Method         string
@@ -733,6 +768,8 @@ func TestBlueprintsDiff(t *testing.T) {
 		ExpectedJSON   string
 	}{
 		{"GET", "/api/v0/blueprints/diff/test/NEWEST/WORKSPACE", ``, http.StatusOK, `{"diff":[{"new":{"Package":{"name":"systemd","version":"123"}},"old":null},{"new":null,"old":{"Package":{"name":"httpd","version":"2.4.*"}}}]}`},
+		{"GET", "/api/v1/blueprints/diff/test/NEWEST/WORKSPACE?format=structured", ``, http.StatusOK, `{"from":"NEWEST","to":"WORKSPACE","diff":{"fields":[{"path":"version","old":"0.0.1","new":"0.0.0"}],"packages":{"added":[{"name":"systemd","version":"123"}],"removed":[{"name":"httpd","version":"2.4.*"}],"changed":[]},"modules":{"added":[],"removed":[],"changed":[]},"groups":{"added":[],"removed":[]},"customizations":[]}}`},
+		{"GET", "/api/v1/blueprints/diff/test/NEWEST/WORKSPACE?format=xml", ``, http.StatusBadRequest, `{"status":false,"errors":[{"id":"InvalidChars","msg":"invalid format parameter: xml"}]}`},
 	}
 
 	tempdir := t.TempDir()
@@ -849,6 +886,57 @@ func TestBlueprintsDepsolve(t *testing.T) {
 	}
 }
 
+func TestBlueprintsPreview(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	test.SendHTTP(api, false, "POST", "/api/v0/blueprints/new", `{"name":"test","description":"Test","packages":[{"name":"dep-package1","version":"*"}],"modules":[{"name":"dep-package3","version":"*"}],"version":"0.0.0"}`)
+
+	type previewReply struct {
+		Blueprint    blueprint.Blueprint `json:"blueprint"`
+		Distro       string              `json:"distro"`
+		Arch         string              `json:"arch"`
+		Dependencies []rpmmd.PackageSpec `json:"dependencies"`
+		Changes      *packagesDiff       `json:"changes"`
+		Errors       []responseError     `json:"errors"`
+	}
+	preview := func(query string) previewReply {
+		response := test.SendHTTP(api, false, "GET", "/api/v1/blueprints/preview/test"+query, ``)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		var reply previewReply
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&reply))
+		return reply
+	}
+
+	// the blueprint's own distro has nothing to compare with
+	reply := preview("")
+	require.Equal(t, test_distro.TestDistroName, reply.Distro)
+	require.Equal(t, test_distro.TestArchName, reply.Arch)
+	require.NotEmpty(t, reply.Dependencies)
+	require.Nil(t, reply.Changes)
+	require.Empty(t, reply.Errors)
+
+	reply = preview("?distro=" + test_distro.TestDistro2Name)
+	require.Equal(t, test_distro.TestDistro2Name, reply.Distro)
+	require.Equal(t, test_distro.TestDistro2Name, reply.Blueprint.Distro)
+	require.NotEmpty(t, reply.Dependencies)
+	require.NotNil(t, reply.Changes)
+	require.Empty(t, reply.Errors)
+
+	test.TestRoute(t, api, false, "GET", "/api/v1/blueprints/preview/test?distro=fedora-1", ``, http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"DistroError","msg":"Unknown distribution: fedora-1"}]}`)
+	test.TestRoute(t, api, false, "GET", "/api/v1/blueprints/preview/test?arch=imaginary", ``, http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"DistroError","msg":"Unknown arch: imaginary"}]}`)
+	// there are no repositories for the other arch
+	test.TestRoute(t, api, false, "GET", "/api/v1/blueprints/preview/test?arch="+test_distro.TestArch2Name, ``, http.StatusBadRequest, `{"status":false}`, "errors")
+	test.TestRoute(t, api, false, "GET", "/api/v1/blueprints/preview/unknown", ``, http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UnknownBlueprint","msg":"Unknown blueprint name: unknown"}]}`)
+	test.TestRoute(t, api, false, "GET", "/api/v0/blueprints/preview/test", ``, http.StatusNotFound,
+		`{"status":false,"errors":[{"code":404,"id":"HTTPError","msg":"Not Found"}]}`)
+}
+
 // TestOldBlueprintsUndo run tests with blueprint changes after a service restart
 // Old blueprints are not saved, after a restart the changes are listed, but cannot be recalled
 func TestOldBlueprintsUndo(t *testing.T) {
@@ -1514,6 +1602,103 @@ func TestComposeLog(t *testing.T) {
 	}
 }
 
+func TestComposeImage(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	tempdir := t.TempDir()
+	api, s := createWeldrAPI(tempdir, rpmmd_mock.BaseFixture)
+	q, err := fsjobqueue.New(t.TempDir())
+	require.NoError(t, err)
+	artifactsDir := t.TempDir()
+	api.workers = worker.NewServer(nil, q, worker.Config{BasePath: "/api/worker/v1", ArtifactsDir: artifactsDir})
+
+	// the worker uploaded the image and its checksum
+	arch, err := test_distro.New().GetArch(test_distro.TestArchName)
+	require.NoError(t, err)
+	it, err := arch.GetImageType(test_distro.TestImageTypeName)
+	require.NoError(t, err)
+	workerTarget := target.NewWorkerServerTarget()
+	workerTarget.OsbuildArtifact.ExportFilename = it.Filename()
+	workerTarget.OsbuildArtifact.ExportName = it.Exports()[0]
+	jobId, err := api.workers.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{Targets: []*target.Target{workerTarget}}, "")
+	require.NoError(t, err)
+	_, token, _, _, _, err := api.workers.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeOSBuild}, []string{""})
+	require.NoError(t, err)
+	image := []byte("0123456789abcdefghij")
+	checksum := sha256.Sum256(image)
+	result, err := json.Marshal(worker.OSBuildJobResult{
+		Success:           true,
+		OSBuildOutput:     &osbuild.Result{Success: true},
+		ArtifactChecksums: map[string]string{path.Join(it.Exports()[0], it.Filename()): fmt.Sprintf("sha256:%x", checksum)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, api.workers.FinishJob(token, result))
+	require.NoError(t, os.MkdirAll(filepath.Join(artifactsDir, jobId.String()), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(artifactsDir, jobId.String(), it.Filename()), image, 0600))
+	id := uuid.New()
+	require.NoError(t, s.PushCompose(id, nil, it, &blueprint.Blueprint{Name: "test"}, 0, nil, jobId, nil))
+	etag := fmt.Sprintf(`"%x"`, checksum)
+
+	response := test.SendHTTP(api, false, "GET", "/api/v1/compose/image/"+id.String(), "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "bytes", response.Header.Get("Accept-Ranges"))
+	require.Equal(t, etag, response.Header.Get("ETag"))
+	require.Equal(t, "sha-256=:"+base64.StdEncoding.EncodeToString(checksum[:])+":", response.Header.Get("Repr-Digest"))
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, image, body)
+
+	// an interrupted download is resumed
+	request := httptest.NewRequest("GET", "/api/v1/compose/image/"+id.String(), nil)
+	request.Header.Set("Range", "bytes=10-")
+	request.Header.Set("If-Range", etag)
+	recorder := httptest.NewRecorder()
+	api.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusPartialContent, recorder.Code)
+	require.Equal(t, "bytes 10-19/20", recorder.Header().Get("Content-Range"))
+	require.Equal(t, etag, recorder.Header().Get("ETag"))
+	require.Equal(t, "abcdefghij", recorder.Body.String())
+
+	// the whole image is sent if it changed since
+	request.Header.Set("If-Range", `"other"`)
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, image, recorder.Body.Bytes())
+
+	request.Header.Del("If-Range")
+	request.Header.Set("Range", "bytes=30-")
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusRequestedRangeNotSatisfiable, recorder.Code)
+
+	request = httptest.NewRequest("HEAD", "/api/v1/compose/image/"+id.String(), nil)
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "20", recorder.Header().Get("Content-Length"))
+	require.Equal(t, etag, recorder.Header().Get("ETag"))
+	require.Empty(t, recorder.Body.Bytes())
+
+	// the finished compose of the fixture predates the job queue, its image
+	// in the compat output directory has no checksum
+	api.compatOutputDir = t.TempDir()
+	compatId := "30000000-0000-0000-0000-000000000002"
+	compose, _ := s.GetCompose(uuid.MustParse(compatId))
+	require.NoError(t, os.MkdirAll(filepath.Join(api.compatOutputDir, compatId, "0"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(api.compatOutputDir, compatId, "0", compose.ImageBuild.ImageType.Filename()), image, 0600))
+	request = httptest.NewRequest("GET", "/api/v1/compose/image/"+compatId, nil)
+	request.Header.Set("Range", "bytes=10-")
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusPartialContent, recorder.Code)
+	require.Empty(t, recorder.Header().Get("ETag"))
+	require.Empty(t, recorder.Header().Get("Repr-Digest"))
+	require.Equal(t, "abcdefghij", recorder.Body.String())
+}
+
 func TestComposeQueue(t *testing.T) {
 	var cases = []struct {
 		Fixture        rpmmd_mock.FixtureGenerator
@@ -1613,6 +1798,10 @@ func TestSourcesNew(t *testing.T) {
 		{"POST", "/api/v1/projects/source/new", `{"id": "fish","name":"fish repo","url": "https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/","type": "yum-baseurl","check_ssl": false,"check_gpg": false,"distros":["test-distro", "test-distro-2"]}`, http.StatusOK, `{"status":true}`},
 		{"POST", "/api/v1/projects/source/new", `{"id": "fish","name":"fish repo","url": "https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/","type": "yum-baseurl","check_ssl": false,"check_gpg": false,"distros":["fedora-1"]}`, http.StatusBadRequest, `{"status":false, "errors":[{"id":"ProjectsError", "msg":"Invalid distributions: fedora-1"}]}`},
 		{"POST", "/api/v1/projects/source/new", `{"id": "fish","name":"fish repo","url": "https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/","type": "yum-baseurl","check_ssl": false,"check_gpg": true,"check_repogpg":true,"gpgkeys": ["https://repourl/path/to/key.pub"]}`, http.StatusOK, `{"status":true}`},
+		{"POST", "/api/v1/projects/source/new", `{"id": "fish","name":"fish copr","url": "@fish/fish-shell","type": "copr","check_ssl": true,"check_gpg": true,"module_hotfixes":true,"module_streams":["nodejs:18"]}`, http.StatusOK, `{"status":true}`},
+		{"POST", "/api/v1/projects/source/new", `{"id": "fish","name":"fish copr","url": "https://copr.fedorainfracloud.org/coprs/fish/fish-shell/","type": "copr","check_ssl": true,"check_gpg": true}`, http.StatusBadRequest, `{"status":false, "errors":[{"id":"ProjectsError", "msg":"Problem parsing POST body: 'url' of a copr source must be owner/project or @group/project, not \"https://copr.fedorainfracloud.org/coprs/fish/fish-shell/\""}]}`},
+		{"POST", "/api/v1/projects/source/new", `{"id": "fish","name":"fish repo","url": "https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/","type": "yum-baseurl","check_ssl": false,"check_gpg": false,"module_streams":["nodejs"]}`, http.StatusBadRequest, `{"status":false, "errors":[{"id":"ProjectsError", "msg":"Problem parsing POST body: invalid module stream \"nodejs\", it must be name:stream"}]}`},
+		{"POST", "/api/v1/projects/source/new", `{"id": "fish","name":"fish repo","url": "https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/","type": "yum-baseurl","check_ssl": false,"check_gpg": false,"module_streams":["nodejs:18","nodejs:20"]}`, http.StatusBadRequest, `{"status":false, "errors":[{"id":"ProjectsError", "msg":"Problem parsing POST body: more than one stream of module nodejs: 18 and 20"}]}`},
 	}
 
 	tempdir := t.TempDir()
@@ -1790,6 +1979,27 @@ func TestSourcesInfoGPGKeysV1(t *testing.T) {
 	test.TestRoute(t, api, true, "GET", "/api/v1/projects/source/info/fish?format=json", ``, 200, `{"sources":{"fish":`+sourceStr+`},"errors":[]}`)
 }
 
+func TestSourcesCoprModulesV1(t *testing.T) {
+	sourceStr := `{"id":"fish","name":"fish copr","type":"copr","url":"@fish/fish-shell","check_gpg":true,"check_repogpg":false,"check_ssl":true,"rhsm":false,"system":false,"module_hotfixes":true,"module_streams":["nodejs:18"]}`
+
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	test.TestRoute(t, api, true, "POST", "/api/v1/projects/source/new", sourceStr, http.StatusOK, `{"status":true}`)
+	test.TestRoute(t, api, true, "GET", "/api/v1/projects/source/info/fish", ``, 200, `{"sources":{"fish":`+sourceStr+`},"errors":[]}`)
+
+	repos := api.payloadRepositories("fedora-39")
+	require.Len(t, repos, 1)
+	require.Equal(t, []string{"https://download.copr.fedorainfracloud.org/results/@fish/fish-shell/fedora-39-$basearch/"}, repos[0].BaseURLs)
+	require.Equal(t, []string{"https://download.copr.fedorainfracloud.org/results/@fish/fish-shell/pubkey.gpg"}, repos[0].GPGKeys)
+	repos = api.payloadRepositories("rhel-93")
+	require.Equal(t, []string{"https://download.copr.fedorainfracloud.org/results/@fish/fish-shell/epel-9-$basearch/"}, repos[0].BaseURLs)
+	repos = api.payloadRepositories("centos-9")
+	require.Equal(t, []string{"https://download.copr.fedorainfracloud.org/results/@fish/fish-shell/centos-stream-9-$basearch/"}, repos[0].BaseURLs)
+
+	hotfixes, streams := api.sourceModules("fedora-39")
+	require.Equal(t, api.payloadRepositories("fedora-39"), hotfixes)
+	require.Equal(t, []string{"nodejs:18"}, streams)
+}
+
 // TestSourcesNewWrongTomlV1 Tests that Empty TOML, and invalid TOML should return an error
 func TestSourcesNewWrongTomlV1(t *testing.T) {
 	tempdir := t.TempDir()
@@ -0,0 +1,188 @@
+package weldr
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+// blueprintDiff is the difference between two versions of a blueprint, in a
+// form which can be rendered without comparing the blueprints again.
+type blueprintDiff struct {
+	// the top-level fields, e.g. the description and the distro
+	Fields         []valueChange `json:"fields"`
+	Packages       packagesDiff  `json:"packages"`
+	Modules        packagesDiff  `json:"modules"`
+	Groups         groupsDiff    `json:"groups"`
+	Customizations []valueChange `json:"customizations"`
+}
+
+type packagesDiff struct {
+	Added   []blueprint.Package `json:"added"`
+	Removed []blueprint.Package `json:"removed"`
+	Changed []packageChange     `json:"changed"`
+}
+
+type packageChange struct {
+	Name       string `json:"name"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+}
+
+type groupsDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// valueChange is a changed value, at the dot-separated path of its field.
+// The old or the new value is null when the field was added or removed.
+type valueChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+func diffBlueprints(oldBlueprint, newBlueprint *blueprint.Blueprint) blueprintDiff {
+	oldFields := blueprintFields(oldBlueprint)
+	newFields := blueprintFields(newBlueprint)
+	oldCustomizations := oldFields["customizations"]
+	newCustomizations := newFields["customizations"]
+	// these have their own sections
+	for _, key := range []string{"name", "packages", "modules", "groups", "customizations"} {
+		delete(oldFields, key)
+		delete(newFields, key)
+	}
+
+	return blueprintDiff{
+		Fields:         diffValues("", oldFields, newFields),
+		Packages:       diffPackages(oldBlueprint.Packages, newBlueprint.Packages),
+		Modules:        diffPackages(oldBlueprint.Modules, newBlueprint.Modules),
+		Groups:         diffGroups(oldBlueprint.Groups, newBlueprint.Groups),
+		Customizations: diffValues("", oldCustomizations, newCustomizations),
+	}
+}
+
+// blueprintFields returns the fields of the blueprint as they're in its
+// json, only the set fields are included.
+func blueprintFields(bp *blueprint.Blueprint) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(bp)
+	if err == nil {
+		err = json.Unmarshal(data, &fields)
+	}
+	if err != nil {
+		// blueprints are always valid json
+		panic(err)
+	}
+	return fields
+}
+
+// diffValues returns the changes between the json values, the objects are
+// compared field by field and everything else as a whole.
+func diffValues(path string, oldValue, newValue interface{}) []valueChange {
+	changes := []valueChange{}
+	oldObject, oldIsObject := oldValue.(map[string]interface{})
+	newObject, newIsObject := newValue.(map[string]interface{})
+	if !oldIsObject && !newIsObject {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, valueChange{Path: path, Old: oldValue, New: newValue})
+		}
+		return changes
+	}
+	if oldValue == nil {
+		oldObject = map[string]interface{}{}
+	} else if !oldIsObject {
+		return append(changes, valueChange{Path: path, Old: oldValue, New: newValue})
+	}
+	if newValue == nil {
+		newObject = map[string]interface{}{}
+	} else if !newIsObject {
+		return append(changes, valueChange{Path: path, Old: oldValue, New: newValue})
+	}
+
+	keys := map[string]bool{}
+	for key := range oldObject {
+		keys[key] = true
+	}
+	for key := range newObject {
+		keys[key] = true
+	}
+	sortedKeys := []string{}
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+		changes = append(changes, diffValues(keyPath, oldObject[key], newObject[key])...)
+	}
+	return changes
+}
+
+func diffPackages(oldPackages, newPackages []blueprint.Package) packagesDiff {
+	diff := packagesDiff{
+		Added:   []blueprint.Package{},
+		Removed: []blueprint.Package{},
+		Changed: []packageChange{},
+	}
+
+	oldMap := make(map[string]blueprint.Package)
+	for _, oldPackage := range oldPackages {
+		oldMap[oldPackage.Name] = oldPackage
+	}
+	for _, newPackage := range newPackages {
+		oldPackage, found := oldMap[newPackage.Name]
+		if !found {
+			diff.Added = append(diff.Added, newPackage)
+			continue
+		}
+		delete(oldMap, oldPackage.Name)
+		if oldPackage.Version != newPackage.Version {
+			diff.Changed = append(diff.Changed, packageChange{
+				Name:       newPackage.Name,
+				OldVersion: oldPackage.Version,
+				NewVersion: newPackage.Version,
+			})
+		}
+	}
+	for _, oldPackage := range oldMap {
+		diff.Removed = append(diff.Removed, oldPackage)
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+	return diff
+}
+
+func diffGroups(oldGroups, newGroups []blueprint.Group) groupsDiff {
+	diff := groupsDiff{
+		Added:   []string{},
+		Removed: []string{},
+	}
+
+	oldMap := make(map[string]bool)
+	for _, oldGroup := range oldGroups {
+		oldMap[oldGroup.Name] = true
+	}
+	for _, newGroup := range newGroups {
+		if oldMap[newGroup.Name] {
+			delete(oldMap, newGroup.Name)
+		} else {
+			diff.Added = append(diff.Added, newGroup.Name)
+		}
+	}
+	for name := range oldMap {
+		diff.Removed = append(diff.Removed, name)
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	return diff
+}
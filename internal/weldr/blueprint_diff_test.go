@@ -0,0 +1,65 @@
+package weldr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+)
+
+func TestDiffBlueprints(t *testing.T) {
+	var oldBlueprint, newBlueprint blueprint.Blueprint
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "test",
+		"description": "Test",
+		"version": "0.0.1",
+		"packages": [{"name": "httpd", "version": "2.4.*"}, {"name": "tmux", "version": "*"}],
+		"groups": [{"name": "core"}],
+		"customizations": {
+			"hostname": "old-host",
+			"kernel": {"append": "nosmt"},
+			"services": {"enabled": ["sshd"]}
+		}
+	}`), &oldBlueprint))
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "test",
+		"description": "Test server",
+		"version": "0.0.2",
+		"packages": [{"name": "httpd", "version": "2.4.58"}, {"name": "vim", "version": "*"}],
+		"groups": [{"name": "core"}, {"name": "standard"}],
+		"customizations": {
+			"hostname": "new-host",
+			"services": {"enabled": ["sshd", "httpd"]},
+			"timezone": {"timezone": "UTC"}
+		}
+	}`), &newBlueprint))
+
+	diff := diffBlueprints(&oldBlueprint, &newBlueprint)
+	require.Equal(t, []valueChange{
+		{Path: "description", Old: "Test", New: "Test server"},
+		{Path: "version", Old: "0.0.1", New: "0.0.2"},
+	}, diff.Fields)
+	require.Equal(t, packagesDiff{
+		Added:   []blueprint.Package{{Name: "vim", Version: "*"}},
+		Removed: []blueprint.Package{{Name: "tmux", Version: "*"}},
+		Changed: []packageChange{{Name: "httpd", OldVersion: "2.4.*", NewVersion: "2.4.58"}},
+	}, diff.Packages)
+	require.Equal(t, groupsDiff{Added: []string{"standard"}, Removed: []string{}}, diff.Groups)
+
+	customizations, err := json.Marshal(diff.Customizations)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"path": "hostname", "old": "old-host", "new": "new-host"},
+		{"path": "kernel.append", "old": "nosmt", "new": null},
+		{"path": "services.enabled", "old": ["sshd"], "new": ["sshd", "httpd"]},
+		{"path": "timezone.timezone", "old": null, "new": "UTC"}
+	]`, string(customizations))
+
+	// the same blueprints have no differences
+	diff = diffBlueprints(&oldBlueprint, &oldBlueprint)
+	require.Empty(t, diff.Fields)
+	require.Empty(t, diff.Customizations)
+	require.Empty(t, diff.Packages.Changed)
+}
@@ -0,0 +1,40 @@
+package weldr
+
+import (
+	"fmt"
+)
+
+// imageTypeAliases are the old names of renamed image types, by their
+// current names. Composes can still be started with the old names, with a
+// warning, and they're listed as deprecated so scripts can migrate.
+var imageTypeAliases = map[string]string{
+	"rhel-edge-commit":     "edge-commit",
+	"rhel-edge-container":  "edge-container",
+	"rhel-edge-installer":  "edge-installer",
+	"fedora-iot-commit":    "iot-commit",
+	"fedora-iot-container": "iot-container",
+	"fedora-iot-installer": "iot-installer",
+}
+
+// resolveImageTypeAlias returns the current name of the image type. The
+// names which are still image types of the distro aren't aliases for it.
+func (api *API) resolveImageTypeAlias(distroName, imageType string) string {
+	name, isAlias := imageTypeAliases[imageType]
+	if !isAlias {
+		return imageType
+	}
+	if d := api.getDistro(distroName); d != nil {
+		if arch, err := d.GetArch(api.archName); err == nil {
+			if _, err := arch.GetImageType(imageType); err == nil {
+				return imageType
+			}
+		}
+	}
+	return name
+}
+
+// imageTypeDeprecation is the warning for using the deprecated name of the
+// image type.
+func imageTypeDeprecation(alias, name string) string {
+	return fmt.Sprintf("compose type %s is deprecated, use %s instead", alias, name)
+}
@@ -0,0 +1,45 @@
+package weldr
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/osbuild/images/pkg/distro/test_distro"
+	"github.com/stretchr/testify/require"
+
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/test"
+)
+
+func TestImageTypeAliases(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	imageTypeAliases["old_test_type"] = test_distro.TestImageTypeName
+	// still an image type of the test distro
+	imageTypeAliases[test_distro.TestImageTypeName] = "new_test_type"
+	t.Cleanup(func() {
+		delete(imageTypeAliases, "old_test_type")
+		delete(imageTypeAliases, test_distro.TestImageTypeName)
+	})
+
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.NoComposesFixture)
+	require.Equal(t, test_distro.TestImageTypeName, api.resolveImageTypeAlias(test_distro.TestDistroName, "old_test_type"))
+	require.Equal(t, test_distro.TestImageTypeName, api.resolveImageTypeAlias(test_distro.TestDistroName, test_distro.TestImageTypeName))
+
+	test.TestRoute(t, api, true, "GET", "/api/v1/compose/types", ``, http.StatusOK, `{"types":[
+		{"name":"test_ostree_type","enabled":true},
+		{"name":"test_type","enabled":true},
+		{"name":"old_test_type","enabled":true,"deprecated":true,"replaced_by":"test_type","deprecation":"compose type old_test_type is deprecated, use test_type instead"}]}`)
+
+	// composes of the old name build the current one
+	reply := test.TestRouteWithReply(t, api, false, "POST", "/api/v1/compose", `{"blueprint_name":"test","compose_type":"old_test_type"}`, http.StatusOK, `{"status":true}`, "build_id", "warnings")
+	var started struct {
+		Warnings []string `json:"warnings"`
+	}
+	require.NoError(t, json.Unmarshal(reply, &started))
+	require.Contains(t, started.Warnings, "compose type old_test_type is deprecated, use test_type instead")
+}
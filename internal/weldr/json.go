@@ -195,24 +195,28 @@ func NewSourceConfigV1(id string, s store.SourceConfig) SourceConfigV1 {
 	sc.RHSM = s.RHSM
 	sc.CheckRepoGPG = s.CheckRepoGPG
 	sc.GPGKeys = s.GPGKeys
+	sc.ModuleHotfixes = s.ModuleHotfixes
+	sc.ModuleStreams = s.ModuleStreams
 
 	return sc
 }
 
 // SourceConfigV1 holds the source repository information
 type SourceConfigV1 struct {
-	ID           string   `json:"id" toml:"id"`
-	Name         string   `json:"name" toml:"name"`
-	Type         string   `json:"type" toml:"type"`
-	URL          string   `json:"url" toml:"url"`
-	CheckGPG     bool     `json:"check_gpg" toml:"check_gpg"`
-	CheckSSL     bool     `json:"check_ssl" toml:"check_ssl"`
-	System       bool     `json:"system" toml:"system"`
-	Proxy        string   `json:"proxy,omitempty" toml:"proxy,omitempty"`
-	GPGKeys      []string `json:"gpgkeys,omitempty" toml:"gpgkeys,omitempty"`
-	Distros      []string `json:"distros,omitempty" toml:"distros,omitempty"`
-	RHSM         bool     `json:"rhsm" toml:"rhsm"`
-	CheckRepoGPG bool     `json:"check_repogpg" toml:"check_repogpg"`
+	ID             string   `json:"id" toml:"id"`
+	Name           string   `json:"name" toml:"name"`
+	Type           string   `json:"type" toml:"type"`
+	URL            string   `json:"url" toml:"url"`
+	CheckGPG       bool     `json:"check_gpg" toml:"check_gpg"`
+	CheckSSL       bool     `json:"check_ssl" toml:"check_ssl"`
+	System         bool     `json:"system" toml:"system"`
+	Proxy          string   `json:"proxy,omitempty" toml:"proxy,omitempty"`
+	GPGKeys        []string `json:"gpgkeys,omitempty" toml:"gpgkeys,omitempty"`
+	Distros        []string `json:"distros,omitempty" toml:"distros,omitempty"`
+	RHSM           bool     `json:"rhsm" toml:"rhsm"`
+	CheckRepoGPG   bool     `json:"check_repogpg" toml:"check_repogpg"`
+	ModuleHotfixes bool     `json:"module_hotfixes,omitempty" toml:"module_hotfixes,omitempty"`
+	ModuleStreams  []string `json:"module_streams,omitempty" toml:"module_streams,omitempty"`
 }
 
 // Key returns the key, .ID in this case
@@ -242,6 +246,8 @@ func (s SourceConfigV1) SourceConfig() (ssc store.SourceConfig) {
 	ssc.RHSM = s.RHSM
 	ssc.CheckRepoGPG = s.CheckRepoGPG
 	ssc.GPGKeys = s.GPGKeys
+	ssc.ModuleHotfixes = s.ModuleHotfixes
+	ssc.ModuleStreams = s.ModuleStreams
 
 	return ssc
 }
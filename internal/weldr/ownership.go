@@ -0,0 +1,268 @@
+package weldr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"golang.org/x/sys/unix"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/store"
+)
+
+type requestUserKey struct{}
+
+// requestUser is the user of the process on the other end of the weldr
+// socket, which several users can access by being members of its group.
+type requestUser struct {
+	Name string
+	// root and the user composer runs as can access all the blueprints
+	Admin bool
+}
+
+// connContext adds the user of the peer of unix socket connections to the
+// context of their requests.
+func connContext(ctx context.Context, conn net.Conn) context.Context {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return ctx
+	}
+
+	var cred *unix.Ucred
+	var credErr error
+	err = rawConn.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err == nil {
+		err = credErr
+	}
+	if err != nil {
+		log.Printf("Error getting the credentials of the weldr client: %v", err)
+		return ctx
+	}
+
+	u := requestUser{
+		Name:  strconv.FormatUint(uint64(cred.Uid), 10),
+		Admin: cred.Uid == 0 || int(cred.Uid) == os.Getuid(),
+	}
+	if passwd, err := user.LookupId(u.Name); err == nil {
+		u.Name = passwd.Username
+	}
+	return context.WithValue(ctx, requestUserKey{}, u)
+}
+
+// getRequestUser returns the user of the request. Clients of the TLS
+// listener are the subjects of their verified certificates, they are never
+// admins and the ones without a certificate are anonymous users which can
+// only access the blueprints without owners. The user is unknown when the
+// request came from neither, these requests can access all the blueprints.
+func getRequestUser(request *http.Request) (requestUser, bool) {
+	if request.TLS != nil {
+		var u requestUser
+		if len(request.TLS.VerifiedChains) > 0 && len(request.TLS.VerifiedChains[0]) > 0 {
+			u.Name = request.TLS.VerifiedChains[0][0].Subject.CommonName
+		}
+		return u, true
+	}
+	u, ok := request.Context().Value(requestUserKey{}).(requestUser)
+	return u, ok
+}
+
+// canReadBlueprint returns whether the user of the request can see the
+// blueprint, the private ones are only visible to their owners.
+func (api *API) canReadBlueprint(request *http.Request, name string) bool {
+	u, known := getRequestUser(request)
+	if !known || u.Admin {
+		return true
+	}
+	ownership, exists := api.store.GetBlueprintOwnership(name)
+	return !exists || !ownership.Private || (u.Name != "" && ownership.Owner == u.Name)
+}
+
+// canWriteBlueprint returns whether the user of the request can change the
+// blueprint, only the owners can change theirs. Blueprints without owners,
+// e.g. the ones created before the ownership, can be changed by anyone.
+func (api *API) canWriteBlueprint(request *http.Request, name string) bool {
+	u, known := getRequestUser(request)
+	if !known || u.Admin {
+		return true
+	}
+	ownership, exists := api.store.GetBlueprintOwnership(name)
+	return !exists || ownership.Owner == "" || (u.Name != "" && ownership.Owner == u.Name)
+}
+
+// verifyBlueprintsReadable writes an error and returns false if one of the
+// blueprints is private to another user.
+func (api *API) verifyBlueprintsReadable(writer http.ResponseWriter, request *http.Request, names []string) bool {
+	for _, name := range names {
+		if !api.canReadBlueprint(request, name) {
+			errors := responseError{
+				ID:  "BlueprintsError",
+				Msg: fmt.Sprintf("blueprint %s is private", name),
+			}
+			statusResponseError(writer, http.StatusForbidden, errors)
+			return false
+		}
+	}
+	return true
+}
+
+// verifyBlueprintWritable writes an error and returns false if the blueprint
+// is owned by another user.
+func (api *API) verifyBlueprintWritable(writer http.ResponseWriter, request *http.Request, name string) bool {
+	if !api.canWriteBlueprint(request, name) {
+		ownership, _ := api.store.GetBlueprintOwnership(name)
+		errors := responseError{
+			ID:  "BlueprintsError",
+			Msg: fmt.Sprintf("blueprint %s is owned by %s", name, ownership.Owner),
+		}
+		statusResponseError(writer, http.StatusForbidden, errors)
+		return false
+	}
+	return true
+}
+
+// canAccessSchedule returns whether the user of the request can see and
+// delete the schedule, only the owners can access theirs. Schedules without
+// owners can be accessed by anyone.
+func canAccessSchedule(request *http.Request, schedule store.ComposeSchedule) bool {
+	u, known := getRequestUser(request)
+	if !known || u.Admin {
+		return true
+	}
+	return schedule.Owner == "" || (u.Name != "" && schedule.Owner == u.Name)
+}
+
+// verifyScheduleAccessible writes an error and returns false if the schedule
+// is owned by another user.
+func verifyScheduleAccessible(writer http.ResponseWriter, request *http.Request, schedule store.ComposeSchedule) bool {
+	if !canAccessSchedule(request, schedule) {
+		errors := responseError{
+			ID:  "ScheduleError",
+			Msg: fmt.Sprintf("schedule %s is owned by %s", schedule.ID, schedule.Owner),
+		}
+		statusResponseError(writer, http.StatusForbidden, errors)
+		return false
+	}
+	return true
+}
+
+// setNewBlueprintOwner makes the user of the request the owner of the
+// blueprint it just created.
+func (api *API) setNewBlueprintOwner(request *http.Request, name string) error {
+	u, known := getRequestUser(request)
+	if !known || u.Name == "" {
+		return nil
+	}
+	return api.store.SetBlueprintOwnership(name, store.BlueprintOwnership{Owner: u.Name})
+}
+
+type blueprintOwnership struct {
+	Owner   string `json:"owner"`
+	Private bool   `json:"private"`
+}
+
+func (api *API) blueprintsOwnerHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	name := params.ByName("blueprint")
+	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
+		return
+	}
+	if !api.verifyBlueprintsReadable(writer, request, []string{name}) {
+		return
+	}
+	if bp, _ := api.store.GetBlueprint(name); bp == nil {
+		errors := responseError{
+			ID:  "UnknownBlueprint",
+			Msg: fmt.Sprintf("Unknown blueprint name: %s", name),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	ownership, _ := api.store.GetBlueprintOwnership(name)
+	err := json.NewEncoder(writer).Encode(blueprintOwnership(ownership))
+	common.PanicOnError(err)
+}
+
+// blueprintsSetOwnerHandler changes the owner or the visibility of the
+// blueprint, the fields which aren't in the request are kept.
+func (api *API) blueprintsSetOwnerHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type ownershipRequest struct {
+		Owner   *string `json:"owner"`
+		Private *bool   `json:"private"`
+	}
+
+	name := params.ByName("blueprint")
+	if !verifyStringsWithRegex(writer, []string{name}, ValidBlueprintName) {
+		return
+	}
+	if !api.verifyBlueprintWritable(writer, request, name) {
+		return
+	}
+	if bp, _ := api.store.GetBlueprint(name); bp == nil {
+		errors := responseError{
+			ID:  "UnknownBlueprint",
+			Msg: fmt.Sprintf("Unknown blueprint name: %s", name),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	var or ownershipRequest
+	err := json.NewDecoder(request.Body).Decode(&or)
+	if err != nil {
+		errors := responseError{
+			ID:  "BlueprintsError",
+			Msg: fmt.Sprintf("invalid ownership: %v", err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	ownership, _ := api.store.GetBlueprintOwnership(name)
+	if or.Owner != nil {
+		ownership.Owner = *or.Owner
+	}
+	if or.Private != nil {
+		ownership.Private = *or.Private
+	}
+	if ownership.Private && ownership.Owner == "" {
+		errors := responseError{
+			ID:  "BlueprintsError",
+			Msg: fmt.Sprintf("blueprint %s has no owner, it can't be private", name),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	err = api.store.SetBlueprintOwnership(name, ownership)
+	if err != nil {
+		errors := responseError{
+			ID:  "BlueprintsError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+	statusResponseOK(writer)
+}
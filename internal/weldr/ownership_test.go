@@ -0,0 +1,222 @@
+package weldr
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"path"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/osbuild/images/pkg/distro/test_distro"
+	"github.com/stretchr/testify/require"
+
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+)
+
+func TestConnContext(t *testing.T) {
+	listener, err := net.Listen("unix", path.Join(t.TempDir(), "api.socket"))
+	require.NoError(t, err)
+	defer listener.Close()
+
+	client, err := net.Dial("unix", listener.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// composer's own user is an admin
+	current, err := user.Current()
+	require.NoError(t, err)
+	request := httptest.NewRequest("GET", "/api/status", nil).WithContext(connContext(context.Background(), conn))
+	u, known := getRequestUser(request)
+	require.True(t, known)
+	require.Equal(t, requestUser{Name: current.Username, Admin: true}, u)
+
+	_, known = getRequestUser(httptest.NewRequest("GET", "/api/status", nil))
+	require.False(t, known)
+}
+
+func TestRequestUserTLS(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	// clients of the TLS listener are the subjects of their certificates
+	request := httptest.NewRequest("GET", "/api/status", nil)
+	request.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "alice"}}}},
+	}
+	u, known := getRequestUser(request)
+	require.True(t, known)
+	require.Equal(t, requestUser{Name: "alice"}, u)
+
+	// the ones without a certificate are anonymous
+	request.TLS = &tls.ConnectionState{}
+	u, known = getRequestUser(request)
+	require.True(t, known)
+	require.Equal(t, requestUser{}, u)
+
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	status, _ := sendAs(api, requestUser{Name: "alice"}, "POST", "/api/v0/blueprints/new", `{"name":"alice-bp","description":"","version":"0.0.1"}`)
+	require.Equal(t, http.StatusOK, status)
+	status, _ = sendAs(api, requestUser{Name: "alice"}, "POST", "/api/v1/blueprints/owner/alice-bp", `{"private":true}`)
+	require.Equal(t, http.StatusOK, status)
+
+	sendTLS := func(commonName, method, path, body string) int {
+		request := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		request.Header.Set("Content-Type", "application/json")
+		request.TLS = &tls.ConnectionState{}
+		if commonName != "" {
+			request.TLS.VerifiedChains = [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: commonName}}}}
+		}
+		recorder := httptest.NewRecorder()
+		api.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	require.Equal(t, http.StatusOK, sendTLS("alice", "GET", "/api/v0/blueprints/info/alice-bp", ``))
+	require.Equal(t, http.StatusForbidden, sendTLS("bob", "GET", "/api/v0/blueprints/info/alice-bp", ``))
+	require.Equal(t, http.StatusForbidden, sendTLS("", "GET", "/api/v0/blueprints/info/alice-bp", ``))
+	require.Equal(t, http.StatusForbidden, sendTLS("", "DELETE", "/api/v0/blueprints/delete/alice-bp", ``))
+}
+
+// sendAs sends the request as the user, like connContext does for the
+// requests from the weldr socket.
+func sendAs(api http.Handler, u requestUser, method, path, body string) (int, string) {
+	request := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	request.Header.Set("Content-Type", "application/json")
+	request = request.WithContext(context.WithValue(request.Context(), requestUserKey{}, u))
+	recorder := httptest.NewRecorder()
+	api.ServeHTTP(recorder, request)
+	reply, _ := io.ReadAll(recorder.Result().Body)
+	return recorder.Code, string(reply)
+}
+
+func TestBlueprintOwnership(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, s := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	alice := requestUser{Name: "alice"}
+	bob := requestUser{Name: "bob"}
+	root := requestUser{Name: "root", Admin: true}
+
+	status, _ := sendAs(api, alice, "POST", "/api/v0/blueprints/new", `{"name":"alice-bp","description":"","version":"0.0.1"}`)
+	require.Equal(t, http.StatusOK, status)
+	ownership, exists := s.GetBlueprintOwnership("alice-bp")
+	require.True(t, exists)
+	require.Equal(t, "alice", ownership.Owner)
+
+	// others can see the blueprint but not change it
+	status, reply := sendAs(api, bob, "GET", "/api/v1/blueprints/owner/alice-bp", ``)
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"owner":"alice","private":false}`, reply)
+	status, reply = sendAs(api, bob, "POST", "/api/v0/blueprints/workspace", `{"name":"alice-bp","description":"bob was here","version":"0.0.1"}`)
+	require.Equal(t, http.StatusForbidden, status)
+	require.JSONEq(t, `{"status":false,"errors":[{"id":"BlueprintsError","msg":"blueprint alice-bp is owned by alice"}]}`, reply)
+	status, _ = sendAs(api, bob, "DELETE", "/api/v0/blueprints/delete/alice-bp", ``)
+	require.Equal(t, http.StatusForbidden, status)
+	status, _ = sendAs(api, bob, "POST", "/api/v1/blueprints/owner/alice-bp", `{"owner":"bob"}`)
+	require.Equal(t, http.StatusForbidden, status)
+
+	// private blueprints are hidden from the others
+	status, _ = sendAs(api, alice, "POST", "/api/v1/blueprints/owner/alice-bp", `{"private":true}`)
+	require.Equal(t, http.StatusOK, status)
+	listed := func(u requestUser) []string {
+		status, reply := sendAs(api, u, "GET", "/api/v0/blueprints/list", ``)
+		require.Equal(t, http.StatusOK, status)
+		var list struct {
+			Blueprints []string `json:"blueprints"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(reply), &list))
+		return list.Blueprints
+	}
+	require.Contains(t, listed(alice), "alice-bp")
+	require.Contains(t, listed(root), "alice-bp")
+	require.NotContains(t, listed(bob), "alice-bp")
+	status, reply = sendAs(api, bob, "GET", "/api/v0/blueprints/info/alice-bp", ``)
+	require.Equal(t, http.StatusForbidden, status)
+	require.JSONEq(t, `{"status":false,"errors":[{"id":"BlueprintsError","msg":"blueprint alice-bp is private"}]}`, reply)
+	status, _ = sendAs(api, bob, "POST", "/api/v1/compose", `{"blueprint_name":"alice-bp","compose_type":"test_type","branch":"master"}`)
+	require.Equal(t, http.StatusForbidden, status)
+	status, _ = sendAs(api, root, "GET", "/api/v0/blueprints/info/alice-bp", ``)
+	require.Equal(t, http.StatusOK, status)
+
+	// private blueprints need an owner
+	status, _ = sendAs(api, alice, "POST", "/api/v1/blueprints/owner/alice-bp", `{"owner":""}`)
+	require.Equal(t, http.StatusBadRequest, status)
+
+	// the owner can give the blueprint away
+	status, _ = sendAs(api, alice, "POST", "/api/v1/blueprints/owner/alice-bp", `{"owner":"bob","private":false}`)
+	require.Equal(t, http.StatusOK, status)
+	status, _ = sendAs(api, alice, "DELETE", "/api/v0/blueprints/delete/alice-bp", ``)
+	require.Equal(t, http.StatusForbidden, status)
+	status, _ = sendAs(api, bob, "DELETE", "/api/v0/blueprints/delete/alice-bp", ``)
+	require.Equal(t, http.StatusOK, status)
+	_, exists = s.GetBlueprintOwnership("alice-bp")
+	require.False(t, exists)
+
+	// the blueprints without an owner can be changed by anyone
+	_, exists = s.GetBlueprintOwnership("test")
+	require.False(t, exists)
+	status, _ = sendAs(api, bob, "POST", "/api/v0/blueprints/workspace", `{"name":"test","description":"bob was here","version":"0.0.1"}`)
+	require.Equal(t, http.StatusOK, status)
+}
+
+func TestComposeScheduleOwnership(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, s := createWeldrAPI(t.TempDir(), rpmmd_mock.NoComposesFixture)
+	alice := requestUser{Name: "alice"}
+	bob := requestUser{Name: "bob"}
+	root := requestUser{Name: "root", Admin: true}
+
+	body := fmt.Sprintf(`{"cron":"0 3 * * sun","compose":{"blueprint_name":"test","compose_type":"%s"}}`, test_distro.TestImageTypeName)
+	status, reply := sendAs(api, alice, "POST", "/api/v1/compose/schedules/new", body)
+	require.Equal(t, http.StatusOK, status, reply)
+	var created struct {
+		ID uuid.UUID `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(reply), &created))
+	schedule, exists := s.GetComposeSchedule(created.ID)
+	require.True(t, exists)
+	require.Equal(t, "alice", schedule.Owner)
+
+	// the schedules of the other users are hidden from them
+	for _, u := range []requestUser{alice, root} {
+		status, reply = sendAs(api, u, "GET", "/api/v1/compose/schedules/list", "")
+		require.Equal(t, http.StatusOK, status)
+		require.Contains(t, reply, created.ID.String(), u.Name)
+	}
+	status, reply = sendAs(api, bob, "GET", "/api/v1/compose/schedules/list", "")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, `{"schedules":[]}`+"\n", reply)
+
+	status, _ = sendAs(api, bob, "GET", "/api/v1/compose/schedules/info/"+created.ID.String(), "")
+	require.Equal(t, http.StatusForbidden, status)
+	status, _ = sendAs(api, bob, "DELETE", "/api/v1/compose/schedules/delete/"+created.ID.String(), "")
+	require.Equal(t, http.StatusForbidden, status)
+	_, exists = s.GetComposeSchedule(created.ID)
+	require.True(t, exists)
+
+	status, _ = sendAs(api, alice, "GET", "/api/v1/compose/schedules/info/"+created.ID.String(), "")
+	require.Equal(t, http.StatusOK, status)
+	status, _ = sendAs(api, alice, "DELETE", "/api/v1/compose/schedules/delete/"+created.ID.String(), "")
+	require.Equal(t, http.StatusOK, status)
+	_, exists = s.GetComposeSchedule(created.ID)
+	require.False(t, exists)
+}
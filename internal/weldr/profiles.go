@@ -0,0 +1,312 @@
+package weldr
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/store"
+)
+
+// uploadProfilesKeyFile is the AES-256 key the settings of the upload
+// profiles are encrypted with, in the state directory. Only composer can
+// read it, it's generated when composer starts for the first time.
+const uploadProfilesKeyFile = "upload-profiles.key"
+
+// uploadProviders are the display names of the providers images can be
+// uploaded to.
+var uploadProviders = map[string]string{
+	"aws":         "AWS",
+	"aws.s3":      "AWS S3",
+	"azure":       "Azure",
+	"gcp":         "Google Cloud Platform",
+	"vmware":      "VMware vSphere",
+	"oci":         "Oracle Cloud Infrastructure",
+	"container":   "Container registry",
+	"pulp.ostree": "Pulp OSTree",
+}
+
+func loadUploadProfilesKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("the upload profiles key %s isn't 32 bytes long", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key = make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.Write(key)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return key, f.Close()
+}
+
+// uploadProfileCipher returns the cipher of the settings, the provider and
+// the name of the profile are authenticated with them so they can't be
+// swapped with the ones of another profile.
+func (api *API) uploadProfileCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(api.uploadProfilesKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (api *API) sealUploadSettings(provider, name string, settings uploadSettings) ([]byte, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := api.uploadProfileCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, []byte(provider+"/"+name)), nil
+}
+
+func (api *API) openUploadSettings(profile store.UploadProfile) (uploadSettings, error) {
+	aead, err := api.uploadProfileCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(profile.EncryptedSettings) < aead.NonceSize() {
+		return nil, errors.New("the encrypted settings are too short")
+	}
+	nonce := profile.EncryptedSettings[:aead.NonceSize()]
+	data, err := aead.Open(nil, nonce, profile.EncryptedSettings[aead.NonceSize():], []byte(profile.Provider+"/"+profile.Name))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt the settings: %v", err)
+	}
+
+	settings, err := newUploadSettings(profile.Provider)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// uploadProfileSettings returns the decrypted settings of the profile.
+func (api *API) uploadProfileSettings(provider, name string) (uploadSettings, error) {
+	profile, exists := api.store.GetUploadProfile(provider, name)
+	if !exists {
+		return nil, fmt.Errorf("Unknown upload profile %s of provider %s", name, provider)
+	}
+	settings, err := api.openUploadSettings(profile)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot load upload profile %s of provider %s: %v", name, provider, err)
+	}
+	return settings, nil
+}
+
+// redactUploadSettings returns the settings without the credentials, they
+// can be saved but never queried.
+func redactUploadSettings(settings uploadSettings) uploadSettings {
+	switch s := settings.(type) {
+	case *awsUploadSettings:
+		redacted := *s
+		redacted.AccessKeyID = ""
+		redacted.SecretAccessKey = ""
+		redacted.SessionToken = ""
+		return &redacted
+	case *awsS3UploadSettings:
+		redacted := *s
+		redacted.AccessKeyID = ""
+		redacted.SecretAccessKey = ""
+		redacted.SessionToken = ""
+		return &redacted
+	case *azureUploadSettings:
+		redacted := *s
+		redacted.StorageAccount = ""
+		redacted.StorageAccessKey = ""
+		return &redacted
+	case *gcpUploadSettings:
+		redacted := *s
+		redacted.Credentials = ""
+		return &redacted
+	case *vmwareUploadSettings:
+		redacted := *s
+		redacted.Username = ""
+		redacted.Password = ""
+		return &redacted
+	case *ociUploadSettings:
+		redacted := *s
+		redacted.PrivateKey = ""
+		return &redacted
+	case *containerUploadSettings:
+		redacted := *s
+		redacted.Username = ""
+		redacted.Password = ""
+		return &redacted
+	case *pulpOSTreeUploadSettings:
+		redacted := *s
+		redacted.Username = ""
+		redacted.Password = ""
+		return &redacted
+	}
+	return settings
+}
+
+// providersHandler returns the upload providers with their profiles, the
+// credentials of the profiles aren't included.
+func (api *API) providersHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type provider struct {
+		Display  string                    `json:"display"`
+		Profiles map[string]uploadSettings `json:"profiles"`
+	}
+	type reply struct {
+		Providers map[string]*provider `json:"providers"`
+	}
+
+	providers := make(map[string]*provider)
+	for name, display := range uploadProviders {
+		providers[name] = &provider{
+			Display:  display,
+			Profiles: make(map[string]uploadSettings),
+		}
+	}
+	for _, profile := range api.store.GetUploadProfiles() {
+		settings, err := api.openUploadSettings(profile)
+		if err != nil {
+			errors := responseError{
+				ID:  "ProviderError",
+				Msg: fmt.Sprintf("Cannot load upload profile %s of provider %s: %v", profile.Name, profile.Provider, err),
+			}
+			statusResponseError(writer, http.StatusInternalServerError, errors)
+			return
+		}
+		if providers[profile.Provider] == nil {
+			providers[profile.Provider] = &provider{
+				Display:  profile.Provider,
+				Profiles: make(map[string]uploadSettings),
+			}
+		}
+		providers[profile.Provider].Profiles[profile.Name] = redactUploadSettings(settings)
+	}
+
+	err := json.NewEncoder(writer).Encode(reply{providers})
+	common.PanicOnError(err)
+}
+
+// providersSaveHandler saves the upload profile, replacing the one of the
+// provider with the same name.
+func (api *API) providersSaveHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type saveRequest struct {
+		Provider string          `json:"provider"`
+		Profile  string          `json:"profile"`
+		Settings json.RawMessage `json:"settings"`
+	}
+
+	contentType := request.Header["Content-Type"]
+	if len(contentType) != 1 || contentType[0] != "application/json" {
+		errors := responseError{
+			ID:  "MissingPost",
+			Msg: "upload profile must be json",
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	var sr saveRequest
+	err := json.NewDecoder(request.Body).Decode(&sr)
+	if err != nil {
+		errors := responseError{
+			ID:  "ProviderError",
+			Msg: fmt.Sprintf("invalid upload profile: %v", err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	if !verifyStringsWithRegex(writer, []string{sr.Profile}, ValidBlueprintName) {
+		return
+	}
+
+	settings, err := newUploadSettings(sr.Provider)
+	if err == nil {
+		err = json.Unmarshal(sr.Settings, settings)
+	}
+	if err != nil {
+		errors := responseError{
+			ID:  "ProviderError",
+			Msg: fmt.Sprintf("invalid settings of provider %q: %v", sr.Provider, err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	encrypted, err := api.sealUploadSettings(sr.Provider, sr.Profile, settings)
+	if err == nil {
+		err = api.store.PushUploadProfile(store.UploadProfile{
+			Provider:          sr.Provider,
+			Name:              sr.Profile,
+			EncryptedSettings: encrypted,
+		})
+	}
+	if err != nil {
+		errors := responseError{
+			ID:  "ProviderError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+	statusResponseOK(writer)
+}
+
+func (api *API) providersDeleteHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	provider := params.ByName("provider")
+	profile := params.ByName("profile")
+	err := api.store.DeleteUploadProfile(provider, profile)
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownProfile",
+			Msg: fmt.Sprintf("Unknown upload profile %s of provider %s", profile, provider),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+	statusResponseOK(writer)
+}
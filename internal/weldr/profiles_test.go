@@ -0,0 +1,100 @@
+package weldr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/osbuild/images/pkg/distro/test_distro"
+	"github.com/stretchr/testify/require"
+
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/test"
+)
+
+func TestLoadUploadProfilesKey(t *testing.T) {
+	keyPath := path.Join(t.TempDir(), uploadProfilesKeyFile)
+
+	key, err := loadUploadProfilesKey(keyPath)
+	require.NoError(t, err)
+	require.Len(t, key, 32)
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := loadUploadProfilesKey(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, key, loaded)
+
+	require.NoError(t, os.WriteFile(keyPath, []byte("short"), 0600))
+	_, err = loadUploadProfilesKey(keyPath)
+	require.Error(t, err)
+}
+
+func TestUploadProfiles(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, s := createWeldrAPI(t.TempDir(), rpmmd_mock.NoComposesFixture)
+
+	test.TestRoute(t, api, false, "POST", "/api/v1/upload/providers/save", `{"provider":"aws","profile":"prod","settings":{"region":"frankfurt","accessKeyID":"accesskey","secretAccessKey":"secretkey","bucket":"clay","key":"imagekey"}}`, http.StatusOK, `{"status":true}`)
+	test.TestRoute(t, api, false, "POST", "/api/v1/upload/providers/save", `{"provider":"nimbus","profile":"prod","settings":{}}`, http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"ProviderError","msg":"invalid settings of provider \"nimbus\": unexpected provider name"}]}`)
+	test.TestRoute(t, api, false, "POST", "/api/v1/upload/providers/save", `{"provider":"aws","profile":"p r o d","settings":{}}`, http.StatusBadRequest, `{"status":false}`, "errors")
+
+	// the store only has the encrypted settings
+	profile, exists := s.GetUploadProfile("aws", "prod")
+	require.True(t, exists)
+	require.False(t, bytes.Contains(profile.EncryptedSettings, []byte("secretkey")))
+
+	// the credentials are never returned
+	test.TestRoute(t, api, false, "GET", "/api/v1/upload/providers", ``, http.StatusOK,
+		`{"providers":{
+			"aws":{"display":"AWS","profiles":{"prod":{"region":"frankfurt","bucket":"clay","key":"imagekey"}}},
+			"aws.s3":{"display":"AWS S3","profiles":{}},
+			"azure":{"display":"Azure","profiles":{}},
+			"container":{"display":"Container registry","profiles":{}},
+			"gcp":{"display":"Google Cloud Platform","profiles":{}},
+			"oci":{"display":"Oracle Cloud Infrastructure","profiles":{}},
+			"pulp.ostree":{"display":"Pulp OSTree","profiles":{}},
+			"vmware":{"display":"VMware vSphere","profiles":{}}}}`)
+
+	// the settings of composes are loaded from the profile
+	test.TestRoute(t, api, false, "POST", "/api/v1/compose", fmt.Sprintf(`{"blueprint_name":"test","compose_type":"%s","upload":{"image_name":"golden","provider":"aws","profile":"staging"}}`, test_distro.TestImageTypeName), http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UnknownProfile","msg":"Unknown upload profile staging of provider aws"}]}`)
+	test.TestRoute(t, api, false, "POST", "/api/v1/compose", fmt.Sprintf(`{"blueprint_name":"test","compose_type":"%s","upload":{"image_name":"golden","provider":"aws","profile":"prod","settings":{"region":"frankfurt"}}}`, test_distro.TestImageTypeName), http.StatusNotFound,
+		`{"status":false,"errors":[{"code":404,"id":"HTTPError","msg":"Not Found"}]}`)
+	reply := test.TestRouteWithReply(t, api, false, "POST", "/api/v1/compose", fmt.Sprintf(`{"blueprint_name":"test","compose_type":"%s","upload":{"image_name":"golden","provider":"aws","profile":"prod"}}`, test_distro.TestImageTypeName), http.StatusOK, `{"status":true}`, "build_id", "warnings")
+	var started struct {
+		BuildID uuid.UUID `json:"build_id"`
+	}
+	require.NoError(t, json.Unmarshal(reply, &started))
+	compose, exists := s.GetCompose(started.BuildID)
+	require.True(t, exists)
+	require.Len(t, compose.ImageBuild.Targets, 2)
+	options, ok := compose.ImageBuild.Targets[1].Options.(*target.AWSTargetOptions)
+	require.True(t, ok)
+	require.Equal(t, "accesskey", options.AccessKeyID)
+	require.Equal(t, "secretkey", options.SecretAccessKey)
+	require.Equal(t, "frankfurt", options.Region)
+
+	// the settings can't be moved to another profile
+	profile.Name = "staging"
+	require.NoError(t, s.PushUploadProfile(profile))
+	_, err := api.uploadProfileSettings("aws", "staging")
+	require.Error(t, err)
+	require.NoError(t, s.DeleteUploadProfile("aws", "staging"))
+
+	test.TestRoute(t, api, false, "DELETE", "/api/v1/upload/providers/delete/aws/prod", ``, http.StatusOK, `{"status":true}`)
+	test.TestRoute(t, api, false, "DELETE", "/api/v1/upload/providers/delete/aws/prod", ``, http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UnknownProfile","msg":"Unknown upload profile prod of provider aws"}]}`)
+	_, exists = s.GetUploadProfile("aws", "prod")
+	require.False(t, exists)
+}
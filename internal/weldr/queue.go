@@ -0,0 +1,125 @@
+package weldr
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/osbuild/images/pkg/distro"
+	"github.com/osbuild/images/pkg/manifest"
+
+	"github.com/osbuild/osbuild-composer/internal/store"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// SetMaxParallelComposes limits the number of composes building at the same
+// time, 0 removes the limit. The composes over the limit are deferred and
+// started once the running ones finish, the ones with priority first.
+func (api *API) SetMaxParallelComposes(max int) {
+	api.composeQueueMu.Lock()
+	defer api.composeQueueMu.Unlock()
+	api.maxParallelComposes = max
+}
+
+func (api *API) enqueueCompose(mf manifest.OSBuildManifest, imageType distro.ImageType, targets []*target.Target) (uuid.UUID, error) {
+	return api.workers.EnqueueOSBuild(api.archName, &worker.OSBuildJob{
+		Manifest: mf,
+		Targets:  targets,
+		PipelineNames: &worker.PipelineNames{
+			Build:   imageType.BuildPipelines(),
+			Payload: imageType.PayloadPipelines(),
+		},
+		ImageBootMode: imageType.BootMode().String(),
+	}, "")
+}
+
+// RunComposeQueue starts the deferred composes, checking for finished
+// composes once per interval.
+func (api *API) RunComposeQueue(interval time.Duration) {
+	for range time.Tick(interval) {
+		api.dispatchComposes()
+	}
+}
+
+// dispatchComposes enqueues the jobs of the deferred composes while there are
+// fewer composes building than the limit. The priority composes go first,
+// then the oldest ones.
+func (api *API) dispatchComposes() {
+	api.composeQueueMu.Lock()
+	defer api.composeQueueMu.Unlock()
+
+	type deferredCompose struct {
+		id      uuid.UUID
+		compose store.Compose
+	}
+
+	var deferred []deferredCompose
+	running := 0
+	for id, compose := range api.store.GetAllComposes() {
+		if compose.ImageBuild.Deferred {
+			deferred = append(deferred, deferredCompose{id, compose})
+			continue
+		}
+		if compose.ImageBuild.JobID == uuid.Nil {
+			continue
+		}
+		status, err := api.getComposeStatus(compose)
+		if err != nil {
+			log.Printf("Error getting the status of compose %s: %v", id, err)
+			continue
+		}
+		if status.State == ComposeWaiting || status.State == ComposeRunning {
+			running++
+		}
+	}
+	if len(deferred) == 0 {
+		return
+	}
+
+	sort.Slice(deferred, func(i, j int) bool {
+		bi, bj := deferred[i].compose.ImageBuild, deferred[j].compose.ImageBuild
+		if bi.Priority != bj.Priority {
+			return bi.Priority
+		}
+		return bi.JobCreated.Before(bj.JobCreated)
+	})
+
+	for _, d := range deferred {
+		if api.maxParallelComposes > 0 && running >= api.maxParallelComposes {
+			break
+		}
+		ib := d.compose.ImageBuild
+		jobID, err := api.enqueueCompose(ib.Manifest, ib.ImageType, ib.Targets)
+		if err != nil {
+			log.Printf("Error enqueueing the job of compose %s: %v", d.id, err)
+			continue
+		}
+		err = api.store.SetComposeJob(d.id, jobID)
+		if err != nil {
+			// the compose was deleted in the meantime
+			log.Printf("Error setting the job of compose %s: %v", d.id, err)
+			_ = api.workers.Cancel(jobID)
+			continue
+		}
+		running++
+	}
+}
+
+// cancelCompose cancels the job of the compose, a deferred compose fails
+// without its job ever being enqueued.
+func (api *API) cancelCompose(id uuid.UUID) error {
+	api.composeQueueMu.Lock()
+	defer api.composeQueueMu.Unlock()
+
+	compose, exists := api.store.GetCompose(id)
+	if !exists {
+		return fmt.Errorf("compose %s doesn't exist", id)
+	}
+	if compose.ImageBuild.Deferred {
+		return api.store.CancelDeferredCompose(id)
+	}
+	return api.workers.Cancel(compose.ImageBuild.JobID)
+}
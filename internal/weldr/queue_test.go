@@ -0,0 +1,76 @@
+package weldr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/osbuild/images/pkg/distro/test_distro"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/test"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestMaxParallelComposes(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, s := createWeldrAPI(t.TempDir(), rpmmd_mock.NoComposesFixture)
+	api.SetMaxParallelComposes(1)
+
+	startCompose := func(priority bool) uuid.UUID {
+		body := fmt.Sprintf(`{"blueprint_name":"test","compose_type":"%s","priority":%t}`, test_distro.TestImageTypeName, priority)
+		reply := test.TestRouteWithReply(t, api, false, "POST", "/api/v1/compose", body, http.StatusOK, `{"status":true}`, "build_id", "warnings")
+		var started struct {
+			BuildID uuid.UUID `json:"build_id"`
+		}
+		require.NoError(t, json.Unmarshal(reply, &started))
+		return started.BuildID
+	}
+	first := startCompose(false)
+	second := startCompose(false)
+	third := startCompose(true)
+
+	// only the first compose builds, the others wait for it
+	compose, _ := s.GetCompose(first)
+	require.False(t, compose.ImageBuild.Deferred)
+	require.NotEqual(t, uuid.Nil, compose.ImageBuild.JobID)
+	for _, id := range []uuid.UUID{second, third} {
+		compose, _ = s.GetCompose(id)
+		require.True(t, compose.ImageBuild.Deferred)
+		status, err := api.getComposeStatus(compose)
+		require.NoError(t, err)
+		require.Equal(t, ComposeWaiting, status.State)
+	}
+
+	// the priority compose starts once the first one finished
+	_, token, _, _, _, err := api.workers.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeOSBuild}, []string{""})
+	require.NoError(t, err)
+	api.dispatchComposes()
+	compose, _ = s.GetCompose(third)
+	require.True(t, compose.ImageBuild.Deferred)
+	rawResult, err := json.Marshal(worker.OSBuildJobResult{Success: true})
+	require.NoError(t, err)
+	require.NoError(t, api.workers.FinishJob(token, rawResult))
+	api.dispatchComposes()
+	compose, _ = s.GetCompose(third)
+	require.False(t, compose.ImageBuild.Deferred)
+	require.NotEqual(t, uuid.Nil, compose.ImageBuild.JobID)
+	compose, _ = s.GetCompose(second)
+	require.True(t, compose.ImageBuild.Deferred)
+
+	// deferred composes fail without building
+	test.TestRoute(t, api, false, "DELETE", fmt.Sprintf("/api/v1/compose/cancel/%s", second), ``, http.StatusOK, fmt.Sprintf(`{"uuid":"%s","status":true}`, second))
+	compose, _ = s.GetCompose(second)
+	require.False(t, compose.ImageBuild.Deferred)
+	require.Equal(t, common.IBFailed, compose.ImageBuild.QueueStatus)
+	require.Equal(t, uuid.Nil, compose.ImageBuild.JobID)
+}
@@ -0,0 +1,168 @@
+package weldr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/reporegistry"
+)
+
+// getRepoRegistry returns the repositories loaded last.
+func (api *API) getRepoRegistry() *reporegistry.RepoRegistry {
+	api.reposMu.RLock()
+	defer api.reposMu.RUnlock()
+	return api.repoRegistry
+}
+
+// getDistros returns the names of the distros with repositories, sorted.
+func (api *API) getDistros() []string {
+	api.reposMu.RLock()
+	defer api.reposMu.RUnlock()
+	return api.distros
+}
+
+// repositoriesFingerprint describes the repository files in the repositories
+// directories of the paths, it changes when any of them is added, removed or
+// modified.
+func repositoriesFingerprint(repoPaths []string) (string, error) {
+	var files []string
+	for _, repoPath := range repoPaths {
+		reposPath := filepath.Join(repoPath, "repositories")
+		entries, err := os.ReadDir(reposPath)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+			info, err := entry.Info()
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return "", err
+			}
+			files = append(files, fmt.Sprintf("%s:%d:%d", filepath.Join(reposPath, entry.Name()), info.Size(), info.ModTime().UnixNano()))
+		}
+	}
+	sort.Strings(files)
+	return strings.Join(files, "\n"), nil
+}
+
+// ReloadRepositories loads the repository definitions again. The ones loaded
+// before are kept if the new ones can't be loaded.
+func (api *API) ReloadRepositories() error {
+	fingerprint, err := repositoriesFingerprint(api.repoPaths)
+	if err == nil {
+		var rr *reporegistry.RepoRegistry
+		rr, err = reporegistry.New(api.repoPaths)
+		if err == nil {
+			logger := api.logger
+			if logger == nil {
+				logger = log.Default()
+			}
+			distros := validDistros(rr, api.distroRegistry, api.archName, logger)
+
+			api.reposMu.Lock()
+			api.repoRegistry = rr
+			api.distros = distros
+			api.reposFingerprint = fingerprint
+			api.reposLoaded = time.Now()
+			api.reposError = nil
+			api.reposMu.Unlock()
+
+			log.Printf("Reloaded repository definitions, distros with repositories: %s", strings.Join(distros, ", "))
+			return nil
+		}
+	}
+
+	api.reposMu.Lock()
+	api.reposError = err
+	api.reposMu.Unlock()
+	return fmt.Errorf("error reloading repository definitions: %v", err)
+}
+
+// WatchRepositories reloads the repository definitions whenever the
+// repository files change, checking them once per interval.
+func (api *API) WatchRepositories(interval time.Duration) {
+	for range time.Tick(interval) {
+		fingerprint, err := repositoriesFingerprint(api.repoPaths)
+		if err != nil {
+			log.Printf("Error checking repository definitions for changes: %v", err)
+			continue
+		}
+
+		api.reposMu.RLock()
+		changed := fingerprint != api.reposFingerprint
+		api.reposMu.RUnlock()
+		if !changed {
+			continue
+		}
+
+		err = api.ReloadRepositories()
+		if err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+type repositoriesStatusReply struct {
+	Loaded  time.Time `json:"loaded"`
+	Distros []string  `json:"distros"`
+	// Error of the last reload, if it failed
+	Error string `json:"error,omitempty"`
+}
+
+func (api *API) repositoriesStatus() repositoriesStatusReply {
+	api.reposMu.RLock()
+	defer api.reposMu.RUnlock()
+
+	reply := repositoriesStatusReply{
+		Loaded:  api.reposLoaded,
+		Distros: api.distros,
+	}
+	if api.reposError != nil {
+		reply.Error = api.reposError.Error()
+	}
+	return reply
+}
+
+func (api *API) repositoriesStatusHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	err := json.NewEncoder(writer).Encode(api.repositoriesStatus())
+	common.PanicOnError(err)
+}
+
+func (api *API) repositoriesReloadHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	err := api.ReloadRepositories()
+	if err != nil {
+		errors := responseError{
+			ID:  "RepositoriesError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(api.repositoriesStatus())
+	common.PanicOnError(err)
+}
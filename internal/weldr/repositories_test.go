@@ -0,0 +1,69 @@
+package weldr
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/osbuild/images/pkg/distro/test_distro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/test"
+)
+
+func writeRepositories(t *testing.T, dir, distro, content string) {
+	t.Helper()
+	reposPath := filepath.Join(dir, "repositories")
+	require.NoError(t, os.MkdirAll(reposPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reposPath, distro+".json"), []byte(content), 0600))
+}
+
+func TestRepositoriesFingerprint(t *testing.T) {
+	dir := t.TempDir()
+
+	empty, err := repositoriesFingerprint([]string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, "", empty)
+
+	writeRepositories(t, dir, test_distro.TestDistroName, `{}`)
+	fingerprint, err := repositoriesFingerprint([]string{dir})
+	require.NoError(t, err)
+	assert.NotEqual(t, empty, fingerprint)
+
+	// modified files change the fingerprint
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "repositories", test_distro.TestDistroName+".json"), later, later))
+	modified, err := repositoriesFingerprint([]string{dir})
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, modified)
+}
+
+func TestRepositoriesReload(t *testing.T) {
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	dir := t.TempDir()
+	api.repoPaths = []string{dir}
+
+	test.TestRoute(t, api, false, "GET", "/api/v1/repositories/status", ``, http.StatusOK, `{"distros": ["test-distro", "test-distro-2"]}`, "loaded")
+
+	// the definitions of test-distro-2 were removed
+	writeRepositories(t, dir, test_distro.TestDistroName, `{
+		"test_arch": [{"name": "reloaded", "baseurl": "http://example.com/reloaded/os"}]
+	}`)
+	test.TestRoute(t, api, false, "POST", "/api/v1/repositories/reload", ``, http.StatusOK, `{"distros": ["test-distro"]}`, "loaded")
+	test.TestRoute(t, api, false, "GET", "/api/v1/distros/list", ``, http.StatusOK, `{"distros": ["test-distro"]}`)
+	repos, err := api.allRepositories(test_distro.TestDistroName)
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	assert.Equal(t, "reloaded", repos[0].Name)
+
+	// invalid definitions keep the ones loaded before
+	writeRepositories(t, dir, test_distro.TestDistroName, `{`)
+	test.TestRoute(t, api, false, "POST", "/api/v1/repositories/reload", ``, http.StatusBadRequest, `{"status": false, "errors": [{"id": "RepositoriesError"}]}`, "msg")
+	reply := api.repositoriesStatus()
+	assert.Equal(t, []string{test_distro.TestDistroName}, reply.Distros)
+	assert.NotEmpty(t, reply.Error)
+}
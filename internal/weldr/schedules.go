@@ -0,0 +1,344 @@
+package weldr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/store"
+)
+
+type composeScheduleInfo struct {
+	ID            uuid.UUID               `json:"id"`
+	Cron          string                  `json:"cron"`
+	BlueprintName string                  `json:"blueprint_name"`
+	ComposeType   string                  `json:"compose_type"`
+	Upload        *composeScheduleUpload  `json:"upload,omitempty"`
+	Created       float64                 `json:"created"`
+	Next          float64                 `json:"next,omitempty"`
+	History       []scheduledComposeEntry `json:"history,omitempty"`
+}
+
+// composeScheduleUpload is the upload of the composes of a schedule, the
+// settings are intentionally not included, they contain the credentials.
+type composeScheduleUpload struct {
+	Provider  string `json:"provider"`
+	ImageName string `json:"image_name"`
+	Profile   string `json:"profile,omitempty"`
+}
+
+type scheduledComposeEntry struct {
+	Started   float64    `json:"started"`
+	ComposeID *uuid.UUID `json:"compose_id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+func unixTime(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / 1000000000
+}
+
+func composeScheduleToInfo(schedule store.ComposeSchedule, history bool) composeScheduleInfo {
+	info := composeScheduleInfo{
+		ID:      schedule.ID,
+		Cron:    schedule.Cron,
+		Created: unixTime(schedule.Created),
+		Next:    unixTime(schedule.Next),
+	}
+
+	// the request was validated when the schedule was created
+	var cr composeRequest
+	err := json.Unmarshal(schedule.Request, &cr)
+	if err == nil {
+		info.BlueprintName = cr.BlueprintName
+		info.ComposeType = cr.ComposeType
+		if cr.Upload != nil {
+			info.Upload = &composeScheduleUpload{
+				Provider:  cr.Upload.Provider,
+				ImageName: cr.Upload.ImageName,
+				Profile:   cr.Upload.Profile,
+			}
+		}
+	}
+
+	if history {
+		info.History = []scheduledComposeEntry{}
+		for _, compose := range schedule.History {
+			entry := scheduledComposeEntry{
+				Started: unixTime(compose.Started),
+				Error:   compose.Error,
+			}
+			if compose.ComposeID != uuid.Nil {
+				entry.ComposeID = common.ToPtr(compose.ComposeID)
+			}
+			info.History = append(info.History, entry)
+		}
+	}
+	return info
+}
+
+// composeScheduleNewHandler schedules composes of the blueprint on a cron
+// expression, with the request of the compose route.
+func (api *API) composeScheduleNewHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type scheduleRequest struct {
+		Cron    string          `json:"cron"`
+		Compose json.RawMessage `json:"compose"`
+	}
+	type scheduleReply struct {
+		ID     uuid.UUID `json:"id"`
+		Status bool      `json:"status"`
+		Next   float64   `json:"next"`
+	}
+
+	contentType := request.Header["Content-Type"]
+	if len(contentType) != 1 || contentType[0] != "application/json" {
+		errors := responseError{
+			ID:  "MissingPost",
+			Msg: "schedule must be json",
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	var sr scheduleRequest
+	var cr composeRequest
+	err := json.NewDecoder(request.Body).Decode(&sr)
+	if err == nil {
+		err = json.Unmarshal(sr.Compose, &cr)
+	}
+	if err != nil {
+		errors := responseError{
+			ID:  "ScheduleError",
+			Msg: fmt.Sprintf("invalid schedule: %v", err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	cron, err := parseCron(sr.Cron)
+	if err != nil {
+		errors := responseError{
+			ID:  "ScheduleError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+	now := time.Now()
+	next := cron.next(now)
+	if next.IsZero() {
+		errors := responseError{
+			ID:  "ScheduleError",
+			Msg: fmt.Sprintf("the cron expression %q never matches", sr.Cron),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	if !verifyStringsWithRegex(writer, []string{cr.BlueprintName}, ValidBlueprintName) {
+		return
+	}
+	if !api.verifyBlueprintsReadable(writer, request, []string{cr.BlueprintName}) {
+		return
+	}
+	bp := api.store.GetBlueprintCommitted(cr.BlueprintName)
+	if bp == nil {
+		errors := responseError{
+			ID:  "UnknownBlueprint",
+			Msg: fmt.Sprintf("Unknown blueprint name: %s", cr.BlueprintName),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+	distroName := bp.Distro
+	if distroName == "" {
+		distroName = api.hostDistroName
+	}
+	_, err = api.getImageType(distroName, cr.ComposeType)
+	if err != nil {
+		errors := responseError{
+			ID:  "ComposeError",
+			Msg: fmt.Sprintf("Failed to get compose type %q: %v", cr.ComposeType, err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	schedule := store.ComposeSchedule{
+		ID:      uuid.New(),
+		Cron:    sr.Cron,
+		Request: sr.Compose,
+		Created: now,
+		Next:    next,
+	}
+	if u, known := getRequestUser(request); known {
+		schedule.Owner = u.Name
+	}
+	err = api.store.PushComposeSchedule(schedule)
+	if err != nil {
+		errors := responseError{
+			ID:  "ScheduleError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(scheduleReply{
+		ID:     schedule.ID,
+		Status: true,
+		Next:   unixTime(next),
+	})
+	common.PanicOnError(err)
+}
+
+func (api *API) composeScheduleListHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type reply struct {
+		Schedules []composeScheduleInfo `json:"schedules"`
+	}
+
+	// the schedules of the other users aren't listed
+	schedules := []composeScheduleInfo{}
+	for _, schedule := range api.store.GetAllComposeSchedules() {
+		if canAccessSchedule(request, schedule) {
+			schedules = append(schedules, composeScheduleToInfo(schedule, false))
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Created < schedules[j].Created
+	})
+
+	err := json.NewEncoder(writer).Encode(reply{schedules})
+	common.PanicOnError(err)
+}
+
+// composeScheduleInfoHandler returns the schedule with the composes it
+// started.
+func (api *API) composeScheduleInfoHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	id, err := uuid.Parse(params.ByName("uuid"))
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("%s is not a valid uuid", params.ByName("uuid")),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	schedule, exists := api.store.GetComposeSchedule(id)
+	if !exists {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("schedule %s doesn't exist", id),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+	if !verifyScheduleAccessible(writer, request, schedule) {
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(composeScheduleToInfo(schedule, true))
+	common.PanicOnError(err)
+}
+
+// composeScheduleDeleteHandler deletes the schedule, the composes it started
+// aren't deleted.
+func (api *API) composeScheduleDeleteHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	id, err := uuid.Parse(params.ByName("uuid"))
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("%s is not a valid uuid", params.ByName("uuid")),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	schedule, exists := api.store.GetComposeSchedule(id)
+	if exists && !verifyScheduleAccessible(writer, request, schedule) {
+		return
+	}
+
+	err = api.store.DeleteComposeSchedule(id)
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("schedule %s doesn't exist", id),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+	statusResponseOK(writer)
+}
+
+// RunComposeSchedules starts the composes of the schedules, checking them
+// once per interval.
+func (api *API) RunComposeSchedules(interval time.Duration) {
+	for range time.Tick(interval) {
+		api.runComposeSchedules(time.Now())
+	}
+}
+
+// runComposeSchedules starts the composes of the schedules due at now. A
+// schedule due several times while composer wasn't running starts a single
+// compose.
+func (api *API) runComposeSchedules(now time.Time) {
+	for id, schedule := range api.store.GetAllComposeSchedules() {
+		if schedule.Next.IsZero() || schedule.Next.After(now) {
+			continue
+		}
+
+		compose := store.ScheduledCompose{Started: now}
+		var cr composeRequest
+		err := json.Unmarshal(schedule.Request, &cr)
+		if err != nil {
+			compose.Error = err.Error()
+		} else {
+			var composeErr *composeError
+			compose.ComposeID, _, composeErr = api.startCompose(cr, true, "")
+			if composeErr != nil {
+				compose.Error = composeErr.err.Msg
+			}
+		}
+		if compose.Error != "" {
+			log.Printf("Error starting the compose of schedule %s: %s", id, compose.Error)
+		}
+
+		var next time.Time
+		cron, err := parseCron(schedule.Cron)
+		if err == nil {
+			next = cron.next(now)
+		}
+		err = api.store.PushScheduledCompose(id, compose, next)
+		if err != nil {
+			// the schedule was deleted in the meantime
+			log.Printf("Error recording the compose of schedule %s: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package weldr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/osbuild/images/pkg/distro/test_distro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/test"
+)
+
+func TestCronNext(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2023, time.March, 15, 10, 30, 20, 0, time.UTC)
+	cases := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2023, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2023, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * sun", time.Date(2023, time.March, 19, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 7", time.Date(2023, time.March, 19, 3, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2023, time.March, 19, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 10 15 3 *", time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * mon-fri", time.Date(2023, time.March, 15, 13, 0, 0, 0, time.UTC)},
+		// either the day of month or the day of week matches
+		{"0 0 20 * fri", time.Date(2023, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		cron, err := parseCron(c.expr)
+		require.NoError(t, err, c.expr)
+		assert.Equal(t, c.next, cron.next(now), c.expr)
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "@never", "0 0 * * monday"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestComposeSchedules(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, s := createWeldrAPI(t.TempDir(), rpmmd_mock.NoComposesFixture)
+
+	test.TestRoute(t, api, false, "POST", "/api/v1/compose/schedules/new", `{"cron":"0 3 * * sun","compose":{"blueprint_name":"unknown","compose_type":"test_type"}}`, http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UnknownBlueprint","msg":"Unknown blueprint name: unknown"}]}`)
+	test.TestRoute(t, api, false, "POST", "/api/v1/compose/schedules/new", `{"cron":"0 3 * *","compose":{"blueprint_name":"test","compose_type":"test_type"}}`, http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"ScheduleError","msg":"the cron expression \"0 3 * *\" doesn't have 5 fields"}]}`)
+	test.TestRoute(t, api, false, "POST", "/api/v0/compose/schedules/new", `{"cron":"0 3 * * sun","compose":{"blueprint_name":"test","compose_type":"test_type"}}`, http.StatusNotFound, "*")
+
+	body := fmt.Sprintf(`{"cron":"0 3 * * sun","compose":{"blueprint_name":"test","compose_type":"%s","upload":{"image_name":"golden","provider":"aws","settings":{"region":"frankfurt","accessKeyID":"accesskey","secretAccessKey":"secretkey","bucket":"clay","key":"imagekey"}}}}`, test_distro.TestImageTypeName)
+	reply := test.TestRouteWithReply(t, api, false, "POST", "/api/v1/compose/schedules/new", body, http.StatusOK, `{"status":true}`, "id", "next")
+	var created struct {
+		ID   uuid.UUID `json:"id"`
+		Next float64   `json:"next"`
+	}
+	require.NoError(t, json.Unmarshal(reply, &created))
+	schedule, exists := s.GetComposeSchedule(created.ID)
+	require.True(t, exists)
+	require.Equal(t, time.Sunday, schedule.Next.Weekday())
+	require.Equal(t, 3, schedule.Next.Hour())
+
+	// the credentials of the upload aren't returned
+	test.TestRoute(t, api, false, "GET", "/api/v1/compose/schedules/list", ``, http.StatusOK,
+		fmt.Sprintf(`{"schedules":[{"id":"%s","cron":"0 3 * * sun","blueprint_name":"test","compose_type":"%s","upload":{"provider":"aws","image_name":"golden"}}]}`, created.ID, test_distro.TestImageTypeName), "created", "next")
+
+	// nothing is due before the next time
+	api.runComposeSchedules(schedule.Next.Add(-time.Minute))
+	require.Empty(t, s.GetAllComposes())
+
+	api.runComposeSchedules(schedule.Next)
+	composes := s.GetAllComposes()
+	require.Len(t, composes, 1)
+	schedule, _ = s.GetComposeSchedule(created.ID)
+	require.Len(t, schedule.History, 1)
+	compose, exists := composes[schedule.History[0].ComposeID]
+	require.True(t, exists)
+	require.Len(t, compose.ImageBuild.Targets, 2)
+	require.Equal(t, "golden", compose.ImageBuild.Targets[1].ImageName)
+	require.Equal(t, schedule.History[0].Started.AddDate(0, 0, 7), schedule.Next)
+
+	test.TestRoute(t, api, false, "GET", "/api/v1/compose/schedules/info/"+created.ID.String(), ``, http.StatusOK,
+		fmt.Sprintf(`{"id":"%s","cron":"0 3 * * sun","blueprint_name":"test","compose_type":"%s","upload":{"provider":"aws","image_name":"golden"},"history":[{"compose_id":"%s"}]}`, created.ID, test_distro.TestImageTypeName, schedule.History[0].ComposeID), "created", "next", "started")
+
+	// composes which can't be started are in the history too
+	require.NoError(t, s.DeleteBlueprint("test"))
+	api.runComposeSchedules(schedule.Next)
+	schedule, _ = s.GetComposeSchedule(created.ID)
+	require.Len(t, schedule.History, 2)
+	require.Equal(t, uuid.Nil, schedule.History[1].ComposeID)
+	require.Equal(t, "Unknown blueprint name: test", schedule.History[1].Error)
+
+	test.TestRoute(t, api, false, "DELETE", "/api/v1/compose/schedules/delete/"+created.ID.String(), ``, http.StatusOK, `{"status":true}`)
+	test.TestRoute(t, api, false, "GET", "/api/v1/compose/schedules/info/"+created.ID.String(), ``, http.StatusBadRequest,
+		fmt.Sprintf(`{"status":false,"errors":[{"id":"UnknownUUID","msg":"schedule %s doesn't exist"}]}`, created.ID))
+	// the composes of the schedule are kept
+	require.Len(t, s.GetAllComposes(), 1)
+}
@@ -99,11 +99,18 @@ type ociUploadSettings struct {
 
 func (ociUploadSettings) isUploadSettings() {}
 
+// containerUploadSettings push the image to the registry of its image name,
+// a reference like registry.example.com/edge/image:tag.
 type containerUploadSettings struct {
 	Username string `json:"username,omitempty"`
 	Password string `json:"password,omitempty"`
 
 	TlsVerify *bool `json:"tls_verify,omitempty"`
+	// Sign the image with the cosign key of the worker
+	Sign bool `json:"sign,omitempty"`
+	// Tags the image is pushed with in addition to the one of its image
+	// name, in the same repository
+	Tags []string `json:"tags,omitempty"`
 }
 
 func (containerUploadSettings) isUploadSettings() {}
@@ -122,12 +129,16 @@ type uploadRequest struct {
 	Provider  string         `json:"provider"`
 	ImageName string         `json:"image_name"`
 	Settings  uploadSettings `json:"settings"`
+	// Upload profile of the provider with the settings, instead of passing
+	// them in the request
+	Profile string `json:"profile,omitempty"`
 }
 
 type rawUploadRequest struct {
 	Provider  string          `json:"provider"`
 	ImageName string          `json:"image_name"`
 	Settings  json.RawMessage `json:"settings"`
+	Profile   string          `json:"profile"`
 }
 
 func (u *uploadRequest) UnmarshalJSON(data []byte) error {
@@ -137,8 +148,67 @@ func (u *uploadRequest) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	if rawUploadRequest.Profile != "" {
+		if len(rawUploadRequest.Settings) != 0 && string(rawUploadRequest.Settings) != "null" {
+			return errors.New("upload settings and profile are mutually exclusive")
+		}
+		// the settings are loaded from the profile when the compose starts
+		_, err = newUploadSettings(rawUploadRequest.Provider)
+		if err != nil {
+			return err
+		}
+		u.Provider = rawUploadRequest.Provider
+		u.ImageName = rawUploadRequest.ImageName
+		u.Profile = rawUploadRequest.Profile
+		return nil
+	}
+
+	settings, err := newUploadSettings(rawUploadRequest.Provider)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(rawUploadRequest.Settings, settings)
+	if err != nil {
+		return err
+	}
+
+	u.Provider = rawUploadRequest.Provider
+	u.ImageName = rawUploadRequest.ImageName
+	u.Settings = settings
+
+	return err
+}
+
+// containerRepository returns the repository of the container reference,
+// without its tag or digest.
+func containerRepository(reference string) string {
+	if i := strings.LastIndex(reference, "@"); i >= 0 {
+		reference = reference[:i]
+	}
+	if i := strings.LastIndex(reference, ":"); i > strings.LastIndex(reference, "/") {
+		reference = reference[:i]
+	}
+	return reference
+}
+
+// checkContainerUpload returns an error if the image can't be pushed with
+// the settings.
+func checkContainerUpload(imageName string, settings *containerUploadSettings) error {
+	if imageName == "" {
+		return errors.New("container uploads need the reference the image is pushed to as their image name")
+	}
+	for _, tag := range settings.Tags {
+		if !ValidContainerTag.MatchString(tag) {
+			return fmt.Errorf("invalid container tag %q", tag)
+		}
+	}
+	return nil
+}
+
+// newUploadSettings returns empty settings of the provider.
+func newUploadSettings(provider string) (uploadSettings, error) {
 	var settings uploadSettings
-	switch rawUploadRequest.Provider {
+	switch provider {
 	case "azure":
 		settings = new(azureUploadSettings)
 	case "aws":
@@ -160,18 +230,9 @@ func (u *uploadRequest) UnmarshalJSON(data []byte) error {
 	case "pulp.ostree":
 		settings = new(pulpOSTreeUploadSettings)
 	default:
-		return errors.New("unexpected provider name")
+		return nil, errors.New("unexpected provider name")
 	}
-	err = json.Unmarshal(rawUploadRequest.Settings, settings)
-	if err != nil {
-		return err
-	}
-
-	u.Provider = rawUploadRequest.Provider
-	u.ImageName = rawUploadRequest.ImageName
-	u.Settings = settings
-
-	return err
+	return settings, nil
 }
 
 // Converts a `Target` to a serializable `uploadResponse`.
@@ -247,6 +308,18 @@ func targetsToUploadResponses(targets []*target.Target, state ComposeState) []up
 				// AccessKeyID and SecretAccessKey are intentionally not included.
 			}
 			uploads = append(uploads, upload)
+		case *target.ContainerTargetOptions:
+			upload.ProviderName = "container"
+			settings := &containerUploadSettings{
+				TlsVerify: options.TlsVerify,
+				Sign:      options.Sign,
+				// Username and Password are intentionally not included.
+			}
+			for _, reference := range options.AdditionalReferences {
+				settings.Tags = append(settings.Tags, reference[len(containerRepository(reference))+1:])
+			}
+			upload.Settings = settings
+			uploads = append(uploads, upload)
 		case *target.PulpOSTreeTargetOptions:
 			upload.ProviderName = "pulp.ostree"
 			upload.Settings = &pulpOSTreeUploadSettings{
@@ -378,12 +451,17 @@ func uploadRequestToTarget(u uploadRequest, imageType distro.ImageType) *target.
 		}
 	case *containerUploadSettings:
 		t.Name = target.TargetNameContainer
-		t.Options = &target.ContainerTargetOptions{
+		targetOptions := &target.ContainerTargetOptions{
 			Username: options.Username,
 			Password: options.Password,
 
 			TlsVerify: options.TlsVerify,
+			Sign:      options.Sign,
+		}
+		for _, tag := range options.Tags {
+			targetOptions.AdditionalReferences = append(targetOptions.AdditionalReferences, containerRepository(u.ImageName)+":"+tag)
 		}
+		t.Options = targetOptions
 	case *pulpOSTreeUploadSettings:
 		t.Name = target.TargetNamePulpOSTree
 		convertedOptions := target.PulpOSTreeTargetOptions(*options)
@@ -0,0 +1,97 @@
+package weldr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/osbuild/images/pkg/distro/test_distro"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/test"
+)
+
+func TestContainerRepository(t *testing.T) {
+	require.Equal(t, "registry.example.com/edge/image", containerRepository("registry.example.com/edge/image:v1"))
+	require.Equal(t, "registry.example.com:5000/edge/image", containerRepository("registry.example.com:5000/edge/image"))
+	require.Equal(t, "registry.example.com:5000/edge/image", containerRepository("registry.example.com:5000/edge/image:v1"))
+	require.Equal(t, "registry.example.com/edge/image", containerRepository("registry.example.com/edge/image@sha256:0123"))
+}
+
+func TestContainerUpload(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, s := createWeldrAPI(t.TempDir(), rpmmd_mock.NoComposesFixture)
+
+	test.TestRoute(t, api, false, "POST", "/api/v1/compose", fmt.Sprintf(`{"blueprint_name":"test","compose_type":"%s","upload":{"image_name":"","provider":"container","settings":{}}}`, test_distro.TestImageTypeName), http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UploadError","msg":"container uploads need the reference the image is pushed to as their image name"}]}`)
+	test.TestRoute(t, api, false, "POST", "/api/v1/compose", fmt.Sprintf(`{"blueprint_name":"test","compose_type":"%s","upload":{"image_name":"registry.example.com/edge/image:v1","provider":"container","settings":{"tags":["bad/tag"]}}}`, test_distro.TestImageTypeName), http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UploadError","msg":"invalid container tag \"bad/tag\""}]}`)
+
+	reply := test.TestRouteWithReply(t, api, false, "POST", "/api/v1/compose", fmt.Sprintf(`{"blueprint_name":"test","compose_type":"%s","upload":{"image_name":"registry.example.com/edge/image:v1","provider":"container","settings":{"username":"user","password":"secret","tls_verify":false,"sign":true,"tags":["latest"]}}}`, test_distro.TestImageTypeName), http.StatusOK, `{"status":true}`, "build_id", "warnings")
+	var started struct {
+		BuildID uuid.UUID `json:"build_id"`
+	}
+	require.NoError(t, json.Unmarshal(reply, &started))
+	compose, exists := s.GetCompose(started.BuildID)
+	require.True(t, exists)
+	require.Len(t, compose.ImageBuild.Targets, 2)
+	require.Equal(t, "registry.example.com/edge/image:v1", compose.ImageBuild.Targets[1].ImageName)
+	require.Equal(t, &target.ContainerTargetOptions{
+		Username:             "user",
+		Password:             "secret",
+		TlsVerify:            common.ToPtr(false),
+		Sign:                 true,
+		AdditionalReferences: []string{"registry.example.com/edge/image:latest"},
+	}, compose.ImageBuild.Targets[1].Options)
+
+	// the credentials are never returned
+	uploads := targetsToUploadResponses(compose.ImageBuild.Targets, ComposeWaiting)
+	require.Len(t, uploads, 1)
+	require.Equal(t, "container", uploads[0].ProviderName)
+	require.Equal(t, &containerUploadSettings{
+		TlsVerify: common.ToPtr(false),
+		Sign:      true,
+		Tags:      []string{"latest"},
+	}, uploads[0].Settings)
+}
+
+func TestPulpOSTreeUpload(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, s := createWeldrAPI(t.TempDir(), rpmmd_mock.NoComposesFixture)
+
+	reply := test.TestRouteWithReply(t, api, false, "POST", "/api/v1/compose", fmt.Sprintf(`{"blueprint_name":"test","compose_type":"%s","upload":{"image_name":"commit","provider":"pulp.ostree","settings":{"server_address":"https://pulp.example.com","repository":"edge","basepath":"edge/x86_64","username":"user","password":"secret"}}}`, test_distro.TestImageTypeName), http.StatusOK, `{"status":true}`, "build_id", "warnings")
+	var started struct {
+		BuildID uuid.UUID `json:"build_id"`
+	}
+	require.NoError(t, json.Unmarshal(reply, &started))
+	compose, exists := s.GetCompose(started.BuildID)
+	require.True(t, exists)
+	require.Len(t, compose.ImageBuild.Targets, 2)
+	require.Equal(t, &target.PulpOSTreeTargetOptions{
+		ServerAddress: "https://pulp.example.com",
+		Repository:    "edge",
+		BasePath:      "edge/x86_64",
+		Username:      "user",
+		Password:      "secret",
+	}, compose.ImageBuild.Targets[1].Options)
+
+	uploads := targetsToUploadResponses(compose.ImageBuild.Targets, ComposeWaiting)
+	require.Len(t, uploads, 1)
+	require.Equal(t, &pulpOSTreeUploadSettings{
+		ServerAddress: "https://pulp.example.com",
+		Repository:    "edge",
+		BasePath:      "edge/x86_64",
+	}, uploads[0].Settings)
+}
@@ -23,6 +23,14 @@ const (
 	BearerScopes = "Bearer.Scopes"
 )
 
+// ArtifactStatusResponse defines model for ArtifactStatusResponse.
+type ArtifactStatusResponse struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Offset int64 `json:"offset"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
@@ -51,10 +59,29 @@ type ObjectReference struct {
 	Kind string `json:"kind"`
 }
 
+// RenewCertificateRequest defines model for RenewCertificateRequest.
+type RenewCertificateRequest struct {
+	// PEM encoded certificate signing request
+	Csr string `json:"csr"`
+}
+
+// RenewCertificateResponse defines model for RenewCertificateResponse.
+type RenewCertificateResponse struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// PEM encoded client certificate
+	Certificate string `json:"certificate"`
+}
+
 // RequestJobRequest defines model for RequestJobRequest.
 type RequestJobRequest struct {
 	Arch  string   `json:"arch"`
 	Types []string `json:"types"`
+
+	// ID of the worker, it receives the jobs pinned to it in addition
+	// to the other jobs
+	WorkerId *string `json:"worker_id,omitempty"`
 }
 
 // RequestJobResponse defines model for RequestJobResponse.
@@ -85,12 +112,26 @@ type UpdateJobRequest struct {
 // UpdateJobResponse defines model for UpdateJobResponse.
 type UpdateJobResponse ObjectReference
 
+// RenewCertificateJSONBody defines parameters for RenewCertificate.
+type RenewCertificateJSONBody RenewCertificateRequest
+
 // RequestJobJSONBody defines parameters for RequestJob.
 type RequestJobJSONBody RequestJobRequest
 
 // UpdateJobJSONBody defines parameters for UpdateJob.
 type UpdateJobJSONBody UpdateJobRequest
 
+// UploadJobArtifactParams defines parameters for UploadJobArtifact.
+type UploadJobArtifactParams struct {
+	Offset *int64 `json:"offset,omitempty"`
+
+	// SHA256 checksum of the complete artifact
+	Checksum *string `json:"checksum,omitempty"`
+}
+
+// RenewCertificateJSONRequestBody defines body for RenewCertificate for application/json ContentType.
+type RenewCertificateJSONRequestBody RenewCertificateJSONBody
+
 // RequestJobJSONRequestBody defines body for RequestJob for application/json ContentType.
 type RequestJobJSONRequestBody RequestJobJSONBody
 
@@ -99,6 +140,9 @@ type UpdateJobJSONRequestBody UpdateJobJSONBody
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Renew the client certificate of a worker
+	// (POST /certificate)
+	RenewCertificate(ctx echo.Context) error
 	// Get error description
 	// (GET /errors/{id})
 	GetError(ctx echo.Context, id string) error
@@ -111,9 +155,12 @@ type ServerInterface interface {
 	// Update a running job
 	// (PATCH /jobs/{token})
 	UpdateJob(ctx echo.Context, token string) error
+	// Get the upload status of an artifact
+	// (GET /jobs/{token}/artifacts/{name})
+	GetJobArtifactStatus(ctx echo.Context, token string, name string) error
 	// Upload an artifact
 	// (PUT /jobs/{token}/artifacts/{name})
-	UploadJobArtifact(ctx echo.Context, token string, name string) error
+	UploadJobArtifact(ctx echo.Context, token string, name string, params UploadJobArtifactParams) error
 	// Get the openapi spec in json format
 	// (GET /openapi)
 	GetOpenapi(ctx echo.Context) error
@@ -127,6 +174,15 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// RenewCertificate converts echo context to params.
+func (w *ServerInterfaceWrapper) RenewCertificate(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RenewCertificate(ctx)
+	return err
+}
+
 // GetError converts echo context to params.
 func (w *ServerInterfaceWrapper) GetError(ctx echo.Context) error {
 	var err error
@@ -186,6 +242,30 @@ func (w *ServerInterfaceWrapper) UpdateJob(ctx echo.Context) error {
 	return err
 }
 
+// GetJobArtifactStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJobArtifactStatus(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "token", runtime.ParamLocationPath, ctx.Param("token"), &token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetJobArtifactStatus(ctx, token, name)
+	return err
+}
+
 // UploadJobArtifact converts echo context to params.
 func (w *ServerInterfaceWrapper) UploadJobArtifact(ctx echo.Context) error {
 	var err error
@@ -205,8 +285,24 @@ func (w *ServerInterfaceWrapper) UploadJobArtifact(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UploadJobArtifactParams
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// ------------- Optional query parameter "checksum" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "checksum", ctx.QueryParams(), &params.Checksum)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter checksum: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.UploadJobArtifact(ctx, token, name)
+	err = w.Handler.UploadJobArtifact(ctx, token, name, params)
 	return err
 }
 
@@ -256,10 +352,12 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/certificate", wrapper.RenewCertificate)
 	router.GET(baseURL+"/errors/:id", wrapper.GetError)
 	router.POST(baseURL+"/jobs", wrapper.RequestJob)
 	router.GET(baseURL+"/jobs/:token", wrapper.GetJob)
 	router.PATCH(baseURL+"/jobs/:token", wrapper.UpdateJob)
+	router.GET(baseURL+"/jobs/:token/artifacts/:name", wrapper.GetJobArtifactStatus)
 	router.PUT(baseURL+"/jobs/:token/artifacts/:name", wrapper.UploadJobArtifact)
 	router.GET(baseURL+"/openapi", wrapper.GetOpenapi)
 	router.GET(baseURL+"/status", wrapper.GetStatus)
@@ -269,26 +367,37 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xYX2/bNhD/KgS3hw2QLadpXwTsoemGIh26DMmKFciC4EydLSYSqZAnO4ah7z6QlP9J",
-	"ip0A8UPzJFk83p/f/Xh39JILXZRaoSLLkyW3IsMC/OsfxmjjXiDPLyY8uV7ynw1OeMJ/ijeb4mZHfDG+",
-	"Q0GXOEGDSiCvoyUvjS7RkESvUOgU3ZMWJfKEWzJSTXkd8QKthalfS9EKI0uSWvGEn4G4n4NJmbMHJMcy",
-	"l7Rgc0kZm2tzj8ay/6rR6FT8xmanpxHDhwpyywyC1YpHXVPOH3Dab2Xa60uztbvk1x4qaTDlyXUIZi3e",
-	"UrwJ6Wbtg/b48PqmjvhnpC96fIm21Mriq2IMSmCO27GNtc4RVDeClWi/j21bSdtU5h3tgfAJZO+lSg/j",
-	"6tHzolGw0PUu4pf4UKENGPq3rndgRNbrhvvgJSRhYZ8U4QkHY2DRcTDsj4KBQ869foLBTP3zcTDVg8b2",
-	"ndVqeAnzrw3paucdyQkIus21gHCaegJNFwoKKW5XSteQHNC+C1DE9xoJHw7l3a9uaeoLoZ+oVwRU2WNg",
-	"bb3mw743cv3ufStTINxHVYO2yukg7C2jza4+Bm6Z3IDyIiicMakmuluS/8mkZdIyUOzj3+dsos26EpNm",
-	"JsTIQKUsA5XmyO702A5dKZaUOzcvrs4qmafsk3PDomED9q9XwCM+Q2ODmZOmWCsoJU/46XA0HPGIl0CZ",
-	"xyxG151svJRp7X5Pkbq+fkbnCZPKkqt1TE8YZcj8VmZLFHIiMWXjBfNVZ13Cz9OwOXRAZ9VAgYTGelLt",
-	"Gjn/fUcvd8DxxHvKI66gcEF7/ZvskakwanqtcxsfoSg9Oien3a5V37i9IZM++HejUeinilD5uKEscxlO",
-	"SXzX9K+N+n2pDzHWPuPvv38/it4PR9FbR9yiqIykhU/LGYJBw5PrGweYrYoCzKJhQUj5duLc9thx059H",
-	"bXvo0xxYy8CReMg89dckYeNci3vLKkUyDyL+XMxA5jDOcdhh1KYxNGRAS2c6XbwaNt22GGBqkefkKAab",
-	"SuMN7uL4ySAQpu5Evxu9fzXjvUVr1/Jf2qdlDlt5iRiZBYMpSMV/NM634/Ms3jD9clV9XdQbhsdL0veo",
-	"tutkp9StSHmkKtMaeHtCufiT/5AVaKfMmEopqaYB/k7f6OkLPjF7W0NPLyiBwmy7m8V11z9SdekMMr3F",
-	"ZXQMe2+YNiFKBrvcaR/deDUM23jpqOPPcllRHwtyDekXPf7Y7ODP4aF/vISG0evR+Xlc1YKQBpYMQrEL",
-	"elvlU6R8c8RxiXbz7YobgTbrofnpYn/RiDwHp0adH5eZVMz57qb+AvxV48MxRtH2If+m8LFEQZg2g5wW",
-	"ojKOX90S7AbxvT47jDYXu957w5V00zgLUs09xrB5JkXGDFJllGUWzUyKlVDf7eFqtXK0Ctm6+b7F8tjA",
-	"20z7ZtZ/B/sKUrFfSqPTSrhPv7IgyyNemZwnPCMqbRLHUMqhY4fN5ISGQhfuSywLmOJg7K6laAbhOhvP",
-	"Tvw/Ai1mEExdkd6j3hJM8YVGgpaXiG0t3NT/BwAA//+IToO0xRUAAA==",
+	"H4sIAAAAAAAC/+RZb2/bvBH/KgQ3YBug2G6cNImBvWi7B102dHmQtliBJigo8mQxkUiVpOIahr/7cKRk",
+	"yxYdJ0AMrH1e2ZbI+/u73x3pBeW6rLQC5SydLKjlOZTMf31jnMwYdx8dc7W9BltpZQHfsKK4yujk64L+",
+	"2UBGJ/RPw7WUYSNieJXeAXfXkIEBxYEukwWtjK7AOAleg84yCw6/ZdqUzNEJlcq9PqEJdfMKwk+YgqHL",
+	"ZUINfK+lAUEnX9udt6uF2iujy9tlQn8zRpuXtJNr4f1udFlnpJrSZUJLsJZN/TsBlhtZOakVndC3jN/P",
+	"mBEE9TEnU1lINycz6XIy0+YejCU39Wg05n8nD+NxQuB7zQpLDDCrFU36qtAehtK/SRG1pdnaf7UVOu/M",
+	"avmW4LVL8dC+B/cvnR4CC5wpDgV0fUu1LoCpvgft0riN27om26pyb2gkhDsiey+V2B9XHz2/NAka+tYl",
+	"9BoUzN6hKZnkzME1fK/Bur6N3Jo+qn7/7QMBhQkUhK+FECunSqopMY20ZB8ErHmidQfI81r8HgcLCcp1",
+	"/dzvV2dtHBtNvD2Gd0SeGZ5HYYAP/ArpoLQ7l9AJZcawOf4Opd5U7Karl/8gOiMuh4YPEiIdMcBBPoD1",
+	"z+90akkllQJBnMbXUhEmhEQJN8ppv0q7HIxfe6P2Bii4kAQf4whYx+flc8/M1H/+OJrqo0b3ndVqcM1m",
+	"HxreWaJ1ofF8KzRnIVyRWIu5YqXk31qhq6zskb6do0eVhAeLJ4SVdiTFXIjj8XC91XrJ+21v1sXN+1wJ",
+	"5uCxajFg68LtDfuW0mZXDIEdleugPCsUqEyqTPdr7lMuLZGWMEXe/H5JMm1Wzdjplj0JU4LkTIkilOAA",
+	"q0q6As28+vi2loUg79AMC4Yckf96ATShD2BsUPOq6deKVZJO6HgwGoxoQivmch+z4RYHVtq6vrGX1tZg",
+	"CSMKZhEy9NYjAVR1WkhO7mHeEkpn1Y3a6g0D8ikHIgUoJ91qh1fRkS0t4bqSIEhmdIlLblTEBNzaRm3G",
+	"LGG1y1EwvhR+2EmI1asYc6aIVsX8RhlsNLhdGqJnakNoCpk2gHQHPyppwA5uNsaUS0EnvU5FA77Aurda",
+	"zMPMphwoH1dWVYUMZTi8a2akgJ592NrVrpfLgOiAUJ/U49GrA6oNigK2N2HyzgBGGyF38uXLi5kQZujg",
+	"5+lB5C4TauuyZGbe5jOAtw8znRHWgMgzyRBQiB0upFiivilEyuc9YCUTqazDcbFFut9KbAVcZojvdE6k",
+	"6MHrPbhgKFatYSU4MNaT8q4mDs1yiY+x0mlCFSv9GUbQLvs5U0PSiRb8YGXl2eXVONLAb3s4Gx0kyT8f",
+	"eIDXRrq5T8tbYAYMnXy9Xd52cYUoCCnvJs6DCLl9N/s2lY78e6dTZE1pyQokJC00v7ekVk4WYYnvKw9M",
+	"FiwtYBAhrHawOhhVbU+2Byep3qj4OD0dj05eTHm06W9q/o/2aZmxTl4S4sycsCmT6qcjzG3/6DaDNtML",
+	"er1G+HDh9D2oLk/2qK4F5YFYZuvOIOLK1b9/8vaFNGNq5QctH/5e34j0BZ+YR1tDpBdUzPG8n8XV1Hwg",
+	"dukdBKLkMjqEvl8YNsFLwjaxs126w/YwaYcLhM7umecaXG1UuD1QdZmCwfEknTuw7ZzSyvI/LJgHMIQV",
+	"BpjwY7m/fhBJmJwcGFNXOMrXVaGZ8AN8CgRPb+X6bICN0V/Hxgb1UPybV8n0KdXhP55THMnLFdnBYL3j",
+	"Sv1XpUTEWAOdcM3gR3m1AqHnyToC5M9+UwDyLNfFGrcJ0YYwwvNa3aM06cgsB+VXBhTiIBbA+Gn9rKyt",
+	"IyVyZ7Q4Hi8GfBsU4oxXVaBEuJJzOZQDctmcunPg97YuG/1JODU3Uh/A4HkjaMIgFuA6xajxhIKvBHMM",
+	"BcyMdA4U3kXcKCEtZ0ZYdFZ6l4UGq/7SeBQruxDATuX9f9dcK+d7DWa+FhSSR7s7e38UlVLJsi7pZBT7",
+	"02gbWB//+eb49PU6VzqLZ4QmUYPafTR+gLM5Oz59PUkvTsTxWXpxMT4RYxids9NjOD0WZ4KdCZZmjJ+c",
+	"n0AG4zN2Oj4fAVyMzs+zM8bhGDIu4GLXKfApbV1zB+7IOgOs3KzhbZG7+vcv12M9/2ywDnbY1f3c7rn4",
+	"qlnylDg14vzNAt7To+2kASs6eohT+3bP+KzgRwUcG3Y482rOa4MFGKfmR23GGK3vkKPjxkeJuG+pPVyZ",
+	"GjLLJc+JaUYR5D/J20Wx8WA1Exys6/4Bum0T3vDUt5zYddUHJhX5a2W0qDk++lvT9GhCa1PQCc2dq+xk",
+	"OGSVHCA6bC4zN+C6xCdDWbIpHKV4Aw7mKFzIDR9e0QjNOjbFefYR8daxKTxTSZDynGWdF7fL/w0ABBVP",
+	"K+wgAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
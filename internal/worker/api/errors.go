@@ -23,6 +23,12 @@ const (
 	ErrorErrorNotFound        ServiceErrorCode = 14
 	ErrorInvalidJobType       ServiceErrorCode = 15
 	ErrorTenantNotFound       ServiceErrorCode = 16
+	ErrorArtifactOffset       ServiceErrorCode = 17
+	ErrorArtifactUploading    ServiceErrorCode = 18
+	ErrorArtifactChecksum     ServiceErrorCode = 19
+	ErrorCertificateRenewal   ServiceErrorCode = 20
+	ErrorNoClientCertificate  ServiceErrorCode = 21
+	ErrorInvalidCSR           ServiceErrorCode = 22
 	// ErrorTokenNotFound ServiceErrorCode = 6
 
 	// internal errors
@@ -34,6 +40,7 @@ const (
 	ErrorRetrievingJobStatus      ServiceErrorCode = 1005
 	ErrorRequestingJob            ServiceErrorCode = 1006
 	ErrorFailedLoadingOpenAPISpec ServiceErrorCode = 1007
+	ErrorIssuingCertificate       ServiceErrorCode = 1008
 
 	// Errors contained within this file
 	ErrorUnspecified          ServiceErrorCode = 10000
@@ -76,6 +83,13 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorErrorNotFound, http.StatusNotFound, "Error with given id not found"},
 		serviceError{ErrorInvalidJobType, http.StatusBadRequest, "Requested job type cannot be dequeued"},
 		serviceError{ErrorTenantNotFound, http.StatusBadRequest, "Tenant not found in JWT claims"},
+		serviceError{ErrorArtifactOffset, http.StatusConflict, "Offset doesn't match the size of the uploaded artifact"},
+		serviceError{ErrorArtifactUploading, http.StatusConflict, "Artifact is being uploaded already"},
+		serviceError{ErrorArtifactChecksum, http.StatusConflict, "Checksum of the uploaded artifact doesn't match"},
+		serviceError{ErrorCertificateRenewal, http.StatusNotFound, "Renewal of client certificates isn't enabled"},
+		serviceError{ErrorNoClientCertificate, http.StatusUnauthorized, "Request isn't authenticated by a client certificate"},
+		serviceError{ErrorInvalidCSR, http.StatusBadRequest, "Invalid certificate signing request"},
+		serviceError{ErrorIssuingCertificate, http.StatusInternalServerError, "Error issuing certificate"},
 
 		serviceError{ErrorUnspecified, http.StatusInternalServerError, "Unspecified internal error "},
 		serviceError{ErrorNotHTTPError, http.StatusInternalServerError, "Error is not an instance of HTTPError"},
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+// The artifact store keeps one object per distinct artifact content, named
+// by its checksum. Artifacts of finished jobs are hard links to these
+// objects, so identical artifacts of different jobs share their data on
+// disk. An object is removed once no job links to it anymore.
+const artifactStoreDir = "store"
+
+// artifactObjectPath returns the path of the store object for the checksum,
+// which is in the form of "sha256:<hex digest>".
+func (s *Server) artifactObjectPath(checksum string) string {
+	return path.Join(s.config.ArtifactsDir, artifactStoreDir, strings.Replace(checksum, ":", "/", 1))
+}
+
+// deduplicateArtifacts replaces the artifacts of the job with hard links to
+// objects in the artifact store, adding the artifacts which aren't in the
+// store yet.
+func (s *Server) deduplicateArtifacts(jobId uuid.UUID) error {
+	s.artifactStoreMu.Lock()
+	defer s.artifactStoreMu.Unlock()
+
+	dir := path.Join(s.config.ArtifactsDir, jobId.String())
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		p := path.Join(dir, entry.Name())
+		checksum, err := common.FileSHA256(p)
+		if err != nil {
+			return fmt.Errorf("error computing checksum of artifact %s: %v", p, err)
+		}
+
+		object := s.artifactObjectPath(checksum)
+		err = os.MkdirAll(path.Dir(object), 0700)
+		if err != nil {
+			return err
+		}
+
+		// the artifact becomes the object if there is none yet
+		err = os.Link(p, object)
+		if err == nil {
+			continue
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("error adding artifact %s to the store: %v", p, err)
+		}
+
+		artifactInfo, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		objectInfo, err := os.Stat(object)
+		if err != nil {
+			return err
+		}
+		if os.SameFile(artifactInfo, objectInfo) {
+			continue
+		}
+
+		// replace the artifact atomically, so it can be read meanwhile
+		tmp := p + ".dedup"
+		err = os.Link(object, tmp)
+		if err != nil {
+			return fmt.Errorf("error linking artifact %s to the store: %v", p, err)
+		}
+		err = os.Rename(tmp, p)
+		if err != nil {
+			_ = os.Remove(tmp)
+			return fmt.Errorf("error linking artifact %s to the store: %v", p, err)
+		}
+	}
+
+	return nil
+}
+
+// pruneArtifactStore removes the objects no job links to anymore.
+func (s *Server) pruneArtifactStore() error {
+	s.artifactStoreMu.Lock()
+	defer s.artifactStoreMu.Unlock()
+
+	root := path.Join(s.config.ArtifactsDir, artifactStoreDir)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return fmt.Errorf("cannot get the link count of %s", p)
+		}
+		if stat.Nlink > 1 {
+			return nil
+		}
+
+		logrus.Debugf("Removing unused artifact %s", p)
+		return os.Remove(p)
+	})
+	return err
+}
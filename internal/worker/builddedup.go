@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Builds of the cloud API with the same manifest and compose request
+// produce the same images, so successful osbuild jobs are indexed by a key
+// derived from both and the channel of the job, builds are only reused
+// within the same tenant. A job identical to one which finished recently
+// isn't built again, it's finished right away with the result of the
+// earlier job.
+//
+// The index is kept in memory, builds finished before composer was started
+// aren't reused.
+type finishedBuild struct {
+	jobId    uuid.UUID
+	finished time.Time
+}
+
+// DeduplicatesBuilds returns true if identical builds are deduplicated.
+// Manifests of identical compose requests have to be identical for that,
+// which requires them to be generated with the same seed, that's up to the
+// requests.
+func (s *Server) DeduplicatesBuilds() bool {
+	return s.config.BuildDeduplicationWindow > 0
+}
+
+// buildKey returns the deduplication key of an osbuild job of the channel
+// with the given arguments and the results of its dependencies. Only jobs of
+// the cloud API which aren't koji builds have a key.
+func buildKey(args json.RawMessage, dynamicArgs []json.RawMessage, channel string) (string, bool) {
+	var job OSBuildJob
+	if err := json.Unmarshal(args, &job); err != nil {
+		return "", false
+	}
+	if len(job.ComposeRequest) == 0 || job.ManifestDynArgsIdx != nil || len(dynamicArgs) != 1 {
+		return "", false
+	}
+
+	var manifestJR ManifestJobByIDResult
+	if err := json.Unmarshal(dynamicArgs[0], &manifestJR); err != nil {
+		return "", false
+	}
+	if manifestJR.JobError != nil || len(manifestJR.Manifest) == 0 {
+		return "", false
+	}
+
+	// the targets are generated from the upload options of the request,
+	// with random names, so the request identifies them
+	h := sha256.New()
+	h.Write([]byte(channel))
+	h.Write([]byte{0})
+	h.Write(manifestJR.Manifest)
+	h.Write([]byte{0})
+	h.Write(job.ComposeRequest)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// jobBuildKey returns the deduplication key of the osbuild job.
+func (s *Server) jobBuildKey(id uuid.UUID) (string, bool) {
+	_, args, deps, channel, err := s.jobs.Job(id)
+	if err != nil {
+		return "", false
+	}
+	var dynamicArgs []json.RawMessage
+	for _, depID := range deps {
+		_, _, result, _, _, _, _, _, _, err := s.jobs.JobStatus(depID)
+		if err != nil {
+			return "", false
+		}
+		dynamicArgs = append(dynamicArgs, result)
+	}
+	return buildKey(args, dynamicArgs, channel)
+}
+
+// buildSucceeded returns true if the build and all its uploads succeeded.
+func buildSucceeded(result *OSBuildJobResult) bool {
+	return result.Success && result.JobError == nil && len(result.TargetErrors()) == 0
+}
+
+// recordBuild adds the finished osbuild job to the index of builds, if it
+// succeeded, and drops the builds which are too old to be reused.
+func (s *Server) recordBuild(jobId uuid.UUID, result *OSBuildJobResult, finished time.Time) {
+	// duplicates aren't recorded, so a build is only reused for the
+	// window after it was actually built
+	if !buildSucceeded(result) || result.DeduplicatedFrom != nil {
+		return
+	}
+	key, ok := s.jobBuildKey(jobId)
+	if !ok {
+		return
+	}
+
+	s.buildsMu.Lock()
+	defer s.buildsMu.Unlock()
+	for k, build := range s.builds {
+		if time.Since(build.finished) > s.config.BuildDeduplicationWindow {
+			delete(s.builds, k)
+		}
+	}
+	s.builds[key] = finishedBuild{jobId: jobId, finished: finished}
+}
+
+// findBuild returns the job of a recent successful build with the key.
+func (s *Server) findBuild(key string) (uuid.UUID, bool) {
+	s.buildsMu.Lock()
+	defer s.buildsMu.Unlock()
+	build, ok := s.builds[key]
+	if !ok || time.Since(build.finished) > s.config.BuildDeduplicationWindow {
+		return uuid.Nil, false
+	}
+	return build.jobId, true
+}
+
+// finishDuplicateBuild finishes the dequeued osbuild job with the result
+// and artifacts of an identical recent build. It returns false if there is
+// no such build and the job has to be built by a worker.
+func (s *Server) finishDuplicateBuild(jobId, token uuid.UUID, channel string, args json.RawMessage, dynamicArgs []json.RawMessage) bool {
+	key, ok := buildKey(args, dynamicArgs, channel)
+	if !ok {
+		return false
+	}
+	original, ok := s.findBuild(key)
+	if !ok || original == jobId {
+		return false
+	}
+
+	var result OSBuildJobResult
+	jobInfo, err := s.OSBuildJobInfo(original, &result)
+	if err != nil || jobInfo.JobStatus.Finished.IsZero() || !buildSucceeded(&result) {
+		return false
+	}
+	result.DeduplicatedFrom = &original
+
+	rawResult, err := json.Marshal(result)
+	if err != nil {
+		logrus.Errorf("Error marshaling the result of job %s: %v", original, err)
+		return false
+	}
+	err = s.FinishJob(token, rawResult)
+	if err != nil {
+		logrus.Errorf("Error finishing job %s as a duplicate of job %s, building it instead: %v", jobId, original, err)
+		return false
+	}
+	logrus.Infof("Job %s is identical to job %s, reusing its result", jobId, original)
+
+	if s.config.ArtifactsDir != "" {
+		err = s.linkArtifacts(original, jobId)
+		if err != nil {
+			logrus.Errorf("Error linking the artifacts of job %s to job %s: %v", original, jobId, err)
+		}
+	}
+	return true
+}
+
+// linkArtifacts hard links the artifacts of a job to another job.
+func (s *Server) linkArtifacts(from, to uuid.UUID) error {
+	s.artifactStoreMu.Lock()
+	defer s.artifactStoreMu.Unlock()
+
+	fromDir := path.Join(s.config.ArtifactsDir, from.String())
+	toDir := path.Join(s.config.ArtifactsDir, to.String())
+	entries, err := os.ReadDir(fromDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	err = os.MkdirAll(toDir, 0700)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		err = os.Link(path.Join(fromDir, entry.Name()), path.Join(toDir, entry.Name()))
+		if err != nil && !os.IsExist(err) {
+			return fmt.Errorf("error linking artifact %s: %v", entry.Name(), err)
+		}
+	}
+	return nil
+}
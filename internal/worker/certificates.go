@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Certificates are valid a bit before they are issued, so workers with a
+// clock running late can use them right away.
+const certificateBackdate = 5 * time.Minute
+
+// CertificateAuthority issues the renewed client certificates of workers.
+// It has to be the authority the worker API verifies client certificates
+// with, otherwise the renewed certificates aren't accepted.
+type CertificateAuthority struct {
+	Certificate *x509.Certificate
+	Key         crypto.Signer
+	// Validity of the issued certificates
+	Validity time.Duration
+}
+
+// LoadCertificateAuthority loads the certificate and private key of the
+// authority from PEM encoded files.
+func LoadCertificateAuthority(certFile, keyFile string, validity time.Duration) (*CertificateAuthority, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate %s is not a certificate authority", certFile)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("private key %s cannot sign certificates", keyFile)
+	}
+	return &CertificateAuthority{
+		Certificate: cert,
+		Key:         key,
+		Validity:    validity,
+	}, nil
+}
+
+// RenewCertificate issues a certificate for the public key of the signing
+// request with the identity of the current certificate. The subject and
+// names of the request are ignored, so a worker cannot gain another
+// identity by renewing its certificate.
+func (ca *CertificateAuthority) RenewCertificate(current *x509.Certificate, csr *x509.CertificateRequest) (*x509.Certificate, error) {
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid signature of the certificate signing request: %v", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:   serial,
+		Subject:        current.Subject,
+		DNSNames:       current.DNSNames,
+		EmailAddresses: current.EmailAddresses,
+		IPAddresses:    current.IPAddresses,
+		URIs:           current.URIs,
+		NotBefore:      now.Add(-certificateBackdate),
+		NotAfter:       now.Add(ca.Validity),
+		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, csr.PublicKey, ca.Key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
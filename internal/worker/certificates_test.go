@@ -0,0 +1,161 @@
+package worker_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func newTestCA(t *testing.T) *worker.CertificateAuthority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "osbuild CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &worker.CertificateAuthority{
+		Certificate: cert,
+		Key:         key,
+		Validity:    24 * time.Hour,
+	}
+}
+
+// newTestCertificate issues a certificate for the given DNS names by the CA.
+func newTestCertificate(t *testing.T, ca *worker.CertificateAuthority, usage x509.ExtKeyUsage, names ...string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: names[0]},
+		DNSNames:     names,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, key.Public(), ca.Key)
+	require.NoError(t, err)
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func newTestCSR(t *testing.T, name string) ([]byte, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: name},
+		DNSNames: []string{name},
+	}, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), key
+}
+
+func TestRenewCertificate(t *testing.T) {
+	ca := newTestCA(t)
+
+	q, err := fsjobqueue.New(t.TempDir())
+	require.NoError(t, err)
+	server := worker.NewServer(nil, q, worker.Config{
+		BasePath:             "/api/worker/v1",
+		CertificateAuthority: ca,
+	})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+
+	httpServer := httptest.NewUnstartedServer(server.Handler())
+	httpServer.TLS = &tls.Config{
+		Certificates: []tls.Certificate{newTestCertificate(t, ca, x509.ExtKeyUsageServerAuth, "localhost")},
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		ClientCAs:    roots,
+		MinVersion:   tls.VersionTLS12,
+	}
+	httpServer.StartTLS()
+	defer httpServer.Close()
+
+	newClient := func(certs ...tls.Certificate) *worker.Client {
+		client, err := worker.NewClient(worker.ClientConfig{
+			BaseURL: httpServer.URL,
+			TlsConfig: &tls.Config{
+				Certificates: certs,
+				RootCAs:      roots,
+				MinVersion:   tls.VersionTLS12,
+			},
+			BasePath: "/api/worker/v1",
+		})
+		require.NoError(t, err)
+		return client
+	}
+
+	csr, key := newTestCSR(t, "admin.example.com")
+
+	// a client certificate is required
+	_, err = newClient().RenewCertificate(csr)
+	require.Error(t, err)
+
+	// the identity of the new certificate is the one of the current one,
+	// not the one requested
+	client := newClient(newTestCertificate(t, ca, x509.ExtKeyUsageClientAuth, "worker.example.com"))
+	certPEM, err := client.RenewCertificate(csr)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	require.Equal(t, "worker.example.com", cert.Subject.CommonName)
+	require.Equal(t, []string{"worker.example.com"}, cert.DNSNames)
+	require.True(t, key.PublicKey.Equal(cert.PublicKey))
+	require.WithinDuration(t, time.Now().Add(ca.Validity), cert.NotAfter, time.Minute)
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(t, err)
+
+	// the signing request has to be valid
+	_, err = client.RenewCertificate([]byte("not a csr"))
+	require.Error(t, err)
+}
+
+func TestRenewCertificateDisabled(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), time.Duration(0), "/api/worker/v1", false)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	client, err := worker.NewClient(worker.ClientConfig{
+		BaseURL:  httpServer.URL,
+		BasePath: "/api/worker/v1",
+	})
+	require.NoError(t, err)
+
+	csr, _ := newTestCSR(t, "worker.example.com")
+	_, err = client.RenewCertificate(csr)
+	require.ErrorContains(t, err, "404")
+}
@@ -12,8 +12,10 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 
 	"github.com/osbuild/osbuild-composer/internal/worker/api"
 )
@@ -26,10 +28,22 @@ type Client struct {
 	accessToken  string
 	clientId     string
 	clientSecret string
+	workerID     string
+
+	artifactChunkSize int64
 
 	tokenMu sync.RWMutex
 }
 
+// DefaultArtifactChunkSize is the size of the chunks artifacts are uploaded
+// in, unless configured otherwise.
+const DefaultArtifactChunkSize int64 = 64 * 1024 * 1024
+
+// An interrupted upload of an artifact chunk is retried this many times,
+// waiting a bit longer before every attempt.
+const artifactUploadRetries = 5
+const artifactUploadRetryDelay = time.Second
+
 type ClientConfig struct {
 	BaseURL      string
 	TlsConfig    *tls.Config
@@ -39,6 +53,13 @@ type ClientConfig struct {
 	ClientSecret string
 	BasePath     string
 	ProxyURL     string
+	// Artifacts which can be seeked are uploaded in chunks of this size
+	// and an interrupted upload is resumed from the last received byte.
+	// DefaultArtifactChunkSize is used when it's not set.
+	ArtifactChunkSize int64
+	// ID of the worker, it receives the jobs pinned to it in addition to
+	// the other jobs
+	WorkerID string
 }
 
 type Job interface {
@@ -49,7 +70,9 @@ type Job interface {
 	NDynamicArgs() int
 	Update(result interface{}) error
 	Canceled() (bool, error)
-	UploadArtifact(name string, reader io.Reader) error
+	// UploadArtifact uploads the artifact to the server. If the checksum
+	// is set, the server verifies it once the whole artifact is received.
+	UploadArtifact(name string, reader io.Reader, checksum string) error
 }
 
 var ErrClientRequestJobTimeout = errors.New("Dequeue timed out, retry")
@@ -109,15 +132,24 @@ func NewClient(conf ClientConfig) (*Client, error) {
 	requester.Transport = transport
 
 	return &Client{
-		server:       server,
-		requester:    requester,
-		offlineToken: conf.OfflineToken,
-		oAuthURL:     conf.OAuthURL,
-		clientId:     conf.ClientId,
-		clientSecret: conf.ClientSecret,
+		server:            server,
+		requester:         requester,
+		offlineToken:      conf.OfflineToken,
+		oAuthURL:          conf.OAuthURL,
+		clientId:          conf.ClientId,
+		clientSecret:      conf.ClientSecret,
+		workerID:          conf.WorkerID,
+		artifactChunkSize: artifactChunkSize(conf),
 	}, nil
 }
 
+func artifactChunkSize(conf ClientConfig) int64 {
+	if conf.ArtifactChunkSize <= 0 {
+		return DefaultArtifactChunkSize
+	}
+	return conf.ArtifactChunkSize
+}
+
 func NewClientUnix(conf ClientConfig) *Client {
 	server, err := url.Parse("http://localhost/")
 	if err != nil {
@@ -140,8 +172,10 @@ func NewClientUnix(conf ClientConfig) *Client {
 	}
 
 	return &Client{
-		server:    server,
-		requester: requester,
+		server:            server,
+		requester:         requester,
+		workerID:          conf.WorkerID,
+		artifactChunkSize: artifactChunkSize(conf),
 	}
 }
 
@@ -220,11 +254,16 @@ func (c *Client) RequestJob(types []string, arch string) (Job, error) {
 		panic(err)
 	}
 
-	var buf bytes.Buffer
-	err = json.NewEncoder(&buf).Encode(api.RequestJobJSONRequestBody{
+	body := api.RequestJobJSONRequestBody{
 		Types: types,
 		Arch:  arch,
-	})
+	}
+	if c.workerID != "" {
+		body.WorkerId = &c.workerID
+	}
+
+	var buf bytes.Buffer
+	err = json.NewEncoder(&buf).Encode(body)
 	if err != nil {
 		panic(err)
 	}
@@ -283,6 +322,42 @@ func (c *Client) RequestJob(types []string, arch string) (Job, error) {
 	}, nil
 }
 
+// RenewCertificate sends a PEM encoded certificate signing request to the
+// server and returns the PEM encoded certificate it issued. The request has
+// to be authenticated by the current client certificate.
+func (c *Client) RenewCertificate(csr []byte) ([]byte, error) {
+	url, err := c.server.Parse("certificate")
+	if err != nil {
+		// This only happens when "certificate" cannot be parsed.
+		panic(err)
+	}
+
+	var buf bytes.Buffer
+	err = json.NewEncoder(&buf).Encode(api.RenewCertificateJSONRequestBody{
+		Csr: string(csr),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	response, err := c.NewRequest("POST", url.String(), map[string]string{"Content-Type": "application/json"}, &buf)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated {
+		return nil, errorFromResponse(response, "error renewing certificate")
+	}
+
+	var cr api.RenewCertificateResponse
+	err = json.NewDecoder(response.Body).Decode(&cr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %v", err)
+	}
+	return []byte(cr.Certificate), nil
+}
+
 func (j *job) Id() uuid.UUID {
 	return j.id
 }
@@ -353,7 +428,7 @@ func (j *job) Canceled() (bool, error) {
 	return jr.Canceled, nil
 }
 
-func (j *job) UploadArtifact(name string, reader io.Reader) error {
+func (j *job) UploadArtifact(name string, reader io.Reader, checksum string) error {
 	if j.artifactLocation == "" {
 		return fmt.Errorf("server does not accept artifacts for this job")
 	}
@@ -368,16 +443,119 @@ func (j *job) UploadArtifact(name string, reader io.Reader) error {
 		panic(err)
 	}
 
+	// only artifacts which can be read again can be resumed
+	seeker, ok := reader.(io.ReadSeeker)
+	if !ok {
+		_, err = j.uploadArtifactChunk(loc, reader, nil, checksum)
+		return err
+	}
+
+	size, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("error getting the size of the artifact: %v", err)
+	}
+
+	var offset int64
+	retries := 0
+	for {
+		_, err = seeker.Seek(offset, io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("error seeking in the artifact: %v", err)
+		}
+
+		chunkSize := j.client.artifactChunkSize
+		if size-offset < chunkSize {
+			chunkSize = size - offset
+		}
+
+		// the checksum is verified with the last chunk
+		chunkChecksum := ""
+		if offset+chunkSize >= size {
+			chunkChecksum = checksum
+		}
+
+		retryable, err := j.uploadArtifactChunk(loc, io.LimitReader(seeker, chunkSize), &offset, chunkChecksum)
+		if err == nil {
+			retries = 0
+			offset += chunkSize
+			if offset >= size {
+				return nil
+			}
+			continue
+		}
+
+		retries++
+		if !retryable || retries > artifactUploadRetries {
+			return err
+		}
+		logrus.Warnf("Uploading artifact %s failed at offset %d, retrying (%d/%d): %v", name, offset, retries, artifactUploadRetries, err)
+		time.Sleep(artifactUploadRetryDelay * time.Duration(retries))
+
+		// continue from whatever the server received
+		serverOffset, err := j.artifactOffset(loc)
+		if err != nil {
+			logrus.Warnf("Getting the upload status of artifact %s failed: %v", name, err)
+			continue
+		}
+		if serverOffset > size {
+			return fmt.Errorf("server received more of the artifact %s than its size (%d > %d)", name, serverOffset, size)
+		}
+		offset = serverOffset
+	}
+}
+
+// uploadArtifactChunk uploads the whole artifact if offset is nil, or the
+// chunk which starts at the given offset otherwise. The returned bool is true
+// if the upload failed in a way it makes sense to retry it.
+func (j *job) uploadArtifactChunk(loc *url.URL, reader io.Reader, offset *int64, checksum string) (bool, error) {
+	query := url.Values{}
+	if offset != nil {
+		query.Set("offset", fmt.Sprintf("%d", *offset))
+	}
+	if checksum != "" {
+		query.Set("checksum", checksum)
+	}
+	if len(query) > 0 {
+		chunkLoc := *loc
+		chunkLoc.RawQuery = query.Encode()
+		loc = &chunkLoc
+	}
+
 	response, err := j.client.NewRequest("PUT", loc.String(), map[string]string{"Content-Type": "application/octet-stream"}, reader)
 	if err != nil {
-		return fmt.Errorf("error uploading artifact: %v", err)
+		return true, fmt.Errorf("error uploading artifact: %v", err)
 	}
+	defer response.Body.Close()
 
 	if response.StatusCode != 200 {
-		return errorFromResponse(response, "error uploading artifact")
+		// conflicts mean the offset has to be negotiated again
+		retryable := response.StatusCode == http.StatusConflict || response.StatusCode >= 500
+		return retryable, errorFromResponse(response, "error uploading artifact")
 	}
 
-	return nil
+	return false, nil
+}
+
+// artifactOffset returns the number of bytes of the artifact the server
+// already received.
+func (j *job) artifactOffset(loc *url.URL) (int64, error) {
+	response, err := j.client.NewRequest("GET", loc.String(), map[string]string{}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error getting artifact status: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, errorFromResponse(response, "error getting artifact status")
+	}
+
+	var status api.ArtifactStatusResponse
+	err = json.NewDecoder(response.Body).Decode(&status)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing artifact status: %v", err)
+	}
+
+	return status.Offset, nil
 }
 
 // Parses an api.Error from a response and returns it as a golang error. Other
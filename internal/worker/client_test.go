@@ -1,7 +1,11 @@
 package worker_test
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -95,7 +99,7 @@ func TestOAuth(t *testing.T) {
 	job, err := client.RequestJob([]string{worker.JobTypeOSBuild}, "arch")
 	require.NoError(t, err)
 	r := strings.NewReader("artifact contents")
-	require.NoError(t, job.UploadArtifact("some-artifact", r))
+	require.NoError(t, job.UploadArtifact("some-artifact", r, ""))
 	c, err := job.Canceled()
 	require.False(t, c)
 	require.NoError(t, err)
@@ -123,7 +127,7 @@ func TestProxy(t *testing.T) {
 	job, err := client.RequestJob([]string{worker.JobTypeOSBuild}, "arch")
 	require.NoError(t, err)
 	r := strings.NewReader("artifact contents")
-	require.NoError(t, job.UploadArtifact("some-artifact", r))
+	require.NoError(t, job.UploadArtifact("some-artifact", r, ""))
 	c, err := job.Canceled()
 	require.False(t, c)
 	require.NoError(t, err)
@@ -136,3 +140,62 @@ func TestProxy(t *testing.T) {
 	// - cancel
 	require.Equal(t, 5, proxy.calls)
 }
+
+func TestUploadArtifactResume(t *testing.T) {
+	tempdir := t.TempDir()
+
+	q, err := fsjobqueue.New(tempdir)
+	require.NoError(t, err)
+	workerServer := worker.NewServer(nil, q, worker.Config{
+		ArtifactsDir: tempdir,
+		BasePath:     "/api/image-builder-worker/v1",
+	})
+	jobID, err := workerServer.EnqueueOSBuild("arch", &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+
+	handler := workerServer.Handler()
+
+	// interrupt the upload of the second chunk halfway through
+	interrupted := false
+	chunks := 0
+	flakySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		chunks++
+		if !interrupted && r.URL.Query().Get("offset") != "0" {
+			interrupted = true
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			r.Body = io.NopCloser(bytes.NewReader(body[:len(body)/2]))
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(flakySrv.Close)
+
+	client, err := worker.NewClient(worker.ClientConfig{
+		BaseURL:           flakySrv.URL,
+		BasePath:          "/api/image-builder-worker/v1",
+		ArtifactChunkSize: 8,
+	})
+	require.NoError(t, err)
+	job, err := client.RequestJob([]string{worker.JobTypeOSBuild}, "arch")
+	require.NoError(t, err)
+
+	content := "this is my resumable artifact"
+	require.NoError(t, job.UploadArtifact("some-artifact", strings.NewReader(content), fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(content)))))
+	require.True(t, interrupted)
+	// 4 chunks, the second one was resumed after the first half of it
+	require.Equal(t, 5, chunks)
+
+	require.NoError(t, job.Update(&worker.OSBuildJobResult{Success: true}))
+	reader, _, err := workerServer.JobArtifact(jobID, "some-artifact")
+	require.NoError(t, err)
+	uploaded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, content, string(uploaded))
+}
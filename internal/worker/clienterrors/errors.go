@@ -1,6 +1,7 @@
 package clienterrors
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -43,6 +44,26 @@ const (
 	ErrorRemoteFileResolution ClientErrorCode = 36
 	ErrorJobPanicked          ClientErrorCode = 37
 	ErrorGeneratingSignedURL  ClientErrorCode = 38
+	ErrorDeadlineExceeded     ClientErrorCode = 39
+
+	// Errors of upload targets which are more specific than
+	// ErrorUploadingImage or ErrorImportingImage
+	ErrorTargetAuthentication    ClientErrorCode = 40
+	ErrorTargetQuotaExceeded     ClientErrorCode = 41
+	ErrorTargetThrottled         ClientErrorCode = 42
+	ErrorTargetNetwork           ClientErrorCode = 43
+	ErrorTargetInvalidParameters ClientErrorCode = 44
+
+	ErrorResourceLimitExceeded ClientErrorCode = 45
+	ErrorVulnerabilityScan     ClientErrorCode = 46
+	ErrorSigningArtifact       ClientErrorCode = 47
+	ErrorMarketplacePublish    ClientErrorCode = 48
+	// the change set was rejected by AWS Marketplace
+	ErrorMarketplaceChangeSet ClientErrorCode = 49
+	ErrorExportingImage       ClientErrorCode = 50
+	ErrorMirroringOSTree      ClientErrorCode = 51
+	ErrorPackagingArtifact    ClientErrorCode = 53
+	ErrorMissingPackages      ClientErrorCode = 54
 )
 
 type ClientErrorCode int
@@ -98,12 +119,24 @@ func GetStatusCode(err *Error) StatusCode {
 		return JobStatusUserInputError
 	case ErrorEmptyPackageSpecs:
 		return JobStatusUserInputError
+	case ErrorMissingPackages:
+		return JobStatusUserInputError
 	case ErrorEmptyManifest:
 		return JobStatusUserInputError
 	case ErrorContainerResolution:
 		return JobStatusUserInputError
 	case ErrorOSTreeDependency:
 		return JobStatusUserInputError
+	case ErrorDeadlineExceeded:
+		return JobStatusUserInputError
+	case ErrorTargetAuthentication:
+		return JobStatusUserInputError
+	case ErrorTargetQuotaExceeded:
+		return JobStatusUserInputError
+	case ErrorTargetInvalidParameters:
+		return JobStatusUserInputError
+	case ErrorMarketplaceChangeSet:
+		return JobStatusUserInputError
 	default:
 		return JobStatusInternalError
 	}
@@ -129,6 +162,37 @@ func (e *Error) IsDependencyError() bool {
 	}
 }
 
+// IsRetryable returns true if the error is most likely transient, so the
+// failed job can be retried without any changes. A target error is
+// retryable if all the targets failed with a retryable error.
+func (e *Error) IsRetryable() bool {
+	switch e.ID {
+	case ErrorTargetThrottled:
+		return true
+	case ErrorTargetNetwork:
+		return true
+	case ErrorTargetError:
+		// the details are the errors of the targets, which are generic
+		// values if the error was unmarshaled
+		data, err := json.Marshal(e.Details)
+		if err != nil {
+			return false
+		}
+		var targetErrors []*Error
+		if err := json.Unmarshal(data, &targetErrors); err != nil || len(targetErrors) == 0 {
+			return false
+		}
+		for _, targetError := range targetErrors {
+			if targetError == nil || !targetError.IsRetryable() {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
 func WorkerClientError(code ClientErrorCode, reason string, details interface{}) *Error {
 	return &Error{
 		ID:      code,
@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+var ErrImageCatalogDisabled = errors.New("image catalog not enabled")
+
+// CatalogImage is an image which was successfully delivered to a target.
+type CatalogImage struct {
+	// the osbuild job which built the image, or the AWS EC2 share job of
+	// a cloned image
+	JobId     uuid.UUID            `json:"job_id"`
+	Arch      string               `json:"arch,omitempty"`
+	Delivered time.Time            `json:"delivered"`
+	Target    *target.TargetResult `json:"target"`
+}
+
+// The catalog of each tenant is a document of the JSON database, named
+// after the hex encoded channel, so any channel makes a valid file name.
+func imageCatalogName(channel string) string {
+	return "tenant-" + hex.EncodeToString([]byte(channel))
+}
+
+// catalogsTarget returns true if images delivered to the target belong to
+// the catalog. Artifacts stored by composer and koji builds are tracked
+// elsewhere.
+func catalogsTarget(name target.TargetName) bool {
+	return name != target.TargetNameWorkerServer && name != target.TargetNameKoji
+}
+
+// ImageCatalog returns the images delivered to the tenant of the channel,
+// the most recently delivered first.
+func (s *Server) ImageCatalog(channel string) ([]CatalogImage, error) {
+	if s.imageCatalog == nil {
+		return nil, ErrImageCatalogDisabled
+	}
+
+	s.imageCatalogMu.Lock()
+	defer s.imageCatalogMu.Unlock()
+
+	var images []CatalogImage
+	_, err := s.imageCatalog.Read(imageCatalogName(channel), &images)
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// addToImageCatalog adds the images to the catalog of the tenant of the
+// channel.
+func (s *Server) addToImageCatalog(channel string, images []CatalogImage) {
+	if s.imageCatalog == nil || len(images) == 0 {
+		return
+	}
+
+	s.imageCatalogMu.Lock()
+	defer s.imageCatalogMu.Unlock()
+
+	name := imageCatalogName(channel)
+	var catalog []CatalogImage
+	_, err := s.imageCatalog.Read(name, &catalog)
+	if err != nil {
+		logrus.Errorf("Error reading the image catalog, not adding the images of job %s: %v", images[0].JobId, err)
+		return
+	}
+	err = s.imageCatalog.Write(name, append(images, catalog...))
+	if err != nil {
+		logrus.Errorf("Error adding the images of job %s to the image catalog: %v", images[0].JobId, err)
+	}
+}
+
+// catalogOSBuildImages adds the images successfully uploaded by the osbuild
+// job to the catalog, even if the uploads to other targets failed. Reused
+// results of identical builds don't deliver new images, they aren't added
+// again.
+func (s *Server) catalogOSBuildImages(jobId uuid.UUID, channel string, result *OSBuildJobResult, finished time.Time) {
+	if result.DeduplicatedFrom != nil {
+		return
+	}
+
+	var images []CatalogImage
+	for _, tr := range result.TargetResults {
+		if tr.TargetError != nil || tr.Options == nil || !catalogsTarget(tr.Name) {
+			continue
+		}
+		images = append(images, CatalogImage{
+			JobId:     jobId,
+			Arch:      result.Arch,
+			Delivered: finished,
+			Target:    tr,
+		})
+	}
+	s.addToImageCatalog(channel, images)
+}
+
+// catalogSharedAMI adds the AMI cloned and shared by the job to the catalog.
+func (s *Server) catalogSharedAMI(jobId uuid.UUID, channel string, result *AWSEC2ShareJobResult, finished time.Time) {
+	if result.JobError != nil || result.Ami == "" {
+		return
+	}
+	s.addToImageCatalog(channel, []CatalogImage{
+		{
+			JobId:     jobId,
+			Delivered: finished,
+			Target: target.NewAWSTargetResult(&target.AWSTargetResultOptions{
+				Ami:    result.Ami,
+				Region: result.Region,
+			}, nil),
+		},
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"runtime/debug"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/osbuild/images/pkg/distro"
 	"github.com/osbuild/images/pkg/manifest"
 	"github.com/osbuild/images/pkg/osbuild"
@@ -28,6 +30,30 @@ type OSBuildJob struct {
 	// the value can be accessed job which depend on it.
 	// (string representation of distro.BootMode values)
 	ImageBootMode string `json:"image_boot_mode,omitempty"`
+	// Deadline of the compose the job belongs to. Targets which would be
+	// started after the deadline are skipped by the worker.
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// The request the compose was submitted with, without the credentials
+	// it held. It isn't used by the worker, but it allows composer to
+	// rebuild the compose later.
+	ComposeRequest json.RawMessage `json:"compose_request,omitempty"`
+	// Paths of the credentials removed from the compose request, they have
+	// to be set again to rebuild the compose.
+	RedactedFields []string `json:"redacted_fields,omitempty"`
+	// ID of the worker the job is pinned to, only that worker runs it.
+	PinnedWorkerID string `json:"pinned_worker_id,omitempty"`
+	// Upload a SHA256SUMS file listing all the artifacts of the job next to
+	// the artifacts uploaded to S3 targets.
+	ChecksumManifest bool `json:"checksum_manifest,omitempty"`
+	// Credentials of the registries the embedded containers are pulled
+	// from during the build, by registry.
+	ContainerAuths map[string]ContainerAuth `json:"container_auths,omitempty"`
+}
+
+// DeadlineExceeded returns true if the job has a deadline, which already
+// passed.
+func (j OSBuildJob) DeadlineExceeded() bool {
+	return j.Deadline != nil && time.Now().After(*j.Deadline)
 }
 
 // OsbuildExports returns a slice of osbuild pipeline names, which should be
@@ -36,11 +62,17 @@ type OSBuildJob struct {
 func (j OSBuildJob) OsbuildExports() []string {
 	exports := []string{}
 	seenExports := map[string]bool{}
-	for _, target := range j.Targets {
-		exists := seenExports[target.OsbuildArtifact.ExportName]
-		if !exists {
-			seenExports[target.OsbuildArtifact.ExportName] = true
-			exports = append(exports, target.OsbuildArtifact.ExportName)
+	for _, t := range j.Targets {
+		names := []string{t.OsbuildArtifact.ExportName}
+		// bare metal targets publish the kernel and initrd of the tree too
+		if options, ok := t.Options.(*target.BareMetalTargetOptions); ok && options.NetbootExport != "" {
+			names = append(names, options.NetbootExport)
+		}
+		for _, name := range names {
+			if !seenExports[name] {
+				seenExports[name] = true
+				exports = append(exports, name)
+			}
 		}
 	}
 	return exports
@@ -65,6 +97,12 @@ type OSBuildJobResult struct {
 	ImageBootMode string `json:"image_boot_mode,omitempty"`
 	// Version of the osbuild binary used by the worker to build the image
 	OSBuildVersion string `json:"osbuild_version,omitempty"`
+	// SHA256 checksums of the exported artifacts, keyed by their path
+	// relative to the output directory ("<export name>/<filename>")
+	ArtifactChecksums map[string]string `json:"artifact_checksums,omitempty"`
+	// The job wasn't built, because it's identical to this job, whose
+	// result it has
+	DeduplicatedFrom *uuid.UUID `json:"deduplicated_from,omitempty"`
 	JobResult
 }
 
@@ -131,6 +169,9 @@ type KojiInitJob struct {
 	Name    string `json:"name"`
 	Version string `json:"version"`
 	Release string `json:"release"`
+	// Kerberos principal to authenticate as, the default one of the server
+	// if empty
+	Principal string `json:"principal,omitempty"`
 }
 
 type KojiInitJobResult struct {
@@ -150,6 +191,29 @@ type KojiFinalizeJob struct {
 	KojiDirectory string   `json:"koji_directory"`
 	TaskID        uint64   `json:"task_id"` /* https://pagure.io/koji/issue/215 */
 	StartTime     uint64   `json:"start_time"`
+	// Kerberos principal to authenticate as, the default one of the server
+	// if empty
+	Principal string `json:"principal,omitempty"`
+	// Tag the build is tagged into once it's imported
+	Tag string `json:"tag,omitempty"`
+	// Summary of the compose request, attached to the build metadata
+	ComposeRequest *KojiComposeRequest `json:"compose_request,omitempty"`
+}
+
+// KojiComposeRequestImage summarizes an image requested in a Koji compose.
+type KojiComposeRequestImage struct {
+	Arch      string `json:"arch"`
+	ImageType string `json:"image_type"`
+	// Names of the upload targets of the image, besides Koji
+	UploadTargets []string `json:"upload_targets,omitempty"`
+}
+
+// KojiComposeRequest summarizes the compose request of a Koji build.
+type KojiComposeRequest struct {
+	Distribution     string                    `json:"distribution"`
+	BlueprintName    string                    `json:"blueprint_name,omitempty"`
+	BlueprintVersion string                    `json:"blueprint_version,omitempty"`
+	Images           []KojiComposeRequestImage `json:"images"`
 }
 
 type KojiFinalizeJobResult struct {
@@ -157,6 +221,24 @@ type KojiFinalizeJobResult struct {
 	JobResult
 }
 
+// ComposeJob groups the builds of a compose of several images, e.g. of
+// different architectures. Its dependencies are the osbuild jobs, in the
+// order of the images. Workers run it once all the builds finished, its
+// result only records whether any of them failed.
+type ComposeJob struct {
+	Images []ComposeJobImage `json:"images"`
+}
+
+// ComposeJobImage describes an image built as part of a ComposeJob.
+type ComposeJobImage struct {
+	Arch      string `json:"arch"`
+	ImageType string `json:"image_type"`
+}
+
+type ComposeJobResult struct {
+	JobResult
+}
+
 // PipelineNames is used to provide two pieces of information related to a job:
 // 1. A categorization of each pipeline into one of two groups
 // // 2. A pipeline ordering when the lists are concatenated: build -> os
@@ -181,6 +263,18 @@ type DepsolveJob struct {
 	ModulePlatformID string                        `json:"module_platform_id"`
 	Arch             string                        `json:"arch"`
 	Releasever       string                        `json:"releasever"`
+	// DNF modules of the package sets, keyed by the names of the package
+	// sets
+	Modules map[string]DepsolveModules `json:"modules,omitempty"`
+	// Names of the package sets depsolved without weak dependencies
+	NoWeakDeps []string `json:"no_weak_deps,omitempty"`
+}
+
+// DepsolveModules are the module streams to enable, as name:stream, and the
+// modules to disable when a package set is depsolved.
+type DepsolveModules struct {
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
 }
 
 // Custom marshaller for keeping compatibility with older workers.  The
@@ -200,6 +294,8 @@ func (ds DepsolveJob) MarshalJSON() ([]byte, error) {
 		ModulePlatformID   string                        `json:"module_platform_id"`
 		Arch               string                        `json:"arch"`
 		Releasever         string                        `json:"releasever"`
+		Modules            map[string]DepsolveModules    `json:"modules,omitempty"`
+		NoWeakDeps         []string                      `json:"no_weak_deps,omitempty"`
 
 		// old format elements
 		PackageSetsChains map[string][]string           `json:"package_sets_chains"`
@@ -211,6 +307,8 @@ func (ds DepsolveJob) MarshalJSON() ([]byte, error) {
 		ModulePlatformID:   ds.ModulePlatformID,
 		Arch:               ds.Arch,
 		Releasever:         ds.Releasever,
+		Modules:            ds.Modules,
+		NoWeakDeps:         ds.NoWeakDeps,
 	}
 
 	// build equivalent old format substruct
@@ -263,6 +361,8 @@ type DepsolveJobResult struct {
 	PackageSpecs map[string][]rpmmd.PackageSpec `json:"package_specs"`
 	Error        string                         `json:"error"`
 	ErrorType    ErrorType                      `json:"error_type"`
+	// set by workers with a depsolve cache, true if dnf-json wasn't run
+	CacheHit *bool `json:"cache_hit,omitempty"`
 	JobResult
 }
 
@@ -311,10 +411,19 @@ type ManifestJobByIDResult struct {
 	JobResult
 }
 
+// ContainerAuth are the credentials of a container registry.
+type ContainerAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
 type ContainerSpec struct {
 	Source    string `json:"source"`
 	Name      string `json:"name"`
 	TLSVerify *bool  `json:"tls-verify,omitempty"`
+	// Credentials of the registry of the source, the results of the job
+	// never include them
+	Auth *ContainerAuth `json:"auth,omitempty"`
 
 	ImageID    string `json:"image_id"`
 	Digest     string `json:"digest"`
@@ -376,6 +485,9 @@ type AWSEC2ShareJob struct {
 	Ami               string   `json:"ami"`
 	Region            string   `json:"region"`
 	ShareWithAccounts []string `json:"shareWithAccounts"`
+	// ARN of the customer managed KMS key encrypting the AMI, the accounts
+	// are granted access to it
+	KMSKeyARN string `json:"kms_key_arn,omitempty"`
 }
 
 type AWSEC2ShareJobResult struct {
@@ -390,6 +502,9 @@ type AWSEC2CopyJob struct {
 	SourceRegion string `json:"source_region"`
 	TargetRegion string `json:"target_region"`
 	TargetName   string `json:"target_name"`
+	// Customer managed KMS key in the target region encrypting the copy,
+	// its ID, ARN or alias
+	KMSKeyID string `json:"kms_key_id,omitempty"`
 }
 
 type AWSEC2CopyJobResult struct {
@@ -397,6 +512,134 @@ type AWSEC2CopyJobResult struct {
 
 	Ami    string `json:"ami"`
 	Region string `json:"region"`
+	// ARN of the KMS key encrypting the copy, empty if not encrypted with a
+	// customer managed key
+	KMSKeyARN string `json:"kms_key_arn,omitempty"`
+}
+
+// VulnerabilityScanJob scans the packages of a build for known
+// vulnerabilities once the build finished. The packages are the result of
+// its first dependency, the depsolve job of the build, the second one is the
+// osbuild job. Only the packages of its payload pipelines are scanned.
+type VulnerabilityScanJob struct {
+	// name of the distribution of the packages, e.g. "rhel-9.4"
+	Distribution string `json:"distribution"`
+}
+
+type Vulnerability struct {
+	// ID of the vulnerability in the database of the scanner
+	ID      string `json:"id"`
+	Package string `json:"package"`
+	Version string `json:"version"`
+	Arch    string `json:"arch"`
+}
+
+type VulnerabilityScanJobResult struct {
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	JobResult
+}
+
+// SignJob signs the artifacts of the targets of an osbuild job, which is its
+// only dependency, and publishes the signatures alongside the artifacts.
+// Only AWS S3 and generic S3 targets are supported.
+type SignJob struct {
+	Targets []*target.Target `json:"targets"`
+	// Also publish a clearsigned CHECKSUM file listing all the artifacts of
+	// the build, in the format of the CHECKSUM files of Fedora releases.
+	ChecksumFile bool `json:"checksum_file,omitempty"`
+	// Only publish the CHECKSUM file, the artifacts themselves aren't
+	// signed.
+	SkipArtifacts bool `json:"skip_artifacts,omitempty"`
+}
+
+type ArtifactSignature struct {
+	// name of the signed artifact in the target
+	Artifact string `json:"artifact"`
+	// SHA256 checksum in the form of "sha256:<hex digest>"
+	Checksum string `json:"checksum"`
+	// detached signature of the artifact
+	SignatureURL string `json:"signature_url"`
+	// SHA256SUMS file listing the artifact and its detached signature
+	ChecksumsURL          string `json:"checksums_url"`
+	ChecksumsSignatureURL string `json:"checksums_signature_url"`
+}
+
+type SignJobResult struct {
+	Signatures []ArtifactSignature `json:"signatures"`
+	// URLs of the CHECKSUM files, one for each target
+	ChecksumFileURLs []string `json:"checksum_file_urls,omitempty"`
+	JobResult
+}
+
+// AWSMarketplacePublishJob submits the AMI as a new version of an AMI product
+// in AWS Marketplace. If the AMI isn't set, it is the result of the
+// AWSEC2CopyJob the job depends on.
+type AWSMarketplacePublishJob struct {
+	Ami                     string                        `json:"ami"`
+	Region                  string                        `json:"region"`
+	ProductID               string                        `json:"product_id"`
+	VersionTitle            string                        `json:"version_title"`
+	ReleaseNotes            string                        `json:"release_notes"`
+	AccessRoleARN           string                        `json:"access_role_arn"`
+	UserName                string                        `json:"user_name"`
+	OperatingSystemName     string                        `json:"operating_system_name"`
+	OperatingSystemVersion  string                        `json:"operating_system_version"`
+	UsageInstructions       string                        `json:"usage_instructions"`
+	RecommendedInstanceType string                        `json:"recommended_instance_type"`
+	SecurityGroups          []AWSMarketplaceSecurityGroup `json:"security_groups,omitempty"`
+}
+
+type AWSMarketplaceSecurityGroup struct {
+	IpProtocol string   `json:"ip_protocol"`
+	FromPort   int      `json:"from_port"`
+	ToPort     int      `json:"to_port"`
+	IpRanges   []string `json:"ip_ranges"`
+}
+
+type AWSMarketplacePublishJobResult struct {
+	JobResult
+
+	Ami          string `json:"ami"`
+	ChangeSetID  string `json:"change_set_id"`
+	ChangeSetARN string `json:"change_set_arn"`
+	// last known status of the change set in the catalog
+	ChangeSetStatus string `json:"change_set_status"`
+}
+
+// GCPImageExportJob exports the image the osbuild job it depends on imported
+// to GCP to a tar.gz archive in a Cloud Storage bucket.
+type GCPImageExportJob struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+	// credentials of the GCP target, the worker's own ones are used if not
+	// set
+	Credentials []byte `json:"credentials,omitempty"`
+}
+
+type GCPImageExportJobResult struct {
+	JobResult
+
+	ImageName string `json:"image_name"`
+	// gs:// URI of the archive
+	URI string `json:"uri"`
+}
+
+// OSTreeMirrorJob pushes the commit built by the osbuild job it depends on to
+// a remote ostree repository. The commit archive is pulled from the S3 target
+// it was uploaded to.
+type OSTreeMirrorJob struct {
+	Target   *target.Target `json:"target"`
+	URL      string         `json:"url"`
+	Username string         `json:"username,omitempty"`
+	Password string         `json:"password,omitempty"`
+}
+
+type OSTreeMirrorJobResult struct {
+	JobResult
+
+	Ref      string `json:"ref"`
+	Checksum string `json:"checksum"`
+	URL      string `json:"url"`
 }
 
 //
@@ -395,6 +395,30 @@ func TestOSBuildJobExports(t *testing.T) {
 			},
 			expectedExports: []string{"archive"},
 		},
+		// bare metal target with the netboot tree exported too
+		{
+			job: &OSBuildJob{
+				Manifest: []byte("manifest"),
+				Targets: []*target.Target{
+					{
+						Name: target.TargetNameBareMetal,
+						OsbuildArtifact: target.OsbuildArtifact{
+							ExportName: "qcow2",
+						},
+						Options: &target.BareMetalTargetOptions{
+							NetbootExport: "os",
+						},
+					},
+					{
+						Name: target.TargetNameAWSS3,
+						OsbuildArtifact: target.OsbuildArtifact{
+							ExportName: "qcow2",
+						},
+					},
+				},
+			},
+			expectedExports: []string{"qcow2", "os"},
+		},
 	}
 
 	for idx, testCase := range testCases {
@@ -2,7 +2,9 @@ package worker
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
@@ -12,6 +14,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,28 +26,56 @@ import (
 
 	"github.com/osbuild/osbuild-composer/internal/auth"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/jsondb"
 	"github.com/osbuild/osbuild-composer/internal/prometheus"
 	"github.com/osbuild/osbuild-composer/internal/worker/api"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
 
 const (
-	JobTypeOSBuild          string = "osbuild"
-	JobTypeKojiInit         string = "koji-init"
-	JobTypeKojiFinalize     string = "koji-finalize"
-	JobTypeDepsolve         string = "depsolve"
-	JobTypeManifestIDOnly   string = "manifest-id-only"
-	JobTypeContainerResolve string = "container-resolve"
-	JobTypeFileResolve      string = "file-resolve"
-	JobTypeOSTreeResolve    string = "ostree-resolve"
-	JobTypeAWSEC2Copy       string = "aws-ec2-copy"
-	JobTypeAWSEC2Share      string = "aws-ec2-share"
+	JobTypeOSBuild               string = "osbuild"
+	JobTypeKojiInit              string = "koji-init"
+	JobTypeKojiFinalize          string = "koji-finalize"
+	JobTypeDepsolve              string = "depsolve"
+	JobTypeManifestIDOnly        string = "manifest-id-only"
+	JobTypeContainerResolve      string = "container-resolve"
+	JobTypeFileResolve           string = "file-resolve"
+	JobTypeOSTreeResolve         string = "ostree-resolve"
+	JobTypeAWSEC2Copy            string = "aws-ec2-copy"
+	JobTypeAWSEC2Share           string = "aws-ec2-share"
+	JobTypeVulnerabilityScan     string = "vulnerability-scan"
+	JobTypeSign                  string = "sign"
+	JobTypeAWSMarketplacePublish string = "aws-marketplace-publish"
+	JobTypeGCPImageExport        string = "gcp-image-export"
+	JobTypeOSTreeMirror          string = "ostree-mirror"
+	JobTypeCompose               string = "compose"
 )
 
 type Server struct {
 	jobs   jobqueue.JobQueue
 	logger *log.Logger
 	config Config
+
+	// artifacts which are being uploaded right now, an interrupted upload
+	// can only be resumed once the previous request wrote all it got
+	artifactUploadsMu sync.Mutex
+	artifactUploads   map[string]bool
+
+	// serializes changes of the artifact store
+	artifactStoreMu sync.Mutex
+
+	// recent successful builds by their deduplication key
+	buildsMu sync.Mutex
+	builds   map[string]finishedBuild
+
+	// deadlines of the osbuild jobs enqueued by this server, until they're
+	// picked up or failed past it
+	pendingDeadlinesMu sync.Mutex
+	pendingDeadlines   map[uuid.UUID]time.Time
+
+	// images delivered to targets, nil if the catalog is disabled
+	imageCatalogMu sync.Mutex
+	imageCatalog   *jsondb.JSONDatabase
 }
 
 type JobStatus struct {
@@ -72,18 +103,36 @@ type Config struct {
 	BasePath             string
 	JWTEnabled           bool
 	TenantProviderFields []string
+	// Hard link identical artifacts of finished jobs to a single copy
+	DeduplicateArtifacts bool
+	// Issues renewed client certificates of workers, renewal is disabled
+	// when it's nil
+	CertificateAuthority *CertificateAuthority
+	// Identical builds finished within this duration are reused instead
+	// of building them again, disabled when zero
+	BuildDeduplicationWindow time.Duration
+	// Keeps a catalog of the images delivered by the jobs of each tenant,
+	// disabled when empty
+	ImageCatalogDir string
 }
 
 func NewServer(logger *log.Logger, jobs jobqueue.JobQueue, config Config) *Server {
 	s := &Server{
-		jobs:   jobs,
-		logger: logger,
-		config: config,
+		jobs:            jobs,
+		logger:          logger,
+		config:          config,
+		artifactUploads:  make(map[string]bool),
+		builds:           make(map[string]finishedBuild),
+		pendingDeadlines: make(map[uuid.UUID]time.Time),
+	}
+	if config.ImageCatalogDir != "" {
+		s.imageCatalog = jsondb.New(config.ImageCatalogDir, 0600)
 	}
 
 	api.BasePath = config.BasePath
 
 	go s.WatchHeartbeats()
+	go s.WatchDeadlines()
 	return s
 }
 
@@ -143,12 +192,130 @@ func (s *Server) WatchHeartbeats() {
 	}
 }
 
+// DeadlineGracePeriod is the time given to workers, which were already
+// building an image when the deadline of its compose passed, to finish the
+// targets they are working on.
+const DeadlineGracePeriod = time.Minute * 10
+
+// This function should be started as a goroutine
+// Every minute it fails the osbuild jobs which are still running after the
+// deadline of their compose and the grace period passed, and the ones nobody
+// picked up before the deadline.
+func (s *Server) WatchDeadlines() {
+	//nolint:staticcheck // avoid SA1015, this is an endless function
+	for range time.Tick(time.Minute) {
+		s.FailJobsPastDeadline(DeadlineGracePeriod)
+	}
+}
+
+// FailJobsPastDeadline finishes the running osbuild jobs whose deadline
+// passed more than gracePeriod ago with a deadline exceeded error, and the
+// pending ones whose deadline passed. The jobs are finished rather than
+// canceled, so that the jobs depending on them, e.g. koji-finalize, still
+// run with the results of the other builds.
+func (s *Server) FailJobsPastDeadline(gracePeriod time.Duration) {
+	s.failPendingJobsPastDeadline()
+
+	for _, token := range s.jobs.Heartbeats(0) {
+		id, err := s.jobs.IdFromToken(token)
+		if err != nil {
+			// the job finished in the meantime
+			continue
+		}
+		jobType, err := s.JobType(id)
+		if err != nil {
+			logrus.Errorf("Error getting the type of job %s: %v", id, err)
+			continue
+		}
+		if jobType != JobTypeOSBuild {
+			continue
+		}
+		var job OSBuildJob
+		err = s.OSBuildJob(id, &job)
+		if err != nil {
+			logrus.Errorf("Error reading the arguments of job %s: %v", id, err)
+			continue
+		}
+		if job.Deadline == nil || time.Now().Before(job.Deadline.Add(gracePeriod)) {
+			continue
+		}
+
+		logrus.Infof("Failing job %s running past the deadline of its compose", id)
+		result, err := json.Marshal(OSBuildJobResult{
+			JobResult: JobResult{
+				JobError: clienterrors.WorkerClientError(clienterrors.ErrorDeadlineExceeded, "compose deadline exceeded while building the image", nil),
+			},
+		})
+		if err != nil {
+			logrus.Panicf("Cannot marshal the deadline error: %v", err)
+		}
+		err = s.RequeueOrFinishJob(token, 0, result)
+		if err != nil {
+			logrus.Errorf("Error finishing job %s past its deadline: %v", id, err)
+		}
+	}
+}
+
+// failPendingJobsPastDeadline finishes the osbuild jobs which weren't picked
+// up before their deadline. Only the jobs enqueued since the server started
+// are known, the older ones are failed by the worker picking them up. Jobs
+// waiting for their dependencies are failed once those finish.
+func (s *Server) failPendingJobsPastDeadline() {
+	now := time.Now()
+	s.pendingDeadlinesMu.Lock()
+	var due []uuid.UUID
+	for id, deadline := range s.pendingDeadlines {
+		if now.After(deadline) {
+			due = append(due, id)
+		}
+	}
+	s.pendingDeadlinesMu.Unlock()
+
+	for _, id := range due {
+		token, _, _, _, err := s.jobs.DequeueByID(context.Background(), id)
+		if err == jobqueue.ErrNotPending {
+			_, _, _, _, started, _, canceled, _, _, err := s.jobs.JobStatus(id)
+			if err == nil && started.IsZero() && !canceled {
+				// its dependencies didn't finish yet
+				continue
+			}
+		}
+		s.pendingDeadlinesMu.Lock()
+		delete(s.pendingDeadlines, id)
+		s.pendingDeadlinesMu.Unlock()
+		if err != nil {
+			// picked up, canceled or deleted in the meantime
+			continue
+		}
+
+		logrus.Infof("Failing job %s pending past the deadline of its compose", id)
+		result, err := json.Marshal(OSBuildJobResult{
+			JobResult: JobResult{
+				JobError: clienterrors.WorkerClientError(clienterrors.ErrorDeadlineExceeded, "compose deadline exceeded before the build started", nil),
+			},
+		})
+		if err != nil {
+			logrus.Panicf("Cannot marshal the deadline error: %v", err)
+		}
+		err = s.RequeueOrFinishJob(token, 0, result)
+		if err != nil {
+			logrus.Errorf("Error finishing job %s pending past its deadline: %v", id, err)
+		}
+	}
+}
+
 func (s *Server) EnqueueOSBuild(arch string, job *OSBuildJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeOSBuild+":"+arch, job, nil, channel)
+	return s.EnqueueOSBuildAsDependency(arch, job, nil, channel)
 }
 
 func (s *Server) EnqueueOSBuildAsDependency(arch string, job *OSBuildJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeOSBuild+":"+arch, job, dependencies, channel)
+	id, err := s.enqueue(JobTypeOSBuild+":"+arch, job, dependencies, channel)
+	if err == nil && job.Deadline != nil {
+		s.pendingDeadlinesMu.Lock()
+		s.pendingDeadlines[id] = *job.Deadline
+		s.pendingDeadlinesMu.Unlock()
+	}
+	return id, err
 }
 
 func (s *Server) EnqueueKojiInit(job *KojiInitJob, channel string) (uuid.UUID, error) {
@@ -159,6 +326,10 @@ func (s *Server) EnqueueKojiFinalize(job *KojiFinalizeJob, initID uuid.UUID, bui
 	return s.enqueue(JobTypeKojiFinalize, job, append([]uuid.UUID{initID}, buildIDs...), channel)
 }
 
+func (s *Server) EnqueueCompose(job *ComposeJob, buildIDs []uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(JobTypeCompose, job, buildIDs, channel)
+}
+
 func (s *Server) EnqueueDepsolve(job *DepsolveJob, channel string) (uuid.UUID, error) {
 	return s.enqueue(JobTypeDepsolve, job, nil, channel)
 }
@@ -190,6 +361,43 @@ func (s *Server) EnqueueAWSEC2ShareJob(job *AWSEC2ShareJob, parent uuid.UUID, ch
 	return s.enqueue(JobTypeAWSEC2Share, job, []uuid.UUID{parent}, channel)
 }
 
+func (s *Server) EnqueueVulnerabilityScanJob(job *VulnerabilityScanJob, depsolveJobID, buildJobID uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(JobTypeVulnerabilityScan, job, []uuid.UUID{depsolveJobID, buildJobID}, channel)
+}
+
+func (s *Server) EnqueueSignJob(job *SignJob, buildJobID uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(JobTypeSign, job, []uuid.UUID{buildJobID}, channel)
+}
+
+func (s *Server) EnqueueAWSMarketplacePublishJob(job *AWSMarketplacePublishJob, parent uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(JobTypeAWSMarketplacePublish, job, []uuid.UUID{parent}, channel)
+}
+
+func (s *Server) EnqueueGCPImageExportJob(job *GCPImageExportJob, buildJobID uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(JobTypeGCPImageExport, job, []uuid.UUID{buildJobID}, channel)
+}
+
+func (s *Server) EnqueueOSTreeMirrorJob(job *OSTreeMirrorJob, buildJobID uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(JobTypeOSTreeMirror, job, []uuid.UUID{buildJobID}, channel)
+}
+
+// Jobs pinned to a worker are enqueued in a channel of their own, which is
+// the channel of the tenant with this separator and the ID of the worker.
+const pinnedChannelSeparator = "/worker:"
+
+// PinnedChannel returns the channel of the jobs of the tenant channel which
+// are pinned to the worker. Only that worker dequeues jobs from it.
+func PinnedChannel(channel, workerID string) string {
+	return channel + pinnedChannelSeparator + workerID
+}
+
+// tenantChannel returns the channel of the tenant of a possibly pinned
+// channel.
+func tenantChannel(channel string) string {
+	tenant, _, _ := strings.Cut(channel, pinnedChannelSeparator)
+	return tenant
+}
+
 func (s *Server) enqueue(jobType string, job interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
 	prometheus.EnqueueJobMetrics(strings.Split(jobType, ":")[0], channel)
 	return s.jobs.Enqueue(jobType, job, dependencies, channel)
@@ -246,6 +454,14 @@ func (s *Server) JobDependencyChainErrors(id uuid.UUID) (*clienterrors.Error, er
 		}
 		jobResult = &kojiFinalizeJR.JobResult
 
+	case JobTypeCompose:
+		var composeJR ComposeJobResult
+		jobInfo, err = s.ComposeJobInfo(id, &composeJR)
+		if err != nil {
+			return nil, err
+		}
+		jobResult = &composeJR.JobResult
+
 	case JobTypeContainerResolve:
 		var containerResolveJR ContainerResolveJobResult
 		jobInfo, err = s.ContainerResolveJobInfo(id, &containerResolveJR)
@@ -358,6 +574,19 @@ func (s *Server) KojiFinalizeJobInfo(id uuid.UUID, result *KojiFinalizeJobResult
 	return jobInfo, nil
 }
 
+func (s *Server) ComposeJobInfo(id uuid.UUID, result *ComposeJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeCompose {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeCompose, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
 func (s *Server) DepsolveJobInfo(id uuid.UUID, result *DepsolveJobResult) (*JobInfo, error) {
 	jobInfo, err := s.jobInfo(id, result)
 	if err != nil {
@@ -459,6 +688,71 @@ func (s *Server) AWSEC2ShareJobInfo(id uuid.UUID, result *AWSEC2ShareJobResult)
 	return jobInfo, nil
 }
 
+func (s *Server) VulnerabilityScanJobInfo(id uuid.UUID, result *VulnerabilityScanJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeVulnerabilityScan {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeVulnerabilityScan, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
+func (s *Server) SignJobInfo(id uuid.UUID, result *SignJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeSign {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeSign, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
+func (s *Server) AWSMarketplacePublishJobInfo(id uuid.UUID, result *AWSMarketplacePublishJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeAWSMarketplacePublish {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeAWSMarketplacePublish, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
+func (s *Server) GCPImageExportJobInfo(id uuid.UUID, result *GCPImageExportJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeGCPImageExport {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeGCPImageExport, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
+func (s *Server) OSTreeMirrorJobInfo(id uuid.UUID, result *OSTreeMirrorJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeOSTreeMirror {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeOSTreeMirror, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
 func (s *Server) jobInfo(id uuid.UUID, result interface{}) (*JobInfo, error) {
 	jobType, channel, rawResult, queued, started, finished, canceled, deps, dependents, err := s.jobs.JobStatus(id)
 	if err != nil {
@@ -474,7 +768,7 @@ func (s *Server) jobInfo(id uuid.UUID, result interface{}) (*JobInfo, error) {
 
 	return &JobInfo{
 		JobType: strings.Split(jobType, ":")[0],
-		Channel: channel,
+		Channel: tenantChannel(channel),
 		JobStatus: &JobStatus{
 			Queued:   queued,
 			Started:  started,
@@ -504,9 +798,47 @@ func (s *Server) OSBuildJob(id uuid.UUID, job *OSBuildJob) error {
 	return nil
 }
 
+// AWSEC2CopyJob returns the arguments of an AWSEC2CopyJob.
+func (s *Server) AWSEC2CopyJob(id uuid.UUID, job *AWSEC2CopyJob) error {
+	jobType, rawArgs, _, _, err := s.jobs.Job(id)
+	if err != nil {
+		return err
+	}
+
+	if jobType != JobTypeAWSEC2Copy {
+		return fmt.Errorf("expected %q, found %q job instead for job '%s'", JobTypeAWSEC2Copy, jobType, id)
+	}
+
+	if err := json.Unmarshal(rawArgs, job); err != nil {
+		return fmt.Errorf("error unmarshaling arguments for job '%s': %v", id, err)
+	}
+
+	return nil
+}
+
+// ComposeJob returns the arguments of a ComposeJob.
+func (s *Server) ComposeJob(id uuid.UUID, job *ComposeJob) error {
+	jobType, rawArgs, _, _, err := s.jobs.Job(id)
+	if err != nil {
+		return err
+	}
+
+	if jobType != JobTypeCompose {
+		return fmt.Errorf("expected %q, found %q job instead for job '%s'", JobTypeCompose, jobType, id)
+	}
+
+	if err := json.Unmarshal(rawArgs, job); err != nil {
+		return fmt.Errorf("error unmarshaling arguments for job '%s': %v", id, err)
+	}
+
+	return nil
+}
+
+// JobChannel returns the channel of the tenant of the job, even if the job
+// is pinned to a worker.
 func (s *Server) JobChannel(id uuid.UUID) (string, error) {
 	_, _, _, channel, err := s.jobs.Job(id)
-	return channel, err
+	return tenantChannel(channel), err
 }
 
 // JobType returns the type of the job
@@ -527,6 +859,36 @@ func (s *Server) Cancel(id uuid.UUID) error {
 	return s.jobs.CancelJob(id)
 }
 
+// CancelUnfinishedJobs cancels the job and all of its dependencies, which
+// haven't finished yet.
+func (s *Server) CancelUnfinishedJobs(id uuid.UUID) error {
+	jobInfo, err := s.jobInfo(id, nil)
+	if err != nil {
+		return err
+	}
+
+	// dependencies of a finished job are finished as well
+	if !jobInfo.JobStatus.Finished.IsZero() {
+		return nil
+	}
+
+	if !jobInfo.JobStatus.Canceled {
+		err = s.Cancel(id)
+		if err != nil {
+			return fmt.Errorf("error canceling job %s: %v", id, err)
+		}
+	}
+
+	for _, dep := range jobInfo.Deps {
+		err = s.CancelUnfinishedJobs(dep)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Provides access to artifacts of a job. Returns an io.Reader for the artifact
 // and the artifact's size.
 func (s *Server) JobArtifact(id uuid.UUID, name string) (io.Reader, int64, error) {
@@ -572,7 +934,15 @@ func (s *Server) DeleteArtifacts(id uuid.UUID) error {
 		return fmt.Errorf("Cannot delete artifacts before job is finished: %s", id)
 	}
 
-	return os.RemoveAll(path.Join(s.config.ArtifactsDir, id.String()))
+	err = os.RemoveAll(path.Join(s.config.ArtifactsDir, id.String()))
+	if err != nil {
+		return err
+	}
+
+	if s.config.DeduplicateArtifacts {
+		return s.pruneArtifactStore()
+	}
+	return nil
 }
 
 func (s *Server) RequestJob(ctx context.Context, arch string, jobTypes []string, channels []string) (uuid.UUID, uuid.UUID, string, json.RawMessage, []json.RawMessage, error) {
@@ -660,6 +1030,12 @@ func (s *Server) requestJob(ctx context.Context, arch string, jobTypes []string,
 
 	prometheus.DequeueJobMetrics(pending, jobInfo.JobStatus.Started, jobInfo.JobType, jobInfo.Channel, archPromLabel)
 
+	// builds identical to a recent one are finished right away, the
+	// worker gets the next job instead
+	if requestedJobId == uuid.Nil && jobType == JobTypeOSBuild && s.DeduplicatesBuilds() && s.finishDuplicateBuild(jobId, token, jobInfo.Channel, args, dynamicArgs) {
+		return s.requestJob(ctx, arch, jobTypes, requestedJobId, channels)
+	}
+
 	return
 }
 
@@ -705,6 +1081,10 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 		}
 		arch = osbuildJR.Arch
 		jobResult = &osbuildJR.JobResult
+		if s.DeduplicatesBuilds() {
+			s.recordBuild(jobId, &osbuildJR, jobInfo.JobStatus.Finished)
+		}
+		s.catalogOSBuildImages(jobId, jobInfo.Channel, &osbuildJR, jobInfo.JobStatus.Finished)
 
 	case JobTypeDepsolve:
 		var depsolveJR DepsolveJobResult
@@ -713,6 +1093,9 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return err
 		}
 		jobResult = &depsolveJR.JobResult
+		if depsolveJR.CacheHit != nil {
+			prometheus.DepsolveCacheMetrics(*depsolveJR.CacheHit, jobInfo.Channel)
+		}
 
 	case JobTypeManifestIDOnly:
 		var manifestJR ManifestJobByIDResult
@@ -737,6 +1120,13 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return err
 		}
 		jobResult = &kojiFinalizeJR.JobResult
+	case JobTypeCompose:
+		var composeJR ComposeJobResult
+		jobInfo, err = s.ComposeJobInfo(jobId, &composeJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &composeJR.JobResult
 	case JobTypeAWSEC2Copy:
 		var awsEC2CopyJR AWSEC2CopyJobResult
 		jobInfo, err = s.AWSEC2CopyJobInfo(jobId, &awsEC2CopyJR)
@@ -751,6 +1141,42 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return err
 		}
 		jobResult = &awsEC2ShareJR.JobResult
+		s.catalogSharedAMI(jobId, jobInfo.Channel, &awsEC2ShareJR, jobInfo.JobStatus.Finished)
+	case JobTypeVulnerabilityScan:
+		var vulnerabilityScanJR VulnerabilityScanJobResult
+		jobInfo, err = s.VulnerabilityScanJobInfo(jobId, &vulnerabilityScanJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &vulnerabilityScanJR.JobResult
+	case JobTypeSign:
+		var signJR SignJobResult
+		jobInfo, err = s.SignJobInfo(jobId, &signJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &signJR.JobResult
+	case JobTypeAWSMarketplacePublish:
+		var awsMarketplacePublishJR AWSMarketplacePublishJobResult
+		jobInfo, err = s.AWSMarketplacePublishJobInfo(jobId, &awsMarketplacePublishJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &awsMarketplacePublishJR.JobResult
+	case JobTypeGCPImageExport:
+		var gcpImageExportJR GCPImageExportJobResult
+		jobInfo, err = s.GCPImageExportJobInfo(jobId, &gcpImageExportJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &gcpImageExportJR.JobResult
+	case JobTypeOSTreeMirror:
+		var ostreeMirrorJR OSTreeMirrorJobResult
+		jobInfo, err = s.OSTreeMirrorJobInfo(jobId, &ostreeMirrorJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &ostreeMirrorJR.JobResult
 	case JobTypeContainerResolve:
 		var containerResolveJR ContainerResolveJobResult
 		jobInfo, err = s.ContainerResolveJobInfo(jobId, &containerResolveJR)
@@ -787,6 +1213,14 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 		err := os.Rename(path.Join(s.config.ArtifactsDir, "tmp", token.String()), path.Join(s.config.ArtifactsDir, jobId.String()))
 		if err != nil {
 			logrus.Errorf("Error moving artifacts for job %s: %v", jobId, err)
+		} else if s.config.DeduplicateArtifacts {
+			// checksumming big artifacts takes a while, don't block the worker
+			go func() {
+				err := s.deduplicateArtifacts(jobId)
+				if err != nil {
+					logrus.Errorf("Error deduplicating artifacts of job %s: %v", jobId, err)
+				}
+			}()
 		}
 	}
 
@@ -852,7 +1286,12 @@ func (h *apiHandlers) RequestJob(ctx echo.Context) error {
 		channel = "org-" + tenant
 	}
 
-	jobId, jobToken, jobType, jobArgs, dynamicJobArgs, err := h.server.RequestJob(ctx.Request().Context(), body.Arch, body.Types, []string{channel})
+	channels := []string{channel}
+	if body.WorkerId != nil && *body.WorkerId != "" {
+		channels = append(channels, PinnedChannel(channel, *body.WorkerId))
+	}
+
+	jobId, jobToken, jobType, jobArgs, dynamicJobArgs, err := h.server.RequestJob(ctx.Request().Context(), body.Arch, body.Types, channels)
 	if err != nil {
 		if err == jobqueue.ErrDequeueTimeout {
 			return ctx.JSON(http.StatusNoContent, api.ObjectReference{
@@ -966,7 +1405,36 @@ func (h *apiHandlers) UpdateJob(ctx echo.Context, idstr string) error {
 	})
 }
 
-func (h *apiHandlers) UploadJobArtifact(ctx echo.Context, tokenstr string, name string) error {
+func (h *apiHandlers) GetJobArtifactStatus(ctx echo.Context, tokenstr string, name string) error {
+	token, err := uuid.Parse(tokenstr)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorMalformedJobId, err)
+	}
+
+	if h.server.config.ArtifactsDir == "" {
+		// indicate to the worker that the server is not accepting any artifacts
+		return ctx.NoContent(http.StatusBadRequest)
+	}
+
+	var offset int64
+	fi, err := os.Stat(path.Join(h.server.config.ArtifactsDir, "tmp", token.String(), name))
+	if err == nil {
+		offset = fi.Size()
+	} else if !os.IsNotExist(err) {
+		return api.HTTPErrorWithInternal(api.ErrorWritingArtifact, err)
+	}
+
+	return ctx.JSON(http.StatusOK, api.ArtifactStatusResponse{
+		ObjectReference: api.ObjectReference{
+			Href: fmt.Sprintf("%s/jobs/%v/artifacts/%s", api.BasePath, token, name),
+			Id:   name,
+			Kind: "ArtifactStatus",
+		},
+		Offset: offset,
+	})
+}
+
+func (h *apiHandlers) UploadJobArtifact(ctx echo.Context, tokenstr string, name string, params api.UploadJobArtifactParams) error {
 	token, err := uuid.Parse(tokenstr)
 	if err != nil {
 		return api.HTTPErrorWithInternal(api.ErrorMalformedJobId, err)
@@ -979,19 +1447,127 @@ func (h *apiHandlers) UploadJobArtifact(ctx echo.Context, tokenstr string, name
 		return ctx.NoContent(http.StatusBadRequest)
 	}
 
-	f, err := os.Create(path.Join(h.server.config.ArtifactsDir, "tmp", token.String(), name))
-	if err != nil {
-		return api.HTTPErrorWithInternal(api.ErrorDiscardingArtifact, err)
+	p := path.Join(h.server.config.ArtifactsDir, "tmp", token.String(), name)
+	if !h.server.startArtifactUpload(p) {
+		return api.HTTPError(api.ErrorArtifactUploading)
 	}
+	defer h.server.finishArtifactUpload(p)
+
+	var f *os.File
+	if params.Offset == nil {
+		f, err = os.Create(p)
+		if err != nil {
+			return api.HTTPErrorWithInternal(api.ErrorDiscardingArtifact, err)
+		}
+	} else {
+		// append the chunk to the part which was uploaded already
+		f, err = os.OpenFile(p, os.O_WRONLY|os.O_CREATE, 0666)
+		if err != nil {
+			return api.HTTPErrorWithInternal(api.ErrorCreatingArtifact, err)
+		}
+		size, err := f.Seek(0, io.SeekEnd)
+		if err != nil {
+			f.Close()
+			return api.HTTPErrorWithInternal(api.ErrorWritingArtifact, err)
+		}
+		if size != *params.Offset {
+			f.Close()
+			return api.HTTPErrorWithInternal(api.ErrorArtifactOffset, fmt.Errorf("expected offset %d, got %d", size, *params.Offset))
+		}
+	}
+	defer f.Close()
 
 	_, err = io.Copy(f, request.Body)
 	if err != nil {
 		return api.HTTPErrorWithInternal(api.ErrorWritingArtifact, err)
 	}
 
+	if params.Checksum != nil {
+		checksum, err := common.FileSHA256(p)
+		if err != nil {
+			return api.HTTPErrorWithInternal(api.ErrorWritingArtifact, err)
+		}
+		if checksum != *params.Checksum {
+			// the artifact is corrupted, let the worker start over
+			if err := os.Remove(p); err != nil {
+				logrus.Errorf("Error removing corrupted artifact %s: %v", p, err)
+			}
+			return api.HTTPErrorWithInternal(api.ErrorArtifactChecksum, fmt.Errorf("expected checksum %s, got %s", *params.Checksum, checksum))
+		}
+	}
+
 	return ctx.NoContent(http.StatusOK)
 }
 
+// RenewCertificate issues a new client certificate for the key of the CSR,
+// for the subject of the certificate the worker authenticated with.
+func (h *apiHandlers) RenewCertificate(ctx echo.Context) error {
+	ca := h.server.config.CertificateAuthority
+	if ca == nil {
+		return api.HTTPError(api.ErrorCertificateRenewal)
+	}
+
+	// the client certificate has been verified during the TLS handshake
+	tlsState := ctx.Request().TLS
+	if tlsState == nil || len(tlsState.VerifiedChains) == 0 {
+		return api.HTTPError(api.ErrorNoClientCertificate)
+	}
+	current := tlsState.VerifiedChains[0][0]
+
+	var body api.RenewCertificateJSONRequestBody
+	err := ctx.Bind(&body)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode([]byte(body.Csr))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return api.HTTPError(api.ErrorInvalidCSR)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorInvalidCSR, err)
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorInvalidCSR, err)
+	}
+
+	cert, err := ca.RenewCertificate(current, csr)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorIssuingCertificate, err)
+	}
+	logrus.Infof("Renewed client certificate of %s, valid until %v", current.Subject, cert.NotAfter)
+
+	return ctx.JSON(http.StatusCreated, api.RenewCertificateResponse{
+		ObjectReference: api.ObjectReference{
+			Href: fmt.Sprintf("%s/certificate", api.BasePath),
+			Id:   cert.SerialNumber.Text(16),
+			Kind: "Certificate",
+		},
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})),
+	})
+}
+
+// startArtifactUpload marks the artifact as being uploaded, it returns false
+// if another upload of the same artifact is still running.
+func (s *Server) startArtifactUpload(p string) bool {
+	s.artifactUploadsMu.Lock()
+	defer s.artifactUploadsMu.Unlock()
+
+	if s.artifactUploads[p] {
+		return false
+	}
+	s.artifactUploads[p] = true
+	return true
+}
+
+func (s *Server) finishArtifactUpload(p string) {
+	s.artifactUploadsMu.Lock()
+	defer s.artifactUploadsMu.Unlock()
+
+	delete(s.artifactUploads, p)
+}
+
 // A simple echo.Binder(), which only accepts application/json, but is more
 // strict than echo's DefaultBinder. It does not handle binding query
 // parameters either.
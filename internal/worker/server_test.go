@@ -2,8 +2,10 @@ package worker_test
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path"
@@ -343,6 +345,275 @@ func TestUploadNotAcceptingArtifacts(t *testing.T) {
 	test.TestRoute(t, handler, false, "PUT", fmt.Sprintf("/api/worker/v1/jobs/%s/artifacts/foobar", token), `this is my artifact`, http.StatusBadRequest, `?`)
 }
 
+func TestUploadArtifactChunks(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), time.Duration(0), "/api/worker/v1", true)
+	handler := server.Handler()
+
+	jobID, err := server.EnqueueOSBuild("arch", &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+
+	j, token, _, _, _, err := server.RequestJob(context.Background(), "arch", []string{worker.JobTypeOSBuild}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, jobID, j)
+
+	artifactPath := fmt.Sprintf("/api/worker/v1/jobs/%s/artifacts/foobar", token)
+	statusJSON := func(offset int) string {
+		return fmt.Sprintf(`{"href":"%s","id":"foobar","kind":"ArtifactStatus","offset":%d}`, artifactPath, offset)
+	}
+
+	// nothing uploaded yet
+	test.TestRoute(t, handler, false, "GET", artifactPath, ``, http.StatusOK, statusJSON(0))
+
+	test.TestRoute(t, handler, false, "PUT", artifactPath+"?offset=0", `this is `, http.StatusOK, `?`)
+	test.TestRoute(t, handler, false, "GET", artifactPath, ``, http.StatusOK, statusJSON(8))
+
+	// the offset has to match what the server already has
+	test.TestRoute(t, handler, false, "PUT", artifactPath+"?offset=3", `my artifact`, http.StatusConflict, `?`)
+	test.TestRoute(t, handler, false, "PUT", artifactPath+"?offset=8", `my artifact`, http.StatusOK, `?`)
+	test.TestRoute(t, handler, false, "GET", artifactPath, ``, http.StatusOK, statusJSON(19))
+
+	err = server.FinishJob(token, nil)
+	require.NoError(t, err)
+
+	reader, size, err := server.JobArtifact(jobID, "foobar")
+	require.NoError(t, err)
+	require.Equal(t, int64(19), size)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "this is my artifact", string(content))
+}
+
+func TestUploadArtifactChecksum(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), time.Duration(0), "/api/worker/v1", true)
+	handler := server.Handler()
+
+	jobID, err := server.EnqueueOSBuild("arch", &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+
+	j, token, _, _, _, err := server.RequestJob(context.Background(), "arch", []string{worker.JobTypeOSBuild}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, jobID, j)
+
+	artifactPath := fmt.Sprintf("/api/worker/v1/jobs/%s/artifacts/foobar", token)
+	checksum := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("this is my artifact")))
+
+	// a corrupted artifact is discarded
+	test.TestRoute(t, handler, false, "PUT", artifactPath+"?offset=0", `this is `, http.StatusOK, `?`)
+	test.TestRoute(t, handler, false, "PUT", artifactPath+"?offset=8&checksum="+checksum, `my artefact`, http.StatusConflict, `?`)
+	test.TestRoute(t, handler, false, "GET", artifactPath, ``, http.StatusOK,
+		fmt.Sprintf(`{"href":"%s","id":"foobar","kind":"ArtifactStatus","offset":0}`, artifactPath))
+
+	test.TestRoute(t, handler, false, "PUT", artifactPath+"?offset=0", `this is `, http.StatusOK, `?`)
+	test.TestRoute(t, handler, false, "PUT", artifactPath+"?offset=8&checksum="+checksum, `my artifact`, http.StatusOK, `?`)
+
+	err = server.FinishJob(token, nil)
+	require.NoError(t, err)
+
+	reader, _, err := server.JobArtifact(jobID, "foobar")
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "this is my artifact", string(content))
+}
+
+func TestDeduplicateArtifacts(t *testing.T) {
+	tempdir := t.TempDir()
+	q, err := fsjobqueue.New(tempdir)
+	require.NoError(t, err)
+	artifactsDir := path.Join(tempdir, "artifacts")
+	require.NoError(t, os.Mkdir(artifactsDir, 0755))
+	server := worker.NewServer(nil, q, worker.Config{
+		ArtifactsDir:         artifactsDir,
+		BasePath:             "/api/worker/v1",
+		DeduplicateArtifacts: true,
+	})
+	handler := server.Handler()
+
+	uploadArtifacts := func(artifacts map[string]string) uuid.UUID {
+		jobID, err := server.EnqueueOSBuild("arch", &worker.OSBuildJob{}, "")
+		require.NoError(t, err)
+		_, token, _, _, _, err := server.RequestJob(context.Background(), "arch", []string{worker.JobTypeOSBuild}, []string{""})
+		require.NoError(t, err)
+		for name, content := range artifacts {
+			test.TestRoute(t, handler, false, "PUT", fmt.Sprintf("/api/worker/v1/jobs/%s/artifacts/%s", token, name), content, http.StatusOK, `?`)
+		}
+		require.NoError(t, server.FinishJob(token, nil))
+		return jobID
+	}
+
+	job1 := uploadArtifacts(map[string]string{"image": "same content", "other": "different content"})
+	job2 := uploadArtifacts(map[string]string{"image": "same content"})
+
+	stat := func(jobID uuid.UUID, name string) os.FileInfo {
+		fi, err := os.Stat(path.Join(artifactsDir, jobID.String(), name))
+		require.NoError(t, err)
+		return fi
+	}
+	require.Eventually(t, func() bool {
+		return os.SameFile(stat(job1, "image"), stat(job2, "image"))
+	}, 5*time.Second, 10*time.Millisecond)
+	require.False(t, os.SameFile(stat(job1, "image"), stat(job1, "other")))
+
+	reader, _, err := server.JobArtifact(job2, "image")
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "same content", string(content))
+
+	object := path.Join(artifactsDir, "store", "sha256", fmt.Sprintf("%x", sha256.Sum256([]byte("same content"))))
+	_, err = os.Stat(object)
+	require.NoError(t, err)
+
+	// objects are removed once no job links to them anymore
+	require.NoError(t, server.DeleteArtifacts(job1))
+	_, err = os.Stat(object)
+	require.NoError(t, err)
+	require.NoError(t, server.DeleteArtifacts(job2))
+	_, err = os.Stat(object)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDeduplicateBuilds(t *testing.T) {
+	tempdir := t.TempDir()
+	q, err := fsjobqueue.New(tempdir)
+	require.NoError(t, err)
+	artifactsDir := path.Join(tempdir, "artifacts")
+	require.NoError(t, os.Mkdir(artifactsDir, 0755))
+	server := worker.NewServer(nil, q, worker.Config{
+		ArtifactsDir:             artifactsDir,
+		BasePath:                 "/api/worker/v1",
+		RequestJobTimeout:        10 * time.Millisecond,
+		BuildDeduplicationWindow: time.Hour,
+	})
+	handler := server.Handler()
+	require.True(t, server.DeduplicatesBuilds())
+
+	// enqueueBuild enqueues a build of a compose request, whose manifest
+	// is resolved already
+	enqueueBuild := func(manifest, request, channel string) uuid.UUID {
+		depsolveID, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, channel)
+		require.NoError(t, err)
+		manifestID, err := server.EnqueueManifestJobByID(&worker.ManifestJobByID{}, []uuid.UUID{depsolveID}, channel)
+		require.NoError(t, err)
+		jobID, err := server.EnqueueOSBuildAsDependency("arch", &worker.OSBuildJob{ComposeRequest: json.RawMessage(request)}, []uuid.UUID{manifestID}, channel)
+		require.NoError(t, err)
+
+		_, token, _, _, _, err := server.RequestJob(context.Background(), "arch", []string{worker.JobTypeDepsolve}, []string{channel})
+		require.NoError(t, err)
+		require.NoError(t, server.FinishJob(token, json.RawMessage(`{}`)))
+		_, token, _, _, _, err = server.RequestJobById(context.Background(), "arch", manifestID)
+		require.NoError(t, err)
+		result, err := json.Marshal(worker.ManifestJobByIDResult{Manifest: []byte(manifest)})
+		require.NoError(t, err)
+		require.NoError(t, server.FinishJob(token, result))
+		return jobID
+	}
+
+	result := worker.OSBuildJobResult{
+		Success:       true,
+		OSBuildOutput: &osbuild.Result{Success: true},
+		TargetResults: []*target.TargetResult{
+			target.NewAWSS3TargetResult(&target.AWSS3TargetResultOptions{URL: "https://example.com/image"}, nil),
+		},
+	}
+	rawResult, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	job1 := enqueueBuild(`{"version":"2"}`, `{"distribution":"fedora"}`, "")
+	id, token, _, _, _, err := server.RequestJob(context.Background(), "arch", []string{worker.JobTypeOSBuild}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, job1, id)
+	test.TestRoute(t, handler, false, "PUT", fmt.Sprintf("/api/worker/v1/jobs/%s/artifacts/image", token), "image", http.StatusOK, `?`)
+	require.NoError(t, server.FinishJob(token, rawResult))
+
+	// an identical build isn't given to a worker
+	job2 := enqueueBuild(`{"version":"2"}`, `{"distribution":"fedora"}`, "")
+	_, _, _, _, _, err = server.RequestJob(context.Background(), "arch", []string{worker.JobTypeOSBuild}, []string{""})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	var result2 worker.OSBuildJobResult
+	jobInfo, err := server.OSBuildJobInfo(job2, &result2)
+	require.NoError(t, err)
+	require.False(t, jobInfo.JobStatus.Finished.IsZero())
+	require.Equal(t, &job1, result2.DeduplicatedFrom)
+	require.Equal(t, result.TargetResults, result2.TargetResults)
+	reader, _, err := server.JobArtifact(job2, "image")
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "image", string(content))
+
+	// builds with another manifest or request, or of another tenant, are
+	// built
+	for _, build := range [][3]string{
+		{`{"version":"2","pipelines":[]}`, `{"distribution":"fedora"}`, ""},
+		{`{"version":"2"}`, `{"distribution":"rhel"}`, ""},
+		{`{"version":"2"}`, `{"distribution":"fedora"}`, "org-2"},
+	} {
+		jobID := enqueueBuild(build[0], build[1], build[2])
+		id, token, _, _, _, err := server.RequestJob(context.Background(), "arch", []string{worker.JobTypeOSBuild}, []string{build[2]})
+		require.NoError(t, err)
+		require.Equal(t, jobID, id)
+		require.NoError(t, server.FinishJob(token, rawResult))
+	}
+}
+
+func TestImageCatalog(t *testing.T) {
+	tempdir := t.TempDir()
+	q, err := fsjobqueue.New(tempdir)
+	require.NoError(t, err)
+
+	server := worker.NewServer(nil, q, worker.Config{BasePath: "/api/worker/v1"})
+	_, err = server.ImageCatalog("")
+	require.Equal(t, worker.ErrImageCatalogDisabled, err)
+
+	catalogDir := path.Join(tempdir, "image-catalog")
+	require.NoError(t, os.Mkdir(catalogDir, 0700))
+	server = worker.NewServer(nil, q, worker.Config{BasePath: "/api/worker/v1", ImageCatalogDir: catalogDir})
+
+	images, err := server.ImageCatalog("org-1")
+	require.NoError(t, err)
+	require.Empty(t, images)
+
+	ami := target.NewAWSTargetResult(&target.AWSTargetResultOptions{Ami: "ami-1", Region: "eu-west-1"}, nil)
+	failedUpload := target.NewGCPTargetResult(&target.GCPTargetResultOptions{}, nil)
+	failedUpload.TargetError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, "upload failed", nil)
+	finishBuild := func(channel string, targetResults ...*target.TargetResult) uuid.UUID {
+		jobID, err := server.EnqueueOSBuild("x86_64", &worker.OSBuildJob{}, channel)
+		require.NoError(t, err)
+		_, token, _, _, _, err := server.RequestJob(context.Background(), "x86_64", []string{worker.JobTypeOSBuild}, []string{channel})
+		require.NoError(t, err)
+		result, err := json.Marshal(worker.OSBuildJobResult{
+			Success:       true,
+			Arch:          "x86_64",
+			OSBuildOutput: &osbuild.Result{Success: true},
+			TargetResults: targetResults,
+		})
+		require.NoError(t, err)
+		require.NoError(t, server.FinishJob(token, result))
+		return jobID
+	}
+
+	// only successful uploads are added, to the catalog of the tenant
+	job1 := finishBuild("org-1", ami, failedUpload, target.NewWorkerServerTargetResult(nil))
+	finishBuild("org-2", target.NewContainerTargetResult(&target.ContainerTargetResultOptions{URL: "registry.example.com/image", Digest: "sha256:0123"}, nil))
+	job3 := finishBuild("org-1", target.NewAzureImageTargetResult(&target.AzureImageTargetResultOptions{ImageName: "image"}, nil))
+
+	images, err = server.ImageCatalog("org-1")
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	require.Equal(t, job3, images[0].JobId)
+	require.Equal(t, target.TargetNameAzureImage, images[0].Target.Name)
+	require.Equal(t, job1, images[1].JobId)
+	require.Equal(t, "x86_64", images[1].Arch)
+	require.Equal(t, ami, images[1].Target)
+
+	images, err = server.ImageCatalog("org-2")
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	require.Equal(t, target.TargetNameContainer, images[0].Target.Name)
+}
+
 func TestUploadAlteredBasePath(t *testing.T) {
 	distroStruct := test_distro.New()
 	arch, err := distroStruct.GetArch(test_distro.TestArchName)
@@ -393,6 +664,31 @@ func TestTimeout(t *testing.T) {
 		`{"href":"/api/image-builder-worker/v1/jobs","id":"00000000-0000-0000-0000-000000000000","kind":"RequestJob"}`)
 }
 
+func TestPinnedJob(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), time.Millisecond*10, "/api/worker/v1", false)
+	handler := server.Handler()
+
+	jobId, err := server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{PinnedWorkerID: "worker-1"}, worker.PinnedChannel("", "worker-1"))
+	require.NoError(t, err)
+
+	// the channel of the tenant is reported, not the one of the worker
+	channel, err := server.JobChannel(jobId)
+	require.NoError(t, err)
+	require.Equal(t, "", channel)
+
+	// other workers don't get the job
+	for _, body := range []string{
+		fmt.Sprintf(`{"arch":"%s","types":["%s"]}`, test_distro.TestArchName, worker.JobTypeOSBuild),
+		fmt.Sprintf(`{"arch":"%s","types":["%s"],"worker_id":"worker-2"}`, test_distro.TestArchName, worker.JobTypeOSBuild),
+	} {
+		test.TestRoute(t, handler, false, "POST", "/api/worker/v1/jobs", body, http.StatusNoContent,
+			`{"href":"/api/worker/v1/jobs","id":"00000000-0000-0000-0000-000000000000","kind":"RequestJob"}`)
+	}
+
+	test.TestRoute(t, handler, false, "POST", "/api/worker/v1/jobs", fmt.Sprintf(`{"arch":"%s","types":["%s"],"worker_id":"worker-1"}`, test_distro.TestArchName, worker.JobTypeOSBuild), http.StatusCreated,
+		fmt.Sprintf(`{"href":"/api/worker/v1/jobs","id":"%s","kind":"RequestJob","type":"%s","args":{"pinned_worker_id":"worker-1"}}`, jobId, worker.JobTypeOSBuild), "location", "artifact_location")
+}
+
 func TestRequestJobById(t *testing.T) {
 	distroStruct := test_distro.New()
 	arch, err := distroStruct.GetArch(test_distro.TestArchName)